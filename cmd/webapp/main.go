@@ -15,6 +15,8 @@ import (
 	"github.com/mdco1990/webapp/internal/db"
 	obslog "github.com/mdco1990/webapp/internal/observability/log"
 	httpapi "github.com/mdco1990/webapp/internal/transport/http"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 func main() {
@@ -32,12 +34,17 @@ func main() {
 	}
 
 	// HTTP server
-	r := httpapi.NewRouter(cfg, dbConn)
+	r, workers, err := httpapi.NewRouter(cfg, dbConn)
+	if err != nil {
+		slog.Error("router setup failed", "err", err)
+		os.Exit(1)
+	}
 	srv := newHTTPServer(cfg, r)
+	stopWorkers := runWorkersAsync(workers)
 	runServerAsync(srv, cfg.HTTPAddress, cfg.Env, cfg.DBDriver)
 
 	// Graceful shutdown
-	waitForShutdown(srv, config.ShutdownTimeout)
+	waitForShutdown(srv, stopWorkers, config.ShutdownTimeout)
 }
 
 // ensureDataDir creates the sqlite data directory when needed.
@@ -92,11 +99,12 @@ func migrateIfSQLite(driver string, dbConn *sql.DB) error {
 	return db.Migrate(dbConn)
 }
 
-// newHTTPServer constructs an http.Server from config.
+// newHTTPServer constructs an http.Server from config. It also accepts
+// cleartext HTTP/2 (h2c), which gRPC clients of the RPC services require.
 func newHTTPServer(cfg config.Config, handler http.Handler) *http.Server {
 	return &http.Server{
 		Addr:              cfg.HTTPAddress,
-		Handler:           handler,
+		Handler:           h2c.NewHandler(handler, &http2.Server{}),
 		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
 		ReadTimeout:       cfg.ReadTimeout,
 		WriteTimeout:      cfg.WriteTimeout,
@@ -117,8 +125,28 @@ func runServerAsync(srv *http.Server, addr, env, dbDriver string) {
 	}()
 }
 
-// waitForShutdown blocks until a termination signal then gracefully shuts the server down.
-func waitForShutdown(srv *http.Server, timeout time.Duration) {
+// runWorkersAsync starts the background workers and returns a func that
+// stops them, waiting until they have returned or ctx is done.
+func runWorkersAsync(workers *httpapi.Workers) func(ctx context.Context) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		workers.Run(ctx)
+	}()
+	return func(stopCtx context.Context) {
+		cancel()
+		select {
+		case <-done:
+		case <-stopCtx.Done():
+			slog.Warn("background workers did not stop in time")
+		}
+	}
+}
+
+// waitForShutdown blocks until a termination signal then gracefully shuts the
+// server down, followed by the background workers.
+func waitForShutdown(srv *http.Server, stopWorkers func(context.Context), timeout time.Duration) {
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 	<-stop
@@ -126,4 +154,5 @@ func waitForShutdown(srv *http.Server, timeout time.Duration) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 	_ = srv.Shutdown(ctx)
+	stopWorkers(ctx)
 }
@@ -0,0 +1,289 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// JWT signing algorithms.
+const (
+	AlgEdDSA = "EdDSA"
+	AlgRS256 = "RS256"
+)
+
+// minRSAKeyBits is the smallest RSA key accepted for RS256.
+const minRSAKeyBits = 2048
+
+// JWT errors. Verification failures all wrap ErrInvalidToken.
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrUnknownKey   = fmt.Errorf("%w: unknown signing key", ErrInvalidToken)
+	ErrBadSignature = fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+)
+
+// SigningKey is a private key that signs JWTs. Its ID is the RFC 7638
+// thumbprint of the public key, sent as the token's kid.
+type SigningKey struct {
+	ID        string
+	Algorithm string
+	private   crypto.Signer
+}
+
+// NewSigningKey wraps an Ed25519 (EdDSA) or RSA (RS256) private key.
+func NewSigningKey(private crypto.Signer) (*SigningKey, error) {
+	key := &SigningKey{private: private}
+	switch k := private.(type) {
+	case ed25519.PrivateKey:
+		key.Algorithm = AlgEdDSA
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", minRSAKeyBits)
+		}
+		key.Algorithm = AlgRS256
+	default:
+		return nil, fmt.Errorf("unsupported signing key type %T", private)
+	}
+	key.ID = key.JWK().thumbprint()
+	return key, nil
+}
+
+// GenerateSigningKey creates a random Ed25519 signing key.
+func GenerateSigningKey() (*SigningKey, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return NewSigningKey(private)
+}
+
+// ParseSigningKeyPEM reads a PKCS#8 Ed25519 or RSA private key, or a PKCS#1
+// RSA private key, from PEM.
+func ParseSigningKeyPEM(data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	var (
+		parsed any
+		err    error
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported signing key type %T", parsed)
+	}
+	return NewSigningKey(signer)
+}
+
+// sign returns the signature of input.
+func (k *SigningKey) sign(input []byte) ([]byte, error) {
+	if k.Algorithm == AlgRS256 {
+		sum := sha256.Sum256(input)
+		return k.private.Sign(rand.Reader, sum[:], crypto.SHA256)
+	}
+	return k.private.Sign(rand.Reader, input, crypto.Hash(0))
+}
+
+// verify checks sig against input. Both ed25519 and rsa compare signatures
+// in constant time.
+func (k *SigningKey) verify(input, sig []byte) bool {
+	switch pub := k.private.Public().(type) {
+	case ed25519.PublicKey:
+		return ed25519.Verify(pub, input, sig)
+	case *rsa.PublicKey:
+		sum := sha256.Sum256(input)
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, sum[:], sig) == nil
+	}
+	return false
+}
+
+// JWK returns the public half of the key as a JSON Web Key.
+func (k *SigningKey) JWK() JWK {
+	jwk := JWK{Kid: k.ID, Alg: k.Algorithm, Use: "sig"}
+	switch pub := k.private.Public().(type) {
+	case ed25519.PublicKey:
+		jwk.Kty, jwk.Crv, jwk.X = "OKP", "Ed25519", b64(pub)
+	case *rsa.PublicKey:
+		jwk.Kty, jwk.N, jwk.E = "RSA", b64(pub.N.Bytes()), b64(big.NewInt(int64(pub.E)).Bytes())
+	}
+	return jwk
+}
+
+// JWK is a public JSON Web Key (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+// thumbprint returns the RFC 7638 thumbprint: the SHA-256 of the required
+// members in lexical order.
+func (j JWK) thumbprint() string {
+	var canonical string
+	if j.Kty == "RSA" {
+		canonical = `{"e":"` + j.E + `","kty":"RSA","n":"` + j.N + `"}`
+	} else {
+		canonical = `{"crv":"` + j.Crv + `","kty":"` + j.Kty + `","x":"` + j.X + `"}`
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return b64(sum[:])
+}
+
+// JWKS is the document served at /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// KeySet signs JWTs with its current key and verifies them with any key it
+// holds, by kid. To rotate, Rotate to a new key and Retire the old one once
+// the tokens it signed have expired.
+type KeySet struct {
+	mu      sync.RWMutex
+	current *SigningKey
+	keys    map[string]*SigningKey
+}
+
+// NewKeySet creates a key set signing with current and still accepting
+// tokens signed by previous.
+func NewKeySet(current *SigningKey, previous ...*SigningKey) *KeySet {
+	ks := &KeySet{keys: make(map[string]*SigningKey)}
+	for _, k := range previous {
+		ks.keys[k.ID] = k
+	}
+	ks.Rotate(current)
+	return ks
+}
+
+// Rotate makes next the signing key. The previous key keeps verifying.
+func (ks *KeySet) Rotate(next *SigningKey) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.current = next
+	ks.keys[next.ID] = next
+}
+
+// Retire drops a previous key; tokens it signed are refused from then on.
+// The current key cannot be retired.
+func (ks *KeySet) Retire(kid string) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if kid != ks.current.ID {
+		delete(ks.keys, kid)
+	}
+}
+
+// JWKS returns the public keys, current key first.
+func (ks *KeySet) JWKS() JWKS {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	set := JWKS{Keys: []JWK{ks.current.JWK()}}
+	for id, k := range ks.keys {
+		if id != ks.current.ID {
+			set.Keys = append(set.Keys, k.JWK())
+		}
+	}
+	slices.SortFunc(set.Keys[1:], func(a, b JWK) int { return strings.Compare(a.Kid, b.Kid) })
+	return set
+}
+
+// jwtHeader is the JOSE header of the tokens.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+// Sign encodes claims as a compact JWT signed with the current key.
+func (ks *KeySet) Sign(claims any) (string, error) {
+	ks.mu.RLock()
+	key := ks.current
+	ks.mu.RUnlock()
+
+	header, err := json.Marshal(jwtHeader{Alg: key.Algorithm, Typ: "JWT", Kid: key.ID})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	input := b64(header) + "." + b64(payload)
+	sig, err := key.sign([]byte(input))
+	if err != nil {
+		return "", err
+	}
+	return input + "." + b64(sig), nil
+}
+
+// Verify checks the token's signature against the key named by its kid and
+// decodes its payload into claims. The header's alg must match the key's, so
+// "none" and algorithm substitution are refused. Claims are not validated.
+func (ks *KeySet) Verify(token string, claims any) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("%w: not a compact JWT", ErrInvalidToken)
+	}
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return err
+	}
+	ks.mu.RLock()
+	key := ks.keys[header.Kid]
+	ks.mu.RUnlock()
+	if key == nil {
+		return ErrUnknownKey
+	}
+	if header.Alg != key.Algorithm {
+		return fmt.Errorf("%w: unexpected algorithm %q", ErrInvalidToken, header.Alg)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !key.verify([]byte(parts[0]+"."+parts[1]), sig) {
+		return ErrBadSignature
+	}
+	return decodeSegment(parts[1], claims)
+}
+
+// decodeSegment decodes a base64url JSON token segment into v.
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("%w: bad encoding", ErrInvalidToken)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: bad JSON", ErrInvalidToken)
+	}
+	return nil
+}
+
+// b64 encodes data as unpadded base64url, as JOSE requires.
+func b64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
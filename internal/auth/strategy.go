@@ -4,19 +4,20 @@ package auth
 import (
 	"context"
 	"crypto/rand"
-	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
 	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/repository"
 	"github.com/mdco1990/webapp/internal/storage"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // Strategy defines the interface for authentication strategies
@@ -44,6 +45,49 @@ type Credentials struct {
 	RememberMe bool   `json:"remember_me"`
 }
 
+// Authentication errors.
+var (
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrAccountLocked      = errors.New("account temporarily locked due to too many failed login attempts")
+	ErrInvalidRefresh     = errors.New("invalid refresh token")
+	ErrTokenExpired       = fmt.Errorf("%w: token expired", ErrInvalidToken)
+)
+
+// UserStore looks users up with their bcrypt password hashes; the
+// repository implements it.
+type UserStore interface {
+	GetUserByUsername(ctx context.Context, username string) (*domain.User, string, error)
+	GetUserByID(ctx context.Context, userID int64) (*domain.User, string, error)
+}
+
+// dummyPasswordHash is compared against when a user does not exist, so that
+// unknown usernames take as long to refuse as wrong passwords.
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// checkCredentials returns the user whose stored bcrypt hash matches the
+// password. Unknown users and wrong passwords both give ErrInvalidCredentials.
+func checkCredentials(ctx context.Context, users UserStore, credentials Credentials) (*domain.User, error) {
+	if credentials.Username == "" || credentials.Password == "" {
+		return nil, errors.New("username and password are required")
+	}
+
+	user, passwordHash, err := users.GetUserByUsername(ctx, credentials.Username)
+	if errors.Is(err, repository.ErrNotFound) {
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(credentials.Password))
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(credentials.Password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
 // Result represents the result of authentication
 type Result struct {
 	User         *domain.User `json:"user"`
@@ -74,10 +118,15 @@ type Config struct {
 	RequireMFA        bool          `json:"require_mfa"`
 	PasswordMinLength int           `json:"password_min_length"`
 	PasswordMaxLength int           `json:"password_max_length"`
+	// Issuer and Audience are set in issued JWTs and required of validated ones.
+	Issuer    string        `json:"issuer"`
+	Audience  string        `json:"audience"`
+	ClockSkew time.Duration `json:"clock_skew"`
 }
 
-// NewAuthService creates a new authentication service
-func NewAuthService(storage storage.Provider, config Config) *Service {
+// NewAuthService creates a new authentication service. Credentials are
+// checked against users; JWTs are signed with keys.
+func NewAuthService(storage storage.Provider, users UserStore, keys *KeySet, config Config) *Service {
 	service := &Service{
 		strategies:      make(map[string]Strategy),
 		defaultStrategy: config.DefaultStrategy,
@@ -86,8 +135,8 @@ func NewAuthService(storage storage.Provider, config Config) *Service {
 	}
 
 	// Register default strategies
-	service.RegisterStrategy("session", NewSessionStrategy(storage, config))
-	service.RegisterStrategy("token", NewTokenStrategy(storage, config))
+	service.RegisterStrategy("session", NewSessionStrategy(storage, users, config))
+	service.RegisterStrategy("token", NewTokenStrategy(storage, users, keys, config))
 
 	return service
 }
@@ -185,7 +234,7 @@ func (as *Service) checkLoginAttempts(ctx context.Context, username string) erro
 	}
 
 	var attempts LoginAttempts
-	attemptsBytes, ok := attemptsData.([]byte)
+	attemptsBytes, ok := storedBytes(attemptsData)
 	if !ok {
 		return nil // Invalid data type, treat as no attempts
 	}
@@ -196,7 +245,7 @@ func (as *Service) checkLoginAttempts(ctx context.Context, username string) erro
 
 	// Check if locked out
 	if attempts.IsLockedOut(as.config.MaxLoginAttempts, as.config.LockoutDuration) {
-		return errors.New("account temporarily locked due to too many failed login attempts")
+		return ErrAccountLocked
 	}
 
 	return nil
@@ -209,7 +258,7 @@ func (as *Service) recordFailedLogin(ctx context.Context, username string) {
 	var attempts LoginAttempts
 	attemptsData, err := as.storage.Load(ctx, key)
 	if err == nil {
-		attemptsBytes, ok := attemptsData.([]byte)
+		attemptsBytes, ok := storedBytes(attemptsData)
 		if ok {
 			if unmarshalErr := json.Unmarshal(attemptsBytes, &attempts); unmarshalErr != nil {
 				// Log error but continue with empty attempts
@@ -223,7 +272,7 @@ func (as *Service) recordFailedLogin(ctx context.Context, username string) {
 
 	attemptsBytes, _ := json.Marshal(attempts)
 	ttl := as.config.LockoutDuration * 2
-	if saveErr := as.storage.Save(ctx, key, attemptsBytes, &ttl); saveErr != nil {
+	if saveErr := as.storage.Save(ctx, key, string(attemptsBytes), &ttl); saveErr != nil {
 		slog.Error("Failed to save login attempts", "error", saveErr)
 	}
 }
@@ -236,6 +285,18 @@ func (as *Service) recordSuccessfulLogin(ctx context.Context, username string) {
 	}
 }
 
+// storedBytes returns a JSON value read from storage. Values are saved as
+// JSON strings so that they survive providers that serialize values.
+func storedBytes(v interface{}) ([]byte, bool) {
+	switch v := v.(type) {
+	case string:
+		return []byte(v), true
+	case []byte:
+		return v, true
+	}
+	return nil, false
+}
+
 // LoginAttempts tracks login attempts for a user
 type LoginAttempts struct {
 	FailedAttempts []time.Time `json:"failed_attempts"`
@@ -277,13 +338,15 @@ func (la *LoginAttempts) IsLockedOut(maxAttempts int, lockoutDuration time.Durat
 // SessionStrategy implements session-based authentication
 type SessionStrategy struct {
 	storage storage.Provider
+	users   UserStore
 	config  Config
 }
 
 // NewSessionStrategy creates a new session authentication strategy
-func NewSessionStrategy(storage storage.Provider, config Config) *SessionStrategy {
+func NewSessionStrategy(storage storage.Provider, users UserStore, config Config) *SessionStrategy {
 	return &SessionStrategy{
 		storage: storage,
+		users:   users,
 		config:  config,
 	}
 }
@@ -295,8 +358,8 @@ func (s *SessionStrategy) GetType() string {
 
 // Authenticate authenticates a user and creates a session
 func (s *SessionStrategy) Authenticate(ctx context.Context, credentials Credentials) (*Result, error) {
-	// Validate credentials (this would typically check against a database)
-	user, err := s.validateCredentials(ctx, credentials)
+	// Validate credentials against the stored password hash
+	user, err := checkCredentials(ctx, s.users, credentials)
 	if err != nil {
 		return nil, err
 	}
@@ -317,7 +380,7 @@ func (s *SessionStrategy) Authenticate(ctx context.Context, credentials Credenti
 	// Store session
 	sessionBytes, _ := json.Marshal(sessionData)
 	ttl := s.config.SessionTimeout
-	err = s.storage.Save(ctx, "session:"+sessionToken, sessionBytes, &ttl)
+	err = s.storage.Save(ctx, "session:"+sessionToken, string(sessionBytes), &ttl)
 	if err != nil {
 		return nil, fmt.Errorf("failed to store session: %w", err)
 	}
@@ -339,7 +402,7 @@ func (s *SessionStrategy) Validate(ctx context.Context, token string) (*Result,
 	}
 
 	var sessionData SessionData
-	sessionBytesData, ok := sessionBytes.([]byte)
+	sessionBytesData, ok := storedBytes(sessionBytes)
 	if !ok {
 		return nil, errors.New("invalid session data type")
 	}
@@ -391,7 +454,7 @@ func (s *SessionStrategy) Refresh(ctx context.Context, token string) (*Result, e
 	// Update session
 	sessionBytes, _ := json.Marshal(sessionData)
 	ttl := s.config.SessionTimeout
-	err = s.storage.Save(ctx, "session:"+token, sessionBytes, &ttl)
+	err = s.storage.Save(ctx, "session:"+token, string(sessionBytes), &ttl)
 	if err != nil {
 		return nil, fmt.Errorf("failed to refresh session: %w", err)
 	}
@@ -405,33 +468,6 @@ func (s *SessionStrategy) Revoke(ctx context.Context, token string) error {
 	return s.storage.Delete(ctx, "session:"+token)
 }
 
-// validateCredentials validates user credentials
-func (s *SessionStrategy) validateCredentials(_ context.Context, credentials Credentials) (*domain.User, error) {
-	// This is a simplified implementation
-	// In a real application, you would:
-	// 1. Hash the password
-	// 2. Query the database for the user
-	// 3. Compare password hashes
-	// 4. Check if the user is active
-
-	if credentials.Username == "" || credentials.Password == "" {
-		return nil, errors.New("username and password are required")
-	}
-
-	if len(credentials.Password) < s.config.PasswordMinLength {
-		return nil, errors.New("password must be at least " + strconv.Itoa(s.config.PasswordMinLength) + " characters")
-	}
-
-	// Mock user for demonstration
-	user := &domain.User{
-		ID:       1,
-		Username: credentials.Username,
-		Email:    credentials.Username + "@example.com",
-	}
-
-	return user, nil
-}
-
 // generateSessionToken generates a secure session token
 func (s *SessionStrategy) generateSessionToken() string {
 	bytes := make([]byte, 32)
@@ -457,19 +493,28 @@ type SessionData struct {
 // TOKEN-BASED AUTHENTICATION STRATEGY
 // ============================================================================
 
-// TokenStrategy implements JWT-based authentication
+// TokenStrategy implements JWT-based authentication. Access tokens are
+// signed by the key set, whose public keys are published as a JWKS, and are
+// only accepted with the configured issuer and audience.
 type TokenStrategy struct {
 	storage storage.Provider
+	users   UserStore
+	keys    *KeySet
 	config  Config
-	secret  []byte
+	now     func() time.Time
+	// consumeMu makes using up a refresh token atomic in this process when
+	// the storage is not a storage.Claimer.
+	consumeMu sync.Mutex
 }
 
 // NewTokenStrategy creates a new token authentication strategy
-func NewTokenStrategy(storage storage.Provider, config Config) *TokenStrategy {
+func NewTokenStrategy(storage storage.Provider, users UserStore, keys *KeySet, config Config) *TokenStrategy {
 	return &TokenStrategy{
 		storage: storage,
+		users:   users,
+		keys:    keys,
 		config:  config,
-		secret:  []byte("your-secret-key"), // In production, use environment variable
+		now:     time.Now,
 	}
 }
 
@@ -481,56 +526,25 @@ func (s *TokenStrategy) GetType() string {
 // Authenticate authenticates a user and creates a JWT token
 func (s *TokenStrategy) Authenticate(ctx context.Context, credentials Credentials) (*Result, error) {
 	// Validate credentials
-	user, err := s.validateCredentials(ctx, credentials)
+	user, err := checkCredentials(ctx, s.users, credentials)
 	if err != nil {
 		return nil, err
 	}
 
-	// Generate JWT token
-	token := s.generateJWTToken(user)
-
-	// Generate refresh token
-	refreshToken := s.generateRefreshToken()
-
-	// Store refresh token
-	refreshData := RefreshTokenData{
-		UserID:    user.ID,
-		Token:     refreshToken,
-		ExpiresAt: time.Now().Add(s.config.RefreshTimeout),
-	}
-
-	refreshBytes, _ := json.Marshal(refreshData)
-	ttl := s.config.RefreshTimeout
-	err = s.storage.Save(ctx, "refresh:"+refreshToken, refreshBytes, &ttl)
-	if err != nil {
-		return nil, fmt.Errorf("failed to store refresh token: %w", err)
-	}
-
-	return &Result{
-		User:         user,
-		Token:        token,
-		TokenType:    "jwt",
-		ExpiresAt:    time.Now().Add(s.config.TokenTimeout),
-		RefreshToken: refreshToken,
-	}, nil
+	return s.issue(ctx, user)
 }
 
 // Validate validates a JWT token
 func (s *TokenStrategy) Validate(_ context.Context, token string) (*Result, error) {
-	// Parse and validate JWT token
+	// Verify the signature, then the registered claims
 	claims, err := s.parseJWTToken(token)
 	if err != nil {
 		return nil, err
 	}
 
-	// Check if token has expired
-	if time.Now().After(claims.ExpiresAt) {
-		return nil, errors.New("token expired")
-	}
-
 	// Create user object from claims
 	user := &domain.User{
-		ID:       claims.UserID,
+		ID:       claims.UserID(),
 		Username: claims.Username,
 		Email:    claims.Email,
 	}
@@ -539,60 +553,60 @@ func (s *TokenStrategy) Validate(_ context.Context, token string) (*Result, erro
 		User:      user,
 		Token:     token,
 		TokenType: "jwt",
-		ExpiresAt: claims.ExpiresAt,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
 	}, nil
 }
 
-// Refresh refreshes a JWT token using a refresh token
+// Refresh refreshes a JWT token using a refresh token. The refresh token is
+// used up first, so of concurrent refreshes with one token only one
+// succeeds, and the user is loaded again, so a deleted user gets no new
+// tokens.
 func (s *TokenStrategy) Refresh(ctx context.Context, refreshToken string) (*Result, error) {
 	// Validate refresh token
 	refreshData, err := s.validateRefreshToken(ctx, refreshToken)
 	if err != nil {
 		return nil, err
 	}
-
-	// Generate new JWT token
-	user := &domain.User{
-		ID:       refreshData.UserID,
-		Username: refreshData.Username,
-		Email:    refreshData.Email,
+	if err := s.consumeRefreshToken(ctx, refreshToken); err != nil {
+		return nil, err
 	}
 
-	token := s.generateJWTToken(user)
-
-	// Generate new refresh token
-	newRefreshToken := s.generateRefreshToken()
-
-	// Update refresh token
-	newRefreshData := RefreshTokenData{
-		UserID:    refreshData.UserID,
-		Username:  refreshData.Username,
-		Email:     refreshData.Email,
-		Token:     newRefreshToken,
-		ExpiresAt: time.Now().Add(s.config.RefreshTimeout),
+	user, _, err := s.users.GetUserByID(ctx, refreshData.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("%w: user no longer exists", ErrInvalidRefresh)
 	}
-
-	newRefreshBytes, _ := json.Marshal(newRefreshData)
-	ttl := s.config.RefreshTimeout
-
-	// Store new refresh token
-	err = s.storage.Save(ctx, "refresh:"+newRefreshToken, newRefreshBytes, &ttl)
 	if err != nil {
-		return nil, fmt.Errorf("failed to store new refresh token: %w", err)
+		return nil, err
 	}
+	return s.issue(ctx, user)
+}
 
-	// Remove old refresh token
-	if deleteErr := s.storage.Delete(ctx, "refresh:"+refreshToken); deleteErr != nil {
-		slog.Error("Failed to delete old refresh token", "error", deleteErr)
+// consumeRefreshToken uses up a refresh token. It is claimed in storage
+// before it is deleted, so a token another refresh has already claimed, or
+// that is gone, is invalid.
+func (s *TokenStrategy) consumeRefreshToken(ctx context.Context, refreshToken string) error {
+	key := "refresh:" + refreshToken
+	if claimer, ok := s.storage.(storage.Claimer); ok {
+		ttl := s.config.RefreshTimeout
+		claimed, err := claimer.SaveIfAbsent(ctx, "refresh-used:"+refreshToken, true, &ttl)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return fmt.Errorf("%w: already used", ErrInvalidRefresh)
+		}
+	} else {
+		s.consumeMu.Lock()
+		defer s.consumeMu.Unlock()
+		if _, err := s.storage.Load(ctx, key); err != nil {
+			return fmt.Errorf("%w: already used", ErrInvalidRefresh)
+		}
 	}
 
-	return &Result{
-		User:         user,
-		Token:        token,
-		TokenType:    "jwt",
-		ExpiresAt:    time.Now().Add(s.config.TokenTimeout),
-		RefreshToken: newRefreshToken,
-	}, nil
+	if err := s.storage.Delete(ctx, key); err != nil {
+		slog.Error("Failed to delete old refresh token", "error", err)
+	}
+	return nil
 }
 
 // Revoke revokes a refresh token
@@ -600,89 +614,61 @@ func (s *TokenStrategy) Revoke(ctx context.Context, refreshToken string) error {
 	return s.storage.Delete(ctx, "refresh:"+refreshToken)
 }
 
-// validateCredentials validates user credentials
-func (s *TokenStrategy) validateCredentials(_ context.Context, credentials Credentials) (*domain.User, error) {
-	// Same implementation as SessionStrategy
-	if credentials.Username == "" || credentials.Password == "" {
-		return nil, errors.New("username and password are required")
-	}
-
-	if len(credentials.Password) < s.config.PasswordMinLength {
-		return nil, errors.New("password must be at least " + strconv.Itoa(s.config.PasswordMinLength) + " characters")
-	}
-
-	user := &domain.User{
-		ID:       1,
-		Username: credentials.Username,
-		Email:    credentials.Username + "@example.com",
+// issue signs an access token for user and stores a new refresh token
+func (s *TokenStrategy) issue(ctx context.Context, user *domain.User) (*Result, error) {
+	now := s.now()
+	expiresAt := now.Add(s.config.TokenTimeout)
+	token, err := s.keys.Sign(JWTClaims{
+		Issuer:    s.config.Issuer,
+		Subject:   strconv.FormatInt(user.ID, 10),
+		Audience:  Audience{s.config.Audience},
+		IssuedAt:  now.Unix(),
+		NotBefore: now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+		ID:        s.generateRefreshToken(),
+		Username:  user.Username,
+		Email:     user.Email,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign token: %w", err)
 	}
 
-	return user, nil
-}
-
-// generateJWTToken generates a JWT token
-func (s *TokenStrategy) generateJWTToken(user *domain.User) string {
-	// This is a simplified JWT implementation
-	// In production, use a proper JWT library
-
-	claims := JWTClaims{
+	refreshToken := s.generateRefreshToken()
+	refreshData := RefreshTokenData{
 		UserID:    user.ID,
 		Username:  user.Username,
 		Email:     user.Email,
-		IssuedAt:  time.Now(),
-		ExpiresAt: time.Now().Add(s.config.TokenTimeout),
+		Token:     refreshToken,
+		ExpiresAt: now.Add(s.config.RefreshTimeout),
 	}
 
-	claimsBytes, _ := json.Marshal(claims)
-	claimsB64 := base64.URLEncoding.EncodeToString(claimsBytes)
-
-	// Generate signature (simplified)
-	signature := s.generateSignature(claimsB64)
-	signatureB64 := base64.URLEncoding.EncodeToString(signature)
+	refreshBytes, _ := json.Marshal(refreshData)
+	ttl := s.config.RefreshTimeout
+	if err := s.storage.Save(ctx, "refresh:"+refreshToken, string(refreshBytes), &ttl); err != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
 
-	return fmt.Sprintf("%s.%s", claimsB64, signatureB64)
+	return &Result{
+		User:         user,
+		Token:        token,
+		TokenType:    "jwt",
+		ExpiresAt:    expiresAt,
+		RefreshToken: refreshToken,
+	}, nil
 }
 
-// parseJWTToken parses and validates a JWT token
+// parseJWTToken verifies a JWT token and validates its claims
 func (s *TokenStrategy) parseJWTToken(token string) (*JWTClaims, error) {
-	parts := strings.Split(token, ".")
-	if len(parts) != 2 {
-		return nil, errors.New("invalid token format")
-	}
-
-	claimsB64 := parts[0]
-	signatureB64 := parts[1]
-
-	// Verify signature
-	expectedSignature := s.generateSignature(claimsB64)
-	expectedSignatureB64 := base64.URLEncoding.EncodeToString(expectedSignature)
-
-	if signatureB64 != expectedSignatureB64 {
-		return nil, errors.New("invalid signature")
-	}
-
-	// Parse claims
-	claimsBytes, err := base64.URLEncoding.DecodeString(claimsB64)
-	if err != nil {
-		return nil, errors.New("invalid claims encoding")
-	}
-
 	var claims JWTClaims
-	if err := json.Unmarshal(claimsBytes, &claims); err != nil {
-		return nil, errors.New("invalid claims format")
+	if err := s.keys.Verify(token, &claims); err != nil {
+		return nil, err
+	}
+	if err := claims.validate(s.config, s.now()); err != nil {
+		return nil, err
 	}
-
 	return &claims, nil
 }
 
-// generateSignature generates a signature for JWT claims
-func (s *TokenStrategy) generateSignature(claimsB64 string) []byte {
-	hash := sha256.New()
-	hash.Write([]byte(claimsB64))
-	hash.Write(s.secret)
-	return hash.Sum(nil)
-}
-
 // generateRefreshToken generates a secure refresh token
 func (s *TokenStrategy) generateRefreshToken() string {
 	bytes := make([]byte, 32)
@@ -698,16 +684,16 @@ func (s *TokenStrategy) generateRefreshToken() string {
 func (s *TokenStrategy) validateRefreshToken(ctx context.Context, refreshToken string) (*RefreshTokenData, error) {
 	refreshBytes, err := s.storage.Load(ctx, "refresh:"+refreshToken)
 	if err != nil {
-		return nil, errors.New("invalid refresh token")
+		return nil, ErrInvalidRefresh
 	}
 
 	var refreshData RefreshTokenData
-	refreshBytesData, ok := refreshBytes.([]byte)
+	refreshBytesData, ok := storedBytes(refreshBytes)
 	if !ok {
-		return nil, errors.New("invalid refresh token data type")
+		return nil, fmt.Errorf("%w: bad data type", ErrInvalidRefresh)
 	}
 	if err := json.Unmarshal(refreshBytesData, &refreshData); err != nil {
-		return nil, errors.New("invalid refresh token data")
+		return nil, fmt.Errorf("%w: bad data", ErrInvalidRefresh)
 	}
 
 	if time.Now().After(refreshData.ExpiresAt) {
@@ -715,19 +701,77 @@ func (s *TokenStrategy) validateRefreshToken(ctx context.Context, refreshToken s
 		if deleteErr := s.storage.Delete(ctx, "refresh:"+refreshToken); deleteErr != nil {
 			slog.Error("Failed to delete expired refresh token", "error", deleteErr)
 		}
-		return nil, errors.New("refresh token expired")
+		return nil, fmt.Errorf("%w: expired", ErrInvalidRefresh)
 	}
 
 	return &refreshData, nil
 }
 
-// JWTClaims represents JWT token claims
+// JWTClaims represents JWT token claims (RFC 7519). Times are seconds since
+// the epoch; the subject is the user ID.
 type JWTClaims struct {
-	UserID    int64     `json:"user_id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	IssuedAt  time.Time `json:"iat"`
-	ExpiresAt time.Time `json:"exp"`
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Audience  Audience `json:"aud"`
+	IssuedAt  int64    `json:"iat"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	ExpiresAt int64    `json:"exp"`
+	ID        string   `json:"jti,omitempty"`
+	Username  string   `json:"username"`
+	Email     string   `json:"email,omitempty"`
+}
+
+// UserID returns the user ID held in the subject, or 0 if it is not one.
+func (c *JWTClaims) UserID() int64 {
+	id, _ := strconv.ParseInt(c.Subject, 10, 64)
+	return id
+}
+
+// validate checks the issuer, audience, subject and validity window, allowing
+// config.ClockSkew either way.
+func (c *JWTClaims) validate(config Config, now time.Time) error {
+	if c.Issuer != config.Issuer {
+		return fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+	if !slices.Contains(c.Audience, config.Audience) {
+		return fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+	if c.UserID() <= 0 {
+		return fmt.Errorf("%w: bad subject", ErrInvalidToken)
+	}
+	if c.ExpiresAt == 0 || !now.Before(time.Unix(c.ExpiresAt, 0).Add(config.ClockSkew)) {
+		return ErrTokenExpired
+	}
+	if c.NotBefore != 0 && now.Add(config.ClockSkew).Before(time.Unix(c.NotBefore, 0)) {
+		return fmt.Errorf("%w: not valid yet", ErrInvalidToken)
+	}
+	return nil
+}
+
+// Audience is the aud claim, which may be a single string or an array.
+type Audience []string
+
+// MarshalJSON writes a single audience as a string.
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// UnmarshalJSON accepts a string or an array of strings.
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
 }
 
 // RefreshTokenData represents refresh token information
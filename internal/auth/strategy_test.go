@@ -0,0 +1,253 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/repository"
+	"github.com/mdco1990/webapp/internal/storage"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeUsers is a UserStore holding one user with a bcrypt hash.
+type fakeUsers struct {
+	user *domain.User
+	hash string
+}
+
+func (f fakeUsers) GetUserByUsername(_ context.Context, username string) (*domain.User, string, error) {
+	if username != f.user.Username {
+		return nil, "", repository.ErrNotFound
+	}
+	return f.user, f.hash, nil
+}
+
+func (f fakeUsers) GetUserByID(_ context.Context, id int64) (*domain.User, string, error) {
+	if f.user == nil || id != f.user.ID {
+		return nil, "", repository.ErrNotFound
+	}
+	return f.user, f.hash, nil
+}
+
+func newTestStrategy(t *testing.T, keys *KeySet) *TokenStrategy {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	users := fakeUsers{user: &domain.User{ID: 7, Username: "alice"}, hash: string(hash)}
+	return NewTokenStrategy(storage.NewMemoryStorage(storage.Options{}), users, keys, Config{
+		TokenTimeout:   15 * time.Minute,
+		RefreshTimeout: time.Hour,
+		Issuer:         "webapp",
+		Audience:       "webapp-api",
+		ClockSkew:      30 * time.Second,
+	})
+}
+
+func TestTokenStrategy(t *testing.T) {
+	key, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newTestStrategy(t, NewKeySet(key))
+	ctx := context.Background()
+
+	if _, err := s.Authenticate(ctx, Credentials{Username: "alice", Password: "wrong password"}); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected a wrong password refused, got %v", err)
+	}
+	if _, err := s.Authenticate(ctx, Credentials{Username: "bob", Password: "correct horse"}); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected an unknown user refused, got %v", err)
+	}
+
+	result, err := s.Authenticate(ctx, Credentials{Username: "alice", Password: "correct horse"})
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	if strings.Count(result.Token, ".") != 2 || result.RefreshToken == "" {
+		t.Fatalf("Expected a compact JWT and a refresh token, got %+v", result)
+	}
+	valid, err := s.Validate(ctx, result.Token)
+	if err != nil || valid.User.ID != 7 || valid.User.Username != "alice" {
+		t.Fatalf("Expected the token to validate as user 7, got %+v (%v)", valid, err)
+	}
+
+	// A changed payload no longer matches the signature.
+	parts := strings.Split(result.Token, ".")
+	forged := parts[0] + "." + base64.RawURLEncoding.EncodeToString(
+		[]byte(`{"iss":"webapp","sub":"1","aud":"webapp-api","exp":9999999999}`)) + "." + parts[2]
+	if _, err := s.Validate(ctx, forged); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("Expected a forged payload refused, got %v", err)
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT","kid":"`+key.ID+`"}`)) +
+		"." + parts[1] + "."
+	if _, err := s.Validate(ctx, unsigned); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected an unsigned token refused, got %v", err)
+	}
+
+	// Tokens are bound to the issuer and audience.
+	other := newTestStrategy(t, s.keys)
+	other.config.Audience = "another-api"
+	if _, err := other.Validate(ctx, result.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected a token for another audience refused, got %v", err)
+	}
+	other.config.Audience, other.config.Issuer = "webapp-api", "elsewhere"
+	if _, err := other.Validate(ctx, result.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected a token from another issuer refused, got %v", err)
+	}
+
+	// Expiry allows for clock skew.
+	s.now = func() time.Time { return time.Now().Add(15*time.Minute + 10*time.Second) }
+	if _, err := s.Validate(ctx, result.Token); err != nil {
+		t.Errorf("Expected a token within the clock skew accepted, got %v", err)
+	}
+	s.now = func() time.Time { return time.Now().Add(16 * time.Minute) }
+	if _, err := s.Validate(ctx, result.Token); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("Expected an expired token refused, got %v", err)
+	}
+	s.now = time.Now
+
+	refreshed, err := s.Refresh(ctx, result.RefreshToken)
+	if err != nil || refreshed.User.Username != "alice" || refreshed.RefreshToken == result.RefreshToken {
+		t.Fatalf("Expected a new token pair, got %+v (%v)", refreshed, err)
+	}
+	if _, err := s.Refresh(ctx, result.RefreshToken); err == nil {
+		t.Error("Expected a used refresh token refused")
+	}
+}
+
+func TestTokenStrategyRefreshOnce(t *testing.T) {
+	key, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	creds := Credentials{Username: "alice", Password: "correct horse"}
+
+	// Only one of concurrent refreshes with one token succeeds, whether the
+	// storage can claim keys or not.
+	claiming := newTestStrategy(t, NewKeySet(key))
+	plain := newTestStrategy(t, NewKeySet(key))
+	plain.storage = struct{ storage.Provider }{plain.storage}
+	for name, s := range map[string]*TokenStrategy{"claimer": claiming, "plain": plain} {
+		result, err := s.Authenticate(ctx, creds)
+		if err != nil {
+			t.Fatalf("Authenticate failed: %v", err)
+		}
+		var (
+			wg        sync.WaitGroup
+			refreshed atomic.Int32
+		)
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := s.Refresh(ctx, result.RefreshToken); err == nil {
+					refreshed.Add(1)
+				} else if !errors.Is(err, ErrInvalidRefresh) {
+					t.Errorf("%s: expected ErrInvalidRefresh, got %v", name, err)
+				}
+			}()
+		}
+		wg.Wait()
+		if n := refreshed.Load(); n != 1 {
+			t.Errorf("%s: expected one refresh to succeed, got %d", name, n)
+		}
+	}
+
+	// A deleted user's refresh token is refused.
+	result, err := claiming.Authenticate(ctx, creds)
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	claiming.users = fakeUsers{}
+	if _, err := claiming.Refresh(ctx, result.RefreshToken); !errors.Is(err, ErrInvalidRefresh) {
+		t.Errorf("Expected a deleted user's refresh refused, got %v", err)
+	}
+}
+
+func TestKeySetRotation(t *testing.T) {
+	oldKey, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newKey, err := NewSigningKey(rsaKey)
+	if err != nil || newKey.Algorithm != AlgRS256 {
+		t.Fatalf("Expected an RS256 key, got %+v (%v)", newKey, err)
+	}
+
+	keys := NewKeySet(oldKey)
+	s := newTestStrategy(t, keys)
+	ctx := context.Background()
+	creds := Credentials{Username: "alice", Password: "correct horse"}
+	before, err := s.Authenticate(ctx, creds)
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+
+	keys.Rotate(newKey)
+	after, err := s.Authenticate(ctx, creds)
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	set := keys.JWKS()
+	if len(set.Keys) != 2 || set.Keys[0].Kid != newKey.ID || set.Keys[0].Kty != "RSA" || set.Keys[1].Crv != "Ed25519" {
+		t.Fatalf("Expected the new RSA key first and the old Ed25519 key, got %+v", set.Keys)
+	}
+	for _, token := range []string{before.Token, after.Token} {
+		if _, err := s.Validate(ctx, token); err != nil {
+			t.Errorf("Expected tokens from both keys accepted, got %v", err)
+		}
+	}
+
+	keys.Retire(oldKey.ID)
+	if _, err := s.Validate(ctx, before.Token); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Expected a token from a retired key refused, got %v", err)
+	}
+	if _, err := s.Validate(ctx, after.Token); err != nil {
+		t.Errorf("Expected a token from the current key accepted, got %v", err)
+	}
+
+	small, _ := rsa.GenerateKey(rand.Reader, 1024)
+	if _, err := NewSigningKey(small); err == nil {
+		t.Error("Expected a 1024-bit RSA key refused")
+	}
+}
+
+func TestTokenStrategySerializingStore(t *testing.T) {
+	key, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "auth.db"), storage.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = store.Close(context.Background()) }()
+	s := newTestStrategy(t, NewKeySet(key))
+	s.storage = store
+	ctx := context.Background()
+
+	// Refresh tokens come back from the SQLite store as JSON strings.
+	result, err := s.Authenticate(ctx, Credentials{Username: "alice", Password: "correct horse"})
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	if refreshed, err := s.Refresh(ctx, result.RefreshToken); err != nil || refreshed.User.ID != 7 {
+		t.Errorf("Expected the refresh token accepted, got %+v (%v)", refreshed, err)
+	}
+}
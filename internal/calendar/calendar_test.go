@@ -0,0 +1,122 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/export"
+)
+
+func samplePayments() []domain.ScheduledPayment {
+	updated := time.Date(2024, 1, 10, 8, 30, 0, 0, time.UTC)
+	return []domain.ScheduledPayment{
+		{Kind: domain.ScheduledIncome, SourceID: 42, Name: "Salary", YearMonth: domain.YearMonth{Year: 2024, Month: 2},
+			DayOfMonth: 31, AmountCents: 250000, UpdatedAt: updated},
+		{Kind: domain.ScheduledBudget, SourceID: 7, Name: "Rent; flat, 2nd floor", YearMonth: domain.YearMonth{Year: 2024, Month: 3},
+			DayOfMonth: 1, AmountCents: 90000, UpdatedAt: updated},
+	}
+}
+
+func TestWriteFeed(t *testing.T) {
+	feed := BuildFeed(samplePayments(), FeedOptions{Format: export.NewFormat("en", "EUR"), ReminderDays: 1, Refresh: 6 * time.Hour})
+	var buf bytes.Buffer
+	if err := Write(&buf, feed); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	out := buf.String()
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if strings.Contains(line, "\n") {
+			t.Fatalf("Expected CRLF line endings only: %q", line)
+		}
+		if len(line) > maxLineOctets {
+			t.Errorf("Line exceeds %d octets: %q", maxLineOctets, line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	for _, s := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"REFRESH-INTERVAL;VALUE=DURATION:PT6H\r\n",
+		"UID:income-42@webapp\r\n",
+		"UID:budget-7@webapp\r\n",
+		"DTSTART;VALUE=DATE:20240229\r\n", // the 31st clamps to the end of February
+		"DTEND;VALUE=DATE:20240301\r\n",
+		"DTSTAMP:20240110T083000Z\r\n",
+		"SUMMARY:Payday: Salary (€2\\,500.00)\r\n",
+		"SUMMARY:Due: Rent\\; flat\\, 2nd floor (€900.00)\r\n",
+		"BEGIN:VALARM\r\nACTION:DISPLAY\r\n",
+		"TRIGGER:-PT15H\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(unfolded, s) {
+			t.Errorf("Expected feed to contain %q:\n%s", s, unfolded)
+		}
+	}
+
+	// Same input, same bytes: clients see no spurious changes on refresh.
+	var again bytes.Buffer
+	_ = Write(&again, BuildFeed(samplePayments(), FeedOptions{Format: export.NewFormat("en", "EUR"), ReminderDays: 1, Refresh: 6 * time.Hour}))
+	if again.String() != out {
+		t.Error("Expected identical output for identical input")
+	}
+}
+
+func TestBuildFeedReminders(t *testing.T) {
+	tests := []struct {
+		days     int
+		expected string
+	}{
+		{-1, ""},
+		{0, "PT9H"},
+		{3, "-PT63H"},
+	}
+	for _, tt := range tests {
+		feed := BuildFeed(samplePayments()[:1], FeedOptions{ReminderDays: tt.days})
+		alarm := feed.Events[0].Alarm
+		switch {
+		case tt.expected == "" && alarm != nil:
+			t.Errorf("days=%d: Expected no alarm, got %v", tt.days, *alarm)
+		case tt.expected != "" && (alarm == nil || trigger(*alarm) != tt.expected):
+			t.Errorf("days=%d: Expected trigger %s, got %v", tt.days, tt.expected, alarm)
+		}
+	}
+}
+
+func TestWriteFoldedUTF8(t *testing.T) {
+	var buf bytes.Buffer
+	name := strings.Repeat("é", 60)
+	if err := Write(&buf, Calendar{Name: name}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		if !utf8.ValidString(line) {
+			t.Errorf("Folding split a UTF-8 sequence: %q", line)
+		}
+	}
+	if !strings.Contains(strings.ReplaceAll(buf.String(), "\r\n ", ""), "X-WR-CALNAME:"+name+"\r\n") {
+		t.Errorf("Expected unfolded name to round-trip")
+	}
+}
+
+func TestEventDate(t *testing.T) {
+	tests := []struct {
+		ym       domain.YearMonth
+		day      int
+		expected string
+	}{
+		{domain.YearMonth{Year: 2024, Month: 2}, 30, "2024-02-29"},
+		{domain.YearMonth{Year: 2023, Month: 2}, 31, "2023-02-28"},
+		{domain.YearMonth{Year: 2024, Month: 4}, 31, "2024-04-30"},
+		{domain.YearMonth{Year: 2024, Month: 12}, 15, "2024-12-15"},
+	}
+	for _, tt := range tests {
+		if got := EventDate(tt.ym, tt.day).Format(time.DateOnly); got != tt.expected {
+			t.Errorf("EventDate(%v, %d): Expected %s, got %s", tt.ym, tt.day, tt.expected, got)
+		}
+	}
+}
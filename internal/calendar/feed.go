@@ -0,0 +1,77 @@
+package calendar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/export"
+)
+
+// DefaultUIDDomain qualifies event UIDs ("income-42@webapp").
+const DefaultUIDDomain = "webapp"
+
+// reminderHour is the local time of day reminders fire.
+const reminderHour = 9
+
+// FeedOptions controls how scheduled payments become events.
+type FeedOptions struct {
+	Format export.Format // zero value means English and euros
+	// ReminderDays is how many days before the event the reminder fires (at
+	// 09:00); negative disables reminders.
+	ReminderDays int
+	UIDDomain    string
+	Refresh      time.Duration
+}
+
+// UID returns the stable event UID for a source row. It depends only on the
+// row's kind and ID, so renaming a source or moving its day updates the
+// existing event.
+func UID(kind string, sourceID int64, uidDomain string) string {
+	if uidDomain == "" {
+		uidDomain = DefaultUIDDomain
+	}
+	return fmt.Sprintf("%s-%d@%s", kind, sourceID, uidDomain)
+}
+
+// EventDate places a pay/due day in its month, clamping days past the end of
+// the month (a 31st in February falls on the 28th or 29th).
+func EventDate(ym domain.YearMonth, day int) time.Time {
+	last := time.Date(ym.Year, time.Month(ym.Month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day > last {
+		day = last
+	}
+	if day < 1 {
+		day = 1
+	}
+	return time.Date(ym.Year, time.Month(ym.Month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// BuildFeed turns scheduled payments into a calendar.
+func BuildFeed(payments []domain.ScheduledPayment, opts FeedOptions) Calendar {
+	f := export.NewFormat(opts.Format.Locale, opts.Format.Currency)
+	var alarm *time.Duration
+	if opts.ReminderDays >= 0 {
+		d := time.Duration(opts.ReminderDays)*24*time.Hour - reminderHour*time.Hour
+		alarm = &d
+	}
+
+	events := make([]Event, 0, len(payments))
+	for _, p := range payments {
+		label, category := f.T("payday"), f.T("income")
+		if p.Kind == domain.ScheduledBudget {
+			label, category = f.T("due"), f.T("budget")
+		}
+		amount := f.Money(p.AmountCents)
+		events = append(events, Event{
+			UID:          UID(p.Kind, p.SourceID, opts.UIDDomain),
+			Date:         EventDate(p.YearMonth, p.DayOfMonth),
+			Summary:      fmt.Sprintf("%s: %s (%s)", label, p.Name, amount),
+			Description:  fmt.Sprintf("%s · %s %d · %s", category, f.MonthName(p.Month), p.Year, amount),
+			Categories:   []string{category},
+			LastModified: p.UpdatedAt,
+			Alarm:        alarm,
+		})
+	}
+	return Calendar{Name: f.T("calendar_name"), Refresh: opts.Refresh, Events: events}
+}
@@ -0,0 +1,138 @@
+// Package calendar renders iCalendar (RFC 5545) feeds of upcoming paydays and
+// budget due dates that phone and desktop calendars can subscribe to.
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ProdID identifies this application in generated feeds.
+const ProdID = "-//mdco1990//webapp//EN"
+
+// maxLineOctets is the RFC 5545 content line limit, excluding CRLF.
+const maxLineOctets = 75
+
+// Event is an all-day VEVENT with an optional display alarm.
+type Event struct {
+	// UID must stay the same across feed refreshes so clients update the
+	// event in place instead of adding a duplicate.
+	UID          string
+	Date         time.Time // only the calendar date is used
+	Summary      string
+	Description  string
+	Categories   []string
+	LastModified time.Time
+	// Alarm is how long before the start of the day the reminder fires;
+	// negative values fire during the day (e.g. -9h is 09:00). Nil disables it.
+	Alarm *time.Duration
+}
+
+// Calendar is a published VCALENDAR.
+type Calendar struct {
+	Name    string
+	Refresh time.Duration // suggested polling interval; 0 omits it
+	Events  []Event
+}
+
+// Write serialises c with CRLF line endings and folded long lines.
+func Write(w io.Writer, c Calendar) error {
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeFolded(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", ProdID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		line("X-WR-CALNAME", escapeText(c.Name))
+	}
+	if c.Refresh > 0 {
+		line("REFRESH-INTERVAL;VALUE=DURATION", duration(c.Refresh))
+		line("X-PUBLISHED-TTL", duration(c.Refresh))
+	}
+
+	for _, e := range c.Events {
+		modified := e.LastModified.UTC().Format("20060102T150405Z")
+		line("BEGIN", "VEVENT")
+		line("UID", e.UID)
+		// DTSTAMP follows the source row so unchanged events serialise identically.
+		line("DTSTAMP", modified)
+		line("LAST-MODIFIED", modified)
+		line("DTSTART;VALUE=DATE", e.Date.Format("20060102"))
+		line("DTEND;VALUE=DATE", e.Date.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY", escapeText(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION", escapeText(e.Description))
+		}
+		if len(e.Categories) > 0 {
+			cats := make([]string, len(e.Categories))
+			for i, c := range e.Categories {
+				cats[i] = escapeText(c)
+			}
+			line("CATEGORIES", strings.Join(cats, ","))
+		}
+		line("TRANSP", "TRANSPARENT")
+		if e.Alarm != nil {
+			line("BEGIN", "VALARM")
+			line("ACTION", "DISPLAY")
+			line("DESCRIPTION", escapeText(e.Summary))
+			line("TRIGGER", trigger(*e.Alarm))
+			line("END", "VALARM")
+		}
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+// escapeText escapes a TEXT value (RFC 5545 section 3.3.11).
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", "").Replace(s)
+}
+
+// writeFolded writes a content line, folding it at 75 octets without
+// splitting a UTF-8 sequence. Continuation lines start with a space.
+func writeFolded(w *bufio.Writer, s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		_, _ = w.WriteString(s[:cut])
+		_, _ = w.WriteString("\r\n ")
+		s = s[cut:]
+		limit = maxLineOctets - 1 // the leading space counts
+	}
+	_, _ = w.WriteString(s)
+	_, _ = w.WriteString("\r\n")
+}
+
+// duration formats a positive duration as "PT12H" / "PT90M" / "P1D".
+func duration(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("P%dD", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("PT%dH", d/time.Hour)
+	default:
+		return fmt.Sprintf("PT%dM", d/time.Minute)
+	}
+}
+
+// trigger formats an alarm offset relative to the event start.
+func trigger(before time.Duration) string {
+	if before <= 0 {
+		return duration(-before)
+	}
+	return "-" + duration(before)
+}
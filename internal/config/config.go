@@ -22,6 +22,7 @@ type Config struct {
 	DBDSN              string // used when DBDriver!=sqlite
 	CORSAllowedOrigins []string
 	APIKey             string
+	ShareLinkSecret    string // HMAC key for share link tokens; random per process if empty
 	Env                string // dev or prod
 	// Logging
 	LogLevel  string // debug, info, warn, error
@@ -31,6 +32,29 @@ type Config struct {
 	ReadTimeout       time.Duration
 	WriteTimeout      time.Duration
 	IdleTimeout       time.Duration
+	// IdempotencyTTL is how long responses to Idempotency-Key requests are replayed.
+	IdempotencyTTL time.Duration
+	// TrashRetention is how long deleted records can be restored before they
+	// are purged; zero keeps them until purged by hand.
+	TrashRetention time.Duration
+	// Rate limits in requests per minute; zero disables a limit.
+	RateLimitPerMinute      int // every request, per client IP
+	APIRateLimitPerMinute   int // API requests, per user
+	LoginRateLimitPerMinute int // login and registration attempts, per client IP
+	// RateLimitStore holds rate limit state: memory, or sqlite at
+	// RateLimitStorePath so that instances sharing the file share limits.
+	RateLimitStore     string
+	RateLimitStorePath string
+	// TrustedProxies are the proxy addresses or CIDRs whose X-Forwarded-For
+	// header is believed when finding a client's IP.
+	TrustedProxies []string
+	// JWTSigningKeyFile is a PEM Ed25519 or RSA private key that signs JWTs;
+	// JWTPreviousKeyFiles are rotated-out keys whose tokens are still
+	// accepted. Without a signing key one is generated per process.
+	JWTSigningKeyFile   string
+	JWTPreviousKeyFiles []string
+	JWTIssuer           string
+	JWTAudience         string
 }
 
 // Load reads configuration from environment variables and optional .env file.
@@ -43,9 +67,18 @@ func Load() Config {
 		DBDriver:    getenv("DB_DRIVER", "sqlite"),
 		DBDSN:       os.Getenv("DB_DSN"),
 		APIKey:      os.Getenv("API_KEY"),
-		Env:         getenv("ENV", "dev"),
-		LogLevel:    getenv("LOG_LEVEL", "info"),
-		LogFormat:   getenv("LOG_FORMAT", "json"),
+		// SHARE_LINK_SECRET keeps share links valid across restarts and replicas.
+		ShareLinkSecret: os.Getenv("SHARE_LINK_SECRET"),
+		Env:             getenv("ENV", "dev"),
+		LogLevel:        getenv("LOG_LEVEL", "info"),
+		LogFormat:       getenv("LOG_FORMAT", "json"),
+		// Rate limiting
+		RateLimitStore:     getenv("RATE_LIMIT_STORE", "memory"),
+		RateLimitStorePath: getenv("RATE_LIMIT_STORE_PATH", "./data/ratelimit.db"),
+		// JWT signing
+		JWTSigningKeyFile: os.Getenv("JWT_SIGNING_KEY_FILE"),
+		JWTIssuer:         getenv("JWT_ISSUER", "webapp"),
+		JWTAudience:       getenv("JWT_AUDIENCE", "webapp-api"),
 	}
 
 	// HTTP timeouts (defaults suitable for APIs)
@@ -53,6 +86,21 @@ func Load() Config {
 	cfg.ReadTimeout = durationFromMillis(getenv("HTTP_READ_TIMEOUT_MS", "15000"))
 	cfg.WriteTimeout = durationFromMillis(getenv("HTTP_WRITE_TIMEOUT_MS", "15000"))
 	cfg.IdleTimeout = durationFromMillis(getenv("HTTP_IDLE_TIMEOUT_MS", "60000"))
+	cfg.IdempotencyTTL = durationFromMillis(getenv("IDEMPOTENCY_TTL_MS", "86400000"))
+	cfg.TrashRetention = time.Duration(max(ParseInt("TRASH_RETENTION_DAYS", 30), 0)) * 24 * time.Hour
+	cfg.RateLimitPerMinute = max(ParseInt("RATE_LIMIT_PER_MINUTE", 600), 0)
+	cfg.APIRateLimitPerMinute = max(ParseInt("API_RATE_LIMIT_PER_MINUTE", 300), 0)
+	cfg.LoginRateLimitPerMinute = max(ParseInt("LOGIN_RATE_LIMIT_PER_MINUTE", 5), 0)
+	for _, p := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			cfg.TrustedProxies = append(cfg.TrustedProxies, p)
+		}
+	}
+	for _, f := range strings.Split(os.Getenv("JWT_PREVIOUS_KEY_FILES"), ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			cfg.JWTPreviousKeyFiles = append(cfg.JWTPreviousKeyFiles, f)
+		}
+	}
 
 	origins := getenv("CORS_ALLOWED_ORIGINS", "http://localhost:5173")
 	// split by comma or space
@@ -80,6 +128,11 @@ func Load() Config {
 			log.Fatal("DB_DSN must not be empty for mysql driver")
 		}
 	}
+	switch cfg.RateLimitStore {
+	case "memory", "sqlite":
+	default:
+		log.Fatalf("RATE_LIMIT_STORE must be memory or sqlite, got %q", cfg.RateLimitStore)
+	}
 
 	return cfg
 }
@@ -15,6 +15,10 @@ func TestLoad(t *testing.T) {
 	t.Setenv("ENV", "")
 	t.Setenv("LOG_LEVEL", "")
 	t.Setenv("LOG_FORMAT", "")
+	t.Setenv("TRASH_RETENTION_DAYS", "")
+	t.Setenv("LOGIN_RATE_LIMIT_PER_MINUTE", "")
+	t.Setenv("RATE_LIMIT_STORE", "")
+	t.Setenv("TRUSTED_PROXIES", "")
 
 	// Test default values
 	cfg := Load()
@@ -42,6 +46,15 @@ func TestLoad(t *testing.T) {
 	if cfg.LogFormat != "json" {
 		t.Errorf("Expected default LOG_FORMAT json, got %s", cfg.LogFormat)
 	}
+
+	if cfg.TrashRetention != 30*24*time.Hour {
+		t.Errorf("Expected default TRASH_RETENTION_DAYS 30, got %s", cfg.TrashRetention)
+	}
+
+	if cfg.LoginRateLimitPerMinute != 5 || cfg.RateLimitStore != "memory" || len(cfg.TrustedProxies) != 0 {
+		t.Errorf("Expected default rate limiting, got %d %s %v",
+			cfg.LoginRateLimitPerMinute, cfg.RateLimitStore, cfg.TrustedProxies)
+	}
 }
 
 func TestLoadWithEnvironment(t *testing.T) {
@@ -52,6 +65,9 @@ func TestLoadWithEnvironment(t *testing.T) {
 	t.Setenv("ENV", "prod")
 	t.Setenv("LOG_LEVEL", "debug")
 	t.Setenv("LOG_FORMAT", "text")
+	t.Setenv("LOGIN_RATE_LIMIT_PER_MINUTE", "0")
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.1")
+	t.Setenv("JWT_PREVIOUS_KEY_FILES", "keys/old.pem,keys/older.pem")
 
 	cfg := Load()
 
@@ -78,6 +94,18 @@ func TestLoadWithEnvironment(t *testing.T) {
 	if cfg.LogFormat != "text" {
 		t.Errorf("Expected LOG_FORMAT text, got %s", cfg.LogFormat)
 	}
+
+	if cfg.LoginRateLimitPerMinute != 0 {
+		t.Errorf("Expected LOGIN_RATE_LIMIT_PER_MINUTE 0, got %d", cfg.LoginRateLimitPerMinute)
+	}
+
+	if len(cfg.TrustedProxies) != 2 || cfg.TrustedProxies[1] != "192.168.1.1" {
+		t.Errorf("Expected 2 TRUSTED_PROXIES, got %v", cfg.TrustedProxies)
+	}
+
+	if len(cfg.JWTPreviousKeyFiles) != 2 || cfg.JWTPreviousKeyFiles[0] != "keys/old.pem" {
+		t.Errorf("Expected 2 JWT_PREVIOUS_KEY_FILES, got %v", cfg.JWTPreviousKeyFiles)
+	}
 }
 
 func TestCORSAllowedOrigins(t *testing.T) {
@@ -52,6 +52,19 @@ func Migrate(db *sql.DB) error {
 	if err != nil {
 		return err
 	}
+	// Search indexes created by this run are filled from the existing rows
+	// below; ones that already exist are kept up to date by their triggers.
+	var newIndexes []string
+	for _, index := range searchIndexes {
+		var exists int
+		if err := db.QueryRow(`SELECT COUNT(1) FROM sqlite_master WHERE type = 'table' AND name = ?`, index).
+			Scan(&exists); err != nil {
+			return err
+		}
+		if exists == 0 {
+			newIndexes = append(newIndexes, index)
+		}
+	}
 	if _, err = db.Exec(string(b)); err != nil {
 		return err
 	}
@@ -72,5 +85,40 @@ func Migrate(db *sql.DB) error {
 			_, _ = db.Exec(`UPDATE users SET is_admin = 1 WHERE username = 'admin'`)
 		}
 	}
+	// Columns added after the initial schema (SQLite only, like is_admin above).
+	addColumnIfMissing(db, "income_sources", "day_of_month", "INTEGER")
+	addColumnIfMissing(db, "budget_sources", "day_of_month", "INTEGER")
+	addColumnIfMissing(db, "income_sources", "version", "INTEGER NOT NULL DEFAULT 1")
+	addColumnIfMissing(db, "budget_sources", "version", "INTEGER NOT NULL DEFAULT 1")
+	addColumnIfMissing(db, "manual_budgets", "version", "INTEGER NOT NULL DEFAULT 1")
+	for _, table := range []string{"income_sources", "budget_sources", "expense", "manual_budget_items"} {
+		addColumnIfMissing(db, table, "deleted_at", "DATETIME")
+	}
+	// The search triggers only see writes made after they were created.
+	for _, index := range newIndexes {
+		if _, err := db.Exec(fmt.Sprintf(`INSERT INTO %[1]s(%[1]s) VALUES('rebuild')`, index)); err != nil {
+			return fmt.Errorf("rebuild %s: %w", index, err)
+		}
+	}
 	return nil
 }
+
+// searchIndexes are the FTS5 tables created by schema.sql.
+var searchIndexes = []string{"expense_fts", "income_sources_fts", "budget_sources_fts", "manual_budget_items_fts"}
+
+// addColumnIfMissing adds a nullable or defaulted column to an existing SQLite table. It is a
+// no-op when pragma_table_info is unavailable (MySQL uses the init scripts).
+func addColumnIfMissing(db *sql.DB, table, column, definition string) {
+	var exists int
+	err := db.QueryRow(
+		`SELECT COUNT(1) FROM pragma_table_info(?) WHERE name = ?`, table, column,
+	).Scan(&exists)
+	if err != nil || exists > 0 {
+		return
+	}
+	if _, err := db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, definition)); err != nil {
+		log.Printf("db: failed to add %s.%s column: %v", table, column, err)
+		return
+	}
+	log.Printf("db: added %s column to %s", column, table)
+}
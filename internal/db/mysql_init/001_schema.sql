@@ -24,10 +24,14 @@ CREATE TABLE IF NOT EXISTS income_sources (
   year INT NOT NULL,
   month INT NOT NULL,
   amount_cents BIGINT NOT NULL,
+  day_of_month TINYINT NULL,
+  version BIGINT NOT NULL DEFAULT 1,
   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  deleted_at TIMESTAMP NULL,
   CONSTRAINT fk_income_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
-  INDEX idx_income_user_year_month (user_id, year, month)
+  INDEX idx_income_user_year_month (user_id, year, month),
+  FULLTEXT INDEX ft_income_name (name)
 );
 
 CREATE TABLE IF NOT EXISTS budget_sources (
@@ -37,10 +41,14 @@ CREATE TABLE IF NOT EXISTS budget_sources (
   year INT NOT NULL,
   month INT NOT NULL,
   amount_cents BIGINT NOT NULL,
+  day_of_month TINYINT NULL,
+  version BIGINT NOT NULL DEFAULT 1,
   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  deleted_at TIMESTAMP NULL,
   CONSTRAINT fk_budget_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
-  INDEX idx_budget_user_year_month (user_id, year, month)
+  INDEX idx_budget_user_year_month (user_id, year, month),
+  FULLTEXT INDEX ft_budget_name (name)
 );
 
 CREATE TABLE IF NOT EXISTS expense (
@@ -51,7 +59,165 @@ CREATE TABLE IF NOT EXISTS expense (
   description TEXT NOT NULL,
   amount_cents BIGINT NOT NULL,
   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-  INDEX idx_expense_year_month (year, month)
+  deleted_at TIMESTAMP NULL,
+  INDEX idx_expense_year_month (year, month),
+  FULLTEXT INDEX ft_expense_text (description, category)
+);
+
+CREATE TABLE IF NOT EXISTS manual_budgets (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  user_id BIGINT NOT NULL,
+  year INT NOT NULL,
+  month INT NOT NULL,
+  bank_amount_cents BIGINT NOT NULL DEFAULT 0,
+  version BIGINT NOT NULL DEFAULT 1,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  UNIQUE KEY uq_manual_budgets_user_year_month (user_id, year, month),
+  CONSTRAINT fk_manual_budgets_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS manual_budget_items (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  budget_id BIGINT NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  amount_cents BIGINT NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  deleted_at TIMESTAMP NULL,
+  CONSTRAINT fk_manual_budget_items_budget FOREIGN KEY (budget_id) REFERENCES manual_budgets(id) ON DELETE CASCADE,
+  INDEX idx_manual_budget_items_budget (budget_id),
+  FULLTEXT INDEX ft_manual_budget_items_name (name)
+);
+
+CREATE TABLE IF NOT EXISTS user_settings (
+  user_id BIGINT NOT NULL,
+  setting_key VARCHAR(64) NOT NULL,
+  setting_value TEXT NOT NULL,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (user_id, setting_key),
+  CONSTRAINT fk_settings_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS share_links (
+  id CHAR(32) PRIMARY KEY,
+  user_id BIGINT NOT NULL,
+  scope VARCHAR(8) NOT NULL,
+  year INT NOT NULL,
+  month INT NOT NULL DEFAULT 0,
+  label VARCHAR(255) NULL,
+  expires_at TIMESTAMP NOT NULL,
+  revoked_at TIMESTAMP NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT fk_share_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  INDEX idx_share_links_user (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS share_link_access (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  link_id CHAR(32) NOT NULL,
+  accessed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  ip_address VARCHAR(64) NULL,
+  user_agent VARCHAR(512) NULL,
+  CONSTRAINT fk_share_access_link FOREIGN KEY (link_id) REFERENCES share_links(id) ON DELETE CASCADE,
+  INDEX idx_share_access_link (link_id, accessed_at)
+);
+
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+  user_id BIGINT PRIMARY KEY,
+  token_hash CHAR(64) NOT NULL UNIQUE,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT fk_calendar_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS webhooks (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  user_id BIGINT NOT NULL,
+  url VARCHAR(2048) NOT NULL,
+  event_types VARCHAR(1024) NOT NULL,
+  secret VARCHAR(128) NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT fk_webhooks_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  INDEX idx_webhooks_user (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  webhook_id BIGINT NOT NULL,
+  event_id VARCHAR(64) NOT NULL,
+  event_type VARCHAR(64) NOT NULL,
+  payload TEXT NOT NULL,
+  status VARCHAR(16) NOT NULL DEFAULT 'pending',
+  attempts INT NOT NULL DEFAULT 0,
+  response_code INT NULL,
+  next_attempt_at TIMESTAMP NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  CONSTRAINT fk_webhook_deliveries_webhook FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE,
+  INDEX idx_webhook_deliveries_webhook (webhook_id, created_at),
+  INDEX idx_webhook_deliveries_status (status)
+);
+
+CREATE TABLE IF NOT EXISTS webhook_attempts (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  delivery_id BIGINT NOT NULL,
+  attempted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  response_code INT NULL,
+  error VARCHAR(512) NULL,
+  duration_ms BIGINT NOT NULL DEFAULT 0,
+  CONSTRAINT fk_webhook_attempts_delivery FOREIGN KEY (delivery_id) REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+  INDEX idx_webhook_attempts_delivery (delivery_id)
+);
+
+CREATE TABLE IF NOT EXISTS record_history (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  entity VARCHAR(32) NOT NULL,
+  record_id BIGINT NOT NULL,
+  user_id BIGINT NULL,
+  action VARCHAR(16) NOT NULL,
+  version BIGINT NULL,
+  actor_id BIGINT NULL,
+  request_id VARCHAR(64) NULL,
+  before_json TEXT NULL,
+  after_json TEXT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  INDEX idx_record_history_record (entity, record_id),
+  INDEX idx_record_history_user (user_id, id)
+);
+
+-- Updated by every write just before it appends to record_history; the row
+-- lock, held until commit, makes history entries commit in ID order so a
+-- sync cursor never passes an entry that commits later
+CREATE TABLE IF NOT EXISTS record_history_lock (
+  id TINYINT PRIMARY KEY,
+  writes BIGINT NOT NULL DEFAULT 0
+);
+INSERT IGNORE INTO record_history_lock (id) VALUES (1);
+
+CREATE TABLE IF NOT EXISTS sync_mutations (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  user_id BIGINT NOT NULL,
+  client_id VARCHAR(36) NOT NULL,
+  history_id BIGINT NULL,
+  result_json TEXT NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE KEY uq_sync_mutations_user_client (user_id, client_id),
+  CONSTRAINT fk_sync_mutations_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS access_tokens (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  user_id BIGINT NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  token_hash CHAR(64) NOT NULL,
+  prefix VARCHAR(16) NOT NULL,
+  scopes VARCHAR(255) NOT NULL,
+  expires_at TIMESTAMP NULL,
+  last_used_at TIMESTAMP NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE KEY uq_access_tokens_hash (token_hash),
+  INDEX idx_access_tokens_user (user_id),
+  CONSTRAINT fk_access_tokens_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
 );
 
 -- Seed admin user only if absent (do not overwrite password on re-runs)
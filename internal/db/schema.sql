@@ -30,8 +30,11 @@ CREATE TABLE IF NOT EXISTS income_sources (
     year INTEGER NOT NULL,
     month INTEGER NOT NULL,
     amount_cents INTEGER NOT NULL,
+    day_of_month INTEGER, -- pay day (income) or due day (budget), 1-31
+    version INTEGER NOT NULL DEFAULT 1, -- optimistic concurrency (ETag / If-Match)
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME, -- in the trash since; purged after the retention period
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
 );
 
@@ -43,8 +46,11 @@ CREATE TABLE IF NOT EXISTS budget_sources (
     year INTEGER NOT NULL,
     month INTEGER NOT NULL,
     amount_cents INTEGER NOT NULL,
+    day_of_month INTEGER, -- pay day (income) or due day (budget), 1-31
+    version INTEGER NOT NULL DEFAULT 1, -- optimistic concurrency (ETag / If-Match)
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME, -- in the trash since; purged after the retention period
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
 );
 
@@ -72,7 +78,8 @@ CREATE TABLE IF NOT EXISTS expense (
     category TEXT,
     description TEXT NOT NULL,
     amount_cents INTEGER NOT NULL,
-    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME -- in the trash since; purged after the retention period
 );
 
 -- Manual budgets (bank amount + list of items) per user/month
@@ -82,6 +89,7 @@ CREATE TABLE IF NOT EXISTS manual_budgets (
     year INTEGER NOT NULL,
     month INTEGER NOT NULL,
     bank_amount_cents INTEGER NOT NULL DEFAULT 0,
+    version INTEGER NOT NULL DEFAULT 1,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     UNIQUE(user_id, year, month),
@@ -95,9 +103,206 @@ CREATE TABLE IF NOT EXISTS manual_budget_items (
     amount_cents INTEGER NOT NULL,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME, -- in the trash since; purged after the retention period
     FOREIGN KEY (budget_id) REFERENCES manual_budgets(id) ON DELETE CASCADE
 );
 
+-- Per-user key/value settings (locale, currency, ...)
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id INTEGER NOT NULL,
+    setting_key TEXT NOT NULL,
+    setting_value TEXT NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, setting_key),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Calendar feed tokens (only the SHA-256 hash is stored)
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    user_id INTEGER PRIMARY KEY,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Read-only share links (signed tokens reference a row here so they can be revoked)
+CREATE TABLE IF NOT EXISTS share_links (
+    id TEXT PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    scope TEXT NOT NULL, -- month or year
+    year INTEGER NOT NULL,
+    month INTEGER NOT NULL DEFAULT 0, -- 0 for yearly links
+    label TEXT,
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Access log for share links, reviewable by the owner
+CREATE TABLE IF NOT EXISTS share_link_access (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    link_id TEXT NOT NULL,
+    accessed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    ip_address TEXT,
+    user_agent TEXT,
+    FOREIGN KEY (link_id) REFERENCES share_links(id) ON DELETE CASCADE
+);
+
+-- Outgoing webhooks; event_types is comma-separated and the secret signs deliveries
+CREATE TABLE IF NOT EXISTS webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    url TEXT NOT NULL,
+    event_types TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- One row per event queued for a webhook, with its retry state
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_id INTEGER NOT NULL,
+    event_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending', -- pending, delivered, failed
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_code INTEGER,
+    next_attempt_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+);
+
+-- Every delivery attempt, reviewable by the owner
+CREATE TABLE IF NOT EXISTS webhook_attempts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    delivery_id INTEGER NOT NULL,
+    attempted_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    response_code INTEGER,
+    error TEXT,
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (delivery_id) REFERENCES webhook_deliveries(id) ON DELETE CASCADE
+);
+
+-- Append-only history of writes to sources, expenses and manual budgets, with
+-- the record as JSON before and after each write
+CREATE TABLE IF NOT EXISTS record_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    entity TEXT NOT NULL, -- income_source, budget_source, expense or manual_budget
+    record_id INTEGER NOT NULL,
+    user_id INTEGER, -- the record's owner; NULL for shared expenses
+    action TEXT NOT NULL, -- created, updated, deleted, restored or reverted
+    version INTEGER,
+    actor_id INTEGER, -- the user who made the write, if known
+    request_id TEXT,
+    before_json TEXT,
+    after_json TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One row that every write updates just before appending to record_history.
+-- Its row lock is held until the write commits, so history entries commit in
+-- ID order and a sync cursor never passes an entry that commits later
+-- (SQLite serialises writers anyway; MySQL needs the lock)
+CREATE TABLE IF NOT EXISTS record_history_lock (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    writes INTEGER NOT NULL DEFAULT 0
+);
+INSERT OR IGNORE INTO record_history_lock (id) VALUES (1);
+
+-- Offline mutations pushed to /sync, by their client-generated ID, so a
+-- retried push answers with the first result instead of applying twice
+CREATE TABLE IF NOT EXISTS sync_mutations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    client_id TEXT NOT NULL,
+    history_id INTEGER, -- the record_history entry the mutation wrote, if applied
+    result_json TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(user_id, client_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Personal access tokens for scripts and integrations. Only a SHA-256 hash of
+-- each token is kept; scopes is comma-separated
+CREATE TABLE IF NOT EXISTS access_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    prefix TEXT NOT NULL, -- the token's first characters, to tell tokens apart
+    scopes TEXT NOT NULL,
+    expires_at DATETIME, -- NULL for tokens that do not expire
+    last_used_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Full-text search indexes over names and descriptions (FTS5, external content).
+-- The triggers below keep them in sync; db.Migrate rebuilds them on start-up
+-- so rows written before they existed are indexed too.
+CREATE VIRTUAL TABLE IF NOT EXISTS expense_fts USING fts5(
+    description, category, content='expense', content_rowid='id', tokenize='unicode61 remove_diacritics 2'
+);
+CREATE VIRTUAL TABLE IF NOT EXISTS income_sources_fts USING fts5(
+    name, content='income_sources', content_rowid='id', tokenize='unicode61 remove_diacritics 2'
+);
+CREATE VIRTUAL TABLE IF NOT EXISTS budget_sources_fts USING fts5(
+    name, content='budget_sources', content_rowid='id', tokenize='unicode61 remove_diacritics 2'
+);
+CREATE VIRTUAL TABLE IF NOT EXISTS manual_budget_items_fts USING fts5(
+    name, content='manual_budget_items', content_rowid='id', tokenize='unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER IF NOT EXISTS expense_fts_insert AFTER INSERT ON expense BEGIN
+    INSERT INTO expense_fts(rowid, description, category) VALUES (new.id, new.description, new.category);
+END;
+CREATE TRIGGER IF NOT EXISTS expense_fts_delete AFTER DELETE ON expense BEGIN
+    INSERT INTO expense_fts(expense_fts, rowid, description, category)
+    VALUES ('delete', old.id, old.description, old.category);
+END;
+CREATE TRIGGER IF NOT EXISTS expense_fts_update AFTER UPDATE OF description, category ON expense BEGIN
+    INSERT INTO expense_fts(expense_fts, rowid, description, category)
+    VALUES ('delete', old.id, old.description, old.category);
+    INSERT INTO expense_fts(rowid, description, category) VALUES (new.id, new.description, new.category);
+END;
+
+CREATE TRIGGER IF NOT EXISTS income_sources_fts_insert AFTER INSERT ON income_sources BEGIN
+    INSERT INTO income_sources_fts(rowid, name) VALUES (new.id, new.name);
+END;
+CREATE TRIGGER IF NOT EXISTS income_sources_fts_delete AFTER DELETE ON income_sources BEGIN
+    INSERT INTO income_sources_fts(income_sources_fts, rowid, name) VALUES ('delete', old.id, old.name);
+END;
+CREATE TRIGGER IF NOT EXISTS income_sources_fts_update AFTER UPDATE OF name ON income_sources BEGIN
+    INSERT INTO income_sources_fts(income_sources_fts, rowid, name) VALUES ('delete', old.id, old.name);
+    INSERT INTO income_sources_fts(rowid, name) VALUES (new.id, new.name);
+END;
+
+CREATE TRIGGER IF NOT EXISTS budget_sources_fts_insert AFTER INSERT ON budget_sources BEGIN
+    INSERT INTO budget_sources_fts(rowid, name) VALUES (new.id, new.name);
+END;
+CREATE TRIGGER IF NOT EXISTS budget_sources_fts_delete AFTER DELETE ON budget_sources BEGIN
+    INSERT INTO budget_sources_fts(budget_sources_fts, rowid, name) VALUES ('delete', old.id, old.name);
+END;
+CREATE TRIGGER IF NOT EXISTS budget_sources_fts_update AFTER UPDATE OF name ON budget_sources BEGIN
+    INSERT INTO budget_sources_fts(budget_sources_fts, rowid, name) VALUES ('delete', old.id, old.name);
+    INSERT INTO budget_sources_fts(rowid, name) VALUES (new.id, new.name);
+END;
+
+CREATE TRIGGER IF NOT EXISTS manual_budget_items_fts_insert AFTER INSERT ON manual_budget_items BEGIN
+    INSERT INTO manual_budget_items_fts(rowid, name) VALUES (new.id, new.name);
+END;
+CREATE TRIGGER IF NOT EXISTS manual_budget_items_fts_delete AFTER DELETE ON manual_budget_items BEGIN
+    INSERT INTO manual_budget_items_fts(manual_budget_items_fts, rowid, name) VALUES ('delete', old.id, old.name);
+END;
+CREATE TRIGGER IF NOT EXISTS manual_budget_items_fts_update AFTER UPDATE OF name ON manual_budget_items BEGIN
+    INSERT INTO manual_budget_items_fts(manual_budget_items_fts, rowid, name) VALUES ('delete', old.id, old.name);
+    INSERT INTO manual_budget_items_fts(rowid, name) VALUES (new.id, new.name);
+END;
+
 -- Seed admin user only if it doesn't exist (do not overwrite password on subsequent migrations)
 -- Default password is 'password'
 INSERT OR IGNORE INTO users (username, password_hash, email) VALUES 
@@ -112,3 +317,12 @@ CREATE INDEX IF NOT EXISTS idx_sessions_expires ON sessions(expires_at);
 CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
 CREATE INDEX IF NOT EXISTS idx_manual_budgets_user_year_month ON manual_budgets(user_id, year, month);
 CREATE INDEX IF NOT EXISTS idx_manual_budget_items_budget_id ON manual_budget_items(budget_id);
+CREATE INDEX IF NOT EXISTS idx_share_links_user ON share_links(user_id);
+CREATE INDEX IF NOT EXISTS idx_share_link_access_link ON share_link_access(link_id, accessed_at);
+CREATE INDEX IF NOT EXISTS idx_webhooks_user ON webhooks(user_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_status ON webhook_deliveries(status);
+CREATE INDEX IF NOT EXISTS idx_webhook_attempts_delivery ON webhook_attempts(delivery_id);
+CREATE INDEX IF NOT EXISTS idx_record_history_record ON record_history(entity, record_id);
+CREATE INDEX IF NOT EXISTS idx_record_history_user ON record_history(user_id, id);
+CREATE INDEX IF NOT EXISTS idx_access_tokens_user ON access_tokens(user_id);
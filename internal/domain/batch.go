@@ -0,0 +1,76 @@
+package domain
+
+import "fmt"
+
+// Batch operation verbs.
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// Batch record types.
+const (
+	BatchIncomeSource     = "income_source"
+	BatchBudgetSource     = "budget_source"
+	BatchExpense          = "expense"
+	BatchManualBudgetItem = "manual_budget_item"
+)
+
+// MaxBatchOperations caps the operations in one batch request.
+const MaxBatchOperations = 200
+
+// BatchOperation is one write in a batch. Update and delete name the record by
+// ID; create and update carry the payload for Type in the matching field. A
+// non-zero Version makes a source update or delete conditional, like If-Match.
+// Expenses can only be created and deleted.
+type BatchOperation struct {
+	Op      string
+	Type    string
+	ID      int64
+	Version int64
+
+	IncomeSource *CreateIncomeSourceRequest // create income_source
+	BudgetSource *CreateBudgetSourceRequest // create budget_source
+	Source       *UpdateSourceRequest       // update income_source, budget_source
+	Expense      *Expense                   // create expense
+	Item         *ManualBudgetItemRequest   // create, update manual_budget_item
+}
+
+// ManualBudgetItemRequest defines the payload to create or update one manual
+// budget item. Year and month pick the budget on create and are ignored on
+// update.
+type ManualBudgetItemRequest struct {
+	Year        int    `json:"year"`
+	Month       int    `json:"month"`
+	Name        string `json:"name"`
+	AmountCents Money  `json:"amount_cents"`
+}
+
+// BatchResult reports one applied operation. Version is the record's new
+// version; item writes report the version of the month's manual budget, which
+// they bump. Deletes of sources and expenses have no version.
+type BatchResult struct {
+	Index   int    `json:"index"`
+	Op      string `json:"op"`
+	Type    string `json:"type"`
+	ID      int64  `json:"id"`
+	Version int64  `json:"version,omitempty"`
+}
+
+// BatchError reports the operation that failed a batch. Nothing in the batch
+// was applied.
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+// Error returns the failing operation's index and error.
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch operation %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the failing operation's error.
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
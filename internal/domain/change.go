@@ -0,0 +1,26 @@
+package domain
+
+// Change actions.
+const (
+	ChangeCreated  = "created"
+	ChangeUpdated  = "updated"
+	ChangeDeleted  = "deleted"
+	ChangeRestored = "restored" // taken back out of the trash
+)
+
+// ChangeManualBudget is the entity of a change to a month's manual budget or
+// its items. Other entities use the batch record types (BatchIncomeSource,
+// BatchBudgetSource, BatchExpense).
+const ChangeManualBudget = "manual_budget"
+
+// Change describes a committed write to a month, so open clients can refresh
+// it. UserID is the owner; expenses are shared by all users and have none.
+// A manual budget is identified by its month alone and has no ID.
+type Change struct {
+	UserID int64 `json:"-"`
+	YearMonth
+	Entity  string `json:"entity"`
+	Action  string `json:"action"`
+	ID      int64  `json:"id,omitempty"`
+	Version int64  `json:"version,omitempty"`
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Error kinds. Branch on them with errors.Is; every *Error unwraps to one.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrRateLimited  = errors.New("rate limited")
+	// ErrPreconditionFailed reports a conditional write (If-Match) against a
+	// record that has changed since the client read it.
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+// FieldError describes one invalid input field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is a typed error that can be shown to API clients. Code is stable and
+// machine-readable ("share_link_not_found"); Message is safe to display; Err,
+// if set, is the internal cause and is never sent to clients.
+type Error struct {
+	Kind       error
+	Code       string
+	Message    string
+	Fields     []FieldError
+	RetryAfter time.Duration
+	Err        error
+}
+
+// Error returns the message followed by the internal cause, if any.
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap exposes both the kind and the cause to errors.Is and errors.As.
+func (e *Error) Unwrap() []error {
+	errs := make([]error, 0, 2)
+	if e.Kind != nil {
+		errs = append(errs, e.Kind)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// WithCause returns a copy of e carrying cause as its internal error.
+func (e *Error) WithCause(cause error) *Error {
+	c := *e
+	c.Err = cause
+	return &c
+}
+
+// NotFound reports a missing or inaccessible resource.
+func NotFound(code, message string) *Error {
+	return &Error{Kind: ErrNotFound, Code: code, Message: message}
+}
+
+// Conflict reports a request that clashes with the current state.
+func Conflict(code, message string) *Error {
+	return &Error{Kind: ErrConflict, Code: code, Message: message}
+}
+
+// PreconditionFailed reports a stale conditional write.
+func PreconditionFailed(code, message string) *Error {
+	return &Error{Kind: ErrPreconditionFailed, Code: code, Message: message}
+}
+
+// Invalid reports rejected input, optionally per field.
+func Invalid(message string, fields ...FieldError) *Error {
+	return &Error{Kind: ErrValidation, Code: "validation_failed", Message: message, Fields: fields}
+}
+
+// Unauthorized reports missing or bad credentials.
+func Unauthorized(message string) *Error {
+	return &Error{Kind: ErrUnauthorized, Code: "unauthorized", Message: message}
+}
+
+// Forbidden reports an authenticated caller lacking permission.
+func Forbidden(message string) *Error {
+	return &Error{Kind: ErrForbidden, Code: "forbidden", Message: message}
+}
+
+// RateLimited reports a throttled caller; retryAfter may be zero if unknown.
+func RateLimited(retryAfter time.Duration) *Error {
+	return &Error{Kind: ErrRateLimited, Code: "rate_limited", Message: "too many requests", RetryAfter: retryAfter}
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// HistoryReverted is the history action of a write that set a record back
+// to an earlier state. Other entries use the change actions.
+const HistoryReverted = "reverted"
+
+// Actor is who made a write: the signed-in user, if any, and the ID of the
+// request it came from.
+type Actor struct {
+	UserID    int64
+	RequestID string
+}
+
+// HistoryEntry is one write to a record. Entity is a batch record type or
+// ChangeManualBudget; Before and After are the record as JSON around the
+// write, absent before a create and after a delete. Changes lists the fields
+// that differ between them.
+type HistoryEntry struct {
+	ID        int64           `json:"id"`
+	Entity    string          `json:"entity"`
+	RecordID  int64           `json:"record_id"`
+	Action    string          `json:"action"`
+	Version   int64           `json:"version,omitempty"` // the record's version after the write
+	ActorID   int64           `json:"actor_id,omitempty"`
+	Actor     string          `json:"actor,omitempty"` // the actor's username
+	RequestID string          `json:"request_id,omitempty"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	Changes   []FieldChange   `json:"changes"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// FieldChange is a field whose value differs between two snapshots; a nil
+// value means the field was absent.
+type FieldChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// historyIgnoredFields change on every write or never, so they are left out
+// of diffs.
+var historyIgnoredFields = map[string]bool{
+	"id": true, "user_id": true, "version": true, "created_at": true, "updated_at": true,
+}
+
+// DiffSnapshots returns the top-level fields of two JSON objects that differ,
+// by name. Either may be empty, as before a create or after a delete.
+func DiffSnapshots(before, after json.RawMessage) []FieldChange {
+	var b, a map[string]json.RawMessage
+	_ = json.Unmarshal(before, &b)
+	_ = json.Unmarshal(after, &a)
+	fields := make(map[string]bool)
+	for f := range b {
+		fields[f] = true
+	}
+	for f := range a {
+		fields[f] = true
+	}
+	changes := []FieldChange{}
+	for f := range fields {
+		if historyIgnoredFields[f] || bytes.Equal(b[f], a[f]) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: f, Before: b[f], After: a[f]})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
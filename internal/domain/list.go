@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// List sort keys.
+const (
+	SortDate   = "date"
+	SortAmount = "amount"
+	SortName   = "name"
+)
+
+// ListQuery holds pagination, sorting and filters for list endpoints. Zero
+// values mean "no filter"; a zero Limit without After means every row.
+type ListQuery struct {
+	Limit  int
+	After  *Cursor
+	Sort   string
+	Desc   bool
+	Status string // users only
+
+	// Period filters: an exact month (Month needs Year) or a range of months,
+	// both inclusive.
+	Year, Month int
+	From, To    *YearMonth
+
+	Category  string
+	MinAmount *Money
+	MaxAmount *Money
+	Search    string
+}
+
+// Cursor marks the last row of a page. It carries the sort it was issued
+// for, so it cannot be replayed against a different ordering.
+type Cursor struct {
+	Sort string `json:"s"`
+	Desc bool   `json:"d,omitempty"`
+	Int  int64  `json:"i,omitempty"`
+	Str  string `json:"t,omitempty"`
+	ID   int64  `json:"id"`
+}
+
+// Encode returns the opaque form of c sent to clients.
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses a cursor produced by Cursor.Encode.
+func DecodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Page is one page of a list. NextCursor is empty on the last page.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+}
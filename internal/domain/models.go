@@ -75,6 +75,8 @@ type IncomeSource struct {
 	Name   string `json:"name"`
 	YearMonth
 	AmountCents Money     `json:"amount_cents"`
+	DayOfMonth  *int      `json:"day_of_month,omitempty"` // pay day; shown in the calendar feed
+	Version     int64     `json:"version"`                // bumped on every write; sent as the ETag
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
@@ -86,6 +88,8 @@ type BudgetSource struct {
 	Name   string `json:"name"`
 	YearMonth
 	AmountCents Money     `json:"amount_cents"`
+	DayOfMonth  *int      `json:"day_of_month,omitempty"` // due day; shown in the calendar feed
+	Version     int64     `json:"version"`                // bumped on every write; sent as the ETag
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
@@ -110,6 +114,7 @@ type ManualBudget struct {
 	YearMonth       `json:"-"`
 	BankAmountCents Money              `json:"bank_amount_cents"`
 	Items           []ManualBudgetItem `json:"items"`
+	Version         int64              `json:"version"` // 0 until the month is first saved
 }
 
 // ManualBudgetItem represents a single manual budget line item.
@@ -136,24 +141,28 @@ type LoginResponse struct {
 
 // CreateIncomeSourceRequest defines the payload to create an income source.
 type CreateIncomeSourceRequest struct {
-	Name        string `json:"name"`
-	Year        int    `json:"year"`
-	Month       int    `json:"month"`
-	AmountCents Money  `json:"amount_cents"`
+	Name        string `json:"name" openapi:"required"`
+	Year        int    `json:"year" openapi:"required,minimum=1970,maximum=3000"`
+	Month       int    `json:"month" openapi:"required,minimum=1,maximum=12"`
+	AmountCents Money  `json:"amount_cents" openapi:"required,minimum=0"`
+	DayOfMonth  *int   `json:"day_of_month,omitempty" openapi:"minimum=0,maximum=31"`
 }
 
 // CreateBudgetSourceRequest defines the payload to create a budget source.
 type CreateBudgetSourceRequest struct {
-	Name        string `json:"name"`
-	Year        int    `json:"year"`
-	Month       int    `json:"month"`
-	AmountCents Money  `json:"amount_cents"`
+	Name        string `json:"name" openapi:"required"`
+	Year        int    `json:"year" openapi:"required,minimum=1970,maximum=3000"`
+	Month       int    `json:"month" openapi:"required,minimum=1,maximum=12"`
+	AmountCents Money  `json:"amount_cents" openapi:"required,minimum=0"`
+	DayOfMonth  *int   `json:"day_of_month,omitempty" openapi:"minimum=0,maximum=31"`
 }
 
-// UpdateSourceRequest defines the payload to update a source's name or amount.
+// UpdateSourceRequest defines the payload to update a source's name, amount or
+// pay/due day. A nil DayOfMonth keeps the current day and 0 clears it.
 type UpdateSourceRequest struct {
-	Name        string `json:"name"`
-	AmountCents Money  `json:"amount_cents"`
+	Name        string `json:"name" openapi:"required"`
+	AmountCents Money  `json:"amount_cents" openapi:"required,minimum=0"`
+	DayOfMonth  *int   `json:"day_of_month,omitempty" openapi:"minimum=0,maximum=31"`
 }
 
 // ============================================================================
@@ -274,3 +283,84 @@ type AuditStats struct {
 	LastActivity        time.Time              `json:"last_activity"`
 	Metadata            map[string]interface{} `json:"metadata,omitempty"`
 }
+
+// UserSettings holds free-form per-user preferences such as locale and currency.
+type UserSettings map[string]string
+
+// UserDataSet is everything owned by (or visible to) a user, used for full
+// account export and import.
+type UserDataSet struct {
+	IncomeSources []IncomeSource `json:"income_sources"`
+	BudgetSources []BudgetSource `json:"budget_sources"`
+	Expenses      []Expense      `json:"expenses"`
+	ManualBudgets []ManualBudget `json:"manual_budgets"`
+	Settings      UserSettings   `json:"settings"`
+}
+
+// Scheduled payment kinds.
+const (
+	ScheduledIncome = "income"
+	ScheduledBudget = "budget"
+)
+
+// ScheduledPayment is an income or budget source row with a pay/due day,
+// as published in the calendar feed.
+type ScheduledPayment struct {
+	Kind     string `json:"kind"` // income, budget
+	SourceID int64  `json:"source_id"`
+	Name     string `json:"name"`
+	YearMonth
+	DayOfMonth  int       `json:"day_of_month"`
+	AmountCents Money     `json:"amount_cents"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Share link scopes.
+const (
+	ShareScopeMonth = "month"
+	ShareScopeYear  = "year"
+)
+
+// ShareLink grants unauthenticated, read-only access to one month or year.
+type ShareLink struct {
+	ID     string `json:"id"`
+	UserID int64  `json:"user_id"`
+	Scope  string `json:"scope"` // month, year
+	YearMonth
+	Label        string     `json:"label,omitempty"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	AccessCount  int        `json:"access_count"`
+	LastAccessAt *time.Time `json:"last_access_at,omitempty"`
+}
+
+// CreateShareLinkRequest defines the payload to create a share link.
+// Month is ignored for yearly links; ExpiresInHours defaults server-side.
+type CreateShareLinkRequest struct {
+	Scope          string `json:"scope" openapi:"required,enum=month|year"`
+	Year           int    `json:"year" openapi:"required,minimum=1970,maximum=3000"`
+	Month          int    `json:"month" openapi:"minimum=0,maximum=12"`
+	Label          string `json:"label"`
+	ExpiresInHours int    `json:"expires_in_hours" openapi:"minimum=0"`
+}
+
+// ShareAccess is one logged use of a share link.
+type ShareAccess struct {
+	ID         int64     `json:"id"`
+	LinkID     string    `json:"link_id"`
+	AccessedAt time.Time `json:"accessed_at"`
+	IPAddress  string    `json:"ip_address,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+}
+
+// SharedReport is what a share link recipient sees.
+type SharedReport struct {
+	Scope     string         `json:"scope"`
+	Label     string         `json:"label,omitempty"`
+	Period    YearMonth      `json:"period"`
+	ExpiresAt time.Time      `json:"expires_at"`
+	Monthly   *MonthlyData   `json:"monthly,omitempty"`
+	Yearly    *YearlySummary `json:"yearly,omitempty"`
+	Months    []Summary      `json:"months,omitempty"`
+}
@@ -0,0 +1,29 @@
+package domain
+
+// SearchQuery is a parsed full-text search over the records a user can see.
+// Terms must all match a record's name or description (the last one as a
+// prefix, so results follow typing). Zero filters mean "no filter"; a
+// category filter only matches expenses, the one record type that has a
+// category.
+type SearchQuery struct {
+	Terms     []string
+	Category  string
+	MinAmount *Money
+	MaxAmount *Money
+	Limit     int
+}
+
+// SearchResult is one record found by a search, best match first. Type is
+// one of the batch record types (expense, income_source, ...). Snippet is the
+// matching text, HTML-escaped, with the matched words wrapped in
+// <mark>...</mark>.
+type SearchResult struct {
+	Type string `json:"type"`
+	ID   int64  `json:"id"`
+	YearMonth
+	Title       string  `json:"title"`
+	Category    string  `json:"category,omitempty"`
+	AmountCents Money   `json:"amount_cents"`
+	Snippet     string  `json:"snippet"`
+	Score       float64 `json:"score"` // higher is better; only comparable within one search
+}
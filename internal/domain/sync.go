@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// SyncManualBudget is the sync record type of a month's manual budget, which
+// pulls return whole, items included. Pushes write its items one at a time
+// as BatchManualBudgetItem operations.
+const SyncManualBudget = ChangeManualBudget
+
+// Sync conflict policies: how a pushed mutation is resolved when its record
+// was changed on the server since the client's cursor.
+const (
+	SyncLastWriterWins = "last_writer_wins" // apply the mutation over the server's change
+	SyncReject         = "reject"           // leave the record and report the server's state
+)
+
+// SyncPolicies maps the record types a mutation can write to their conflict
+// policy. Expenses are shared and have no version, so the last write wins;
+// a user's own sources and manual budgets are rejected and left for the
+// client to merge.
+var SyncPolicies = map[string]string{
+	BatchIncomeSource:     SyncReject,
+	BatchBudgetSource:     SyncReject,
+	BatchExpense:          SyncLastWriterWins,
+	BatchManualBudgetItem: SyncReject,
+}
+
+// Sync mutation outcomes.
+const (
+	SyncApplied  = "applied"  // written; Conflict says whether it overwrote a server change
+	SyncRejected = "rejected" // not written because of a conflict; Current is the server's state
+	SyncFailed   = "failed"   // not written because the write itself failed
+)
+
+// MaxSyncMutations caps the mutations in one push.
+const MaxSyncMutations = MaxBatchOperations
+
+// syncCursorSort marks cursors issued by sync, so list cursors are refused.
+const syncCursorSort = "sync"
+
+// ErrBadSyncCursor reports a cursor that sync did not issue.
+var ErrBadSyncCursor = errors.New("malformed sync cursor")
+
+// EncodeSyncCursor returns the opaque cursor of a position in the change log.
+func EncodeSyncCursor(pos int64) string {
+	return Cursor{Sort: syncCursorSort, ID: pos}.Encode()
+}
+
+// DecodeSyncCursor parses a cursor produced by EncodeSyncCursor.
+func DecodeSyncCursor(s string) (int64, error) {
+	c, err := DecodeCursor(s)
+	if err != nil || c.Sort != syncCursorSort || c.ID < 0 {
+		return 0, ErrBadSyncCursor
+	}
+	return c.ID, nil
+}
+
+// SyncChange is the current state of a record that changed since a cursor.
+// Data is the record as JSON, as in its history; deleted records are
+// tombstones without data.
+type SyncChange struct {
+	Type    string          `json:"type"` // a batch record type or SyncManualBudget
+	ID      int64           `json:"id"`
+	Deleted bool            `json:"deleted,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// SyncPage is one pull: the records changed since the cursor the client
+// sent, and the cursor to send next. HasMore asks the client to pull again
+// straight away.
+type SyncPage struct {
+	Changes []SyncChange `json:"changes"`
+	Cursor  string       `json:"cursor"`
+	HasMore bool         `json:"has_more"`
+}
+
+// SyncMutation is a write queued by an offline client. ClientID is the
+// client-generated UUID that makes pushing it again safe; Cursor is the
+// change log position the client had when it queued the write, against
+// which conflicts are detected (0 skips the check). An update or delete of a
+// record the client created offline has no server ID yet: Ref names the
+// ClientID of the create instead, and the ID it was given is used.
+type SyncMutation struct {
+	ClientID  string
+	Cursor    int64
+	Ref       string
+	Operation BatchOperation
+}
+
+// SyncResult reports what became of one pushed mutation. ID and Version are
+// as in BatchResult. Err is set for failed mutations.
+type SyncResult struct {
+	ClientID string      `json:"client_id"`
+	Status   string      `json:"status"`
+	Conflict bool        `json:"conflict,omitempty"`
+	ID       int64       `json:"id,omitempty"`
+	Version  int64       `json:"version,omitempty"`
+	Current  *SyncChange `json:"current,omitempty"`
+	Err      error       `json:"-"`
+}
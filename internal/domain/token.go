@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Access token scopes. ScopeAdmin grants everything the token's user can do,
+// admin endpoints included for admins.
+const (
+	ScopeReadBudget    = "read:budget"    // read every budget, source and expense endpoint
+	ScopeWriteExpenses = "write:expenses" // add, delete and revert expenses
+	ScopeAdmin         = "admin"
+)
+
+// TokenScopes lists the scopes a token can be given.
+var TokenScopes = []string{ScopeReadBudget, ScopeWriteExpenses, ScopeAdmin}
+
+// AccessTokenPrefix starts every personal access token, which tells them
+// apart from session IDs in an Authorization header.
+const AccessTokenPrefix = "bpat_"
+
+// IsAccessToken reports whether a bearer credential is a personal access
+// token rather than a session ID.
+func IsAccessToken(credential string) bool {
+	return strings.HasPrefix(credential, AccessTokenPrefix)
+}
+
+// AccessToken is a user's personal access token for scripts and
+// integrations. Token is the secret itself, only returned when the token is
+// created; Prefix is its first characters, to recognise it by afterwards.
+type AccessToken struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Token      string     `json:"token,omitempty"`
+}
+
+// HasScope reports whether the token grants scope.
+func (t *AccessToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAccessTokenRequest defines the payload to create an access token.
+// Tokens without ExpiresInDays do not expire.
+type CreateAccessTokenRequest struct {
+	Name          string   `json:"name" openapi:"required,minLength=1,maxLength=100"`
+	Scopes        []string `json:"scopes" openapi:"required"`
+	ExpiresInDays int      `json:"expires_in_days,omitempty" openapi:"minimum=1,maximum=365"`
+}
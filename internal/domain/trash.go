@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// TrashItem is a deleted record that can still be restored. Type is one of
+// the batch record types (expense, income_source, ...); Name is an expense's
+// description. PurgeAt is when it will be deleted for good, if ever.
+type TrashItem struct {
+	Type string `json:"type"`
+	ID   int64  `json:"id"`
+	YearMonth
+	Name        string     `json:"name"`
+	Category    string     `json:"category,omitempty"`
+	AmountCents Money      `json:"amount_cents"`
+	DeletedAt   time.Time  `json:"deleted_at"`
+	PurgeAt     *time.Time `json:"purge_at,omitempty"`
+}
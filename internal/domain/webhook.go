@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook event types: every Change as entity.action, plus these.
+const (
+	WebhookBudgetExceeded = "budget.exceeded"
+	WebhookDataExport     = "data.export"
+	// WebhookAllEvents subscribes to every event type.
+	WebhookAllEvents = "*"
+)
+
+// WebhookEventTypes lists the event types a webhook can subscribe to.
+var WebhookEventTypes = []string{
+	"income_source.created", "income_source.updated", "income_source.deleted", "income_source.restored",
+	"budget_source.created", "budget_source.updated", "budget_source.deleted", "budget_source.restored",
+	"expense.created", "expense.updated", "expense.deleted", "expense.restored",
+	"manual_budget.updated",
+	WebhookBudgetExceeded,
+	WebhookDataExport,
+}
+
+// Webhook delivery statuses.
+const (
+	WebhookPending   = "pending"
+	WebhookDelivered = "delivered"
+	WebhookFailed    = "failed"
+)
+
+// Webhook is a user's subscription to event types, delivered by POST to URL.
+// Secret signs the deliveries; it is only returned when the webhook is created.
+type Webhook struct {
+	ID         int64     `json:"id"`
+	UserID     int64     `json:"user_id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Secret     string    `json:"secret,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Wants reports whether the webhook subscribes to eventType.
+func (w *Webhook) Wants(eventType string) bool {
+	for _, t := range w.EventTypes {
+		if t == eventType || t == WebhookAllEvents {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateWebhookRequest defines the payload to create a webhook.
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" openapi:"required,format=uri"`
+	EventTypes []string `json:"event_types" openapi:"required"`
+}
+
+// WebhookDelivery is one event queued for one webhook. ResponseCode is that
+// of the last attempt (0 if it got no response); NextAttemptAt is set while
+// the delivery is pending.
+type WebhookDelivery struct {
+	ID            int64            `json:"id"`
+	WebhookID     int64            `json:"webhook_id"`
+	EventID       string           `json:"event_id"`
+	EventType     string           `json:"event_type"`
+	Payload       json.RawMessage  `json:"payload"`
+	Status        string           `json:"status"`
+	Attempts      int              `json:"attempts"`
+	ResponseCode  int              `json:"response_code,omitempty"`
+	NextAttemptAt *time.Time       `json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+	History       []WebhookAttempt `json:"attempts_log,omitempty"`
+}
+
+// WebhookAttempt is one logged delivery attempt.
+type WebhookAttempt struct {
+	ID           int64     `json:"id"`
+	DeliveryID   int64     `json:"delivery_id"`
+	AttemptedAt  time.Time `json:"attempted_at"`
+	ResponseCode int       `json:"response_code,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	DurationMS   int64     `json:"duration_ms"`
+}
@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/mdco1990/webapp/internal/domain"
 	"github.com/mdco1990/webapp/internal/storage"
 )
 
@@ -266,9 +267,6 @@ func (eb *EventBus) Publish(ctx context.Context, event Event) error {
 	eb.stats.LastEventTime = time.Now()
 	eb.stats.mu.Unlock()
 
-	// Process event with middleware
-	handler := eb.buildHandlerChain(event)
-
 	// Process event concurrently
 	var wg sync.WaitGroup
 	errors := make(chan error, len(subs))
@@ -283,7 +281,7 @@ func (eb *EventBus) Publish(ctx context.Context, event Event) error {
 			defer wg.Done()
 
 			start := time.Now()
-			err := handler(ctx, event)
+			err := eb.buildHandlerChain(subscription.Handler)(ctx, event)
 			duration := time.Since(start)
 
 			// Update stats
@@ -390,12 +388,10 @@ func (eb *EventBus) matchesPattern(eventType, pattern string) bool {
 	return false
 }
 
-// buildHandlerChain builds the middleware chain for event processing
-func (eb *EventBus) buildHandlerChain(_ Event) EventHandler {
-	handler := func(_ context.Context, _ Event) error {
-		// This is a no-op handler that will be replaced by middleware
-		return nil
-	}
+// buildHandlerChain wraps a subscription's handler in the middleware chain
+func (eb *EventBus) buildHandlerChain(handler EventHandler) EventHandler {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
 
 	// Apply middleware in reverse order
 	for i := len(eb.middleware) - 1; i >= 0; i-- {
@@ -539,6 +535,26 @@ func NewDataExportEvent(source string, userID int64, exportType, format string,
 	}
 }
 
+// ChangeEventPrefix prefixes the type of every ChangeEvent, e.g.
+// "change.income_source.created".
+const ChangeEventPrefix = "change."
+
+// ChangeEvent represents a committed write to a month
+type ChangeEvent struct {
+	BaseEvent
+	Change  domain.Change `json:"change"`
+	ActorID int64         `json:"actor_id,omitempty"` // user who made the change, if known
+}
+
+// NewChangeEvent creates a new change event
+func NewChangeEvent(source string, change domain.Change, actorID int64) *ChangeEvent {
+	return &ChangeEvent{
+		BaseEvent: NewBaseEvent(ChangeEventPrefix+change.Entity+"."+change.Action, source, nil),
+		Change:    change,
+		ActorID:   actorID,
+	}
+}
+
 // ============================================================================
 // UTILITY FUNCTIONS
 // ============================================================================
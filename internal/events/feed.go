@@ -0,0 +1,154 @@
+package events
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// feedBuffer is how many pending entries a subscriber may fall behind by
+// before it is dropped.
+const feedBuffer = 64
+
+// FeedEntry is a change with its position in a ChangeFeed.
+type FeedEntry struct {
+	ID     string
+	Change domain.Change
+	seq    uint64
+}
+
+// ChangeFeed fans the ChangeEvents published on a bus out to live
+// subscribers, keeping the most recent ones so a client that reconnects can
+// resume from the last entry it saw.
+type ChangeFeed struct {
+	mu     sync.Mutex
+	epoch  string // distinguishes IDs issued before a restart
+	seq    uint64
+	size   int
+	recent []FeedEntry
+	subs   map[*FeedSubscription]struct{}
+}
+
+// NewChangeFeed creates a feed that keeps the last size entries.
+func NewChangeFeed(size int) *ChangeFeed {
+	return &ChangeFeed{
+		epoch: strconv.FormatInt(time.Now().UnixNano(), 36),
+		size:  size,
+		subs:  make(map[*FeedSubscription]struct{}),
+	}
+}
+
+// Attach subscribes the feed to the change events published on bus
+func (f *ChangeFeed) Attach(bus *EventBus) (string, error) {
+	return bus.SubscribePattern(ChangeEventPrefix+"*", func(_ context.Context, event Event) error {
+		if e, ok := event.(*ChangeEvent); ok {
+			f.add(e.Change)
+		}
+		return nil
+	})
+}
+
+// FeedSubscription receives the entries of a ChangeFeed that match its filter.
+type FeedSubscription struct {
+	// Replay holds the matching entries since the ID the subscriber resumed
+	// from.
+	Replay []FeedEntry
+	// Reset reports that the ID could not be resumed from (it predates the
+	// kept entries or a restart), so the subscriber must reload.
+	Reset bool
+	// Head is the ID of the newest entry when the subscription started.
+	Head string
+	// C delivers later entries. It is closed if the subscriber falls too far
+	// behind, after which it should reconnect and resume.
+	C <-chan FeedEntry
+
+	c     chan FeedEntry
+	match func(domain.Change) bool
+	feed  *ChangeFeed
+}
+
+// Subscribe starts a subscription to the entries that match. lastID is the
+// last entry the subscriber saw, or empty to start from now. Close must be
+// called when done.
+func (f *ChangeFeed) Subscribe(lastID string, match func(domain.Change) bool) *FeedSubscription {
+	c := make(chan FeedEntry, feedBuffer)
+	s := &FeedSubscription{C: c, c: c, match: match, feed: f}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s.Head = f.id(f.seq)
+	if lastID != "" {
+		seq, ok := f.parseID(lastID)
+		if ok && len(f.recent) > 0 && seq+1 < f.recent[0].seq {
+			ok = false // entries after it are gone
+		}
+		if !ok {
+			s.Reset = true
+		} else {
+			for _, e := range f.recent {
+				if e.seq > seq && match(e.Change) {
+					s.Replay = append(s.Replay, e)
+				}
+			}
+		}
+	}
+	f.subs[s] = struct{}{}
+	return s
+}
+
+// Close ends the subscription.
+func (s *FeedSubscription) Close() {
+	f := s.feed
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.subs[s]; ok {
+		delete(f.subs, s)
+		close(s.c)
+	}
+}
+
+// add appends a change and delivers it to matching subscribers.
+func (f *ChangeFeed) add(c domain.Change) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.seq++
+	e := FeedEntry{ID: f.id(f.seq), Change: c, seq: f.seq}
+	f.recent = append(f.recent, e)
+	if len(f.recent) > f.size {
+		f.recent = f.recent[1:]
+	}
+	for s := range f.subs {
+		if !s.match(c) {
+			continue
+		}
+		select {
+		case s.c <- e:
+		default:
+			// Too far behind: drop it rather than block writers. It
+			// resumes from the kept entries when it reconnects.
+			delete(f.subs, s)
+			close(s.c)
+		}
+	}
+}
+
+func (f *ChangeFeed) id(seq uint64) string {
+	return f.epoch + "-" + strconv.FormatUint(seq, 10)
+}
+
+// parseID returns the sequence number of an ID issued by this feed.
+func (f *ChangeFeed) parseID(id string) (uint64, bool) {
+	epoch, s, ok := strings.Cut(id, "-")
+	if !ok || epoch != f.epoch {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || seq > f.seq {
+		return 0, false
+	}
+	return seq, true
+}
@@ -0,0 +1,66 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// CSVWriter streams monthly data as one row per income source, budget source
+// and expense. Rows are flushed after every month so a year never sits in memory.
+type CSVWriter struct {
+	w      *csv.Writer
+	format Format
+	header bool
+}
+
+// NewCSVWriter returns a CSVWriter writing to w.
+func NewCSVWriter(w io.Writer, f Format) *CSVWriter {
+	cw := csv.NewWriter(w)
+	cw.Comma = f.CSVDelimiter()
+	return &CSVWriter{w: cw, format: f}
+}
+
+// WriteMonth writes all rows for one month (and the header before the first month).
+func (c *CSVWriter) WriteMonth(data *domain.MonthlyData) error {
+	f := c.format
+	if !c.header {
+		c.header = true
+		if err := c.w.Write([]string{
+			f.T("month"), f.T("type"), f.T("name"), f.T("category"), f.T("amount"), f.T("amount_cents"),
+		}); err != nil {
+			return err
+		}
+	}
+
+	month := f.MonthName(data.Month) + " " + strconv.Itoa(data.Year)
+	row := func(kind, name, category string, amount domain.Money) error {
+		return c.w.Write([]string{
+			month, f.T(kind), name, category, f.Money(amount), strconv.FormatInt(int64(amount), 10),
+		})
+	}
+
+	for _, s := range data.IncomeSources {
+		if err := row("income", s.Name, "", s.AmountCents); err != nil {
+			return err
+		}
+	}
+	for _, s := range data.BudgetSources {
+		if err := row("budget", s.Name, "", s.AmountCents); err != nil {
+			return err
+		}
+	}
+	for _, e := range data.Expenses {
+		if err := row("expense", e.Description, e.Category, e.AmountCents); err != nil {
+			return err
+		}
+	}
+	if err := row("remaining", "", "", data.Remaining); err != nil {
+		return err
+	}
+
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,140 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+func sampleMonth(month int) *domain.MonthlyData {
+	return &domain.MonthlyData{
+		YearMonth:     domain.YearMonth{Year: 2024, Month: month},
+		IncomeSources: []domain.IncomeSource{{Name: "Salary", AmountCents: 250000}},
+		BudgetSources: []domain.BudgetSource{{Name: "Rent", AmountCents: 90000}},
+		Expenses: []domain.Expense{
+			{Description: "Groceries", Category: "Food", AmountCents: 123456},
+		},
+		TotalIncome:   250000,
+		TotalBudget:   90000,
+		TotalExpenses: 123456,
+		Remaining:     126544,
+	}
+}
+
+func TestFormatMoney(t *testing.T) {
+	tests := []struct {
+		locale, currency string
+		amount           domain.Money
+		expected         string
+	}{
+		{"en", "EUR", 123456, "€1,234.56"},
+		{"en", "USD", -500, "-$5.00"},
+		{"en-US", "usd", 100000000, "$1,000,000.00"},
+		{"fr", "EUR", 123456, "1\u202f234,56\u00a0€"},
+		{"fr-FR", "USD", 7, "0,07\u00a0$US"},
+		{"de", "GBP", 99, "€0.99"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale+"_"+tt.currency, func(t *testing.T) {
+			got := NewFormat(tt.locale, tt.currency).Money(tt.amount)
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVWriter(&buf, NewFormat("fr", "EUR"))
+	for _, m := range []int{1, 2} {
+		if err := w.WriteMonth(sampleMonth(m)); err != nil {
+			t.Fatalf("WriteMonth failed: %v", err)
+		}
+	}
+
+	r := csv.NewReader(&buf)
+	r.Comma = ';'
+	records, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse CSV: %v", err)
+	}
+	// header + 2 months * (income, budget, expense, remaining)
+	if len(records) != 9 {
+		t.Fatalf("Expected 9 records, got %d", len(records))
+	}
+	if records[0][0] != "Mois" {
+		t.Errorf("Expected French header, got %q", records[0][0])
+	}
+	expense := records[3]
+	if expense[0] != "Janvier 2024" || expense[2] != "Groceries" || expense[3] != "Food" || expense[5] != "123456" {
+		t.Errorf("Unexpected expense row: %v", expense)
+	}
+}
+
+func TestXLSXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewXLSXWriter(&buf, NewFormat("en", "USD"))
+	for _, m := range []int{1, 2, 3} {
+		if err := w.WriteMonth(sampleMonth(m)); err != nil {
+			t.Fatalf("WriteMonth failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("output is not a zip: %v", err)
+	}
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		body, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		parts[f.Name] = string(body)
+
+		// Every part must be well-formed XML.
+		dec := xml.NewDecoder(bytes.NewReader(body))
+		for {
+			if _, err := dec.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s is not well-formed: %v", f.Name, err)
+			}
+		}
+	}
+
+	for _, name := range []string{
+		"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels",
+		"xl/styles.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet4.xml",
+	} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("missing part %s", name)
+		}
+	}
+
+	if !strings.Contains(parts["xl/workbook.xml"], `<sheet name="Summary" sheetId="1"`) {
+		t.Errorf("summary sheet should be first: %s", parts["xl/workbook.xml"])
+	}
+	summary := parts["xl/worksheets/sheet1.xml"]
+	if !strings.Contains(summary, "<f>&#39;January 2024&#39;!C5</f>") {
+		t.Errorf("summary should reference the month sheet total: %s", summary)
+	}
+	if !strings.Contains(summary, "<f>SUM(B2:B4)</f><v>7500.00</v>") {
+		t.Errorf("summary should total income with a formula: %s", summary)
+	}
+	if !strings.Contains(parts["xl/worksheets/sheet2.xml"], "<f>SUM(C4:C4)</f>") {
+		t.Errorf("month sheet should total with a formula")
+	}
+}
@@ -0,0 +1,222 @@
+package export
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// Dialect is a plain-text accounting journal syntax.
+type Dialect string
+
+// Supported journal dialects.
+const (
+	DialectLedger    Dialect = "ledger"
+	DialectHledger   Dialect = "hledger"
+	DialectBeancount Dialect = "beancount"
+)
+
+// ErrUnknownDialect is returned by ParseDialect for unsupported syntaxes.
+var ErrUnknownDialect = errors.New("unknown journal dialect")
+
+// Accounts used by the journal export. Income sources, expense categories and
+// budget sources become sub-accounts of these roots.
+const (
+	AccountChecking      = "Assets:Checking"
+	AccountBudgetRoot    = "Assets:Budget"
+	AccountBudgetEquity  = "Equity:Budget"
+	AccountIncomeRoot    = "Income"
+	AccountExpensesRoot  = "Expenses"
+	uncategorizedAccount = "Uncategorized"
+)
+
+// ParseDialect validates a dialect name.
+func ParseDialect(s string) (Dialect, error) {
+	switch d := Dialect(strings.ToLower(strings.TrimSpace(s))); d {
+	case DialectLedger, DialectHledger, DialectBeancount:
+		return d, nil
+	case "":
+		return DialectLedger, nil
+	}
+	return "", ErrUnknownDialect
+}
+
+// Extension returns the conventional file extension for the dialect.
+func (d Dialect) Extension() string {
+	switch d {
+	case DialectHledger:
+		return "journal"
+	case DialectBeancount:
+		return "beancount"
+	default:
+		return "ledger"
+	}
+}
+
+// AccountName turns a free-form name into an account below root. Words are
+// capitalised and joined with '-', and ':' or '/' in the name start a
+// sub-account, so "food/eating out" under Expenses becomes
+// "Expenses:Food:Eating-Out". The result is valid in all three dialects.
+func AccountName(root, name string) string {
+	var parts []string
+	for _, segment := range strings.FieldsFunc(name, func(r rune) bool { return r == ':' || r == '/' }) {
+		words := strings.FieldsFunc(segment, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for i, w := range words {
+			r := []rune(w)
+			r[0] = unicode.ToUpper(r[0])
+			words[i] = string(r)
+		}
+		if len(words) > 0 {
+			parts = append(parts, strings.Join(words, "-"))
+		}
+	}
+	if len(parts) == 0 {
+		parts = []string{uncategorizedAccount}
+	}
+	return root + ":" + strings.Join(parts, ":")
+}
+
+// JournalWriter streams monthly data as double-entry transactions:
+//
+//   - income sources move money from Income:<Name> into Assets:Checking;
+//   - expenses move money from Assets:Checking to Expenses:<Category>;
+//   - budget sources are envelope allocations from Equity:Budget into
+//     Assets:Budget:<Name>, tagged "budget", and leave Assets:Checking alone.
+//
+// Assets:Checking therefore ends each month at the app's "remaining" amount.
+// Accounts are declared (opened, for beancount) the first time they are used.
+type JournalWriter struct {
+	w        *bufio.Writer
+	dialect  Dialect
+	currency string
+	started  bool
+	declared map[string]bool
+}
+
+// NewJournalWriter returns a JournalWriter writing amounts in the given currency.
+func NewJournalWriter(w io.Writer, dialect Dialect, currency string) *JournalWriter {
+	return &JournalWriter{
+		w:        bufio.NewWriter(w),
+		dialect:  dialect,
+		currency: strings.ToUpper(currency),
+		declared: map[string]bool{},
+	}
+}
+
+type journalPosting struct {
+	account string
+	amount  domain.Money
+}
+
+// WriteMonth writes one month's transactions and flushes them.
+func (j *JournalWriter) WriteMonth(data *domain.MonthlyData) error {
+	if !j.started {
+		j.started = true
+		j.header()
+	}
+	first := time.Date(data.Year, time.Month(data.Month), 1, 0, 0, 0, 0, time.UTC)
+
+	for _, s := range data.IncomeSources {
+		j.transaction(first, s.Name, "", []journalPosting{
+			{AccountChecking, s.AmountCents},
+			{AccountName(AccountIncomeRoot, s.Name), -s.AmountCents},
+		})
+	}
+	for _, s := range data.BudgetSources {
+		j.transaction(first, s.Name, "budget", []journalPosting{
+			{AccountName(AccountBudgetRoot, s.Name), s.AmountCents},
+			{AccountBudgetEquity, -s.AmountCents},
+		})
+	}
+	for _, e := range data.Expenses {
+		j.transaction(expenseDate(e, first), e.Description, "", []journalPosting{
+			{AccountName(AccountExpensesRoot, e.Category), e.AmountCents},
+			{AccountChecking, -e.AmountCents},
+		})
+	}
+	return j.w.Flush()
+}
+
+// expenseDate uses the creation day when it falls in the expense's month and
+// the first of the month otherwise (e.g. back-filled entries).
+func expenseDate(e domain.Expense, first time.Time) time.Time {
+	c := e.CreatedAt.UTC()
+	if c.Year() == first.Year() && c.Month() == first.Month() {
+		return time.Date(c.Year(), c.Month(), c.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return first
+}
+
+func (j *JournalWriter) header() {
+	switch j.dialect {
+	case DialectBeancount:
+		fmt.Fprintf(j.w, "option \"operating_currency\" \"%s\"\n\n", j.currency)
+	default:
+		fmt.Fprintf(j.w, "commodity %s\n\n", j.currency)
+	}
+}
+
+// declare emits the account directive before an account's first posting.
+func (j *JournalWriter) declare(date time.Time, account string) {
+	if j.declared[account] {
+		return
+	}
+	j.declared[account] = true
+	if j.dialect == DialectBeancount {
+		fmt.Fprintf(j.w, "%s open %s %s\n", date.Format(time.DateOnly), account, j.currency)
+		return
+	}
+	fmt.Fprintf(j.w, "account %s\n", account)
+}
+
+func (j *JournalWriter) transaction(date time.Time, description, tag string, postings []journalPosting) {
+	for _, p := range postings {
+		j.declare(date, p.account)
+	}
+
+	description = strings.Join(strings.Fields(description), " ")
+	day := date.Format(time.DateOnly)
+	switch j.dialect {
+	case DialectBeancount:
+		r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+		fmt.Fprintf(j.w, "%s * \"%s\"", day, r.Replace(description))
+		if tag != "" {
+			fmt.Fprintf(j.w, " #%s", tag)
+		}
+		j.w.WriteByte('\n')
+	default:
+		fmt.Fprintf(j.w, "%s * %s\n", day, description)
+		switch {
+		case tag == "":
+		case j.dialect == DialectHledger:
+			fmt.Fprintf(j.w, "    ; %s:\n", tag)
+		default:
+			fmt.Fprintf(j.w, "    ; :%s:\n", tag)
+		}
+	}
+	for _, p := range postings {
+		fmt.Fprintf(j.w, "    %-40s  %12s %s\n", p.account, journalAmount(p.amount), j.currency)
+	}
+	j.w.WriteByte('\n')
+}
+
+// journalAmount formats cents as a plain decimal ("-1234.56"), the only
+// number syntax all three tools agree on.
+func journalAmount(m domain.Money) string {
+	v := int64(m)
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return sign + strconv.FormatInt(v/100, 10) + "." + pad2(v%100)
+}
@@ -0,0 +1,213 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// parseJournal is a minimal reader for the subset of ledger, hledger and
+// beancount syntax the writer emits. It checks that every transaction balances
+// and returns the final balance per account, in cents.
+func parseJournal(t *testing.T, journal, currency string) (map[string]domain.Money, int) {
+	t.Helper()
+	balances := map[string]domain.Money{}
+	declared := map[string]bool{}
+	transactions := 0
+	var pending domain.Money
+	inTx := false
+
+	closeTx := func(line int) {
+		if inTx && pending != 0 {
+			t.Errorf("line %d: transaction does not balance (off by %d)", line, pending)
+		}
+		inTx, pending = false, 0
+	}
+
+	sc := bufio.NewScanner(strings.NewReader(journal))
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			closeTx(n)
+		case strings.HasPrefix(trimmed, ";"):
+			// comment or tag line
+		case line[0] == ' ':
+			if !inTx {
+				t.Fatalf("line %d: posting outside a transaction: %q", n, line)
+			}
+			fields := strings.Fields(trimmed)
+			if len(fields) != 3 || fields[2] != currency {
+				t.Fatalf("line %d: unexpected posting %q", n, line)
+			}
+			if !declared[fields[0]] {
+				t.Errorf("line %d: account %s used before it is declared", n, fields[0])
+			}
+			cents, err := parseCents(fields[1])
+			if err != nil {
+				t.Fatalf("line %d: %v", n, err)
+			}
+			balances[fields[0]] += cents
+			pending += cents
+		case strings.HasPrefix(line, "account "):
+			declared[strings.TrimPrefix(line, "account ")] = true
+		case strings.HasPrefix(line, "commodity "), strings.HasPrefix(line, "option "):
+		default:
+			fields := strings.Fields(line)
+			if _, err := time.Parse(time.DateOnly, fields[0]); err != nil || len(fields) < 2 {
+				t.Fatalf("line %d: unexpected line %q", n, line)
+			}
+			if fields[1] == "open" {
+				declared[fields[2]] = true
+				continue
+			}
+			closeTx(n)
+			inTx = true
+			transactions++
+		}
+	}
+	closeTx(-1)
+	return balances, transactions
+}
+
+func parseCents(s string) (domain.Money, error) {
+	neg := strings.HasPrefix(s, "-")
+	units, frac, ok := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+	if !ok || len(frac) != 2 {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	v, err := strconv.ParseInt(units+frac, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if neg {
+		v = -v
+	}
+	return domain.Money(v), nil
+}
+
+func TestJournalRoundTrip(t *testing.T) {
+	months := []*domain.MonthlyData{sampleMonth(1), sampleMonth(2)}
+	months[1].Expenses = append(months[1].Expenses,
+		domain.Expense{Description: `Cinema "IMAX"`, Category: "fun/movies", AmountCents: 1250,
+			CreatedAt: time.Date(2024, 2, 14, 20, 0, 0, 0, time.UTC)},
+		domain.Expense{Description: "Refund", Category: "", AmountCents: 99},
+	)
+	months[1].TotalExpenses += 1250 + 99
+	months[1].Remaining -= 1250 + 99
+
+	var remaining, income, budget domain.Money
+	byCategory := map[string]domain.Money{}
+	txCount := 0
+	for _, m := range months {
+		remaining += m.Remaining
+		income += m.TotalIncome
+		budget += m.TotalBudget
+		for _, e := range m.Expenses {
+			byCategory[AccountName(AccountExpensesRoot, e.Category)] += e.AmountCents
+		}
+		txCount += len(m.IncomeSources) + len(m.BudgetSources) + len(m.Expenses)
+	}
+
+	for _, dialect := range []Dialect{DialectLedger, DialectHledger, DialectBeancount} {
+		t.Run(string(dialect), func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewJournalWriter(&buf, dialect, "eur")
+			for _, m := range months {
+				if err := w.WriteMonth(m); err != nil {
+					t.Fatalf("WriteMonth failed: %v", err)
+				}
+			}
+
+			balances, transactions := parseJournal(t, buf.String(), "EUR")
+			if transactions != txCount {
+				t.Errorf("Expected %d transactions, got %d", txCount, transactions)
+			}
+			if balances[AccountChecking] != remaining {
+				t.Errorf("Expected %s balance %d, got %d", AccountChecking, remaining, balances[AccountChecking])
+			}
+			if balances["Income:Salary"] != -income {
+				t.Errorf("Expected Income:Salary balance %d, got %d", -income, balances["Income:Salary"])
+			}
+			if balances["Assets:Budget:Rent"] != budget || balances[AccountBudgetEquity] != -budget {
+				t.Errorf("Expected budget envelopes of %d, got %v", budget, balances)
+			}
+			for account, expected := range byCategory {
+				if balances[account] != expected {
+					t.Errorf("Expected %s balance %d, got %d", account, expected, balances[account])
+				}
+			}
+		})
+	}
+}
+
+func TestJournalSyntax(t *testing.T) {
+	data := sampleMonth(2)
+	data.Expenses[0].Description = `Cinema "IMAX"`
+	data.Expenses[0].CreatedAt = time.Date(2024, 2, 14, 20, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		dialect  Dialect
+		contains []string
+	}{
+		{DialectLedger, []string{"commodity EUR\n", "account Expenses:Food\n", "2024-02-14 * Cinema \"IMAX\"\n", "; :budget:"}},
+		{DialectHledger, []string{"account Assets:Checking\n", "; budget:\n"}},
+		{DialectBeancount, []string{
+			"option \"operating_currency\" \"EUR\"\n",
+			"2024-02-01 open Income:Salary EUR\n",
+			"2024-02-14 * \"Cinema \\\"IMAX\\\"\"\n",
+			"2024-02-01 * \"Rent\" #budget\n",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.dialect), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := NewJournalWriter(&buf, tt.dialect, "EUR").WriteMonth(data); err != nil {
+				t.Fatalf("WriteMonth failed: %v", err)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(buf.String(), s) {
+					t.Errorf("Expected output to contain %q:\n%s", s, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestAccountName(t *testing.T) {
+	tests := []struct {
+		name, expected string
+	}{
+		{"Food", "Expenses:Food"},
+		{"eating out", "Expenses:Eating-Out"},
+		{"food/eating out", "Expenses:Food:Eating-Out"},
+		{"Food & Drinks", "Expenses:Food-Drinks"},
+		{"  ", "Expenses:Uncategorized"},
+		{"santé", "Expenses:Santé"},
+	}
+	for _, tt := range tests {
+		if got := AccountName(AccountExpensesRoot, tt.name); got != tt.expected {
+			t.Errorf("AccountName(%q): Expected %q, got %q", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestParseDialect(t *testing.T) {
+	if d, err := ParseDialect(""); err != nil || d != DialectLedger {
+		t.Errorf("Expected ledger default, got %q (%v)", d, err)
+	}
+	if d, err := ParseDialect("Beancount"); err != nil || d != DialectBeancount {
+		t.Errorf("Expected beancount, got %q (%v)", d, err)
+	}
+	if _, err := ParseDialect("gnucash"); !errors.Is(err, ErrUnknownDialect) {
+		t.Errorf("Expected ErrUnknownDialect, got %v", err)
+	}
+}
@@ -0,0 +1,222 @@
+// Package export renders monthly and yearly budget data into spreadsheet,
+// report and journal formats (CSV, XLSX, PDF, ledger/hledger/beancount) for
+// download.
+package export
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// Supported locales and currencies (mirrors the web app's i18n and currency switch).
+const (
+	LocaleEN    = "en"
+	LocaleFR    = "fr"
+	CurrencyEUR = "EUR"
+	CurrencyUSD = "USD"
+)
+
+// Format describes how amounts and labels are rendered.
+type Format struct {
+	Locale   string
+	Currency string
+}
+
+// NewFormat normalises a locale/currency pair, falling back to English and euros
+// like the web app does.
+func NewFormat(locale, currency string) Format {
+	f := Format{Locale: LocaleEN, Currency: CurrencyEUR}
+	if strings.HasPrefix(strings.ToLower(locale), LocaleFR) {
+		f.Locale = LocaleFR
+	}
+	if strings.EqualFold(currency, CurrencyUSD) {
+		f.Currency = CurrencyUSD
+	}
+	return f
+}
+
+// Money formats cents like Intl.NumberFormat does for en-US / fr-FR:
+// "€1,234.56", "-$5.00", "1 234,56 €", "1 234,56 $US".
+func (f Format) Money(m domain.Money) string {
+	v := int64(m)
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	units := strconv.FormatInt(v/100, 10)
+	cents := v % 100
+
+	if f.Locale == LocaleFR {
+		// fr-FR groups with a narrow no-break space and separates the symbol with a no-break space.
+		return sign + group(units, "\u202f") + "," + pad2(cents) + "\u00a0" + f.symbol()
+	}
+	return sign + f.symbol() + group(units, ",") + "." + pad2(cents)
+}
+
+// Decimal formats cents as a plain number using the locale's decimal separator.
+func (f Format) Decimal(m domain.Money) string {
+	v := int64(m)
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	sep := "."
+	if f.Locale == LocaleFR {
+		sep = ","
+	}
+	return sign + strconv.FormatInt(v/100, 10) + sep + pad2(v%100)
+}
+
+// CSVDelimiter returns ';' for locales that use a decimal comma, as spreadsheet
+// software in those locales expects.
+func (f Format) CSVDelimiter() rune {
+	if f.Locale == LocaleFR {
+		return ';'
+	}
+	return ','
+}
+
+// SpreadsheetNumberFormat returns an XLSX number format code showing the currency.
+func (f Format) SpreadsheetNumberFormat() string {
+	if f.Locale == LocaleFR {
+		return `#,##0.00\ "` + f.symbol() + `"`
+	}
+	return `"` + f.symbol() + `"#,##0.00`
+}
+
+// MonthName returns the localised month name.
+func (f Format) MonthName(month int) string {
+	if month < 1 || month > 12 {
+		return ""
+	}
+	return monthNames[f.Locale][month-1]
+}
+
+// Date formats a calendar date ("March 5, 2024" / "5 mars 2024").
+func (f Format) Date(t time.Time) string {
+	if f.Locale == LocaleFR {
+		return strconv.Itoa(t.Day()) + " " + strings.ToLower(f.MonthName(int(t.Month()))) + " " + strconv.Itoa(t.Year())
+	}
+	return f.MonthName(int(t.Month())) + " " + strconv.Itoa(t.Day()) + ", " + strconv.Itoa(t.Year())
+}
+
+// T returns the localised label for key (falls back to the key itself).
+func (f Format) T(key string) string {
+	if s, ok := labels[f.Locale][key]; ok {
+		return s
+	}
+	return key
+}
+
+func (f Format) symbol() string {
+	switch {
+	case f.Currency == CurrencyUSD && f.Locale == LocaleFR:
+		return "$US"
+	case f.Currency == CurrencyUSD:
+		return "$"
+	default:
+		return "€"
+	}
+}
+
+func group(digits, sep string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
+
+func pad2(n int64) string {
+	if n < 10 {
+		return "0" + strconv.FormatInt(n, 10)
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+var monthNames = map[string][]string{
+	LocaleEN: {
+		"January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December",
+	},
+	LocaleFR: {
+		"Janvier", "Février", "Mars", "Avril", "Mai", "Juin",
+		"Juillet", "Août", "Septembre", "Octobre", "Novembre", "Décembre",
+	},
+}
+
+var labels = map[string]map[string]string{
+	LocaleEN: {
+		"month":          "Month",
+		"type":           "Type",
+		"name":           "Name",
+		"category":       "Category",
+		"amount":         "Amount",
+		"amount_cents":   "Amount (cents)",
+		"income":         "Income",
+		"budget":         "Budget",
+		"expense":        "Expense",
+		"expenses":       "Expenses",
+		"remaining":      "Remaining",
+		"summary":        "Summary",
+		"total":          "Total",
+		"total_income":   "Total income",
+		"total_budget":   "Total budget",
+		"total_expenses": "Total expenses",
+
+		"statement_title":  "Monthly statement",
+		"generated_on":     "Generated on",
+		"bank_amount":      "Bank balance",
+		"manual_budget":    "Manual budget",
+		"manual_remaining": "Manual budget remaining",
+		"none":             "None",
+
+		"calendar_name": "Budget",
+		"payday":        "Payday",
+		"due":           "Due",
+	},
+	LocaleFR: {
+		"month":          "Mois",
+		"type":           "Type",
+		"name":           "Libellé",
+		"category":       "Catégorie",
+		"amount":         "Montant",
+		"amount_cents":   "Montant (centimes)",
+		"income":         "Revenu",
+		"budget":         "Budget",
+		"expense":        "Dépense",
+		"expenses":       "Dépenses",
+		"remaining":      "Reste",
+		"summary":        "Résumé",
+		"total":          "Total",
+		"total_income":   "Total des revenus",
+		"total_budget":   "Total du budget",
+		"total_expenses": "Total des dépenses",
+
+		"statement_title":  "Relevé mensuel",
+		"generated_on":     "Généré le",
+		"bank_amount":      "Solde bancaire",
+		"manual_budget":    "Budget manuel",
+		"manual_remaining": "Reste du budget manuel",
+		"none":             "Aucun",
+
+		"calendar_name": "Budget",
+		"payday":        "Jour de paie",
+		"due":           "Échéance",
+	},
+}
@@ -0,0 +1,207 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 page size in PDF points.
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+)
+
+// Fonts registered on every page.
+const (
+	fontRegular = "F1"
+	fontBold    = "F2"
+)
+
+// pdfDocument is a minimal PDF 1.4 writer: standard Type1 fonts with
+// WinAnsiEncoding, text, filled rectangles and lines. It is enough for tabular
+// statements and simple charts without pulling in a PDF dependency.
+type pdfDocument struct {
+	pages []*bytes.Buffer
+}
+
+func newPDFDocument() *pdfDocument {
+	return &pdfDocument{}
+}
+
+// addPage starts a new page and returns its content stream.
+func (d *pdfDocument) addPage() *pdfPage {
+	buf := &bytes.Buffer{}
+	d.pages = append(d.pages, buf)
+	return &pdfPage{buf: buf}
+}
+
+// writeTo serialises the document.
+//
+//nolint:funlen
+func (d *pdfDocument) writeTo(w io.Writer) error {
+	var out bytes.Buffer
+	var offsets []int
+
+	obj := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Object numbering: 1 catalog, 2 page tree, 3-4 fonts, then page/content pairs.
+	const firstPageObj = 5
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPageObj+2*i)
+	}
+
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, content := range d.pages {
+		obj(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+				"/Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, fontRegular, fontBold, firstPageObj+2*i+1))
+
+		var z bytes.Buffer
+		zw := zlib.NewWriter(&z)
+		if _, err := zw.Write(content.Bytes()); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		obj(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", z.Len(), z.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// pdfPage accumulates content stream operators for one page.
+// Coordinates are in points from the bottom-left corner.
+type pdfPage struct {
+	buf *bytes.Buffer
+}
+
+// text draws s with its baseline starting at (x, y).
+func (p *pdfPage) text(font string, size, x, y float64, s string) {
+	fmt.Fprintf(p.buf, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfString(s))
+}
+
+// textRight draws s so that it ends at x.
+func (p *pdfPage) textRight(font string, size, x, y float64, s string) {
+	p.text(font, size, x-textWidth(s, size), y, s)
+}
+
+// rect fills a rectangle with an RGB colour (components 0-1).
+func (p *pdfPage) rect(x, y, w, h float64, r, g, b float64) {
+	fmt.Fprintf(p.buf, "%.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f\n", r, g, b, x, y, w, h)
+}
+
+// line strokes a grey line.
+func (p *pdfPage) line(x1, y1, x2, y2, grey, width float64) {
+	fmt.Fprintf(p.buf, "%.3f G %.2f w %.2f %.2f m %.2f %.2f l S\n", grey, width, x1, y1, x2, y2)
+}
+
+// fill resets the fill colour (used for text) to an RGB value.
+func (p *pdfPage) fill(r, g, b float64) {
+	fmt.Fprintf(p.buf, "%.3f %.3f %.3f rg\n", r, g, b)
+}
+
+// pdfString encodes s as WinAnsi and escapes it for a literal string.
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		c := winAnsi(r)
+		switch c {
+		case '\\', '(', ')':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			if c < 32 || c > 126 {
+				fmt.Fprintf(&b, "\\%03o", c)
+			} else {
+				b.WriteByte(c)
+			}
+		}
+	}
+	return b.String()
+}
+
+// winAnsi maps a rune to its WinAnsiEncoding byte; unsupported runes become '?'.
+func winAnsi(r rune) byte {
+	switch {
+	case r >= 32 && r <= 126, r >= 0xA0 && r <= 0xFF:
+		return byte(r)
+	case r == 0x202F || r == 0x2009:
+		// Thin and narrow no-break spaces (French digit grouping) have no WinAnsi slot.
+		return 0xA0
+	}
+	if c, ok := winAnsiExtras[r]; ok {
+		return c
+	}
+	return '?'
+}
+
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// textWidth approximates the width of s in Helvetica at the given size, using
+// the standard AFM widths for ASCII and 556/1000 em for everything else.
+func textWidth(s string, size float64) float64 {
+	units := 0
+	for _, r := range s {
+		if r >= 32 && r <= 126 {
+			units += helveticaWidths[r-32]
+		} else if r == 0xA0 || r == 0x202F {
+			units += 278
+		} else {
+			units += 556
+		}
+	}
+	return float64(units) * size / 1000
+}
+
+// truncate shortens s with an ellipsis so that it fits in maxWidth.
+func truncate(s string, size, maxWidth float64) string {
+	if textWidth(s, size) <= maxWidth {
+		return s
+	}
+	r := []rune(s)
+	for len(r) > 0 && textWidth(string(r)+"…", size) > maxWidth {
+		r = r[:len(r)-1]
+	}
+	return string(r) + "…"
+}
+
+// helveticaWidths are the Helvetica AFM advance widths for characters 32-126.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space - /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, // 0-9
+	278, 278, 584, 584, 584, 556, 1015, // : - @
+	667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, // A-M
+	722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, // N-Z
+	278, 278, 278, 469, 556, 333, // [ - `
+	556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, // a-m
+	556, 556, 556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, // n-z
+	334, 260, 334, 584, // { - ~
+}
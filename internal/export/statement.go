@@ -0,0 +1,225 @@
+package export
+
+import (
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// Statement layout (points).
+const (
+	marginX      = 50.0
+	marginTop    = 60.0
+	marginBottom = 60.0
+	contentRight = pageWidth - marginX
+	rowHeight    = 16.0
+	bodySize     = 10.0
+	headingSize  = 13.0
+	titleSize    = 18.0
+	chartHeight  = 120.0
+)
+
+// WriteMonthlyStatement renders a month's income, budget, expenses and manual
+// budget as a PDF statement with totals and a bar chart. manual may be nil.
+func WriteMonthlyStatement(
+	w io.Writer,
+	data *domain.MonthlyData,
+	manual *domain.ManualBudget,
+	f Format,
+	generatedAt time.Time,
+) error {
+	s := &statement{doc: newPDFDocument(), f: f}
+	s.newPage()
+
+	period := f.MonthName(data.Month) + " " + strconv.Itoa(data.Year)
+	s.page.text(fontBold, titleSize, marginX, s.y, f.T("statement_title")+" — "+period)
+	s.y -= 18
+	s.page.fill(0.4, 0.4, 0.4)
+	s.page.text(fontRegular, 9, marginX, s.y, f.T("generated_on")+" "+f.Date(generatedAt))
+	s.page.fill(0, 0, 0)
+	s.y -= 28
+
+	s.totals(data)
+	s.chart(data)
+
+	rows := make([]statementRow, 0, len(data.IncomeSources))
+	for _, src := range data.IncomeSources {
+		rows = append(rows, statementRow{label: src.Name, amount: src.AmountCents})
+	}
+	s.table(f.T("income"), false, rows, f.T("total_income"), data.TotalIncome)
+
+	rows = rows[:0]
+	for _, src := range data.BudgetSources {
+		rows = append(rows, statementRow{label: src.Name, amount: src.AmountCents})
+	}
+	s.table(f.T("budget"), false, rows, f.T("total_budget"), data.TotalBudget)
+
+	rows = rows[:0]
+	for _, e := range data.Expenses {
+		rows = append(rows, statementRow{label: e.Description, category: e.Category, amount: e.AmountCents})
+	}
+	s.table(f.T("expenses"), true, rows, f.T("total_expenses"), data.TotalExpenses)
+
+	if manual != nil && (manual.BankAmountCents != 0 || len(manual.Items) > 0) {
+		rows = append(rows[:0], statementRow{label: f.T("bank_amount"), amount: manual.BankAmountCents})
+		total := manual.BankAmountCents
+		for _, it := range manual.Items {
+			rows = append(rows, statementRow{label: it.Name, amount: it.AmountCents})
+			total += it.AmountCents
+		}
+		s.table(f.T("manual_budget"), false, rows, f.T("manual_remaining"), total)
+	}
+
+	s.ensure(rowHeight * 2)
+	s.page.line(marginX, s.y+12, contentRight, s.y+12, 0, 1)
+	s.page.text(fontBold, headingSize, marginX, s.y-4, f.T("remaining"))
+	s.page.textRight(fontBold, headingSize, contentRight, s.y-4, f.Money(data.Remaining))
+
+	return s.doc.writeTo(w)
+}
+
+type statementRow struct {
+	label    string
+	category string
+	amount   domain.Money
+}
+
+// statement tracks the current page and vertical cursor while laying out.
+type statement struct {
+	doc  *pdfDocument
+	page *pdfPage
+	f    Format
+	y    float64
+}
+
+func (s *statement) newPage() {
+	s.page = s.doc.addPage()
+	s.y = pageHeight - marginTop
+}
+
+// ensure starts a new page if fewer than h points remain.
+func (s *statement) ensure(h float64) {
+	if s.y-h < marginBottom {
+		s.newPage()
+	}
+}
+
+func (s *statement) totals(data *domain.MonthlyData) {
+	items := []struct {
+		key    string
+		amount domain.Money
+	}{
+		{"total_income", data.TotalIncome},
+		{"total_budget", data.TotalBudget},
+		{"total_expenses", data.TotalExpenses},
+		{"remaining", data.Remaining},
+	}
+	colWidth := (contentRight - marginX) / float64(len(items))
+	for i, it := range items {
+		x := marginX + float64(i)*colWidth
+		s.page.fill(0.4, 0.4, 0.4)
+		s.page.text(fontRegular, 9, x, s.y, s.f.T(it.key))
+		s.page.fill(0, 0, 0)
+		s.page.text(fontBold, 12, x, s.y-16, s.f.Money(it.amount))
+	}
+	s.y -= 44
+}
+
+// chart draws a vertical bar per total; negative remaining is drawn in red below the axis.
+func (s *statement) chart(data *domain.MonthlyData) {
+	bars := []struct {
+		key     string
+		amount  domain.Money
+		r, g, b float64
+	}{
+		{"income", data.TotalIncome, 0.20, 0.60, 0.35},
+		{"budget", data.TotalBudget, 0.25, 0.45, 0.80},
+		{"expenses", data.TotalExpenses, 0.90, 0.55, 0.15},
+		{"remaining", data.Remaining, 0.45, 0.45, 0.45},
+	}
+	var maxAbs domain.Money = 1
+	hasNegative := false
+	for _, b := range bars {
+		v := b.amount
+		if v < 0 {
+			v = -v
+			hasNegative = true
+		}
+		if v > maxAbs {
+			maxAbs = v
+		}
+	}
+
+	height := chartHeight
+	if hasNegative {
+		height *= 1.5
+	}
+	s.ensure(height + 30)
+
+	axis := s.y - chartHeight
+	scale := chartHeight / float64(maxAbs)
+	slot := (contentRight - marginX) / float64(len(bars))
+	barWidth := slot * 0.5
+
+	for i, b := range bars {
+		x := marginX + float64(i)*slot + (slot-barWidth)/2
+		h := float64(b.amount) * scale
+		if h >= 0 {
+			s.page.rect(x, axis, barWidth, h, b.r, b.g, b.b)
+		} else {
+			s.page.rect(x, axis+h, barWidth, -h, 0.85, 0.20, 0.20)
+		}
+		s.page.fill(0, 0, 0)
+		label := s.f.T(b.key)
+		s.page.text(fontRegular, 8, x+(barWidth-textWidth(label, 8))/2, axis-12, label)
+	}
+	s.page.line(marginX, axis, contentRight, axis, 0.3, 0.8)
+	s.page.fill(0, 0, 0)
+	s.y -= height + 30
+}
+
+// table draws a section heading, one row per item and a bold total row,
+// breaking across pages when needed.
+func (s *statement) table(title string, withCategory bool, rows []statementRow, totalLabel string, total domain.Money) {
+	categoryX := marginX + 260
+	labelWidth := contentRight - marginX - 110
+	if withCategory {
+		labelWidth = categoryX - marginX - 10
+	}
+
+	header := func() {
+		s.page.text(fontBold, headingSize, marginX, s.y, title)
+		s.y -= 6
+		s.page.line(marginX, s.y, contentRight, s.y, 0.6, 0.8)
+		s.y -= rowHeight
+	}
+
+	s.ensure(rowHeight * 4)
+	header()
+	if len(rows) == 0 {
+		s.page.fill(0.5, 0.5, 0.5)
+		s.page.text(fontRegular, bodySize, marginX, s.y, s.f.T("none"))
+		s.page.fill(0, 0, 0)
+		s.y -= rowHeight
+	}
+	for _, r := range rows {
+		if s.y-rowHeight < marginBottom {
+			s.newPage()
+			header()
+		}
+		s.page.text(fontRegular, bodySize, marginX, s.y, truncate(r.label, bodySize, labelWidth))
+		if withCategory && r.category != "" {
+			s.page.text(fontRegular, bodySize, categoryX, s.y,
+				truncate(r.category, bodySize, contentRight-categoryX-110))
+		}
+		s.page.textRight(fontRegular, bodySize, contentRight, s.y, s.f.Money(r.amount))
+		s.y -= rowHeight
+	}
+	s.ensure(rowHeight)
+	s.page.line(marginX, s.y+rowHeight-4, contentRight, s.y+rowHeight-4, 0.8, 0.5)
+	s.page.text(fontBold, bodySize, marginX, s.y, totalLabel)
+	s.page.textRight(fontBold, bodySize, contentRight, s.y, s.f.Money(total))
+	s.y -= rowHeight * 2
+}
@@ -0,0 +1,83 @@
+package export
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+func TestWriteMonthlyStatement(t *testing.T) {
+	data := sampleMonth(3)
+	manual := &domain.ManualBudget{
+		BankAmountCents: 50000,
+		Items:           []domain.ManualBudgetItem{{Name: "Savings", AmountCents: -20000}},
+	}
+
+	var buf bytes.Buffer
+	generated := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+	if err := WriteMonthlyStatement(&buf, data, manual, NewFormat("fr", "EUR"), generated); err != nil {
+		t.Fatalf("WriteMonthlyStatement failed: %v", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "%PDF-1.4\n") {
+		t.Errorf("Expected PDF header, got %q", out[:min(len(out), 16)])
+	}
+	if !strings.HasSuffix(out, "%%EOF\n") {
+		t.Errorf("Expected PDF trailer")
+	}
+
+	// startxref must point at the xref table.
+	i := strings.LastIndex(out, "startxref\n")
+	if i < 0 {
+		t.Fatal("missing startxref")
+	}
+	offset, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(out[i+len("startxref\n"):], "%%EOF\n")))
+	if err != nil {
+		t.Fatalf("invalid startxref: %v", err)
+	}
+	if !strings.HasPrefix(out[offset:], "xref\n") {
+		t.Errorf("startxref %d does not point at the xref table", offset)
+	}
+	if !strings.Contains(out, "/Count 1 >>") {
+		t.Errorf("Expected a single page")
+	}
+}
+
+func TestWriteMonthlyStatementPaging(t *testing.T) {
+	data := sampleMonth(1)
+	for i := 0; i < 120; i++ {
+		data.Expenses = append(data.Expenses, domain.Expense{
+			Description: "Item " + strconv.Itoa(i), Category: "Misc", AmountCents: 100,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := WriteMonthlyStatement(&buf, data, nil, NewFormat("en", "USD"), time.Now()); err != nil {
+		t.Fatalf("WriteMonthlyStatement failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "/Count 1 >>") {
+		t.Errorf("Expected a long expense list to span several pages")
+	}
+}
+
+func TestPDFString(t *testing.T) {
+	tests := []struct {
+		in, expected string
+	}{
+		{"plain", "plain"},
+		{"a (b) \\c", "a \\(b\\) \\\\c"},
+		{"€5", "\\2005"},
+		{"Dépenses", "D\\351penses"},
+		{"1\u202f234", "1\\240234"},
+		{"日本", "??"},
+	}
+	for _, tt := range tests {
+		if got := pdfString(tt.in); got != tt.expected {
+			t.Errorf("pdfString(%q): Expected %q, got %q", tt.in, tt.expected, got)
+		}
+	}
+}
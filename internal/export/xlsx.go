@@ -0,0 +1,342 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// Cell styles defined in styles.xml.
+const (
+	styleDefault = iota
+	styleMoney
+	styleBold
+	styleBoldMoney
+)
+
+// XLSXWriter streams an Office Open XML workbook: one sheet per month written
+// as soon as it is available, and a summary sheet (first in tab order) whose
+// totals are formulas referencing the month sheets. Only per-sheet totals are
+// kept in memory.
+type XLSXWriter struct {
+	zw     *zip.Writer
+	format Format
+	months []monthTotals
+	closed bool
+}
+
+// monthTotals remembers where a month sheet's totals live for the summary formulas.
+type monthTotals struct {
+	sheetName                                string
+	incomeRow, budgetRow, expenseRow         int
+	income, budget, expenses, remainingCents domain.Money
+}
+
+// NewXLSXWriter returns an XLSXWriter writing to w. Close must be called to
+// finish the workbook.
+func NewXLSXWriter(w io.Writer, f Format) *XLSXWriter {
+	return &XLSXWriter{zw: zip.NewWriter(w), format: f}
+}
+
+// WriteMonth appends a sheet for the given month.
+//
+//nolint:funlen
+func (x *XLSXWriter) WriteMonth(data *domain.MonthlyData) error {
+	f := x.format
+	// Sheet 1 is the summary; month sheets start at 2.
+	entry, err := x.zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(x.months)+2))
+	if err != nil {
+		return err
+	}
+	sw := newSheetWriter(entry)
+	sw.start([]float64{40, 24, 18})
+
+	mt := monthTotals{
+		sheetName:      sheetName(f.MonthName(data.Month) + " " + strconv.Itoa(data.Year)),
+		income:         data.TotalIncome,
+		budget:         data.TotalBudget,
+		expenses:       data.TotalExpenses,
+		remainingCents: data.Remaining,
+	}
+
+	sw.row(sw.str("A", mt.sheetName, styleBold))
+	sw.skip()
+
+	section := func(title, totalLabel string, rows [][2]string, amounts []domain.Money, total domain.Money) int {
+		sw.row(
+			sw.str("A", title, styleBold),
+			sw.str("B", f.T("category"), styleBold),
+			sw.str("C", f.T("amount"), styleBold),
+		)
+		first := sw.next
+		for i, r := range rows {
+			sw.row(sw.str("A", r[0], styleDefault), sw.str("B", r[1], styleDefault), sw.num("C", amounts[i], styleMoney))
+		}
+		last := sw.next - 1
+		formula := ""
+		if last >= first {
+			formula = fmt.Sprintf("SUM(C%d:C%d)", first, last)
+		}
+		totalRow := sw.next
+		sw.row(sw.str("A", totalLabel, styleBold), sw.formula("C", formula, total, styleBoldMoney))
+		sw.skip()
+		return totalRow
+	}
+
+	rows, amounts := make([][2]string, 0, len(data.IncomeSources)), make([]domain.Money, 0, len(data.IncomeSources))
+	for _, s := range data.IncomeSources {
+		rows, amounts = append(rows, [2]string{s.Name, ""}), append(amounts, s.AmountCents)
+	}
+	mt.incomeRow = section(f.T("income"), f.T("total_income"), rows, amounts, data.TotalIncome)
+
+	rows, amounts = rows[:0], amounts[:0]
+	for _, s := range data.BudgetSources {
+		rows, amounts = append(rows, [2]string{s.Name, ""}), append(amounts, s.AmountCents)
+	}
+	mt.budgetRow = section(f.T("budget"), f.T("total_budget"), rows, amounts, data.TotalBudget)
+
+	rows, amounts = rows[:0], amounts[:0]
+	for _, e := range data.Expenses {
+		rows, amounts = append(rows, [2]string{e.Description, e.Category}), append(amounts, e.AmountCents)
+	}
+	mt.expenseRow = section(f.T("expenses"), f.T("total_expenses"), rows, amounts, data.TotalExpenses)
+
+	sw.row(
+		sw.str("A", f.T("remaining"), styleBold),
+		sw.formula("C", fmt.Sprintf("C%d-C%d", mt.incomeRow, mt.expenseRow), data.Remaining, styleBoldMoney),
+	)
+
+	if err := sw.end(); err != nil {
+		return err
+	}
+	x.months = append(x.months, mt)
+	return nil
+}
+
+// Close writes the summary sheet and workbook metadata and finishes the zip stream.
+func (x *XLSXWriter) Close() error {
+	if x.closed {
+		return nil
+	}
+	x.closed = true
+	if err := x.writeSummary(); err != nil {
+		return err
+	}
+	if err := x.writeMetadata(); err != nil {
+		return err
+	}
+	return x.zw.Close()
+}
+
+func (x *XLSXWriter) writeSummary() error {
+	f := x.format
+	entry, err := x.zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	sw := newSheetWriter(entry)
+	sw.start([]float64{24, 18, 18, 18, 18})
+	sw.row(
+		sw.str("A", f.T("month"), styleBold),
+		sw.str("B", f.T("income"), styleBold),
+		sw.str("C", f.T("budget"), styleBold),
+		sw.str("D", f.T("expenses"), styleBold),
+		sw.str("E", f.T("remaining"), styleBold),
+	)
+
+	var totals [4]domain.Money
+	first := sw.next
+	for _, m := range x.months {
+		ref := quoteSheet(m.sheetName)
+		r := sw.next
+		sw.row(
+			sw.str("A", m.sheetName, styleDefault),
+			sw.formula("B", fmt.Sprintf("%s!C%d", ref, m.incomeRow), m.income, styleMoney),
+			sw.formula("C", fmt.Sprintf("%s!C%d", ref, m.budgetRow), m.budget, styleMoney),
+			sw.formula("D", fmt.Sprintf("%s!C%d", ref, m.expenseRow), m.expenses, styleMoney),
+			sw.formula("E", fmt.Sprintf("B%d-D%d", r, r), m.remainingCents, styleMoney),
+		)
+		totals[0] += m.income
+		totals[1] += m.budget
+		totals[2] += m.expenses
+		totals[3] += m.remainingCents
+	}
+	last := sw.next - 1
+	cells := []string{sw.str("A", f.T("total"), styleBold)}
+	for i, col := range []string{"B", "C", "D", "E"} {
+		formula := ""
+		if last >= first {
+			formula = fmt.Sprintf("SUM(%s%d:%s%d)", col, first, col, last)
+		}
+		cells = append(cells, sw.formula(col, formula, totals[i], styleBoldMoney))
+	}
+	sw.row(cells...)
+	return sw.end()
+}
+
+func (x *XLSXWriter) writeMetadata() error {
+	sheetCount := len(x.months) + 1
+
+	var ct strings.Builder
+	ct.WriteString(xml.Header)
+	ct.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	ct.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	ct.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	ct.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	ct.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := 1; i <= sheetCount; i++ {
+		fmt.Fprintf(&ct, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+	ct.WriteString(`</Types>`)
+
+	var wb strings.Builder
+	wb.WriteString(xml.Header)
+	wb.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	fmt.Fprintf(&wb, `<sheet name="%s" sheetId="1" r:id="rId1"/>`, escape(sheetName(x.format.T("summary"))))
+	for i, m := range x.months {
+		fmt.Fprintf(&wb, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(m.sheetName), i+2, i+2)
+	}
+	// Ask spreadsheet apps to recompute formulas on open; cached values are provided anyway.
+	wb.WriteString(`</sheets><calcPr calcId="0" fullCalcOnLoad="1"/></workbook>`)
+
+	var rels strings.Builder
+	rels.WriteString(xml.Header)
+	rels.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := 1; i <= sheetCount; i++ {
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i, i)
+	}
+	fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, sheetCount+1)
+	rels.WriteString(`</Relationships>`)
+
+	styles := xml.Header +
+		`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<numFmts count="1"><numFmt numFmtId="164" formatCode="` + escape(x.format.SpreadsheetNumberFormat()) + `"/></numFmts>` +
+		`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="4">` +
+		`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+		`<xf numFmtId="164" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1" applyNumberFormat="1"/>` +
+		`</cellXfs></styleSheet>`
+
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", ct.String()},
+		{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", wb.String()},
+		{"xl/_rels/workbook.xml.rels", rels.String()},
+		{"xl/styles.xml", styles},
+	}
+	for _, p := range parts {
+		w, err := x.zw.Create(p.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, p.body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sheetWriter emits <sheetData> rows with inline strings (no shared string table,
+// so nothing has to be buffered across sheets).
+type sheetWriter struct {
+	w    *bufio.Writer
+	next int // next row number (1-based)
+	err  error
+}
+
+func newSheetWriter(w io.Writer) *sheetWriter {
+	return &sheetWriter{w: bufio.NewWriter(w), next: 1}
+}
+
+func (s *sheetWriter) write(str string) {
+	if s.err == nil {
+		_, s.err = s.w.WriteString(str)
+	}
+}
+
+func (s *sheetWriter) start(widths []float64) {
+	s.write(xml.Header)
+	s.write(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><cols>`)
+	for i, w := range widths {
+		s.write(fmt.Sprintf(`<col min="%d" max="%d" width="%g" customWidth="1"/>`, i+1, i+1, w))
+	}
+	s.write(`</cols><sheetData>`)
+}
+
+func (s *sheetWriter) end() error {
+	s.write(`</sheetData></worksheet>`)
+	if s.err != nil {
+		return s.err
+	}
+	return s.w.Flush()
+}
+
+// row writes the cells (already rendered for the current row) and advances.
+func (s *sheetWriter) row(cells ...string) {
+	s.write(fmt.Sprintf(`<row r="%d">`, s.next))
+	for _, c := range cells {
+		s.write(c)
+	}
+	s.write(`</row>`)
+	s.next++
+}
+
+func (s *sheetWriter) skip() { s.next++ }
+
+func (s *sheetWriter) str(col, v string, style int) string {
+	return fmt.Sprintf(`<c r="%s%d" t="inlineStr" s="%d"><is><t xml:space="preserve">%s</t></is></c>`,
+		col, s.next, style, escape(v))
+}
+
+func (s *sheetWriter) num(col string, m domain.Money, style int) string {
+	return fmt.Sprintf(`<c r="%s%d" s="%d"><v>%s</v></c>`, col, s.next, style, decimal(m))
+}
+
+// formula writes a formula with its cached value; an empty formula writes the value only.
+func (s *sheetWriter) formula(col, f string, cached domain.Money, style int) string {
+	if f == "" {
+		return s.num(col, cached, style)
+	}
+	return fmt.Sprintf(`<c r="%s%d" s="%d"><f>%s</f><v>%s</v></c>`, col, s.next, style, escape(f), decimal(cached))
+}
+
+// decimal renders cents as a locale-independent number for the <v> element.
+func decimal(m domain.Money) string {
+	return NewFormat(LocaleEN, "").Decimal(m)
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// sheetName strips characters Excel forbids in sheet names and enforces the 31 char limit.
+func sheetName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '-'
+		}
+		return r
+	}, s)
+	if r := []rune(s); len(r) > 31 {
+		s = string(r[:31])
+	}
+	return s
+}
+
+// quoteSheet quotes a sheet name for use in a formula reference.
+func quoteSheet(name string) string {
+	return "'" + strings.ReplaceAll(name, "'", "''") + "'"
+}
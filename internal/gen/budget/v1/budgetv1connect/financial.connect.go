@@ -0,0 +1,591 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: budget/v1/financial.proto
+
+package budgetv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/mdco1990/webapp/internal/gen/budget/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// FinancialServiceName is the fully-qualified name of the FinancialService service.
+	FinancialServiceName = "budget.v1.FinancialService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// FinancialServiceGetMonthlySummaryProcedure is the fully-qualified name of the FinancialService's
+	// GetMonthlySummary RPC.
+	FinancialServiceGetMonthlySummaryProcedure = "/budget.v1.FinancialService/GetMonthlySummary"
+	// FinancialServiceGetMonthlyDataProcedure is the fully-qualified name of the FinancialService's
+	// GetMonthlyData RPC.
+	FinancialServiceGetMonthlyDataProcedure = "/budget.v1.FinancialService/GetMonthlyData"
+	// FinancialServiceSetIncomeProcedure is the fully-qualified name of the FinancialService's
+	// SetIncome RPC.
+	FinancialServiceSetIncomeProcedure = "/budget.v1.FinancialService/SetIncome"
+	// FinancialServiceGetIncomeProcedure is the fully-qualified name of the FinancialService's
+	// GetIncome RPC.
+	FinancialServiceGetIncomeProcedure = "/budget.v1.FinancialService/GetIncome"
+	// FinancialServiceListIncomeSourcesProcedure is the fully-qualified name of the FinancialService's
+	// ListIncomeSources RPC.
+	FinancialServiceListIncomeSourcesProcedure = "/budget.v1.FinancialService/ListIncomeSources"
+	// FinancialServiceAddIncomeSourceProcedure is the fully-qualified name of the FinancialService's
+	// AddIncomeSource RPC.
+	FinancialServiceAddIncomeSourceProcedure = "/budget.v1.FinancialService/AddIncomeSource"
+	// FinancialServiceUpdateIncomeSourceProcedure is the fully-qualified name of the FinancialService's
+	// UpdateIncomeSource RPC.
+	FinancialServiceUpdateIncomeSourceProcedure = "/budget.v1.FinancialService/UpdateIncomeSource"
+	// FinancialServiceDeleteIncomeSourceProcedure is the fully-qualified name of the FinancialService's
+	// DeleteIncomeSource RPC.
+	FinancialServiceDeleteIncomeSourceProcedure = "/budget.v1.FinancialService/DeleteIncomeSource"
+	// FinancialServiceSetBudgetProcedure is the fully-qualified name of the FinancialService's
+	// SetBudget RPC.
+	FinancialServiceSetBudgetProcedure = "/budget.v1.FinancialService/SetBudget"
+	// FinancialServiceGetBudgetProcedure is the fully-qualified name of the FinancialService's
+	// GetBudget RPC.
+	FinancialServiceGetBudgetProcedure = "/budget.v1.FinancialService/GetBudget"
+	// FinancialServiceListBudgetSourcesProcedure is the fully-qualified name of the FinancialService's
+	// ListBudgetSources RPC.
+	FinancialServiceListBudgetSourcesProcedure = "/budget.v1.FinancialService/ListBudgetSources"
+	// FinancialServiceAddBudgetSourceProcedure is the fully-qualified name of the FinancialService's
+	// AddBudgetSource RPC.
+	FinancialServiceAddBudgetSourceProcedure = "/budget.v1.FinancialService/AddBudgetSource"
+	// FinancialServiceUpdateBudgetSourceProcedure is the fully-qualified name of the FinancialService's
+	// UpdateBudgetSource RPC.
+	FinancialServiceUpdateBudgetSourceProcedure = "/budget.v1.FinancialService/UpdateBudgetSource"
+	// FinancialServiceDeleteBudgetSourceProcedure is the fully-qualified name of the FinancialService's
+	// DeleteBudgetSource RPC.
+	FinancialServiceDeleteBudgetSourceProcedure = "/budget.v1.FinancialService/DeleteBudgetSource"
+	// FinancialServiceAddExpenseProcedure is the fully-qualified name of the FinancialService's
+	// AddExpense RPC.
+	FinancialServiceAddExpenseProcedure = "/budget.v1.FinancialService/AddExpense"
+	// FinancialServiceDeleteExpenseProcedure is the fully-qualified name of the FinancialService's
+	// DeleteExpense RPC.
+	FinancialServiceDeleteExpenseProcedure = "/budget.v1.FinancialService/DeleteExpense"
+	// FinancialServiceListExpensesProcedure is the fully-qualified name of the FinancialService's
+	// ListExpenses RPC.
+	FinancialServiceListExpensesProcedure = "/budget.v1.FinancialService/ListExpenses"
+)
+
+// FinancialServiceClient is a client for the budget.v1.FinancialService service.
+type FinancialServiceClient interface {
+	GetMonthlySummary(context.Context, *connect.Request[v1.GetMonthlySummaryRequest]) (*connect.Response[v1.GetMonthlySummaryResponse], error)
+	GetMonthlyData(context.Context, *connect.Request[v1.GetMonthlyDataRequest]) (*connect.Response[v1.GetMonthlyDataResponse], error)
+	SetIncome(context.Context, *connect.Request[v1.SetIncomeRequest]) (*connect.Response[v1.SetIncomeResponse], error)
+	GetIncome(context.Context, *connect.Request[v1.GetIncomeRequest]) (*connect.Response[v1.GetIncomeResponse], error)
+	ListIncomeSources(context.Context, *connect.Request[v1.ListIncomeSourcesRequest]) (*connect.Response[v1.ListIncomeSourcesResponse], error)
+	AddIncomeSource(context.Context, *connect.Request[v1.AddIncomeSourceRequest]) (*connect.Response[v1.AddIncomeSourceResponse], error)
+	UpdateIncomeSource(context.Context, *connect.Request[v1.UpdateIncomeSourceRequest]) (*connect.Response[v1.UpdateIncomeSourceResponse], error)
+	DeleteIncomeSource(context.Context, *connect.Request[v1.DeleteIncomeSourceRequest]) (*connect.Response[v1.DeleteIncomeSourceResponse], error)
+	SetBudget(context.Context, *connect.Request[v1.SetBudgetRequest]) (*connect.Response[v1.SetBudgetResponse], error)
+	GetBudget(context.Context, *connect.Request[v1.GetBudgetRequest]) (*connect.Response[v1.GetBudgetResponse], error)
+	ListBudgetSources(context.Context, *connect.Request[v1.ListBudgetSourcesRequest]) (*connect.Response[v1.ListBudgetSourcesResponse], error)
+	AddBudgetSource(context.Context, *connect.Request[v1.AddBudgetSourceRequest]) (*connect.Response[v1.AddBudgetSourceResponse], error)
+	UpdateBudgetSource(context.Context, *connect.Request[v1.UpdateBudgetSourceRequest]) (*connect.Response[v1.UpdateBudgetSourceResponse], error)
+	DeleteBudgetSource(context.Context, *connect.Request[v1.DeleteBudgetSourceRequest]) (*connect.Response[v1.DeleteBudgetSourceResponse], error)
+	AddExpense(context.Context, *connect.Request[v1.AddExpenseRequest]) (*connect.Response[v1.AddExpenseResponse], error)
+	DeleteExpense(context.Context, *connect.Request[v1.DeleteExpenseRequest]) (*connect.Response[v1.DeleteExpenseResponse], error)
+	ListExpenses(context.Context, *connect.Request[v1.ListExpensesRequest]) (*connect.Response[v1.ListExpensesResponse], error)
+}
+
+// NewFinancialServiceClient constructs a client for the budget.v1.FinancialService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewFinancialServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) FinancialServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	financialServiceMethods := v1.File_budget_v1_financial_proto.Services().ByName("FinancialService").Methods()
+	return &financialServiceClient{
+		getMonthlySummary: connect.NewClient[v1.GetMonthlySummaryRequest, v1.GetMonthlySummaryResponse](
+			httpClient,
+			baseURL+FinancialServiceGetMonthlySummaryProcedure,
+			connect.WithSchema(financialServiceMethods.ByName("GetMonthlySummary")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		getMonthlyData: connect.NewClient[v1.GetMonthlyDataRequest, v1.GetMonthlyDataResponse](
+			httpClient,
+			baseURL+FinancialServiceGetMonthlyDataProcedure,
+			connect.WithSchema(financialServiceMethods.ByName("GetMonthlyData")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		setIncome: connect.NewClient[v1.SetIncomeRequest, v1.SetIncomeResponse](
+			httpClient,
+			baseURL+FinancialServiceSetIncomeProcedure,
+			connect.WithSchema(financialServiceMethods.ByName("SetIncome")),
+			connect.WithIdempotency(connect.IdempotencyIdempotent),
+			connect.WithClientOptions(opts...),
+		),
+		getIncome: connect.NewClient[v1.GetIncomeRequest, v1.GetIncomeResponse](
+			httpClient,
+			baseURL+FinancialServiceGetIncomeProcedure,
+			connect.WithSchema(financialServiceMethods.ByName("GetIncome")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		listIncomeSources: connect.NewClient[v1.ListIncomeSourcesRequest, v1.ListIncomeSourcesResponse](
+			httpClient,
+			baseURL+FinancialServiceListIncomeSourcesProcedure,
+			connect.WithSchema(financialServiceMethods.ByName("ListIncomeSources")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		addIncomeSource: connect.NewClient[v1.AddIncomeSourceRequest, v1.AddIncomeSourceResponse](
+			httpClient,
+			baseURL+FinancialServiceAddIncomeSourceProcedure,
+			connect.WithSchema(financialServiceMethods.ByName("AddIncomeSource")),
+			connect.WithClientOptions(opts...),
+		),
+		updateIncomeSource: connect.NewClient[v1.UpdateIncomeSourceRequest, v1.UpdateIncomeSourceResponse](
+			httpClient,
+			baseURL+FinancialServiceUpdateIncomeSourceProcedure,
+			connect.WithSchema(financialServiceMethods.ByName("UpdateIncomeSource")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteIncomeSource: connect.NewClient[v1.DeleteIncomeSourceRequest, v1.DeleteIncomeSourceResponse](
+			httpClient,
+			baseURL+FinancialServiceDeleteIncomeSourceProcedure,
+			connect.WithSchema(financialServiceMethods.ByName("DeleteIncomeSource")),
+			connect.WithClientOptions(opts...),
+		),
+		setBudget: connect.NewClient[v1.SetBudgetRequest, v1.SetBudgetResponse](
+			httpClient,
+			baseURL+FinancialServiceSetBudgetProcedure,
+			connect.WithSchema(financialServiceMethods.ByName("SetBudget")),
+			connect.WithIdempotency(connect.IdempotencyIdempotent),
+			connect.WithClientOptions(opts...),
+		),
+		getBudget: connect.NewClient[v1.GetBudgetRequest, v1.GetBudgetResponse](
+			httpClient,
+			baseURL+FinancialServiceGetBudgetProcedure,
+			connect.WithSchema(financialServiceMethods.ByName("GetBudget")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		listBudgetSources: connect.NewClient[v1.ListBudgetSourcesRequest, v1.ListBudgetSourcesResponse](
+			httpClient,
+			baseURL+FinancialServiceListBudgetSourcesProcedure,
+			connect.WithSchema(financialServiceMethods.ByName("ListBudgetSources")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		addBudgetSource: connect.NewClient[v1.AddBudgetSourceRequest, v1.AddBudgetSourceResponse](
+			httpClient,
+			baseURL+FinancialServiceAddBudgetSourceProcedure,
+			connect.WithSchema(financialServiceMethods.ByName("AddBudgetSource")),
+			connect.WithClientOptions(opts...),
+		),
+		updateBudgetSource: connect.NewClient[v1.UpdateBudgetSourceRequest, v1.UpdateBudgetSourceResponse](
+			httpClient,
+			baseURL+FinancialServiceUpdateBudgetSourceProcedure,
+			connect.WithSchema(financialServiceMethods.ByName("UpdateBudgetSource")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteBudgetSource: connect.NewClient[v1.DeleteBudgetSourceRequest, v1.DeleteBudgetSourceResponse](
+			httpClient,
+			baseURL+FinancialServiceDeleteBudgetSourceProcedure,
+			connect.WithSchema(financialServiceMethods.ByName("DeleteBudgetSource")),
+			connect.WithClientOptions(opts...),
+		),
+		addExpense: connect.NewClient[v1.AddExpenseRequest, v1.AddExpenseResponse](
+			httpClient,
+			baseURL+FinancialServiceAddExpenseProcedure,
+			connect.WithSchema(financialServiceMethods.ByName("AddExpense")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteExpense: connect.NewClient[v1.DeleteExpenseRequest, v1.DeleteExpenseResponse](
+			httpClient,
+			baseURL+FinancialServiceDeleteExpenseProcedure,
+			connect.WithSchema(financialServiceMethods.ByName("DeleteExpense")),
+			connect.WithClientOptions(opts...),
+		),
+		listExpenses: connect.NewClient[v1.ListExpensesRequest, v1.ListExpensesResponse](
+			httpClient,
+			baseURL+FinancialServiceListExpensesProcedure,
+			connect.WithSchema(financialServiceMethods.ByName("ListExpenses")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// financialServiceClient implements FinancialServiceClient.
+type financialServiceClient struct {
+	getMonthlySummary  *connect.Client[v1.GetMonthlySummaryRequest, v1.GetMonthlySummaryResponse]
+	getMonthlyData     *connect.Client[v1.GetMonthlyDataRequest, v1.GetMonthlyDataResponse]
+	setIncome          *connect.Client[v1.SetIncomeRequest, v1.SetIncomeResponse]
+	getIncome          *connect.Client[v1.GetIncomeRequest, v1.GetIncomeResponse]
+	listIncomeSources  *connect.Client[v1.ListIncomeSourcesRequest, v1.ListIncomeSourcesResponse]
+	addIncomeSource    *connect.Client[v1.AddIncomeSourceRequest, v1.AddIncomeSourceResponse]
+	updateIncomeSource *connect.Client[v1.UpdateIncomeSourceRequest, v1.UpdateIncomeSourceResponse]
+	deleteIncomeSource *connect.Client[v1.DeleteIncomeSourceRequest, v1.DeleteIncomeSourceResponse]
+	setBudget          *connect.Client[v1.SetBudgetRequest, v1.SetBudgetResponse]
+	getBudget          *connect.Client[v1.GetBudgetRequest, v1.GetBudgetResponse]
+	listBudgetSources  *connect.Client[v1.ListBudgetSourcesRequest, v1.ListBudgetSourcesResponse]
+	addBudgetSource    *connect.Client[v1.AddBudgetSourceRequest, v1.AddBudgetSourceResponse]
+	updateBudgetSource *connect.Client[v1.UpdateBudgetSourceRequest, v1.UpdateBudgetSourceResponse]
+	deleteBudgetSource *connect.Client[v1.DeleteBudgetSourceRequest, v1.DeleteBudgetSourceResponse]
+	addExpense         *connect.Client[v1.AddExpenseRequest, v1.AddExpenseResponse]
+	deleteExpense      *connect.Client[v1.DeleteExpenseRequest, v1.DeleteExpenseResponse]
+	listExpenses       *connect.Client[v1.ListExpensesRequest, v1.ListExpensesResponse]
+}
+
+// GetMonthlySummary calls budget.v1.FinancialService.GetMonthlySummary.
+func (c *financialServiceClient) GetMonthlySummary(ctx context.Context, req *connect.Request[v1.GetMonthlySummaryRequest]) (*connect.Response[v1.GetMonthlySummaryResponse], error) {
+	return c.getMonthlySummary.CallUnary(ctx, req)
+}
+
+// GetMonthlyData calls budget.v1.FinancialService.GetMonthlyData.
+func (c *financialServiceClient) GetMonthlyData(ctx context.Context, req *connect.Request[v1.GetMonthlyDataRequest]) (*connect.Response[v1.GetMonthlyDataResponse], error) {
+	return c.getMonthlyData.CallUnary(ctx, req)
+}
+
+// SetIncome calls budget.v1.FinancialService.SetIncome.
+func (c *financialServiceClient) SetIncome(ctx context.Context, req *connect.Request[v1.SetIncomeRequest]) (*connect.Response[v1.SetIncomeResponse], error) {
+	return c.setIncome.CallUnary(ctx, req)
+}
+
+// GetIncome calls budget.v1.FinancialService.GetIncome.
+func (c *financialServiceClient) GetIncome(ctx context.Context, req *connect.Request[v1.GetIncomeRequest]) (*connect.Response[v1.GetIncomeResponse], error) {
+	return c.getIncome.CallUnary(ctx, req)
+}
+
+// ListIncomeSources calls budget.v1.FinancialService.ListIncomeSources.
+func (c *financialServiceClient) ListIncomeSources(ctx context.Context, req *connect.Request[v1.ListIncomeSourcesRequest]) (*connect.Response[v1.ListIncomeSourcesResponse], error) {
+	return c.listIncomeSources.CallUnary(ctx, req)
+}
+
+// AddIncomeSource calls budget.v1.FinancialService.AddIncomeSource.
+func (c *financialServiceClient) AddIncomeSource(ctx context.Context, req *connect.Request[v1.AddIncomeSourceRequest]) (*connect.Response[v1.AddIncomeSourceResponse], error) {
+	return c.addIncomeSource.CallUnary(ctx, req)
+}
+
+// UpdateIncomeSource calls budget.v1.FinancialService.UpdateIncomeSource.
+func (c *financialServiceClient) UpdateIncomeSource(ctx context.Context, req *connect.Request[v1.UpdateIncomeSourceRequest]) (*connect.Response[v1.UpdateIncomeSourceResponse], error) {
+	return c.updateIncomeSource.CallUnary(ctx, req)
+}
+
+// DeleteIncomeSource calls budget.v1.FinancialService.DeleteIncomeSource.
+func (c *financialServiceClient) DeleteIncomeSource(ctx context.Context, req *connect.Request[v1.DeleteIncomeSourceRequest]) (*connect.Response[v1.DeleteIncomeSourceResponse], error) {
+	return c.deleteIncomeSource.CallUnary(ctx, req)
+}
+
+// SetBudget calls budget.v1.FinancialService.SetBudget.
+func (c *financialServiceClient) SetBudget(ctx context.Context, req *connect.Request[v1.SetBudgetRequest]) (*connect.Response[v1.SetBudgetResponse], error) {
+	return c.setBudget.CallUnary(ctx, req)
+}
+
+// GetBudget calls budget.v1.FinancialService.GetBudget.
+func (c *financialServiceClient) GetBudget(ctx context.Context, req *connect.Request[v1.GetBudgetRequest]) (*connect.Response[v1.GetBudgetResponse], error) {
+	return c.getBudget.CallUnary(ctx, req)
+}
+
+// ListBudgetSources calls budget.v1.FinancialService.ListBudgetSources.
+func (c *financialServiceClient) ListBudgetSources(ctx context.Context, req *connect.Request[v1.ListBudgetSourcesRequest]) (*connect.Response[v1.ListBudgetSourcesResponse], error) {
+	return c.listBudgetSources.CallUnary(ctx, req)
+}
+
+// AddBudgetSource calls budget.v1.FinancialService.AddBudgetSource.
+func (c *financialServiceClient) AddBudgetSource(ctx context.Context, req *connect.Request[v1.AddBudgetSourceRequest]) (*connect.Response[v1.AddBudgetSourceResponse], error) {
+	return c.addBudgetSource.CallUnary(ctx, req)
+}
+
+// UpdateBudgetSource calls budget.v1.FinancialService.UpdateBudgetSource.
+func (c *financialServiceClient) UpdateBudgetSource(ctx context.Context, req *connect.Request[v1.UpdateBudgetSourceRequest]) (*connect.Response[v1.UpdateBudgetSourceResponse], error) {
+	return c.updateBudgetSource.CallUnary(ctx, req)
+}
+
+// DeleteBudgetSource calls budget.v1.FinancialService.DeleteBudgetSource.
+func (c *financialServiceClient) DeleteBudgetSource(ctx context.Context, req *connect.Request[v1.DeleteBudgetSourceRequest]) (*connect.Response[v1.DeleteBudgetSourceResponse], error) {
+	return c.deleteBudgetSource.CallUnary(ctx, req)
+}
+
+// AddExpense calls budget.v1.FinancialService.AddExpense.
+func (c *financialServiceClient) AddExpense(ctx context.Context, req *connect.Request[v1.AddExpenseRequest]) (*connect.Response[v1.AddExpenseResponse], error) {
+	return c.addExpense.CallUnary(ctx, req)
+}
+
+// DeleteExpense calls budget.v1.FinancialService.DeleteExpense.
+func (c *financialServiceClient) DeleteExpense(ctx context.Context, req *connect.Request[v1.DeleteExpenseRequest]) (*connect.Response[v1.DeleteExpenseResponse], error) {
+	return c.deleteExpense.CallUnary(ctx, req)
+}
+
+// ListExpenses calls budget.v1.FinancialService.ListExpenses.
+func (c *financialServiceClient) ListExpenses(ctx context.Context, req *connect.Request[v1.ListExpensesRequest]) (*connect.Response[v1.ListExpensesResponse], error) {
+	return c.listExpenses.CallUnary(ctx, req)
+}
+
+// FinancialServiceHandler is an implementation of the budget.v1.FinancialService service.
+type FinancialServiceHandler interface {
+	GetMonthlySummary(context.Context, *connect.Request[v1.GetMonthlySummaryRequest]) (*connect.Response[v1.GetMonthlySummaryResponse], error)
+	GetMonthlyData(context.Context, *connect.Request[v1.GetMonthlyDataRequest]) (*connect.Response[v1.GetMonthlyDataResponse], error)
+	SetIncome(context.Context, *connect.Request[v1.SetIncomeRequest]) (*connect.Response[v1.SetIncomeResponse], error)
+	GetIncome(context.Context, *connect.Request[v1.GetIncomeRequest]) (*connect.Response[v1.GetIncomeResponse], error)
+	ListIncomeSources(context.Context, *connect.Request[v1.ListIncomeSourcesRequest]) (*connect.Response[v1.ListIncomeSourcesResponse], error)
+	AddIncomeSource(context.Context, *connect.Request[v1.AddIncomeSourceRequest]) (*connect.Response[v1.AddIncomeSourceResponse], error)
+	UpdateIncomeSource(context.Context, *connect.Request[v1.UpdateIncomeSourceRequest]) (*connect.Response[v1.UpdateIncomeSourceResponse], error)
+	DeleteIncomeSource(context.Context, *connect.Request[v1.DeleteIncomeSourceRequest]) (*connect.Response[v1.DeleteIncomeSourceResponse], error)
+	SetBudget(context.Context, *connect.Request[v1.SetBudgetRequest]) (*connect.Response[v1.SetBudgetResponse], error)
+	GetBudget(context.Context, *connect.Request[v1.GetBudgetRequest]) (*connect.Response[v1.GetBudgetResponse], error)
+	ListBudgetSources(context.Context, *connect.Request[v1.ListBudgetSourcesRequest]) (*connect.Response[v1.ListBudgetSourcesResponse], error)
+	AddBudgetSource(context.Context, *connect.Request[v1.AddBudgetSourceRequest]) (*connect.Response[v1.AddBudgetSourceResponse], error)
+	UpdateBudgetSource(context.Context, *connect.Request[v1.UpdateBudgetSourceRequest]) (*connect.Response[v1.UpdateBudgetSourceResponse], error)
+	DeleteBudgetSource(context.Context, *connect.Request[v1.DeleteBudgetSourceRequest]) (*connect.Response[v1.DeleteBudgetSourceResponse], error)
+	AddExpense(context.Context, *connect.Request[v1.AddExpenseRequest]) (*connect.Response[v1.AddExpenseResponse], error)
+	DeleteExpense(context.Context, *connect.Request[v1.DeleteExpenseRequest]) (*connect.Response[v1.DeleteExpenseResponse], error)
+	ListExpenses(context.Context, *connect.Request[v1.ListExpensesRequest]) (*connect.Response[v1.ListExpensesResponse], error)
+}
+
+// NewFinancialServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewFinancialServiceHandler(svc FinancialServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	financialServiceMethods := v1.File_budget_v1_financial_proto.Services().ByName("FinancialService").Methods()
+	financialServiceGetMonthlySummaryHandler := connect.NewUnaryHandler(
+		FinancialServiceGetMonthlySummaryProcedure,
+		svc.GetMonthlySummary,
+		connect.WithSchema(financialServiceMethods.ByName("GetMonthlySummary")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	financialServiceGetMonthlyDataHandler := connect.NewUnaryHandler(
+		FinancialServiceGetMonthlyDataProcedure,
+		svc.GetMonthlyData,
+		connect.WithSchema(financialServiceMethods.ByName("GetMonthlyData")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	financialServiceSetIncomeHandler := connect.NewUnaryHandler(
+		FinancialServiceSetIncomeProcedure,
+		svc.SetIncome,
+		connect.WithSchema(financialServiceMethods.ByName("SetIncome")),
+		connect.WithIdempotency(connect.IdempotencyIdempotent),
+		connect.WithHandlerOptions(opts...),
+	)
+	financialServiceGetIncomeHandler := connect.NewUnaryHandler(
+		FinancialServiceGetIncomeProcedure,
+		svc.GetIncome,
+		connect.WithSchema(financialServiceMethods.ByName("GetIncome")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	financialServiceListIncomeSourcesHandler := connect.NewUnaryHandler(
+		FinancialServiceListIncomeSourcesProcedure,
+		svc.ListIncomeSources,
+		connect.WithSchema(financialServiceMethods.ByName("ListIncomeSources")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	financialServiceAddIncomeSourceHandler := connect.NewUnaryHandler(
+		FinancialServiceAddIncomeSourceProcedure,
+		svc.AddIncomeSource,
+		connect.WithSchema(financialServiceMethods.ByName("AddIncomeSource")),
+		connect.WithHandlerOptions(opts...),
+	)
+	financialServiceUpdateIncomeSourceHandler := connect.NewUnaryHandler(
+		FinancialServiceUpdateIncomeSourceProcedure,
+		svc.UpdateIncomeSource,
+		connect.WithSchema(financialServiceMethods.ByName("UpdateIncomeSource")),
+		connect.WithHandlerOptions(opts...),
+	)
+	financialServiceDeleteIncomeSourceHandler := connect.NewUnaryHandler(
+		FinancialServiceDeleteIncomeSourceProcedure,
+		svc.DeleteIncomeSource,
+		connect.WithSchema(financialServiceMethods.ByName("DeleteIncomeSource")),
+		connect.WithHandlerOptions(opts...),
+	)
+	financialServiceSetBudgetHandler := connect.NewUnaryHandler(
+		FinancialServiceSetBudgetProcedure,
+		svc.SetBudget,
+		connect.WithSchema(financialServiceMethods.ByName("SetBudget")),
+		connect.WithIdempotency(connect.IdempotencyIdempotent),
+		connect.WithHandlerOptions(opts...),
+	)
+	financialServiceGetBudgetHandler := connect.NewUnaryHandler(
+		FinancialServiceGetBudgetProcedure,
+		svc.GetBudget,
+		connect.WithSchema(financialServiceMethods.ByName("GetBudget")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	financialServiceListBudgetSourcesHandler := connect.NewUnaryHandler(
+		FinancialServiceListBudgetSourcesProcedure,
+		svc.ListBudgetSources,
+		connect.WithSchema(financialServiceMethods.ByName("ListBudgetSources")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	financialServiceAddBudgetSourceHandler := connect.NewUnaryHandler(
+		FinancialServiceAddBudgetSourceProcedure,
+		svc.AddBudgetSource,
+		connect.WithSchema(financialServiceMethods.ByName("AddBudgetSource")),
+		connect.WithHandlerOptions(opts...),
+	)
+	financialServiceUpdateBudgetSourceHandler := connect.NewUnaryHandler(
+		FinancialServiceUpdateBudgetSourceProcedure,
+		svc.UpdateBudgetSource,
+		connect.WithSchema(financialServiceMethods.ByName("UpdateBudgetSource")),
+		connect.WithHandlerOptions(opts...),
+	)
+	financialServiceDeleteBudgetSourceHandler := connect.NewUnaryHandler(
+		FinancialServiceDeleteBudgetSourceProcedure,
+		svc.DeleteBudgetSource,
+		connect.WithSchema(financialServiceMethods.ByName("DeleteBudgetSource")),
+		connect.WithHandlerOptions(opts...),
+	)
+	financialServiceAddExpenseHandler := connect.NewUnaryHandler(
+		FinancialServiceAddExpenseProcedure,
+		svc.AddExpense,
+		connect.WithSchema(financialServiceMethods.ByName("AddExpense")),
+		connect.WithHandlerOptions(opts...),
+	)
+	financialServiceDeleteExpenseHandler := connect.NewUnaryHandler(
+		FinancialServiceDeleteExpenseProcedure,
+		svc.DeleteExpense,
+		connect.WithSchema(financialServiceMethods.ByName("DeleteExpense")),
+		connect.WithHandlerOptions(opts...),
+	)
+	financialServiceListExpensesHandler := connect.NewUnaryHandler(
+		FinancialServiceListExpensesProcedure,
+		svc.ListExpenses,
+		connect.WithSchema(financialServiceMethods.ByName("ListExpenses")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/budget.v1.FinancialService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case FinancialServiceGetMonthlySummaryProcedure:
+			financialServiceGetMonthlySummaryHandler.ServeHTTP(w, r)
+		case FinancialServiceGetMonthlyDataProcedure:
+			financialServiceGetMonthlyDataHandler.ServeHTTP(w, r)
+		case FinancialServiceSetIncomeProcedure:
+			financialServiceSetIncomeHandler.ServeHTTP(w, r)
+		case FinancialServiceGetIncomeProcedure:
+			financialServiceGetIncomeHandler.ServeHTTP(w, r)
+		case FinancialServiceListIncomeSourcesProcedure:
+			financialServiceListIncomeSourcesHandler.ServeHTTP(w, r)
+		case FinancialServiceAddIncomeSourceProcedure:
+			financialServiceAddIncomeSourceHandler.ServeHTTP(w, r)
+		case FinancialServiceUpdateIncomeSourceProcedure:
+			financialServiceUpdateIncomeSourceHandler.ServeHTTP(w, r)
+		case FinancialServiceDeleteIncomeSourceProcedure:
+			financialServiceDeleteIncomeSourceHandler.ServeHTTP(w, r)
+		case FinancialServiceSetBudgetProcedure:
+			financialServiceSetBudgetHandler.ServeHTTP(w, r)
+		case FinancialServiceGetBudgetProcedure:
+			financialServiceGetBudgetHandler.ServeHTTP(w, r)
+		case FinancialServiceListBudgetSourcesProcedure:
+			financialServiceListBudgetSourcesHandler.ServeHTTP(w, r)
+		case FinancialServiceAddBudgetSourceProcedure:
+			financialServiceAddBudgetSourceHandler.ServeHTTP(w, r)
+		case FinancialServiceUpdateBudgetSourceProcedure:
+			financialServiceUpdateBudgetSourceHandler.ServeHTTP(w, r)
+		case FinancialServiceDeleteBudgetSourceProcedure:
+			financialServiceDeleteBudgetSourceHandler.ServeHTTP(w, r)
+		case FinancialServiceAddExpenseProcedure:
+			financialServiceAddExpenseHandler.ServeHTTP(w, r)
+		case FinancialServiceDeleteExpenseProcedure:
+			financialServiceDeleteExpenseHandler.ServeHTTP(w, r)
+		case FinancialServiceListExpensesProcedure:
+			financialServiceListExpensesHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedFinancialServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedFinancialServiceHandler struct{}
+
+func (UnimplementedFinancialServiceHandler) GetMonthlySummary(context.Context, *connect.Request[v1.GetMonthlySummaryRequest]) (*connect.Response[v1.GetMonthlySummaryResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("budget.v1.FinancialService.GetMonthlySummary is not implemented"))
+}
+
+func (UnimplementedFinancialServiceHandler) GetMonthlyData(context.Context, *connect.Request[v1.GetMonthlyDataRequest]) (*connect.Response[v1.GetMonthlyDataResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("budget.v1.FinancialService.GetMonthlyData is not implemented"))
+}
+
+func (UnimplementedFinancialServiceHandler) SetIncome(context.Context, *connect.Request[v1.SetIncomeRequest]) (*connect.Response[v1.SetIncomeResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("budget.v1.FinancialService.SetIncome is not implemented"))
+}
+
+func (UnimplementedFinancialServiceHandler) GetIncome(context.Context, *connect.Request[v1.GetIncomeRequest]) (*connect.Response[v1.GetIncomeResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("budget.v1.FinancialService.GetIncome is not implemented"))
+}
+
+func (UnimplementedFinancialServiceHandler) ListIncomeSources(context.Context, *connect.Request[v1.ListIncomeSourcesRequest]) (*connect.Response[v1.ListIncomeSourcesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("budget.v1.FinancialService.ListIncomeSources is not implemented"))
+}
+
+func (UnimplementedFinancialServiceHandler) AddIncomeSource(context.Context, *connect.Request[v1.AddIncomeSourceRequest]) (*connect.Response[v1.AddIncomeSourceResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("budget.v1.FinancialService.AddIncomeSource is not implemented"))
+}
+
+func (UnimplementedFinancialServiceHandler) UpdateIncomeSource(context.Context, *connect.Request[v1.UpdateIncomeSourceRequest]) (*connect.Response[v1.UpdateIncomeSourceResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("budget.v1.FinancialService.UpdateIncomeSource is not implemented"))
+}
+
+func (UnimplementedFinancialServiceHandler) DeleteIncomeSource(context.Context, *connect.Request[v1.DeleteIncomeSourceRequest]) (*connect.Response[v1.DeleteIncomeSourceResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("budget.v1.FinancialService.DeleteIncomeSource is not implemented"))
+}
+
+func (UnimplementedFinancialServiceHandler) SetBudget(context.Context, *connect.Request[v1.SetBudgetRequest]) (*connect.Response[v1.SetBudgetResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("budget.v1.FinancialService.SetBudget is not implemented"))
+}
+
+func (UnimplementedFinancialServiceHandler) GetBudget(context.Context, *connect.Request[v1.GetBudgetRequest]) (*connect.Response[v1.GetBudgetResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("budget.v1.FinancialService.GetBudget is not implemented"))
+}
+
+func (UnimplementedFinancialServiceHandler) ListBudgetSources(context.Context, *connect.Request[v1.ListBudgetSourcesRequest]) (*connect.Response[v1.ListBudgetSourcesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("budget.v1.FinancialService.ListBudgetSources is not implemented"))
+}
+
+func (UnimplementedFinancialServiceHandler) AddBudgetSource(context.Context, *connect.Request[v1.AddBudgetSourceRequest]) (*connect.Response[v1.AddBudgetSourceResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("budget.v1.FinancialService.AddBudgetSource is not implemented"))
+}
+
+func (UnimplementedFinancialServiceHandler) UpdateBudgetSource(context.Context, *connect.Request[v1.UpdateBudgetSourceRequest]) (*connect.Response[v1.UpdateBudgetSourceResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("budget.v1.FinancialService.UpdateBudgetSource is not implemented"))
+}
+
+func (UnimplementedFinancialServiceHandler) DeleteBudgetSource(context.Context, *connect.Request[v1.DeleteBudgetSourceRequest]) (*connect.Response[v1.DeleteBudgetSourceResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("budget.v1.FinancialService.DeleteBudgetSource is not implemented"))
+}
+
+func (UnimplementedFinancialServiceHandler) AddExpense(context.Context, *connect.Request[v1.AddExpenseRequest]) (*connect.Response[v1.AddExpenseResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("budget.v1.FinancialService.AddExpense is not implemented"))
+}
+
+func (UnimplementedFinancialServiceHandler) DeleteExpense(context.Context, *connect.Request[v1.DeleteExpenseRequest]) (*connect.Response[v1.DeleteExpenseResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("budget.v1.FinancialService.DeleteExpense is not implemented"))
+}
+
+func (UnimplementedFinancialServiceHandler) ListExpenses(context.Context, *connect.Request[v1.ListExpensesRequest]) (*connect.Response[v1.ListExpensesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("budget.v1.FinancialService.ListExpenses is not implemented"))
+}
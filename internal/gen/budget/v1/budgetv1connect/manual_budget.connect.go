@@ -0,0 +1,144 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: budget/v1/manual_budget.proto
+
+package budgetv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/mdco1990/webapp/internal/gen/budget/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// ManualBudgetServiceName is the fully-qualified name of the ManualBudgetService service.
+	ManualBudgetServiceName = "budget.v1.ManualBudgetService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// ManualBudgetServiceGetManualBudgetProcedure is the fully-qualified name of the
+	// ManualBudgetService's GetManualBudget RPC.
+	ManualBudgetServiceGetManualBudgetProcedure = "/budget.v1.ManualBudgetService/GetManualBudget"
+	// ManualBudgetServiceSaveManualBudgetProcedure is the fully-qualified name of the
+	// ManualBudgetService's SaveManualBudget RPC.
+	ManualBudgetServiceSaveManualBudgetProcedure = "/budget.v1.ManualBudgetService/SaveManualBudget"
+)
+
+// ManualBudgetServiceClient is a client for the budget.v1.ManualBudgetService service.
+type ManualBudgetServiceClient interface {
+	GetManualBudget(context.Context, *connect.Request[v1.GetManualBudgetRequest]) (*connect.Response[v1.GetManualBudgetResponse], error)
+	// SaveManualBudget replaces the month's bank amount and items. A non-zero
+	// version makes it conditional on the budget being unchanged since.
+	SaveManualBudget(context.Context, *connect.Request[v1.SaveManualBudgetRequest]) (*connect.Response[v1.SaveManualBudgetResponse], error)
+}
+
+// NewManualBudgetServiceClient constructs a client for the budget.v1.ManualBudgetService service.
+// By default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped
+// responses, and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewManualBudgetServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) ManualBudgetServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	manualBudgetServiceMethods := v1.File_budget_v1_manual_budget_proto.Services().ByName("ManualBudgetService").Methods()
+	return &manualBudgetServiceClient{
+		getManualBudget: connect.NewClient[v1.GetManualBudgetRequest, v1.GetManualBudgetResponse](
+			httpClient,
+			baseURL+ManualBudgetServiceGetManualBudgetProcedure,
+			connect.WithSchema(manualBudgetServiceMethods.ByName("GetManualBudget")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		saveManualBudget: connect.NewClient[v1.SaveManualBudgetRequest, v1.SaveManualBudgetResponse](
+			httpClient,
+			baseURL+ManualBudgetServiceSaveManualBudgetProcedure,
+			connect.WithSchema(manualBudgetServiceMethods.ByName("SaveManualBudget")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// manualBudgetServiceClient implements ManualBudgetServiceClient.
+type manualBudgetServiceClient struct {
+	getManualBudget  *connect.Client[v1.GetManualBudgetRequest, v1.GetManualBudgetResponse]
+	saveManualBudget *connect.Client[v1.SaveManualBudgetRequest, v1.SaveManualBudgetResponse]
+}
+
+// GetManualBudget calls budget.v1.ManualBudgetService.GetManualBudget.
+func (c *manualBudgetServiceClient) GetManualBudget(ctx context.Context, req *connect.Request[v1.GetManualBudgetRequest]) (*connect.Response[v1.GetManualBudgetResponse], error) {
+	return c.getManualBudget.CallUnary(ctx, req)
+}
+
+// SaveManualBudget calls budget.v1.ManualBudgetService.SaveManualBudget.
+func (c *manualBudgetServiceClient) SaveManualBudget(ctx context.Context, req *connect.Request[v1.SaveManualBudgetRequest]) (*connect.Response[v1.SaveManualBudgetResponse], error) {
+	return c.saveManualBudget.CallUnary(ctx, req)
+}
+
+// ManualBudgetServiceHandler is an implementation of the budget.v1.ManualBudgetService service.
+type ManualBudgetServiceHandler interface {
+	GetManualBudget(context.Context, *connect.Request[v1.GetManualBudgetRequest]) (*connect.Response[v1.GetManualBudgetResponse], error)
+	// SaveManualBudget replaces the month's bank amount and items. A non-zero
+	// version makes it conditional on the budget being unchanged since.
+	SaveManualBudget(context.Context, *connect.Request[v1.SaveManualBudgetRequest]) (*connect.Response[v1.SaveManualBudgetResponse], error)
+}
+
+// NewManualBudgetServiceHandler builds an HTTP handler from the service implementation. It returns
+// the path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewManualBudgetServiceHandler(svc ManualBudgetServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	manualBudgetServiceMethods := v1.File_budget_v1_manual_budget_proto.Services().ByName("ManualBudgetService").Methods()
+	manualBudgetServiceGetManualBudgetHandler := connect.NewUnaryHandler(
+		ManualBudgetServiceGetManualBudgetProcedure,
+		svc.GetManualBudget,
+		connect.WithSchema(manualBudgetServiceMethods.ByName("GetManualBudget")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	manualBudgetServiceSaveManualBudgetHandler := connect.NewUnaryHandler(
+		ManualBudgetServiceSaveManualBudgetProcedure,
+		svc.SaveManualBudget,
+		connect.WithSchema(manualBudgetServiceMethods.ByName("SaveManualBudget")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/budget.v1.ManualBudgetService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case ManualBudgetServiceGetManualBudgetProcedure:
+			manualBudgetServiceGetManualBudgetHandler.ServeHTTP(w, r)
+		case ManualBudgetServiceSaveManualBudgetProcedure:
+			manualBudgetServiceSaveManualBudgetHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedManualBudgetServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedManualBudgetServiceHandler struct{}
+
+func (UnimplementedManualBudgetServiceHandler) GetManualBudget(context.Context, *connect.Request[v1.GetManualBudgetRequest]) (*connect.Response[v1.GetManualBudgetResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("budget.v1.ManualBudgetService.GetManualBudget is not implemented"))
+}
+
+func (UnimplementedManualBudgetServiceHandler) SaveManualBudget(context.Context, *connect.Request[v1.SaveManualBudgetRequest]) (*connect.Response[v1.SaveManualBudgetResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("budget.v1.ManualBudgetService.SaveManualBudget is not implemented"))
+}
@@ -0,0 +1,2034 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: budget/v1/financial.proto
+
+package budgetv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetMonthlySummaryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	YearMonth     *YearMonth             `protobuf:"bytes,1,opt,name=year_month,json=yearMonth,proto3" json:"year_month,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMonthlySummaryRequest) Reset() {
+	*x = GetMonthlySummaryRequest{}
+	mi := &file_budget_v1_financial_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMonthlySummaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMonthlySummaryRequest) ProtoMessage() {}
+
+func (x *GetMonthlySummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMonthlySummaryRequest.ProtoReflect.Descriptor instead.
+func (*GetMonthlySummaryRequest) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetMonthlySummaryRequest) GetYearMonth() *YearMonth {
+	if x != nil {
+		return x.YearMonth
+	}
+	return nil
+}
+
+type GetMonthlySummaryResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	YearMonth      *YearMonth             `protobuf:"bytes,1,opt,name=year_month,json=yearMonth,proto3" json:"year_month,omitempty"`
+	SalaryCents    int64                  `protobuf:"varint,2,opt,name=salary_cents,json=salaryCents,proto3" json:"salary_cents,omitempty"`
+	BudgetCents    int64                  `protobuf:"varint,3,opt,name=budget_cents,json=budgetCents,proto3" json:"budget_cents,omitempty"`
+	ExpenseCents   int64                  `protobuf:"varint,4,opt,name=expense_cents,json=expenseCents,proto3" json:"expense_cents,omitempty"`
+	RemainingCents int64                  `protobuf:"varint,5,opt,name=remaining_cents,json=remainingCents,proto3" json:"remaining_cents,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetMonthlySummaryResponse) Reset() {
+	*x = GetMonthlySummaryResponse{}
+	mi := &file_budget_v1_financial_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMonthlySummaryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMonthlySummaryResponse) ProtoMessage() {}
+
+func (x *GetMonthlySummaryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMonthlySummaryResponse.ProtoReflect.Descriptor instead.
+func (*GetMonthlySummaryResponse) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetMonthlySummaryResponse) GetYearMonth() *YearMonth {
+	if x != nil {
+		return x.YearMonth
+	}
+	return nil
+}
+
+func (x *GetMonthlySummaryResponse) GetSalaryCents() int64 {
+	if x != nil {
+		return x.SalaryCents
+	}
+	return 0
+}
+
+func (x *GetMonthlySummaryResponse) GetBudgetCents() int64 {
+	if x != nil {
+		return x.BudgetCents
+	}
+	return 0
+}
+
+func (x *GetMonthlySummaryResponse) GetExpenseCents() int64 {
+	if x != nil {
+		return x.ExpenseCents
+	}
+	return 0
+}
+
+func (x *GetMonthlySummaryResponse) GetRemainingCents() int64 {
+	if x != nil {
+		return x.RemainingCents
+	}
+	return 0
+}
+
+type GetMonthlyDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	YearMonth     *YearMonth             `protobuf:"bytes,1,opt,name=year_month,json=yearMonth,proto3" json:"year_month,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMonthlyDataRequest) Reset() {
+	*x = GetMonthlyDataRequest{}
+	mi := &file_budget_v1_financial_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMonthlyDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMonthlyDataRequest) ProtoMessage() {}
+
+func (x *GetMonthlyDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMonthlyDataRequest.ProtoReflect.Descriptor instead.
+func (*GetMonthlyDataRequest) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetMonthlyDataRequest) GetYearMonth() *YearMonth {
+	if x != nil {
+		return x.YearMonth
+	}
+	return nil
+}
+
+type GetMonthlyDataResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	YearMonth          *YearMonth             `protobuf:"bytes,1,opt,name=year_month,json=yearMonth,proto3" json:"year_month,omitempty"`
+	MonthName          string                 `protobuf:"bytes,2,opt,name=month_name,json=monthName,proto3" json:"month_name,omitempty"`
+	IncomeSources      []*IncomeSource        `protobuf:"bytes,3,rep,name=income_sources,json=incomeSources,proto3" json:"income_sources,omitempty"`
+	BudgetSources      []*BudgetSource        `protobuf:"bytes,4,rep,name=budget_sources,json=budgetSources,proto3" json:"budget_sources,omitempty"`
+	Expenses           []*Expense             `protobuf:"bytes,5,rep,name=expenses,proto3" json:"expenses,omitempty"`
+	TotalIncomeCents   int64                  `protobuf:"varint,6,opt,name=total_income_cents,json=totalIncomeCents,proto3" json:"total_income_cents,omitempty"`
+	TotalBudgetCents   int64                  `protobuf:"varint,7,opt,name=total_budget_cents,json=totalBudgetCents,proto3" json:"total_budget_cents,omitempty"`
+	TotalExpensesCents int64                  `protobuf:"varint,8,opt,name=total_expenses_cents,json=totalExpensesCents,proto3" json:"total_expenses_cents,omitempty"`
+	RemainingCents     int64                  `protobuf:"varint,9,opt,name=remaining_cents,json=remainingCents,proto3" json:"remaining_cents,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *GetMonthlyDataResponse) Reset() {
+	*x = GetMonthlyDataResponse{}
+	mi := &file_budget_v1_financial_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMonthlyDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMonthlyDataResponse) ProtoMessage() {}
+
+func (x *GetMonthlyDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMonthlyDataResponse.ProtoReflect.Descriptor instead.
+func (*GetMonthlyDataResponse) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetMonthlyDataResponse) GetYearMonth() *YearMonth {
+	if x != nil {
+		return x.YearMonth
+	}
+	return nil
+}
+
+func (x *GetMonthlyDataResponse) GetMonthName() string {
+	if x != nil {
+		return x.MonthName
+	}
+	return ""
+}
+
+func (x *GetMonthlyDataResponse) GetIncomeSources() []*IncomeSource {
+	if x != nil {
+		return x.IncomeSources
+	}
+	return nil
+}
+
+func (x *GetMonthlyDataResponse) GetBudgetSources() []*BudgetSource {
+	if x != nil {
+		return x.BudgetSources
+	}
+	return nil
+}
+
+func (x *GetMonthlyDataResponse) GetExpenses() []*Expense {
+	if x != nil {
+		return x.Expenses
+	}
+	return nil
+}
+
+func (x *GetMonthlyDataResponse) GetTotalIncomeCents() int64 {
+	if x != nil {
+		return x.TotalIncomeCents
+	}
+	return 0
+}
+
+func (x *GetMonthlyDataResponse) GetTotalBudgetCents() int64 {
+	if x != nil {
+		return x.TotalBudgetCents
+	}
+	return 0
+}
+
+func (x *GetMonthlyDataResponse) GetTotalExpensesCents() int64 {
+	if x != nil {
+		return x.TotalExpensesCents
+	}
+	return 0
+}
+
+func (x *GetMonthlyDataResponse) GetRemainingCents() int64 {
+	if x != nil {
+		return x.RemainingCents
+	}
+	return 0
+}
+
+type SetIncomeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	YearMonth     *YearMonth             `protobuf:"bytes,1,opt,name=year_month,json=yearMonth,proto3" json:"year_month,omitempty"`
+	AmountCents   int64                  `protobuf:"varint,2,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetIncomeRequest) Reset() {
+	*x = SetIncomeRequest{}
+	mi := &file_budget_v1_financial_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetIncomeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetIncomeRequest) ProtoMessage() {}
+
+func (x *SetIncomeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetIncomeRequest.ProtoReflect.Descriptor instead.
+func (*SetIncomeRequest) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SetIncomeRequest) GetYearMonth() *YearMonth {
+	if x != nil {
+		return x.YearMonth
+	}
+	return nil
+}
+
+func (x *SetIncomeRequest) GetAmountCents() int64 {
+	if x != nil {
+		return x.AmountCents
+	}
+	return 0
+}
+
+type SetIncomeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetIncomeResponse) Reset() {
+	*x = SetIncomeResponse{}
+	mi := &file_budget_v1_financial_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetIncomeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetIncomeResponse) ProtoMessage() {}
+
+func (x *SetIncomeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetIncomeResponse.ProtoReflect.Descriptor instead.
+func (*SetIncomeResponse) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{5}
+}
+
+type GetIncomeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	YearMonth     *YearMonth             `protobuf:"bytes,1,opt,name=year_month,json=yearMonth,proto3" json:"year_month,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetIncomeRequest) Reset() {
+	*x = GetIncomeRequest{}
+	mi := &file_budget_v1_financial_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIncomeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIncomeRequest) ProtoMessage() {}
+
+func (x *GetIncomeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIncomeRequest.ProtoReflect.Descriptor instead.
+func (*GetIncomeRequest) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetIncomeRequest) GetYearMonth() *YearMonth {
+	if x != nil {
+		return x.YearMonth
+	}
+	return nil
+}
+
+type GetIncomeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AmountCents   int64                  `protobuf:"varint,1,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetIncomeResponse) Reset() {
+	*x = GetIncomeResponse{}
+	mi := &file_budget_v1_financial_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIncomeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIncomeResponse) ProtoMessage() {}
+
+func (x *GetIncomeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIncomeResponse.ProtoReflect.Descriptor instead.
+func (*GetIncomeResponse) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetIncomeResponse) GetAmountCents() int64 {
+	if x != nil {
+		return x.AmountCents
+	}
+	return 0
+}
+
+type ListIncomeSourcesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	YearMonth     *YearMonth             `protobuf:"bytes,1,opt,name=year_month,json=yearMonth,proto3" json:"year_month,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListIncomeSourcesRequest) Reset() {
+	*x = ListIncomeSourcesRequest{}
+	mi := &file_budget_v1_financial_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListIncomeSourcesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIncomeSourcesRequest) ProtoMessage() {}
+
+func (x *ListIncomeSourcesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIncomeSourcesRequest.ProtoReflect.Descriptor instead.
+func (*ListIncomeSourcesRequest) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListIncomeSourcesRequest) GetYearMonth() *YearMonth {
+	if x != nil {
+		return x.YearMonth
+	}
+	return nil
+}
+
+type ListIncomeSourcesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sources       []*IncomeSource        `protobuf:"bytes,1,rep,name=sources,proto3" json:"sources,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListIncomeSourcesResponse) Reset() {
+	*x = ListIncomeSourcesResponse{}
+	mi := &file_budget_v1_financial_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListIncomeSourcesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIncomeSourcesResponse) ProtoMessage() {}
+
+func (x *ListIncomeSourcesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIncomeSourcesResponse.ProtoReflect.Descriptor instead.
+func (*ListIncomeSourcesResponse) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListIncomeSourcesResponse) GetSources() []*IncomeSource {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+type AddIncomeSourceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	YearMonth     *YearMonth             `protobuf:"bytes,2,opt,name=year_month,json=yearMonth,proto3" json:"year_month,omitempty"`
+	AmountCents   int64                  `protobuf:"varint,3,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
+	DayOfMonth    *int32                 `protobuf:"varint,4,opt,name=day_of_month,json=dayOfMonth,proto3,oneof" json:"day_of_month,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddIncomeSourceRequest) Reset() {
+	*x = AddIncomeSourceRequest{}
+	mi := &file_budget_v1_financial_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddIncomeSourceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddIncomeSourceRequest) ProtoMessage() {}
+
+func (x *AddIncomeSourceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddIncomeSourceRequest.ProtoReflect.Descriptor instead.
+func (*AddIncomeSourceRequest) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AddIncomeSourceRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AddIncomeSourceRequest) GetYearMonth() *YearMonth {
+	if x != nil {
+		return x.YearMonth
+	}
+	return nil
+}
+
+func (x *AddIncomeSourceRequest) GetAmountCents() int64 {
+	if x != nil {
+		return x.AmountCents
+	}
+	return 0
+}
+
+func (x *AddIncomeSourceRequest) GetDayOfMonth() int32 {
+	if x != nil && x.DayOfMonth != nil {
+		return *x.DayOfMonth
+	}
+	return 0
+}
+
+type AddIncomeSourceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        *IncomeSource          `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddIncomeSourceResponse) Reset() {
+	*x = AddIncomeSourceResponse{}
+	mi := &file_budget_v1_financial_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddIncomeSourceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddIncomeSourceResponse) ProtoMessage() {}
+
+func (x *AddIncomeSourceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddIncomeSourceResponse.ProtoReflect.Descriptor instead.
+func (*AddIncomeSourceResponse) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AddIncomeSourceResponse) GetSource() *IncomeSource {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+type UpdateIncomeSourceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version       int64                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	AmountCents   int64                  `protobuf:"varint,4,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
+	DayOfMonth    *int32                 `protobuf:"varint,5,opt,name=day_of_month,json=dayOfMonth,proto3,oneof" json:"day_of_month,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateIncomeSourceRequest) Reset() {
+	*x = UpdateIncomeSourceRequest{}
+	mi := &file_budget_v1_financial_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateIncomeSourceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateIncomeSourceRequest) ProtoMessage() {}
+
+func (x *UpdateIncomeSourceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateIncomeSourceRequest.ProtoReflect.Descriptor instead.
+func (*UpdateIncomeSourceRequest) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateIncomeSourceRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateIncomeSourceRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *UpdateIncomeSourceRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateIncomeSourceRequest) GetAmountCents() int64 {
+	if x != nil {
+		return x.AmountCents
+	}
+	return 0
+}
+
+func (x *UpdateIncomeSourceRequest) GetDayOfMonth() int32 {
+	if x != nil && x.DayOfMonth != nil {
+		return *x.DayOfMonth
+	}
+	return 0
+}
+
+type UpdateIncomeSourceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        *IncomeSource          `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateIncomeSourceResponse) Reset() {
+	*x = UpdateIncomeSourceResponse{}
+	mi := &file_budget_v1_financial_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateIncomeSourceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateIncomeSourceResponse) ProtoMessage() {}
+
+func (x *UpdateIncomeSourceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateIncomeSourceResponse.ProtoReflect.Descriptor instead.
+func (*UpdateIncomeSourceResponse) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UpdateIncomeSourceResponse) GetSource() *IncomeSource {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+type DeleteIncomeSourceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version       int64                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteIncomeSourceRequest) Reset() {
+	*x = DeleteIncomeSourceRequest{}
+	mi := &file_budget_v1_financial_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteIncomeSourceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteIncomeSourceRequest) ProtoMessage() {}
+
+func (x *DeleteIncomeSourceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteIncomeSourceRequest.ProtoReflect.Descriptor instead.
+func (*DeleteIncomeSourceRequest) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DeleteIncomeSourceRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeleteIncomeSourceRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type DeleteIncomeSourceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteIncomeSourceResponse) Reset() {
+	*x = DeleteIncomeSourceResponse{}
+	mi := &file_budget_v1_financial_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteIncomeSourceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteIncomeSourceResponse) ProtoMessage() {}
+
+func (x *DeleteIncomeSourceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteIncomeSourceResponse.ProtoReflect.Descriptor instead.
+func (*DeleteIncomeSourceResponse) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{15}
+}
+
+type SetBudgetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	YearMonth     *YearMonth             `protobuf:"bytes,1,opt,name=year_month,json=yearMonth,proto3" json:"year_month,omitempty"`
+	AmountCents   int64                  `protobuf:"varint,2,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetBudgetRequest) Reset() {
+	*x = SetBudgetRequest{}
+	mi := &file_budget_v1_financial_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetBudgetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetBudgetRequest) ProtoMessage() {}
+
+func (x *SetBudgetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetBudgetRequest.ProtoReflect.Descriptor instead.
+func (*SetBudgetRequest) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SetBudgetRequest) GetYearMonth() *YearMonth {
+	if x != nil {
+		return x.YearMonth
+	}
+	return nil
+}
+
+func (x *SetBudgetRequest) GetAmountCents() int64 {
+	if x != nil {
+		return x.AmountCents
+	}
+	return 0
+}
+
+type SetBudgetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetBudgetResponse) Reset() {
+	*x = SetBudgetResponse{}
+	mi := &file_budget_v1_financial_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetBudgetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetBudgetResponse) ProtoMessage() {}
+
+func (x *SetBudgetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetBudgetResponse.ProtoReflect.Descriptor instead.
+func (*SetBudgetResponse) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{17}
+}
+
+type GetBudgetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	YearMonth     *YearMonth             `protobuf:"bytes,1,opt,name=year_month,json=yearMonth,proto3" json:"year_month,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBudgetRequest) Reset() {
+	*x = GetBudgetRequest{}
+	mi := &file_budget_v1_financial_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBudgetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBudgetRequest) ProtoMessage() {}
+
+func (x *GetBudgetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBudgetRequest.ProtoReflect.Descriptor instead.
+func (*GetBudgetRequest) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetBudgetRequest) GetYearMonth() *YearMonth {
+	if x != nil {
+		return x.YearMonth
+	}
+	return nil
+}
+
+type GetBudgetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AmountCents   int64                  `protobuf:"varint,1,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBudgetResponse) Reset() {
+	*x = GetBudgetResponse{}
+	mi := &file_budget_v1_financial_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBudgetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBudgetResponse) ProtoMessage() {}
+
+func (x *GetBudgetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBudgetResponse.ProtoReflect.Descriptor instead.
+func (*GetBudgetResponse) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetBudgetResponse) GetAmountCents() int64 {
+	if x != nil {
+		return x.AmountCents
+	}
+	return 0
+}
+
+type ListBudgetSourcesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	YearMonth     *YearMonth             `protobuf:"bytes,1,opt,name=year_month,json=yearMonth,proto3" json:"year_month,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBudgetSourcesRequest) Reset() {
+	*x = ListBudgetSourcesRequest{}
+	mi := &file_budget_v1_financial_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBudgetSourcesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBudgetSourcesRequest) ProtoMessage() {}
+
+func (x *ListBudgetSourcesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBudgetSourcesRequest.ProtoReflect.Descriptor instead.
+func (*ListBudgetSourcesRequest) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListBudgetSourcesRequest) GetYearMonth() *YearMonth {
+	if x != nil {
+		return x.YearMonth
+	}
+	return nil
+}
+
+type ListBudgetSourcesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sources       []*BudgetSource        `protobuf:"bytes,1,rep,name=sources,proto3" json:"sources,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBudgetSourcesResponse) Reset() {
+	*x = ListBudgetSourcesResponse{}
+	mi := &file_budget_v1_financial_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBudgetSourcesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBudgetSourcesResponse) ProtoMessage() {}
+
+func (x *ListBudgetSourcesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBudgetSourcesResponse.ProtoReflect.Descriptor instead.
+func (*ListBudgetSourcesResponse) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListBudgetSourcesResponse) GetSources() []*BudgetSource {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+type AddBudgetSourceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	YearMonth     *YearMonth             `protobuf:"bytes,2,opt,name=year_month,json=yearMonth,proto3" json:"year_month,omitempty"`
+	AmountCents   int64                  `protobuf:"varint,3,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
+	DayOfMonth    *int32                 `protobuf:"varint,4,opt,name=day_of_month,json=dayOfMonth,proto3,oneof" json:"day_of_month,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddBudgetSourceRequest) Reset() {
+	*x = AddBudgetSourceRequest{}
+	mi := &file_budget_v1_financial_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddBudgetSourceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddBudgetSourceRequest) ProtoMessage() {}
+
+func (x *AddBudgetSourceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddBudgetSourceRequest.ProtoReflect.Descriptor instead.
+func (*AddBudgetSourceRequest) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *AddBudgetSourceRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AddBudgetSourceRequest) GetYearMonth() *YearMonth {
+	if x != nil {
+		return x.YearMonth
+	}
+	return nil
+}
+
+func (x *AddBudgetSourceRequest) GetAmountCents() int64 {
+	if x != nil {
+		return x.AmountCents
+	}
+	return 0
+}
+
+func (x *AddBudgetSourceRequest) GetDayOfMonth() int32 {
+	if x != nil && x.DayOfMonth != nil {
+		return *x.DayOfMonth
+	}
+	return 0
+}
+
+type AddBudgetSourceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        *BudgetSource          `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddBudgetSourceResponse) Reset() {
+	*x = AddBudgetSourceResponse{}
+	mi := &file_budget_v1_financial_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddBudgetSourceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddBudgetSourceResponse) ProtoMessage() {}
+
+func (x *AddBudgetSourceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddBudgetSourceResponse.ProtoReflect.Descriptor instead.
+func (*AddBudgetSourceResponse) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *AddBudgetSourceResponse) GetSource() *BudgetSource {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+type UpdateBudgetSourceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version       int64                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	AmountCents   int64                  `protobuf:"varint,4,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
+	DayOfMonth    *int32                 `protobuf:"varint,5,opt,name=day_of_month,json=dayOfMonth,proto3,oneof" json:"day_of_month,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateBudgetSourceRequest) Reset() {
+	*x = UpdateBudgetSourceRequest{}
+	mi := &file_budget_v1_financial_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateBudgetSourceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateBudgetSourceRequest) ProtoMessage() {}
+
+func (x *UpdateBudgetSourceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateBudgetSourceRequest.ProtoReflect.Descriptor instead.
+func (*UpdateBudgetSourceRequest) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *UpdateBudgetSourceRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateBudgetSourceRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *UpdateBudgetSourceRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateBudgetSourceRequest) GetAmountCents() int64 {
+	if x != nil {
+		return x.AmountCents
+	}
+	return 0
+}
+
+func (x *UpdateBudgetSourceRequest) GetDayOfMonth() int32 {
+	if x != nil && x.DayOfMonth != nil {
+		return *x.DayOfMonth
+	}
+	return 0
+}
+
+type UpdateBudgetSourceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        *BudgetSource          `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateBudgetSourceResponse) Reset() {
+	*x = UpdateBudgetSourceResponse{}
+	mi := &file_budget_v1_financial_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateBudgetSourceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateBudgetSourceResponse) ProtoMessage() {}
+
+func (x *UpdateBudgetSourceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateBudgetSourceResponse.ProtoReflect.Descriptor instead.
+func (*UpdateBudgetSourceResponse) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *UpdateBudgetSourceResponse) GetSource() *BudgetSource {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+type DeleteBudgetSourceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version       int64                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteBudgetSourceRequest) Reset() {
+	*x = DeleteBudgetSourceRequest{}
+	mi := &file_budget_v1_financial_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteBudgetSourceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBudgetSourceRequest) ProtoMessage() {}
+
+func (x *DeleteBudgetSourceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBudgetSourceRequest.ProtoReflect.Descriptor instead.
+func (*DeleteBudgetSourceRequest) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DeleteBudgetSourceRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeleteBudgetSourceRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type DeleteBudgetSourceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteBudgetSourceResponse) Reset() {
+	*x = DeleteBudgetSourceResponse{}
+	mi := &file_budget_v1_financial_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteBudgetSourceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBudgetSourceResponse) ProtoMessage() {}
+
+func (x *DeleteBudgetSourceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBudgetSourceResponse.ProtoReflect.Descriptor instead.
+func (*DeleteBudgetSourceResponse) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{27}
+}
+
+type AddExpenseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	YearMonth     *YearMonth             `protobuf:"bytes,1,opt,name=year_month,json=yearMonth,proto3" json:"year_month,omitempty"`
+	Category      string                 `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	AmountCents   int64                  `protobuf:"varint,4,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddExpenseRequest) Reset() {
+	*x = AddExpenseRequest{}
+	mi := &file_budget_v1_financial_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddExpenseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddExpenseRequest) ProtoMessage() {}
+
+func (x *AddExpenseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddExpenseRequest.ProtoReflect.Descriptor instead.
+func (*AddExpenseRequest) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *AddExpenseRequest) GetYearMonth() *YearMonth {
+	if x != nil {
+		return x.YearMonth
+	}
+	return nil
+}
+
+func (x *AddExpenseRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *AddExpenseRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *AddExpenseRequest) GetAmountCents() int64 {
+	if x != nil {
+		return x.AmountCents
+	}
+	return 0
+}
+
+type AddExpenseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddExpenseResponse) Reset() {
+	*x = AddExpenseResponse{}
+	mi := &file_budget_v1_financial_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddExpenseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddExpenseResponse) ProtoMessage() {}
+
+func (x *AddExpenseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddExpenseResponse.ProtoReflect.Descriptor instead.
+func (*AddExpenseResponse) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *AddExpenseResponse) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteExpenseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteExpenseRequest) Reset() {
+	*x = DeleteExpenseRequest{}
+	mi := &file_budget_v1_financial_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteExpenseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteExpenseRequest) ProtoMessage() {}
+
+func (x *DeleteExpenseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteExpenseRequest.ProtoReflect.Descriptor instead.
+func (*DeleteExpenseRequest) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *DeleteExpenseRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteExpenseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteExpenseResponse) Reset() {
+	*x = DeleteExpenseResponse{}
+	mi := &file_budget_v1_financial_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteExpenseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteExpenseResponse) ProtoMessage() {}
+
+func (x *DeleteExpenseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteExpenseResponse.ProtoReflect.Descriptor instead.
+func (*DeleteExpenseResponse) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{31}
+}
+
+type ListExpensesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	YearMonth     *YearMonth             `protobuf:"bytes,1,opt,name=year_month,json=yearMonth,proto3" json:"year_month,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListExpensesRequest) Reset() {
+	*x = ListExpensesRequest{}
+	mi := &file_budget_v1_financial_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListExpensesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListExpensesRequest) ProtoMessage() {}
+
+func (x *ListExpensesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListExpensesRequest.ProtoReflect.Descriptor instead.
+func (*ListExpensesRequest) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ListExpensesRequest) GetYearMonth() *YearMonth {
+	if x != nil {
+		return x.YearMonth
+	}
+	return nil
+}
+
+type ListExpensesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Expenses      []*Expense             `protobuf:"bytes,1,rep,name=expenses,proto3" json:"expenses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListExpensesResponse) Reset() {
+	*x = ListExpensesResponse{}
+	mi := &file_budget_v1_financial_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListExpensesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListExpensesResponse) ProtoMessage() {}
+
+func (x *ListExpensesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_financial_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListExpensesResponse.ProtoReflect.Descriptor instead.
+func (*ListExpensesResponse) Descriptor() ([]byte, []int) {
+	return file_budget_v1_financial_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ListExpensesResponse) GetExpenses() []*Expense {
+	if x != nil {
+		return x.Expenses
+	}
+	return nil
+}
+
+var File_budget_v1_financial_proto protoreflect.FileDescriptor
+
+const file_budget_v1_financial_proto_rawDesc = "" +
+	"\n" +
+	"\x19budget/v1/financial.proto\x12\tbudget.v1\x1a\x15budget/v1/types.proto\"O\n" +
+	"\x18GetMonthlySummaryRequest\x123\n" +
+	"\n" +
+	"year_month\x18\x01 \x01(\v2\x14.budget.v1.YearMonthR\tyearMonth\"\xe4\x01\n" +
+	"\x19GetMonthlySummaryResponse\x123\n" +
+	"\n" +
+	"year_month\x18\x01 \x01(\v2\x14.budget.v1.YearMonthR\tyearMonth\x12!\n" +
+	"\fsalary_cents\x18\x02 \x01(\x03R\vsalaryCents\x12!\n" +
+	"\fbudget_cents\x18\x03 \x01(\x03R\vbudgetCents\x12#\n" +
+	"\rexpense_cents\x18\x04 \x01(\x03R\fexpenseCents\x12'\n" +
+	"\x0fremaining_cents\x18\x05 \x01(\x03R\x0eremainingCents\"L\n" +
+	"\x15GetMonthlyDataRequest\x123\n" +
+	"\n" +
+	"year_month\x18\x01 \x01(\v2\x14.budget.v1.YearMonthR\tyearMonth\"\xd3\x03\n" +
+	"\x16GetMonthlyDataResponse\x123\n" +
+	"\n" +
+	"year_month\x18\x01 \x01(\v2\x14.budget.v1.YearMonthR\tyearMonth\x12\x1d\n" +
+	"\n" +
+	"month_name\x18\x02 \x01(\tR\tmonthName\x12>\n" +
+	"\x0eincome_sources\x18\x03 \x03(\v2\x17.budget.v1.IncomeSourceR\rincomeSources\x12>\n" +
+	"\x0ebudget_sources\x18\x04 \x03(\v2\x17.budget.v1.BudgetSourceR\rbudgetSources\x12.\n" +
+	"\bexpenses\x18\x05 \x03(\v2\x12.budget.v1.ExpenseR\bexpenses\x12,\n" +
+	"\x12total_income_cents\x18\x06 \x01(\x03R\x10totalIncomeCents\x12,\n" +
+	"\x12total_budget_cents\x18\a \x01(\x03R\x10totalBudgetCents\x120\n" +
+	"\x14total_expenses_cents\x18\b \x01(\x03R\x12totalExpensesCents\x12'\n" +
+	"\x0fremaining_cents\x18\t \x01(\x03R\x0eremainingCents\"j\n" +
+	"\x10SetIncomeRequest\x123\n" +
+	"\n" +
+	"year_month\x18\x01 \x01(\v2\x14.budget.v1.YearMonthR\tyearMonth\x12!\n" +
+	"\famount_cents\x18\x02 \x01(\x03R\vamountCents\"\x13\n" +
+	"\x11SetIncomeResponse\"G\n" +
+	"\x10GetIncomeRequest\x123\n" +
+	"\n" +
+	"year_month\x18\x01 \x01(\v2\x14.budget.v1.YearMonthR\tyearMonth\"6\n" +
+	"\x11GetIncomeResponse\x12!\n" +
+	"\famount_cents\x18\x01 \x01(\x03R\vamountCents\"O\n" +
+	"\x18ListIncomeSourcesRequest\x123\n" +
+	"\n" +
+	"year_month\x18\x01 \x01(\v2\x14.budget.v1.YearMonthR\tyearMonth\"N\n" +
+	"\x19ListIncomeSourcesResponse\x121\n" +
+	"\asources\x18\x01 \x03(\v2\x17.budget.v1.IncomeSourceR\asources\"\xbc\x01\n" +
+	"\x16AddIncomeSourceRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x123\n" +
+	"\n" +
+	"year_month\x18\x02 \x01(\v2\x14.budget.v1.YearMonthR\tyearMonth\x12!\n" +
+	"\famount_cents\x18\x03 \x01(\x03R\vamountCents\x12%\n" +
+	"\fday_of_month\x18\x04 \x01(\x05H\x00R\n" +
+	"dayOfMonth\x88\x01\x01B\x0f\n" +
+	"\r_day_of_month\"J\n" +
+	"\x17AddIncomeSourceResponse\x12/\n" +
+	"\x06source\x18\x01 \x01(\v2\x17.budget.v1.IncomeSourceR\x06source\"\xb4\x01\n" +
+	"\x19UpdateIncomeSourceRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12!\n" +
+	"\famount_cents\x18\x04 \x01(\x03R\vamountCents\x12%\n" +
+	"\fday_of_month\x18\x05 \x01(\x05H\x00R\n" +
+	"dayOfMonth\x88\x01\x01B\x0f\n" +
+	"\r_day_of_month\"M\n" +
+	"\x1aUpdateIncomeSourceResponse\x12/\n" +
+	"\x06source\x18\x01 \x01(\v2\x17.budget.v1.IncomeSourceR\x06source\"E\n" +
+	"\x19DeleteIncomeSourceRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\"\x1c\n" +
+	"\x1aDeleteIncomeSourceResponse\"j\n" +
+	"\x10SetBudgetRequest\x123\n" +
+	"\n" +
+	"year_month\x18\x01 \x01(\v2\x14.budget.v1.YearMonthR\tyearMonth\x12!\n" +
+	"\famount_cents\x18\x02 \x01(\x03R\vamountCents\"\x13\n" +
+	"\x11SetBudgetResponse\"G\n" +
+	"\x10GetBudgetRequest\x123\n" +
+	"\n" +
+	"year_month\x18\x01 \x01(\v2\x14.budget.v1.YearMonthR\tyearMonth\"6\n" +
+	"\x11GetBudgetResponse\x12!\n" +
+	"\famount_cents\x18\x01 \x01(\x03R\vamountCents\"O\n" +
+	"\x18ListBudgetSourcesRequest\x123\n" +
+	"\n" +
+	"year_month\x18\x01 \x01(\v2\x14.budget.v1.YearMonthR\tyearMonth\"N\n" +
+	"\x19ListBudgetSourcesResponse\x121\n" +
+	"\asources\x18\x01 \x03(\v2\x17.budget.v1.BudgetSourceR\asources\"\xbc\x01\n" +
+	"\x16AddBudgetSourceRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x123\n" +
+	"\n" +
+	"year_month\x18\x02 \x01(\v2\x14.budget.v1.YearMonthR\tyearMonth\x12!\n" +
+	"\famount_cents\x18\x03 \x01(\x03R\vamountCents\x12%\n" +
+	"\fday_of_month\x18\x04 \x01(\x05H\x00R\n" +
+	"dayOfMonth\x88\x01\x01B\x0f\n" +
+	"\r_day_of_month\"J\n" +
+	"\x17AddBudgetSourceResponse\x12/\n" +
+	"\x06source\x18\x01 \x01(\v2\x17.budget.v1.BudgetSourceR\x06source\"\xb4\x01\n" +
+	"\x19UpdateBudgetSourceRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12!\n" +
+	"\famount_cents\x18\x04 \x01(\x03R\vamountCents\x12%\n" +
+	"\fday_of_month\x18\x05 \x01(\x05H\x00R\n" +
+	"dayOfMonth\x88\x01\x01B\x0f\n" +
+	"\r_day_of_month\"M\n" +
+	"\x1aUpdateBudgetSourceResponse\x12/\n" +
+	"\x06source\x18\x01 \x01(\v2\x17.budget.v1.BudgetSourceR\x06source\"E\n" +
+	"\x19DeleteBudgetSourceRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\"\x1c\n" +
+	"\x1aDeleteBudgetSourceResponse\"\xa9\x01\n" +
+	"\x11AddExpenseRequest\x123\n" +
+	"\n" +
+	"year_month\x18\x01 \x01(\v2\x14.budget.v1.YearMonthR\tyearMonth\x12\x1a\n" +
+	"\bcategory\x18\x02 \x01(\tR\bcategory\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12!\n" +
+	"\famount_cents\x18\x04 \x01(\x03R\vamountCents\"$\n" +
+	"\x12AddExpenseResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"&\n" +
+	"\x14DeleteExpenseRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x17\n" +
+	"\x15DeleteExpenseResponse\"J\n" +
+	"\x13ListExpensesRequest\x123\n" +
+	"\n" +
+	"year_month\x18\x01 \x01(\v2\x14.budget.v1.YearMonthR\tyearMonth\"F\n" +
+	"\x14ListExpensesResponse\x12.\n" +
+	"\bexpenses\x18\x01 \x03(\v2\x12.budget.v1.ExpenseR\bexpenses2\x86\f\n" +
+	"\x10FinancialService\x12c\n" +
+	"\x11GetMonthlySummary\x12#.budget.v1.GetMonthlySummaryRequest\x1a$.budget.v1.GetMonthlySummaryResponse\"\x03\x90\x02\x01\x12Z\n" +
+	"\x0eGetMonthlyData\x12 .budget.v1.GetMonthlyDataRequest\x1a!.budget.v1.GetMonthlyDataResponse\"\x03\x90\x02\x01\x12K\n" +
+	"\tSetIncome\x12\x1b.budget.v1.SetIncomeRequest\x1a\x1c.budget.v1.SetIncomeResponse\"\x03\x90\x02\x02\x12K\n" +
+	"\tGetIncome\x12\x1b.budget.v1.GetIncomeRequest\x1a\x1c.budget.v1.GetIncomeResponse\"\x03\x90\x02\x01\x12c\n" +
+	"\x11ListIncomeSources\x12#.budget.v1.ListIncomeSourcesRequest\x1a$.budget.v1.ListIncomeSourcesResponse\"\x03\x90\x02\x01\x12X\n" +
+	"\x0fAddIncomeSource\x12!.budget.v1.AddIncomeSourceRequest\x1a\".budget.v1.AddIncomeSourceResponse\x12a\n" +
+	"\x12UpdateIncomeSource\x12$.budget.v1.UpdateIncomeSourceRequest\x1a%.budget.v1.UpdateIncomeSourceResponse\x12a\n" +
+	"\x12DeleteIncomeSource\x12$.budget.v1.DeleteIncomeSourceRequest\x1a%.budget.v1.DeleteIncomeSourceResponse\x12K\n" +
+	"\tSetBudget\x12\x1b.budget.v1.SetBudgetRequest\x1a\x1c.budget.v1.SetBudgetResponse\"\x03\x90\x02\x02\x12K\n" +
+	"\tGetBudget\x12\x1b.budget.v1.GetBudgetRequest\x1a\x1c.budget.v1.GetBudgetResponse\"\x03\x90\x02\x01\x12c\n" +
+	"\x11ListBudgetSources\x12#.budget.v1.ListBudgetSourcesRequest\x1a$.budget.v1.ListBudgetSourcesResponse\"\x03\x90\x02\x01\x12X\n" +
+	"\x0fAddBudgetSource\x12!.budget.v1.AddBudgetSourceRequest\x1a\".budget.v1.AddBudgetSourceResponse\x12a\n" +
+	"\x12UpdateBudgetSource\x12$.budget.v1.UpdateBudgetSourceRequest\x1a%.budget.v1.UpdateBudgetSourceResponse\x12a\n" +
+	"\x12DeleteBudgetSource\x12$.budget.v1.DeleteBudgetSourceRequest\x1a%.budget.v1.DeleteBudgetSourceResponse\x12I\n" +
+	"\n" +
+	"AddExpense\x12\x1c.budget.v1.AddExpenseRequest\x1a\x1d.budget.v1.AddExpenseResponse\x12R\n" +
+	"\rDeleteExpense\x12\x1f.budget.v1.DeleteExpenseRequest\x1a .budget.v1.DeleteExpenseResponse\x12T\n" +
+	"\fListExpenses\x12\x1e.budget.v1.ListExpensesRequest\x1a\x1f.budget.v1.ListExpensesResponse\"\x03\x90\x02\x01B<Z:github.com/mdco1990/webapp/internal/gen/budget/v1;budgetv1b\x06proto3"
+
+var (
+	file_budget_v1_financial_proto_rawDescOnce sync.Once
+	file_budget_v1_financial_proto_rawDescData []byte
+)
+
+func file_budget_v1_financial_proto_rawDescGZIP() []byte {
+	file_budget_v1_financial_proto_rawDescOnce.Do(func() {
+		file_budget_v1_financial_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_budget_v1_financial_proto_rawDesc), len(file_budget_v1_financial_proto_rawDesc)))
+	})
+	return file_budget_v1_financial_proto_rawDescData
+}
+
+var file_budget_v1_financial_proto_msgTypes = make([]protoimpl.MessageInfo, 34)
+var file_budget_v1_financial_proto_goTypes = []any{
+	(*GetMonthlySummaryRequest)(nil),   // 0: budget.v1.GetMonthlySummaryRequest
+	(*GetMonthlySummaryResponse)(nil),  // 1: budget.v1.GetMonthlySummaryResponse
+	(*GetMonthlyDataRequest)(nil),      // 2: budget.v1.GetMonthlyDataRequest
+	(*GetMonthlyDataResponse)(nil),     // 3: budget.v1.GetMonthlyDataResponse
+	(*SetIncomeRequest)(nil),           // 4: budget.v1.SetIncomeRequest
+	(*SetIncomeResponse)(nil),          // 5: budget.v1.SetIncomeResponse
+	(*GetIncomeRequest)(nil),           // 6: budget.v1.GetIncomeRequest
+	(*GetIncomeResponse)(nil),          // 7: budget.v1.GetIncomeResponse
+	(*ListIncomeSourcesRequest)(nil),   // 8: budget.v1.ListIncomeSourcesRequest
+	(*ListIncomeSourcesResponse)(nil),  // 9: budget.v1.ListIncomeSourcesResponse
+	(*AddIncomeSourceRequest)(nil),     // 10: budget.v1.AddIncomeSourceRequest
+	(*AddIncomeSourceResponse)(nil),    // 11: budget.v1.AddIncomeSourceResponse
+	(*UpdateIncomeSourceRequest)(nil),  // 12: budget.v1.UpdateIncomeSourceRequest
+	(*UpdateIncomeSourceResponse)(nil), // 13: budget.v1.UpdateIncomeSourceResponse
+	(*DeleteIncomeSourceRequest)(nil),  // 14: budget.v1.DeleteIncomeSourceRequest
+	(*DeleteIncomeSourceResponse)(nil), // 15: budget.v1.DeleteIncomeSourceResponse
+	(*SetBudgetRequest)(nil),           // 16: budget.v1.SetBudgetRequest
+	(*SetBudgetResponse)(nil),          // 17: budget.v1.SetBudgetResponse
+	(*GetBudgetRequest)(nil),           // 18: budget.v1.GetBudgetRequest
+	(*GetBudgetResponse)(nil),          // 19: budget.v1.GetBudgetResponse
+	(*ListBudgetSourcesRequest)(nil),   // 20: budget.v1.ListBudgetSourcesRequest
+	(*ListBudgetSourcesResponse)(nil),  // 21: budget.v1.ListBudgetSourcesResponse
+	(*AddBudgetSourceRequest)(nil),     // 22: budget.v1.AddBudgetSourceRequest
+	(*AddBudgetSourceResponse)(nil),    // 23: budget.v1.AddBudgetSourceResponse
+	(*UpdateBudgetSourceRequest)(nil),  // 24: budget.v1.UpdateBudgetSourceRequest
+	(*UpdateBudgetSourceResponse)(nil), // 25: budget.v1.UpdateBudgetSourceResponse
+	(*DeleteBudgetSourceRequest)(nil),  // 26: budget.v1.DeleteBudgetSourceRequest
+	(*DeleteBudgetSourceResponse)(nil), // 27: budget.v1.DeleteBudgetSourceResponse
+	(*AddExpenseRequest)(nil),          // 28: budget.v1.AddExpenseRequest
+	(*AddExpenseResponse)(nil),         // 29: budget.v1.AddExpenseResponse
+	(*DeleteExpenseRequest)(nil),       // 30: budget.v1.DeleteExpenseRequest
+	(*DeleteExpenseResponse)(nil),      // 31: budget.v1.DeleteExpenseResponse
+	(*ListExpensesRequest)(nil),        // 32: budget.v1.ListExpensesRequest
+	(*ListExpensesResponse)(nil),       // 33: budget.v1.ListExpensesResponse
+	(*YearMonth)(nil),                  // 34: budget.v1.YearMonth
+	(*IncomeSource)(nil),               // 35: budget.v1.IncomeSource
+	(*BudgetSource)(nil),               // 36: budget.v1.BudgetSource
+	(*Expense)(nil),                    // 37: budget.v1.Expense
+}
+var file_budget_v1_financial_proto_depIdxs = []int32{
+	34, // 0: budget.v1.GetMonthlySummaryRequest.year_month:type_name -> budget.v1.YearMonth
+	34, // 1: budget.v1.GetMonthlySummaryResponse.year_month:type_name -> budget.v1.YearMonth
+	34, // 2: budget.v1.GetMonthlyDataRequest.year_month:type_name -> budget.v1.YearMonth
+	34, // 3: budget.v1.GetMonthlyDataResponse.year_month:type_name -> budget.v1.YearMonth
+	35, // 4: budget.v1.GetMonthlyDataResponse.income_sources:type_name -> budget.v1.IncomeSource
+	36, // 5: budget.v1.GetMonthlyDataResponse.budget_sources:type_name -> budget.v1.BudgetSource
+	37, // 6: budget.v1.GetMonthlyDataResponse.expenses:type_name -> budget.v1.Expense
+	34, // 7: budget.v1.SetIncomeRequest.year_month:type_name -> budget.v1.YearMonth
+	34, // 8: budget.v1.GetIncomeRequest.year_month:type_name -> budget.v1.YearMonth
+	34, // 9: budget.v1.ListIncomeSourcesRequest.year_month:type_name -> budget.v1.YearMonth
+	35, // 10: budget.v1.ListIncomeSourcesResponse.sources:type_name -> budget.v1.IncomeSource
+	34, // 11: budget.v1.AddIncomeSourceRequest.year_month:type_name -> budget.v1.YearMonth
+	35, // 12: budget.v1.AddIncomeSourceResponse.source:type_name -> budget.v1.IncomeSource
+	35, // 13: budget.v1.UpdateIncomeSourceResponse.source:type_name -> budget.v1.IncomeSource
+	34, // 14: budget.v1.SetBudgetRequest.year_month:type_name -> budget.v1.YearMonth
+	34, // 15: budget.v1.GetBudgetRequest.year_month:type_name -> budget.v1.YearMonth
+	34, // 16: budget.v1.ListBudgetSourcesRequest.year_month:type_name -> budget.v1.YearMonth
+	36, // 17: budget.v1.ListBudgetSourcesResponse.sources:type_name -> budget.v1.BudgetSource
+	34, // 18: budget.v1.AddBudgetSourceRequest.year_month:type_name -> budget.v1.YearMonth
+	36, // 19: budget.v1.AddBudgetSourceResponse.source:type_name -> budget.v1.BudgetSource
+	36, // 20: budget.v1.UpdateBudgetSourceResponse.source:type_name -> budget.v1.BudgetSource
+	34, // 21: budget.v1.AddExpenseRequest.year_month:type_name -> budget.v1.YearMonth
+	34, // 22: budget.v1.ListExpensesRequest.year_month:type_name -> budget.v1.YearMonth
+	37, // 23: budget.v1.ListExpensesResponse.expenses:type_name -> budget.v1.Expense
+	0,  // 24: budget.v1.FinancialService.GetMonthlySummary:input_type -> budget.v1.GetMonthlySummaryRequest
+	2,  // 25: budget.v1.FinancialService.GetMonthlyData:input_type -> budget.v1.GetMonthlyDataRequest
+	4,  // 26: budget.v1.FinancialService.SetIncome:input_type -> budget.v1.SetIncomeRequest
+	6,  // 27: budget.v1.FinancialService.GetIncome:input_type -> budget.v1.GetIncomeRequest
+	8,  // 28: budget.v1.FinancialService.ListIncomeSources:input_type -> budget.v1.ListIncomeSourcesRequest
+	10, // 29: budget.v1.FinancialService.AddIncomeSource:input_type -> budget.v1.AddIncomeSourceRequest
+	12, // 30: budget.v1.FinancialService.UpdateIncomeSource:input_type -> budget.v1.UpdateIncomeSourceRequest
+	14, // 31: budget.v1.FinancialService.DeleteIncomeSource:input_type -> budget.v1.DeleteIncomeSourceRequest
+	16, // 32: budget.v1.FinancialService.SetBudget:input_type -> budget.v1.SetBudgetRequest
+	18, // 33: budget.v1.FinancialService.GetBudget:input_type -> budget.v1.GetBudgetRequest
+	20, // 34: budget.v1.FinancialService.ListBudgetSources:input_type -> budget.v1.ListBudgetSourcesRequest
+	22, // 35: budget.v1.FinancialService.AddBudgetSource:input_type -> budget.v1.AddBudgetSourceRequest
+	24, // 36: budget.v1.FinancialService.UpdateBudgetSource:input_type -> budget.v1.UpdateBudgetSourceRequest
+	26, // 37: budget.v1.FinancialService.DeleteBudgetSource:input_type -> budget.v1.DeleteBudgetSourceRequest
+	28, // 38: budget.v1.FinancialService.AddExpense:input_type -> budget.v1.AddExpenseRequest
+	30, // 39: budget.v1.FinancialService.DeleteExpense:input_type -> budget.v1.DeleteExpenseRequest
+	32, // 40: budget.v1.FinancialService.ListExpenses:input_type -> budget.v1.ListExpensesRequest
+	1,  // 41: budget.v1.FinancialService.GetMonthlySummary:output_type -> budget.v1.GetMonthlySummaryResponse
+	3,  // 42: budget.v1.FinancialService.GetMonthlyData:output_type -> budget.v1.GetMonthlyDataResponse
+	5,  // 43: budget.v1.FinancialService.SetIncome:output_type -> budget.v1.SetIncomeResponse
+	7,  // 44: budget.v1.FinancialService.GetIncome:output_type -> budget.v1.GetIncomeResponse
+	9,  // 45: budget.v1.FinancialService.ListIncomeSources:output_type -> budget.v1.ListIncomeSourcesResponse
+	11, // 46: budget.v1.FinancialService.AddIncomeSource:output_type -> budget.v1.AddIncomeSourceResponse
+	13, // 47: budget.v1.FinancialService.UpdateIncomeSource:output_type -> budget.v1.UpdateIncomeSourceResponse
+	15, // 48: budget.v1.FinancialService.DeleteIncomeSource:output_type -> budget.v1.DeleteIncomeSourceResponse
+	17, // 49: budget.v1.FinancialService.SetBudget:output_type -> budget.v1.SetBudgetResponse
+	19, // 50: budget.v1.FinancialService.GetBudget:output_type -> budget.v1.GetBudgetResponse
+	21, // 51: budget.v1.FinancialService.ListBudgetSources:output_type -> budget.v1.ListBudgetSourcesResponse
+	23, // 52: budget.v1.FinancialService.AddBudgetSource:output_type -> budget.v1.AddBudgetSourceResponse
+	25, // 53: budget.v1.FinancialService.UpdateBudgetSource:output_type -> budget.v1.UpdateBudgetSourceResponse
+	27, // 54: budget.v1.FinancialService.DeleteBudgetSource:output_type -> budget.v1.DeleteBudgetSourceResponse
+	29, // 55: budget.v1.FinancialService.AddExpense:output_type -> budget.v1.AddExpenseResponse
+	31, // 56: budget.v1.FinancialService.DeleteExpense:output_type -> budget.v1.DeleteExpenseResponse
+	33, // 57: budget.v1.FinancialService.ListExpenses:output_type -> budget.v1.ListExpensesResponse
+	41, // [41:58] is the sub-list for method output_type
+	24, // [24:41] is the sub-list for method input_type
+	24, // [24:24] is the sub-list for extension type_name
+	24, // [24:24] is the sub-list for extension extendee
+	0,  // [0:24] is the sub-list for field type_name
+}
+
+func init() { file_budget_v1_financial_proto_init() }
+func file_budget_v1_financial_proto_init() {
+	if File_budget_v1_financial_proto != nil {
+		return
+	}
+	file_budget_v1_types_proto_init()
+	file_budget_v1_financial_proto_msgTypes[10].OneofWrappers = []any{}
+	file_budget_v1_financial_proto_msgTypes[12].OneofWrappers = []any{}
+	file_budget_v1_financial_proto_msgTypes[22].OneofWrappers = []any{}
+	file_budget_v1_financial_proto_msgTypes[24].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_budget_v1_financial_proto_rawDesc), len(file_budget_v1_financial_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   34,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_budget_v1_financial_proto_goTypes,
+		DependencyIndexes: file_budget_v1_financial_proto_depIdxs,
+		MessageInfos:      file_budget_v1_financial_proto_msgTypes,
+	}.Build()
+	File_budget_v1_financial_proto = out.File
+	file_budget_v1_financial_proto_goTypes = nil
+	file_budget_v1_financial_proto_depIdxs = nil
+}
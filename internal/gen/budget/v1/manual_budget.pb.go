@@ -0,0 +1,308 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: budget/v1/manual_budget.proto
+
+package budgetv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetManualBudgetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	YearMonth     *YearMonth             `protobuf:"bytes,1,opt,name=year_month,json=yearMonth,proto3" json:"year_month,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetManualBudgetRequest) Reset() {
+	*x = GetManualBudgetRequest{}
+	mi := &file_budget_v1_manual_budget_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetManualBudgetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetManualBudgetRequest) ProtoMessage() {}
+
+func (x *GetManualBudgetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_manual_budget_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetManualBudgetRequest.ProtoReflect.Descriptor instead.
+func (*GetManualBudgetRequest) Descriptor() ([]byte, []int) {
+	return file_budget_v1_manual_budget_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetManualBudgetRequest) GetYearMonth() *YearMonth {
+	if x != nil {
+		return x.YearMonth
+	}
+	return nil
+}
+
+type GetManualBudgetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Budget        *ManualBudget          `protobuf:"bytes,1,opt,name=budget,proto3" json:"budget,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetManualBudgetResponse) Reset() {
+	*x = GetManualBudgetResponse{}
+	mi := &file_budget_v1_manual_budget_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetManualBudgetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetManualBudgetResponse) ProtoMessage() {}
+
+func (x *GetManualBudgetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_manual_budget_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetManualBudgetResponse.ProtoReflect.Descriptor instead.
+func (*GetManualBudgetResponse) Descriptor() ([]byte, []int) {
+	return file_budget_v1_manual_budget_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetManualBudgetResponse) GetBudget() *ManualBudget {
+	if x != nil {
+		return x.Budget
+	}
+	return nil
+}
+
+type SaveManualBudgetRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	YearMonth       *YearMonth             `protobuf:"bytes,1,opt,name=year_month,json=yearMonth,proto3" json:"year_month,omitempty"`
+	Version         int64                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	BankAmountCents int64                  `protobuf:"varint,3,opt,name=bank_amount_cents,json=bankAmountCents,proto3" json:"bank_amount_cents,omitempty"`
+	Items           []*ManualBudgetItem    `protobuf:"bytes,4,rep,name=items,proto3" json:"items,omitempty"` // ids are ignored
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SaveManualBudgetRequest) Reset() {
+	*x = SaveManualBudgetRequest{}
+	mi := &file_budget_v1_manual_budget_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveManualBudgetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveManualBudgetRequest) ProtoMessage() {}
+
+func (x *SaveManualBudgetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_manual_budget_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveManualBudgetRequest.ProtoReflect.Descriptor instead.
+func (*SaveManualBudgetRequest) Descriptor() ([]byte, []int) {
+	return file_budget_v1_manual_budget_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SaveManualBudgetRequest) GetYearMonth() *YearMonth {
+	if x != nil {
+		return x.YearMonth
+	}
+	return nil
+}
+
+func (x *SaveManualBudgetRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *SaveManualBudgetRequest) GetBankAmountCents() int64 {
+	if x != nil {
+		return x.BankAmountCents
+	}
+	return 0
+}
+
+func (x *SaveManualBudgetRequest) GetItems() []*ManualBudgetItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type SaveManualBudgetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Budget        *ManualBudget          `protobuf:"bytes,1,opt,name=budget,proto3" json:"budget,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SaveManualBudgetResponse) Reset() {
+	*x = SaveManualBudgetResponse{}
+	mi := &file_budget_v1_manual_budget_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveManualBudgetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveManualBudgetResponse) ProtoMessage() {}
+
+func (x *SaveManualBudgetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_manual_budget_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveManualBudgetResponse.ProtoReflect.Descriptor instead.
+func (*SaveManualBudgetResponse) Descriptor() ([]byte, []int) {
+	return file_budget_v1_manual_budget_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SaveManualBudgetResponse) GetBudget() *ManualBudget {
+	if x != nil {
+		return x.Budget
+	}
+	return nil
+}
+
+var File_budget_v1_manual_budget_proto protoreflect.FileDescriptor
+
+const file_budget_v1_manual_budget_proto_rawDesc = "" +
+	"\n" +
+	"\x1dbudget/v1/manual_budget.proto\x12\tbudget.v1\x1a\x15budget/v1/types.proto\"M\n" +
+	"\x16GetManualBudgetRequest\x123\n" +
+	"\n" +
+	"year_month\x18\x01 \x01(\v2\x14.budget.v1.YearMonthR\tyearMonth\"J\n" +
+	"\x17GetManualBudgetResponse\x12/\n" +
+	"\x06budget\x18\x01 \x01(\v2\x17.budget.v1.ManualBudgetR\x06budget\"\xc7\x01\n" +
+	"\x17SaveManualBudgetRequest\x123\n" +
+	"\n" +
+	"year_month\x18\x01 \x01(\v2\x14.budget.v1.YearMonthR\tyearMonth\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\x12*\n" +
+	"\x11bank_amount_cents\x18\x03 \x01(\x03R\x0fbankAmountCents\x121\n" +
+	"\x05items\x18\x04 \x03(\v2\x1b.budget.v1.ManualBudgetItemR\x05items\"K\n" +
+	"\x18SaveManualBudgetResponse\x12/\n" +
+	"\x06budget\x18\x01 \x01(\v2\x17.budget.v1.ManualBudgetR\x06budget2\xd1\x01\n" +
+	"\x13ManualBudgetService\x12]\n" +
+	"\x0fGetManualBudget\x12!.budget.v1.GetManualBudgetRequest\x1a\".budget.v1.GetManualBudgetResponse\"\x03\x90\x02\x01\x12[\n" +
+	"\x10SaveManualBudget\x12\".budget.v1.SaveManualBudgetRequest\x1a#.budget.v1.SaveManualBudgetResponseB<Z:github.com/mdco1990/webapp/internal/gen/budget/v1;budgetv1b\x06proto3"
+
+var (
+	file_budget_v1_manual_budget_proto_rawDescOnce sync.Once
+	file_budget_v1_manual_budget_proto_rawDescData []byte
+)
+
+func file_budget_v1_manual_budget_proto_rawDescGZIP() []byte {
+	file_budget_v1_manual_budget_proto_rawDescOnce.Do(func() {
+		file_budget_v1_manual_budget_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_budget_v1_manual_budget_proto_rawDesc), len(file_budget_v1_manual_budget_proto_rawDesc)))
+	})
+	return file_budget_v1_manual_budget_proto_rawDescData
+}
+
+var file_budget_v1_manual_budget_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_budget_v1_manual_budget_proto_goTypes = []any{
+	(*GetManualBudgetRequest)(nil),   // 0: budget.v1.GetManualBudgetRequest
+	(*GetManualBudgetResponse)(nil),  // 1: budget.v1.GetManualBudgetResponse
+	(*SaveManualBudgetRequest)(nil),  // 2: budget.v1.SaveManualBudgetRequest
+	(*SaveManualBudgetResponse)(nil), // 3: budget.v1.SaveManualBudgetResponse
+	(*YearMonth)(nil),                // 4: budget.v1.YearMonth
+	(*ManualBudget)(nil),             // 5: budget.v1.ManualBudget
+	(*ManualBudgetItem)(nil),         // 6: budget.v1.ManualBudgetItem
+}
+var file_budget_v1_manual_budget_proto_depIdxs = []int32{
+	4, // 0: budget.v1.GetManualBudgetRequest.year_month:type_name -> budget.v1.YearMonth
+	5, // 1: budget.v1.GetManualBudgetResponse.budget:type_name -> budget.v1.ManualBudget
+	4, // 2: budget.v1.SaveManualBudgetRequest.year_month:type_name -> budget.v1.YearMonth
+	6, // 3: budget.v1.SaveManualBudgetRequest.items:type_name -> budget.v1.ManualBudgetItem
+	5, // 4: budget.v1.SaveManualBudgetResponse.budget:type_name -> budget.v1.ManualBudget
+	0, // 5: budget.v1.ManualBudgetService.GetManualBudget:input_type -> budget.v1.GetManualBudgetRequest
+	2, // 6: budget.v1.ManualBudgetService.SaveManualBudget:input_type -> budget.v1.SaveManualBudgetRequest
+	1, // 7: budget.v1.ManualBudgetService.GetManualBudget:output_type -> budget.v1.GetManualBudgetResponse
+	3, // 8: budget.v1.ManualBudgetService.SaveManualBudget:output_type -> budget.v1.SaveManualBudgetResponse
+	7, // [7:9] is the sub-list for method output_type
+	5, // [5:7] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_budget_v1_manual_budget_proto_init() }
+func file_budget_v1_manual_budget_proto_init() {
+	if File_budget_v1_manual_budget_proto != nil {
+		return
+	}
+	file_budget_v1_types_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_budget_v1_manual_budget_proto_rawDesc), len(file_budget_v1_manual_budget_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_budget_v1_manual_budget_proto_goTypes,
+		DependencyIndexes: file_budget_v1_manual_budget_proto_depIdxs,
+		MessageInfos:      file_budget_v1_manual_budget_proto_msgTypes,
+	}.Build()
+	File_budget_v1_manual_budget_proto = out.File
+	file_budget_v1_manual_budget_proto_goTypes = nil
+	file_budget_v1_manual_budget_proto_depIdxs = nil
+}
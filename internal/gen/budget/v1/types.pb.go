@@ -0,0 +1,609 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: budget/v1/types.proto
+
+package budgetv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type YearMonth struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Year          int32                  `protobuf:"varint,1,opt,name=year,proto3" json:"year,omitempty"`
+	Month         int32                  `protobuf:"varint,2,opt,name=month,proto3" json:"month,omitempty"` // 1-12
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *YearMonth) Reset() {
+	*x = YearMonth{}
+	mi := &file_budget_v1_types_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *YearMonth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*YearMonth) ProtoMessage() {}
+
+func (x *YearMonth) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_types_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use YearMonth.ProtoReflect.Descriptor instead.
+func (*YearMonth) Descriptor() ([]byte, []int) {
+	return file_budget_v1_types_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *YearMonth) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *YearMonth) GetMonth() int32 {
+	if x != nil {
+		return x.Month
+	}
+	return 0
+}
+
+type IncomeSource struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	YearMonth     *YearMonth             `protobuf:"bytes,3,opt,name=year_month,json=yearMonth,proto3" json:"year_month,omitempty"`
+	AmountCents   int64                  `protobuf:"varint,4,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
+	DayOfMonth    *int32                 `protobuf:"varint,5,opt,name=day_of_month,json=dayOfMonth,proto3,oneof" json:"day_of_month,omitempty"` // pay day
+	Version       int64                  `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`                                 // bumped on every write
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IncomeSource) Reset() {
+	*x = IncomeSource{}
+	mi := &file_budget_v1_types_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IncomeSource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncomeSource) ProtoMessage() {}
+
+func (x *IncomeSource) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_types_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncomeSource.ProtoReflect.Descriptor instead.
+func (*IncomeSource) Descriptor() ([]byte, []int) {
+	return file_budget_v1_types_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *IncomeSource) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *IncomeSource) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *IncomeSource) GetYearMonth() *YearMonth {
+	if x != nil {
+		return x.YearMonth
+	}
+	return nil
+}
+
+func (x *IncomeSource) GetAmountCents() int64 {
+	if x != nil {
+		return x.AmountCents
+	}
+	return 0
+}
+
+func (x *IncomeSource) GetDayOfMonth() int32 {
+	if x != nil && x.DayOfMonth != nil {
+		return *x.DayOfMonth
+	}
+	return 0
+}
+
+func (x *IncomeSource) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *IncomeSource) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *IncomeSource) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type BudgetSource struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	YearMonth     *YearMonth             `protobuf:"bytes,3,opt,name=year_month,json=yearMonth,proto3" json:"year_month,omitempty"`
+	AmountCents   int64                  `protobuf:"varint,4,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
+	DayOfMonth    *int32                 `protobuf:"varint,5,opt,name=day_of_month,json=dayOfMonth,proto3,oneof" json:"day_of_month,omitempty"` // due day
+	Version       int64                  `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`                                 // bumped on every write
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BudgetSource) Reset() {
+	*x = BudgetSource{}
+	mi := &file_budget_v1_types_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BudgetSource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BudgetSource) ProtoMessage() {}
+
+func (x *BudgetSource) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_types_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BudgetSource.ProtoReflect.Descriptor instead.
+func (*BudgetSource) Descriptor() ([]byte, []int) {
+	return file_budget_v1_types_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BudgetSource) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *BudgetSource) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BudgetSource) GetYearMonth() *YearMonth {
+	if x != nil {
+		return x.YearMonth
+	}
+	return nil
+}
+
+func (x *BudgetSource) GetAmountCents() int64 {
+	if x != nil {
+		return x.AmountCents
+	}
+	return 0
+}
+
+func (x *BudgetSource) GetDayOfMonth() int32 {
+	if x != nil && x.DayOfMonth != nil {
+		return *x.DayOfMonth
+	}
+	return 0
+}
+
+func (x *BudgetSource) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *BudgetSource) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *BudgetSource) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type Expense struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	YearMonth     *YearMonth             `protobuf:"bytes,2,opt,name=year_month,json=yearMonth,proto3" json:"year_month,omitempty"`
+	Category      string                 `protobuf:"bytes,3,opt,name=category,proto3" json:"category,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	AmountCents   int64                  `protobuf:"varint,5,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Expense) Reset() {
+	*x = Expense{}
+	mi := &file_budget_v1_types_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Expense) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Expense) ProtoMessage() {}
+
+func (x *Expense) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_types_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Expense.ProtoReflect.Descriptor instead.
+func (*Expense) Descriptor() ([]byte, []int) {
+	return file_budget_v1_types_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Expense) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Expense) GetYearMonth() *YearMonth {
+	if x != nil {
+		return x.YearMonth
+	}
+	return nil
+}
+
+func (x *Expense) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Expense) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Expense) GetAmountCents() int64 {
+	if x != nil {
+		return x.AmountCents
+	}
+	return 0
+}
+
+func (x *Expense) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ManualBudgetItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	AmountCents   int64                  `protobuf:"varint,3,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ManualBudgetItem) Reset() {
+	*x = ManualBudgetItem{}
+	mi := &file_budget_v1_types_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ManualBudgetItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ManualBudgetItem) ProtoMessage() {}
+
+func (x *ManualBudgetItem) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_types_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ManualBudgetItem.ProtoReflect.Descriptor instead.
+func (*ManualBudgetItem) Descriptor() ([]byte, []int) {
+	return file_budget_v1_types_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ManualBudgetItem) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ManualBudgetItem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ManualBudgetItem) GetAmountCents() int64 {
+	if x != nil {
+		return x.AmountCents
+	}
+	return 0
+}
+
+type ManualBudget struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	YearMonth       *YearMonth             `protobuf:"bytes,1,opt,name=year_month,json=yearMonth,proto3" json:"year_month,omitempty"`
+	BankAmountCents int64                  `protobuf:"varint,2,opt,name=bank_amount_cents,json=bankAmountCents,proto3" json:"bank_amount_cents,omitempty"`
+	Items           []*ManualBudgetItem    `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	Version         int64                  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"` // 0 until the month is first saved
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ManualBudget) Reset() {
+	*x = ManualBudget{}
+	mi := &file_budget_v1_types_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ManualBudget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ManualBudget) ProtoMessage() {}
+
+func (x *ManualBudget) ProtoReflect() protoreflect.Message {
+	mi := &file_budget_v1_types_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ManualBudget.ProtoReflect.Descriptor instead.
+func (*ManualBudget) Descriptor() ([]byte, []int) {
+	return file_budget_v1_types_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ManualBudget) GetYearMonth() *YearMonth {
+	if x != nil {
+		return x.YearMonth
+	}
+	return nil
+}
+
+func (x *ManualBudget) GetBankAmountCents() int64 {
+	if x != nil {
+		return x.BankAmountCents
+	}
+	return 0
+}
+
+func (x *ManualBudget) GetItems() []*ManualBudgetItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ManualBudget) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+var File_budget_v1_types_proto protoreflect.FileDescriptor
+
+const file_budget_v1_types_proto_rawDesc = "" +
+	"\n" +
+	"\x15budget/v1/types.proto\x12\tbudget.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"5\n" +
+	"\tYearMonth\x12\x12\n" +
+	"\x04year\x18\x01 \x01(\x05R\x04year\x12\x14\n" +
+	"\x05month\x18\x02 \x01(\x05R\x05month\"\xd2\x02\n" +
+	"\fIncomeSource\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x123\n" +
+	"\n" +
+	"year_month\x18\x03 \x01(\v2\x14.budget.v1.YearMonthR\tyearMonth\x12!\n" +
+	"\famount_cents\x18\x04 \x01(\x03R\vamountCents\x12%\n" +
+	"\fday_of_month\x18\x05 \x01(\x05H\x00R\n" +
+	"dayOfMonth\x88\x01\x01\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\x03R\aversion\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB\x0f\n" +
+	"\r_day_of_month\"\xd2\x02\n" +
+	"\fBudgetSource\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x123\n" +
+	"\n" +
+	"year_month\x18\x03 \x01(\v2\x14.budget.v1.YearMonthR\tyearMonth\x12!\n" +
+	"\famount_cents\x18\x04 \x01(\x03R\vamountCents\x12%\n" +
+	"\fday_of_month\x18\x05 \x01(\x05H\x00R\n" +
+	"dayOfMonth\x88\x01\x01\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\x03R\aversion\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB\x0f\n" +
+	"\r_day_of_month\"\xea\x01\n" +
+	"\aExpense\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x123\n" +
+	"\n" +
+	"year_month\x18\x02 \x01(\v2\x14.budget.v1.YearMonthR\tyearMonth\x12\x1a\n" +
+	"\bcategory\x18\x03 \x01(\tR\bcategory\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12!\n" +
+	"\famount_cents\x18\x05 \x01(\x03R\vamountCents\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"Y\n" +
+	"\x10ManualBudgetItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12!\n" +
+	"\famount_cents\x18\x03 \x01(\x03R\vamountCents\"\xbc\x01\n" +
+	"\fManualBudget\x123\n" +
+	"\n" +
+	"year_month\x18\x01 \x01(\v2\x14.budget.v1.YearMonthR\tyearMonth\x12*\n" +
+	"\x11bank_amount_cents\x18\x02 \x01(\x03R\x0fbankAmountCents\x121\n" +
+	"\x05items\x18\x03 \x03(\v2\x1b.budget.v1.ManualBudgetItemR\x05items\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x03R\aversionB<Z:github.com/mdco1990/webapp/internal/gen/budget/v1;budgetv1b\x06proto3"
+
+var (
+	file_budget_v1_types_proto_rawDescOnce sync.Once
+	file_budget_v1_types_proto_rawDescData []byte
+)
+
+func file_budget_v1_types_proto_rawDescGZIP() []byte {
+	file_budget_v1_types_proto_rawDescOnce.Do(func() {
+		file_budget_v1_types_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_budget_v1_types_proto_rawDesc), len(file_budget_v1_types_proto_rawDesc)))
+	})
+	return file_budget_v1_types_proto_rawDescData
+}
+
+var file_budget_v1_types_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_budget_v1_types_proto_goTypes = []any{
+	(*YearMonth)(nil),             // 0: budget.v1.YearMonth
+	(*IncomeSource)(nil),          // 1: budget.v1.IncomeSource
+	(*BudgetSource)(nil),          // 2: budget.v1.BudgetSource
+	(*Expense)(nil),               // 3: budget.v1.Expense
+	(*ManualBudgetItem)(nil),      // 4: budget.v1.ManualBudgetItem
+	(*ManualBudget)(nil),          // 5: budget.v1.ManualBudget
+	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
+}
+var file_budget_v1_types_proto_depIdxs = []int32{
+	0,  // 0: budget.v1.IncomeSource.year_month:type_name -> budget.v1.YearMonth
+	6,  // 1: budget.v1.IncomeSource.created_at:type_name -> google.protobuf.Timestamp
+	6,  // 2: budget.v1.IncomeSource.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 3: budget.v1.BudgetSource.year_month:type_name -> budget.v1.YearMonth
+	6,  // 4: budget.v1.BudgetSource.created_at:type_name -> google.protobuf.Timestamp
+	6,  // 5: budget.v1.BudgetSource.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 6: budget.v1.Expense.year_month:type_name -> budget.v1.YearMonth
+	6,  // 7: budget.v1.Expense.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 8: budget.v1.ManualBudget.year_month:type_name -> budget.v1.YearMonth
+	4,  // 9: budget.v1.ManualBudget.items:type_name -> budget.v1.ManualBudgetItem
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_budget_v1_types_proto_init() }
+func file_budget_v1_types_proto_init() {
+	if File_budget_v1_types_proto != nil {
+		return
+	}
+	file_budget_v1_types_proto_msgTypes[1].OneofWrappers = []any{}
+	file_budget_v1_types_proto_msgTypes[2].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_budget_v1_types_proto_rawDesc), len(file_budget_v1_types_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_budget_v1_types_proto_goTypes,
+		DependencyIndexes: file_budget_v1_types_proto_depIdxs,
+		MessageInfos:      file_budget_v1_types_proto_msgTypes,
+	}.Build()
+	File_budget_v1_types_proto = out.File
+	file_budget_v1_types_proto_goTypes = nil
+	file_budget_v1_types_proto_depIdxs = nil
+}
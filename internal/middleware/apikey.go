@@ -3,25 +3,29 @@ package middleware
 
 import (
 	"net/http"
+
+	"github.com/mdco1990/webapp/internal/domain"
 )
 
 // APIKeyConfig configures simple header-based API key authentication.
 type APIKeyConfig struct {
 	Header string
 	Key    string
+	// Skip exempts requests that carry their own credentials, such as
+	// personal access tokens, from the shared key.
+	Skip func(*http.Request) bool
 }
 
 // APIKeyAuth returns middleware that validates a shared API key in a header.
 func APIKeyAuth(cfg APIKeyConfig) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if cfg.Key == "" {
+			if cfg.Key == "" || (cfg.Skip != nil && cfg.Skip(r)) {
 				next.ServeHTTP(w, r)
 				return
 			}
 			if r.Header.Get(cfg.Header) != cfg.Key {
-				w.WriteHeader(http.StatusUnauthorized)
-				_, _ = w.Write([]byte("unauthorized"))
+				WriteProblem(w, r, domain.Unauthorized("missing or invalid API key"))
 				return
 			}
 			next.ServeHTTP(w, r)
@@ -0,0 +1,283 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/storage"
+)
+
+// Idempotency headers.
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+const (
+	maxIdempotencyKeyLength   = 255
+	defaultIdempotencyTTL     = 24 * time.Hour
+	defaultIdempotencyLockTTL = time.Minute
+	idempotencyStoragePrefix  = "idempotency:"
+	idempotencyLockSuffix     = ":lock"
+)
+
+// replayedHeaders are the response headers stored and replayed with the body.
+var replayedHeaders = []string{"Content-Type", "Location", "ETag"}
+
+// IdempotencyConfig configures the Idempotency middleware.
+type IdempotencyConfig struct {
+	Store storage.Provider
+	// TTL is how long a response can be replayed (24h if zero).
+	TTL time.Duration
+	// LockTTL is how long a request holds its key against repeats if the
+	// process handling it never releases it (1 minute if zero).
+	LockTTL time.Duration
+	// Scope namespaces keys per caller, typically by user ID. Requests with an
+	// empty scope are passed through unchanged.
+	Scope func(*http.Request) string
+}
+
+// idempotentResponse is a stored response, keyed by scope and key.
+type idempotentResponse struct {
+	Fingerprint string              `json:"fingerprint"`
+	Status      int                 `json:"status"`
+	Header      map[string][]string `json:"header,omitempty"`
+	Body        []byte              `json:"body"`
+}
+
+// Idempotency makes retried writes safe. For a non-safe request carrying an
+// Idempotency-Key header, the first response below 500 is stored and replayed
+// (with Idempotent-Replayed: true) for later requests with the same key and
+// the same method, path and body. Reusing a key with a different request is
+// rejected with 422, and a repeat that arrives while the first is still being
+// handled gets 409 with Retry-After. Server errors are not stored, so they can
+// be retried.
+//
+// Stored responses are shared by whatever Store is configured. Requests in
+// flight are tracked in the Store too when it is a storage.Claimer, as the
+// built-in providers are, and otherwise per process.
+func Idempotency(cfg IdempotencyConfig) func(http.Handler) http.Handler {
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultIdempotencyTTL
+	}
+	if cfg.LockTTL <= 0 {
+		cfg.LockTTL = defaultIdempotencyLockTTL
+	}
+	locks := &idempotencyLocks{store: cfg.Store, ttl: cfg.LockTTL, local: make(map[string]struct{})}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" || isSafeMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			scope := cfg.Scope(r)
+			if scope == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !validIdempotencyKey(key) {
+				WriteProblem(w, r, domain.Invalid("invalid Idempotency-Key",
+					domain.FieldError{Field: IdempotencyKeyHeader, Message: "must be 1-255 printable ASCII characters"}))
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				WriteProblemStatus(w, r, http.StatusBadRequest, "failed to read request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			fingerprint := requestFingerprint(r, body)
+			storeKey := idempotencyStoragePrefix + scope + ":" + key
+
+			acquired, err := locks.acquire(r.Context(), storeKey)
+			if err != nil {
+				slog.Error("idempotency: failed to lock key", "err", err, "request_id", GetRequestID(r.Context()))
+				WriteProblemStatus(w, r, http.StatusInternalServerError, "failed to check Idempotency-Key")
+				return
+			}
+			if !acquired {
+				WriteProblem(w, r, &domain.Error{
+					Kind:       domain.ErrConflict,
+					Code:       "idempotency_key_in_use",
+					Message:    "a request with this Idempotency-Key is still being processed",
+					RetryAfter: time.Second,
+				})
+				return
+			}
+			defer locks.release(r.Context(), storeKey)
+
+			if prev, ok := loadIdempotentResponse(r, cfg.Store, storeKey); ok {
+				if prev.Fingerprint != fingerprint {
+					writeProblemBody(w, r, Problem{
+						Status: http.StatusUnprocessableEntity,
+						Code:   "idempotency_key_reused",
+						Detail: "Idempotency-Key was already used for a different request",
+					})
+					return
+				}
+				for name, values := range prev.Header {
+					w.Header()[name] = values
+				}
+				w.Header().Set(IdempotentReplayedHeader, "true")
+				w.WriteHeader(prev.Status)
+				_, _ = w.Write(prev.Body)
+				return
+			}
+
+			rec := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+			if rec.status >= http.StatusInternalServerError {
+				return
+			}
+			saveIdempotentResponse(r, cfg.Store, storeKey, cfg.TTL, idempotentResponse{
+				Fingerprint: fingerprint,
+				Status:      rec.status,
+				Header:      storedHeaders(w.Header()),
+				Body:        rec.body.Bytes(),
+			})
+		})
+	}
+}
+
+// idempotencyLocks marks the keys whose first request is being handled.
+type idempotencyLocks struct {
+	store storage.Provider
+	ttl   time.Duration
+	mu    sync.Mutex
+	local map[string]struct{}
+}
+
+// acquire takes key, reporting false if another request holds it.
+func (l *idempotencyLocks) acquire(ctx context.Context, key string) (bool, error) {
+	if c, ok := l.store.(storage.Claimer); ok {
+		return c.SaveIfAbsent(ctx, key+idempotencyLockSuffix, true, &l.ttl)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, busy := l.local[key]; busy {
+		return false, nil
+	}
+	l.local[key] = struct{}{}
+	return true, nil
+}
+
+// release gives key back, even if the client has gone away.
+func (l *idempotencyLocks) release(ctx context.Context, key string) {
+	if _, ok := l.store.(storage.Claimer); ok {
+		if err := l.store.Delete(context.WithoutCancel(ctx), key+idempotencyLockSuffix); err != nil {
+			slog.Warn("idempotency: failed to unlock key; it expires on its own", "err", err)
+		}
+		return
+	}
+	l.mu.Lock()
+	delete(l.local, key)
+	l.mu.Unlock()
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// requestFingerprint identifies a request by method, path, query and body.
+func requestFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	_, _ = io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	_, _ = h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func storedHeaders(h http.Header) map[string][]string {
+	out := make(map[string][]string)
+	for _, name := range replayedHeaders {
+		if v := h.Values(name); len(v) > 0 {
+			out[name] = v
+		}
+	}
+	return out
+}
+
+// loadIdempotentResponse reads a stored response. Responses are stored as
+// JSON strings so that they survive providers that serialize values.
+func loadIdempotentResponse(r *http.Request, store storage.Provider, key string) (idempotentResponse, bool) {
+	var resp idempotentResponse
+	v, err := store.Load(r.Context(), key)
+	if err != nil {
+		return resp, false
+	}
+	var raw []byte
+	switch v := v.(type) {
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return resp, false
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		slog.Warn("idempotency: discarding unreadable stored response", "err", err)
+		return resp, false
+	}
+	return resp, true
+}
+
+func saveIdempotentResponse(
+	r *http.Request,
+	store storage.Provider,
+	key string,
+	ttl time.Duration,
+	resp idempotentResponse,
+) {
+	raw, err := json.Marshal(resp)
+	if err == nil {
+		// Store even if the client has gone away; its retry will want this.
+		err = store.Save(context.WithoutCancel(r.Context()), key, string(raw), &ttl)
+	}
+	if err != nil {
+		slog.Error("idempotency: failed to store response", "err", err, "request_id", GetRequestID(r.Context()))
+	}
+}
+
+// recordingWriter passes a response through while keeping a copy.
+type recordingWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (w *recordingWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mdco1990/webapp/internal/storage"
+)
+
+func newIdempotentHandler(t *testing.T, h http.HandlerFunc) http.Handler {
+	t.Helper()
+	return Idempotency(IdempotencyConfig{
+		Store: storage.NewMemoryStorage(storage.Options{}),
+		Scope: func(r *http.Request) string { return r.Header.Get("X-User") },
+	})(h)
+}
+
+func idempotentRequest(h http.Handler, user, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/expenses", strings.NewReader(body))
+	req.Header.Set("X-User", user)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	var calls atomic.Int32
+	h := newIdempotentHandler(t, func(w http.ResponseWriter, _ *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"id":%d}`, n)
+	})
+
+	first := idempotentRequest(h, "1", "abc", `{"amount_cents":100}`)
+	if first.Code != http.StatusCreated || first.Body.String() != `{"id":1}` {
+		t.Fatalf("Expected 201 {\"id\":1}, got %d %s", first.Code, first.Body.String())
+	}
+
+	retry := idempotentRequest(h, "1", "abc", `{"amount_cents":100}`)
+	if retry.Code != http.StatusCreated || retry.Body.String() != `{"id":1}` {
+		t.Errorf("Expected the stored response to be replayed, got %d %s", retry.Code, retry.Body.String())
+	}
+	if retry.Header().Get(IdempotentReplayedHeader) != "true" || retry.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Expected replay headers, got %v", retry.Header())
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected the handler to run once, ran %d times", calls.Load())
+	}
+
+	// Keys are per user, and requests without a key are never deduplicated.
+	if w := idempotentRequest(h, "2", "abc", `{"amount_cents":100}`); w.Body.String() != `{"id":2}` {
+		t.Errorf("Expected another user's key to be independent, got %s", w.Body.String())
+	}
+	if w := idempotentRequest(h, "1", "", `{"amount_cents":100}`); w.Body.String() != `{"id":3}` {
+		t.Errorf("Expected a request without a key to run, got %s", w.Body.String())
+	}
+}
+
+func TestIdempotencyKeyReuse(t *testing.T) {
+	h := newIdempotentHandler(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	idempotentRequest(h, "1", "abc", `{"amount_cents":100}`)
+	w := idempotentRequest(h, "1", "abc", `{"amount_cents":999}`)
+	if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), `"idempotency_key_reused"`) {
+		t.Errorf("Expected 422 idempotency_key_reused, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = idempotentRequest(h, "1", "bad\x01key", `{}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid key, got %d", w.Code)
+	}
+}
+
+func TestIdempotencyServerErrorsAreNotStored(t *testing.T) {
+	var calls atomic.Int32
+	h := newIdempotentHandler(t, func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	idempotentRequest(h, "1", "abc", `{}`)
+	if w := idempotentRequest(h, "1", "abc", `{}`); w.Code != http.StatusCreated {
+		t.Errorf("Expected a retry after a 500 to run again, got %d", w.Code)
+	}
+}
+
+func TestIdempotencyInFlight(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	h := newIdempotentHandler(t, func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- idempotentRequest(h, "1", "abc", `{}`) }()
+	<-started
+
+	w := idempotentRequest(h, "1", "abc", `{}`)
+	if w.Code != http.StatusConflict || w.Header().Get("Retry-After") != "1" {
+		t.Errorf("Expected 409 with Retry-After while in flight, got %d %v", w.Code, w.Header())
+	}
+	if !strings.Contains(w.Body.String(), `"idempotency_key_in_use"`) {
+		t.Errorf("Expected code idempotency_key_in_use, got %s", w.Body.String())
+	}
+
+	close(release)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Errorf("Expected the first request to complete, got %d", first.Code)
+	}
+	if w := idempotentRequest(h, "1", "abc", `{}`); w.Code != http.StatusCreated ||
+		w.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("Expected a replay once the first request finished, got %d", w.Code)
+	}
+}
+
+func TestIdempotencySharedStore(t *testing.T) {
+	// Two instances with their own provider on the same file see each
+	// other's requests in flight and replay each other's responses.
+	path := filepath.Join(t.TempDir(), "idempotency.db")
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var calls atomic.Int32
+	var handlers []http.Handler
+	for range 2 {
+		store, err := storage.NewSQLiteStorage(path, storage.Options{})
+		if err != nil {
+			t.Fatalf("Failed to open SQLite store: %v", err)
+		}
+		t.Cleanup(func() { _ = store.Close(context.Background()) })
+		handlers = append(handlers, Idempotency(IdempotencyConfig{
+			Store: store,
+			Scope: func(r *http.Request) string { return r.Header.Get("X-User") },
+		})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if calls.Add(1) == 1 {
+				close(started)
+				<-release
+			}
+			w.WriteHeader(http.StatusCreated)
+		})))
+	}
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- idempotentRequest(handlers[0], "1", "abc", `{}`) }()
+	<-started
+	if w := idempotentRequest(handlers[1], "1", "abc", `{}`); w.Code != http.StatusConflict {
+		t.Errorf("Expected 409 from the other instance while in flight, got %d %s", w.Code, w.Body.String())
+	}
+	close(release)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Errorf("Expected the first request to complete, got %d", first.Code)
+	}
+	w := idempotentRequest(handlers[1], "1", "abc", `{}`)
+	if w.Code != http.StatusCreated || w.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("Expected a replay from the other instance, got %d %v", w.Code, w.Header())
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected the handler to run once, got %d", n)
+	}
+}
@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// ProblemContentType is the media type of RFC 7807 error responses.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body. Code is a stable,
+// machine-readable identifier clients can branch on; Detail is for humans.
+type Problem struct {
+	Type      string              `json:"type"`
+	Title     string              `json:"title"`
+	Status    int                 `json:"status"`
+	Detail    string              `json:"detail,omitempty"`
+	Instance  string              `json:"instance,omitempty"`
+	Code      string              `json:"code"`
+	RequestID string              `json:"request_id,omitempty"`
+	Errors    []domain.FieldError `json:"errors,omitempty"`
+}
+
+// statusCodes overrides the codes derived from the status text where the
+// domain error model already has a name for the condition.
+var statusCodes = map[int]string{
+	http.StatusUnprocessableEntity: "validation_failed",
+	http.StatusTooManyRequests:     "rate_limited",
+	http.StatusInternalServerError: "internal_error",
+}
+
+// StatusCode returns the default problem code for an HTTP status
+// ("not_found" for 404).
+func StatusCode(status int) string {
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	text := http.StatusText(status)
+	if text == "" {
+		return "error"
+	}
+	return strings.ToLower(strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(text))
+}
+
+// kindStatus maps domain error kinds to HTTP statuses, in match order.
+var kindStatus = []struct {
+	kind   error
+	status int
+}{
+	{domain.ErrValidation, http.StatusBadRequest},
+	{domain.ErrNotFound, http.StatusNotFound},
+	{domain.ErrConflict, http.StatusConflict},
+	{domain.ErrUnauthorized, http.StatusUnauthorized},
+	{domain.ErrForbidden, http.StatusForbidden},
+	{domain.ErrRateLimited, http.StatusTooManyRequests},
+	{domain.ErrPreconditionFailed, http.StatusPreconditionFailed},
+}
+
+// domainErrorer is implemented by errors from other layers (such as
+// security.ValidationError) that know their domain equivalent.
+type domainErrorer interface {
+	DomainError() *domain.Error
+}
+
+// ProblemFor maps err to a status and problem body. Typed domain errors keep
+// their code, message and field details; bare kinds get the default code for
+// their status; anything else becomes an opaque 500.
+func ProblemFor(err error) (int, Problem) {
+	var conv domainErrorer
+	if errors.As(err, &conv) {
+		err = conv.DomainError()
+	}
+	status := http.StatusInternalServerError
+	for _, ks := range kindStatus {
+		if errors.Is(err, ks.kind) {
+			status = ks.status
+			break
+		}
+	}
+	if status == http.StatusInternalServerError && errors.Is(err, sql.ErrNoRows) {
+		status = http.StatusNotFound
+	}
+
+	p := Problem{Status: status, Code: StatusCode(status)}
+	var de *domain.Error
+	switch {
+	case errors.As(err, &de):
+		if de.Code != "" {
+			p.Code = de.Code
+		}
+		p.Detail = de.Message
+		p.Errors = de.Fields
+	case status == http.StatusBadRequest:
+		// Bare validation errors are built from constant messages.
+		p.Detail = err.Error()
+	case status != http.StatusInternalServerError:
+		p.Detail = strings.ToLower(http.StatusText(status))
+	default:
+		p.Detail = "internal server error"
+	}
+	return status, p
+}
+
+// WriteProblem maps err to a problem response. Unexpected errors are logged
+// with the request ID and answered with a generic 500.
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error) {
+	status, p := ProblemFor(err)
+	if status == http.StatusInternalServerError {
+		attrs := []any{"err", err}
+		if r != nil {
+			attrs = append(attrs, "request_id", GetRequestID(r.Context()), "path", r.URL.Path)
+		}
+		slog.Error("request failed", attrs...)
+	}
+	var de *domain.Error
+	if errors.As(err, &de) && de.RetryAfter > 0 {
+		secs := int((de.RetryAfter + 999_999_999) / 1_000_000_000)
+		w.Header().Set("Retry-After", strconv.Itoa(secs))
+	}
+	writeProblemBody(w, r, p)
+}
+
+// WriteProblemStatus writes a problem with an explicit status and detail,
+// using the default code for the status.
+func WriteProblemStatus(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	writeProblemBody(w, r, Problem{Status: status, Code: StatusCode(status), Detail: detail})
+}
+
+func writeProblemBody(w http.ResponseWriter, r *http.Request, p Problem) {
+	p.Type = "about:blank"
+	p.Title = http.StatusText(p.Status)
+	if r != nil {
+		p.Instance = r.URL.Path
+		p.RequestID = GetRequestID(r.Context())
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+func TestWriteProblem(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantDetail string
+		wantFields int
+	}{
+		{"typed not found", domain.NotFound("share_link_not_found", "share link not found"),
+			http.StatusNotFound, "share_link_not_found", "share link not found", 0},
+		{"cause stays internal", domain.NotFound("task_not_found", "task not found").WithCause(errors.New("secret")),
+			http.StatusNotFound, "task_not_found", "task not found", 0},
+		{"validation with fields", domain.Invalid("invalid request", domain.FieldError{Field: "scope", Message: "bad"}),
+			http.StatusBadRequest, "validation_failed", "invalid request", 1},
+		{"wrapped bare kind", fmt.Errorf("%w: unknown import mode", domain.ErrValidation),
+			http.StatusBadRequest, "bad_request", "validation failed: unknown import mode", 0},
+		{"conflict", domain.Conflict("account_not_empty", "account is not empty"),
+			http.StatusConflict, "account_not_empty", "account is not empty", 0},
+		{"forbidden", domain.Forbidden("admin only"), http.StatusForbidden, "forbidden", "admin only", 0},
+		{"precondition failed", domain.PreconditionFailed("version_mismatch", "record was modified"),
+			http.StatusPreconditionFailed, "version_mismatch", "record was modified", 0},
+		{"no rows", sql.ErrNoRows, http.StatusNotFound, "not_found", "not found", 0},
+		{"unexpected", errors.New("disk on fire"),
+			http.StatusInternalServerError, "internal_error", "internal server error", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				WriteProblem(w, r, tt.err)
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/things/7", nil)
+			req.Header.Set("X-Request-ID", "req-123")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != ProblemContentType {
+				t.Errorf("Expected content type %s, got %s", ProblemContentType, ct)
+			}
+			var p Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+				t.Fatalf("Expected JSON body, got %q: %v", w.Body.String(), err)
+			}
+			if p.Code != tt.wantCode {
+				t.Errorf("Expected code %s, got %s", tt.wantCode, p.Code)
+			}
+			if p.Detail != tt.wantDetail {
+				t.Errorf("Expected detail %q, got %q", tt.wantDetail, p.Detail)
+			}
+			if len(p.Errors) != tt.wantFields {
+				t.Errorf("Expected %d field errors, got %d", tt.wantFields, len(p.Errors))
+			}
+			if p.Status != tt.wantStatus || p.Title != http.StatusText(tt.wantStatus) || p.Type != "about:blank" {
+				t.Errorf("Expected status, title and type to match %d, got %+v", tt.wantStatus, p)
+			}
+			if p.RequestID != "req-123" {
+				t.Errorf("Expected request ID req-123, got %q", p.RequestID)
+			}
+			if p.Instance != "/api/v1/things/7" {
+				t.Errorf("Expected instance /api/v1/things/7, got %q", p.Instance)
+			}
+			if strings.Contains(w.Body.String(), "secret") || strings.Contains(w.Body.String(), "disk on fire") {
+				t.Errorf("Expected internal causes to stay out of the body, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestWriteProblemRetryAfter(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteProblem(w, httptest.NewRequest(http.MethodGet, "/", nil), domain.RateLimited(1500*time.Millisecond))
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status 429, got %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Expected Retry-After 2, got %q", got)
+	}
+}
+
+func TestStatusCode(t *testing.T) {
+	tests := map[int]string{
+		http.StatusBadRequest:            "bad_request",
+		http.StatusNotFound:              "not_found",
+		http.StatusRequestEntityTooLarge: "request_entity_too_large",
+		http.StatusTooManyRequests:       "rate_limited",
+		http.StatusInternalServerError:   "internal_error",
+		999:                              "error",
+	}
+	for status, want := range tests {
+		if got := StatusCode(status); got != want {
+			t.Errorf("StatusCode(%d): Expected %s, got %s", status, want, got)
+		}
+	}
+}
+
+func TestAPIKeyAuthProblem(t *testing.T) {
+	h := APIKeyAuth(APIKeyConfig{Header: "X-API-Key", Key: "k"})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", w.Code)
+	}
+	var p Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil || p.Code != "unauthorized" {
+		t.Errorf("Expected unauthorized problem, got %q (%v)", w.Body.String(), err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-API-Key", "k")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204 with a valid key, got %d", w.Code)
+	}
+}
@@ -0,0 +1,268 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/storage"
+)
+
+// Rate limit headers, after the IETF RateLimit header fields draft.
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+	RateLimitPolicyHeader    = "RateLimit-Policy"
+)
+
+const (
+	rateLimitStoragePrefix = "ratelimit:"
+	// rateLimitLockStripes bounds the per-process locks serializing bucket
+	// updates; keys hashing to the same stripe wait for each other.
+	rateLimitLockStripes = 64
+)
+
+// RateLimitPolicy is a token bucket: a caller can make Limit requests at
+// once, and the bucket refills evenly so that Limit more are allowed per
+// Window. Name keeps the buckets of different policies apart.
+type RateLimitPolicy struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+}
+
+// rate is the refill rate in tokens per second.
+func (p RateLimitPolicy) rate() float64 {
+	return float64(p.Limit) / p.Window.Seconds()
+}
+
+// RateLimitConfig configures the RateLimit middleware.
+type RateLimitConfig struct {
+	// Store holds bucket state; instances sharing a Store share limits.
+	Store  storage.Provider
+	Policy RateLimitPolicy
+	// Key identifies the caller a bucket belongs to, e.g. "user:42".
+	// Requests with an empty key are not limited.
+	Key func(*http.Request) string
+	// Now returns the current time (time.Now if nil).
+	Now func() time.Time
+}
+
+// rateLimitBucket is the stored state of a bucket: the tokens left at At.
+type rateLimitBucket struct {
+	Tokens float64 `json:"tokens"`
+	At     int64   `json:"at"` // Unix nanoseconds
+}
+
+// RateLimit limits requests per caller with cfg.Policy. Every limited
+// response carries RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset
+// (seconds until the bucket is full) and RateLimit-Policy; a request over
+// the limit gets 429 with Retry-After.
+//
+// Bucket updates are serialized per process. Instances sharing a Store may
+// race on the same bucket and together let a few requests over the limit.
+// If the Store fails, requests are let through.
+func RateLimit(cfg RateLimitConfig) func(http.Handler) http.Handler {
+	if cfg.Policy.Limit <= 0 || cfg.Policy.Window <= 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	var locks [rateLimitLockStripes]sync.Mutex
+	policy := fmt.Sprintf("%d;w=%d", cfg.Policy.Limit, int64(math.Ceil(cfg.Policy.Window.Seconds())))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := cfg.Key(r)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			storeKey := rateLimitStoragePrefix + cfg.Policy.Name + ":" + key
+
+			h := fnv.New32a()
+			_, _ = h.Write([]byte(storeKey))
+			mu := &locks[h.Sum32()%rateLimitLockStripes]
+			mu.Lock()
+			bucket, allowed, err := takeToken(r.Context(), cfg, storeKey)
+			mu.Unlock()
+			if err != nil {
+				slog.Warn("rate limit: store unavailable, not limiting", "err", err,
+					"policy", cfg.Policy.Name, "request_id", GetRequestID(r.Context()))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rate := cfg.Policy.rate()
+			header := w.Header()
+			header.Set(RateLimitLimitHeader, strconv.Itoa(cfg.Policy.Limit))
+			header.Set(RateLimitRemainingHeader, strconv.Itoa(int(bucket.Tokens)))
+			header.Set(RateLimitResetHeader, strconv.FormatInt(ceilSeconds((float64(cfg.Policy.Limit)-bucket.Tokens)/rate), 10))
+			header.Set(RateLimitPolicyHeader, policy)
+			if !allowed {
+				wait := time.Duration(ceilSeconds((1-bucket.Tokens)/rate)) * time.Second
+				WriteProblem(w, r, domain.RateLimited(wait))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// takeToken refills the bucket at key for the time since it was last used
+// and takes a token from it if one is left. It returns the bucket as left.
+func takeToken(ctx context.Context, cfg RateLimitConfig, key string) (rateLimitBucket, bool, error) {
+	now := cfg.Now()
+	limit := float64(cfg.Policy.Limit)
+	bucket, err := loadRateLimitBucket(ctx, cfg.Store, key)
+	switch {
+	case errors.Is(err, errNoBucket):
+		bucket = rateLimitBucket{Tokens: limit, At: now.UnixNano()}
+	case err != nil:
+		return bucket, false, err
+	default:
+		elapsed := time.Duration(max(now.UnixNano()-bucket.At, 0))
+		bucket.Tokens = min(limit, bucket.Tokens+elapsed.Seconds()*cfg.Policy.rate())
+		bucket.At = now.UnixNano()
+	}
+	if bucket.Tokens < 1 {
+		return bucket, false, nil
+	}
+	bucket.Tokens--
+
+	// A bucket left alone until it is full again is the same as none, so it
+	// expires then.
+	ttl := time.Duration((limit - bucket.Tokens) / cfg.Policy.rate() * float64(time.Second))
+	ttl = max(ttl, time.Second)
+	raw, err := json.Marshal(bucket)
+	if err != nil {
+		return bucket, false, err
+	}
+	// Count the request even if the client has gone away.
+	return bucket, true, cfg.Store.Save(context.WithoutCancel(ctx), key, string(raw), &ttl)
+}
+
+// errNoBucket reports a bucket that is not stored (or has expired).
+var errNoBucket = errors.New("no rate limit bucket")
+
+// loadRateLimitBucket reads a bucket. Buckets are stored as JSON strings so
+// that they survive providers that serialize values.
+func loadRateLimitBucket(ctx context.Context, store storage.Provider, key string) (rateLimitBucket, error) {
+	var bucket rateLimitBucket
+	v, err := store.Load(ctx, key)
+	if err != nil {
+		var se *storage.Error
+		if errors.As(err, &se) && (se.Code == "KEY_NOT_FOUND" || se.Code == "KEY_EXPIRED") {
+			return bucket, errNoBucket
+		}
+		return bucket, err
+	}
+	var raw []byte
+	switch v := v.(type) {
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return bucket, errNoBucket
+	}
+	if err := json.Unmarshal(raw, &bucket); err != nil {
+		slog.Warn("rate limit: discarding unreadable bucket", "err", err)
+		return bucket, errNoBucket
+	}
+	return bucket, nil
+}
+
+// ceilSeconds rounds a non-negative number of seconds up to a whole second.
+func ceilSeconds(s float64) int64 {
+	return int64(math.Ceil(max(s, 0) - 1e-9))
+}
+
+// ClientIPResolver finds the address of the client that made a request.
+// X-Forwarded-For is only believed when the request comes from a trusted
+// proxy, and then only up to the first hop that is not one.
+type ClientIPResolver struct {
+	trusted []netip.Prefix
+}
+
+// NewClientIPResolver returns a resolver trusting the given proxies, each an
+// IP address or CIDR prefix.
+func NewClientIPResolver(trustedProxies []string) (*ClientIPResolver, error) {
+	c := &ClientIPResolver{}
+	for _, s := range trustedProxies {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if !strings.Contains(s, "/") {
+			addr, err := netip.ParseAddr(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", s, err)
+			}
+			c.trusted = append(c.trusted, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", s, err)
+		}
+		c.trusted = append(c.trusted, prefix.Masked())
+	}
+	return c, nil
+}
+
+// ClientIP returns the client's address: the peer address, or if the peer
+// is a trusted proxy, the nearest untrusted address in X-Forwarded-For.
+func (c *ClientIPResolver) ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	peer, err := netip.ParseAddr(host)
+	if err != nil {
+		return host
+	}
+	peer = peer.Unmap()
+	if !c.isTrusted(peer) {
+		return peer.String()
+	}
+
+	// Walk the chain back from the nearest hop; entries further left were
+	// written by the client and can be forged.
+	client := peer
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		client = addr.Unmap()
+		if !c.isTrusted(client) {
+			break
+		}
+	}
+	return client.String()
+}
+
+func (c *ClientIPResolver) isTrusted(addr netip.Addr) bool {
+	for _, p := range c.trusted {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
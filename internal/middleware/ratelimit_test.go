@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/storage"
+)
+
+func TestRateLimitTokenBucket(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	var clock sync.Mutex
+	store := storage.NewMemoryStorage(storage.Options{})
+	limited := func(name string) http.Handler {
+		return RateLimit(RateLimitConfig{
+			Store:  store,
+			Policy: RateLimitPolicy{Name: name, Limit: 3, Window: time.Minute},
+			Key:    func(r *http.Request) string { return r.Header.Get("X-User") },
+			Now: func() time.Time {
+				clock.Lock()
+				defer clock.Unlock()
+				return now
+			},
+		})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }))
+	}
+	h := limited("api")
+	do := func(h http.Handler, user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-User", user)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 2; i >= 0; i-- {
+		w := do(h, "1")
+		if w.Code != http.StatusNoContent || w.Header().Get(RateLimitRemainingHeader) != strconv.Itoa(i) {
+			t.Fatalf("Expected request allowed with %d remaining, got %d %v", i, w.Code, w.Header())
+		}
+	}
+	w := do(h, "1")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "20" {
+		t.Fatalf("Expected 429 retrying after 20s, got %d %v", w.Code, w.Header())
+	}
+	if w.Header().Get(RateLimitLimitHeader) != "3" || w.Header().Get(RateLimitResetHeader) != "60" ||
+		w.Header().Get(RateLimitPolicyHeader) != "3;w=60" {
+		t.Errorf("Expected RateLimit headers, got %v", w.Header())
+	}
+
+	// Other callers and other policies have their own buckets; instances
+	// sharing the store share them.
+	if w := do(h, "2"); w.Code != http.StatusNoContent {
+		t.Errorf("Expected another user allowed, got %d", w.Code)
+	}
+	if w := do(limited("login"), "1"); w.Code != http.StatusNoContent {
+		t.Errorf("Expected another policy allowed, got %d", w.Code)
+	}
+	if w := do(limited("api"), "1"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected a second instance to share the limit, got %d", w.Code)
+	}
+
+	// The bucket refills a token every 20 seconds.
+	clock.Lock()
+	now = now.Add(20 * time.Second)
+	clock.Unlock()
+	if w := do(h, "1"); w.Code != http.StatusNoContent || w.Header().Get(RateLimitRemainingHeader) != "0" {
+		t.Errorf("Expected one refilled token, got %d %v", w.Code, w.Header())
+	}
+	if w := do(h, "1"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected the refilled token spent, got %d", w.Code)
+	}
+
+	// Concurrent requests never take more than the bucket holds.
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if do(h, "3").Code == http.StatusNoContent {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if allowed.Load() != 3 {
+		t.Errorf("Expected 3 concurrent requests allowed, got %d", allowed.Load())
+	}
+}
+
+func TestRateLimitSQLiteStore(t *testing.T) {
+	// Two instances with their own provider on the same file share limits.
+	path := filepath.Join(t.TempDir(), "ratelimit.db")
+	var handlers []http.Handler
+	for range 2 {
+		store, err := storage.NewSQLiteStorage(path, storage.Options{})
+		if err != nil {
+			t.Fatalf("Failed to open SQLite store: %v", err)
+		}
+		t.Cleanup(func() { _ = store.Close(context.Background()) })
+		handlers = append(handlers, RateLimit(RateLimitConfig{
+			Store:  store,
+			Policy: RateLimitPolicy{Name: "api", Limit: 2, Window: time.Minute},
+			Key:    func(*http.Request) string { return "1" },
+		})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) })))
+	}
+	for i, want := range []int{http.StatusNoContent, http.StatusNoContent, http.StatusTooManyRequests} {
+		w := httptest.NewRecorder()
+		handlers[i%2].ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Code != want {
+			t.Errorf("Request %d: Expected %d, got %d", i+1, want, w.Code)
+		}
+	}
+}
+
+func TestRateLimitDisabled(t *testing.T) {
+	h := RateLimit(RateLimitConfig{Store: storage.NewMemoryStorage(storage.Options{})})(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusNoContent || w.Header().Get(RateLimitLimitHeader) != "" {
+		t.Errorf("Expected a zero policy not to limit, got %d %v", w.Code, w.Header())
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	ips, err := NewClientIPResolver([]string{"10.0.0.0/8", "192.168.1.1"})
+	if err != nil {
+		t.Fatalf("NewClientIPResolver failed: %v", err)
+	}
+	tests := []struct {
+		remote, forwarded string
+		expected          string
+	}{
+		{"203.0.113.7:5000", "", "203.0.113.7"},
+		// Untrusted peers cannot claim another address.
+		{"203.0.113.7:5000", "198.51.100.1", "203.0.113.7"},
+		{"10.1.2.3:443", "198.51.100.1", "198.51.100.1"},
+		// Forged entries left of the nearest untrusted hop are ignored.
+		{"10.1.2.3:443", "1.1.1.1, 198.51.100.1, 192.168.1.1", "198.51.100.1"},
+		{"10.1.2.3:443", "garbage, 10.0.0.9", "10.0.0.9"},
+		{"[::ffff:203.0.113.7]:80", "", "203.0.113.7"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = tt.remote
+		if tt.forwarded != "" {
+			req.Header.Set("X-Forwarded-For", tt.forwarded)
+		}
+		if got := ips.ClientIP(req); got != tt.expected {
+			t.Errorf("%s via %q: Expected %s, got %s", tt.remote, tt.forwarded, tt.expected, got)
+		}
+	}
+
+	if _, err := NewClientIPResolver([]string{"not-an-ip"}); err == nil {
+		t.Error("Expected an invalid proxy to be refused")
+	}
+}
@@ -0,0 +1,112 @@
+package qif
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// DefaultIncomeCategory is written on exported income transactions.
+const DefaultIncomeCategory = "Income"
+
+// Imported holds transactions mapped onto the application's models.
+type Imported struct {
+	Expenses      []domain.Expense
+	IncomeSources []domain.CreateIncomeSourceRequest
+	// Skipped counts transfers and zero-amount lines that carry no budget meaning.
+	Skipped int
+}
+
+// ToDomain maps QIF transactions onto expenses and income sources.
+// Outflows become expenses (one per split line); inflows become income sources,
+// summed per name and month since an income source is a monthly figure.
+func ToDomain(txs []Transaction) Imported {
+	out := Imported{
+		Expenses:      []domain.Expense{},
+		IncomeSources: []domain.CreateIncomeSourceRequest{},
+	}
+	incomeIndex := map[string]int{}
+
+	add := func(tx Transaction, category, memo string, amount domain.Money) {
+		if amount == 0 || IsTransfer(category) {
+			out.Skipped++
+			return
+		}
+		ym := domain.YearMonth{Year: tx.Date.Year(), Month: int(tx.Date.Month())}
+		if amount < 0 {
+			out.Expenses = append(out.Expenses, domain.Expense{
+				YearMonth:   ym,
+				Category:    category,
+				Description: firstNonEmpty(tx.Payee, memo, tx.Memo, category, "QIF import"),
+				AmountCents: -amount,
+				CreatedAt:   tx.Date,
+			})
+			return
+		}
+		name := firstNonEmpty(tx.Payee, category, memo, "QIF import")
+		key := strings.ToLower(name) + "|" + tx.Date.Format("2006-01")
+		if i, ok := incomeIndex[key]; ok {
+			out.IncomeSources[i].AmountCents += amount
+			return
+		}
+		incomeIndex[key] = len(out.IncomeSources)
+		out.IncomeSources = append(out.IncomeSources, domain.CreateIncomeSourceRequest{
+			Name:        name,
+			Year:        ym.Year,
+			Month:       ym.Month,
+			AmountCents: amount,
+		})
+	}
+
+	for _, tx := range txs {
+		if len(tx.Splits) == 0 {
+			add(tx, tx.Category, "", tx.Amount)
+			continue
+		}
+		for _, s := range tx.Splits {
+			add(tx, s.Category, s.Memo, s.Amount)
+		}
+	}
+	return out
+}
+
+// FromMonthlyData turns a month's income sources and expenses into bank
+// transactions. Income is dated on the first of the month; expenses keep
+// their creation day when it falls inside the month.
+func FromMonthlyData(data *domain.MonthlyData) []Transaction {
+	first := time.Date(data.Year, time.Month(data.Month), 1, 0, 0, 0, 0, time.UTC)
+	out := make([]Transaction, 0, len(data.IncomeSources)+len(data.Expenses))
+	for _, src := range data.IncomeSources {
+		out = append(out, Transaction{
+			Account:  AccountBank,
+			Date:     first,
+			Amount:   src.AmountCents,
+			Payee:    src.Name,
+			Category: DefaultIncomeCategory,
+		})
+	}
+	for _, e := range data.Expenses {
+		date := first
+		if e.CreatedAt.Year() == data.Year && int(e.CreatedAt.Month()) == data.Month {
+			date = time.Date(data.Year, time.Month(data.Month), e.CreatedAt.Day(), 0, 0, 0, 0, time.UTC)
+		}
+		out = append(out, Transaction{
+			Account:  AccountBank,
+			Date:     date,
+			Amount:   -e.AmountCents,
+			Payee:    e.Description,
+			Category: e.Category,
+		})
+	}
+	return out
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
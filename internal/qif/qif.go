@@ -0,0 +1,174 @@
+// Package qif reads and writes Quicken Interchange Format (QIF) files and maps
+// their transactions onto the application's expenses and income sources.
+package qif
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// AccountType identifies a QIF section (the value after "!Type:").
+type AccountType string
+
+// Supported account sections.
+const (
+	AccountBank  AccountType = "Bank"
+	AccountCash  AccountType = "Cash"
+	AccountCCard AccountType = "CCard"
+)
+
+// ErrInvalidFormat is returned when a QIF file cannot be parsed.
+var ErrInvalidFormat = errors.New("qif: invalid format")
+
+// Split is one line of a split transaction (S/E/$ fields).
+type Split struct {
+	Category string
+	Memo     string
+	Amount   domain.Money
+}
+
+// Transaction is a single QIF record terminated by "^".
+// Amount is signed: negative for money leaving the account.
+type Transaction struct {
+	Account  AccountType
+	Date     time.Time
+	Amount   domain.Money
+	Payee    string
+	Memo     string
+	Category string
+	Number   string
+	Cleared  string
+	Splits   []Split
+}
+
+// IsTransfer reports whether a category refers to another account ("[Savings]").
+func IsTransfer(category string) bool {
+	return strings.HasPrefix(category, "[") && strings.HasSuffix(category, "]")
+}
+
+// parseAccountType maps a "!Type:" header value to a supported section.
+func parseAccountType(s string) (AccountType, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "bank":
+		return AccountBank, true
+	case "cash":
+		return AccountCash, true
+	case "ccard":
+		return AccountCCard, true
+	default:
+		return "", false
+	}
+}
+
+// parseAmount converts a QIF amount ("-1,234.56", "12", "12,5") to cents
+// without going through floating point.
+func parseAmount(s string) (domain.Money, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "$")
+	if s == "" {
+		return 0, fmt.Errorf("%w: empty amount", ErrInvalidFormat)
+	}
+	neg := false
+	switch s[0] {
+	case '-':
+		neg = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+	// A lone comma is a decimal separator ("12,50"); otherwise commas group thousands.
+	if strings.Contains(s, ",") && !strings.Contains(s, ".") && len(s)-strings.LastIndex(s, ",") <= 3 {
+		s = strings.Replace(s, ",", ".", 1)
+	}
+	s = strings.ReplaceAll(s, ",", "")
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" {
+		whole = "0"
+	}
+	if len(frac) > 2 {
+		return 0, fmt.Errorf("%w: amount %q has more than two decimals", ErrInvalidFormat, s)
+	}
+	for len(frac) < 2 {
+		frac += "0"
+	}
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: amount %q", ErrInvalidFormat, s)
+	}
+	cents, err := strconv.ParseInt(frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: amount %q", ErrInvalidFormat, s)
+	}
+	total := units*100 + cents
+	if neg {
+		total = -total
+	}
+	return domain.Money(total), nil
+}
+
+// formatAmount renders cents as a plain QIF amount ("-12.34").
+func formatAmount(m domain.Money) string {
+	v := int64(m)
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/100, v%100)
+}
+
+// parseDate accepts the usual Quicken spellings: "1/15/2024", "1/15'24",
+// " 1/ 5/24", "01-15-2024" and ISO "2024-01-15". When dayFirst is set the
+// first two fields are read as day/month instead of month/day.
+func parseDate(s string, dayFirst bool) (time.Time, error) {
+	norm := strings.NewReplacer("'", "/", "-", "/", ".", "/", " ", "").Replace(strings.TrimSpace(s))
+	parts := strings.Split(norm, "/")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("%w: date %q", ErrInvalidFormat, s)
+	}
+	nums := make([]int, 3)
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: date %q", ErrInvalidFormat, s)
+		}
+		nums[i] = n
+	}
+
+	var year, month, day int
+	switch {
+	case len(parts[0]) == 4:
+		year, month, day = nums[0], nums[1], nums[2]
+	case dayFirst:
+		day, month, year = nums[0], nums[1], nums[2]
+	default:
+		month, day, year = nums[0], nums[1], nums[2]
+	}
+	if len(parts[2]) <= 2 && len(parts[0]) != 4 {
+		// Quicken writes 2000+ years with an apostrophe; a slash means 19xx
+		// in old files, but a pivot keeps both readable.
+		if year < 70 || strings.Contains(s, "'") {
+			year += 2000
+		} else {
+			year += 1900
+		}
+	}
+	if month < 1 || month > 12 || day < 1 || day > 31 {
+		return time.Time{}, fmt.Errorf("%w: date %q", ErrInvalidFormat, s)
+	}
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if t.Day() != day {
+		return time.Time{}, fmt.Errorf("%w: date %q", ErrInvalidFormat, s)
+	}
+	return t, nil
+}
+
+// formatDate renders a date as MM/DD/YYYY, which every QIF consumer accepts.
+func formatDate(t time.Time) string {
+	return t.Format("01/02/2006")
+}
@@ -0,0 +1,184 @@
+package qif
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+const sampleQIF = `!Type:Bank
+D1/15'24
+T-1,234.56
+PLandlord
+LRent/Home
+^
+D01/31/2024
+T2500.00
+PACME Corp
+LSalary
+^
+D2/3'24
+T-100.00
+PSupermarket
+SGroceries
+EWeekly shop
+$-70.00
+SHousehold
+$-30.00
+^
+D2/4'24
+T-500.00
+L[Savings]
+^
+!Type:Cat
+NGroceries
+E
+^
+!Type:CCard
+D2024-02-10
+U-19.99
+PStreaming
+LSubscriptions
+^
+!Type:Cash
+D2/11/24
+T-5,50
+PCoffee
+^
+`
+
+func TestReadAll(t *testing.T) {
+	txs, err := NewReader(strings.NewReader(sampleQIF)).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if len(txs) != 6 {
+		t.Fatalf("Expected 6 transactions, got %d", len(txs))
+	}
+
+	rent := txs[0]
+	if rent.Account != AccountBank || rent.Amount != -123456 || rent.Category != "Rent" {
+		t.Errorf("Unexpected rent transaction: %+v", rent)
+	}
+	if !rent.Date.Equal(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected 2024-01-15, got %s", rent.Date)
+	}
+	if n := len(txs[2].Splits); n != 2 {
+		t.Fatalf("Expected 2 splits, got %d", n)
+	}
+	if txs[2].Splits[0].Memo != "Weekly shop" || txs[2].Splits[0].Amount != -7000 {
+		t.Errorf("Unexpected split: %+v", txs[2].Splits[0])
+	}
+	if txs[4].Account != AccountCCard || txs[4].Amount != -1999 {
+		t.Errorf("Unexpected credit card transaction: %+v", txs[4])
+	}
+	if txs[5].Account != AccountCash || txs[5].Amount != -550 {
+		t.Errorf("Unexpected cash transaction: %+v", txs[5])
+	}
+}
+
+func TestReadAllInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"bad date", "!Type:Bank\nD13/45/2024\nT-1.00\n^\n"},
+		{"bad amount", "!Type:Bank\nD1/1/2024\nTabc\n^\n"},
+		{"too many decimals", "!Type:Bank\nD1/1/2024\nT1.234\n^\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewReader(strings.NewReader(tt.input)).ReadAll(); err == nil {
+				t.Errorf("Expected error but got none")
+			}
+		})
+	}
+}
+
+func TestParseDateDayFirst(t *testing.T) {
+	r := NewReader(strings.NewReader("!Type:Bank\nD15/01/2024\nT-1.00\n^\n"))
+	r.DayFirst = true
+	txs, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if got := txs[0].Date.Format("2006-01-02"); got != "2024-01-15" {
+		t.Errorf("Expected 2024-01-15, got %s", got)
+	}
+}
+
+func TestToDomain(t *testing.T) {
+	txs, err := NewReader(strings.NewReader(sampleQIF)).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	got := ToDomain(txs)
+
+	// rent, two grocery splits, streaming, coffee
+	if len(got.Expenses) != 5 {
+		t.Fatalf("Expected 5 expenses, got %d", len(got.Expenses))
+	}
+	if got.Skipped != 1 {
+		t.Errorf("Expected 1 skipped transfer, got %d", got.Skipped)
+	}
+	if e := got.Expenses[1]; e.Category != "Groceries" || e.AmountCents != 7000 || e.Description != "Supermarket" {
+		t.Errorf("Unexpected split expense: %+v", e)
+	}
+	if len(got.IncomeSources) != 1 {
+		t.Fatalf("Expected 1 income source, got %d", len(got.IncomeSources))
+	}
+	inc := got.IncomeSources[0]
+	if inc.Name != "ACME Corp" || inc.AmountCents != 250000 || inc.Year != 2024 || inc.Month != 1 {
+		t.Errorf("Unexpected income source: %+v", inc)
+	}
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	data := &domain.MonthlyData{
+		YearMonth: domain.YearMonth{Year: 2024, Month: 3},
+		IncomeSources: []domain.IncomeSource{
+			{Name: "Salary", AmountCents: 300000},
+		},
+		Expenses: []domain.Expense{
+			{
+				Category:    "Food",
+				Description: "Lunch",
+				AmountCents: 1250,
+				CreatedAt:   time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	if err := w.WriteHeader(AccountBank); err != nil {
+		t.Fatalf("WriteHeader failed: %v", err)
+	}
+	for _, tx := range FromMonthlyData(data) {
+		if err := w.Write(tx); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	txs, err := NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	got := ToDomain(txs)
+	if len(got.IncomeSources) != 1 || got.IncomeSources[0].AmountCents != 300000 {
+		t.Errorf("Unexpected income after round trip: %+v", got.IncomeSources)
+	}
+	if len(got.Expenses) != 1 {
+		t.Fatalf("Expected 1 expense, got %d", len(got.Expenses))
+	}
+	e := got.Expenses[0]
+	if e.AmountCents != 1250 || e.Category != "Food" || e.Description != "Lunch" || e.CreatedAt.Day() != 9 {
+		t.Errorf("Unexpected expense after round trip: %+v", e)
+	}
+}
@@ -0,0 +1,146 @@
+package qif
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Reader parses QIF transactions from bank, cash and credit-card sections.
+// Other sections (categories, classes, investments, memorized items) are skipped.
+type Reader struct {
+	// DayFirst reads ambiguous dates as DD/MM/YYYY instead of MM/DD/YYYY.
+	DayFirst bool
+
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewReader returns a Reader consuming r.
+func NewReader(r io.Reader) *Reader {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	return &Reader{scanner: s}
+}
+
+// ReadAll parses every supported transaction in the input.
+//
+//nolint:cyclop,funlen
+func (r *Reader) ReadAll() ([]Transaction, error) {
+	out := []Transaction{}
+	var (
+		section   AccountType
+		supported bool
+		current   Transaction
+		dirty     bool
+	)
+
+	flush := func() {
+		if dirty && supported {
+			current.Account = section
+			out = append(out, current)
+		}
+		current = Transaction{}
+		dirty = false
+	}
+
+	for r.scanner.Scan() {
+		r.line++
+		line := strings.TrimRight(r.scanner.Text(), "\r")
+		if r.line == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if line[0] == '!' {
+			flush()
+			header := strings.TrimSpace(line[1:])
+			if name, ok := strings.CutPrefix(header, "Type:"); ok {
+				section, supported = parseAccountType(name)
+			} else if !strings.HasPrefix(header, "Option:") && !strings.HasPrefix(header, "Clear:") {
+				// "!Account" and friends start a non-transaction block.
+				supported = false
+			}
+			continue
+		}
+
+		code, value := line[0], strings.TrimSpace(line[1:])
+		if code == '^' {
+			flush()
+			continue
+		}
+		if !supported {
+			continue
+		}
+		dirty = true
+
+		if err := r.applyField(&current, code, value); err != nil {
+			return nil, fmt.Errorf("line %d: %w", r.line, err)
+		}
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	// Tolerate a missing final "^".
+	flush()
+	return out, nil
+}
+
+// applyField stores one "<code><value>" line on the transaction being built.
+func (r *Reader) applyField(tx *Transaction, code byte, value string) error {
+	switch code {
+	case 'D':
+		d, err := parseDate(value, r.DayFirst)
+		if err != nil {
+			return err
+		}
+		tx.Date = d
+	case 'T', 'U':
+		amount, err := parseAmount(value)
+		if err != nil {
+			return err
+		}
+		tx.Amount = amount
+	case 'P':
+		tx.Payee = value
+	case 'M':
+		tx.Memo = value
+	case 'L':
+		tx.Category = stripClass(value)
+	case 'N':
+		tx.Number = value
+	case 'C':
+		tx.Cleared = value
+	case 'S':
+		tx.Splits = append(tx.Splits, Split{Category: stripClass(value)})
+	case 'E':
+		if n := len(tx.Splits); n > 0 {
+			tx.Splits[n-1].Memo = value
+		}
+	case '$':
+		if n := len(tx.Splits); n > 0 {
+			amount, err := parseAmount(value)
+			if err != nil {
+				return err
+			}
+			tx.Splits[n-1].Amount = amount
+		}
+	default:
+		// Address lines (A), percentages (%) and other fields carry nothing we store.
+	}
+	return nil
+}
+
+// stripClass drops the "/Class" suffix Quicken appends to categories.
+func stripClass(category string) string {
+	if IsTransfer(category) {
+		return category
+	}
+	if i := strings.Index(category, "/"); i >= 0 {
+		category = category[:i]
+	}
+	return strings.TrimSpace(category)
+}
@@ -0,0 +1,72 @@
+package qif
+
+import (
+	"bufio"
+	"io"
+)
+
+// Writer emits QIF records. Call WriteHeader before the first transaction of
+// each section and Flush when done.
+type Writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+// NewWriter returns a Writer that writes to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// WriteHeader starts a new account section.
+func (w *Writer) WriteHeader(account AccountType) error {
+	w.line("!Type:", string(account))
+	return w.err
+}
+
+// Write emits a single transaction terminated by "^".
+func (w *Writer) Write(tx Transaction) error {
+	w.line("D", formatDate(tx.Date))
+	w.line("T", formatAmount(tx.Amount))
+	if tx.Cleared != "" {
+		w.line("C", tx.Cleared)
+	}
+	if tx.Number != "" {
+		w.line("N", tx.Number)
+	}
+	if tx.Payee != "" {
+		w.line("P", tx.Payee)
+	}
+	if tx.Memo != "" {
+		w.line("M", tx.Memo)
+	}
+	if tx.Category != "" {
+		w.line("L", tx.Category)
+	}
+	for _, s := range tx.Splits {
+		w.line("S", s.Category)
+		if s.Memo != "" {
+			w.line("E", s.Memo)
+		}
+		w.line("$", formatAmount(s.Amount))
+	}
+	w.line("^", "")
+	return w.err
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (w *Writer) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	return w.w.Flush()
+}
+
+// line writes "<code><value>\n", remembering the first error.
+func (w *Writer) line(code, value string) {
+	if w.err != nil {
+		return
+	}
+	if _, err := w.w.WriteString(code + value + "\n"); err != nil {
+		w.err = err
+	}
+}
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// ApplyBatch applies a user's operations in order in one transaction and
+// returns a result per operation. If any operation fails the transaction is
+// rolled back and the error is a *domain.BatchError naming it. Operations are
+// expected to have been validated; each needs the payload for its type.
+func (r *Repository) ApplyBatch(
+	ctx context.Context,
+	userID int64,
+	ops []domain.BatchOperation,
+) ([]domain.BatchResult, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	results := make([]domain.BatchResult, 0, len(ops))
+	changes := make([]domain.Change, 0, len(ops))
+	for i, op := range ops {
+		var res domain.BatchResult
+		var change domain.Change
+		if res, change, err = r.applyBatchOp(ctx, tx, userID, op); err != nil {
+			err = &domain.BatchError{Index: i, Err: err}
+			return nil, err
+		}
+		res.Index, res.Op, res.Type = i, op.Op, op.Type
+		results = append(results, res)
+		changes = append(changes, change)
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	r.notify(ctx, changes...)
+	return results, nil
+}
+
+// batchActions maps batch verbs to change actions.
+var batchActions = map[string]string{
+	domain.BatchCreate: domain.ChangeCreated,
+	domain.BatchUpdate: domain.ChangeUpdated,
+	domain.BatchDelete: domain.ChangeDeleted,
+}
+
+// applyBatchOp applies one operation within tx and returns the change to
+// report once the batch commits.
+func (r *Repository) applyBatchOp(
+	ctx context.Context,
+	tx *sql.Tx,
+	userID int64,
+	op domain.BatchOperation,
+) (domain.BatchResult, domain.Change, error) {
+	res := domain.BatchResult{ID: op.ID}
+	change := domain.Change{UserID: userID, Entity: op.Type, Action: batchActions[op.Op]}
+	var err error
+	switch op.Type + ":" + op.Op {
+	case domain.BatchIncomeSource + ":" + domain.BatchCreate:
+		s := op.IncomeSource
+		change.YearMonth = domain.YearMonth{Year: s.Year, Month: s.Month}
+		res.ID, err = insertSource(ctx, tx, "income_sources", userID, s.Name, change.YearMonth,
+			s.AmountCents, s.DayOfMonth, time.Now())
+		res.Version = 1
+	case domain.BatchBudgetSource + ":" + domain.BatchCreate:
+		s := op.BudgetSource
+		change.YearMonth = domain.YearMonth{Year: s.Year, Month: s.Month}
+		res.ID, err = insertSource(ctx, tx, "budget_sources", userID, s.Name, change.YearMonth,
+			s.AmountCents, s.DayOfMonth, time.Now())
+		res.Version = 1
+	case domain.BatchIncomeSource + ":" + domain.BatchUpdate:
+		res.Version, change.YearMonth, err = r.updateSource(ctx, tx, "income_sources", op.ID, userID, op.Version,
+			*op.Source, domain.ChangeUpdated)
+	case domain.BatchBudgetSource + ":" + domain.BatchUpdate:
+		res.Version, change.YearMonth, err = r.updateSource(ctx, tx, "budget_sources", op.ID, userID, op.Version,
+			*op.Source, domain.ChangeUpdated)
+	case domain.BatchIncomeSource + ":" + domain.BatchDelete:
+		change.YearMonth, err = r.deleteSource(ctx, tx, "income_sources", op.ID, userID, op.Version)
+	case domain.BatchBudgetSource + ":" + domain.BatchDelete:
+		change.YearMonth, err = r.deleteSource(ctx, tx, "budget_sources", op.ID, userID, op.Version)
+	case domain.BatchExpense + ":" + domain.BatchCreate:
+		change.UserID, change.YearMonth = 0, op.Expense.YearMonth
+		res.ID, err = insertExpense(ctx, tx, op.Expense)
+	case domain.BatchExpense + ":" + domain.BatchDelete:
+		change.UserID = 0
+		change.YearMonth, err = deleteExpense(ctx, tx, op.ID)
+	case domain.BatchManualBudgetItem + ":" + domain.BatchCreate:
+		change.YearMonth = domain.YearMonth{Year: op.Item.Year, Month: op.Item.Month}
+		res.ID, res.Version, err = createManualBudgetItem(ctx, tx, userID, *op.Item)
+	case domain.BatchManualBudgetItem + ":" + domain.BatchUpdate:
+		res.Version, change.YearMonth, err = updateManualBudgetItem(ctx, tx, userID, op.ID, *op.Item)
+	case domain.BatchManualBudgetItem + ":" + domain.BatchDelete:
+		res.Version, change.YearMonth, err = deleteManualBudgetItem(ctx, tx, userID, op.ID)
+	default:
+		err = domain.Invalid(fmt.Sprintf("unsupported operation %q on %q", op.Op, op.Type))
+	}
+	change.ID, change.Version = res.ID, res.Version
+	if op.Type == domain.BatchManualBudgetItem {
+		// Item writes change the month's manual budget as a whole.
+		change.Entity, change.Action, change.ID = domain.ChangeManualBudget, domain.ChangeUpdated, 0
+	}
+	return res, change, err
+}
+
+// createManualBudgetItem adds an item to the user's manual budget for the
+// item's month, creating an empty budget if there is none, and returns the
+// item ID and the budget's new version.
+func createManualBudgetItem(
+	ctx context.Context,
+	tx *sql.Tx,
+	userID int64,
+	item domain.ManualBudgetItemRequest,
+) (int64, int64, error) {
+	before, err := manualBudgetSnapshot(ctx, tx, userID, domain.YearMonth{Year: item.Year, Month: item.Month})
+	if err != nil {
+		return 0, 0, err
+	}
+	res, err := tx.ExecContext(ctx,
+		`UPDATE manual_budgets SET version = version + 1, updated_at = CURRENT_TIMESTAMP
+		 WHERE user_id = ? AND year = ? AND month = ?`,
+		userID, item.Year, item.Month)
+	if err != nil {
+		return 0, 0, translateError(err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO manual_budgets(user_id, year, month, bank_amount_cents) VALUES(?, ?, ?, 0)`,
+			userID, item.Year, item.Month); err != nil {
+			return 0, 0, translateError(err)
+		}
+	}
+	var budgetID, version int64
+	if err := tx.QueryRowContext(ctx,
+		`SELECT id, version FROM manual_budgets WHERE user_id = ? AND year = ? AND month = ?`,
+		userID, item.Year, item.Month).Scan(&budgetID, &version); err != nil {
+		return 0, 0, translateError(err)
+	}
+	res, err = tx.ExecContext(ctx,
+		`INSERT INTO manual_budget_items(budget_id, name, amount_cents) VALUES(?, ?, ?)`,
+		budgetID, item.Name, int64(item.AmountCents))
+	if err != nil {
+		return 0, 0, translateError(err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, 0, err
+	}
+	action := domain.ChangeUpdated
+	if before == nil {
+		action = domain.ChangeCreated
+	}
+	return id, version, recordHistory(ctx, tx, domain.ChangeManualBudget, budgetID, action, before)
+}
+
+// updateManualBudgetItem renames or re-prices one of the user's manual budget
+// items and returns its budget's new version and month.
+func updateManualBudgetItem(
+	ctx context.Context,
+	tx *sql.Tx,
+	userID, id int64,
+	item domain.ManualBudgetItemRequest,
+) (int64, domain.YearMonth, error) {
+	budgetID, before, err := manualBudgetItemOwner(ctx, tx, userID, id)
+	if err != nil {
+		return 0, domain.YearMonth{}, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE manual_budget_items SET name = ?, amount_cents = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+		item.Name, int64(item.AmountCents), id); err != nil {
+		return 0, domain.YearMonth{}, translateError(err)
+	}
+	return bumpManualBudgetVersion(ctx, tx, budgetID, before)
+}
+
+// deleteManualBudgetItem moves one of the user's manual budget items to the
+// trash and returns its budget's new version and month.
+func deleteManualBudgetItem(ctx context.Context, tx *sql.Tx, userID, id int64) (int64, domain.YearMonth, error) {
+	budgetID, before, err := manualBudgetItemOwner(ctx, tx, userID, id)
+	if err != nil {
+		return 0, domain.YearMonth{}, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE manual_budget_items SET deleted_at = ? WHERE id = ?`, deletedNow(), id); err != nil {
+		return 0, domain.YearMonth{}, translateError(err)
+	}
+	return bumpManualBudgetVersion(ctx, tx, budgetID, before)
+}
+
+// manualBudgetItemOwner returns the budget holding item id, and the budget as
+// JSON for its history. It returns ErrNotFound for unknown or deleted items
+// and another user's items.
+func manualBudgetItemOwner(ctx context.Context, tx *sql.Tx, userID, id int64) (int64, json.RawMessage, error) {
+	var budgetID, owner int64
+	err := tx.QueryRowContext(ctx,
+		`SELECT b.id, b.user_id FROM manual_budget_items i
+		 JOIN manual_budgets b ON b.id = i.budget_id WHERE i.id = ? AND i.deleted_at IS NULL`, id).Scan(&budgetID, &owner)
+	if err != nil {
+		return 0, nil, translateError(err)
+	}
+	if owner != userID {
+		return 0, nil, errNotOwned
+	}
+	before, err := snapshotBefore(ctx, tx, domain.ChangeManualBudget, budgetID)
+	return budgetID, before, err
+}
+
+// bumpManualBudgetVersion marks a manual budget as changed by an item write,
+// so ETags read before it no longer match, records the write in the budget's
+// history from before, and returns its version and month.
+func bumpManualBudgetVersion(
+	ctx context.Context,
+	tx *sql.Tx,
+	budgetID int64,
+	before json.RawMessage,
+) (int64, domain.YearMonth, error) {
+	var ym domain.YearMonth
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE manual_budgets SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+		budgetID); err != nil {
+		return 0, ym, translateError(err)
+	}
+	var version int64
+	err := tx.QueryRowContext(ctx, `SELECT version, year, month FROM manual_budgets WHERE id = ?`, budgetID).
+		Scan(&version, &ym.Year, &ym.Month)
+	if err != nil {
+		return 0, ym, translateError(err)
+	}
+	return version, ym, recordHistory(ctx, tx, domain.ChangeManualBudget, budgetID, domain.ChangeUpdated, before)
+}
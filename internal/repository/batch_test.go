@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+func TestRepository_ApplyBatch(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+	ym := domain.YearMonth{Year: 2024, Month: 3}
+
+	budget, err := repo.CreateBudgetSource(ctx, 1, domain.CreateBudgetSourceRequest{
+		Name: "Rent", Year: ym.Year, Month: ym.Month, AmountCents: 90000,
+	})
+	if err != nil {
+		t.Fatalf("CreateBudgetSource failed: %v", err)
+	}
+
+	results, err := repo.ApplyBatch(ctx, 1, []domain.BatchOperation{
+		{Op: domain.BatchCreate, Type: domain.BatchIncomeSource, IncomeSource: &domain.CreateIncomeSourceRequest{
+			Name: "Salary", Year: ym.Year, Month: ym.Month, AmountCents: 250000,
+		}},
+		{Op: domain.BatchUpdate, Type: domain.BatchBudgetSource, ID: budget.ID, Version: 1,
+			Source: &domain.UpdateSourceRequest{Name: "Rent", AmountCents: 95000}},
+		{Op: domain.BatchCreate, Type: domain.BatchManualBudgetItem, Item: &domain.ManualBudgetItemRequest{
+			Year: ym.Year, Month: ym.Month, Name: "Groceries", AmountCents: 40000,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("ApplyBatch failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	if results[0].ID == 0 || results[0].Version != 1 {
+		t.Errorf("Expected a new income source at version 1, got %+v", results[0])
+	}
+	if results[1].ID != budget.ID || results[1].Version != 2 {
+		t.Errorf("Expected budget source %d at version 2, got %+v", budget.ID, results[1])
+	}
+	if results[2].Index != 2 || results[2].Version != 1 {
+		t.Errorf("Expected the item's new manual budget at version 1, got %+v", results[2])
+	}
+
+	// Item writes bump the month's manual budget version.
+	itemID := results[2].ID
+	results, err = repo.ApplyBatch(ctx, 1, []domain.BatchOperation{
+		{Op: domain.BatchUpdate, Type: domain.BatchManualBudgetItem, ID: itemID,
+			Item: &domain.ManualBudgetItemRequest{Name: "Food", AmountCents: 45000}},
+	})
+	if err != nil {
+		t.Fatalf("ApplyBatch failed: %v", err)
+	}
+	mb, err := repo.GetManualBudget(ctx, 1, ym)
+	if err != nil {
+		t.Fatalf("GetManualBudget failed: %v", err)
+	}
+	if mb.Version != 2 || results[0].Version != 2 || len(mb.Items) != 1 || mb.Items[0].Name != "Food" {
+		t.Errorf("Expected the renamed item at budget version 2, got %+v (result %+v)", mb, results[0])
+	}
+
+	_, err = repo.ApplyBatch(ctx, 2, []domain.BatchOperation{
+		{Op: domain.BatchDelete, Type: domain.BatchManualBudgetItem, ID: itemID},
+	})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for another user's item, got %v", err)
+	}
+}
+
+func TestRepository_ApplyBatchRollsBack(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+	ym := domain.YearMonth{Year: 2024, Month: 3}
+
+	_, err := repo.ApplyBatch(ctx, 1, []domain.BatchOperation{
+		{Op: domain.BatchCreate, Type: domain.BatchIncomeSource, IncomeSource: &domain.CreateIncomeSourceRequest{
+			Name: "Salary", Year: ym.Year, Month: ym.Month, AmountCents: 250000,
+		}},
+		{Op: domain.BatchDelete, Type: domain.BatchBudgetSource, ID: 999},
+	})
+	var be *domain.BatchError
+	if !errors.As(err, &be) || be.Index != 1 || !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected a not-found BatchError for operation 1, got %v", err)
+	}
+
+	sources, err := repo.ListIncomeSources(ctx, 1, ym)
+	if err != nil {
+		t.Fatalf("ListIncomeSources failed: %v", err)
+	}
+	if len(sources) != 0 {
+		t.Errorf("Expected the failed batch to be rolled back, got %d income sources", len(sources))
+	}
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// Calendar feed tokens
+
+// hashFeedToken returns the stored form of a calendar feed token.
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateCalendarToken issues a new calendar feed token for a user, replacing
+// (and so revoking) any previous one. Only the token's hash is stored.
+func (r *Repository) CreateCalendarToken(ctx context.Context, userID int64) (string, error) {
+	token := generateSessionID()
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO calendar_feeds (user_id, token_hash, created_at) VALUES (?, ?, CURRENT_TIMESTAMP)
+		 ON CONFLICT(user_id) DO UPDATE SET token_hash = excluded.token_hash, created_at = CURRENT_TIMESTAMP`,
+		userID, hashFeedToken(token))
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// DeleteCalendarToken revokes a user's calendar feed token.
+func (r *Repository) DeleteCalendarToken(ctx context.Context, userID int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM calendar_feeds WHERE user_id = ?`, userID)
+	return err
+}
+
+// GetUserIDByCalendarToken resolves a feed token to its user (ErrNotFound if unknown).
+func (r *Repository) GetUserIDByCalendarToken(ctx context.Context, token string) (int64, error) {
+	var userID int64
+	err := r.db.QueryRowContext(ctx,
+		`SELECT user_id FROM calendar_feeds WHERE token_hash = ?`, hashFeedToken(token)).Scan(&userID)
+	return userID, translateError(err)
+}
+
+// ListScheduledPayments returns income and budget sources that have a pay/due
+// day, for months from..to inclusive, ordered by date.
+func (r *Repository) ListScheduledPayments(
+	ctx context.Context,
+	userID int64,
+	from, to domain.YearMonth,
+) ([]domain.ScheduledPayment, error) {
+	lo, hi := from.Year*12+from.Month, to.Year*12+to.Month
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT 'income', id, name, year, month, day_of_month, amount_cents, updated_at
+		 FROM income_sources
+		 WHERE user_id = ? AND day_of_month IS NOT NULL AND deleted_at IS NULL AND year * 12 + month BETWEEN ? AND ?
+		 UNION ALL
+		 SELECT 'budget', id, name, year, month, day_of_month, amount_cents, updated_at
+		 FROM budget_sources
+		 WHERE user_id = ? AND day_of_month IS NOT NULL AND deleted_at IS NULL AND year * 12 + month BETWEEN ? AND ?
+		 ORDER BY 4, 5, 6, 3`,
+		userID, lo, hi, userID, lo, hi)
+	if err != nil {
+		return []domain.ScheduledPayment{}, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	payments := []domain.ScheduledPayment{}
+	for rows.Next() {
+		var p domain.ScheduledPayment
+		var amount int64
+		if err := rows.Scan(&p.Kind, &p.SourceID, &p.Name, &p.Year, &p.Month,
+			&p.DayOfMonth, &amount, &p.UpdatedAt); err != nil {
+			return []domain.ScheduledPayment{}, err
+		}
+		p.AmountCents = domain.Money(amount)
+		payments = append(payments, p)
+	}
+	return payments, rows.Err()
+}
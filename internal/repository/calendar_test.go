@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+func TestRepository_CalendarToken(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	first, err := repo.CreateCalendarToken(ctx, 1)
+	if err != nil {
+		t.Fatalf("CreateCalendarToken failed: %v", err)
+	}
+	if userID, err := repo.GetUserIDByCalendarToken(ctx, first); err != nil || userID != 1 {
+		t.Fatalf("Expected user 1, got %d (%v)", userID, err)
+	}
+
+	var stored string
+	if err := repo.db.QueryRowContext(ctx, `SELECT token_hash FROM calendar_feeds WHERE user_id = 1`).Scan(&stored); err != nil {
+		t.Fatalf("failed to read token hash: %v", err)
+	}
+	if stored == first {
+		t.Error("Expected only the token hash to be stored")
+	}
+
+	// Rotating revokes the previous token.
+	second, err := repo.CreateCalendarToken(ctx, 1)
+	if err != nil {
+		t.Fatalf("CreateCalendarToken failed: %v", err)
+	}
+	if _, err := repo.GetUserIDByCalendarToken(ctx, first); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected rotated token to be rejected, got %v", err)
+	}
+
+	if err := repo.DeleteCalendarToken(ctx, 1); err != nil {
+		t.Fatalf("DeleteCalendarToken failed: %v", err)
+	}
+	if _, err := repo.GetUserIDByCalendarToken(ctx, second); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected revoked token to be rejected, got %v", err)
+	}
+}
+
+func TestRepository_ScheduledPayments(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	day := func(d int) *int { return &d }
+	salary, err := repo.CreateIncomeSource(ctx, 1, domain.CreateIncomeSourceRequest{
+		Name: "Salary", Year: 2024, Month: 3, AmountCents: 250000, DayOfMonth: day(25),
+	})
+	if err != nil {
+		t.Fatalf("CreateIncomeSource failed: %v", err)
+	}
+	if salary.DayOfMonth == nil || *salary.DayOfMonth != 25 {
+		t.Errorf("Expected pay day 25, got %v", salary.DayOfMonth)
+	}
+	rent, err := repo.CreateBudgetSource(ctx, 1, domain.CreateBudgetSourceRequest{
+		Name: "Rent", Year: 2024, Month: 3, AmountCents: 90000, DayOfMonth: day(1),
+	})
+	if err != nil {
+		t.Fatalf("CreateBudgetSource failed: %v", err)
+	}
+	// No day, another user, and outside the window: none of these are listed.
+	_, _ = repo.CreateBudgetSource(ctx, 1, domain.CreateBudgetSourceRequest{
+		Name: "Food", Year: 2024, Month: 3, AmountCents: 40000,
+	})
+	_, _ = repo.CreateIncomeSource(ctx, 2, domain.CreateIncomeSourceRequest{
+		Name: "Other", Year: 2024, Month: 3, AmountCents: 1000, DayOfMonth: day(5),
+	})
+	_, _ = repo.CreateIncomeSource(ctx, 1, domain.CreateIncomeSourceRequest{
+		Name: "Salary", Year: 2025, Month: 1, AmountCents: 250000, DayOfMonth: day(25),
+	})
+
+	from, to := domain.YearMonth{Year: 2024, Month: 1}, domain.YearMonth{Year: 2024, Month: 12}
+	payments, err := repo.ListScheduledPayments(ctx, 1, from, to)
+	if err != nil {
+		t.Fatalf("ListScheduledPayments failed: %v", err)
+	}
+	if len(payments) != 2 {
+		t.Fatalf("Expected 2 scheduled payments, got %d: %+v", len(payments), payments)
+	}
+	if payments[0].Kind != domain.ScheduledBudget || payments[0].SourceID != rent.ID || payments[0].DayOfMonth != 1 {
+		t.Errorf("Expected rent first, got %+v", payments[0])
+	}
+	if payments[1].Kind != domain.ScheduledIncome || payments[1].SourceID != salary.ID {
+		t.Errorf("Expected salary second, got %+v", payments[1])
+	}
+
+	// nil keeps the day, 0 clears it.
+	if _, err := repo.UpdateIncomeSource(ctx, salary.ID, 1, 0, domain.UpdateSourceRequest{
+		Name: "Salary", AmountCents: 260000,
+	}); err != nil {
+		t.Fatalf("UpdateIncomeSource failed: %v", err)
+	}
+	sources, _ := repo.ListIncomeSources(ctx, 1, domain.YearMonth{Year: 2024, Month: 3})
+	if len(sources) != 1 || sources[0].DayOfMonth == nil || *sources[0].DayOfMonth != 25 {
+		t.Errorf("Expected pay day to be kept, got %+v", sources)
+	}
+	if _, err := repo.UpdateIncomeSource(ctx, salary.ID, 1, 0, domain.UpdateSourceRequest{
+		Name: "Salary", AmountCents: 260000, DayOfMonth: day(0),
+	}); err != nil {
+		t.Fatalf("UpdateIncomeSource failed: %v", err)
+	}
+	payments, _ = repo.ListScheduledPayments(ctx, 1, from, to)
+	if len(payments) != 1 {
+		t.Errorf("Expected cleared pay day to drop the event, got %+v", payments)
+	}
+}
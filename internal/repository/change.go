@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// OnChange sets fn to be called after each committed write to a month's
+// sources, expenses or manual budget, in commit order. It must be set before
+// the repository is shared; fn runs on the writing goroutine and should not
+// block.
+func (r *Repository) OnChange(fn func(context.Context, domain.Change)) {
+	r.onChange = fn
+}
+
+// notify reports committed changes to the OnChange callback, if any.
+func (r *Repository) notify(ctx context.Context, changes ...domain.Change) {
+	if r.onChange == nil {
+		return
+	}
+	for _, c := range changes {
+		r.onChange(ctx, c)
+	}
+}
+
+// monthOf returns the month of row id in table, which is always a constant
+// from this package. It returns ErrNotFound for unknown and deleted IDs.
+func monthOf(ctx context.Context, q querier, table string, id int64) (domain.YearMonth, error) {
+	var ym domain.YearMonth
+	err := q.QueryRowContext(ctx, `SELECT year, month FROM `+table+` WHERE id = ? AND deleted_at IS NULL`, id).Scan(&ym.Year, &ym.Month)
+	return ym, translateError(err)
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sqlite "github.com/glebarez/go-sqlite"
+	"github.com/go-sql-driver/mysql"
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// Repository errors. Driver errors are translated into these in one place
+// (translateError), so callers can branch with errors.Is on either database.
+// The original driver error stays in the chain for logging.
+var (
+	ErrNotFound   = domain.ErrNotFound
+	ErrConflict   = domain.ErrConflict
+	ErrForbidden  = domain.ErrForbidden
+	ErrConstraint = fmt.Errorf("constraint violation: %w", domain.ErrValidation)
+	// ErrPreconditionFailed reports a versioned write against a stale version.
+	ErrPreconditionFailed = domain.ErrPreconditionFailed
+)
+
+// SQLite result codes (https://www.sqlite.org/rescode.html).
+const (
+	sqliteConstraint           = 19
+	sqliteConstraintPrimaryKey = 1555
+	sqliteConstraintUnique     = 2067
+)
+
+// MySQL server error numbers.
+const (
+	mysqlDupEntry         = 1062
+	mysqlDupEntryWithKey  = 1586
+	mysqlBadNull          = 1048
+	mysqlNoReferencedRow  = 1216
+	mysqlRowIsReferenced  = 1217
+	mysqlRowIsReferenced2 = 1451
+	mysqlNoReferencedRow2 = 1452
+	mysqlCheckViolated    = 3819
+)
+
+// translateError maps driver errors to repository errors. Errors it does not
+// recognise are returned unchanged.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var de *domain.Error
+	if errors.As(err, &de) {
+		return err
+	}
+	switch classifyError(err) {
+	case ErrNotFound:
+		return &domain.Error{Kind: ErrNotFound, Code: "not_found", Message: "record not found", Err: err}
+	case ErrConflict:
+		return &domain.Error{Kind: ErrConflict, Code: "conflict", Message: "record already exists", Err: err}
+	case ErrConstraint:
+		return &domain.Error{Kind: ErrConstraint, Code: "constraint_violation", Message: "constraint violation", Err: err}
+	}
+	return err
+}
+
+// classifyError returns the repository error kind for a driver error, or nil.
+func classifyError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		switch myErr.Number {
+		case mysqlDupEntry, mysqlDupEntryWithKey:
+			return ErrConflict
+		case mysqlBadNull, mysqlNoReferencedRow, mysqlRowIsReferenced,
+			mysqlRowIsReferenced2, mysqlNoReferencedRow2, mysqlCheckViolated:
+			return ErrConstraint
+		}
+		return nil
+	}
+	var liteErr *sqlite.Error
+	if errors.As(err, &liteErr) {
+		switch code := liteErr.Code(); {
+		case code == sqliteConstraintUnique, code == sqliteConstraintPrimaryKey:
+			return ErrConflict
+		case code&0xff == sqliteConstraint:
+			return ErrConstraint
+		}
+	}
+	return nil
+}
+
+// errNotOwned reports a row that exists but belongs to another user. It is
+// the same not-found error as for a missing row, so that callers cannot tell
+// which IDs exist.
+var errNotOwned = &domain.Error{Kind: ErrNotFound, Code: "not_found", Message: "record not found"}
+
+// checkOwned explains a user-scoped UPDATE or DELETE on table. If it matched
+// no rows, it returns ErrNotFound when the row does not exist, is in the
+// trash or belongs to someone else.
+func (r *Repository) checkOwned(
+	ctx context.Context,
+	q querier,
+	res sql.Result,
+	table string,
+	id, userID int64,
+) error {
+	n, err := res.RowsAffected()
+	if err != nil || n > 0 {
+		return translateError(err)
+	}
+	var owner int64
+	// table is always a constant from this package.
+	err = q.QueryRowContext(ctx, `SELECT user_id FROM `+table+` WHERE id = ? AND deleted_at IS NULL`, id).Scan(&owner)
+	if err != nil {
+		return translateError(err)
+	}
+	if owner != userID {
+		return errNotOwned
+	}
+	// The row is ours but was left unchanged (MySQL reports 0 affected rows
+	// when values do not change).
+	return nil
+}
+
+// errVersionMismatch reports a versioned write whose row has since changed.
+var errVersionMismatch = domain.PreconditionFailed("version_mismatch", "record was modified by another request")
+
+// checkVersioned is checkOwned for writes that bump the version and, when
+// version is non-zero, match on it. Such writes always change the row, so an
+// owned row that was not written has a different version.
+func (r *Repository) checkVersioned(
+	ctx context.Context,
+	q querier,
+	res sql.Result,
+	table string,
+	id, userID, version int64,
+) error {
+	if err := r.checkOwned(ctx, q, res, table, id, userID); err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 && version > 0 {
+		return errVersionMismatch
+	}
+	return nil
+}
+
+// checkAffected returns ErrNotFound if a write matched no rows.
+func checkAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return translateError(err)
+	}
+	if n == 0 {
+		return translateError(sql.ErrNoRows)
+	}
+	return nil
+}
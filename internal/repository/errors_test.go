@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+func TestTranslateErrorMySQL(t *testing.T) {
+	tests := []struct {
+		name   string
+		number uint16
+		want   error
+	}{
+		{"duplicate entry", 1062, ErrConflict},
+		{"duplicate entry with key", 1586, ErrConflict},
+		{"column cannot be null", 1048, ErrConstraint},
+		{"parent row missing", 1452, ErrConstraint},
+		{"row is referenced", 1451, ErrConstraint},
+		{"check constraint", 3819, ErrConstraint},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			driverErr := &mysql.MySQLError{Number: tt.number, Message: "boom"}
+			got := translateError(fmt.Errorf("exec: %w", driverErr))
+			if !errors.Is(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+			var myErr *mysql.MySQLError
+			if !errors.As(got, &myErr) || myErr.Number != tt.number {
+				t.Errorf("Expected the driver error to stay in the chain, got %v", got)
+			}
+		})
+	}
+
+	other := &mysql.MySQLError{Number: 1205, Message: "lock wait timeout"}
+	if got := translateError(other); got != other {
+		t.Errorf("Expected unknown MySQL errors unchanged, got %v", got)
+	}
+}
+
+func TestTranslateErrorSQLite(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	if _, err := repo.CreateUser(ctx, "dup", "secret123", ""); err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	_, err := repo.CreateUser(ctx, "dup", "secret123", "")
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected ErrConflict for a duplicate username, got %v", err)
+	}
+
+	_, err = repo.db.ExecContext(ctx,
+		`INSERT INTO income_sources (user_id, name, year, month, amount_cents) VALUES (1, NULL, 2024, 1, 1)`)
+	if err = translateError(err); !errors.Is(err, ErrConstraint) {
+		t.Errorf("Expected ErrConstraint for a NOT NULL violation, got %v", err)
+	}
+	if !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected ErrConstraint to be a validation error, got %v", err)
+	}
+
+	_, _, err = repo.GetUserByID(ctx, 424242)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unknown user, got %v", err)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows to stay in the chain, got %v", err)
+	}
+
+	if translateError(nil) != nil {
+		t.Error("Expected nil to stay nil")
+	}
+}
+
+func TestRepository_SourceOwnershipErrors(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	income, err := repo.CreateIncomeSource(ctx, 1, domain.CreateIncomeSourceRequest{
+		Name: "Salary", Year: 2024, Month: 1, AmountCents: 100,
+	})
+	if err != nil {
+		t.Fatalf("CreateIncomeSource failed: %v", err)
+	}
+	budget, err := repo.CreateBudgetSource(ctx, 1, domain.CreateBudgetSourceRequest{
+		Name: "Rent", Year: 2024, Month: 1, AmountCents: 100,
+	})
+	if err != nil {
+		t.Fatalf("CreateBudgetSource failed: %v", err)
+	}
+	update := domain.UpdateSourceRequest{Name: "Renamed", AmountCents: 200}
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{"update income of another user", func() error {
+			_, err := repo.UpdateIncomeSource(ctx, income.ID, 2, 0, update)
+			return err
+		}, ErrNotFound},
+		{"update unknown income", func() error {
+			_, err := repo.UpdateIncomeSource(ctx, 999, 1, 0, update)
+			return err
+		}, ErrNotFound},
+		{"delete budget of another user", func() error {
+			return repo.DeleteBudgetSource(ctx, budget.ID, 2, 0)
+		}, ErrNotFound},
+		{"delete unknown budget", func() error {
+			return repo.DeleteBudgetSource(ctx, 999, 1, 0)
+		}, ErrNotFound},
+		{"delete unknown expense", func() error {
+			return repo.DeleteExpense(ctx, 999)
+		}, ErrNotFound},
+		{"update income as owner", func() error {
+			_, err := repo.UpdateIncomeSource(ctx, income.ID, 1, 0, update)
+			return err
+		}, nil},
+		{"delete income as owner", func() error {
+			return repo.DeleteIncomeSource(ctx, income.ID, 1, 0)
+		}, nil},
+		{"delete income twice", func() error {
+			return repo.DeleteIncomeSource(ctx, income.ID, 1, 0)
+		}, ErrNotFound},
+	}
+	for _, tt := range tests {
+		err := tt.call()
+		if tt.want == nil && err != nil {
+			t.Errorf("%s: Expected no error, got %v", tt.name, err)
+		}
+		if tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("%s: Expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestRepository_SourceVersions(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	income, err := repo.CreateIncomeSource(ctx, 1, domain.CreateIncomeSourceRequest{
+		Name: "Salary", Year: 2024, Month: 1, AmountCents: 100,
+	})
+	if err != nil {
+		t.Fatalf("CreateIncomeSource failed: %v", err)
+	}
+	if income.Version != 1 {
+		t.Errorf("Expected new source at version 1, got %d", income.Version)
+	}
+	update := domain.UpdateSourceRequest{Name: "Salary", AmountCents: 200}
+
+	// Two tabs read version 1; the first write wins, the second is stale.
+	v, err := repo.UpdateIncomeSource(ctx, income.ID, 1, 1, update)
+	if err != nil || v != 2 {
+		t.Fatalf("Expected version 2, got %d (%v)", v, err)
+	}
+	if _, err := repo.UpdateIncomeSource(ctx, income.ID, 1, 1, update); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("Expected ErrPreconditionFailed for a stale update, got %v", err)
+	}
+	if err := repo.DeleteIncomeSource(ctx, income.ID, 1, 1); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("Expected ErrPreconditionFailed for a stale delete, got %v", err)
+	}
+	// Ownership still wins over the version check.
+	if _, err := repo.UpdateIncomeSource(ctx, income.ID, 2, 2, update); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	// Unconditional writes still bump and report the version.
+	v, err = repo.UpdateIncomeSource(ctx, income.ID, 1, 0, update)
+	if err != nil || v != 3 {
+		t.Fatalf("Expected version 3, got %d (%v)", v, err)
+	}
+	got, err := repo.GetIncomeSource(ctx, income.ID, 1)
+	if err != nil || got.Version != 3 || got.AmountCents != 200 {
+		t.Fatalf("Expected source at version 3, got %+v (%v)", got, err)
+	}
+	// Another user's source is indistinguishable from a missing one.
+	_, errOther := repo.GetIncomeSource(ctx, income.ID, 2)
+	_, errMissing := repo.GetIncomeSource(ctx, 999, 2)
+	var other, missing *domain.Error
+	if !errors.As(errOther, &other) || !errors.As(errMissing, &missing) ||
+		other.Kind != missing.Kind || other.Code != missing.Code || other.Message != missing.Message {
+		t.Errorf("Expected the same not-found error for another user's source, got %v and %v", errOther, errMissing)
+	}
+	if err := repo.DeleteIncomeSource(ctx, income.ID, 1, 3); err != nil {
+		t.Errorf("Expected delete at the current version to succeed, got %v", err)
+	}
+}
+
+func TestRepository_ManualBudgetVersions(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+	ym := domain.YearMonth{Year: 2024, Month: 1}
+	items := []domain.ManualBudgetItem{{Name: "Rent", AmountCents: -1000}}
+
+	if _, err := repo.UpsertManualBudget(ctx, 1, ym, 1, 0, items); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("Expected ErrPreconditionFailed for an unsaved month, got %v", err)
+	}
+	v, err := repo.UpsertManualBudget(ctx, 1, ym, 0, 500, items)
+	if err != nil || v != 1 {
+		t.Fatalf("Expected version 1, got %d (%v)", v, err)
+	}
+	v, err = repo.UpsertManualBudget(ctx, 1, ym, 1, 600, nil)
+	if err != nil || v != 2 {
+		t.Fatalf("Expected version 2, got %d (%v)", v, err)
+	}
+	if _, err := repo.UpsertManualBudget(ctx, 1, ym, 1, 700, items); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("Expected ErrPreconditionFailed for a stale write, got %v", err)
+	}
+
+	mb, err := repo.GetManualBudget(ctx, 1, ym)
+	if err != nil {
+		t.Fatalf("GetManualBudget failed: %v", err)
+	}
+	if mb.Version != 2 || mb.BankAmountCents != 600 || len(mb.Items) != 0 {
+		t.Errorf("Expected the stale write to leave version 2 untouched, got %+v", mb)
+	}
+}
@@ -0,0 +1,342 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// maxRequestIDLength caps the request IDs stored with history entries; the
+// ID may come from the client.
+const maxRequestIDLength = 64
+
+// errNotRevertible reports a revert to a history entry that deleted its record.
+var errNotRevertible = domain.Conflict("not_revertible", "cannot revert to a deletion; restore the record from the trash")
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx naming the actor that history entries of
+// writes made with it record.
+func WithActor(ctx context.Context, actor domain.Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// actorFrom returns the actor set by WithActor, if any.
+func actorFrom(ctx context.Context) domain.Actor {
+	actor, _ := ctx.Value(actorKey{}).(domain.Actor)
+	return actor
+}
+
+// sourceEntities maps the source tables to their history entities.
+var sourceEntities = map[string]string{
+	"income_sources": domain.BatchIncomeSource,
+	"budget_sources": domain.BatchBudgetSource,
+}
+
+// recordState is a record as its history stores it.
+type recordState struct {
+	data    json.RawMessage
+	owner   sql.NullInt64 // NULL for shared expenses
+	version sql.NullInt64 // NULL for expenses, which have none
+}
+
+// loadRecordState reads a history entity's record, deleted or not. It returns
+// ErrNotFound for unknown records.
+func loadRecordState(ctx context.Context, q querier, entity string, id int64) (recordState, error) {
+	var (
+		st     recordState
+		record any
+		err    error
+	)
+	switch entity {
+	case domain.BatchIncomeSource, domain.BatchBudgetSource:
+		table := "income_sources"
+		if entity == domain.BatchBudgetSource {
+			table = "budget_sources"
+		}
+		var s domain.IncomeSource
+		row := q.QueryRowContext(ctx, `SELECT `+sourceSelectColumns+` FROM `+table+` WHERE id = ?`, id)
+		err = scanSource(row, &s.ID, &s.UserID, &s.Name, &s.YearMonth,
+			&s.AmountCents, &s.DayOfMonth, &s.Version, &s.CreatedAt, &s.UpdatedAt)
+		record = s
+		st.owner = sql.NullInt64{Int64: s.UserID, Valid: true}
+		st.version = sql.NullInt64{Int64: s.Version, Valid: true}
+	case domain.BatchExpense:
+		var e domain.Expense
+		var category sql.NullString
+		var amount int64
+		err = q.QueryRowContext(ctx,
+			`SELECT id, year, month, category, description, amount_cents, created_at FROM expense WHERE id = ?`, id).
+			Scan(&e.ID, &e.Year, &e.Month, &category, &e.Description, &amount, &e.CreatedAt)
+		e.Category, e.AmountCents = category.String, domain.Money(amount)
+		record = e
+	case domain.ChangeManualBudget:
+		var mb *domain.ManualBudget
+		mb, err = loadManualBudget(ctx, q, id)
+		if mb != nil {
+			record = mb
+			st.owner = sql.NullInt64{Int64: mb.UserID, Valid: true}
+			st.version = sql.NullInt64{Int64: mb.Version, Valid: true}
+		}
+	default:
+		return st, domain.Invalid("invalid history entity " + entity)
+	}
+	if err != nil {
+		return st, translateError(err)
+	}
+	st.data, err = json.Marshal(record)
+	return st, err
+}
+
+// loadManualBudget reads a manual budget with its items by ID.
+func loadManualBudget(ctx context.Context, q querier, id int64) (*domain.ManualBudget, error) {
+	mb := &domain.ManualBudget{ID: id, Items: []domain.ManualBudgetItem{}}
+	var bank int64
+	if err := q.QueryRowContext(ctx,
+		`SELECT user_id, year, month, bank_amount_cents, version FROM manual_budgets WHERE id = ?`, id).
+		Scan(&mb.UserID, &mb.Year, &mb.Month, &bank, &mb.Version); err != nil {
+		return nil, err
+	}
+	mb.BankAmountCents = domain.Money(bank)
+	rows, err := q.QueryContext(ctx,
+		`SELECT id, name, amount_cents FROM manual_budget_items WHERE budget_id = ? AND deleted_at IS NULL ORDER BY id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		it := domain.ManualBudgetItem{BudgetID: id}
+		var amount int64
+		if err := rows.Scan(&it.ID, &it.Name, &amount); err != nil {
+			return nil, err
+		}
+		it.AmountCents = domain.Money(amount)
+		mb.Items = append(mb.Items, it)
+	}
+	return mb, rows.Err()
+}
+
+// snapshotBefore returns a record as JSON ahead of a write to it, or nil if
+// there is no such record; the write itself then reports the error.
+func snapshotBefore(ctx context.Context, q querier, entity string, id int64) (json.RawMessage, error) {
+	st, err := loadRecordState(ctx, q, entity, id)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return st.data, err
+}
+
+// recordHistory appends a history entry for a write to a record, from before
+// to the record as it is now. A delete has no after state. q must be the
+// write's transaction: the entry is only given its ID once the transaction
+// holds the record_history_lock row, which it keeps until it ends, so
+// entries commit in ID order and SyncChanges cannot skip one that commits
+// after a pull.
+func recordHistory(
+	ctx context.Context,
+	q querier,
+	entity string,
+	id int64,
+	action string,
+	before json.RawMessage,
+) error {
+	st, err := loadRecordState(ctx, q, entity, id)
+	if err != nil {
+		return err
+	}
+	after := st.data
+	if action == domain.ChangeDeleted {
+		after = nil
+	}
+	if _, err := q.ExecContext(ctx, `UPDATE record_history_lock SET writes = writes + 1 WHERE id = 1`); err != nil {
+		return err
+	}
+	actor := actorFrom(ctx)
+	requestID := actor.RequestID
+	if len(requestID) > maxRequestIDLength {
+		requestID = requestID[:maxRequestIDLength]
+	}
+	_, err = q.ExecContext(ctx,
+		`INSERT INTO record_history
+		 (entity, record_id, user_id, action, version, actor_id, request_id, before_json, after_json, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entity, id, st.owner, action, st.version, nullableID(actor.UserID), nullify(requestID),
+		nullableJSON(before), nullableJSON(after), deletedNow())
+	return translateError(err)
+}
+
+func nullableID(id int64) any {
+	if id <= 0 {
+		return nil
+	}
+	return id
+}
+
+func nullableJSON(data json.RawMessage) any {
+	if data == nil {
+		return nil
+	}
+	return string(data)
+}
+
+// ListHistory returns the history of one of the records userID can see,
+// newest first, with the fields each write changed. It returns ErrNotFound
+// for unknown or purged records and another user's records.
+func (r *Repository) ListHistory(
+	ctx context.Context,
+	userID int64,
+	entity string,
+	id int64,
+) ([]domain.HistoryEntry, error) {
+	st, err := loadRecordState(ctx, r.db, entity, id)
+	if err != nil {
+		return nil, err
+	}
+	if st.owner.Valid && st.owner.Int64 != userID {
+		return nil, errNotOwned
+	}
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT h.id, h.action, h.version, h.actor_id, u.username, h.request_id, h.before_json, h.after_json, h.created_at
+		 FROM record_history h LEFT JOIN users u ON u.id = h.actor_id
+		 WHERE h.entity = ? AND h.record_id = ? ORDER BY h.id DESC`,
+		entity, id)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	entries := []domain.HistoryEntry{}
+	for rows.Next() {
+		e := domain.HistoryEntry{Entity: entity, RecordID: id}
+		var (
+			version, actorID      sql.NullInt64
+			actor, requestID      sql.NullString
+			beforeJSON, afterJSON sql.NullString
+		)
+		if err := rows.Scan(&e.ID, &e.Action, &version, &actorID, &actor, &requestID,
+			&beforeJSON, &afterJSON, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		e.Version, e.ActorID, e.Actor, e.RequestID = version.Int64, actorID.Int64, actor.String, requestID.String
+		if beforeJSON.Valid {
+			e.Before = json.RawMessage(beforeJSON.String)
+		}
+		if afterJSON.Valid {
+			e.After = json.RawMessage(afterJSON.String)
+		}
+		e.Changes = domain.DiffSnapshots(e.Before, e.After)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// RevertRecord sets one of the records userID can see back to its state
+// after history entry entryID, as a new write, and returns its new version
+// (0 for expenses). A non-zero version makes the write conditional on it,
+// like If-Match. It returns ErrNotFound for unknown entries, records in the
+// trash and another user's records, and ErrConflict for an entry that
+// deleted the record.
+func (r *Repository) RevertRecord(
+	ctx context.Context,
+	userID int64,
+	entity string,
+	id, entryID, version int64,
+) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var after sql.NullString
+	err = tx.QueryRowContext(ctx,
+		`SELECT after_json FROM record_history WHERE id = ? AND entity = ? AND record_id = ?`,
+		entryID, entity, id).Scan(&after)
+	if err != nil {
+		err = translateError(err)
+		return 0, err
+	}
+	if !after.Valid {
+		err = errNotRevertible
+		return 0, err
+	}
+
+	change := domain.Change{Entity: entity, Action: domain.ChangeUpdated, ID: id}
+	switch entity {
+	case domain.BatchIncomeSource, domain.BatchBudgetSource:
+		var s domain.IncomeSource
+		if err = json.Unmarshal([]byte(after.String), &s); err != nil {
+			return 0, err
+		}
+		day := 0
+		if s.DayOfMonth != nil {
+			day = *s.DayOfMonth
+		}
+		table := "income_sources"
+		if entity == domain.BatchBudgetSource {
+			table = "budget_sources"
+		}
+		change.UserID = userID
+		change.Version, change.YearMonth, err = r.updateSource(ctx, tx, table, id, userID, version,
+			domain.UpdateSourceRequest{Name: s.Name, AmountCents: s.AmountCents, DayOfMonth: &day},
+			domain.HistoryReverted)
+	case domain.BatchExpense:
+		var e domain.Expense
+		if err = json.Unmarshal([]byte(after.String), &e); err != nil {
+			return 0, err
+		}
+		change.YearMonth, err = revertExpense(ctx, tx, id, e)
+	case domain.ChangeManualBudget:
+		var mb domain.ManualBudget
+		if err = json.Unmarshal([]byte(after.String), &mb); err != nil {
+			return 0, err
+		}
+		var current *domain.ManualBudget
+		if current, err = loadManualBudget(ctx, tx, id); err != nil {
+			err = translateError(err)
+			return 0, err
+		}
+		if current.UserID != userID {
+			err = errNotOwned
+			return 0, err
+		}
+		change = domain.Change{UserID: userID, YearMonth: current.YearMonth,
+			Entity: domain.ChangeManualBudget, Action: domain.ChangeUpdated}
+		_, change.Version, err = r.saveManualBudget(ctx, tx, userID, current.YearMonth, version,
+			mb.BankAmountCents, mb.Items, domain.HistoryReverted)
+	default:
+		err = domain.Invalid("invalid history entity " + entity)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	r.notify(ctx, change)
+	return change.Version, nil
+}
+
+// revertExpense rewrites a live expense from a snapshot and returns its month.
+func revertExpense(ctx context.Context, tx *sql.Tx, id int64, e domain.Expense) (domain.YearMonth, error) {
+	before, err := snapshotBefore(ctx, tx, domain.BatchExpense, id)
+	if err != nil {
+		return domain.YearMonth{}, err
+	}
+	if _, err := monthOf(ctx, tx, "expense", id); err != nil {
+		return domain.YearMonth{}, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE expense SET year = ?, month = ?, category = ?, description = ?, amount_cents = ? WHERE id = ?`,
+		e.Year, e.Month, nullify(e.Category), e.Description, int64(e.AmountCents), id); err != nil {
+		return domain.YearMonth{}, translateError(err)
+	}
+	return e.YearMonth, recordHistory(ctx, tx, domain.BatchExpense, id, domain.HistoryReverted, before)
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+func TestRepository_SourceHistory(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo.db.SetMaxOpenConns(1)
+	ctx := WithActor(context.Background(), domain.Actor{UserID: 1, RequestID: "req-1"})
+
+	day := 1
+	rent, err := repo.CreateBudgetSource(ctx, 1, domain.CreateBudgetSourceRequest{
+		Name: "Rent", Year: 2024, Month: 3, AmountCents: 95000, DayOfMonth: &day,
+	})
+	if err != nil {
+		t.Fatalf("CreateBudgetSource failed: %v", err)
+	}
+	if _, err := repo.UpdateBudgetSource(ctx, rent.ID, 1, 0, domain.UpdateSourceRequest{
+		Name: "Rent", AmountCents: 97600, DayOfMonth: &day,
+	}); err != nil {
+		t.Fatalf("UpdateBudgetSource failed: %v", err)
+	}
+	if err := repo.DeleteBudgetSource(ctx, rent.ID, 1, 0); err != nil {
+		t.Fatalf("DeleteBudgetSource failed: %v", err)
+	}
+	if err := repo.RestoreTrashItem(ctx, 1, domain.BatchBudgetSource, rent.ID); err != nil {
+		t.Fatalf("RestoreTrashItem failed: %v", err)
+	}
+
+	entries, err := repo.ListHistory(ctx, 1, domain.BatchBudgetSource, rent.ID)
+	if err != nil {
+		t.Fatalf("ListHistory failed: %v", err)
+	}
+	actions := make([]string, len(entries))
+	for i, e := range entries {
+		actions[i] = e.Action
+	}
+	want := []string{domain.ChangeRestored, domain.ChangeDeleted, domain.ChangeUpdated, domain.ChangeCreated}
+	if len(actions) != len(want) {
+		t.Fatalf("Expected actions %v, got %v", want, actions)
+	}
+	for i := range want {
+		if actions[i] != want[i] {
+			t.Fatalf("Expected actions %v, got %v", want, actions)
+		}
+	}
+	update := entries[2]
+	if update.ActorID != 1 || update.Actor != "admin" || update.RequestID != "req-1" || update.Version != 2 {
+		t.Errorf("Expected the update by admin in req-1 at version 2, got %+v", update)
+	}
+	if len(update.Changes) != 1 || update.Changes[0].Field != "amount_cents" ||
+		string(update.Changes[0].Before) != "95000" || string(update.Changes[0].After) != "97600" {
+		t.Errorf("Expected only the amount to change, got %+v", update.Changes)
+	}
+	if entries[1].After != nil || entries[3].Before != nil {
+		t.Errorf("Expected no state after the delete or before the create, got %+v", entries)
+	}
+
+	// Reverting to the create is a new write, conditional on the version.
+	_, err = repo.RevertRecord(ctx, 1, domain.BatchBudgetSource, rent.ID, entries[3].ID, 1)
+	if !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("Expected ErrPreconditionFailed for a stale version, got %v", err)
+	}
+	version, err := repo.RevertRecord(ctx, 1, domain.BatchBudgetSource, rent.ID, entries[3].ID, 0)
+	if err != nil {
+		t.Fatalf("RevertRecord failed: %v", err)
+	}
+	reverted, err := repo.GetBudgetSource(ctx, rent.ID, 1)
+	if err != nil || reverted.AmountCents != 95000 || reverted.Version != version {
+		t.Errorf("Expected the original amount at version %d, got %+v (%v)", version, reverted, err)
+	}
+	_, err = repo.RevertRecord(ctx, 1, domain.BatchBudgetSource, rent.ID, entries[1].ID, 0)
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected ErrConflict reverting to a delete, got %v", err)
+	}
+	entries, _ = repo.ListHistory(ctx, 1, domain.BatchBudgetSource, rent.ID)
+	if len(entries) != 5 || entries[0].Action != domain.HistoryReverted {
+		t.Errorf("Expected the revert recorded, got %+v", entries)
+	}
+
+	other, err := repo.CreateUser(ctx, "other", "password123", "other@example.com")
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	if _, err := repo.ListHistory(ctx, other.ID, domain.BatchBudgetSource, rent.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound reading another user's history, got %v", err)
+	}
+}
+
+func TestRepository_ManualBudgetHistory(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+	march := domain.YearMonth{Year: 2024, Month: 3}
+
+	if _, err := repo.UpsertManualBudget(ctx, 1, march, 0, 1000, []domain.ManualBudgetItem{
+		{Name: "Gym", AmountCents: 3000},
+	}); err != nil {
+		t.Fatalf("UpsertManualBudget failed: %v", err)
+	}
+	if _, err := repo.UpsertManualBudget(ctx, 1, march, 0, 1500, []domain.ManualBudgetItem{
+		{Name: "Gym", AmountCents: 3000}, {Name: "Phone", AmountCents: 2000},
+	}); err != nil {
+		t.Fatalf("UpsertManualBudget failed: %v", err)
+	}
+	mb, err := repo.GetManualBudget(ctx, 1, march)
+	if err != nil {
+		t.Fatalf("GetManualBudget failed: %v", err)
+	}
+	entries, err := repo.ListHistory(ctx, 1, domain.ChangeManualBudget, mb.ID)
+	if err != nil {
+		t.Fatalf("ListHistory failed: %v", err)
+	}
+	if len(entries) != 2 || entries[1].Action != domain.ChangeCreated || len(entries[0].Changes) != 2 {
+		t.Fatalf("Expected the create and an update of the bank amount and items, got %+v", entries)
+	}
+
+	if _, err := repo.RevertRecord(ctx, 1, domain.ChangeManualBudget, mb.ID, entries[1].ID, mb.Version); err != nil {
+		t.Fatalf("RevertRecord failed: %v", err)
+	}
+	reverted, err := repo.GetManualBudget(ctx, 1, march)
+	if err != nil {
+		t.Fatalf("GetManualBudget failed: %v", err)
+	}
+	if reverted.BankAmountCents != 1000 || len(reverted.Items) != 1 || reverted.Items[0].ID != mb.Items[0].ID {
+		t.Errorf("Expected the first save back with its gym item, got %+v", reverted)
+	}
+}
@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// Page sizes for list queries. A query with neither a limit nor a cursor is
+// not paged and returns every row, as the lists did before they were paged;
+// one with a cursor but no limit returns DefaultListLimit rows.
+const (
+	DefaultListLimit = 100
+	MaxListLimit     = 500
+)
+
+// listColumns maps ListQuery sorting and filters onto a table. An empty
+// column means the sort or filter is not supported there.
+type listColumns struct {
+	date     string // integer expression ordered by domain.SortDate
+	amount   string
+	name     string
+	category string
+	search   []string
+	period   string // year*100+month expression for period filters
+	created  string // timestamp column for period filters when period is ""
+}
+
+var (
+	expenseColumns = listColumns{
+		date: "year*100+month", amount: "amount_cents", name: "description", category: "category",
+		search: []string{"description", "category"}, period: "year*100+month",
+	}
+	sourceColumns = listColumns{
+		date: "year*100+month", amount: "amount_cents", name: "name",
+		search: []string{"name"}, period: "year*100+month",
+	}
+	// Users sort by sign-up order for "date"; IDs follow created_at.
+	userColumns = listColumns{
+		date: "id", name: "username", search: []string{"username", "email"}, created: "created_at",
+	}
+)
+
+// listKey returns the sort values of a row: its date key, amount, name and ID.
+type listKey[T any] func(item T) (date, amount int64, name string, id int64)
+
+func unsupported(field string) error {
+	return domain.Invalid("unsupported list parameter", domain.FieldError{Field: field, Message: "not supported here"})
+}
+
+// sortKey returns the expression for a sort and whether it is textual.
+func (c listColumns) sortKey(sort string) (string, bool, error) {
+	var expr string
+	switch sort {
+	case domain.SortDate:
+		expr = c.date
+	case domain.SortAmount:
+		expr = c.amount
+	case domain.SortName:
+		expr = c.name
+	}
+	if expr == "" {
+		return "", false, unsupported("sort")
+	}
+	return expr, sort == domain.SortName, nil
+}
+
+// build appends q's filters, keyset condition, order and limit to base, which
+// must end in a WHERE clause. It fetches one row more than the limit so the
+// caller can tell whether there is a next page.
+func (c listColumns) build(base string, args []any, q *domain.ListQuery) (string, []any, error) {
+	if q.Sort == "" {
+		q.Sort, q.Desc = domain.SortDate, true
+	}
+	if q.Limit <= 0 && q.After != nil {
+		q.Limit = DefaultListLimit
+	}
+	q.Limit = min(q.Limit, MaxListLimit)
+	key, text, err := c.sortKey(q.Sort)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var b strings.Builder
+	b.WriteString(base)
+	where := func(cond string, a ...any) {
+		b.WriteString(" AND " + cond)
+		args = append(args, a...)
+	}
+
+	if q.Year != 0 {
+		from, to := domain.YearMonth{Year: q.Year, Month: 1}, domain.YearMonth{Year: q.Year, Month: 12}
+		if q.Month != 0 {
+			from.Month, to.Month = q.Month, q.Month
+		}
+		c.periodRange(where, &from, &to)
+	}
+	c.periodRange(where, q.From, q.To)
+
+	if q.Category != "" {
+		if c.category == "" {
+			return "", nil, unsupported("category")
+		}
+		where(c.category+" = ?", q.Category)
+	}
+	if q.MinAmount != nil || q.MaxAmount != nil {
+		if c.amount == "" {
+			return "", nil, unsupported("min_amount")
+		}
+		if q.MinAmount != nil {
+			where(c.amount+" >= ?", int64(*q.MinAmount))
+		}
+		if q.MaxAmount != nil {
+			where(c.amount+" <= ?", int64(*q.MaxAmount))
+		}
+	}
+	if q.Search != "" {
+		pattern := "%" + escapeLike(strings.ToLower(q.Search)) + "%"
+		conds := make([]string, len(c.search))
+		for i, col := range c.search {
+			conds[i] = "LOWER(" + col + ") LIKE ? ESCAPE '!'"
+			args = append(args, pattern)
+		}
+		b.WriteString(" AND (" + strings.Join(conds, " OR ") + ")")
+	}
+
+	op, dir := ">", "ASC"
+	if q.Desc {
+		op, dir = "<", "DESC"
+	}
+	if a := q.After; a != nil {
+		if a.Sort != q.Sort || a.Desc != q.Desc {
+			return "", nil, domain.Invalid("cursor does not match sort",
+				domain.FieldError{Field: "cursor", Message: "was issued for a different sort"})
+		}
+		var v any = a.Int
+		if text {
+			v = a.Str
+		}
+		where(fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", key, op), v, v, a.ID)
+	}
+	fmt.Fprintf(&b, " ORDER BY %[1]s %[2]s, id %[2]s", key, dir)
+	if q.Limit <= 0 {
+		return b.String(), args, nil
+	}
+	return b.String() + " LIMIT ?", append(args, q.Limit+1), nil
+}
+
+// periodRange filters on an inclusive range of months.
+func (c listColumns) periodRange(where func(string, ...any), from, to *domain.YearMonth) {
+	if c.period != "" {
+		if from != nil {
+			where(c.period+" >= ?", from.Year*100+from.Month)
+		}
+		if to != nil {
+			where(c.period+" <= ?", to.Year*100+to.Month)
+		}
+		return
+	}
+	if from != nil {
+		where(c.created+" >= ?", monthStart(from.Year, from.Month))
+	}
+	if to != nil {
+		where(c.created+" < ?", monthStart(to.Year, to.Month+1))
+	}
+}
+
+// monthStart formats the first day of a month (month 13 rolls over).
+func monthStart(year, month int) string {
+	if month > 12 {
+		year, month = year+1, month-12
+	}
+	return fmt.Sprintf("%04d-%02d-01", year, month)
+}
+
+// escapeLike escapes LIKE wildcards for ESCAPE '!'.
+func escapeLike(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+}
+
+// listPage runs a list query built by listColumns.build and cuts the extra
+// row into a next-page cursor.
+func listPage[T any](
+	ctx context.Context,
+	db *sql.DB,
+	query string,
+	args []any,
+	q domain.ListQuery,
+	scan func(*sql.Rows) (T, error),
+	key listKey[T],
+) (domain.Page[T], error) {
+	page := domain.Page[T]{Items: []T{}}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return page, translateError(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return domain.Page[T]{Items: []T{}}, err
+		}
+		page.Items = append(page.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.Page[T]{Items: []T{}}, err
+	}
+
+	if q.Limit > 0 && len(page.Items) > q.Limit {
+		page.Items = page.Items[:q.Limit]
+		date, amount, name, id := key(page.Items[q.Limit-1])
+		c := domain.Cursor{Sort: q.Sort, Desc: q.Desc, ID: id}
+		switch q.Sort {
+		case domain.SortDate:
+			c.Int = date
+		case domain.SortAmount:
+			c.Int = amount
+		default:
+			c.Str = name
+		}
+		page.NextCursor = c.Encode()
+	}
+	return page, nil
+}
+
+// ListExpensesPage lists expenses with pagination, sorting and filters.
+func (r *Repository) ListExpensesPage(ctx context.Context, q domain.ListQuery) (domain.Page[domain.Expense], error) {
+	query, args, err := expenseColumns.build(
+		`SELECT id, year, month, category, description, amount_cents, created_at FROM expense WHERE deleted_at IS NULL`, nil, &q)
+	if err != nil {
+		return domain.Page[domain.Expense]{Items: []domain.Expense{}}, err
+	}
+	return listPage(ctx, r.db, query, args, q, scanExpense,
+		func(e domain.Expense) (int64, int64, string, int64) {
+			return int64(e.Year*100 + e.Month), int64(e.AmountCents), e.Description, e.ID
+		})
+}
+
+// ListIncomeSourcesPage lists a user's income sources with pagination, sorting and filters.
+func (r *Repository) ListIncomeSourcesPage(
+	ctx context.Context,
+	userID int64,
+	q domain.ListQuery,
+) (domain.Page[domain.IncomeSource], error) {
+	query, args, err := sourceColumns.build(
+		`SELECT `+sourceSelectColumns+` FROM income_sources WHERE user_id = ? AND deleted_at IS NULL`, []any{userID}, &q)
+	if err != nil {
+		return domain.Page[domain.IncomeSource]{Items: []domain.IncomeSource{}}, err
+	}
+	return listPage(ctx, r.db, query, args, q,
+		func(rows *sql.Rows) (domain.IncomeSource, error) {
+			var s domain.IncomeSource
+			err := scanSource(rows, &s.ID, &s.UserID, &s.Name, &s.YearMonth, &s.AmountCents,
+				&s.DayOfMonth, &s.Version, &s.CreatedAt, &s.UpdatedAt)
+			return s, err
+		},
+		func(s domain.IncomeSource) (int64, int64, string, int64) {
+			return int64(s.Year*100 + s.Month), int64(s.AmountCents), s.Name, s.ID
+		})
+}
+
+// ListBudgetSourcesPage lists a user's budget sources with pagination, sorting and filters.
+func (r *Repository) ListBudgetSourcesPage(
+	ctx context.Context,
+	userID int64,
+	q domain.ListQuery,
+) (domain.Page[domain.BudgetSource], error) {
+	query, args, err := sourceColumns.build(
+		`SELECT `+sourceSelectColumns+` FROM budget_sources WHERE user_id = ? AND deleted_at IS NULL`, []any{userID}, &q)
+	if err != nil {
+		return domain.Page[domain.BudgetSource]{Items: []domain.BudgetSource{}}, err
+	}
+	return listPage(ctx, r.db, query, args, q,
+		func(rows *sql.Rows) (domain.BudgetSource, error) {
+			var s domain.BudgetSource
+			err := scanSource(rows, &s.ID, &s.UserID, &s.Name, &s.YearMonth, &s.AmountCents,
+				&s.DayOfMonth, &s.Version, &s.CreatedAt, &s.UpdatedAt)
+			return s, err
+		},
+		func(s domain.BudgetSource) (int64, int64, string, int64) {
+			return int64(s.Year*100 + s.Month), int64(s.AmountCents), s.Name, s.ID
+		})
+}
+
+// ListUsersPage lists users with pagination, sorting and filters. Amount and
+// category filters are not supported.
+func (r *Repository) ListUsersPage(ctx context.Context, q domain.ListQuery) (domain.Page[domain.User], error) {
+	builder := NewUserQueryBuilder(r.hasIsAdmin, r.hasStatus)
+	base, args := builder.selectFrom()+" WHERE 1=1", []any{}
+	if q.Status != "" && r.hasStatus {
+		base += " AND status = ?"
+		args = append(args, q.Status)
+	}
+	query, args, err := userColumns.build(base, args, &q)
+	if err != nil {
+		return domain.Page[domain.User]{Items: []domain.User{}}, err
+	}
+	scanner := NewUserRowScanner(r.hasIsAdmin, r.hasStatus)
+	return listPage(ctx, r.db, query, args, q, scanner.ScanRow,
+		func(u domain.User) (int64, int64, string, int64) {
+			return u.ID, 0, u.Username, u.ID
+		})
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+func TestRepository_ListExpensesPage(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	seed := []domain.Expense{
+		{YearMonth: domain.YearMonth{Year: 2024, Month: 1}, Category: "food", Description: "Bakery", AmountCents: 300},
+		{YearMonth: domain.YearMonth{Year: 2024, Month: 1}, Category: "food", Description: "Market 100%", AmountCents: 1200},
+		{YearMonth: domain.YearMonth{Year: 2024, Month: 2}, Category: "rent", Description: "Rent", AmountCents: 90000},
+		{YearMonth: domain.YearMonth{Year: 2024, Month: 3}, Category: "food", Description: "Café", AmountCents: 450},
+		{YearMonth: domain.YearMonth{Year: 2024, Month: 3}, Category: "fun", Description: "Cinema", AmountCents: 1200},
+	}
+	for i := range seed {
+		if _, err := repo.AddExpense(ctx, &seed[i]); err != nil {
+			t.Fatalf("AddExpense failed: %v", err)
+		}
+	}
+
+	// Walk every page of an amount sort with ties and check nothing repeats.
+	var seen []string
+	q := domain.ListQuery{Limit: 2, Sort: domain.SortAmount, Desc: true}
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("Expected pagination to terminate")
+		}
+		page, err := repo.ListExpensesPage(ctx, q)
+		if err != nil {
+			t.Fatalf("ListExpensesPage failed: %v", err)
+		}
+		for _, e := range page.Items {
+			seen = append(seen, e.Description)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		q.After, err = domain.DecodeCursor(page.NextCursor)
+		if err != nil {
+			t.Fatalf("DecodeCursor failed: %v", err)
+		}
+	}
+	want := []string{"Rent", "Cinema", "Market 100%", "Café", "Bakery"}
+	if len(seen) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, seen)
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, seen)
+			break
+		}
+	}
+
+	minAmount, maxAmount := domain.Money(400), domain.Money(1500)
+	tests := []struct {
+		name string
+		q    domain.ListQuery
+		want []string
+	}{
+		{"category", domain.ListQuery{Category: "food", Sort: domain.SortName}, []string{"Bakery", "Café", "Market 100%"}},
+		{"amount range", domain.ListQuery{MinAmount: &minAmount, MaxAmount: &maxAmount, Sort: domain.SortName},
+			[]string{"Café", "Cinema", "Market 100%"}},
+		{"search is case-insensitive", domain.ListQuery{Search: "CIN", Sort: domain.SortName}, []string{"Cinema"}},
+		{"search escapes wildcards", domain.ListQuery{Search: "100%", Sort: domain.SortName}, []string{"Market 100%"}},
+		{"exact month", domain.ListQuery{Year: 2024, Month: 2}, []string{"Rent"}},
+		{"period range", domain.ListQuery{
+			From: &domain.YearMonth{Year: 2024, Month: 2}, To: &domain.YearMonth{Year: 2024, Month: 3}, Sort: domain.SortDate,
+		}, []string{"Rent", "Café", "Cinema"}},
+	}
+	for _, tt := range tests {
+		page, err := repo.ListExpensesPage(ctx, tt.q)
+		if err != nil {
+			t.Errorf("%s: ListExpensesPage failed: %v", tt.name, err)
+			continue
+		}
+		got := make([]string, 0, len(page.Items))
+		for _, e := range page.Items {
+			got = append(got, e.Description)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: Expected %v, got %v", tt.name, tt.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: Expected %v, got %v", tt.name, tt.want, got)
+				break
+			}
+		}
+		if page.NextCursor != "" {
+			t.Errorf("%s: Expected no next page, got cursor %q", tt.name, page.NextCursor)
+		}
+	}
+
+	// A cursor issued for one sort cannot be used with another.
+	page, err := repo.ListExpensesPage(ctx, domain.ListQuery{Limit: 1, Sort: domain.SortName})
+	if err != nil || page.NextCursor == "" {
+		t.Fatalf("Expected a next page, got %v (%v)", page.NextCursor, err)
+	}
+	after, _ := domain.DecodeCursor(page.NextCursor)
+	if _, err := repo.ListExpensesPage(ctx, domain.ListQuery{After: after, Sort: domain.SortAmount}); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error for a mismatched cursor, got %v", err)
+	}
+
+	// Without a limit or cursor the list is not paged; a cursor alone pages
+	// by DefaultListLimit.
+	for i := len(seed); i <= DefaultListLimit; i++ {
+		if _, err := repo.AddExpense(ctx, &domain.Expense{
+			YearMonth: domain.YearMonth{Year: 2023, Month: 1}, Category: "misc", Description: "Filler", AmountCents: 1,
+		}); err != nil {
+			t.Fatalf("AddExpense failed: %v", err)
+		}
+	}
+	page, err = repo.ListExpensesPage(ctx, domain.ListQuery{Sort: domain.SortName})
+	if err != nil || len(page.Items) != DefaultListLimit+1 || page.NextCursor != "" {
+		t.Errorf("Expected all %d expenses unpaged, got %d (%v)", DefaultListLimit+1, len(page.Items), err)
+	}
+	page, err = repo.ListExpensesPage(ctx, domain.ListQuery{After: &domain.Cursor{Sort: domain.SortName}, Sort: domain.SortName})
+	if err != nil || len(page.Items) != DefaultListLimit || page.NextCursor == "" {
+		t.Errorf("Expected a page of %d after a cursor, got %d (%v)", DefaultListLimit, len(page.Items), err)
+	}
+}
+
+func TestRepository_ListSourcesAndUsersPage(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	for _, name := range []string{"Salary", "Bonus", "Dividends"} {
+		if _, err := repo.CreateIncomeSource(ctx, 1, domain.CreateIncomeSourceRequest{
+			Name: name, Year: 2024, Month: 1, AmountCents: 100,
+		}); err != nil {
+			t.Fatalf("CreateIncomeSource failed: %v", err)
+		}
+	}
+	other, err := repo.CreateUser(ctx, "carol", "secret123", "")
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	if _, err := repo.CreateIncomeSource(ctx, other.ID, domain.CreateIncomeSourceRequest{
+		Name: "Other user", Year: 2024, Month: 1, AmountCents: 100,
+	}); err != nil {
+		t.Fatalf("CreateIncomeSource failed: %v", err)
+	}
+
+	page, err := repo.ListIncomeSourcesPage(ctx, 1, domain.ListQuery{Sort: domain.SortName})
+	if err != nil {
+		t.Fatalf("ListIncomeSourcesPage failed: %v", err)
+	}
+	if len(page.Items) != 3 || page.Items[0].Name != "Bonus" || page.Items[2].Name != "Salary" {
+		t.Errorf("Expected the user's three sources by name, got %+v", page.Items)
+	}
+	if _, err := repo.ListIncomeSourcesPage(ctx, 1, domain.ListQuery{Category: "x"}); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected category to be rejected for sources, got %v", err)
+	}
+
+	for _, name := range []string{"alice", "bob"} {
+		if _, err := repo.CreateUser(ctx, name, "secret123", name+"@example.com"); err != nil {
+			t.Fatalf("CreateUser failed: %v", err)
+		}
+	}
+	users, err := repo.ListUsersPage(ctx, domain.ListQuery{Search: "example.com", Sort: domain.SortName, Desc: true})
+	if err != nil {
+		t.Fatalf("ListUsersPage failed: %v", err)
+	}
+	if len(users.Items) != 2 || users.Items[0].Username != "bob" {
+		t.Errorf("Expected bob then alice, got %+v", users.Items)
+	}
+	if _, err := repo.ListUsersPage(ctx, domain.ListQuery{Sort: domain.SortAmount}); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected amount sort to be rejected for users, got %v", err)
+	}
+}
@@ -143,7 +143,7 @@ func (cbs *CreateBudgetStrategy) Execute(
 	t.Helper()
 	userID, ym, bankAmount, items := cbs.builder.Build()
 
-	err := repo.UpsertManualBudget(ctx, userID, ym, bankAmount, items)
+	_, err := repo.UpsertManualBudget(ctx, userID, ym, 0, bankAmount, items)
 	if err != nil {
 		return err
 	}
@@ -208,7 +208,7 @@ func (ubs *UpdateBudgetStrategy) Execute(
 	t.Helper()
 	userID, ym, bankAmount, items := ubs.builder.Build()
 
-	err := repo.UpsertManualBudget(ctx, userID, ym, bankAmount, items)
+	_, err := repo.UpsertManualBudget(ctx, userID, ym, 0, bankAmount, items)
 	if err != nil {
 		return err
 	}
@@ -253,7 +253,7 @@ func (ecs *EdgeCaseStrategy) Execute(ctx context.Context, t *testing.T, repo *Re
 	t.Helper()
 	userID, ym, bankAmount, items := ecs.builder.Build()
 
-	err := repo.UpsertManualBudget(ctx, userID, ym, bankAmount, items)
+	_, err := repo.UpsertManualBudget(ctx, userID, ym, 0, bankAmount, items)
 	if err != nil {
 		return err
 	}
@@ -453,7 +453,7 @@ func BenchmarkRepository_UpsertManualBudget(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		if err := repo.UpsertManualBudget(ctx, userID, ym, bankAmount, items); err != nil {
+		if _, err := repo.UpsertManualBudget(ctx, userID, ym, 0, bankAmount, items); err != nil {
 			b.Fatalf("benchmark failed: %v", err)
 		}
 	}
@@ -475,7 +475,7 @@ func BenchmarkRepository_GetManualBudget(b *testing.B) {
 		{Name: "Groceries", AmountCents: domain.Money(-30000)},
 	}
 
-	if err := repo.UpsertManualBudget(ctx, userID, ym, bankAmount, items); err != nil {
+	if _, err := repo.UpsertManualBudget(ctx, userID, ym, 0, bankAmount, items); err != nil {
 		b.Fatalf("setup failed: %v", err)
 	}
 
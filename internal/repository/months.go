@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// Multi-month reads. They back batched loaders (GraphQL), which gather the
+// months a query touches and fetch them in one round trip per table instead
+// of one per month. Months with no rows are absent from the returned maps.
+
+// monthsWhere matches rows in any of months.
+func monthsWhere(months []domain.YearMonth) (string, []any) {
+	parts := make([]string, len(months))
+	args := make([]any, 0, 2*len(months))
+	for i, ym := range months {
+		parts[i] = `(year = ? AND month = ?)`
+		args = append(args, ym.Year, ym.Month)
+	}
+	return `(` + strings.Join(parts, ` OR `) + `)`, args
+}
+
+// ListIncomeSourcesForMonths returns a user's income sources for each of
+// months, by name.
+func (r *Repository) ListIncomeSourcesForMonths(
+	ctx context.Context,
+	userID int64,
+	months []domain.YearMonth,
+) (map[domain.YearMonth][]domain.IncomeSource, error) {
+	out := make(map[domain.YearMonth][]domain.IncomeSource)
+	if len(months) == 0 {
+		return out, nil
+	}
+	where, args := monthsWhere(months)
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+sourceSelectColumns+` FROM income_sources WHERE user_id = ? AND deleted_at IS NULL AND `+where+` ORDER BY name, id`,
+		append([]any{userID}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var s domain.IncomeSource
+		if err := scanSource(rows, &s.ID, &s.UserID, &s.Name, &s.YearMonth,
+			&s.AmountCents, &s.DayOfMonth, &s.Version, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out[s.YearMonth] = append(out[s.YearMonth], s)
+	}
+	return out, rows.Err()
+}
+
+// ListBudgetSourcesForMonths returns a user's budget sources for each of
+// months, by name.
+func (r *Repository) ListBudgetSourcesForMonths(
+	ctx context.Context,
+	userID int64,
+	months []domain.YearMonth,
+) (map[domain.YearMonth][]domain.BudgetSource, error) {
+	out := make(map[domain.YearMonth][]domain.BudgetSource)
+	if len(months) == 0 {
+		return out, nil
+	}
+	where, args := monthsWhere(months)
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+sourceSelectColumns+` FROM budget_sources WHERE user_id = ? AND deleted_at IS NULL AND `+where+` ORDER BY name, id`,
+		append([]any{userID}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var s domain.BudgetSource
+		if err := scanSource(rows, &s.ID, &s.UserID, &s.Name, &s.YearMonth,
+			&s.AmountCents, &s.DayOfMonth, &s.Version, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out[s.YearMonth] = append(out[s.YearMonth], s)
+	}
+	return out, rows.Err()
+}
+
+// ListExpensesForMonths returns the expenses for each of months, newest first.
+func (r *Repository) ListExpensesForMonths(
+	ctx context.Context,
+	months []domain.YearMonth,
+) (map[domain.YearMonth][]domain.Expense, error) {
+	out := make(map[domain.YearMonth][]domain.Expense)
+	if len(months) == 0 {
+		return out, nil
+	}
+	where, args := monthsWhere(months)
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, year, month, category, description, amount_cents, created_at
+		 FROM expense WHERE deleted_at IS NULL AND `+where+` ORDER BY id DESC`,
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		e, err := scanExpense(rows)
+		if err != nil {
+			return nil, err
+		}
+		out[e.YearMonth] = append(out[e.YearMonth], e)
+	}
+	return out, rows.Err()
+}
+
+// GetManualBudgetsForMonths returns a user's saved manual budgets, with their
+// items, for each of months.
+func (r *Repository) GetManualBudgetsForMonths(
+	ctx context.Context,
+	userID int64,
+	months []domain.YearMonth,
+) (map[domain.YearMonth]*domain.ManualBudget, error) {
+	out := make(map[domain.YearMonth]*domain.ManualBudget)
+	if len(months) == 0 {
+		return out, nil
+	}
+	where, args := monthsWhere(months)
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, year, month, bank_amount_cents, version FROM manual_budgets WHERE user_id = ? AND `+where,
+		append([]any{userID}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	byID := make(map[int64]*domain.ManualBudget)
+	ids := make([]any, 0, len(months))
+	for rows.Next() {
+		mb := &domain.ManualBudget{UserID: userID, Items: []domain.ManualBudgetItem{}}
+		var bank int64
+		if err := rows.Scan(&mb.ID, &mb.Year, &mb.Month, &bank, &mb.Version); err != nil {
+			return nil, err
+		}
+		mb.BankAmountCents = domain.Money(bank)
+		out[mb.YearMonth] = mb
+		byID[mb.ID] = mb
+		ids = append(ids, mb.ID)
+	}
+	if err := rows.Err(); err != nil || len(ids) == 0 {
+		return out, err
+	}
+
+	items, err := r.db.QueryContext(ctx,
+		`SELECT id, budget_id, name, amount_cents FROM manual_budget_items
+		 WHERE deleted_at IS NULL AND budget_id IN (?`+strings.Repeat(`, ?`, len(ids)-1)+`) ORDER BY id`,
+		ids...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = items.Close() }()
+	for items.Next() {
+		var it domain.ManualBudgetItem
+		var amount int64
+		if err := items.Scan(&it.ID, &it.BudgetID, &it.Name, &amount); err != nil {
+			return nil, err
+		}
+		it.AmountCents = domain.Money(amount)
+		mb := byID[it.BudgetID]
+		mb.Items = append(mb.Items, it)
+	}
+	return out, items.Err()
+}
@@ -6,9 +6,11 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/mdco1990/webapp/internal/domain"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -19,11 +21,16 @@ type Repository struct {
 	// feature flags detected from DB schema
 	hasIsAdmin bool
 	hasStatus  bool
+	// mysql selects MySQL syntax where the dialects differ (see Search).
+	mysql bool
+	// onChange is called after committed writes (see OnChange).
+	onChange func(context.Context, domain.Change)
 }
 
 // New creates a new Repository.
 func New(db *sql.DB) *Repository {
 	r := &Repository{db: db}
+	_, r.mysql = db.Driver().(*mysql.MySQLDriver)
 	// Detect if users table has is_admin column (SQLite specific PRAGMA)
 	// If the PRAGMA query fails for any reason, default to false.
 	var exists int
@@ -70,13 +77,33 @@ func (r *Repository) UpsertBudget(
 
 // AddExpense creates a new expense record.
 func (r *Repository) AddExpense(ctx context.Context, e *domain.Expense) (int64, error) {
-	res, err := r.db.ExecContext(ctx,
+	var id int64
+	err := r.inTx(ctx, func(tx *sql.Tx) (err error) {
+		id, err = insertExpense(ctx, tx, e)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	r.notify(ctx, domain.Change{
+		YearMonth: e.YearMonth, Entity: domain.BatchExpense, Action: domain.ChangeCreated, ID: id,
+	})
+	return id, nil
+}
+
+// insertExpense adds an expense and records it in its history.
+func insertExpense(ctx context.Context, q querier, e *domain.Expense) (int64, error) {
+	res, err := q.ExecContext(ctx,
 		`INSERT INTO expense(year, month, category, description, amount_cents)
 		 VALUES(?, ?, ?, ?, ?)`, e.Year, e.Month, nullify(e.Category), e.Description, int64(e.AmountCents))
 	if err != nil {
 		return 0, err
 	}
-	return res.LastInsertId()
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return id, recordHistory(ctx, q, domain.BatchExpense, id, domain.ChangeCreated, nil)
 }
 
 // ListExpenses returns all expenses for the provided year/month.
@@ -86,7 +113,7 @@ func (r *Repository) ListExpenses(
 ) ([]domain.Expense, error) {
 	rows, err := r.db.QueryContext(
 		ctx,
-		`SELECT id, year, month, category, description, amount_cents, created_at FROM expense WHERE year=? AND month=? ORDER BY id DESC`,
+		`SELECT id, year, month, category, description, amount_cents, created_at FROM expense WHERE year=? AND month=? AND deleted_at IS NULL ORDER BY id DESC`,
 		ym.Year,
 		ym.Month,
 	)
@@ -96,14 +123,10 @@ func (r *Repository) ListExpenses(
 	defer func() { _ = rows.Close() }()
 	var out []domain.Expense
 	for rows.Next() {
-		var e domain.Expense
-		var category sql.NullString
-		var amount int64
-		if err := rows.Scan(&e.ID, &e.Year, &e.Month, &category, &e.Description, &amount, &e.CreatedAt); err != nil {
+		e, err := scanExpense(rows)
+		if err != nil {
 			return []domain.Expense{}, err
 		}
-		e.Category = category.String
-		e.AmountCents = domain.Money(amount)
 		out = append(out, e)
 	}
 	// Ensure we return an empty slice instead of nil
@@ -113,10 +136,52 @@ func (r *Repository) ListExpenses(
 	return out, rows.Err()
 }
 
-// DeleteExpense removes an expense by ID.
+// scanExpense scans id, year, month, category, description, amount_cents, created_at.
+func scanExpense(rows *sql.Rows) (domain.Expense, error) {
+	var e domain.Expense
+	var category sql.NullString
+	var amount int64
+	if err := rows.Scan(&e.ID, &e.Year, &e.Month, &category, &e.Description, &amount, &e.CreatedAt); err != nil {
+		return domain.Expense{}, err
+	}
+	e.Category = category.String
+	e.AmountCents = domain.Money(amount)
+	return e, nil
+}
+
+// DeleteExpense moves an expense to the trash by ID (ErrNotFound if there is
+// none).
 func (r *Repository) DeleteExpense(ctx context.Context, id int64) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM expense WHERE id=?`, id)
-	return err
+	var ym domain.YearMonth
+	err := r.inTx(ctx, func(tx *sql.Tx) (err error) {
+		ym, err = deleteExpense(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	r.notify(ctx, domain.Change{YearMonth: ym, Entity: domain.BatchExpense, Action: domain.ChangeDeleted, ID: id})
+	return nil
+}
+
+// deleteExpense moves an expense to the trash and returns its month.
+func deleteExpense(ctx context.Context, q querier, id int64) (domain.YearMonth, error) {
+	ym, err := monthOf(ctx, q, "expense", id)
+	if err != nil {
+		return ym, err
+	}
+	before, err := snapshotBefore(ctx, q, domain.BatchExpense, id)
+	if err != nil {
+		return ym, err
+	}
+	res, err := q.ExecContext(ctx, `UPDATE expense SET deleted_at=? WHERE id=? AND deleted_at IS NULL`, deletedNow(), id)
+	if err != nil {
+		return ym, translateError(err)
+	}
+	if err := checkAffected(res); err != nil {
+		return ym, err
+	}
+	return ym, recordHistory(ctx, q, domain.BatchExpense, id, domain.ChangeDeleted, before)
 }
 
 // GetSalary returns salary for a given year/month or 0 if none.
@@ -147,7 +212,7 @@ func (r *Repository) GetExpensesTotal(
 	ym domain.YearMonth,
 ) (domain.Money, error) {
 	var total int64
-	err := r.db.QueryRowContext(ctx, `SELECT COALESCE(SUM(amount_cents),0) FROM expense WHERE year=? AND month=?`, ym.Year, ym.Month).
+	err := r.db.QueryRowContext(ctx, `SELECT COALESCE(SUM(amount_cents),0) FROM expense WHERE year=? AND month=? AND deleted_at IS NULL`, ym.Year, ym.Month).
 		Scan(&total)
 	return domain.Money(total), err
 }
@@ -173,7 +238,8 @@ func (r *Repository) CreateUser(
 		}(),
 		username, string(hashedPassword), nullify(email))
 	if err != nil {
-		return nil, err
+		// A taken username surfaces as ErrConflict.
+		return nil, translateError(err)
 	}
 
 	id, err := result.LastInsertId()
@@ -216,7 +282,7 @@ func (r *Repository) getUserByUsernameWithAdmin(
 		&user.ID, &user.Username, &passwordHash, &email, &user.CreatedAt, &lastLogin, &isAdminInt,
 	)
 	if err != nil {
-		return nil, "", err
+		return nil, "", translateError(err)
 	}
 
 	r.populateUserFields(&user, email, lastLogin)
@@ -238,7 +304,7 @@ func (r *Repository) getUserByUsernameWithoutAdmin(
 	err := r.db.QueryRowContext(ctx, query, username).Scan(
 		&user.ID, &user.Username, &passwordHash, &email, &user.CreatedAt, &lastLogin)
 	if err != nil {
-		return nil, "", err
+		return nil, "", translateError(err)
 	}
 
 	r.populateUserFields(&user, email, lastLogin)
@@ -283,7 +349,7 @@ func (r *Repository) getUserByIDWithAdmin(
 		 FROM users WHERE id = ?`, userID).Scan(
 		&user.ID, &user.Username, &passwordHash, &email, &user.CreatedAt, &lastLogin, &isAdminInt)
 	if err != nil {
-		return nil, "", err
+		return nil, "", translateError(err)
 	}
 
 	r.populateUserFields(&user, email, lastLogin)
@@ -306,7 +372,7 @@ func (r *Repository) getUserByIDWithoutAdmin(
 		 FROM users WHERE id = ?`, userID).Scan(
 		&user.ID, &user.Username, &passwordHash, &email, &user.CreatedAt, &lastLogin)
 	if err != nil {
-		return nil, "", err
+		return nil, "", translateError(err)
 	}
 
 	r.populateUserFields(&user, email, lastLogin)
@@ -321,13 +387,13 @@ func (r *Repository) IsUserAdmin(ctx context.Context, userID int64) (bool, error
 	if r.hasIsAdmin {
 		var isAdmin int64
 		if err := r.db.QueryRowContext(ctx, `SELECT COALESCE(is_admin, 0) FROM users WHERE id = ?`, userID).Scan(&isAdmin); err != nil {
-			return false, err
+			return false, translateError(err)
 		}
 		return isAdmin != 0, nil
 	}
 	var username string
 	if err := r.db.QueryRowContext(ctx, `SELECT username FROM users WHERE id = ?`, userID).Scan(&username); err != nil {
-		return false, err
+		return false, translateError(err)
 	}
 	return username == "admin", nil
 }
@@ -384,7 +450,7 @@ func (r *Repository) GetSession(ctx context.Context, sessionID string) (*domain.
 		 WHERE id = ? AND expires_at > CURRENT_TIMESTAMP`, sessionID).Scan(
 		&session.ID, &session.UserID, &session.CreatedAt, &session.ExpiresAt)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 
 	return &session, nil
@@ -405,50 +471,59 @@ func (r *Repository) CreateIncomeSource(
 	req domain.CreateIncomeSourceRequest,
 ) (*domain.IncomeSource, error) {
 	now := time.Now()
-	result, err := r.db.ExecContext(
-		ctx,
-		`INSERT INTO income_sources (user_id, name, year, month, amount_cents, created_at, updated_at)
-		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
-		userID,
-		req.Name,
-		req.Year,
-		req.Month,
-		int64(req.AmountCents),
-		now,
-		now,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	id, err := result.LastInsertId()
+	ym := domain.YearMonth{Year: req.Year, Month: req.Month}
+	var id int64
+	err := r.inTx(ctx, func(tx *sql.Tx) (err error) {
+		id, err = insertSource(ctx, tx, "income_sources", userID, req.Name, ym, req.AmountCents, req.DayOfMonth, now)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
+	r.notify(ctx, domain.Change{
+		UserID: userID, YearMonth: ym, Entity: domain.BatchIncomeSource, Action: domain.ChangeCreated, ID: id, Version: 1,
+	})
 
 	return &domain.IncomeSource{
 		ID:          id,
 		UserID:      userID,
 		Name:        req.Name,
-		YearMonth:   domain.YearMonth{Year: req.Year, Month: req.Month},
+		YearMonth:   ym,
 		AmountCents: req.AmountCents,
+		DayOfMonth:  dayPtr(nullableDay(req.DayOfMonth)),
+		Version:     1,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}, nil
 }
 
-// UpdateIncomeSource updates an existing income source.
+// GetIncomeSource returns one of a user's income sources. It returns ErrNotFound
+// for unknown IDs and another user's sources.
+func (r *Repository) GetIncomeSource(ctx context.Context, id, userID int64) (*domain.IncomeSource, error) {
+	var s domain.IncomeSource
+	row := r.db.QueryRowContext(ctx, `SELECT `+sourceSelectColumns+` FROM income_sources WHERE id = ? AND deleted_at IS NULL`, id)
+	if err := scanSource(row, &s.ID, &s.UserID, &s.Name, &s.YearMonth,
+		&s.AmountCents, &s.DayOfMonth, &s.Version, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return nil, translateError(err)
+	}
+	if s.UserID != userID {
+		return nil, errNotOwned
+	}
+	return &s, nil
+}
+
+// UpdateIncomeSource updates an existing income source and returns its new
+// version. A non-zero version makes the update conditional on it: a source
+// that has changed since yields ErrPreconditionFailed. It returns ErrNotFound
+// for unknown IDs and another user's sources.
 func (r *Repository) UpdateIncomeSource(
 	ctx context.Context,
 	id int64,
 	userID int64,
+	version int64,
 	req domain.UpdateSourceRequest,
-) error {
-	_, err := r.db.ExecContext(ctx,
-		`UPDATE income_sources SET name = ?, amount_cents = ?, updated_at = CURRENT_TIMESTAMP
-		 WHERE id = ? AND user_id = ?`,
-		req.Name, int64(req.AmountCents), id, userID)
-	return err
+) (int64, error) {
+	return r.updateSourceNotify(ctx, "income_sources", domain.BatchIncomeSource, id, userID, version, req)
 }
 
 // ListIncomeSources lists income sources for a user and month.
@@ -458,8 +533,8 @@ func (r *Repository) ListIncomeSources(
 	ym domain.YearMonth,
 ) ([]domain.IncomeSource, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, user_id, name, year, month, amount_cents, created_at, updated_at
-		 FROM income_sources WHERE user_id = ? AND year = ? AND month = ?
+		`SELECT `+sourceSelectColumns+`
+		 FROM income_sources WHERE user_id = ? AND year = ? AND month = ? AND deleted_at IS NULL
 		 ORDER BY name`,
 		userID, ym.Year, ym.Month)
 	if err != nil {
@@ -470,12 +545,10 @@ func (r *Repository) ListIncomeSources(
 	var sources []domain.IncomeSource
 	for rows.Next() {
 		var source domain.IncomeSource
-		var amount int64
-		if err := rows.Scan(&source.ID, &source.UserID, &source.Name,
-			&source.Year, &source.Month, &amount, &source.CreatedAt, &source.UpdatedAt); err != nil {
+		if err := scanSource(rows, &source.ID, &source.UserID, &source.Name, &source.YearMonth,
+			&source.AmountCents, &source.DayOfMonth, &source.Version, &source.CreatedAt, &source.UpdatedAt); err != nil {
 			return []domain.IncomeSource{}, err
 		}
-		source.AmountCents = domain.Money(amount)
 		sources = append(sources, source)
 	}
 
@@ -485,15 +558,223 @@ func (r *Repository) ListIncomeSources(
 	return sources, rows.Err()
 }
 
-// DeleteIncomeSource deletes an income source by ID for a user.
-func (r *Repository) DeleteIncomeSource(ctx context.Context, id int64, userID int64) error {
-	_, err := r.db.ExecContext(
+// querier is satisfied by *sql.DB and *sql.Tx, so write helpers can run
+// alone or inside a transaction (see ApplyBatch).
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// inTx runs fn in a transaction, which it commits if fn succeeds.
+func (r *Repository) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertSource implements CreateIncomeSource and CreateBudgetSource, and
+// records the new source in its history.
+func insertSource(
+	ctx context.Context,
+	q querier,
+	table string,
+	userID int64,
+	name string,
+	ym domain.YearMonth,
+	amount domain.Money,
+	day *int,
+	now time.Time,
+) (int64, error) {
+	result, err := q.ExecContext(
 		ctx,
-		`DELETE FROM income_sources WHERE id = ? AND user_id = ?`,
-		id,
+		`INSERT INTO `+table+` (user_id, name, year, month, amount_cents, day_of_month, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
 		userID,
+		name,
+		ym.Year,
+		ym.Month,
+		int64(amount),
+		nullableDay(day),
+		now,
+		now,
 	)
-	return err
+	if err != nil {
+		return 0, translateError(err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return id, recordHistory(ctx, q, sourceEntities[table], id, domain.ChangeCreated, nil)
+}
+
+// sourceSelectColumns are the income_sources/budget_sources columns read by scanSource.
+const sourceSelectColumns = `id, user_id, name, year, month, amount_cents, day_of_month, version, created_at, updated_at`
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanSource scans sourceSelectColumns into the fields shared by income and
+// budget sources.
+func scanSource(
+	rows rowScanner,
+	id, userID *int64,
+	name *string,
+	ym *domain.YearMonth,
+	amount *domain.Money,
+	day **int,
+	version *int64,
+	createdAt, updatedAt *time.Time,
+) error {
+	var cents int64
+	var d sql.NullInt64
+	if err := rows.Scan(id, userID, name, &ym.Year, &ym.Month, &cents, &d, version, createdAt, updatedAt); err != nil {
+		return err
+	}
+	*amount = domain.Money(cents)
+	*day = dayPtr(d)
+	return nil
+}
+
+// updateSource implements UpdateIncomeSource and UpdateBudgetSource, and
+// records the write in the source's history as action. A nil DayOfMonth
+// keeps the current day.
+func (r *Repository) updateSource(
+	ctx context.Context,
+	q querier,
+	table string,
+	id, userID, version int64,
+	req domain.UpdateSourceRequest,
+	action string,
+) (int64, domain.YearMonth, error) {
+	entity := sourceEntities[table]
+	before, err := snapshotBefore(ctx, q, entity, id)
+	if err != nil {
+		return 0, domain.YearMonth{}, err
+	}
+	set := `name = ?, amount_cents = ?`
+	args := []any{req.Name, int64(req.AmountCents)}
+	if req.DayOfMonth != nil {
+		set += `, day_of_month = ?`
+		args = append(args, nullableDay(req.DayOfMonth))
+	}
+	where, whereArgs := versionedWhere(id, userID, version)
+	res, err := q.ExecContext(ctx,
+		`UPDATE `+table+` SET `+set+`, version = version + 1, updated_at = CURRENT_TIMESTAMP `+where,
+		append(args, whereArgs...)...)
+	if err != nil {
+		return 0, domain.YearMonth{}, translateError(err)
+	}
+	if err := r.checkVersioned(ctx, q, res, table, id, userID, version); err != nil {
+		return 0, domain.YearMonth{}, err
+	}
+	var current int64
+	var ym domain.YearMonth
+	err = q.QueryRowContext(ctx, `SELECT version, year, month FROM `+table+` WHERE id = ?`, id).
+		Scan(&current, &ym.Year, &ym.Month)
+	// Unconditional writes read the version back; a concurrent writer may
+	// already have moved it on, which the caller accepted by not sending one.
+	if version > 0 {
+		current = version + 1
+	}
+	if err != nil {
+		return 0, domain.YearMonth{}, translateError(err)
+	}
+	return current, ym, recordHistory(ctx, q, entity, id, action, before)
+}
+
+// updateSourceNotify runs updateSource in its own transaction and reports
+// the change.
+func (r *Repository) updateSourceNotify(
+	ctx context.Context,
+	table, entity string,
+	id, userID, version int64,
+	req domain.UpdateSourceRequest,
+) (int64, error) {
+	var (
+		newVersion int64
+		ym         domain.YearMonth
+	)
+	err := r.inTx(ctx, func(tx *sql.Tx) (err error) {
+		newVersion, ym, err = r.updateSource(ctx, tx, table, id, userID, version, req, domain.ChangeUpdated)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	r.notify(ctx, domain.Change{
+		UserID: userID, YearMonth: ym, Entity: entity, Action: domain.ChangeUpdated, ID: id, Version: newVersion,
+	})
+	return newVersion, nil
+}
+
+// deleteSource implements DeleteIncomeSource and DeleteBudgetSource, and
+// returns the deleted source's month. The source goes to the trash with a new
+// version, so ETags read before the delete do not match it once restored.
+func (r *Repository) deleteSource(
+	ctx context.Context,
+	q querier,
+	table string,
+	id, userID, version int64,
+) (domain.YearMonth, error) {
+	ym, err := monthOf(ctx, q, table, id)
+	if err != nil {
+		return ym, err
+	}
+	before, err := snapshotBefore(ctx, q, sourceEntities[table], id)
+	if err != nil {
+		return ym, err
+	}
+	where, args := versionedWhere(id, userID, version)
+	res, err := q.ExecContext(ctx,
+		`UPDATE `+table+` SET deleted_at = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP `+where,
+		append([]any{deletedNow()}, args...)...)
+	if err != nil {
+		return ym, translateError(err)
+	}
+	if err := r.checkVersioned(ctx, q, res, table, id, userID, version); err != nil {
+		return ym, err
+	}
+	return ym, recordHistory(ctx, q, sourceEntities[table], id, domain.ChangeDeleted, before)
+}
+
+// deleteSourceNotify runs deleteSource in its own transaction and reports
+// the change.
+func (r *Repository) deleteSourceNotify(ctx context.Context, table, entity string, id, userID, version int64) error {
+	var ym domain.YearMonth
+	err := r.inTx(ctx, func(tx *sql.Tx) (err error) {
+		ym, err = r.deleteSource(ctx, tx, table, id, userID, version)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	r.notify(ctx, domain.Change{UserID: userID, YearMonth: ym, Entity: entity, Action: domain.ChangeDeleted, ID: id})
+	return nil
+}
+
+// versionedWhere matches a user's live row by ID and, if version is non-zero,
+// by version.
+func versionedWhere(id, userID, version int64) (string, []any) {
+	if version > 0 {
+		return `WHERE id = ? AND user_id = ? AND version = ? AND deleted_at IS NULL`, []any{id, userID, version}
+	}
+	return `WHERE id = ? AND user_id = ? AND deleted_at IS NULL`, []any{id, userID}
+}
+
+// DeleteIncomeSource moves an income source to the trash by ID for a user,
+// with the same version check and errors as UpdateIncomeSource.
+func (r *Repository) DeleteIncomeSource(ctx context.Context, id int64, userID int64, version int64) error {
+	return r.deleteSourceNotify(ctx, "income_sources", domain.BatchIncomeSource, id, userID, version)
 }
 
 // Budget Sources methods
@@ -505,50 +786,59 @@ func (r *Repository) CreateBudgetSource(
 	req domain.CreateBudgetSourceRequest,
 ) (*domain.BudgetSource, error) {
 	now := time.Now()
-	result, err := r.db.ExecContext(
-		ctx,
-		`INSERT INTO budget_sources (user_id, name, year, month, amount_cents, created_at, updated_at)
-		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
-		userID,
-		req.Name,
-		req.Year,
-		req.Month,
-		int64(req.AmountCents),
-		now,
-		now,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	id, err := result.LastInsertId()
+	ym := domain.YearMonth{Year: req.Year, Month: req.Month}
+	var id int64
+	err := r.inTx(ctx, func(tx *sql.Tx) (err error) {
+		id, err = insertSource(ctx, tx, "budget_sources", userID, req.Name, ym, req.AmountCents, req.DayOfMonth, now)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
+	r.notify(ctx, domain.Change{
+		UserID: userID, YearMonth: ym, Entity: domain.BatchBudgetSource, Action: domain.ChangeCreated, ID: id, Version: 1,
+	})
 
 	return &domain.BudgetSource{
 		ID:          id,
 		UserID:      userID,
 		Name:        req.Name,
-		YearMonth:   domain.YearMonth{Year: req.Year, Month: req.Month},
+		YearMonth:   ym,
 		AmountCents: req.AmountCents,
+		DayOfMonth:  dayPtr(nullableDay(req.DayOfMonth)),
+		Version:     1,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}, nil
 }
 
-// UpdateBudgetSource updates an existing budget source.
+// GetBudgetSource returns one of a user's budget sources. It returns ErrNotFound
+// for unknown IDs and another user's sources.
+func (r *Repository) GetBudgetSource(ctx context.Context, id, userID int64) (*domain.BudgetSource, error) {
+	var s domain.BudgetSource
+	row := r.db.QueryRowContext(ctx, `SELECT `+sourceSelectColumns+` FROM budget_sources WHERE id = ? AND deleted_at IS NULL`, id)
+	if err := scanSource(row, &s.ID, &s.UserID, &s.Name, &s.YearMonth,
+		&s.AmountCents, &s.DayOfMonth, &s.Version, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return nil, translateError(err)
+	}
+	if s.UserID != userID {
+		return nil, errNotOwned
+	}
+	return &s, nil
+}
+
+// UpdateBudgetSource updates an existing budget source and returns its new
+// version. A non-zero version makes the update conditional on it: a source
+// that has changed since yields ErrPreconditionFailed. It returns ErrNotFound
+// for unknown IDs and another user's sources.
 func (r *Repository) UpdateBudgetSource(
 	ctx context.Context,
 	id int64,
 	userID int64,
+	version int64,
 	req domain.UpdateSourceRequest,
-) error {
-	_, err := r.db.ExecContext(ctx,
-		`UPDATE budget_sources SET name = ?, amount_cents = ?, updated_at = CURRENT_TIMESTAMP
-		 WHERE id = ? AND user_id = ?`,
-		req.Name, int64(req.AmountCents), id, userID)
-	return err
+) (int64, error) {
+	return r.updateSourceNotify(ctx, "budget_sources", domain.BatchBudgetSource, id, userID, version, req)
 }
 
 // ListBudgetSources lists budget sources for a user and month.
@@ -558,8 +848,8 @@ func (r *Repository) ListBudgetSources(
 	ym domain.YearMonth,
 ) ([]domain.BudgetSource, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, user_id, name, year, month, amount_cents, created_at, updated_at
-		 FROM budget_sources WHERE user_id = ? AND year = ? AND month = ?
+		`SELECT `+sourceSelectColumns+`
+		 FROM budget_sources WHERE user_id = ? AND year = ? AND month = ? AND deleted_at IS NULL
 		 ORDER BY name`,
 		userID, ym.Year, ym.Month)
 	if err != nil {
@@ -570,12 +860,10 @@ func (r *Repository) ListBudgetSources(
 	var sources []domain.BudgetSource
 	for rows.Next() {
 		var source domain.BudgetSource
-		var amount int64
-		if err := rows.Scan(&source.ID, &source.UserID, &source.Name,
-			&source.Year, &source.Month, &amount, &source.CreatedAt, &source.UpdatedAt); err != nil {
+		if err := scanSource(rows, &source.ID, &source.UserID, &source.Name, &source.YearMonth,
+			&source.AmountCents, &source.DayOfMonth, &source.Version, &source.CreatedAt, &source.UpdatedAt); err != nil {
 			return []domain.BudgetSource{}, err
 		}
-		source.AmountCents = domain.Money(amount)
 		sources = append(sources, source)
 	}
 
@@ -585,15 +873,10 @@ func (r *Repository) ListBudgetSources(
 	return sources, rows.Err()
 }
 
-// DeleteBudgetSource deletes a budget source by ID for a user.
-func (r *Repository) DeleteBudgetSource(ctx context.Context, id int64, userID int64) error {
-	_, err := r.db.ExecContext(
-		ctx,
-		`DELETE FROM budget_sources WHERE id = ? AND user_id = ?`,
-		id,
-		userID,
-	)
-	return err
+// DeleteBudgetSource moves a budget source to the trash by ID for a user,
+// with the same version check and errors as UpdateBudgetSource.
+func (r *Repository) DeleteBudgetSource(ctx context.Context, id int64, userID int64, version int64) error {
+	return r.deleteSourceNotify(ctx, "budget_sources", domain.BatchBudgetSource, id, userID, version)
 }
 
 // Manual Budget methods
@@ -605,16 +888,17 @@ func (r *Repository) GetManualBudget(
 	ym domain.YearMonth,
 ) (*domain.ManualBudget, error) {
 	var (
-		id   int64
-		bank int64
+		id      int64
+		bank    int64
+		version int64
 	)
 	err := r.db.QueryRowContext(
 		ctx,
-		`SELECT id, bank_amount_cents FROM manual_budgets WHERE user_id = ? AND year = ? AND month = ?`,
+		`SELECT id, bank_amount_cents, version FROM manual_budgets WHERE user_id = ? AND year = ? AND month = ?`,
 		userID,
 		ym.Year,
 		ym.Month,
-	).Scan(&id, &bank)
+	).Scan(&id, &bank, &version)
 	if errors.Is(err, sql.ErrNoRows) {
 		// Return empty structure
 		return &domain.ManualBudget{
@@ -630,7 +914,7 @@ func (r *Repository) GetManualBudget(
 
 	rows, err := r.db.QueryContext(
 		ctx,
-		`SELECT id, name, amount_cents FROM manual_budget_items WHERE budget_id = ? ORDER BY id`,
+		`SELECT id, name, amount_cents FROM manual_budget_items WHERE budget_id = ? AND deleted_at IS NULL ORDER BY id`,
 		id,
 	)
 	if err != nil {
@@ -659,21 +943,25 @@ func (r *Repository) GetManualBudget(
 		YearMonth:       ym,
 		BankAmountCents: domain.Money(bank),
 		Items:           items,
+		Version:         version,
 	}, nil
 }
 
-// UpsertManualBudget replaces the manual budget and its items for a given user/month atomically
-// UpsertManualBudget replaces the manual budget and items for a user/month atomically.
+// UpsertManualBudget replaces the manual budget and items for a user/month
+// atomically and returns the new version. A non-zero version makes the write
+// conditional on it: ErrPreconditionFailed if the month has been saved since
+// (or was never saved).
 func (r *Repository) UpsertManualBudget(
 	ctx context.Context,
 	userID int64,
 	ym domain.YearMonth,
+	version int64,
 	bank domain.Money,
 	items []domain.ManualBudgetItem,
-) error {
+) (int64, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer func() {
 		if err != nil {
@@ -681,41 +969,96 @@ func (r *Repository) UpsertManualBudget(
 		}
 	}()
 
-	budgetID, err := r.upsertManualBudgetRow(ctx, tx, userID, ym, bank)
+	_, newVersion, err := r.saveManualBudget(ctx, tx, userID, ym, version, bank, items, domain.ChangeUpdated)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	if err = r.replaceManualBudgetItems(ctx, tx, budgetID, items); err != nil {
-		return err
+	if err = tx.Commit(); err != nil {
+		return 0, err
 	}
+	r.notify(ctx, domain.Change{
+		UserID: userID, YearMonth: ym, Entity: domain.ChangeManualBudget, Action: domain.ChangeUpdated, Version: newVersion,
+	})
+	return newVersion, nil
+}
 
-	return tx.Commit()
+// saveManualBudget upserts the manual budget row and items for a user/month
+// within tx and records the write in the budget's history as action (as
+// created for the month's first save). It returns the budget's ID and new
+// version.
+func (r *Repository) saveManualBudget(
+	ctx context.Context,
+	tx *sql.Tx,
+	userID int64,
+	ym domain.YearMonth,
+	version int64,
+	bank domain.Money,
+	items []domain.ManualBudgetItem,
+	action string,
+) (int64, int64, error) {
+	before, err := manualBudgetSnapshot(ctx, tx, userID, ym)
+	if err != nil {
+		return 0, 0, err
+	}
+	budgetID, newVersion, err := r.upsertManualBudgetRow(ctx, tx, userID, ym, version, bank)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := r.replaceManualBudgetItems(ctx, tx, budgetID, items); err != nil {
+		return 0, 0, err
+	}
+	if before == nil && action == domain.ChangeUpdated {
+		action = domain.ChangeCreated
+	}
+	return budgetID, newVersion, recordHistory(ctx, tx, domain.ChangeManualBudget, budgetID, action, before)
 }
 
-// upsertManualBudgetRow inserts or updates the manual_budgets row and returns its ID
+// manualBudgetSnapshot returns a user's manual budget for a month as JSON, or
+// nil if the month has none.
+func manualBudgetSnapshot(ctx context.Context, q querier, userID int64, ym domain.YearMonth) (json.RawMessage, error) {
+	var id int64
+	err := q.QueryRowContext(ctx,
+		`SELECT id FROM manual_budgets WHERE user_id = ? AND year = ? AND month = ?`,
+		userID, ym.Year, ym.Month).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return snapshotBefore(ctx, q, domain.ChangeManualBudget, id)
+}
+
+// upsertManualBudgetRow inserts or updates the manual_budgets row, bumping its
+// version, and returns its ID and new version. A non-zero version must match
+// the existing row.
 func (r *Repository) upsertManualBudgetRow(
 	ctx context.Context,
 	tx *sql.Tx,
 	userID int64,
 	ym domain.YearMonth,
+	version int64,
 	bank domain.Money,
-) (int64, error) {
+) (int64, int64, error) {
 	// Try update first
-	res, err := tx.ExecContext(
-		ctx,
-		`UPDATE manual_budgets SET bank_amount_cents = ?, updated_at = CURRENT_TIMESTAMP WHERE user_id = ? AND year = ? AND month = ?`,
-		int64(bank),
-		userID,
-		ym.Year,
-		ym.Month,
-	)
+	query := `UPDATE manual_budgets SET bank_amount_cents = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = ? AND year = ? AND month = ?`
+	args := []any{int64(bank), userID, ym.Year, ym.Month}
+	if version > 0 {
+		query += ` AND version = ?`
+		args = append(args, version)
+	}
+	res, err := tx.ExecContext(ctx, query, args...)
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 
 	rowsAff, _ := res.RowsAffected()
 	if rowsAff == 0 {
+		if version > 0 {
+			return 0, 0, errVersionMismatch
+		}
 		// Insert new row
 		result, err := tx.ExecContext(
 			ctx,
@@ -726,37 +1069,83 @@ func (r *Repository) upsertManualBudgetRow(
 			int64(bank),
 		)
 		if err != nil {
-			return 0, err
+			return 0, 0, translateError(err)
 		}
-		return result.LastInsertId()
+		id, err := result.LastInsertId()
+		return id, 1, err
 	}
 
-	// Get ID for existing row
-	var budgetID int64
+	// Get ID and version for existing row
+	var budgetID, newVersion int64
 	err = tx.QueryRowContext(
 		ctx,
-		`SELECT id FROM manual_budgets WHERE user_id = ? AND year = ? AND month = ?`,
+		`SELECT id, version FROM manual_budgets WHERE user_id = ? AND year = ? AND month = ?`,
 		userID,
 		ym.Year,
 		ym.Month,
-	).Scan(&budgetID)
-	return budgetID, err
+	).Scan(&budgetID, &newVersion)
+	return budgetID, newVersion, err
 }
 
-// replaceManualBudgetItems deletes existing items and inserts new ones for the budget
+// replaceManualBudgetItems makes items the budget's items. Existing items
+// with the same name and amount are kept as they are and the rest go to the
+// trash, so a save that drops or edits an item can be undone; new items are
+// added after the kept ones.
 func (r *Repository) replaceManualBudgetItems(
 	ctx context.Context,
 	tx *sql.Tx,
 	budgetID int64,
 	items []domain.ManualBudgetItem,
 ) error {
-	// Delete existing items
-	if _, err := tx.ExecContext(ctx, `DELETE FROM manual_budget_items WHERE budget_id = ?`, budgetID); err != nil {
+	type itemKey struct {
+		name   string
+		amount domain.Money
+	}
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id, name, amount_cents FROM manual_budget_items WHERE budget_id = ? AND deleted_at IS NULL ORDER BY id`,
+		budgetID)
+	if err != nil {
+		return err
+	}
+	existing := make(map[itemKey][]int64)
+	for rows.Next() {
+		var id, amount int64
+		var name string
+		if err := rows.Scan(&id, &name, &amount); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		k := itemKey{name, domain.Money(amount)}
+		existing[k] = append(existing[k], id)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
 		return err
 	}
 
+	var added []domain.ManualBudgetItem
+	for _, it := range items {
+		k := itemKey{it.Name, it.AmountCents}
+		if ids := existing[k]; len(ids) > 0 {
+			existing[k] = ids[1:]
+			continue
+		}
+		added = append(added, it)
+	}
+
+	// Trash the items that were not kept
+	now := deletedNow()
+	for _, ids := range existing {
+		for _, id := range ids {
+			if _, err := tx.ExecContext(ctx,
+				`UPDATE manual_budget_items SET deleted_at = ? WHERE id = ?`, now, id); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Insert new items if any
-	if len(items) == 0 {
+	if len(added) == 0 {
 		return nil
 	}
 
@@ -769,7 +1158,7 @@ func (r *Repository) replaceManualBudgetItems(
 	}
 	defer func() { _ = stmt.Close() }()
 
-	for _, it := range items {
+	for _, it := range added {
 		if _, err := stmt.ExecContext(ctx, budgetID, it.Name, int64(it.AmountCents)); err != nil {
 			return err
 		}
@@ -835,6 +1224,24 @@ func (r *Repository) GetMonthlyData(
 }
 
 // Utility functions
+
+// nullableDay stores an unset or zero pay/due day as NULL.
+func nullableDay(day *int) sql.NullInt64 {
+	if day == nil || *day == 0 {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*day), Valid: true}
+}
+
+// dayPtr converts a scanned day_of_month column back to the domain pointer.
+func dayPtr(day sql.NullInt64) *int {
+	if !day.Valid {
+		return nil
+	}
+	d := int(day.Int64)
+	return &d
+}
+
 func nullify(s string) any {
 	if s == "" {
 		return nil
@@ -864,8 +1271,8 @@ func NewUserQueryBuilder(hasIsAdmin, hasStatus bool) *UserQueryBuilder {
 	}
 }
 
-// BuildQuery builds the SQL query for listing users
-func (qb *UserQueryBuilder) BuildQuery(status string) (string, []any) {
+// selectFrom returns the SELECT ... FROM users part of user queries
+func (qb *UserQueryBuilder) selectFrom() string {
 	base := "SELECT id, username, email, created_at, last_login"
 	if qb.hasIsAdmin {
 		base += ", is_admin"
@@ -873,7 +1280,12 @@ func (qb *UserQueryBuilder) BuildQuery(status string) (string, []any) {
 	if qb.hasStatus {
 		base += ", status"
 	}
-	base += " FROM users"
+	return base + " FROM users"
+}
+
+// BuildQuery builds the SQL query for listing users
+func (qb *UserQueryBuilder) BuildQuery(status string) (string, []any) {
+	base := qb.selectFrom()
 
 	args := []any{}
 	if status != "" && qb.hasStatus {
@@ -995,14 +1407,22 @@ func (r *Repository) UpdateUserStatus(ctx context.Context, userID int64, status
 	case "pending", "approved", "rejected":
 		// ok
 	default:
-		return errors.New("invalid status value")
+		return domain.Invalid("invalid status value", domain.FieldError{
+			Field: "status", Message: "must be pending, approved or rejected",
+		})
 	}
-	_, err := r.db.ExecContext(ctx, `UPDATE users SET status = ? WHERE id = ?`, status, userID)
-	return err
+	res, err := r.db.ExecContext(ctx, `UPDATE users SET status = ? WHERE id = ?`, status, userID)
+	if err != nil {
+		return translateError(err)
+	}
+	return checkAffected(res)
 }
 
-// DeleteUser removes a user by ID.
+// DeleteUser removes a user by ID (ErrNotFound if there is none).
 func (r *Repository) DeleteUser(ctx context.Context, userID int64) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM users WHERE id = ?`, userID)
-	return err
+	res, err := r.db.ExecContext(ctx, `DELETE FROM users WHERE id = ?`, userID)
+	if err != nil {
+		return translateError(err)
+	}
+	return checkAffected(res)
 }
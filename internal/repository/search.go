@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// Result counts for searches.
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+// Snippet markers emitted by FTS5 around matched words; markSnippet turns
+// them into <mark> tags after HTML-escaping the text around them.
+const (
+	markOpen  = "\x02"
+	markClose = "\x03"
+)
+
+// searchSource describes one searchable table. SQLite matches its FTS5 table,
+// MySQL the FULLTEXT index over match.
+type searchSource struct {
+	typ      string
+	table    string // aliased t
+	fts      string
+	match    string // indexed columns, in index order
+	title    string // column shown as the result title
+	category string // "" when the table has none
+	period   string // year and month columns
+	join     string // joined tables, if any
+	owner    string // condition limiting rows to the user; "" for shared rows
+}
+
+// Expenses are shared between users; everything else belongs to one.
+var searchSources = []searchSource{
+	{
+		typ: domain.BatchExpense, table: "expense", fts: "expense_fts",
+		match: "t.description, t.category", title: "t.description", category: "t.category",
+		period: "t.year, t.month",
+	},
+	{
+		typ: domain.BatchIncomeSource, table: "income_sources", fts: "income_sources_fts",
+		match: "t.name", title: "t.name", period: "t.year, t.month", owner: "t.user_id = ?",
+	},
+	{
+		typ: domain.BatchBudgetSource, table: "budget_sources", fts: "budget_sources_fts",
+		match: "t.name", title: "t.name", period: "t.year, t.month", owner: "t.user_id = ?",
+	},
+	{
+		typ: domain.BatchManualBudgetItem, table: "manual_budget_items", fts: "manual_budget_items_fts",
+		match: "t.name", title: "t.name", period: "b.year, b.month",
+		join: "JOIN manual_budgets b ON b.id = t.budget_id", owner: "b.user_id = ?",
+	},
+}
+
+// Search finds the expenses, income sources, budget sources and manual
+// budget items userID can see whose text matches q, across all months, best
+// match first. Ranking uses BM25 on SQLite and the FULLTEXT relevance on
+// MySQL; scores are not comparable between the two.
+func (r *Repository) Search(ctx context.Context, userID int64, q domain.SearchQuery) ([]domain.SearchResult, error) {
+	terms := searchTerms(q.Terms)
+	results := []domain.SearchResult{}
+	if len(terms) == 0 {
+		return results, nil
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultSearchLimit
+	}
+	q.Limit = min(q.Limit, MaxSearchLimit)
+
+	var (
+		selects []string
+		args    []any
+	)
+	for _, src := range searchSources {
+		if q.Category != "" && src.category == "" {
+			continue
+		}
+		query, a := r.searchSelect(src, terms, q, userID)
+		selects = append(selects, query)
+		args = append(args, a...)
+	}
+	query := strings.Join(selects, " UNION ALL ") + " ORDER BY score DESC, type, id LIMIT ?"
+	args = append(args, q.Limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var res domain.SearchResult
+		if err := rows.Scan(&res.Type, &res.ID, &res.Year, &res.Month, &res.Title, &res.Category,
+			&res.AmountCents, &res.Snippet, &res.Score); err != nil {
+			return nil, err
+		}
+		if r.mysql {
+			res.Snippet = highlightTerms(res.Title, terms)
+		} else {
+			res.Snippet = markSnippet(res.Snippet)
+		}
+		results = append(results, res)
+	}
+	return results, rows.Err()
+}
+
+// searchSelect returns the SELECT of one source for Search, with its
+// arguments. Every select yields the columns of a domain.SearchResult.
+func (r *Repository) searchSelect(
+	src searchSource,
+	terms []string,
+	q domain.SearchQuery,
+	userID int64,
+) (string, []any) {
+	category := "''"
+	if src.category != "" {
+		category = "COALESCE(" + src.category + ", '')"
+	}
+	var b strings.Builder
+	var args []any
+	if r.mysql {
+		against := "MATCH(" + src.match + ") AGAINST (? IN BOOLEAN MODE)"
+		fmt.Fprintf(&b, `SELECT '%s' AS type, t.id AS id, %s, %s, %s, t.amount_cents, '', %s AS score
+			FROM %s t %s WHERE %s`,
+			src.typ, src.period, src.title, category, against, src.table, src.join, against)
+		expr := mysqlMatchExpr(terms)
+		args = append(args, expr, expr)
+	} else {
+		fmt.Fprintf(&b, `SELECT '%s' AS type, t.id AS id, %s, %s, %s, t.amount_cents,
+			snippet(%s, -1, char(2), char(3), '…', 12), -bm25(%s) AS score
+			FROM %s JOIN %s t ON t.id = %s.rowid %s WHERE %s MATCH ?`,
+			src.typ, src.period, src.title, category, src.fts, src.fts,
+			src.fts, src.table, src.fts, src.join, src.fts)
+		args = append(args, ftsMatchExpr(terms))
+	}
+	where := func(cond string, a ...any) {
+		b.WriteString(" AND " + cond)
+		args = append(args, a...)
+	}
+	b.WriteString(" AND t.deleted_at IS NULL")
+	if src.owner != "" {
+		where(src.owner, userID)
+	}
+	if q.Category != "" {
+		where("LOWER("+src.category+") = ?", strings.ToLower(q.Category))
+	}
+	if q.MinAmount != nil {
+		where("t.amount_cents >= ?", int64(*q.MinAmount))
+	}
+	if q.MaxAmount != nil {
+		where("t.amount_cents <= ?", int64(*q.MaxAmount))
+	}
+	return b.String(), args
+}
+
+// searchTerms drops terms without a letter or digit, which neither full-text
+// engine can match.
+func searchTerms(terms []string) []string {
+	var out []string
+	for _, t := range terms {
+		if strings.IndexFunc(t, isWordRune) >= 0 {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// ftsMatchExpr builds an FTS5 query requiring every term, each as a quoted
+// phrase so user input cannot use query syntax. The last term matches as a
+// prefix.
+func ftsMatchExpr(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, t := range terms {
+		parts[i] = `"` + strings.ReplaceAll(t, `"`, `""`) + `"`
+	}
+	return strings.Join(parts, " ") + "*"
+}
+
+// mysqlMatchExpr builds the boolean-mode equivalent of ftsMatchExpr. InnoDB
+// ignores words shorter than innodb_ft_min_token_size (3 by default) and
+// stopwords, so short terms match less than on SQLite.
+func mysqlMatchExpr(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, t := range terms {
+		words := strings.FieldsFunc(t, func(r rune) bool { return !isWordRune(r) })
+		switch {
+		case len(words) > 1:
+			parts[i] = `+"` + strings.Join(words, " ") + `"`
+		case i == len(terms)-1:
+			parts[i] = "+" + words[0] + "*"
+		default:
+			parts[i] = "+" + words[0]
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// markSnippet HTML-escapes an FTS5 snippet and turns its markers into <mark>
+// tags.
+func markSnippet(s string) string {
+	s = html.EscapeString(s)
+	return strings.NewReplacer(markOpen, "<mark>", markClose, "</mark>").Replace(s)
+}
+
+// highlightTerms marks the words of text that start with a word of terms,
+// case-insensitively, for databases without a snippet function.
+func highlightTerms(text string, terms []string) string {
+	var prefixes []string
+	for _, t := range terms {
+		for _, w := range strings.FieldsFunc(t, func(r rune) bool { return !isWordRune(r) }) {
+			prefixes = append(prefixes, strings.ToLower(w))
+		}
+	}
+	var b strings.Builder
+	for len(text) > 0 {
+		start := strings.IndexFunc(text, isWordRune)
+		if start < 0 {
+			b.WriteString(html.EscapeString(text))
+			break
+		}
+		b.WriteString(html.EscapeString(text[:start]))
+		text = text[start:]
+		end := strings.IndexFunc(text, func(r rune) bool { return !isWordRune(r) })
+		if end < 0 {
+			end = len(text)
+		}
+		word := text[:end]
+		text = text[end:]
+		lower := strings.ToLower(word)
+		matched := false
+		for _, p := range prefixes {
+			if strings.HasPrefix(lower, p) {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			b.WriteString("<mark>" + html.EscapeString(word) + "</mark>")
+		} else {
+			b.WriteString(html.EscapeString(word))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+func TestRepository_Search(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	other, err := repo.CreateUser(ctx, "other", "password123", "other@example.com")
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	march := domain.YearMonth{Year: 2024, Month: 3}
+	for _, e := range []domain.Expense{
+		{YearMonth: march, Category: "Food", Description: "Coffee beans <organic>", AmountCents: 1800},
+		{YearMonth: domain.YearMonth{Year: 2023, Month: 11}, Category: "food", Description: "Coffee shop", AmountCents: 450},
+		{YearMonth: march, Category: "fun", Description: "Cinema", AmountCents: 1200},
+	} {
+		if _, err := repo.AddExpense(ctx, &e); err != nil {
+			t.Fatalf("AddExpense failed: %v", err)
+		}
+	}
+	income, err := repo.CreateIncomeSource(ctx, 1, domain.CreateIncomeSourceRequest{
+		Name: "Coffee roastery salary", Year: 2024, Month: 1, AmountCents: 250000,
+	})
+	if err != nil {
+		t.Fatalf("CreateIncomeSource failed: %v", err)
+	}
+	if _, err := repo.CreateBudgetSource(ctx, other.ID, domain.CreateBudgetSourceRequest{
+		Name: "Coffee club", Year: 2024, Month: 1, AmountCents: 900,
+	}); err != nil {
+		t.Fatalf("CreateBudgetSource failed: %v", err)
+	}
+	if _, err := repo.UpsertManualBudget(ctx, 1, march, 0, 0, []domain.ManualBudgetItem{
+		{Name: "Café au lait", AmountCents: 300},
+	}); err != nil {
+		t.Fatalf("UpsertManualBudget failed: %v", err)
+	}
+
+	search := func(q domain.SearchQuery) []domain.SearchResult {
+		t.Helper()
+		results, err := repo.Search(ctx, 1, q)
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		return results
+	}
+
+	// Prefix matching, across months and record types; another user's budget
+	// source is not visible.
+	results := search(domain.SearchQuery{Terms: []string{"coff"}})
+	types := map[string]int{}
+	for _, r := range results {
+		types[r.Type]++
+		if r.Score <= 0 {
+			t.Errorf("Expected a positive score, got %+v", r)
+		}
+	}
+	if len(results) != 3 || types[domain.BatchExpense] != 2 || types[domain.BatchIncomeSource] != 1 {
+		t.Fatalf("Expected 2 expenses and 1 income source, got %+v", results)
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i].Score > results[i-1].Score {
+			t.Errorf("Expected results by descending score, got %+v", results)
+		}
+	}
+
+	results = search(domain.SearchQuery{Terms: []string{"coffee", "bean"}})
+	if len(results) != 1 || results[0].Snippet != "<mark>Coffee</mark> <mark>beans</mark> &lt;organic&gt;" ||
+		results[0].YearMonth != march || results[0].Category != "Food" || results[0].AmountCents != 1800 {
+		t.Errorf("Expected the highlighted, escaped coffee beans expense, got %+v", results)
+	}
+
+	// Accents are folded; manual budget items are found.
+	results = search(domain.SearchQuery{Terms: []string{"cafe"}})
+	if len(results) != 1 || results[0].Type != domain.BatchManualBudgetItem || results[0].YearMonth != march {
+		t.Errorf("Expected the manual budget item, got %+v", results)
+	}
+
+	// Filters: the category only matches expenses, case-insensitively.
+	if results = search(domain.SearchQuery{Terms: []string{"coffee"}, Category: "FOOD"}); len(results) != 2 {
+		t.Errorf("Expected 2 food expenses, got %+v", results)
+	}
+	minimum := domain.Money(1000)
+	if results = search(domain.SearchQuery{Terms: []string{"coffee"}, MinAmount: &minimum}); len(results) != 2 {
+		t.Errorf("Expected the beans and the salary, got %+v", results)
+	}
+	if results = search(domain.SearchQuery{Terms: []string{"coffee"}, Limit: 1}); len(results) != 1 {
+		t.Errorf("Expected 1 result, got %d", len(results))
+	}
+	if results = search(domain.SearchQuery{Terms: []string{`"*`, "-"}}); len(results) != 0 {
+		t.Errorf("Expected no results for punctuation, got %+v", results)
+	}
+
+	// The triggers keep the index in sync with updates and deletes.
+	if _, err := repo.UpdateIncomeSource(ctx, income.ID, 1, 0, domain.UpdateSourceRequest{
+		Name: "Bakery salary", AmountCents: 250000,
+	}); err != nil {
+		t.Fatalf("UpdateIncomeSource failed: %v", err)
+	}
+	if results = search(domain.SearchQuery{Terms: []string{"bakery"}}); len(results) != 1 || results[0].ID != income.ID {
+		t.Errorf("Expected the renamed income source, got %+v", results)
+	}
+	if err := repo.DeleteIncomeSource(ctx, income.ID, 1, 0); err != nil {
+		t.Fatalf("DeleteIncomeSource failed: %v", err)
+	}
+	if results = search(domain.SearchQuery{Terms: []string{"salary"}}); len(results) != 0 {
+		t.Errorf("Expected the deleted income source gone, got %+v", results)
+	}
+}
+
+func TestSearchMatchExpressions(t *testing.T) {
+	terms := []string{`say "hi"`, "coffee", "be"}
+	if got, want := ftsMatchExpr(terms), `"say ""hi""" "coffee" "be"*`; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	if got, want := mysqlMatchExpr(terms), `+"say hi" +coffee +be*`; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	if got, want := highlightTerms("Coffee & beans, cocoa", terms), "<mark>Coffee</mark> &amp; <mark>beans</mark>, cocoa"; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
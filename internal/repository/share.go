@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// Share links
+
+// CreateShareLink stores a new share link; the caller assigns ID and expiry.
+func (r *Repository) CreateShareLink(ctx context.Context, link *domain.ShareLink) error {
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = time.Now()
+	}
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO share_links (id, user_id, scope, year, month, label, expires_at, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		link.ID, link.UserID, link.Scope, link.Year, link.Month, nullify(link.Label),
+		link.ExpiresAt.UTC(), link.CreatedAt.UTC())
+	return translateError(err)
+}
+
+const shareLinkColumns = `l.id, l.user_id, l.scope, l.year, l.month, l.label, l.expires_at, l.revoked_at, l.created_at,
+	(SELECT COUNT(1) FROM share_link_access a WHERE a.link_id = l.id),
+	(SELECT MAX(a.accessed_at) FROM share_link_access a WHERE a.link_id = l.id)`
+
+func scanShareLink(row interface{ Scan(...any) error }) (*domain.ShareLink, error) {
+	var (
+		link       domain.ShareLink
+		label      sql.NullString
+		revokedAt  sql.NullTime
+		lastAccess sql.NullString
+	)
+	if err := row.Scan(&link.ID, &link.UserID, &link.Scope, &link.Year, &link.Month, &label,
+		&link.ExpiresAt, &revokedAt, &link.CreatedAt, &link.AccessCount, &lastAccess); err != nil {
+		return nil, err
+	}
+	link.Label = label.String
+	if revokedAt.Valid {
+		link.RevokedAt = &revokedAt.Time
+	}
+	// MAX() loses the column type in SQLite, so parse the aggregate by hand.
+	if t, ok := parseDBTime(lastAccess.String); ok {
+		link.LastAccessAt = &t
+	}
+	return &link, nil
+}
+
+// GetShareLink fetches a share link by ID (ErrNotFound if unknown).
+func (r *Repository) GetShareLink(ctx context.Context, id string) (*domain.ShareLink, error) {
+	link, err := scanShareLink(r.db.QueryRowContext(ctx,
+		`SELECT `+shareLinkColumns+` FROM share_links l WHERE l.id = ?`, id))
+	return link, translateError(err)
+}
+
+// ListShareLinks lists a user's share links, newest first, with access counts.
+func (r *Repository) ListShareLinks(ctx context.Context, userID int64) ([]domain.ShareLink, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+shareLinkColumns+` FROM share_links l WHERE l.user_id = ?
+		 ORDER BY l.created_at DESC, l.id`, userID)
+	if err != nil {
+		return []domain.ShareLink{}, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	links := []domain.ShareLink{}
+	for rows.Next() {
+		link, err := scanShareLink(rows)
+		if err != nil {
+			return []domain.ShareLink{}, err
+		}
+		links = append(links, *link)
+	}
+	return links, rows.Err()
+}
+
+// RevokeShareLink marks a user's share link as revoked. It returns
+// ErrNotFound if the link does not exist, belongs to someone else or was
+// already revoked.
+func (r *Repository) RevokeShareLink(ctx context.Context, id string, userID int64) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE share_links SET revoked_at = ? WHERE id = ? AND user_id = ? AND revoked_at IS NULL`,
+		time.Now().UTC(), id, userID)
+	if err != nil {
+		return translateError(err)
+	}
+	return checkAffected(res)
+}
+
+// LogShareAccess records one use of a share link.
+func (r *Repository) LogShareAccess(ctx context.Context, access domain.ShareAccess) error {
+	if access.AccessedAt.IsZero() {
+		access.AccessedAt = time.Now()
+	}
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO share_link_access (link_id, accessed_at, ip_address, user_agent) VALUES (?, ?, ?, ?)`,
+		access.LinkID, access.AccessedAt.UTC(), nullify(access.IPAddress), nullify(access.UserAgent))
+	return err
+}
+
+// ListShareAccess returns the most recent accesses of a user's share link.
+func (r *Repository) ListShareAccess(
+	ctx context.Context,
+	linkID string,
+	userID int64,
+	limit int,
+) ([]domain.ShareAccess, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT a.id, a.link_id, a.accessed_at, a.ip_address, a.user_agent
+		 FROM share_link_access a JOIN share_links l ON l.id = a.link_id
+		 WHERE a.link_id = ? AND l.user_id = ?
+		 ORDER BY a.accessed_at DESC, a.id DESC LIMIT ?`,
+		linkID, userID, limit)
+	if err != nil {
+		return []domain.ShareAccess{}, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	accesses := []domain.ShareAccess{}
+	for rows.Next() {
+		var a domain.ShareAccess
+		var ip, ua sql.NullString
+		if err := rows.Scan(&a.ID, &a.LinkID, &a.AccessedAt, &ip, &ua); err != nil {
+			return []domain.ShareAccess{}, err
+		}
+		a.IPAddress, a.UserAgent = ip.String, ua.String
+		accesses = append(accesses, a)
+	}
+	return accesses, rows.Err()
+}
+
+// parseDBTime parses timestamps returned untyped by aggregates.
+func parseDBTime(s string) (time.Time, bool) {
+	for _, layout := range []string{
+		time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00", "2006-01-02 15:04:05.999999999", time.DateTime,
+	} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
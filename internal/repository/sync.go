@@ -0,0 +1,398 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// Sync reads record_history as its change log: a cursor is the ID of the
+// last entry a client has seen, and a pull returns the current state of
+// every record written since.
+
+// DefaultSyncLimit and MaxSyncLimit bound the log entries read by one pull.
+const (
+	DefaultSyncLimit = 500
+	MaxSyncLimit     = 1000
+)
+
+// latestHistoryID returns the newest position in the change log.
+func latestHistoryID(ctx context.Context, q querier) (int64, error) {
+	var id int64
+	err := q.QueryRowContext(ctx, `SELECT COALESCE(MAX(id), 0) FROM record_history`).Scan(&id)
+	return id, err
+}
+
+// syncChange returns the current state of a record for a pull: its JSON, or
+// a tombstone if it is in the trash or gone.
+func syncChange(ctx context.Context, q querier, entity string, id int64) (domain.SyncChange, error) {
+	c := domain.SyncChange{Type: entity, ID: id}
+	st, err := loadRecordState(ctx, q, entity, id)
+	if errors.Is(err, ErrNotFound) {
+		c.Deleted = true
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	if entity != domain.SyncManualBudget {
+		src, err := trashSourceFor(entity)
+		if err != nil {
+			return c, err
+		}
+		var trashed bool
+		if err := q.QueryRowContext(ctx,
+			`SELECT deleted_at IS NOT NULL FROM `+src.table+` WHERE id = ?`, id).Scan(&trashed); err != nil {
+			return c, translateError(err)
+		}
+		if trashed {
+			c.Deleted = true
+			return c, nil
+		}
+	}
+	c.Data = st.data
+	return c, nil
+}
+
+// SyncSnapshot returns every live record userID can see, for a client's
+// first pull, with the cursor to pull changes from next. Writes made while
+// it is read may be returned again by that pull.
+func (r *Repository) SyncSnapshot(ctx context.Context, userID int64) (domain.SyncPage, error) {
+	page := domain.SyncPage{Changes: []domain.SyncChange{}}
+	pos, err := latestHistoryID(ctx, r.db)
+	if err != nil {
+		return page, err
+	}
+	queries := []struct {
+		entity string
+		query  string
+		args   []any
+	}{
+		{domain.BatchIncomeSource,
+			`SELECT id FROM income_sources WHERE user_id = ? AND deleted_at IS NULL ORDER BY id`, []any{userID}},
+		{domain.BatchBudgetSource,
+			`SELECT id FROM budget_sources WHERE user_id = ? AND deleted_at IS NULL ORDER BY id`, []any{userID}},
+		{domain.BatchExpense, `SELECT id FROM expense WHERE deleted_at IS NULL ORDER BY id`, nil},
+		{domain.SyncManualBudget, `SELECT id FROM manual_budgets WHERE user_id = ? ORDER BY id`, []any{userID}},
+	}
+	for _, sq := range queries {
+		ids, err := r.queryIDs(ctx, sq.query, sq.args...)
+		if err != nil {
+			return page, err
+		}
+		for _, id := range ids {
+			c, err := syncChange(ctx, r.db, sq.entity, id)
+			if err != nil {
+				return page, err
+			}
+			page.Changes = append(page.Changes, c)
+		}
+	}
+	page.Cursor = domain.EncodeSyncCursor(pos)
+	return page, nil
+}
+
+// queryIDs runs a query selecting IDs.
+func (r *Repository) queryIDs(ctx context.Context, query string, args ...any) ([]int64, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// SyncChanges returns the current state of the records userID can see that
+// were written after change log position after, reading at most limit log
+// entries, oldest first. Each record is returned once however often it was
+// written. Log entries commit in position order (see recordHistory), so no
+// entry below the returned cursor can appear later.
+func (r *Repository) SyncChanges(ctx context.Context, userID, after int64, limit int) (domain.SyncPage, error) {
+	page := domain.SyncPage{Changes: []domain.SyncChange{}, Cursor: domain.EncodeSyncCursor(after)}
+	if limit <= 0 || limit > MaxSyncLimit {
+		limit = DefaultSyncLimit
+	}
+	// Shared expenses have no owner in the log.
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, entity, record_id FROM record_history
+		 WHERE id > ? AND (user_id = ? OR user_id IS NULL) ORDER BY id LIMIT ?`,
+		after, userID, limit+1)
+	if err != nil {
+		return page, err
+	}
+	type record struct {
+		entity string
+		id     int64
+	}
+	var (
+		records []record
+		seen    = make(map[record]bool)
+		n       int
+		pos     = after
+	)
+	for rows.Next() {
+		if n++; n > limit {
+			page.HasMore = true
+			break
+		}
+		var rec record
+		if err := rows.Scan(&pos, &rec.entity, &rec.id); err != nil {
+			_ = rows.Close()
+			return page, err
+		}
+		if !seen[rec] {
+			seen[rec] = true
+			records = append(records, rec)
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return page, err
+	}
+	if err := rows.Err(); err != nil {
+		return page, err
+	}
+
+	for _, rec := range records {
+		c, err := syncChange(ctx, r.db, rec.entity, rec.id)
+		if err != nil {
+			return page, err
+		}
+		page.Changes = append(page.Changes, c)
+	}
+	page.Cursor = domain.EncodeSyncCursor(pos)
+	return page, nil
+}
+
+// PushSyncMutations applies a user's offline mutations in order, each in its
+// own transaction, and returns a result per mutation. A mutation whose
+// ClientID was pushed before is not applied again; its first result is
+// returned. Update and delete mutations conflict when their record was
+// written after the mutation's cursor by anything but the mutations of this
+// push; domain.SyncPolicies decides whether they are then applied. A
+// mutation with a Ref targets the record created by the referenced mutation,
+// pushed earlier in this push or a previous one. Failed mutations do not stop
+// the ones after them.
+func (r *Repository) PushSyncMutations(
+	ctx context.Context,
+	userID int64,
+	mutations []domain.SyncMutation,
+) []domain.SyncResult {
+	own := make(map[int64]bool)
+	results := make([]domain.SyncResult, 0, len(mutations))
+	for _, m := range mutations {
+		res, err := r.pushSyncMutation(ctx, userID, m, own)
+		if err != nil {
+			res = domain.SyncResult{ClientID: m.ClientID, Status: domain.SyncFailed, ID: m.Operation.ID, Err: err}
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// pushSyncMutation applies or replays one mutation. own holds the change log
+// entries written by the push's earlier mutations and gains this one's.
+func (r *Repository) pushSyncMutation(
+	ctx context.Context,
+	userID int64,
+	m domain.SyncMutation,
+	own map[int64]bool,
+) (domain.SyncResult, error) {
+	var (
+		res       domain.SyncResult
+		stored    string
+		historyID sql.NullInt64
+	)
+	err := r.db.QueryRowContext(ctx,
+		`SELECT result_json, history_id FROM sync_mutations WHERE user_id = ? AND client_id = ?`,
+		userID, m.ClientID).Scan(&stored, &historyID)
+	switch {
+	case err == nil:
+		if historyID.Valid {
+			own[historyID.Int64] = true
+		}
+		return res, json.Unmarshal([]byte(stored), &res)
+	case !errors.Is(err, sql.ErrNoRows):
+		return res, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return res, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	op := m.Operation
+	if m.Ref != "" {
+		if op.ID, err = resolveSyncRef(ctx, tx, userID, m.Ref, op.Type); err != nil {
+			return res, err
+		}
+	}
+	res = domain.SyncResult{ClientID: m.ClientID, ID: op.ID}
+	var entity string
+	var recordID int64
+	if op.Op != domain.BatchCreate && m.Cursor > 0 {
+		if entity, recordID, err = syncTarget(ctx, tx, op.Type, op.ID); err != nil {
+			return res, err
+		}
+		if res.Conflict, err = changedSince(ctx, tx, entity, recordID, m.Cursor, own); err != nil {
+			return res, err
+		}
+	}
+
+	var change domain.Change
+	if !res.Conflict || domain.SyncPolicies[op.Type] == domain.SyncLastWriterWins {
+		var br domain.BatchResult
+		br, change, err = r.applyBatchOp(ctx, tx, userID, op)
+		switch {
+		case errors.Is(err, ErrPreconditionFailed):
+			// A stale version is a conflict the client already asked to reject.
+			res.Conflict, err = true, nil
+		case err != nil:
+			return res, err
+		default:
+			res.Status, res.ID, res.Version = domain.SyncApplied, br.ID, br.Version
+		}
+	}
+
+	historyID = sql.NullInt64{}
+	if res.Status == domain.SyncApplied {
+		if entity, recordID, err = syncTarget(ctx, tx, op.Type, res.ID); err != nil {
+			return res, err
+		}
+		if err = tx.QueryRowContext(ctx,
+			`SELECT MAX(id) FROM record_history WHERE entity = ? AND record_id = ?`,
+			entity, recordID).Scan(&historyID); err != nil {
+			return res, err
+		}
+	} else {
+		res.Status = domain.SyncRejected
+		if entity == "" {
+			if entity, recordID, err = syncTarget(ctx, tx, op.Type, op.ID); err != nil {
+				return res, err
+			}
+		}
+		var current domain.SyncChange
+		if current, err = syncChange(ctx, tx, entity, recordID); err != nil {
+			return res, err
+		}
+		res.Current = &current
+	}
+
+	var data []byte
+	if data, err = json.Marshal(res); err != nil {
+		return res, err
+	}
+	if _, err = tx.ExecContext(ctx,
+		`INSERT INTO sync_mutations (user_id, client_id, history_id, result_json) VALUES (?, ?, ?, ?)`,
+		userID, m.ClientID, historyID, string(data)); err != nil {
+		err = translateError(err)
+		return res, err
+	}
+	if err = tx.Commit(); err != nil {
+		return res, err
+	}
+	if historyID.Valid {
+		own[historyID.Int64] = true
+	}
+	if res.Status == domain.SyncApplied {
+		r.notify(ctx, change)
+	}
+	return res, nil
+}
+
+// resolveSyncRef returns the ID of the record of type typ that userID's
+// mutation ref wrote, normally its create.
+func resolveSyncRef(ctx context.Context, q querier, userID int64, ref, typ string) (int64, error) {
+	errBadRef := domain.Invalid("ref does not name an applied mutation of this record type",
+		domain.FieldError{Field: "ref", Message: "names no applied mutation of this type"})
+	var (
+		stored    string
+		historyID sql.NullInt64
+	)
+	err := q.QueryRowContext(ctx,
+		`SELECT result_json, history_id FROM sync_mutations WHERE user_id = ? AND client_id = ?`,
+		userID, ref).Scan(&stored, &historyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, errBadRef
+	}
+	if err != nil {
+		return 0, err
+	}
+	var res domain.SyncResult
+	if err := json.Unmarshal([]byte(stored), &res); err != nil {
+		return 0, err
+	}
+	if res.Status != domain.SyncApplied || !historyID.Valid {
+		return 0, errBadRef
+	}
+	// The create's change log entry must be for the record typ and the
+	// created ID point at, so a ref cannot cross record types.
+	var entity string
+	var recordID int64
+	if err := q.QueryRowContext(ctx,
+		`SELECT entity, record_id FROM record_history WHERE id = ?`,
+		historyID.Int64).Scan(&entity, &recordID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errBadRef
+		}
+		return 0, err
+	}
+	wantEntity, wantID, err := syncTarget(ctx, q, typ, res.ID)
+	if errors.Is(err, ErrNotFound) || (err == nil && (entity != wantEntity || recordID != wantID)) {
+		return 0, errBadRef
+	}
+	return res.ID, err
+}
+
+// syncTarget returns the change log record written by a mutation on record
+// id of typ: the record itself, or for manual budget items their budget.
+func syncTarget(ctx context.Context, q querier, typ string, id int64) (string, int64, error) {
+	if typ != domain.BatchManualBudgetItem {
+		return typ, id, nil
+	}
+	var budgetID int64
+	err := q.QueryRowContext(ctx, `SELECT budget_id FROM manual_budget_items WHERE id = ?`, id).Scan(&budgetID)
+	return domain.SyncManualBudget, budgetID, translateError(err)
+}
+
+// changedSince reports whether a record was written after change log
+// position after by anything but the entries in own.
+func changedSince(
+	ctx context.Context,
+	q querier,
+	entity string,
+	id, after int64,
+	own map[int64]bool,
+) (bool, error) {
+	rows, err := q.QueryContext(ctx,
+		`SELECT id FROM record_history WHERE entity = ? AND record_id = ? AND id > ?`, entity, id, after)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var hid int64
+		if err := rows.Scan(&hid); err != nil {
+			return false, err
+		}
+		if !own[hid] {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
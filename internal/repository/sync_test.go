@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+func TestRepository_SyncPull(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+	march := domain.YearMonth{Year: 2024, Month: 3}
+
+	salary, err := repo.CreateIncomeSource(ctx, 1, domain.CreateIncomeSourceRequest{
+		Name: "Salary", Year: 2024, Month: 3, AmountCents: 250000,
+	})
+	if err != nil {
+		t.Fatalf("CreateIncomeSource failed: %v", err)
+	}
+	if _, err := repo.UpsertManualBudget(ctx, 1, march, 0, 1000, []domain.ManualBudgetItem{
+		{Name: "Gym", AmountCents: 3000},
+	}); err != nil {
+		t.Fatalf("UpsertManualBudget failed: %v", err)
+	}
+	other, err := repo.CreateUser(ctx, "other", "password123", "other@example.com")
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	if _, err := repo.CreateBudgetSource(ctx, other.ID, domain.CreateBudgetSourceRequest{
+		Name: "Rent", Year: 2024, Month: 3, AmountCents: 90000,
+	}); err != nil {
+		t.Fatalf("CreateBudgetSource failed: %v", err)
+	}
+
+	snapshot, err := repo.SyncSnapshot(ctx, 1)
+	if err != nil {
+		t.Fatalf("SyncSnapshot failed: %v", err)
+	}
+	if len(snapshot.Changes) != 2 || snapshot.Changes[0].Type != domain.BatchIncomeSource ||
+		snapshot.Changes[1].Type != domain.SyncManualBudget || snapshot.Changes[1].Data == nil {
+		t.Fatalf("Expected the salary and the manual budget, got %+v", snapshot.Changes)
+	}
+	after, err := domain.DecodeSyncCursor(snapshot.Cursor)
+	if err != nil {
+		t.Fatalf("DecodeSyncCursor failed: %v", err)
+	}
+
+	// Changes since the snapshot include tombstones and each record once.
+	expenseID, err := repo.AddExpense(ctx, &domain.Expense{YearMonth: march, Description: "Coffee", AmountCents: 350})
+	if err != nil {
+		t.Fatalf("AddExpense failed: %v", err)
+	}
+	for _, amount := range []domain.Money{260000, 270000} {
+		if _, err := repo.UpdateIncomeSource(ctx, salary.ID, 1, 0, domain.UpdateSourceRequest{
+			Name: "Salary", AmountCents: amount,
+		}); err != nil {
+			t.Fatalf("UpdateIncomeSource failed: %v", err)
+		}
+	}
+	if err := repo.DeleteExpense(ctx, expenseID); err != nil {
+		t.Fatalf("DeleteExpense failed: %v", err)
+	}
+	page, err := repo.SyncChanges(ctx, 1, after, 0)
+	if err != nil {
+		t.Fatalf("SyncChanges failed: %v", err)
+	}
+	if len(page.Changes) != 2 || page.HasMore {
+		t.Fatalf("Expected the expense and the salary, got %+v", page)
+	}
+	if c := page.Changes[0]; c.Type != domain.BatchExpense || !c.Deleted || c.Data != nil {
+		t.Errorf("Expected an expense tombstone, got %+v", c)
+	}
+	if c := page.Changes[1]; c.ID != salary.ID || c.Deleted || c.Data == nil {
+		t.Errorf("Expected the live salary, got %+v", c)
+	}
+
+	// A small limit pages through the log.
+	page, err = repo.SyncChanges(ctx, 1, after, 2)
+	if err != nil || !page.HasMore || len(page.Changes) != 2 {
+		t.Fatalf("Expected a first page of two records, got %+v (%v)", page, err)
+	}
+	next, _ := domain.DecodeSyncCursor(page.Cursor)
+	page, err = repo.SyncChanges(ctx, 1, next, 2)
+	if err != nil || page.HasMore || len(page.Changes) != 2 {
+		t.Errorf("Expected a last page of two records, got %+v (%v)", page, err)
+	}
+	last, _ := domain.DecodeSyncCursor(page.Cursor)
+	if page, _ := repo.SyncChanges(ctx, 1, last, 0); len(page.Changes) != 0 || page.Cursor != domain.EncodeSyncCursor(last) {
+		t.Errorf("Expected no more changes, got %+v", page)
+	}
+
+	// Every entry was appended under the history lock, which keeps entries
+	// committing in ID order on MySQL.
+	var writes, entries int
+	_ = repo.db.QueryRow(`SELECT writes FROM record_history_lock WHERE id = 1`).Scan(&writes)
+	_ = repo.db.QueryRow(`SELECT COUNT(*) FROM record_history`).Scan(&entries)
+	if writes == 0 || writes != entries {
+		t.Errorf("Expected %d history entries to take the lock, got %d", entries, writes)
+	}
+}
+
+func TestRepository_SyncPush(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+	march := domain.YearMonth{Year: 2024, Month: 3}
+
+	salary, err := repo.CreateIncomeSource(ctx, 1, domain.CreateIncomeSourceRequest{
+		Name: "Salary", Year: 2024, Month: 3, AmountCents: 250000,
+	})
+	if err != nil {
+		t.Fatalf("CreateIncomeSource failed: %v", err)
+	}
+	expenseID, err := repo.AddExpense(ctx, &domain.Expense{YearMonth: march, Description: "Coffee", AmountCents: 350})
+	if err != nil {
+		t.Fatalf("AddExpense failed: %v", err)
+	}
+	snapshot, err := repo.SyncSnapshot(ctx, 1)
+	if err != nil {
+		t.Fatalf("SyncSnapshot failed: %v", err)
+	}
+	cursor, _ := domain.DecodeSyncCursor(snapshot.Cursor)
+
+	// Someone else changes the salary after the client went offline.
+	if _, err := repo.UpdateIncomeSource(ctx, salary.ID, 1, 0, domain.UpdateSourceRequest{
+		Name: "Salary", AmountCents: 260000,
+	}); err != nil {
+		t.Fatalf("UpdateIncomeSource failed: %v", err)
+	}
+	if _, err := repo.AddExpense(ctx, &domain.Expense{YearMonth: march, Description: "Tea", AmountCents: 300}); err != nil {
+		t.Fatalf("AddExpense failed: %v", err)
+	}
+
+	update := func(clientID string, amount domain.Money) domain.SyncMutation {
+		return domain.SyncMutation{ClientID: clientID, Cursor: cursor, Operation: domain.BatchOperation{
+			Op: domain.BatchUpdate, Type: domain.BatchIncomeSource, ID: salary.ID,
+			Source: &domain.UpdateSourceRequest{Name: "Salary", AmountCents: amount},
+		}}
+	}
+	mutations := []domain.SyncMutation{
+		update("0b7e4f4e-9c62-4f55-9d0e-5d6f1f0d9a01", 255000),
+		{ClientID: "0b7e4f4e-9c62-4f55-9d0e-5d6f1f0d9a02", Operation: domain.BatchOperation{
+			Op: domain.BatchCreate, Type: domain.BatchBudgetSource,
+			BudgetSource: &domain.CreateBudgetSourceRequest{Name: "Rent", Year: 2024, Month: 3, AmountCents: 90000},
+		}},
+		{ClientID: "0b7e4f4e-9c62-4f55-9d0e-5d6f1f0d9a03", Cursor: cursor, Operation: domain.BatchOperation{
+			Op: domain.BatchDelete, Type: domain.BatchExpense, ID: expenseID,
+		}},
+		{ClientID: "0b7e4f4e-9c62-4f55-9d0e-5d6f1f0d9a04", Operation: domain.BatchOperation{
+			Op: domain.BatchDelete, Type: domain.BatchExpense, ID: 999,
+		}},
+	}
+	results := repo.PushSyncMutations(ctx, 1, mutations)
+	if len(results) != 4 {
+		t.Fatalf("Expected 4 results, got %+v", results)
+	}
+	if r := results[0]; r.Status != domain.SyncRejected || !r.Conflict || r.Current == nil || r.Current.ID != salary.ID {
+		t.Errorf("Expected the salary update rejected with the server's salary, got %+v", r)
+	}
+	if r := results[1]; r.Status != domain.SyncApplied || r.Conflict || r.ID == 0 || r.Version != 1 {
+		t.Errorf("Expected the rent created, got %+v", r)
+	}
+	if r := results[2]; r.Status != domain.SyncApplied || r.Conflict {
+		t.Errorf("Expected the unchanged expense deleted without conflict, got %+v", r)
+	}
+	if r := results[3]; r.Status != domain.SyncFailed || !errors.Is(r.Err, ErrNotFound) {
+		t.Errorf("Expected deleting an unknown expense to fail, got %+v", r)
+	}
+	if source, _ := repo.GetIncomeSource(ctx, salary.ID, 1); source.AmountCents != 260000 {
+		t.Errorf("Expected the server's salary kept, got %d", source.AmountCents)
+	}
+
+	// Pushing again replays the first results, creating nothing twice.
+	again := repo.PushSyncMutations(ctx, 1, mutations[1:2])
+	if len(again) != 1 || again[0].ID != results[1].ID || again[0].Status != domain.SyncApplied {
+		t.Errorf("Expected the first result replayed, got %+v", again)
+	}
+	if budgets, _ := repo.ListBudgetSources(ctx, 1, march); len(budgets) != 1 {
+		t.Errorf("Expected one budget source, got %d", len(budgets))
+	}
+
+	// Writes of the same push do not conflict with each other, and expenses
+	// are last writer wins.
+	rentID := results[1].ID
+	rentUpdate := func(clientID string, amount domain.Money) domain.SyncMutation {
+		return domain.SyncMutation{ClientID: clientID, Cursor: cursor, Operation: domain.BatchOperation{
+			Op: domain.BatchUpdate, Type: domain.BatchBudgetSource, ID: rentID,
+			Source: &domain.UpdateSourceRequest{Name: "Rent", AmountCents: amount},
+		}}
+	}
+	results = repo.PushSyncMutations(ctx, 1, []domain.SyncMutation{
+		mutations[1],
+		rentUpdate("0b7e4f4e-9c62-4f55-9d0e-5d6f1f0d9a05", 91000),
+		rentUpdate("0b7e4f4e-9c62-4f55-9d0e-5d6f1f0d9a06", 92000),
+	})
+	for i, r := range results {
+		if r.Status != domain.SyncApplied || r.Conflict {
+			t.Errorf("Expected mutation %d applied without conflict, got %+v", i, r)
+		}
+	}
+	if err := repo.RestoreTrashItem(ctx, 1, domain.BatchExpense, expenseID); err != nil {
+		t.Fatalf("RestoreTrashItem failed: %v", err)
+	}
+	results = repo.PushSyncMutations(ctx, 1, []domain.SyncMutation{
+		{ClientID: "0b7e4f4e-9c62-4f55-9d0e-5d6f1f0d9a07", Cursor: cursor, Operation: domain.BatchOperation{
+			Op: domain.BatchDelete, Type: domain.BatchExpense, ID: expenseID,
+		}},
+	})
+	if r := results[0]; r.Status != domain.SyncApplied || !r.Conflict {
+		t.Errorf("Expected the restored expense deleted over the conflict, got %+v", r)
+	}
+}
+
+func TestRepository_SyncPushRef(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+	march := domain.YearMonth{Year: 2024, Month: 3}
+	snapshot, err := repo.SyncSnapshot(ctx, 1)
+	if err != nil {
+		t.Fatalf("SyncSnapshot failed: %v", err)
+	}
+	cursor, _ := domain.DecodeSyncCursor(snapshot.Cursor)
+
+	// A record created offline is updated and deleted in the same push.
+	const (
+		rentCreate   = "7c1d2e3f-4a5b-4c6d-8e9f-0a1b2c3d4e01"
+		coffeeCreate = "7c1d2e3f-4a5b-4c6d-8e9f-0a1b2c3d4e02"
+	)
+	results := repo.PushSyncMutations(ctx, 1, []domain.SyncMutation{
+		{ClientID: rentCreate, Cursor: cursor, Operation: domain.BatchOperation{
+			Op: domain.BatchCreate, Type: domain.BatchBudgetSource,
+			BudgetSource: &domain.CreateBudgetSourceRequest{Name: "Rent", Year: 2024, Month: 3, AmountCents: 90000},
+		}},
+		{ClientID: "7c1d2e3f-4a5b-4c6d-8e9f-0a1b2c3d4e03", Cursor: cursor, Ref: rentCreate,
+			Operation: domain.BatchOperation{
+				Op: domain.BatchUpdate, Type: domain.BatchBudgetSource,
+				Source: &domain.UpdateSourceRequest{Name: "Rent", AmountCents: 95000},
+			}},
+		{ClientID: coffeeCreate, Cursor: cursor, Operation: domain.BatchOperation{
+			Op: domain.BatchCreate, Type: domain.BatchExpense,
+			Expense: &domain.Expense{YearMonth: march, Description: "Coffee", AmountCents: 350},
+		}},
+		{ClientID: "7c1d2e3f-4a5b-4c6d-8e9f-0a1b2c3d4e04", Cursor: cursor, Ref: coffeeCreate,
+			Operation: domain.BatchOperation{Op: domain.BatchDelete, Type: domain.BatchExpense}},
+		{ClientID: "7c1d2e3f-4a5b-4c6d-8e9f-0a1b2c3d4e05", Ref: rentCreate,
+			Operation: domain.BatchOperation{Op: domain.BatchDelete, Type: domain.BatchIncomeSource}},
+		{ClientID: "7c1d2e3f-4a5b-4c6d-8e9f-0a1b2c3d4e06", Ref: "7c1d2e3f-4a5b-4c6d-8e9f-0a1b2c3d4eff",
+			Operation: domain.BatchOperation{Op: domain.BatchDelete, Type: domain.BatchExpense}},
+	})
+	if len(results) != 6 {
+		t.Fatalf("Expected 6 results, got %+v", results)
+	}
+	rentID := results[0].ID
+	if r := results[1]; r.Status != domain.SyncApplied || r.Conflict || r.ID != rentID || r.Version != 2 {
+		t.Errorf("Expected the new rent updated, got %+v", r)
+	}
+	if r := results[3]; r.Status != domain.SyncApplied || r.Conflict || r.ID != results[2].ID {
+		t.Errorf("Expected the new expense deleted, got %+v", r)
+	}
+	for _, r := range results[4:] {
+		if r.Status != domain.SyncFailed || !errors.Is(r.Err, domain.ErrValidation) {
+			t.Errorf("Expected a ref to another type or to nothing to fail, got %+v", r)
+		}
+	}
+	if source, _ := repo.GetBudgetSource(ctx, rentID, 1); source == nil || source.AmountCents != 95000 {
+		t.Errorf("Expected the rent updated, got %+v", source)
+	}
+
+	// A later push can still refer to the create.
+	results = repo.PushSyncMutations(ctx, 1, []domain.SyncMutation{
+		{ClientID: "7c1d2e3f-4a5b-4c6d-8e9f-0a1b2c3d4e07", Ref: rentCreate,
+			Operation: domain.BatchOperation{Op: domain.BatchDelete, Type: domain.BatchBudgetSource}},
+	})
+	if r := results[0]; r.Status != domain.SyncApplied || r.ID != rentID {
+		t.Errorf("Expected the rent deleted, got %+v", r)
+	}
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// Personal access tokens
+
+// CreateAccessToken stores a new token under the hash of its secret and
+// sets its ID. The secret itself is not stored.
+func (r *Repository) CreateAccessToken(ctx context.Context, t *domain.AccessToken, hash string) error {
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = time.Now()
+	}
+	res, err := r.db.ExecContext(ctx,
+		`INSERT INTO access_tokens (user_id, name, token_hash, prefix, scopes, expires_at, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		t.UserID, t.Name, hash, t.Prefix, strings.Join(t.Scopes, ","), nullableTime(t.ExpiresAt), t.CreatedAt.UTC())
+	if err != nil {
+		return translateError(err)
+	}
+	t.ID, err = res.LastInsertId()
+	return err
+}
+
+const accessTokenColumns = `t.id, t.user_id, t.name, t.prefix, t.scopes, t.expires_at, t.last_used_at, t.created_at`
+
+func scanAccessToken(row rowScanner, t *domain.AccessToken) error {
+	var (
+		scopes           string
+		expires, lastUse sql.NullTime
+	)
+	if err := row.Scan(&t.ID, &t.UserID, &t.Name, &t.Prefix, &scopes, &expires, &lastUse, &t.CreatedAt); err != nil {
+		return err
+	}
+	t.Scopes = strings.Split(scopes, ",")
+	if expires.Valid {
+		t.ExpiresAt = &expires.Time
+	}
+	if lastUse.Valid {
+		t.LastUsedAt = &lastUse.Time
+	}
+	return nil
+}
+
+// ListAccessTokens lists a user's tokens, oldest first.
+func (r *Repository) ListAccessTokens(ctx context.Context, userID int64) ([]domain.AccessToken, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+accessTokenColumns+` FROM access_tokens t WHERE t.user_id = ? ORDER BY t.id`, userID)
+	if err != nil {
+		return []domain.AccessToken{}, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	tokens := []domain.AccessToken{}
+	for rows.Next() {
+		var t domain.AccessToken
+		if err := scanAccessToken(rows, &t); err != nil {
+			return []domain.AccessToken{}, err
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens, rows.Err()
+}
+
+// GetAccessTokenByHash returns the token whose secret hashes to hash. It
+// returns ErrNotFound if there is none or its user is gone.
+func (r *Repository) GetAccessTokenByHash(ctx context.Context, hash string) (*domain.AccessToken, error) {
+	var t domain.AccessToken
+	err := scanAccessToken(r.db.QueryRowContext(ctx,
+		`SELECT `+accessTokenColumns+` FROM access_tokens t JOIN users u ON u.id = t.user_id
+		 WHERE t.token_hash = ?`, hash), &t)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &t, nil
+}
+
+// TouchAccessToken records that a token was used at.
+func (r *Repository) TouchAccessToken(ctx context.Context, id int64, at time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE access_tokens SET last_used_at = ? WHERE id = ?`, nullableTime(&at), id)
+	return translateError(err)
+}
+
+// DeleteAccessToken revokes a user's token. It returns ErrNotFound if the
+// token does not exist or belongs to someone else.
+func (r *Repository) DeleteAccessToken(ctx context.Context, id, userID int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM access_tokens WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return translateError(err)
+	}
+	return checkAffected(res)
+}
@@ -0,0 +1,377 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// trashSource describes one table whose rows are soft-deleted by setting
+// deleted_at.
+type trashSource struct {
+	typ      string
+	table    string
+	from     string // the table aliased t, with joined tables if any
+	period   string // year and month columns
+	name     string
+	category string // "''" when the table has none
+	owner    string // owning user column; "NULL" for shared rows
+	budget   string // manual budget holding the row; "0" for other tables
+	restore  string // SET clause that takes a row out of the trash
+}
+
+// Expenses are shared between users, so anyone can restore or purge them.
+var trashSources = []trashSource{
+	{
+		typ: domain.BatchIncomeSource, table: "income_sources", from: "income_sources t",
+		period: "t.year, t.month", name: "t.name", category: "''", owner: "t.user_id", budget: "0",
+		restore: "deleted_at = NULL, version = version + 1, updated_at = CURRENT_TIMESTAMP",
+	},
+	{
+		typ: domain.BatchBudgetSource, table: "budget_sources", from: "budget_sources t",
+		period: "t.year, t.month", name: "t.name", category: "''", owner: "t.user_id", budget: "0",
+		restore: "deleted_at = NULL, version = version + 1, updated_at = CURRENT_TIMESTAMP",
+	},
+	{
+		typ: domain.BatchExpense, table: "expense", from: "expense t",
+		period: "t.year, t.month", name: "t.description", category: "COALESCE(t.category, '')",
+		owner: "NULL", budget: "0", restore: "deleted_at = NULL",
+	},
+	{
+		typ: domain.BatchManualBudgetItem, table: "manual_budget_items",
+		from:   "manual_budget_items t JOIN manual_budgets b ON b.id = t.budget_id",
+		period: "b.year, b.month", name: "t.name", category: "''", owner: "b.user_id", budget: "t.budget_id",
+		restore: "deleted_at = NULL, updated_at = CURRENT_TIMESTAMP",
+	},
+}
+
+// deletedNow is the deleted_at stamp of rows moved to the trash now. Stamps
+// are stored in UTC to the second so they compare as stored.
+func deletedNow() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// trashSourceFor returns the trash source of a record type.
+func trashSourceFor(typ string) (trashSource, error) {
+	for _, src := range trashSources {
+		if src.typ == typ {
+			return src, nil
+		}
+	}
+	types := make([]string, len(trashSources))
+	for i, src := range trashSources {
+		types[i] = src.typ
+	}
+	return trashSource{}, domain.Invalid("invalid record type",
+		domain.FieldError{Field: "type", Message: "must be one of " + strings.Join(types, ", ")})
+}
+
+// ListTrash returns the records userID deleted and can still restore, plus
+// every deleted expense, most recently deleted first.
+func (r *Repository) ListTrash(ctx context.Context, userID int64) ([]domain.TrashItem, error) {
+	var (
+		selects []string
+		args    []any
+	)
+	for _, src := range trashSources {
+		query := fmt.Sprintf(`SELECT '%s', t.id, %s, %s, %s, t.amount_cents, t.deleted_at
+			FROM %s WHERE t.deleted_at IS NOT NULL`,
+			src.typ, src.period, src.name, src.category, src.from)
+		if src.owner != "NULL" {
+			query += ` AND ` + src.owner + ` = ?`
+			args = append(args, userID)
+		}
+		selects = append(selects, query)
+	}
+	rows, err := r.db.QueryContext(ctx, strings.Join(selects, " UNION ALL ")+" ORDER BY 8 DESC, 1, 2", args...)
+	if err != nil {
+		return []domain.TrashItem{}, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	items := []domain.TrashItem{}
+	for rows.Next() {
+		var it domain.TrashItem
+		var amount int64
+		if err := rows.Scan(&it.Type, &it.ID, &it.Year, &it.Month, &it.Name, &it.Category,
+			&amount, &it.DeletedAt); err != nil {
+			return []domain.TrashItem{}, err
+		}
+		it.AmountCents = domain.Money(amount)
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+// trashedRow looks up a record of src in the trash for userID and returns
+// its month and manual budget. It returns ErrNotFound for unknown records,
+// records that are not in the trash and another user's records.
+func trashedRow(
+	ctx context.Context,
+	q querier,
+	src trashSource,
+	userID, id int64,
+) (domain.YearMonth, int64, error) {
+	var (
+		ym       domain.YearMonth
+		owner    sql.NullInt64
+		budgetID int64
+	)
+	err := q.QueryRowContext(ctx,
+		`SELECT `+src.period+`, `+src.owner+`, `+src.budget+` FROM `+src.from+
+			` WHERE t.id = ? AND t.deleted_at IS NOT NULL`, id).
+		Scan(&ym.Year, &ym.Month, &owner, &budgetID)
+	if err != nil {
+		return ym, 0, translateError(err)
+	}
+	if owner.Valid && owner.Int64 != userID {
+		return ym, 0, errNotOwned
+	}
+	return ym, budgetID, nil
+}
+
+// RestoreTrashItem takes one of the records userID can see out of the
+// trash. A restored source gets a new version; a restored manual budget
+// item bumps its budget's version. It returns ErrValidation for unknown
+// record types and ErrNotFound for records that are not in the trash and
+// another user's records.
+func (r *Repository) RestoreTrashItem(ctx context.Context, userID int64, typ string, id int64) error {
+	src, err := trashSourceFor(typ)
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	ym, budgetID, err := trashedRow(ctx, tx, src, userID, id)
+	if err != nil {
+		return err
+	}
+	// Items are recorded in their budget's history; other records go from
+	// no state back to live.
+	var before json.RawMessage
+	if typ == domain.BatchManualBudgetItem {
+		if before, err = snapshotBefore(ctx, tx, domain.ChangeManualBudget, budgetID); err != nil {
+			return err
+		}
+	}
+	if _, err = tx.ExecContext(ctx, `UPDATE `+src.table+` SET `+src.restore+` WHERE id = ?`, id); err != nil {
+		return translateError(err)
+	}
+	change := domain.Change{YearMonth: ym, Entity: typ, Action: domain.ChangeRestored, ID: id}
+	switch typ {
+	case domain.BatchIncomeSource, domain.BatchBudgetSource:
+		change.UserID = userID
+		err = tx.QueryRowContext(ctx, `SELECT version FROM `+src.table+` WHERE id = ?`, id).Scan(&change.Version)
+		if err == nil {
+			err = recordHistory(ctx, tx, typ, id, domain.ChangeRestored, nil)
+		}
+	case domain.BatchExpense:
+		err = recordHistory(ctx, tx, typ, id, domain.ChangeRestored, nil)
+	case domain.BatchManualBudgetItem:
+		change = domain.Change{UserID: userID, Entity: domain.ChangeManualBudget, Action: domain.ChangeUpdated}
+		change.Version, change.YearMonth, err = bumpManualBudgetVersion(ctx, tx, budgetID, before)
+	}
+	if err != nil {
+		return translateError(err)
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	r.notify(ctx, change)
+	return nil
+}
+
+// PurgeTrashItem deletes one of the records userID can see from the trash
+// for good, with its snapshots in the history, with the same errors as
+// RestoreTrashItem.
+func (r *Repository) PurgeTrashItem(ctx context.Context, userID int64, typ string, id int64) error {
+	src, err := trashSourceFor(typ)
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var budgetID int64
+	if _, budgetID, err = trashedRow(ctx, tx, src, userID, id); err != nil {
+		return err
+	}
+	if err = redactHistory(ctx, tx, typ, id, budgetID); err != nil {
+		return err
+	}
+	var res sql.Result
+	if res, err = tx.ExecContext(ctx,
+		`DELETE FROM `+src.table+` WHERE id = ? AND deleted_at IS NOT NULL`, id); err != nil {
+		err = translateError(err)
+		return err
+	}
+	if err = checkAffected(res); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// PurgeTrash deletes every record that went to the trash before cutoff, for
+// all users, with their snapshots in the history, and returns how many it
+// deleted.
+func (r *Repository) PurgeTrash(ctx context.Context, cutoff time.Time) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	cutoff = cutoff.UTC().Truncate(time.Second)
+	var total int64
+	for _, src := range trashSources {
+		var n int64
+		if n, err = purgeTrashSource(ctx, tx, src, cutoff); err != nil {
+			return total, err
+		}
+		total += n
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// purgeTrashSource deletes the rows of src that went to the trash before
+// cutoff and redacts their history.
+func purgeTrashSource(ctx context.Context, tx *sql.Tx, src trashSource, cutoff time.Time) (int64, error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT t.id, `+src.budget+` FROM `+src.from+` WHERE t.deleted_at IS NOT NULL AND t.deleted_at < ?`, cutoff)
+	if err != nil {
+		return 0, translateError(err)
+	}
+	type purged struct{ id, budgetID int64 }
+	var rs []purged
+	for rows.Next() {
+		var p purged
+		if err := rows.Scan(&p.id, &p.budgetID); err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+		rs = append(rs, p)
+	}
+	if err := rows.Close(); err != nil {
+		return 0, err
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, p := range rs {
+		if err := redactHistory(ctx, tx, src.typ, p.id, p.budgetID); err != nil {
+			return 0, err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+src.table+` WHERE id = ?`, p.id); err != nil {
+			return 0, translateError(err)
+		}
+	}
+	return int64(len(rs)), nil
+}
+
+// redactHistory drops the snapshots of a purged record from record_history.
+// Its entries stay, so sync clients still pull a tombstone for it. A manual
+// budget item is removed from the snapshots of its budget instead.
+func redactHistory(ctx context.Context, q querier, typ string, id, budgetID int64) error {
+	if typ != domain.BatchManualBudgetItem {
+		_, err := q.ExecContext(ctx,
+			`UPDATE record_history SET before_json = NULL, after_json = NULL WHERE entity = ? AND record_id = ?`,
+			typ, id)
+		return translateError(err)
+	}
+
+	rows, err := q.QueryContext(ctx,
+		`SELECT id, before_json, after_json FROM record_history WHERE entity = ? AND record_id = ?`,
+		domain.ChangeManualBudget, budgetID)
+	if err != nil {
+		return err
+	}
+	type entry struct {
+		id            int64
+		before, after sql.NullString
+	}
+	var entries []entry
+	for rows.Next() {
+		var e entry
+		if err := rows.Scan(&e.id, &e.before, &e.after); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		before, changedBefore, err := withoutItem(e.before, id)
+		if err != nil {
+			return err
+		}
+		after, changedAfter, err := withoutItem(e.after, id)
+		if err != nil {
+			return err
+		}
+		if !changedBefore && !changedAfter {
+			continue
+		}
+		if _, err := q.ExecContext(ctx,
+			`UPDATE record_history SET before_json = ?, after_json = ? WHERE id = ?`, before, after, e.id); err != nil {
+			return translateError(err)
+		}
+	}
+	return nil
+}
+
+// withoutItem returns a manual budget snapshot without item id, and whether
+// the snapshot held it.
+func withoutItem(snapshot sql.NullString, id int64) (sql.NullString, bool, error) {
+	if !snapshot.Valid {
+		return snapshot, false, nil
+	}
+	var mb domain.ManualBudget
+	if err := json.Unmarshal([]byte(snapshot.String), &mb); err != nil {
+		return snapshot, false, err
+	}
+	items := slices.DeleteFunc(slices.Clone(mb.Items), func(it domain.ManualBudgetItem) bool { return it.ID == id })
+	if len(items) == len(mb.Items) {
+		return snapshot, false, nil
+	}
+	mb.Items = items
+	data, err := json.Marshal(mb)
+	if err != nil {
+		return snapshot, false, err
+	}
+	return sql.NullString{String: string(data), Valid: true}, true, nil
+}
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+func TestRepository_Trash(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+	var changes []domain.Change
+	repo.OnChange(func(_ context.Context, c domain.Change) { changes = append(changes, c) })
+
+	other, err := repo.CreateUser(ctx, "other", "password123", "other@example.com")
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	march := domain.YearMonth{Year: 2024, Month: 3}
+	income, err := repo.CreateIncomeSource(ctx, 1, domain.CreateIncomeSourceRequest{
+		Name: "Salary", Year: 2024, Month: 3, AmountCents: 250000,
+	})
+	if err != nil {
+		t.Fatalf("CreateIncomeSource failed: %v", err)
+	}
+	budget, err := repo.CreateBudgetSource(ctx, other.ID, domain.CreateBudgetSourceRequest{
+		Name: "Rent", Year: 2024, Month: 3, AmountCents: 90000,
+	})
+	if err != nil {
+		t.Fatalf("CreateBudgetSource failed: %v", err)
+	}
+	expenseID, err := repo.AddExpense(ctx, &domain.Expense{
+		YearMonth: march, Category: "Food", Description: "Groceries", AmountCents: 4200,
+	})
+	if err != nil {
+		t.Fatalf("AddExpense failed: %v", err)
+	}
+
+	if err := repo.DeleteIncomeSource(ctx, income.ID, 1, 0); err != nil {
+		t.Fatalf("DeleteIncomeSource failed: %v", err)
+	}
+	if err := repo.DeleteBudgetSource(ctx, budget.ID, other.ID, 0); err != nil {
+		t.Fatalf("DeleteBudgetSource failed: %v", err)
+	}
+	if err := repo.DeleteExpense(ctx, expenseID); err != nil {
+		t.Fatalf("DeleteExpense failed: %v", err)
+	}
+
+	// Deleted rows are hidden from reads and can be neither changed nor
+	// deleted again.
+	if sources, _ := repo.ListIncomeSources(ctx, 1, march); len(sources) != 0 {
+		t.Errorf("Expected no income sources, got %+v", sources)
+	}
+	if expenses, _ := repo.ListExpenses(ctx, march); len(expenses) != 0 {
+		t.Errorf("Expected no expenses, got %+v", expenses)
+	}
+	if total, _ := repo.GetExpensesTotal(ctx, march); total != 0 {
+		t.Errorf("Expected an expenses total of 0, got %d", total)
+	}
+	if _, err := repo.GetIncomeSource(ctx, income.ID, 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound reading a deleted source, got %v", err)
+	}
+	if _, err := repo.UpdateIncomeSource(ctx, income.ID, 1, 0, domain.UpdateSourceRequest{
+		Name: "Bonus", AmountCents: 1,
+	}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound updating a deleted source, got %v", err)
+	}
+	if err := repo.DeleteExpense(ctx, expenseID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound deleting twice, got %v", err)
+	}
+	if results, _ := repo.Search(ctx, 1, domain.SearchQuery{Terms: []string{"groceries"}}); len(results) != 0 {
+		t.Errorf("Expected deleted rows to be left out of search, got %+v", results)
+	}
+
+	// Each user sees their own sources and every expense.
+	items, err := repo.ListTrash(ctx, 1)
+	if err != nil {
+		t.Fatalf("ListTrash failed: %v", err)
+	}
+	if len(items) != 2 || items[0].DeletedAt.IsZero() {
+		t.Fatalf("Expected the income source and the expense, got %+v", items)
+	}
+	for _, it := range items {
+		if it.Type == domain.BatchExpense && (it.Name != "Groceries" || it.Category != "Food" ||
+			it.AmountCents != 4200 || it.YearMonth != march) {
+			t.Errorf("Expected the groceries expense, got %+v", it)
+		}
+	}
+	if err := repo.RestoreTrashItem(ctx, 1, domain.BatchBudgetSource, budget.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound restoring another user's source, got %v", err)
+	}
+	if err := repo.RestoreTrashItem(ctx, 1, "salary", 1); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error for an unknown type, got %v", err)
+	}
+
+	// Restoring brings a source back with a new version.
+	changes = nil
+	if err := repo.RestoreTrashItem(ctx, 1, domain.BatchIncomeSource, income.ID); err != nil {
+		t.Fatalf("RestoreTrashItem failed: %v", err)
+	}
+	restored, err := repo.GetIncomeSource(ctx, income.ID, 1)
+	if err != nil || restored.Version != 3 {
+		t.Fatalf("Expected the restored source at version 3, got %+v (%v)", restored, err)
+	}
+	if len(changes) != 1 || changes[0].Action != domain.ChangeRestored || changes[0].Version != 3 {
+		t.Errorf("Expected one restored change, got %+v", changes)
+	}
+	if err := repo.RestoreTrashItem(ctx, 1, domain.BatchIncomeSource, income.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound restoring a live source, got %v", err)
+	}
+
+	// Purging deletes for good.
+	if err := repo.PurgeTrashItem(ctx, 1, domain.BatchExpense, expenseID); err != nil {
+		t.Fatalf("PurgeTrashItem failed: %v", err)
+	}
+	if err := repo.RestoreTrashItem(ctx, 1, domain.BatchExpense, expenseID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound restoring a purged expense, got %v", err)
+	}
+	if items, _ := repo.ListTrash(ctx, 1); len(items) != 0 {
+		t.Errorf("Expected an empty trash, got %+v", items)
+	}
+	if entries, snapshots := historySnapshots(t, repo, domain.BatchExpense, expenseID); entries != 2 || snapshots != 0 {
+		t.Errorf("Expected the expense's 2 history entries without snapshots, got %d with %d", entries, snapshots)
+	}
+
+	// The scheduled purge only removes rows deleted before the cutoff.
+	n, err := repo.PurgeTrash(ctx, time.Now().Add(-time.Hour))
+	if err != nil || n != 0 {
+		t.Errorf("Expected nothing purged before the deletes, got %d (%v)", n, err)
+	}
+	n, err = repo.PurgeTrash(ctx, time.Now().Add(time.Hour))
+	if err != nil || n != 1 {
+		t.Errorf("Expected the budget source purged, got %d (%v)", n, err)
+	}
+	if items, _ := repo.ListTrash(ctx, other.ID); len(items) != 0 {
+		t.Errorf("Expected an empty trash, got %+v", items)
+	}
+	if _, snapshots := historySnapshots(t, repo, domain.BatchBudgetSource, budget.ID); snapshots != 0 {
+		t.Errorf("Expected no snapshots of the purged budget source, got %d", snapshots)
+	}
+	if _, snapshots := historySnapshots(t, repo, domain.BatchIncomeSource, income.ID); snapshots == 0 {
+		t.Error("Expected the live income source's history kept")
+	}
+}
+
+// historySnapshots counts the history entries of a record and the before and
+// after snapshots they hold.
+func historySnapshots(t *testing.T, repo *Repository, entity string, id int64) (entries, snapshots int) {
+	t.Helper()
+	err := repo.db.QueryRow(
+		`SELECT COUNT(*), COALESCE(SUM((before_json IS NOT NULL) + (after_json IS NOT NULL)), 0)
+		 FROM record_history WHERE entity = ? AND record_id = ?`, entity, id).Scan(&entries, &snapshots)
+	if err != nil {
+		t.Fatalf("Failed to read history: %v", err)
+	}
+	return entries, snapshots
+}
+
+func TestRepository_TrashManualBudgetItems(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+	march := domain.YearMonth{Year: 2024, Month: 3}
+
+	if _, err := repo.UpsertManualBudget(ctx, 1, march, 0, 1000, []domain.ManualBudgetItem{
+		{Name: "Gym", AmountCents: 3000}, {Name: "Phone", AmountCents: 2000}, {Name: "Gym", AmountCents: 3000},
+	}); err != nil {
+		t.Fatalf("UpsertManualBudget failed: %v", err)
+	}
+	before, err := repo.GetManualBudget(ctx, 1, march)
+	if err != nil {
+		t.Fatalf("GetManualBudget failed: %v", err)
+	}
+
+	// Saving keeps unchanged items and trashes the dropped and edited ones.
+	if _, err := repo.UpsertManualBudget(ctx, 1, march, 0, 1000, []domain.ManualBudgetItem{
+		{Name: "Gym", AmountCents: 3000}, {Name: "Phone", AmountCents: 2500},
+	}); err != nil {
+		t.Fatalf("UpsertManualBudget failed: %v", err)
+	}
+	mb, err := repo.GetManualBudget(ctx, 1, march)
+	if err != nil {
+		t.Fatalf("GetManualBudget failed: %v", err)
+	}
+	if len(mb.Items) != 2 || mb.Items[0].ID != before.Items[0].ID || mb.Items[1].AmountCents != 2500 {
+		t.Fatalf("Expected the first gym item kept and the new phone price, got %+v", mb.Items)
+	}
+	items, err := repo.ListTrash(ctx, 1)
+	if err != nil {
+		t.Fatalf("ListTrash failed: %v", err)
+	}
+	if len(items) != 2 || items[0].Type != domain.BatchManualBudgetItem || items[0].YearMonth != march {
+		t.Fatalf("Expected the old phone and second gym items, got %+v", items)
+	}
+
+	// Restoring an item adds it back and bumps the budget's version.
+	if err := repo.RestoreTrashItem(ctx, 1, domain.BatchManualBudgetItem, before.Items[1].ID); err != nil {
+		t.Fatalf("RestoreTrashItem failed: %v", err)
+	}
+	restored, err := repo.GetManualBudget(ctx, 1, march)
+	if err != nil {
+		t.Fatalf("GetManualBudget failed: %v", err)
+	}
+	if len(restored.Items) != 3 || restored.Version != mb.Version+1 {
+		t.Errorf("Expected 3 items at version %d, got %+v", mb.Version+1, restored)
+	}
+
+	// Purging an item removes it from every snapshot of its budget.
+	purged := before.Items[2].ID
+	if err := repo.PurgeTrashItem(ctx, 1, domain.BatchManualBudgetItem, purged); err != nil {
+		t.Fatalf("PurgeTrashItem failed: %v", err)
+	}
+	history, err := repo.ListHistory(ctx, 1, domain.ChangeManualBudget, mb.ID)
+	if err != nil || len(history) == 0 {
+		t.Fatalf("Expected the budget's history kept, got %+v (%v)", history, err)
+	}
+	for _, e := range history {
+		for _, snapshot := range []json.RawMessage{e.Before, e.After} {
+			var b domain.ManualBudget
+			if snapshot == nil {
+				continue
+			}
+			if err := json.Unmarshal(snapshot, &b); err != nil {
+				t.Fatalf("Failed to decode snapshot: %v", err)
+			}
+			for _, it := range b.Items {
+				if it.ID == purged {
+					t.Errorf("Expected the purged item redacted from history entry %d, got %s", e.ID, snapshot)
+				}
+			}
+		}
+	}
+}
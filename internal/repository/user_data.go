@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// Settings
+
+// GetUserSettings returns all settings stored for a user (empty map if none).
+func (r *Repository) GetUserSettings(ctx context.Context, userID int64) (domain.UserSettings, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT setting_key, setting_value FROM user_settings WHERE user_id = ? ORDER BY setting_key`, userID)
+	if err != nil {
+		return domain.UserSettings{}, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	settings := domain.UserSettings{}
+	for rows.Next() {
+		var k, v string
+		if err := rows.Scan(&k, &v); err != nil {
+			return domain.UserSettings{}, err
+		}
+		settings[k] = v
+	}
+	return settings, rows.Err()
+}
+
+// SetUserSettings upserts the provided settings; keys not present are left untouched.
+func (r *Repository) SetUserSettings(
+	ctx context.Context,
+	userID int64,
+	settings domain.UserSettings,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err = upsertSettings(ctx, tx, userID, settings); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func upsertSettings(ctx context.Context, tx *sql.Tx, userID int64, settings domain.UserSettings) error {
+	for k, v := range settings {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO user_settings(user_id, setting_key, setting_value) VALUES(?, ?, ?)
+			 ON CONFLICT(user_id, setting_key) DO UPDATE SET setting_value=excluded.setting_value, updated_at=CURRENT_TIMESTAMP`,
+			userID, k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Full account export / import
+
+// ExportUserData loads every income source, budget source, manual budget and
+// setting owned by the user, plus all expenses (expenses are not user-scoped).
+func (r *Repository) ExportUserData(ctx context.Context, userID int64) (*domain.UserDataSet, error) {
+	income, err := r.listAllIncomeSources(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	budget, err := r.listAllBudgetSources(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	expenses, err := r.listAllExpenses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	manual, err := r.listManualBudgets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := r.GetUserSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.UserDataSet{
+		IncomeSources: income,
+		BudgetSources: budget,
+		Expenses:      expenses,
+		ManualBudgets: manual,
+		Settings:      settings,
+	}, nil
+}
+
+// ImportUserData inserts the given data for a user in a single transaction.
+// Manual budgets replace any existing budget for the same month.
+func (r *Repository) ImportUserData(
+	ctx context.Context,
+	userID int64,
+	data *domain.UserDataSet,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	now := time.Now()
+	for _, s := range data.IncomeSources {
+		if _, err = insertSource(ctx, tx, "income_sources", userID, s.Name, s.YearMonth,
+			s.AmountCents, s.DayOfMonth, now); err != nil {
+			return err
+		}
+	}
+	for _, s := range data.BudgetSources {
+		if _, err = insertSource(ctx, tx, "budget_sources", userID, s.Name, s.YearMonth,
+			s.AmountCents, s.DayOfMonth, now); err != nil {
+			return err
+		}
+	}
+	for i := range data.Expenses {
+		if _, err = insertExpense(ctx, tx, &data.Expenses[i]); err != nil {
+			return err
+		}
+	}
+	for _, mb := range data.ManualBudgets {
+		if _, _, err = r.saveManualBudget(ctx, tx, userID, mb.YearMonth, 0, mb.BankAmountCents, mb.Items,
+			domain.ChangeUpdated); err != nil {
+			return err
+		}
+	}
+	if err = upsertSettings(ctx, tx, userID, data.Settings); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) listAllIncomeSources(ctx context.Context, userID int64) ([]domain.IncomeSource, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, user_id, name, year, month, amount_cents, day_of_month, created_at, updated_at
+		 FROM income_sources WHERE user_id = ? AND deleted_at IS NULL ORDER BY year, month, name`, userID)
+	if err != nil {
+		return []domain.IncomeSource{}, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	sources := []domain.IncomeSource{}
+	for rows.Next() {
+		var s domain.IncomeSource
+		var amount int64
+		var day sql.NullInt64
+		if err := rows.Scan(&s.ID, &s.UserID, &s.Name, &s.Year, &s.Month, &amount, &day,
+			&s.CreatedAt, &s.UpdatedAt); err != nil {
+			return []domain.IncomeSource{}, err
+		}
+		s.AmountCents = domain.Money(amount)
+		s.DayOfMonth = dayPtr(day)
+		sources = append(sources, s)
+	}
+	return sources, rows.Err()
+}
+
+func (r *Repository) listAllBudgetSources(ctx context.Context, userID int64) ([]domain.BudgetSource, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, user_id, name, year, month, amount_cents, day_of_month, created_at, updated_at
+		 FROM budget_sources WHERE user_id = ? AND deleted_at IS NULL ORDER BY year, month, name`, userID)
+	if err != nil {
+		return []domain.BudgetSource{}, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	sources := []domain.BudgetSource{}
+	for rows.Next() {
+		var s domain.BudgetSource
+		var amount int64
+		var day sql.NullInt64
+		if err := rows.Scan(&s.ID, &s.UserID, &s.Name, &s.Year, &s.Month, &amount, &day,
+			&s.CreatedAt, &s.UpdatedAt); err != nil {
+			return []domain.BudgetSource{}, err
+		}
+		s.AmountCents = domain.Money(amount)
+		s.DayOfMonth = dayPtr(day)
+		sources = append(sources, s)
+	}
+	return sources, rows.Err()
+}
+
+func (r *Repository) listAllExpenses(ctx context.Context) ([]domain.Expense, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, year, month, category, description, amount_cents, created_at
+		 FROM expense WHERE deleted_at IS NULL ORDER BY year, month, id`)
+	if err != nil {
+		return []domain.Expense{}, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []domain.Expense{}
+	for rows.Next() {
+		var e domain.Expense
+		var category sql.NullString
+		var amount int64
+		if err := rows.Scan(&e.ID, &e.Year, &e.Month, &category, &e.Description, &amount, &e.CreatedAt); err != nil {
+			return []domain.Expense{}, err
+		}
+		e.Category = category.String
+		e.AmountCents = domain.Money(amount)
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (r *Repository) listManualBudgets(ctx context.Context, userID int64) ([]domain.ManualBudget, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT year, month FROM manual_budgets WHERE user_id = ? ORDER BY year, month`, userID)
+	if err != nil {
+		return []domain.ManualBudget{}, err
+	}
+	var months []domain.YearMonth
+	for rows.Next() {
+		var ym domain.YearMonth
+		if err := rows.Scan(&ym.Year, &ym.Month); err != nil {
+			_ = rows.Close()
+			return []domain.ManualBudget{}, err
+		}
+		months = append(months, ym)
+	}
+	// Release the connection before the per-month lookups below.
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return []domain.ManualBudget{}, err
+	}
+
+	out := make([]domain.ManualBudget, 0, len(months))
+	for _, ym := range months {
+		mb, err := r.GetManualBudget(ctx, userID, ym)
+		if err != nil {
+			return []domain.ManualBudget{}, err
+		}
+		out = append(out, *mb)
+	}
+	return out, nil
+}
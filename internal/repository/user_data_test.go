@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+func TestRepository_UserData_RoundTrip(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+	// An in-memory SQLite database is per connection; keep everything on one.
+	repo.db.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+	const userID = int64(1)
+	ym := domain.YearMonth{Year: 2024, Month: 5}
+
+	data := &domain.UserDataSet{
+		IncomeSources: []domain.IncomeSource{{Name: "Salary", YearMonth: ym, AmountCents: 300000}},
+		BudgetSources: []domain.BudgetSource{{Name: "Rent", YearMonth: ym, AmountCents: 90000}},
+		Expenses: []domain.Expense{
+			{YearMonth: ym, Category: "Food", Description: "Groceries", AmountCents: 4500},
+		},
+		ManualBudgets: []domain.ManualBudget{{
+			YearMonth:       ym,
+			BankAmountCents: 120000,
+			Items:           []domain.ManualBudgetItem{{Name: "Gift", AmountCents: -2500}},
+		}},
+		Settings: domain.UserSettings{"locale": "fr", "currency": "EUR"},
+	}
+
+	if err := repo.ImportUserData(ctx, userID, data); err != nil {
+		t.Fatalf("ImportUserData failed: %v", err)
+	}
+
+	got, err := repo.ExportUserData(ctx, userID)
+	if err != nil {
+		t.Fatalf("ExportUserData failed: %v", err)
+	}
+	if len(got.IncomeSources) != 1 || got.IncomeSources[0].AmountCents != 300000 {
+		t.Errorf("Expected 1 income source of 300000, got %+v", got.IncomeSources)
+	}
+	if len(got.BudgetSources) != 1 || got.BudgetSources[0].Name != "Rent" {
+		t.Errorf("Expected 1 budget source named Rent, got %+v", got.BudgetSources)
+	}
+	if len(got.Expenses) != 1 || got.Expenses[0].Category != "Food" {
+		t.Errorf("Expected 1 Food expense, got %+v", got.Expenses)
+	}
+	if len(got.ManualBudgets) != 1 || got.ManualBudgets[0].BankAmountCents != 120000 ||
+		len(got.ManualBudgets[0].Items) != 1 {
+		t.Errorf("Unexpected manual budgets: %+v", got.ManualBudgets)
+	}
+	if got.Settings["locale"] != "fr" || got.Settings["currency"] != "EUR" {
+		t.Errorf("Unexpected settings: %+v", got.Settings)
+	}
+
+	if err := repo.SetUserSettings(ctx, userID, domain.UserSettings{"locale": "en"}); err != nil {
+		t.Fatalf("SetUserSettings failed: %v", err)
+	}
+	settings, err := repo.GetUserSettings(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetUserSettings failed: %v", err)
+	}
+	if settings["locale"] != "en" || settings["currency"] != "EUR" {
+		t.Errorf("Expected locale=en and currency kept, got %+v", settings)
+	}
+}
@@ -0,0 +1,298 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// Webhooks
+
+// CreateWebhook stores a new webhook and sets its ID.
+func (r *Repository) CreateWebhook(ctx context.Context, w *domain.Webhook) error {
+	if w.CreatedAt.IsZero() {
+		w.CreatedAt = time.Now()
+	}
+	res, err := r.db.ExecContext(ctx,
+		`INSERT INTO webhooks (user_id, url, event_types, secret, created_at) VALUES (?, ?, ?, ?, ?)`,
+		w.UserID, w.URL, strings.Join(w.EventTypes, ","), w.Secret, w.CreatedAt.UTC())
+	if err != nil {
+		return translateError(err)
+	}
+	w.ID, err = res.LastInsertId()
+	return err
+}
+
+const webhookColumns = `w.id, w.user_id, w.url, w.event_types, w.secret, w.created_at`
+
+func scanWebhook(row rowScanner, w *domain.Webhook) error {
+	var types string
+	if err := row.Scan(&w.ID, &w.UserID, &w.URL, &types, &w.Secret, &w.CreatedAt); err != nil {
+		return err
+	}
+	w.EventTypes = strings.Split(types, ",")
+	return nil
+}
+
+// ListWebhooks lists a user's webhooks, oldest first, secrets included.
+func (r *Repository) ListWebhooks(ctx context.Context, userID int64) ([]domain.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+webhookColumns+` FROM webhooks w WHERE w.user_id = ? ORDER BY w.id`, userID)
+	if err != nil {
+		return []domain.Webhook{}, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	hooks := []domain.Webhook{}
+	for rows.Next() {
+		var w domain.Webhook
+		if err := scanWebhook(rows, &w); err != nil {
+			return []domain.Webhook{}, err
+		}
+		hooks = append(hooks, w)
+	}
+	return hooks, rows.Err()
+}
+
+// DeleteWebhook deletes a user's webhook with its deliveries. It returns
+// ErrNotFound if the webhook does not exist or belongs to someone else.
+func (r *Repository) DeleteWebhook(ctx context.Context, id, userID int64) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM webhooks WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return translateError(err)
+	}
+	if err = checkAffected(res); err != nil {
+		return err
+	}
+	// Foreign keys are not enforced on every SQLite connection, so cascade by hand.
+	if _, err = tx.ExecContext(ctx,
+		`DELETE FROM webhook_attempts WHERE delivery_id IN (SELECT id FROM webhook_deliveries WHERE webhook_id = ?)`,
+		id); err != nil {
+		return translateError(err)
+	}
+	if _, err = tx.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE webhook_id = ?`, id); err != nil {
+		return translateError(err)
+	}
+	return tx.Commit()
+}
+
+// Webhook deliveries
+
+// CreateWebhookDelivery queues a delivery and sets its ID.
+func (r *Repository) CreateWebhookDelivery(ctx context.Context, d *domain.WebhookDelivery) error {
+	now := time.Now()
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = now
+	}
+	d.UpdatedAt = d.CreatedAt
+	res, err := r.db.ExecContext(ctx,
+		`INSERT INTO webhook_deliveries
+		 (webhook_id, event_id, event_type, payload, status, next_attempt_at, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		d.WebhookID, d.EventID, d.EventType, string(d.Payload), d.Status,
+		nullableTime(d.NextAttemptAt), d.CreatedAt.UTC(), d.UpdatedAt.UTC())
+	if err != nil {
+		return translateError(err)
+	}
+	d.ID, err = res.LastInsertId()
+	return err
+}
+
+const webhookDeliveryColumns = `d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+	d.response_code, d.next_attempt_at, d.created_at, d.updated_at`
+
+func scanWebhookDelivery(row rowScanner, d *domain.WebhookDelivery, extra ...any) error {
+	var (
+		payload string
+		code    sql.NullInt64
+		next    sql.NullTime
+	)
+	dest := []any{&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts,
+		&code, &next, &d.CreatedAt, &d.UpdatedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+	d.Payload = []byte(payload)
+	d.ResponseCode = int(code.Int64)
+	if next.Valid {
+		d.NextAttemptAt = &next.Time
+	}
+	return nil
+}
+
+// ListWebhookDeliveries returns the most recent deliveries of a user's webhook.
+func (r *Repository) ListWebhookDeliveries(
+	ctx context.Context,
+	webhookID, userID int64,
+	limit int,
+) ([]domain.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+webhookDeliveryColumns+`
+		 FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id
+		 WHERE d.webhook_id = ? AND w.user_id = ?
+		 ORDER BY d.id DESC LIMIT ?`,
+		webhookID, userID, limit)
+	if err != nil {
+		return []domain.WebhookDelivery{}, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	deliveries := []domain.WebhookDelivery{}
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		if err := scanWebhookDelivery(rows, &d); err != nil {
+			return []domain.WebhookDelivery{}, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// GetWebhookDelivery returns a delivery of a user's webhook with its
+// attempts, oldest first. It returns ErrNotFound if there is no such delivery.
+func (r *Repository) GetWebhookDelivery(
+	ctx context.Context,
+	id, webhookID, userID int64,
+) (*domain.WebhookDelivery, error) {
+	var d domain.WebhookDelivery
+	err := scanWebhookDelivery(r.db.QueryRowContext(ctx,
+		`SELECT `+webhookDeliveryColumns+`
+		 FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id
+		 WHERE d.id = ? AND d.webhook_id = ? AND w.user_id = ?`,
+		id, webhookID, userID), &d)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, delivery_id, attempted_at, response_code, error, duration_ms
+		 FROM webhook_attempts WHERE delivery_id = ? ORDER BY id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	d.History = []domain.WebhookAttempt{}
+	for rows.Next() {
+		var a domain.WebhookAttempt
+		var code sql.NullInt64
+		var msg sql.NullString
+		if err := rows.Scan(&a.ID, &a.DeliveryID, &a.AttemptedAt, &code, &msg, &a.DurationMS); err != nil {
+			return nil, err
+		}
+		a.ResponseCode, a.Error = int(code.Int64), msg.String
+		d.History = append(d.History, a)
+	}
+	return &d, rows.Err()
+}
+
+// DueWebhookDeliveries returns up to limit pending deliveries due by now,
+// earliest first, each with its webhook.
+func (r *Repository) DueWebhookDeliveries(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]domain.WebhookDelivery, []domain.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+webhookDeliveryColumns+`, `+webhookColumns+`
+		 FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id
+		 WHERE d.status = ? AND d.next_attempt_at <= ?
+		 ORDER BY d.next_attempt_at, d.id LIMIT ?`,
+		domain.WebhookPending, nullableTime(&now), limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var deliveries []domain.WebhookDelivery
+	var hooks []domain.Webhook
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		var w domain.Webhook
+		var types string
+		if err := scanWebhookDelivery(rows, &d,
+			&w.ID, &w.UserID, &w.URL, &types, &w.Secret, &w.CreatedAt); err != nil {
+			return nil, nil, err
+		}
+		w.EventTypes = strings.Split(types, ",")
+		deliveries = append(deliveries, d)
+		hooks = append(hooks, w)
+	}
+	return deliveries, hooks, rows.Err()
+}
+
+// RecordWebhookAttempt logs an attempt and moves its delivery to status,
+// with the next attempt due at next (nil unless pending).
+func (r *Repository) RecordWebhookAttempt(
+	ctx context.Context,
+	a domain.WebhookAttempt,
+	status string,
+	next *time.Time,
+) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.ExecContext(ctx,
+		`INSERT INTO webhook_attempts (delivery_id, attempted_at, response_code, error, duration_ms)
+		 VALUES (?, ?, ?, ?, ?)`,
+		a.DeliveryID, a.AttemptedAt.UTC(), nullableCode(a.ResponseCode), nullify(a.Error), a.DurationMS); err != nil {
+		return translateError(err)
+	}
+	if _, err = tx.ExecContext(ctx,
+		`UPDATE webhook_deliveries
+		 SET status = ?, attempts = attempts + 1, response_code = ?, next_attempt_at = ?, updated_at = ?
+		 WHERE id = ?`,
+		status, nullableCode(a.ResponseCode), nullableTime(next), a.AttemptedAt.UTC(), a.DeliveryID); err != nil {
+		return translateError(err)
+	}
+	return tx.Commit()
+}
+
+// RequeueWebhookDelivery makes a delivery of a user's webhook pending again,
+// due at at, with a fresh set of retries. It returns ErrNotFound if there is
+// no such delivery.
+func (r *Repository) RequeueWebhookDelivery(ctx context.Context, id, webhookID, userID int64, at time.Time) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE webhook_deliveries SET status = ?, attempts = 0, next_attempt_at = ?, updated_at = ?
+		 WHERE id = ? AND webhook_id = ? AND webhook_id IN (SELECT id FROM webhooks WHERE user_id = ?)`,
+		domain.WebhookPending, nullableTime(&at), at.UTC(), id, webhookID, userID)
+	if err != nil {
+		return translateError(err)
+	}
+	return checkAffected(res)
+}
+
+// nullableTime stores whole UTC seconds, which SQLite compares correctly as
+// text.
+func nullableTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Truncate(time.Second)
+}
+
+func nullableCode(code int) any {
+	if code == 0 {
+		return nil
+	}
+	return code
+}
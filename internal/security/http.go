@@ -12,6 +12,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/middleware"
 )
 
 const (
@@ -205,9 +206,7 @@ func (s *SecureHTTPHandler) SecureJSONResponse(
 ) {
 	// Set security headers
 	w.Header().Set(HeaderContentType, ContentTypeJSON)
-	w.Header().Set(HeaderXContentTypeOptions, "nosniff")
-	w.Header().Set(HeaderXFrameOptions, "DENY")
-	w.Header().Set(HeaderXXSSProtection, "1; mode=block")
+	setSecurityHeaders(w)
 
 	w.WriteHeader(status)
 
@@ -221,8 +220,14 @@ func (s *SecureHTTPHandler) SecureJSONResponse(
 	}
 }
 
-// SecureErrorResponse safely sends error responses
-func (s *SecureHTTPHandler) SecureErrorResponse(w http.ResponseWriter, status int, message string) {
+// SecureErrorResponse safely sends an application/problem+json error with
+// the default code for status.
+func (s *SecureHTTPHandler) SecureErrorResponse(
+	w http.ResponseWriter,
+	r *http.Request,
+	status int,
+	message string,
+) {
 	// Sanitize error message to prevent information leakage
 	sanitizedMsg, err := SanitizeString(message, "error_message")
 	if err != nil {
@@ -230,23 +235,53 @@ func (s *SecureHTTPHandler) SecureErrorResponse(w http.ResponseWriter, status in
 		sanitizedMsg = "an error occurred"
 	}
 
-	s.SecureJSONResponse(w, status, map[string]string{
-		"error": sanitizedMsg,
-	})
+	setSecurityHeaders(w)
+	middleware.WriteProblemStatus(w, r, status, sanitizedMsg)
+}
+
+// SecureProblemResponse maps a typed domain error to a problem response.
+func (s *SecureHTTPHandler) SecureProblemResponse(w http.ResponseWriter, r *http.Request, err error) {
+	setSecurityHeaders(w)
+	middleware.WriteProblem(w, r, err)
+}
+
+func setSecurityHeaders(w http.ResponseWriter) {
+	w.Header().Set(HeaderXContentTypeOptions, "nosniff")
+	w.Header().Set(HeaderXFrameOptions, "DENY")
+	w.Header().Set(HeaderXXSSProtection, "1; mode=block")
 }
 
 // Middleware functions
 
-// RequestSizeLimit middleware limits request body size
+// RequestSizeLimit middleware limits request body size. Routes that accept
+// larger uploads replace the limit with LimitRequestBody.
 func RequestSizeLimit(maxSize int64) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+			r.Body = &limitedBody{ReadCloser: http.MaxBytesReader(w, r.Body, maxSize), orig: r.Body}
 			next.ServeHTTP(w, r)
 		})
 	}
 }
 
+// limitedBody is a body limited by RequestSizeLimit, keeping the original so
+// the limit can be replaced.
+type limitedBody struct {
+	io.ReadCloser
+	orig io.ReadCloser
+}
+
+// LimitRequestBody limits r's body to maxSize instead of the RequestSizeLimit
+// limit, which may be lower. Call it before the body is read; reading past
+// maxSize fails with an *http.MaxBytesError.
+func LimitRequestBody(w http.ResponseWriter, r *http.Request, maxSize int64) {
+	body := r.Body
+	if lb, ok := body.(*limitedBody); ok {
+		body = lb.orig
+	}
+	r.Body = http.MaxBytesReader(w, body, maxSize)
+}
+
 // HeadersMiddleware adds security headers to all responses
 func HeadersMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -285,7 +320,7 @@ func InputValidationMiddleware(next http.Handler) http.Handler {
 		if userAgent != "" {
 			if containsSQLInjection(userAgent) || containsXSS(userAgent) {
 				handler := NewSecureHandler()
-				handler.SecureErrorResponse(w, http.StatusBadRequest, "invalid user agent")
+				handler.SecureErrorResponse(w, r, http.StatusBadRequest, "invalid user agent")
 				return
 			}
 		}
@@ -294,7 +329,7 @@ func InputValidationMiddleware(next http.Handler) http.Handler {
 		if referer := r.Header.Get("Referer"); referer != "" {
 			if containsSQLInjection(referer) || containsXSS(referer) {
 				handler := NewSecureHandler()
-				handler.SecureErrorResponse(w, http.StatusBadRequest, "invalid referer")
+				handler.SecureErrorResponse(w, r, http.StatusBadRequest, "invalid referer")
 				return
 			}
 		}
@@ -303,65 +338,6 @@ func InputValidationMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-// RateLimitMiddleware provides basic rate limiting per IP address
-func RateLimitMiddleware(requestsPerMinute int) func(http.Handler) http.Handler {
-	// Simple in-memory rate limiting - in production, use Redis or similar
-	type client struct {
-		requests  int
-		resetTime int64
-	}
-
-	clients := make(map[string]*client)
-
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Get client IP
-			ip := r.RemoteAddr
-			if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
-				ip = strings.Split(forwarded, ",")[0]
-			}
-			if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
-				ip = realIP
-			}
-
-			// Clean up old entries and check rate limit
-			now := getCurrentTimestamp()
-			if c, exists := clients[ip]; exists {
-				if now > c.resetTime {
-					c.requests = 0
-					c.resetTime = now + 60 // Reset after 60 seconds
-				}
-
-				if c.requests >= requestsPerMinute {
-					handler := NewSecureHandler()
-					handler.SecureErrorResponse(
-						w,
-						http.StatusTooManyRequests,
-						"rate limit exceeded",
-					)
-					return
-				}
-
-				c.requests++
-			} else {
-				clients[ip] = &client{
-					requests:  1,
-					resetTime: now + 60,
-				}
-			}
-
-			next.ServeHTTP(w, r)
-		})
-	}
-}
-
-// getCurrentTimestamp returns current Unix timestamp
-func getCurrentTimestamp() int64 {
-	// In production, use time.Now().Unix()
-	// For demo purposes, return a simple incrementing value
-	return 1700000000 // Placeholder timestamp
-}
-
 // ValidateSessionToken validates session tokens
 func ValidateSessionToken(token string) error {
 	if len(token) == 0 {
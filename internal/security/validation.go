@@ -88,6 +88,12 @@ func (v ValidationError) Unwrap() error {
 	return v.Err
 }
 
+// DomainError converts v to a validation error with field details, so it is
+// answered like any other domain validation failure.
+func (v ValidationError) DomainError() *domain.Error {
+	return domain.Invalid(v.Message, domain.FieldError{Field: v.Field, Message: v.Message}).WithCause(v.Err)
+}
+
 // Validator provides object-oriented validation capabilities
 type Validator struct {
 	fieldName string
@@ -533,6 +539,19 @@ func ValidateAmount(amount domain.Money, fieldName string) error {
 	return nil
 }
 
+// ValidateDayOfMonth validates an optional pay/due day (1-31; 0 clears it)
+func ValidateDayOfMonth(day *int) error {
+	if day == nil || (*day >= 0 && *day <= 31) {
+		return nil
+	}
+	return ValidationError{
+		Field:   "day_of_month",
+		Value:   fmt.Sprintf("%d", *day),
+		Message: "day_of_month must be between 1 and 31",
+		Err:     ErrInvalidRange,
+	}
+}
+
 // ValidateUserID validates user ID values
 func ValidateUserID(userID int64) error {
 	if userID < MinUserID || userID > MaxUserID {
@@ -590,6 +609,12 @@ func ValidateCreateIncomeSourceRequest(
 	}
 	validated.AmountCents = req.AmountCents
 
+	// Validate pay/due day (optional)
+	if err := ValidateDayOfMonth(req.DayOfMonth); err != nil {
+		return nil, err
+	}
+	validated.DayOfMonth = req.DayOfMonth
+
 	return &validated, nil
 }
 
@@ -620,6 +645,12 @@ func ValidateCreateBudgetSourceRequest(
 	}
 	validated.AmountCents = req.AmountCents
 
+	// Validate pay/due day (optional)
+	if err := ValidateDayOfMonth(req.DayOfMonth); err != nil {
+		return nil, err
+	}
+	validated.DayOfMonth = req.DayOfMonth
+
 	return &validated, nil
 }
 
@@ -642,6 +673,12 @@ func ValidateUpdateSourceRequest(
 	}
 	validated.AmountCents = req.AmountCents
 
+	// Validate pay/due day (optional)
+	if err := ValidateDayOfMonth(req.DayOfMonth); err != nil {
+		return nil, err
+	}
+	validated.DayOfMonth = req.DayOfMonth
+
 	return &validated, nil
 }
 
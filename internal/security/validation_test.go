@@ -1,9 +1,13 @@
 package security
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/middleware"
 )
 
 func TestValidateUsername(t *testing.T) {
@@ -316,3 +320,25 @@ func TestValidateExpense(t *testing.T) {
 		})
 	}
 }
+
+func TestValidationErrorProblem(t *testing.T) {
+	_, err := ValidateCreateIncomeSourceRequest(domain.CreateIncomeSourceRequest{
+		Name: "Salary", Year: 2024, Month: 13, AmountCents: 100,
+	})
+	if err == nil {
+		t.Fatal("Expected validation error for month 13")
+	}
+
+	w := httptest.NewRecorder()
+	NewSecureHandler().SecureProblemResponse(w, httptest.NewRequest(http.MethodPost, "/", nil), err)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+	var p middleware.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+		t.Fatalf("Expected problem body, got %q: %v", w.Body.String(), err)
+	}
+	if p.Code != "validation_failed" || len(p.Errors) != 1 || p.Errors[0].Field == "" {
+		t.Errorf("Expected validation_failed with one field error, got %+v", p)
+	}
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/events"
+	"github.com/mdco1990/webapp/internal/repository"
+)
+
+// ChangeAnnouncer publishes committed repository changes on an event bus.
+type ChangeAnnouncer struct {
+	repo *repository.Repository
+	bus  *events.EventBus
+}
+
+// NewChangeAnnouncer creates a ChangeAnnouncer.
+func NewChangeAnnouncer(repo *repository.Repository, bus *events.EventBus) *ChangeAnnouncer {
+	return &ChangeAnnouncer{repo: repo, bus: bus}
+}
+
+// Announce publishes a change made by actorID (0 if unknown) as a
+// ChangeEvent and, when a new expense leaves its month over budget, a
+// BudgetExceededEvent. Events are published synchronously, so subscribers
+// see changes in commit order.
+func (a *ChangeAnnouncer) Announce(ctx context.Context, actorID int64, c domain.Change) {
+	// The write has committed; announce it even if the client has gone.
+	ctx = context.WithoutCancel(ctx)
+	a.publish(ctx, events.NewChangeEvent("repository", c, actorID))
+
+	if c.Entity != domain.BatchExpense || c.Action != domain.ChangeCreated || actorID <= 0 {
+		return
+	}
+	budget, err := a.repo.GetBudget(ctx, c.YearMonth)
+	if err != nil || budget <= 0 {
+		return
+	}
+	spent, err := a.repo.GetExpensesTotal(ctx, c.YearMonth)
+	if err != nil || spent <= budget {
+		return
+	}
+	a.publish(ctx, events.NewBudgetExceededEvent(
+		"repository", actorID, c.Month, c.Year, int64(budget), int64(spent)))
+}
+
+func (a *ChangeAnnouncer) publish(ctx context.Context, event events.Event) {
+	if err := a.bus.Publish(ctx, event); err != nil {
+		slog.Error("failed to publish event", "err", err, "type", event.Type())
+	}
+}
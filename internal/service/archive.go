@@ -0,0 +1,238 @@
+// Package service implements the versioned account archive used by data export/import.
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// ArchiveVersion is the current archive format version. Readers accept any
+// version up to and including this one.
+const ArchiveVersion = 1
+
+// Archive file names.
+const (
+	archiveManifest      = "manifest.json"
+	archiveIncomeSources = "income_sources.json"
+	archiveBudgetSources = "budget_sources.json"
+	archiveExpenses      = "expenses.json"
+	archiveManualBudgets = "manual_budgets.json"
+	archiveSettings      = "settings.json"
+)
+
+// Archive errors.
+var (
+	ErrArchiveInvalid  = errors.New("invalid archive")
+	ErrArchiveChecksum = errors.New("archive checksum mismatch")
+	ErrArchiveVersion  = errors.New("unsupported archive version")
+)
+
+// ArchiveManifest describes an export archive.
+type ArchiveManifest struct {
+	Version   int           `json:"version"`
+	CreatedAt time.Time     `json:"created_at"`
+	UserID    int64         `json:"user_id"`
+	Files     []ArchiveFile `json:"files"`
+}
+
+// ArchiveFile is one entity file listed in the manifest.
+type ArchiveFile struct {
+	Name   string `json:"name"`
+	Count  int    `json:"count"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size_bytes"`
+}
+
+// archivedManualBudget is the on-disk form of a manual budget; the domain type
+// hides its year/month from JSON.
+type archivedManualBudget struct {
+	Year            int                       `json:"year"`
+	Month           int                       `json:"month"`
+	BankAmountCents domain.Money              `json:"bank_amount_cents"`
+	Items           []domain.ManualBudgetItem `json:"items"`
+}
+
+// BuildArchive serialises a user's data into a zip archive with a manifest.
+func BuildArchive(userID int64, data *domain.UserDataSet, now time.Time) ([]byte, *ArchiveManifest, error) {
+	manual := make([]archivedManualBudget, 0, len(data.ManualBudgets))
+	for _, mb := range data.ManualBudgets {
+		manual = append(manual, archivedManualBudget{
+			Year:            mb.Year,
+			Month:           mb.Month,
+			BankAmountCents: mb.BankAmountCents,
+			Items:           mb.Items,
+		})
+	}
+	settings := data.Settings
+	if settings == nil {
+		settings = domain.UserSettings{}
+	}
+
+	entries := []struct {
+		name  string
+		count int
+		value any
+	}{
+		{archiveIncomeSources, len(data.IncomeSources), data.IncomeSources},
+		{archiveBudgetSources, len(data.BudgetSources), data.BudgetSources},
+		{archiveExpenses, len(data.Expenses), data.Expenses},
+		{archiveManualBudgets, len(manual), manual},
+		{archiveSettings, len(settings), settings},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	manifest := &ArchiveManifest{Version: ArchiveVersion, CreatedAt: now.UTC(), UserID: userID}
+
+	for _, e := range entries {
+		body, err := json.MarshalIndent(e.value, "", "  ")
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := writeZipFile(zw, e.name, body, now); err != nil {
+			return nil, nil, err
+		}
+		sum := sha256.Sum256(body)
+		manifest.Files = append(manifest.Files, ArchiveFile{
+			Name:   e.name,
+			Count:  e.count,
+			SHA256: hex.EncodeToString(sum[:]),
+			Size:   int64(len(body)),
+		})
+	}
+
+	body, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := writeZipFile(zw, archiveManifest, body, now); err != nil {
+		return nil, nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), manifest, nil
+}
+
+// ReadArchive validates an archive against its manifest and decodes it. The
+// archive may hold no files other than the manifest and those it lists.
+//
+//nolint:cyclop
+func ReadArchive(b []byte) (*domain.UserDataSet, *ArchiveManifest, error) {
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrArchiveInvalid, err)
+	}
+
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		if strings.Contains(f.Name, "/") || strings.Contains(f.Name, "..") {
+			return nil, nil, fmt.Errorf("%w: unexpected entry %q", ErrArchiveInvalid, f.Name)
+		}
+		if _, dup := files[f.Name]; dup {
+			return nil, nil, fmt.Errorf("%w: duplicate entry %q", ErrArchiveInvalid, f.Name)
+		}
+		body, err := readZipFile(f)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrArchiveInvalid, err)
+		}
+		files[f.Name] = body
+	}
+
+	rawManifest, ok := files[archiveManifest]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: missing %s", ErrArchiveInvalid, archiveManifest)
+	}
+	var manifest ArchiveManifest
+	if err := json.Unmarshal(rawManifest, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("%w: manifest: %v", ErrArchiveInvalid, err)
+	}
+	if manifest.Version < 1 || manifest.Version > ArchiveVersion {
+		return nil, nil, fmt.Errorf("%w: %d", ErrArchiveVersion, manifest.Version)
+	}
+
+	// Every entry but the manifest must be listed in it, so none is read
+	// without its checksum being checked.
+	listed := map[string]bool{archiveManifest: true}
+	for _, f := range manifest.Files {
+		body, ok := files[f.Name]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: missing %s", ErrArchiveInvalid, f.Name)
+		}
+		sum := sha256.Sum256(body)
+		if hex.EncodeToString(sum[:]) != f.SHA256 {
+			return nil, nil, fmt.Errorf("%w: %s", ErrArchiveChecksum, f.Name)
+		}
+		listed[f.Name] = true
+	}
+	for name := range files {
+		if !listed[name] {
+			return nil, nil, fmt.Errorf("%w: %s is not listed in the manifest", ErrArchiveInvalid, name)
+		}
+	}
+
+	data := &domain.UserDataSet{Settings: domain.UserSettings{}}
+	var manual []archivedManualBudget
+	targets := map[string]any{
+		archiveIncomeSources: &data.IncomeSources,
+		archiveBudgetSources: &data.BudgetSources,
+		archiveExpenses:      &data.Expenses,
+		archiveManualBudgets: &manual,
+		archiveSettings:      &data.Settings,
+	}
+	for name, target := range targets {
+		body, ok := files[name]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(body, target); err != nil {
+			return nil, nil, fmt.Errorf("%w: %s: %v", ErrArchiveInvalid, name, err)
+		}
+	}
+	for _, mb := range manual {
+		data.ManualBudgets = append(data.ManualBudgets, domain.ManualBudget{
+			YearMonth:       domain.YearMonth{Year: mb.Year, Month: mb.Month},
+			BankAmountCents: mb.BankAmountCents,
+			Items:           mb.Items,
+		})
+	}
+	return data, &manifest, nil
+}
+
+func writeZipFile(zw *zip.Writer, name string, body []byte, now time.Time) error {
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: now})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
+
+// maxArchiveEntrySize bounds decompressed entries to guard against zip bombs.
+const maxArchiveEntrySize = 64 << 20
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+	body, err := io.ReadAll(io.LimitReader(rc, maxArchiveEntrySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxArchiveEntrySize {
+		return nil, fmt.Errorf("entry %s too large", f.Name)
+	}
+	return body, nil
+}
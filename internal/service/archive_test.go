@@ -0,0 +1,172 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+func sampleUserData() *domain.UserDataSet {
+	ym := domain.YearMonth{Year: 2024, Month: 2}
+	return &domain.UserDataSet{
+		IncomeSources: []domain.IncomeSource{{Name: "Salary", YearMonth: ym, AmountCents: 250000}},
+		BudgetSources: []domain.BudgetSource{{Name: "Rent", YearMonth: ym, AmountCents: 80000}},
+		Expenses: []domain.Expense{
+			{YearMonth: ym, Category: "Food", Description: "Lunch", AmountCents: 1200},
+			{YearMonth: ym, Category: "Food", Description: "Lunch", AmountCents: 1200},
+		},
+		ManualBudgets: []domain.ManualBudget{{
+			YearMonth:       ym,
+			BankAmountCents: 50000,
+			Items:           []domain.ManualBudgetItem{{Name: "Gift", AmountCents: -3000}},
+		}},
+		Settings: domain.UserSettings{"locale": "fr"},
+	}
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	archive, manifest, err := BuildArchive(7, sampleUserData(), time.Now())
+	if err != nil {
+		t.Fatalf("BuildArchive failed: %v", err)
+	}
+	if manifest.Version != ArchiveVersion || len(manifest.Files) != 5 {
+		t.Fatalf("Unexpected manifest: %+v", manifest)
+	}
+
+	data, readManifest, err := ReadArchive(archive)
+	if err != nil {
+		t.Fatalf("ReadArchive failed: %v", err)
+	}
+	if readManifest.UserID != 7 {
+		t.Errorf("Expected user 7, got %d", readManifest.UserID)
+	}
+	if len(data.Expenses) != 2 || len(data.IncomeSources) != 1 || len(data.BudgetSources) != 1 {
+		t.Errorf("Unexpected entity counts: %+v", data)
+	}
+	if len(data.ManualBudgets) != 1 || data.ManualBudgets[0].Month != 2 || data.ManualBudgets[0].Items[0].AmountCents != -3000 {
+		t.Errorf("Unexpected manual budgets: %+v", data.ManualBudgets)
+	}
+	if data.Settings["locale"] != "fr" {
+		t.Errorf("Expected locale fr, got %q", data.Settings["locale"])
+	}
+}
+
+// rewriteArchive copies archive with each file's body replaced by edit's
+// result; files for which edit returns nil are left out.
+func rewriteArchive(t *testing.T, archive []byte, edit func(name string, body []byte) []byte) []byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("zip.NewReader failed: %v", err)
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		body, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		if body = edit(f.Name, body); body == nil {
+			continue
+		}
+		w, _ := zw.Create(f.Name)
+		_, _ = w.Write(body)
+	}
+	_ = zw.Close()
+	return buf.Bytes()
+}
+
+func TestReadArchiveRejectsTampering(t *testing.T) {
+	archive, _, err := BuildArchive(1, sampleUserData(), time.Now())
+	if err != nil {
+		t.Fatalf("BuildArchive failed: %v", err)
+	}
+
+	// A modified expenses file with the original manifest.
+	tampered := rewriteArchive(t, archive, func(name string, body []byte) []byte {
+		if name == archiveExpenses {
+			return []byte("[]")
+		}
+		return body
+	})
+	if _, _, err := ReadArchive(tampered); !errors.Is(err, ErrArchiveChecksum) {
+		t.Errorf("Expected ErrArchiveChecksum, got %v", err)
+	}
+
+	// A manifest that no longer lists the expenses file.
+	unlisted := rewriteArchive(t, archive, func(name string, body []byte) []byte {
+		if name != archiveManifest {
+			return body
+		}
+		var manifest ArchiveManifest
+		_ = json.Unmarshal(body, &manifest)
+		manifest.Files = slices.DeleteFunc(manifest.Files, func(f ArchiveFile) bool { return f.Name == archiveExpenses })
+		body, _ = json.Marshal(manifest)
+		return body
+	})
+	if _, _, err := ReadArchive(unlisted); !errors.Is(err, ErrArchiveInvalid) {
+		t.Errorf("Expected ErrArchiveInvalid for an unlisted file, got %v", err)
+	}
+
+	if _, _, err := ReadArchive([]byte("not a zip")); !errors.Is(err, ErrArchiveInvalid) {
+		t.Errorf("Expected ErrArchiveInvalid, got %v", err)
+	}
+}
+
+func TestPlanImport(t *testing.T) {
+	incoming := sampleUserData()
+
+	t.Run("restore into empty account", func(t *testing.T) {
+		plan, report, err := PlanImport(&domain.UserDataSet{}, incoming, ImportModeRestore)
+		if err != nil {
+			t.Fatalf("PlanImport failed: %v", err)
+		}
+		if len(plan.Expenses) != 2 || report.Expenses.Created != 2 {
+			t.Errorf("Expected both expenses created, got %d (%+v)", len(plan.Expenses), report.Expenses)
+		}
+		if report.ManualBudgets.Created != 1 || report.Settings.Created != 1 {
+			t.Errorf("Unexpected report: %+v", report)
+		}
+	})
+
+	t.Run("restore into non-empty account", func(t *testing.T) {
+		existing := &domain.UserDataSet{IncomeSources: incoming.IncomeSources}
+		if _, _, err := PlanImport(existing, incoming, ImportModeRestore); !errors.Is(err, ErrAccountNotEmpty) {
+			t.Errorf("Expected ErrAccountNotEmpty, got %v", err)
+		}
+	})
+
+	t.Run("merge skips existing", func(t *testing.T) {
+		existing := &domain.UserDataSet{
+			IncomeSources: []domain.IncomeSource{{Name: "salary", YearMonth: incoming.IncomeSources[0].YearMonth}},
+			Expenses:      incoming.Expenses[:1],
+			ManualBudgets: incoming.ManualBudgets,
+			Settings:      domain.UserSettings{"locale": "en"},
+		}
+		plan, report, err := PlanImport(existing, incoming, ImportModeMerge)
+		if err != nil {
+			t.Fatalf("PlanImport failed: %v", err)
+		}
+		if len(plan.IncomeSources) != 0 || report.IncomeSources.Skipped != 1 {
+			t.Errorf("Expected income source skipped, got %+v", report.IncomeSources)
+		}
+		if len(plan.BudgetSources) != 1 {
+			t.Errorf("Expected 1 budget source, got %d", len(plan.BudgetSources))
+		}
+		if len(plan.Expenses) != 1 || report.Expenses.Skipped != 1 {
+			t.Errorf("Expected 1 of 2 duplicate expenses kept, got %+v", report.Expenses)
+		}
+		if len(plan.ManualBudgets) != 0 || len(plan.Settings) != 0 {
+			t.Errorf("Expected manual budgets and settings kept, got %+v", plan)
+		}
+	})
+}
@@ -4,6 +4,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -48,11 +49,20 @@ type BackgroundTask struct {
 	Progress    int                    `json:"progress"` // 0-100
 }
 
+// Finished tasks, and the exports and statements they produced, are kept for
+// taskRetention after they finish; Run evicts them every
+// taskCleanupInterval.
+const (
+	taskRetention       = time.Hour
+	taskCleanupInterval = 10 * time.Minute
+)
+
 // BackgroundService handles background task processing.
 type BackgroundService struct {
 	repo        *repository.Repository
 	tasks       map[string]*BackgroundTask
 	taskResults map[string]interface{}
+	publisher   EventPublisher
 	mu          sync.RWMutex
 }
 
@@ -206,7 +216,8 @@ func (s *BackgroundService) ListTasks(taskType *TaskType, status *TaskStatus) []
 	return filteredTasks
 }
 
-// CleanupCompletedTasks removes completed tasks older than the specified duration.
+// CleanupCompletedTasks removes tasks, with their results, that finished
+// longer ago than the specified duration.
 func (s *BackgroundService) CleanupCompletedTasks(olderThan time.Duration) int {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -215,8 +226,12 @@ func (s *BackgroundService) CleanupCompletedTasks(olderThan time.Duration) int {
 	removed := 0
 
 	for taskID, task := range s.tasks {
+		finishedAt := task.CreatedAt
+		if task.CompletedAt != nil {
+			finishedAt = *task.CompletedAt
+		}
 		if (task.Status == TaskStatusCompleted || task.Status == TaskStatusFailed ||
-			task.Status == TaskStatusCancelled) && task.CreatedAt.Before(cutoff) {
+			task.Status == TaskStatusCancelled) && finishedAt.Before(cutoff) {
 			delete(s.tasks, taskID)
 			delete(s.taskResults, taskID)
 			removed++
@@ -226,6 +241,23 @@ func (s *BackgroundService) CleanupCompletedTasks(olderThan time.Duration) int {
 	return removed
 }
 
+// Run evicts finished tasks every taskCleanupInterval until ctx is done, so
+// their results do not stay in memory for good.
+func (s *BackgroundService) Run(ctx context.Context) {
+	ticker := time.NewTicker(taskCleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n := s.CleanupCompletedTasks(taskRetention); n > 0 {
+				slog.Info("evicted finished background tasks", "tasks", n)
+			}
+		}
+	}
+}
+
 // updateTaskStatus updates the status of a task.
 func (s *BackgroundService) updateTaskStatus(taskID string, status TaskStatus, result interface{}, errorMsg string) {
 	s.mu.Lock()
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackgroundService_CleanupCompletedTasks(t *testing.T) {
+	s := NewBackgroundService(nil)
+	finished := func(ago time.Duration) *time.Time {
+		at := time.Now().Add(-ago)
+		return &at
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	s.tasks = map[string]*BackgroundTask{
+		"expired": {ID: "expired", Status: TaskStatusCompleted, CreatedAt: old, CompletedAt: finished(61 * time.Minute)},
+		"recent":  {ID: "recent", Status: TaskStatusCompleted, CreatedAt: old, CompletedAt: finished(time.Minute)},
+		"failed":  {ID: "failed", Status: TaskStatusFailed, CreatedAt: old, CompletedAt: finished(2 * time.Hour)},
+		"running": {ID: "running", Status: TaskStatusProcessing, CreatedAt: old},
+	}
+	s.taskResults = map[string]interface{}{"expired": []byte("zip"), "recent": []byte("pdf")}
+
+	if n := s.CleanupCompletedTasks(taskRetention); n != 2 {
+		t.Errorf("Expected 2 tasks evicted, got %d", n)
+	}
+	if len(s.tasks) != 2 || s.tasks["recent"] == nil || s.tasks["running"] == nil {
+		t.Errorf("Expected the recent and running tasks kept, got %v", s.tasks)
+	}
+	if _, ok := s.taskResults["expired"]; ok || len(s.taskResults) != 1 {
+		t.Errorf("Expected the expired artifact dropped, got %v", s.taskResults)
+	}
+}
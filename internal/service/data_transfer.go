@@ -0,0 +1,372 @@
+// Package service implements full account export and import as background tasks.
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/events"
+)
+
+// ImportMode selects how an archive is applied to an account.
+type ImportMode string
+
+// Import modes
+const (
+	// ImportModeRestore requires an account without income sources, budget
+	// sources or manual budgets and loads the archive as-is.
+	ImportModeRestore ImportMode = "restore"
+	// ImportModeMerge keeps existing data and only adds what is missing.
+	ImportModeMerge ImportMode = "merge"
+)
+
+// ErrAccountNotEmpty is returned when restoring into an account that already has data.
+var ErrAccountNotEmpty = domain.Conflict("account_not_empty", "account is not empty; use merge mode")
+
+// ErrTaskNotFound is returned for unknown tasks and tasks of other users.
+var ErrTaskNotFound = domain.NotFound("task_not_found", "task not found")
+
+// ParseImportMode validates an import mode string (empty means merge).
+func ParseImportMode(s string) (ImportMode, error) {
+	switch ImportMode(s) {
+	case "", ImportModeMerge:
+		return ImportModeMerge, nil
+	case ImportModeRestore:
+		return ImportModeRestore, nil
+	default:
+		return "", fmt.Errorf("%w: unknown import mode %q", ErrValidation, s)
+	}
+}
+
+// EntityImportStats counts what an import does to one entity type.
+type EntityImportStats struct {
+	InArchive int `json:"in_archive"`
+	Created   int `json:"created"`
+	Skipped   int `json:"skipped"`
+}
+
+// ImportReport is the outcome (or, for dry runs, the forecast) of an import.
+type ImportReport struct {
+	Mode           ImportMode        `json:"mode"`
+	DryRun         bool              `json:"dry_run"`
+	ArchiveVersion int               `json:"archive_version"`
+	IncomeSources  EntityImportStats `json:"income_sources"`
+	BudgetSources  EntityImportStats `json:"budget_sources"`
+	Expenses       EntityImportStats `json:"expenses"`
+	ManualBudgets  EntityImportStats `json:"manual_budgets"`
+	Settings       EntityImportStats `json:"settings"`
+}
+
+// EventPublisher is the subset of the event bus used by background tasks.
+type EventPublisher interface {
+	PublishAsync(ctx context.Context, event events.Event)
+}
+
+// PlanImport decides which archived records to insert into an account that
+// currently holds existing. Merge skips records that already exist (same name
+// and month for sources, same month/category/description/amount for expenses,
+// same month for manual budgets, same key for settings).
+//
+//nolint:cyclop,funlen
+func PlanImport(existing, incoming *domain.UserDataSet, mode ImportMode) (*domain.UserDataSet, *ImportReport, error) {
+	if mode == ImportModeRestore &&
+		(len(existing.IncomeSources) > 0 || len(existing.BudgetSources) > 0 || len(existing.ManualBudgets) > 0) {
+		return nil, nil, ErrAccountNotEmpty
+	}
+
+	report := &ImportReport{Mode: mode}
+	plan := &domain.UserDataSet{
+		IncomeSources: []domain.IncomeSource{},
+		BudgetSources: []domain.BudgetSource{},
+		Expenses:      []domain.Expense{},
+		ManualBudgets: []domain.ManualBudget{},
+		Settings:      domain.UserSettings{},
+	}
+
+	sourceKey := func(name string, ym domain.YearMonth) string {
+		return fmt.Sprintf("%04d-%02d|%s", ym.Year, ym.Month, strings.ToLower(strings.TrimSpace(name)))
+	}
+
+	seenIncome := map[string]bool{}
+	for _, s := range existing.IncomeSources {
+		seenIncome[sourceKey(s.Name, s.YearMonth)] = true
+	}
+	report.IncomeSources.InArchive = len(incoming.IncomeSources)
+	for _, s := range incoming.IncomeSources {
+		if err := validateYM(s.YearMonth); err != nil || s.Name == "" {
+			report.IncomeSources.Skipped++
+			continue
+		}
+		k := sourceKey(s.Name, s.YearMonth)
+		if seenIncome[k] {
+			report.IncomeSources.Skipped++
+			continue
+		}
+		seenIncome[k] = true
+		s.DayOfMonth = validDayOfMonth(s.DayOfMonth)
+		plan.IncomeSources = append(plan.IncomeSources, s)
+		report.IncomeSources.Created++
+	}
+
+	seenBudget := map[string]bool{}
+	for _, s := range existing.BudgetSources {
+		seenBudget[sourceKey(s.Name, s.YearMonth)] = true
+	}
+	report.BudgetSources.InArchive = len(incoming.BudgetSources)
+	for _, s := range incoming.BudgetSources {
+		if err := validateYM(s.YearMonth); err != nil || s.Name == "" {
+			report.BudgetSources.Skipped++
+			continue
+		}
+		k := sourceKey(s.Name, s.YearMonth)
+		if seenBudget[k] {
+			report.BudgetSources.Skipped++
+			continue
+		}
+		seenBudget[k] = true
+		s.DayOfMonth = validDayOfMonth(s.DayOfMonth)
+		plan.BudgetSources = append(plan.BudgetSources, s)
+		report.BudgetSources.Created++
+	}
+
+	// Expenses are matched as a multiset so genuine duplicates in the archive survive.
+	expenseKey := func(e domain.Expense) string {
+		return fmt.Sprintf("%04d-%02d|%s|%s|%d", e.Year, e.Month, e.Category, e.Description, e.AmountCents)
+	}
+	existingExpenses := map[string]int{}
+	for _, e := range existing.Expenses {
+		existingExpenses[expenseKey(e)]++
+	}
+	report.Expenses.InArchive = len(incoming.Expenses)
+	for _, e := range incoming.Expenses {
+		if err := validateYM(e.YearMonth); err != nil || e.Description == "" || e.AmountCents <= 0 {
+			report.Expenses.Skipped++
+			continue
+		}
+		k := expenseKey(e)
+		if existingExpenses[k] > 0 {
+			existingExpenses[k]--
+			report.Expenses.Skipped++
+			continue
+		}
+		plan.Expenses = append(plan.Expenses, e)
+		report.Expenses.Created++
+	}
+
+	seenManual := map[domain.YearMonth]bool{}
+	for _, mb := range existing.ManualBudgets {
+		seenManual[mb.YearMonth] = true
+	}
+	report.ManualBudgets.InArchive = len(incoming.ManualBudgets)
+	for _, mb := range incoming.ManualBudgets {
+		if err := validateYM(mb.YearMonth); err != nil || seenManual[mb.YearMonth] {
+			report.ManualBudgets.Skipped++
+			continue
+		}
+		seenManual[mb.YearMonth] = true
+		plan.ManualBudgets = append(plan.ManualBudgets, mb)
+		report.ManualBudgets.Created++
+	}
+
+	report.Settings.InArchive = len(incoming.Settings)
+	for k, v := range incoming.Settings {
+		if _, ok := existing.Settings[k]; ok && mode == ImportModeMerge {
+			report.Settings.Skipped++
+			continue
+		}
+		plan.Settings[k] = v
+		report.Settings.Created++
+	}
+
+	return plan, report, nil
+}
+
+// WithEventPublisher attaches an event publisher used to announce task outcomes.
+func (s *BackgroundService) WithEventPublisher(p EventPublisher) *BackgroundService {
+	s.publisher = p
+	return s
+}
+
+// ProcessDataExportAsync builds a full account archive in the background and
+// returns the task ID immediately. Download it with GetTaskArtifact.
+func (s *BackgroundService) ProcessDataExportAsync(ctx context.Context, userID int64) (string, error) {
+	if userID <= 0 {
+		return "", ErrValidation
+	}
+	task := s.newTask(TaskTypeDataExport, map[string]interface{}{"user_id": userID, "format": "zip"})
+	// The task outlives the request that started it.
+	go s.processDataExportTask(context.WithoutCancel(ctx), task, userID)
+	return task.ID, nil
+}
+
+func (s *BackgroundService) processDataExportTask(ctx context.Context, task *BackgroundTask, userID int64) {
+	s.markTaskStarted(task.ID)
+
+	data, err := s.repo.ExportUserData(ctx, userID)
+	if err != nil {
+		s.failDataExport(ctx, task.ID, userID, err)
+		return
+	}
+	s.updateTaskProgress(task.ID, 50)
+
+	archive, manifest, err := BuildArchive(userID, data, time.Now())
+	if err != nil {
+		s.failDataExport(ctx, task.ID, userID, err)
+		return
+	}
+
+	s.mu.Lock()
+	s.taskResults[task.ID] = archive
+	s.mu.Unlock()
+
+	s.updateTaskStatus(task.ID, TaskStatusCompleted, map[string]interface{}{
+		"manifest":   manifest,
+		"size_bytes": len(archive),
+	}, "")
+	s.publish(ctx, events.NewDataExportEvent(
+		"background_service", userID, "account", "zip", int64(len(archive)), string(TaskStatusCompleted)))
+}
+
+func (s *BackgroundService) failDataExport(ctx context.Context, taskID string, userID int64, err error) {
+	s.updateTaskStatus(taskID, TaskStatusFailed, nil, err.Error())
+	s.publish(ctx, events.NewDataExportEvent(
+		"background_service", userID, "account", "zip", 0, string(TaskStatusFailed)))
+}
+
+// ProcessDataImportAsync validates an archive synchronously (so malformed
+// uploads fail fast) and applies it in the background. With dryRun the task
+// result is the report of what would happen and nothing is written.
+func (s *BackgroundService) ProcessDataImportAsync(
+	ctx context.Context,
+	userID int64,
+	archive []byte,
+	mode ImportMode,
+	dryRun bool,
+) (string, error) {
+	if userID <= 0 {
+		return "", ErrValidation
+	}
+	incoming, manifest, err := ReadArchive(archive)
+	if err != nil {
+		return "", err
+	}
+	task := s.newTask(TaskTypeDataImport, map[string]interface{}{
+		"user_id": userID,
+		"mode":    mode,
+		"dry_run": dryRun,
+	})
+	go s.processDataImportTask(context.WithoutCancel(ctx), task, userID, incoming, manifest, mode, dryRun)
+	return task.ID, nil
+}
+
+func (s *BackgroundService) processDataImportTask(
+	ctx context.Context,
+	task *BackgroundTask,
+	userID int64,
+	incoming *domain.UserDataSet,
+	manifest *ArchiveManifest,
+	mode ImportMode,
+	dryRun bool,
+) {
+	s.markTaskStarted(task.ID)
+
+	existing, err := s.repo.ExportUserData(ctx, userID)
+	if err != nil {
+		s.updateTaskStatus(task.ID, TaskStatusFailed, nil, err.Error())
+		return
+	}
+	plan, report, err := PlanImport(existing, incoming, mode)
+	if err != nil {
+		s.updateTaskStatus(task.ID, TaskStatusFailed, nil, err.Error())
+		return
+	}
+	report.DryRun = dryRun
+	report.ArchiveVersion = manifest.Version
+	s.updateTaskProgress(task.ID, 50)
+
+	if !dryRun {
+		if err := s.repo.ImportUserData(ctx, userID, plan); err != nil {
+			s.updateTaskStatus(task.ID, TaskStatusFailed, nil, err.Error())
+			return
+		}
+	}
+	s.updateTaskStatus(task.ID, TaskStatusCompleted, report, "")
+}
+
+// GetUserTask returns a task only if it was started by the given user.
+func (s *BackgroundService) GetUserTask(taskID string, userID int64) (*BackgroundTask, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	task, exists := s.tasks[taskID]
+	if !exists {
+		return nil, ErrTaskNotFound
+	}
+	if owner, _ := task.Data["user_id"].(int64); owner != userID {
+		return nil, ErrTaskNotFound
+	}
+	// Return a copy so callers can encode it without holding the lock.
+	cp := *task
+	return &cp, nil
+}
+
+// GetTaskArtifact returns the binary output of a completed task (e.g. an archive).
+func (s *BackgroundService) GetTaskArtifact(taskID string, userID int64) ([]byte, error) {
+	task, err := s.GetUserTask(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != TaskStatusCompleted {
+		return nil, fmt.Errorf("task not completed: %s", task.Status)
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	artifact, ok := s.taskResults[taskID].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("task has no artifact: %s", taskID)
+	}
+	return artifact, nil
+}
+
+// newTask registers a pending task.
+func (s *BackgroundService) newTask(taskType TaskType, data map[string]interface{}) *BackgroundTask {
+	task := &BackgroundTask{
+		ID:        generateTaskID(),
+		Type:      taskType,
+		Data:      data,
+		Status:    TaskStatusPending,
+		CreatedAt: time.Now(),
+	}
+	s.mu.Lock()
+	s.tasks[task.ID] = task
+	s.mu.Unlock()
+	return task
+}
+
+// markTaskStarted moves a task to processing.
+func (s *BackgroundService) markTaskStarted(taskID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if task, ok := s.tasks[taskID]; ok {
+		task.Status = TaskStatusProcessing
+		now := time.Now()
+		task.StartedAt = &now
+	}
+}
+
+func (s *BackgroundService) publish(ctx context.Context, event events.Event) {
+	if s.publisher != nil {
+		s.publisher.PublishAsync(ctx, event)
+	}
+}
+
+// validDayOfMonth drops an out-of-range pay/due day rather than the whole row.
+func validDayOfMonth(day *int) *int {
+	if day == nil || *day < 1 || *day > 31 {
+		return nil
+	}
+	return day
+}
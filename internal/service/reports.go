@@ -0,0 +1,117 @@
+// Package service implements PDF statement generation as a background task.
+package service
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/export"
+)
+
+// MonthlyReportRequest describes a monthly PDF statement to generate.
+type MonthlyReportRequest struct {
+	UserID   int64
+	Period   domain.YearMonth
+	Locale   string
+	Currency string
+	// FileURL builds the download URL for a report ID; the transport layer owns routing.
+	FileURL func(reportID string) string
+}
+
+// ProcessMonthlyReportAsync renders a monthly PDF statement in the background.
+// The task ID doubles as the report ID; once completed the task result is a
+// *domain.ExpenseReport whose FileURL points at the PDF (see GetTaskArtifact).
+func (s *BackgroundService) ProcessMonthlyReportAsync(ctx context.Context, req MonthlyReportRequest) (string, error) {
+	if req.UserID <= 0 {
+		return "", ErrValidation
+	}
+	if err := validateYM(req.Period); err != nil {
+		return "", err
+	}
+	task := s.newTask(TaskTypeExpenseReport, map[string]interface{}{
+		"user_id":    req.UserID,
+		"year_month": req.Period,
+		"format":     "pdf",
+		"locale":     req.Locale,
+	})
+	go s.processMonthlyReportTask(context.WithoutCancel(ctx), task, req)
+	return task.ID, nil
+}
+
+func (s *BackgroundService) processMonthlyReportTask(ctx context.Context, task *BackgroundTask, req MonthlyReportRequest) {
+	s.markTaskStarted(task.ID)
+
+	data, err := s.repo.GetMonthlyData(ctx, req.UserID, req.Period)
+	if err != nil {
+		s.updateTaskStatus(task.ID, TaskStatusFailed, nil, err.Error())
+		return
+	}
+	manual, err := s.repo.GetManualBudget(ctx, req.UserID, req.Period)
+	if err != nil {
+		s.updateTaskStatus(task.ID, TaskStatusFailed, nil, err.Error())
+		return
+	}
+	s.updateTaskProgress(task.ID, 40)
+
+	generatedAt := time.Now()
+	var pdf bytes.Buffer
+	if err := export.WriteMonthlyStatement(&pdf, data, manual,
+		export.NewFormat(req.Locale, req.Currency), generatedAt); err != nil {
+		s.updateTaskStatus(task.ID, TaskStatusFailed, nil, err.Error())
+		return
+	}
+
+	report := NewMonthlyExpenseReport(task.ID, req.UserID, data, generatedAt)
+	report.Status = string(TaskStatusCompleted)
+	if req.FileURL != nil {
+		report.FileURL = req.FileURL(task.ID)
+	}
+
+	s.mu.Lock()
+	s.taskResults[task.ID] = pdf.Bytes()
+	s.mu.Unlock()
+	s.updateTaskStatus(task.ID, TaskStatusCompleted, report, "")
+}
+
+// NewMonthlyExpenseReport summarises a month's expenses by category.
+func NewMonthlyExpenseReport(id string, userID int64, data *domain.MonthlyData, generatedAt time.Time) *domain.ExpenseReport {
+	report := &domain.ExpenseReport{
+		ID:          id,
+		UserID:      userID,
+		Type:        "monthly",
+		Period:      data.YearMonth,
+		GeneratedAt: generatedAt,
+	}
+	report.Data.TotalExpenses = data.TotalExpenses
+
+	totals := map[string]domain.Money{}
+	counts := map[string]int{}
+	for _, e := range data.Expenses {
+		totals[e.Category] += e.AmountCents
+		counts[e.Category]++
+	}
+	categories := make([]string, 0, len(totals))
+	for c := range totals {
+		categories = append(categories, c)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if totals[categories[i]] != totals[categories[j]] {
+			return totals[categories[i]] > totals[categories[j]]
+		}
+		return categories[i] < categories[j]
+	})
+
+	// The breakdown element type is an anonymous struct, so grow in place.
+	breakdown := slices.Grow(report.Data.CategoryBreakdown, len(categories))[:len(categories)]
+	for i, c := range categories {
+		breakdown[i].Category = c
+		breakdown[i].Amount = totals[c]
+		breakdown[i].Count = counts[c]
+	}
+	report.Data.CategoryBreakdown = breakdown
+	return report
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+func TestNewMonthlyExpenseReport(t *testing.T) {
+	data := &domain.MonthlyData{
+		YearMonth: domain.YearMonth{Year: 2024, Month: 5},
+		Expenses: []domain.Expense{
+			{Category: "Food", AmountCents: 1500},
+			{Category: "Rent", AmountCents: 90000},
+			{Category: "Food", AmountCents: 2500},
+			{Category: "Fun", AmountCents: 4000},
+		},
+		TotalExpenses: 98000,
+	}
+	generated := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	report := NewMonthlyExpenseReport("r1", 7, data, generated)
+	if report.ID != "r1" || report.UserID != 7 || report.Period != data.YearMonth {
+		t.Errorf("Unexpected report header: %+v", report)
+	}
+	if report.Data.TotalExpenses != 98000 {
+		t.Errorf("Expected total 98000, got %d", report.Data.TotalExpenses)
+	}
+
+	expected := []struct {
+		category string
+		amount   domain.Money
+		count    int
+	}{
+		{"Rent", 90000, 1},
+		{"Food", 4000, 2},
+		{"Fun", 4000, 1},
+	}
+	if len(report.Data.CategoryBreakdown) != len(expected) {
+		t.Fatalf("Expected %d categories, got %d", len(expected), len(report.Data.CategoryBreakdown))
+	}
+	for i, e := range expected {
+		got := report.Data.CategoryBreakdown[i]
+		if got.Category != e.category || got.Amount != e.amount || got.Count != e.count {
+			t.Errorf("Category %d: expected %+v, got %+v", i, e, got)
+		}
+	}
+}
@@ -3,7 +3,6 @@ package service
 
 import (
 	"context"
-	"errors"
 
 	"github.com/mdco1990/webapp/internal/domain"
 	"github.com/mdco1990/webapp/internal/repository"
@@ -17,8 +16,9 @@ type Service struct {
 // New creates a Service backed by the provided repository.
 func New(repo *repository.Repository) *Service { return &Service{repo: repo} }
 
-// ErrValidation is returned when inputs fail validation.
-var ErrValidation = errors.New("validation error")
+// ErrValidation is returned when inputs fail validation. It is the domain
+// validation kind, so handlers map it to a 400 problem response.
+var ErrValidation = domain.ErrValidation
 
 func validateYM(ym domain.YearMonth) error {
 	if ym.Year < 1970 || ym.Year > 3000 || ym.Month < 1 || ym.Month > 12 {
@@ -68,6 +68,14 @@ func (s *Service) ListExpenses(ctx context.Context, ym domain.YearMonth) ([]doma
 	return s.repo.ListExpenses(ctx, ym)
 }
 
+// ListExpensesPage returns a page of expenses matching q.
+func (s *Service) ListExpensesPage(
+	ctx context.Context,
+	q domain.ListQuery,
+) (domain.Page[domain.Expense], error) {
+	return s.repo.ListExpensesPage(ctx, q)
+}
+
 // DeleteExpense removes an expense by ID.
 func (s *Service) DeleteExpense(ctx context.Context, id int64) error {
 	if id <= 0 {
@@ -0,0 +1,291 @@
+// Package service implements read-only share links for monthly and yearly reports.
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/repository"
+)
+
+// Share link lifetimes.
+const (
+	DefaultShareTTL = 7 * 24 * time.Hour
+	MaxShareTTL     = 90 * 24 * time.Hour
+	shareTopN       = 5
+)
+
+// ErrShareNotFound is returned for links that do not exist or belong to
+// someone else.
+var ErrShareNotFound = domain.NotFound("share_link_not_found", "share link not found")
+
+// Share link errors. They all read as ErrShareNotFound to clients, so
+// recipients cannot tell which one occurred; errors.Is still tells them apart.
+var (
+	ErrShareInvalid = ErrShareNotFound.WithCause(errors.New("invalid share link"))
+	ErrShareExpired = ErrShareNotFound.WithCause(errors.New("share link expired"))
+	ErrShareRevoked = ErrShareNotFound.WithCause(errors.New("share link revoked"))
+)
+
+// ShareSigner signs and verifies share tokens of the form
+// "<link id>.<expiry unix>.<hmac>". The signature lets forged or tampered
+// tokens be rejected without touching the database; revocation still needs
+// the stored link.
+type ShareSigner struct {
+	secret []byte
+}
+
+// NewShareSigner returns a signer for secret. An empty secret gets a random
+// per-process key, so links stop working after a restart.
+func NewShareSigner(secret []byte) *ShareSigner {
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		_, _ = rand.Read(secret)
+	}
+	return &ShareSigner{secret: secret}
+}
+
+func (s *ShareSigner) mac(payload string) string {
+	m := hmac.New(sha256.New, s.secret)
+	m.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(m.Sum(nil))
+}
+
+// Sign builds the token for a link.
+func (s *ShareSigner) Sign(linkID string, expiresAt time.Time) string {
+	payload := linkID + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return payload + "." + s.mac(payload)
+}
+
+// Verify checks the signature and expiry and returns the link ID.
+func (s *ShareSigner) Verify(token string, now time.Time) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", ErrShareInvalid
+	}
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(s.mac(payload))) {
+		return "", ErrShareInvalid
+	}
+	exp, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", ErrShareInvalid
+	}
+	if !now.Before(time.Unix(exp, 0)) {
+		return "", ErrShareExpired
+	}
+	return parts[0], nil
+}
+
+// ShareService manages share links and renders what recipients see.
+type ShareService struct {
+	repo   *repository.Repository
+	signer *ShareSigner
+	now    func() time.Time
+}
+
+// NewShareService creates a ShareService.
+func NewShareService(repo *repository.Repository, signer *ShareSigner) *ShareService {
+	return &ShareService{repo: repo, signer: signer, now: time.Now}
+}
+
+// CreateLink validates req and stores a new link, returning it with its token.
+func (s *ShareService) CreateLink(
+	ctx context.Context,
+	userID int64,
+	req domain.CreateShareLinkRequest,
+) (*domain.ShareLink, string, error) {
+	period := domain.YearMonth{Year: req.Year, Month: req.Month}
+	var fields []domain.FieldError
+	switch req.Scope {
+	case domain.ShareScopeMonth:
+		if validateYM(period) != nil {
+			fields = append(fields, domain.FieldError{Field: "month", Message: "must be a valid year and month"})
+		}
+	case domain.ShareScopeYear:
+		period.Month = 0
+		if validateYM(domain.YearMonth{Year: req.Year, Month: 1}) != nil {
+			fields = append(fields, domain.FieldError{Field: "year", Message: "must be a valid year"})
+		}
+	default:
+		fields = append(fields, domain.FieldError{Field: "scope", Message: "must be month or year"})
+	}
+
+	ttl := DefaultShareTTL
+	if req.ExpiresInHours > 0 {
+		ttl = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+	if req.ExpiresInHours < 0 || ttl > MaxShareTTL {
+		fields = append(fields, domain.FieldError{
+			Field:   "expires_in_hours",
+			Message: "must be between 0 and " + strconv.Itoa(int(MaxShareTTL.Hours())),
+		})
+	}
+	if len(fields) > 0 {
+		return nil, "", domain.Invalid("invalid share link request", fields...)
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, "", err
+	}
+	now := s.now()
+	link := &domain.ShareLink{
+		ID:        hex.EncodeToString(id),
+		UserID:    userID,
+		Scope:     req.Scope,
+		YearMonth: period,
+		Label:     strings.TrimSpace(req.Label),
+		// Whole seconds, so the stored expiry matches the one in the token.
+		ExpiresAt: now.Add(ttl).Truncate(time.Second),
+		CreatedAt: now,
+	}
+	if err := s.repo.CreateShareLink(ctx, link); err != nil {
+		return nil, "", err
+	}
+	return link, s.signer.Sign(link.ID, link.ExpiresAt), nil
+}
+
+// ListLinks lists a user's links.
+func (s *ShareService) ListLinks(ctx context.Context, userID int64) ([]domain.ShareLink, error) {
+	return s.repo.ListShareLinks(ctx, userID)
+}
+
+// RevokeLink revokes one of the user's links (ErrShareNotFound if not found).
+func (s *ShareService) RevokeLink(ctx context.Context, userID int64, linkID string) error {
+	err := s.repo.RevokeShareLink(ctx, linkID, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrShareNotFound
+	}
+	return err
+}
+
+// ListAccess returns the access log of one of the user's links.
+func (s *ShareService) ListAccess(
+	ctx context.Context,
+	userID int64,
+	linkID string,
+	limit int,
+) ([]domain.ShareAccess, error) {
+	return s.repo.ListShareAccess(ctx, linkID, userID, limit)
+}
+
+// OpenSharedReport resolves a token, logs the access and returns the report.
+func (s *ShareService) OpenSharedReport(
+	ctx context.Context,
+	token string,
+	access domain.ShareAccess,
+) (*domain.SharedReport, error) {
+	now := s.now()
+	linkID, err := s.signer.Verify(token, now)
+	if err != nil {
+		return nil, err
+	}
+	link, err := s.repo.GetShareLink(ctx, linkID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrShareInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if link.RevokedAt != nil {
+		return nil, ErrShareRevoked
+	}
+	if !now.Before(link.ExpiresAt) {
+		return nil, ErrShareExpired
+	}
+
+	access.LinkID = link.ID
+	access.AccessedAt = now
+	if err := s.repo.LogShareAccess(ctx, access); err != nil {
+		return nil, err
+	}
+
+	report := &domain.SharedReport{
+		Scope:     link.Scope,
+		Label:     link.Label,
+		Period:    link.YearMonth,
+		ExpiresAt: link.ExpiresAt,
+	}
+	if link.Scope == domain.ShareScopeMonth {
+		report.Monthly, err = s.repo.GetMonthlyData(ctx, link.UserID, link.YearMonth)
+		return report, err
+	}
+
+	months := make([]*domain.MonthlyData, 0, 12)
+	for m := 1; m <= 12; m++ {
+		data, err := s.repo.GetMonthlyData(ctx, link.UserID, domain.YearMonth{Year: link.Year, Month: m})
+		if err != nil {
+			return nil, err
+		}
+		months = append(months, data)
+	}
+	report.Yearly, report.Months = BuildYearlySummary(link.Year, months)
+	return report, nil
+}
+
+// BuildYearlySummary totals a year of monthly data. MonthlyAverages holds the
+// average monthly income, budget and expenses, in that order.
+func BuildYearlySummary(year int, months []*domain.MonthlyData) (*domain.YearlySummary, []domain.Summary) {
+	summary := &domain.YearlySummary{Year: year}
+	perMonth := make([]domain.Summary, 0, len(months))
+	byCategory := map[string]domain.Money{}
+
+	for _, m := range months {
+		summary.TotalIncome += m.TotalIncome
+		summary.TotalBudget += m.TotalBudget
+		summary.TotalExpenses += m.TotalExpenses
+		for _, e := range m.Expenses {
+			byCategory[e.Category] += e.AmountCents
+		}
+		perMonth = append(perMonth, domain.Summary{
+			YearMonth:    m.YearMonth,
+			SalaryCents:  m.TotalIncome,
+			BudgetCents:  m.TotalBudget,
+			ExpenseCents: m.TotalExpenses,
+			Remaining:    m.Remaining,
+		})
+	}
+	summary.NetSavings = summary.TotalIncome - summary.TotalExpenses
+
+	summary.MonthlyAverages = []domain.Money{}
+	if n := domain.Money(len(months)); n > 0 {
+		summary.MonthlyAverages = []domain.Money{
+			summary.TotalIncome / n, summary.TotalBudget / n, summary.TotalExpenses / n,
+		}
+	}
+
+	categories := make([]string, 0, len(byCategory))
+	for c := range byCategory {
+		categories = append(categories, c)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if byCategory[categories[i]] != byCategory[categories[j]] {
+			return byCategory[categories[i]] > byCategory[categories[j]]
+		}
+		return categories[i] < categories[j]
+	})
+	if len(categories) > shareTopN {
+		categories = categories[:shareTopN]
+	}
+	// TopCategories has an anonymous element type; literals must repeat its tags.
+	top := summary.TopCategories[:0:0]
+	for _, c := range categories {
+		top = append(top, struct {
+			Category string       `json:"category"`
+			Amount   domain.Money `json:"amount_cents"`
+		}{c, byCategory[c]})
+	}
+	summary.TopCategories = top
+	return summary, perMonth
+}
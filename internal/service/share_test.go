@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/db"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/repository"
+)
+
+func TestShareSigner(t *testing.T) {
+	signer := NewShareSigner([]byte("secret"))
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	token := signer.Sign("abc123", now.Add(time.Hour))
+
+	if id, err := signer.Verify(token, now); err != nil || id != "abc123" {
+		t.Fatalf("Expected abc123, got %q (%v)", id, err)
+	}
+	if _, err := signer.Verify(token, now.Add(2*time.Hour)); !errors.Is(err, ErrShareExpired) {
+		t.Errorf("Expected ErrShareExpired, got %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	tampered := []string{
+		"other." + parts[1] + "." + parts[2],                  // different link
+		parts[0] + ".9999999999." + parts[2],                  // extended expiry
+		parts[0] + "." + parts[1] + "." + parts[2][:10] + "x", // bad signature
+		"garbage",
+	}
+	for _, tok := range tampered {
+		if _, err := signer.Verify(tok, now); !errors.Is(err, ErrShareInvalid) {
+			t.Errorf("Verify(%q): Expected ErrShareInvalid, got %v", tok, err)
+		}
+	}
+	if _, err := NewShareSigner([]byte("other")).Verify(token, now); !errors.Is(err, ErrShareInvalid) {
+		t.Errorf("Expected a different secret to reject the token, got %v", err)
+	}
+}
+
+func TestShareService_OpenSharedReport(t *testing.T) {
+	database, err := db.Open("sqlite", ":memory:", "")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	defer func() { _ = database.Close() }()
+	database.SetMaxOpenConns(1)
+	if err := db.Migrate(database); err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+	repo := repository.New(database)
+	ctx := context.Background()
+
+	ym := domain.YearMonth{Year: 2024, Month: 3}
+	if _, err := repo.CreateIncomeSource(ctx, 1, domain.CreateIncomeSourceRequest{
+		Name: "Salary", Year: ym.Year, Month: ym.Month, AmountCents: 300000,
+	}); err != nil {
+		t.Fatalf("CreateIncomeSource failed: %v", err)
+	}
+
+	now := time.Date(2024, 4, 1, 10, 0, 0, 0, time.UTC)
+	shares := NewShareService(repo, NewShareSigner([]byte("k")))
+	shares.now = func() time.Time { return now }
+
+	if _, _, err := shares.CreateLink(ctx, 1, domain.CreateShareLinkRequest{Scope: "week", Year: 2024}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected ErrValidation for unknown scope, got %v", err)
+	}
+	if _, _, err := shares.CreateLink(ctx, 1, domain.CreateShareLinkRequest{
+		Scope: domain.ShareScopeMonth, Year: 2024, Month: 3, ExpiresInHours: 24 * 365,
+	}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected ErrValidation for a too long expiry, got %v", err)
+	}
+
+	link, token, err := shares.CreateLink(ctx, 1, domain.CreateShareLinkRequest{
+		Scope: domain.ShareScopeMonth, Year: 2024, Month: 3, Label: "For the accountant", ExpiresInHours: 48,
+	})
+	if err != nil {
+		t.Fatalf("CreateLink failed: %v", err)
+	}
+
+	report, err := shares.OpenSharedReport(ctx, token, domain.ShareAccess{IPAddress: "203.0.113.7", UserAgent: "test"})
+	if err != nil {
+		t.Fatalf("OpenSharedReport failed: %v", err)
+	}
+	if report.Monthly == nil || report.Monthly.TotalIncome != 300000 || report.Label != "For the accountant" {
+		t.Errorf("Unexpected shared report: %+v", report)
+	}
+
+	yearLink, yearToken, err := shares.CreateLink(ctx, 1, domain.CreateShareLinkRequest{Scope: domain.ShareScopeYear, Year: 2024})
+	if err != nil {
+		t.Fatalf("CreateLink (year) failed: %v", err)
+	}
+	if yearLink.Month != 0 || !yearLink.ExpiresAt.Equal(now.Add(DefaultShareTTL)) {
+		t.Errorf("Unexpected yearly link: %+v", yearLink)
+	}
+	yearly, err := shares.OpenSharedReport(ctx, yearToken, domain.ShareAccess{})
+	if err != nil {
+		t.Fatalf("OpenSharedReport (year) failed: %v", err)
+	}
+	if yearly.Yearly == nil || yearly.Yearly.TotalIncome != 300000 || len(yearly.Months) != 12 {
+		t.Errorf("Unexpected yearly report: %+v", yearly)
+	}
+
+	accesses, err := shares.ListAccess(ctx, 1, link.ID, 10)
+	if err != nil || len(accesses) != 1 || accesses[0].IPAddress != "203.0.113.7" {
+		t.Errorf("Expected one logged access, got %+v (%v)", accesses, err)
+	}
+	if other, _ := shares.ListAccess(ctx, 2, link.ID, 10); len(other) != 0 {
+		t.Errorf("Expected other users not to see the access log, got %+v", other)
+	}
+
+	// Only the owner can revoke; afterwards the token stops working.
+	if err := shares.RevokeLink(ctx, 2, link.ID); err == nil {
+		t.Error("Expected revoking someone else's link to fail")
+	}
+	if err := shares.RevokeLink(ctx, 1, link.ID); err != nil {
+		t.Fatalf("RevokeLink failed: %v", err)
+	}
+	if _, err := shares.OpenSharedReport(ctx, token, domain.ShareAccess{}); !errors.Is(err, ErrShareRevoked) {
+		t.Errorf("Expected ErrShareRevoked, got %v", err)
+	}
+
+	links, err := shares.ListLinks(ctx, 1)
+	if err != nil || len(links) != 2 {
+		t.Fatalf("Expected 2 links, got %d (%v)", len(links), err)
+	}
+	for _, l := range links {
+		if l.ID == link.ID && (l.RevokedAt == nil || l.AccessCount != 1 || l.LastAccessAt == nil) {
+			t.Errorf("Unexpected revoked link state: %+v", l)
+		}
+	}
+
+	shares.now = func() time.Time { return now.Add(49 * time.Hour) }
+	if _, err := shares.OpenSharedReport(ctx, yearToken, domain.ShareAccess{}); err != nil {
+		t.Errorf("Expected the 7-day yearly link to still work, got %v", err)
+	}
+	shares.now = func() time.Time { return now.Add(DefaultShareTTL) }
+	if _, err := shares.OpenSharedReport(ctx, yearToken, domain.ShareAccess{}); !errors.Is(err, ErrShareExpired) {
+		t.Errorf("Expected ErrShareExpired, got %v", err)
+	}
+}
+
+func TestBuildYearlySummary(t *testing.T) {
+	months := []*domain.MonthlyData{
+		{YearMonth: domain.YearMonth{Year: 2024, Month: 1}, TotalIncome: 1000, TotalBudget: 400, TotalExpenses: 300,
+			Remaining: 700, Expenses: []domain.Expense{{Category: "Food", AmountCents: 200}, {Category: "Fun", AmountCents: 100}}},
+		{YearMonth: domain.YearMonth{Year: 2024, Month: 2}, TotalIncome: 1000, TotalBudget: 400, TotalExpenses: 500,
+			Remaining: 500, Expenses: []domain.Expense{{Category: "Food", AmountCents: 500}}},
+	}
+	summary, perMonth := BuildYearlySummary(2024, months)
+	if summary.TotalIncome != 2000 || summary.TotalExpenses != 800 || summary.NetSavings != 1200 {
+		t.Errorf("Unexpected totals: %+v", summary)
+	}
+	if len(summary.MonthlyAverages) != 3 || summary.MonthlyAverages[2] != 400 {
+		t.Errorf("Unexpected averages: %v", summary.MonthlyAverages)
+	}
+	if len(summary.TopCategories) != 2 || summary.TopCategories[0].Category != "Food" || summary.TopCategories[0].Amount != 700 {
+		t.Errorf("Unexpected top categories: %+v", summary.TopCategories)
+	}
+	if len(perMonth) != 2 || perMonth[1].Remaining != 500 {
+		t.Errorf("Unexpected per-month totals: %+v", perMonth)
+	}
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/repository"
+)
+
+// Access token limits. Last use is recorded at most once per
+// tokenUsageResolution, so scripts do not write on every request.
+const (
+	MaxAccessTokensPerUser = 20
+	maxAccessTokenName     = 100
+	maxAccessTokenDays     = 365
+	accessTokenPrefixLen   = len(domain.AccessTokenPrefix) + 6
+	tokenUsageResolution   = time.Minute
+)
+
+// Access token errors.
+var (
+	ErrAccessTokenNotFound = domain.NotFound("access_token_not_found", "access token not found")
+	ErrTooManyAccessTokens = domain.Conflict("too_many_access_tokens",
+		"at most "+strconv.Itoa(MaxAccessTokensPerUser)+" access tokens per user")
+	// ErrAccessTokenInvalid is returned for unknown, revoked and expired
+	// tokens alike.
+	ErrAccessTokenInvalid = domain.Unauthorized("invalid or expired access token")
+	ErrAccessTokenExpired = ErrAccessTokenInvalid.WithCause(errors.New("access token expired"))
+)
+
+// HashAccessToken returns the stored form of a token secret. Tokens are
+// long and random, so a fast hash is enough to keep a leaked table useless.
+func HashAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// TokenService manages users' personal access tokens and authenticates
+// requests made with them.
+type TokenService struct {
+	repo *repository.Repository
+	now  func() time.Time
+}
+
+// NewTokenService creates a TokenService.
+func NewTokenService(repo *repository.Repository) *TokenService {
+	return &TokenService{repo: repo, now: time.Now}
+}
+
+// CreateToken validates req and creates a token for the user. The returned
+// token holds its secret, which is not shown again.
+func (s *TokenService) CreateToken(
+	ctx context.Context,
+	userID int64,
+	req domain.CreateAccessTokenRequest,
+) (*domain.AccessToken, error) {
+	var fields []domain.FieldError
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxAccessTokenName {
+		fields = append(fields, domain.FieldError{
+			Field: "name", Message: "must be 1 to " + strconv.Itoa(maxAccessTokenName) + " characters",
+		})
+	}
+	var scopes []string
+	for _, scope := range req.Scopes {
+		if !slices.Contains(domain.TokenScopes, scope) {
+			fields = append(fields, domain.FieldError{Field: "scopes", Message: "unknown scope " + scope})
+		} else if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(req.Scopes) == 0 {
+		fields = append(fields, domain.FieldError{Field: "scopes", Message: "is required"})
+	}
+	if req.ExpiresInDays < 0 || req.ExpiresInDays > maxAccessTokenDays {
+		fields = append(fields, domain.FieldError{
+			Field: "expires_in_days", Message: "must be between 1 and " + strconv.Itoa(maxAccessTokenDays),
+		})
+	}
+	if len(fields) > 0 {
+		return nil, domain.Invalid("invalid access token request", fields...)
+	}
+
+	existing, err := s.repo.ListAccessTokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= MaxAccessTokensPerUser {
+		return nil, ErrTooManyAccessTokens
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	now := s.now()
+	token := &domain.AccessToken{
+		UserID:    userID,
+		Name:      name,
+		Scopes:    scopes,
+		CreatedAt: now,
+		Token:     domain.AccessTokenPrefix + base64.RawURLEncoding.EncodeToString(secret),
+	}
+	token.Prefix = token.Token[:accessTokenPrefixLen]
+	if req.ExpiresInDays > 0 {
+		expires := now.Add(time.Duration(req.ExpiresInDays) * 24 * time.Hour)
+		token.ExpiresAt = &expires
+	}
+	if err := s.repo.CreateAccessToken(ctx, token, HashAccessToken(token.Token)); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// ListTokens lists the user's tokens, without their secrets.
+func (s *TokenService) ListTokens(ctx context.Context, userID int64) ([]domain.AccessToken, error) {
+	return s.repo.ListAccessTokens(ctx, userID)
+}
+
+// RevokeToken deletes one of the user's tokens; requests made with it fail
+// from then on.
+func (s *TokenService) RevokeToken(ctx context.Context, userID, id int64) error {
+	err := s.repo.DeleteAccessToken(ctx, id, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrAccessTokenNotFound
+	}
+	return err
+}
+
+// Authenticate returns the live token whose secret is token and records its
+// use.
+func (s *TokenService) Authenticate(ctx context.Context, token string) (*domain.AccessToken, error) {
+	if !domain.IsAccessToken(token) {
+		return nil, ErrAccessTokenInvalid
+	}
+	t, err := s.repo.GetAccessTokenByHash(ctx, HashAccessToken(token))
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrAccessTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if t.ExpiresAt != nil && !now.Before(*t.ExpiresAt) {
+		return nil, ErrAccessTokenExpired
+	}
+	if t.LastUsedAt == nil || now.Sub(*t.LastUsedAt) >= tokenUsageResolution {
+		if err := s.repo.TouchAccessToken(context.WithoutCancel(ctx), t.ID, now); err != nil {
+			slog.Warn("failed to record access token use", "err", err, "token_id", t.ID)
+		} else {
+			t.LastUsedAt = &now
+		}
+	}
+	return t, nil
+}
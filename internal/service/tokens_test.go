@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/db"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/repository"
+)
+
+func TestTokenService(t *testing.T) {
+	database, err := db.Open("sqlite", ":memory:", "")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	defer func() { _ = database.Close() }()
+	database.SetMaxOpenConns(1)
+	if err := db.Migrate(database); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	repo := repository.New(database)
+	tokens := NewTokenService(repo)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tokens.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := tokens.CreateToken(ctx, 1, domain.CreateAccessTokenRequest{
+		Name: " ", Scopes: []string{"write:everything"}, ExpiresInDays: 400,
+	}); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+
+	created, err := tokens.CreateToken(ctx, 1, domain.CreateAccessTokenRequest{
+		Name: "CI export", Scopes: []string{domain.ScopeReadBudget, domain.ScopeReadBudget}, ExpiresInDays: 30,
+	})
+	if err != nil {
+		t.Fatalf("CreateToken failed: %v", err)
+	}
+	if !domain.IsAccessToken(created.Token) || !strings.HasPrefix(created.Token, created.Prefix) ||
+		len(created.Scopes) != 1 || created.ExpiresAt == nil || !created.ExpiresAt.Equal(now.Add(30*24*time.Hour)) {
+		t.Fatalf("Expected a 30-day read token with its secret, got %+v", created)
+	}
+
+	// Only the hash is kept, so listing cannot show the secret again.
+	list, err := tokens.ListTokens(ctx, 1)
+	if err != nil || len(list) != 1 || list[0].Token != "" || list[0].Prefix != created.Prefix {
+		t.Fatalf("Expected the token listed without its secret, got %+v (%v)", list, err)
+	}
+
+	got, err := tokens.Authenticate(ctx, created.Token)
+	if err != nil || got.UserID != 1 || got.LastUsedAt == nil {
+		t.Fatalf("Expected the token to authenticate user 1, got %+v (%v)", got, err)
+	}
+	if got.HasScope(domain.ScopeWriteExpenses) || !got.HasScope(domain.ScopeReadBudget) {
+		t.Errorf("Expected only the read scope, got %v", got.Scopes)
+	}
+	if list, _ := tokens.ListTokens(ctx, 1); list[0].LastUsedAt == nil {
+		t.Error("Expected the last use to be recorded")
+	}
+	if _, err := tokens.Authenticate(ctx, created.Token+"x"); !errors.Is(err, ErrAccessTokenInvalid) {
+		t.Errorf("Expected an unknown token refused, got %v", err)
+	}
+
+	now = now.Add(31 * 24 * time.Hour)
+	if _, err := tokens.Authenticate(ctx, created.Token); !errors.Is(err, ErrAccessTokenExpired) {
+		t.Errorf("Expected the expired token refused, got %v", err)
+	}
+
+	// Revoked tokens stop working; other users cannot revoke them.
+	forever, err := tokens.CreateToken(ctx, 1, domain.CreateAccessTokenRequest{
+		Name: "Home Assistant", Scopes: []string{domain.ScopeAdmin},
+	})
+	if err != nil || forever.ExpiresAt != nil {
+		t.Fatalf("Expected a token without expiry, got %+v (%v)", forever, err)
+	}
+	if err := tokens.RevokeToken(ctx, 2, forever.ID); !errors.Is(err, ErrAccessTokenNotFound) {
+		t.Errorf("Expected another user's revoke to fail, got %v", err)
+	}
+	if err := tokens.RevokeToken(ctx, 1, forever.ID); err != nil {
+		t.Fatalf("RevokeToken failed: %v", err)
+	}
+	if _, err := tokens.Authenticate(ctx, forever.Token); !errors.Is(err, ErrAccessTokenInvalid) {
+		t.Errorf("Expected the revoked token refused, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/repository"
+)
+
+// trashPurgeInterval is how often Run purges expired trash.
+const trashPurgeInterval = time.Hour
+
+// TrashService lists, restores and purges deleted records. Records stay in
+// the trash for the retention period, after which Run deletes them for good;
+// a zero retention keeps them until they are purged by hand.
+type TrashService struct {
+	repo      *repository.Repository
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewTrashService creates a trash service keeping deleted records for
+// retention.
+func NewTrashService(repo *repository.Repository, retention time.Duration) *TrashService {
+	return &TrashService{repo: repo, retention: retention, now: time.Now}
+}
+
+// List returns the user's trash, most recently deleted first, with the time
+// each record will be purged.
+func (s *TrashService) List(ctx context.Context, userID int64) ([]domain.TrashItem, error) {
+	items, err := s.repo.ListTrash(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if s.retention > 0 {
+		for i := range items {
+			purgeAt := items[i].DeletedAt.Add(s.retention)
+			items[i].PurgeAt = &purgeAt
+		}
+	}
+	return items, nil
+}
+
+// Restore takes a record out of the user's trash.
+func (s *TrashService) Restore(ctx context.Context, userID int64, typ string, id int64) error {
+	return s.repo.RestoreTrashItem(ctx, userID, typ, id)
+}
+
+// Purge deletes a record from the user's trash for good.
+func (s *TrashService) Purge(ctx context.Context, userID int64, typ string, id int64) error {
+	return s.repo.PurgeTrashItem(ctx, userID, typ, id)
+}
+
+// PurgeExpired deletes the records that have been in the trash longer than
+// the retention period and returns how many it deleted.
+func (s *TrashService) PurgeExpired(ctx context.Context) (int64, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+	return s.repo.PurgeTrash(ctx, s.now().Add(-s.retention))
+}
+
+// Run purges expired trash now and then every trashPurgeInterval until ctx
+// is done.
+func (s *TrashService) Run(ctx context.Context) {
+	if s.retention <= 0 {
+		return
+	}
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+	for {
+		if n, err := s.PurgeExpired(ctx); err != nil {
+			slog.Error("failed to purge trash", "err", err)
+		} else if n > 0 {
+			slog.Info("purged trash", "records", n, "retention", s.retention)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
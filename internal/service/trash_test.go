@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/db"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/repository"
+)
+
+func TestTrashService_Retention(t *testing.T) {
+	database, err := db.Open("sqlite", ":memory:", "")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	defer func() { _ = database.Close() }()
+	database.SetMaxOpenConns(1)
+	if err := db.Migrate(database); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	repo := repository.New(database)
+	ctx := context.Background()
+
+	id, err := repo.AddExpense(ctx, &domain.Expense{
+		YearMonth: domain.YearMonth{Year: 2024, Month: 3}, Description: "Taxi", AmountCents: 1500,
+	})
+	if err != nil {
+		t.Fatalf("AddExpense failed: %v", err)
+	}
+	if err := repo.DeleteExpense(ctx, id); err != nil {
+		t.Fatalf("DeleteExpense failed: %v", err)
+	}
+
+	// Without retention the trash is kept and has no purge time.
+	keep := NewTrashService(repo, 0)
+	items, err := keep.List(ctx, 1)
+	if err != nil || len(items) != 1 || items[0].PurgeAt != nil {
+		t.Fatalf("Expected one item without a purge time, got %+v (%v)", items, err)
+	}
+	if n, err := keep.PurgeExpired(ctx); err != nil || n != 0 {
+		t.Errorf("Expected nothing purged without retention, got %d (%v)", n, err)
+	}
+
+	trash := NewTrashService(repo, 7*24*time.Hour)
+	items, err = trash.List(ctx, 1)
+	if err != nil || len(items) != 1 || items[0].PurgeAt == nil ||
+		!items[0].PurgeAt.Equal(items[0].DeletedAt.Add(7*24*time.Hour)) {
+		t.Fatalf("Expected a purge time a week after the delete, got %+v (%v)", items, err)
+	}
+	if n, err := trash.PurgeExpired(ctx); err != nil || n != 0 {
+		t.Errorf("Expected nothing purged within retention, got %d (%v)", n, err)
+	}
+	trash.now = func() time.Time { return time.Now().Add(8 * 24 * time.Hour) }
+	if n, err := trash.PurgeExpired(ctx); err != nil || n != 1 {
+		t.Errorf("Expected the expense purged after retention, got %d (%v)", n, err)
+	}
+}
@@ -0,0 +1,440 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/events"
+	"github.com/mdco1990/webapp/internal/repository"
+)
+
+// Webhook limits and delivery schedule. A failing delivery is retried after
+// 30s, 1m, 2m, ... (capped at 6h) until it has been attempted
+// webhookMaxAttempts times.
+const (
+	MaxWebhooksPerUser  = 10
+	maxWebhookURLLength = 2048
+	webhookMaxAttempts  = 8
+	webhookBaseBackoff  = 30 * time.Second
+	webhookMaxBackoff   = 6 * time.Hour
+	webhookTimeout      = 10 * time.Second
+	webhookPollInterval = 15 * time.Second
+	webhookBatchSize    = 50
+	maxWebhookErrorLen  = 512
+)
+
+// Headers sent with every delivery.
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// Webhook errors.
+var (
+	ErrWebhookNotFound         = domain.NotFound("webhook_not_found", "webhook not found")
+	ErrWebhookDeliveryNotFound = domain.NotFound("webhook_delivery_not_found", "webhook delivery not found")
+	ErrTooManyWebhooks         = domain.Conflict("too_many_webhooks",
+		"at most "+strconv.Itoa(MaxWebhooksPerUser)+" webhooks per user")
+	errWebhookAddress = errors.New("webhook address is not public")
+)
+
+// SignWebhook returns the signature header value for a delivery body sent
+// at timestamp: "t=<unix>,v1=<hex HMAC-SHA256 of "<unix>.<body>">". Receivers
+// recompute it with the webhook's secret and should reject old timestamps.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	t := strconv.FormatInt(timestamp, 10)
+	m := hmac.New(sha256.New, []byte(secret))
+	m.Write([]byte(t + "."))
+	m.Write(body)
+	return "t=" + t + ",v1=" + hex.EncodeToString(m.Sum(nil))
+}
+
+// webhookPayload is the JSON body of a delivery.
+type webhookPayload struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// WebhookService manages users' webhooks and delivers the events they
+// subscribe to. Deliveries are queued in the database, so retries survive a
+// restart.
+type WebhookService struct {
+	repo        *repository.Repository
+	client      *http.Client
+	now         func() time.Time
+	wake        chan struct{}
+	maxAttempts int
+	baseBackoff time.Duration
+}
+
+// NewWebhookService creates a WebhookService. Call Attach to queue events
+// and Run to deliver them.
+func NewWebhookService(repo *repository.Repository) *WebhookService {
+	return &WebhookService{
+		repo:        repo,
+		client:      newWebhookClient(),
+		now:         time.Now,
+		wake:        make(chan struct{}, 1),
+		maxAttempts: webhookMaxAttempts,
+		baseBackoff: webhookBaseBackoff,
+	}
+}
+
+// newWebhookClient returns the client deliveries are sent with. Users choose
+// the URLs, so it only connects to public addresses: the check runs on the
+// address actually dialled, after DNS resolution, so a name that is later
+// rebound to an internal address is refused too. No proxy is used, since
+// the proxy would make the connection instead.
+func newWebhookClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout:   webhookTimeout,
+		KeepAlive: 30 * time.Second,
+		Control:   webhookDialControl,
+	}).DialContext
+	return &http.Client{
+		Transport: transport,
+		Timeout:   webhookTimeout,
+		// A redirect is reported as the 3xx it is, not followed.
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+}
+
+// webhookDialControl refuses connections to addresses that are not public.
+func webhookDialControl(_, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !publicAddr(ap.Addr()) {
+		return fmt.Errorf("%w: %s", errWebhookAddress, ap.Addr())
+	}
+	return nil
+}
+
+// publicAddr reports whether a webhook may be delivered to addr: it must not
+// be loopback, link-local, private, multicast or unspecified.
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() && addr.IsGlobalUnicast() && !addr.IsPrivate()
+}
+
+// CreateWebhook validates req and stores a new webhook for the user. The
+// returned webhook holds its signing secret, which is not shown again.
+func (s *WebhookService) CreateWebhook(
+	ctx context.Context,
+	userID int64,
+	req domain.CreateWebhookRequest,
+) (*domain.Webhook, error) {
+	var fields []domain.FieldError
+	u, err := url.Parse(strings.TrimSpace(req.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+		len(req.URL) > maxWebhookURLLength {
+		fields = append(fields, domain.FieldError{Field: "url", Message: "must be an absolute http or https URL"})
+	}
+	var types []string
+	for _, t := range req.EventTypes {
+		if t != domain.WebhookAllEvents && !slices.Contains(domain.WebhookEventTypes, t) {
+			fields = append(fields, domain.FieldError{Field: "event_types", Message: "unknown event type " + t})
+		} else if !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	if len(req.EventTypes) == 0 {
+		fields = append(fields, domain.FieldError{Field: "event_types", Message: "is required"})
+	}
+	if len(fields) > 0 {
+		return nil, domain.Invalid("invalid webhook request", fields...)
+	}
+
+	existing, err := s.repo.ListWebhooks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= MaxWebhooksPerUser {
+		return nil, ErrTooManyWebhooks
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	hook := &domain.Webhook{
+		UserID:     userID,
+		URL:        u.String(),
+		EventTypes: types,
+		Secret:     "whsec_" + hex.EncodeToString(secret),
+		CreatedAt:  s.now(),
+	}
+	if err := s.repo.CreateWebhook(ctx, hook); err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+// ListWebhooks lists the user's webhooks without their secrets.
+func (s *WebhookService) ListWebhooks(ctx context.Context, userID int64) ([]domain.Webhook, error) {
+	hooks, err := s.repo.ListWebhooks(ctx, userID)
+	for i := range hooks {
+		hooks[i].Secret = ""
+	}
+	return hooks, err
+}
+
+// DeleteWebhook deletes one of the user's webhooks and its delivery log.
+func (s *WebhookService) DeleteWebhook(ctx context.Context, userID, id int64) error {
+	err := s.repo.DeleteWebhook(ctx, id, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrWebhookNotFound
+	}
+	return err
+}
+
+// ListDeliveries returns the most recent deliveries of one of the user's
+// webhooks.
+func (s *WebhookService) ListDeliveries(
+	ctx context.Context,
+	userID, webhookID int64,
+	limit int,
+) ([]domain.WebhookDelivery, error) {
+	return s.repo.ListWebhookDeliveries(ctx, webhookID, userID, limit)
+}
+
+// GetDelivery returns a delivery of one of the user's webhooks with every
+// attempt made.
+func (s *WebhookService) GetDelivery(
+	ctx context.Context,
+	userID, webhookID, id int64,
+) (*domain.WebhookDelivery, error) {
+	d, err := s.repo.GetWebhookDelivery(ctx, id, webhookID, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	return d, err
+}
+
+// Redeliver queues a delivery again right away, whatever its status, with a
+// fresh set of retries.
+func (s *WebhookService) Redeliver(ctx context.Context, userID, webhookID, id int64) error {
+	err := s.repo.RequeueWebhookDelivery(ctx, id, webhookID, userID, s.now())
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrWebhookDeliveryNotFound
+	}
+	if err == nil {
+		s.notify()
+	}
+	return err
+}
+
+// Attach queues a delivery to each subscribed webhook for events published
+// on bus.
+func (s *WebhookService) Attach(bus *events.EventBus) (string, error) {
+	return bus.SubscribePattern("*", s.enqueue)
+}
+
+// enqueue queues event for the webhooks of the user it concerns.
+func (s *WebhookService) enqueue(ctx context.Context, event events.Event) error {
+	userID, eventType, data, ok := webhookEvent(event)
+	if !ok || userID <= 0 {
+		return nil
+	}
+	hooks, err := s.repo.ListWebhooks(ctx, userID)
+	if err != nil {
+		return err
+	}
+	var payload []byte
+	queued := false
+	for _, hook := range hooks {
+		if !hook.Wants(eventType) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(webhookPayload{
+				ID: event.ID(), Type: eventType, CreatedAt: event.Timestamp().UTC(), Data: data,
+			}); err != nil {
+				return err
+			}
+		}
+		now := s.now()
+		if err := s.repo.CreateWebhookDelivery(ctx, &domain.WebhookDelivery{
+			WebhookID:     hook.ID,
+			EventID:       event.ID(),
+			EventType:     eventType,
+			Payload:       payload,
+			Status:        domain.WebhookPending,
+			NextAttemptAt: &now,
+			CreatedAt:     now,
+		}); err != nil {
+			return err
+		}
+		queued = true
+	}
+	if queued {
+		s.notify()
+	}
+	return nil
+}
+
+// webhookEvent maps a bus event to the user it concerns, its webhook event
+// type and payload data. Expense changes concern the user who made them.
+func webhookEvent(event events.Event) (int64, string, any, bool) {
+	switch e := event.(type) {
+	case *events.ChangeEvent:
+		userID := e.Change.UserID
+		if userID == 0 {
+			userID = e.ActorID
+		}
+		return userID, e.Change.Entity + "." + e.Change.Action, e.Change, true
+	case *events.BudgetExceededEvent:
+		return e.UserID, domain.WebhookBudgetExceeded, map[string]any{
+			"year":               e.Year,
+			"month":              e.Month,
+			"budget_limit_cents": e.BudgetLimit,
+			"actual_spent_cents": e.ActualSpent,
+			"excess_cents":       e.Excess,
+		}, true
+	case *events.DataExportEvent:
+		return e.UserID, domain.WebhookDataExport, map[string]any{
+			"export_type":     e.ExportType,
+			"format":          e.Format,
+			"file_size_bytes": e.FileSize,
+			"status":          e.Status,
+		}, true
+	}
+	return 0, "", nil, false
+}
+
+// notify wakes Run without blocking.
+func (s *WebhookService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run delivers due webhooks until ctx is done, whenever deliveries are
+// queued and at least every poll interval for retries.
+func (s *WebhookService) Run(ctx context.Context) {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+	for {
+		s.deliverDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// deliverDue attempts every delivery that is due, one at a time, until ctx
+// is done.
+func (s *WebhookService) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		deliveries, hooks, err := s.repo.DueWebhookDeliveries(ctx, s.now(), webhookBatchSize)
+		if err != nil {
+			slog.Error("failed to load webhook deliveries", "err", err)
+			return
+		}
+		for i := range deliveries {
+			if ctx.Err() != nil {
+				return
+			}
+			s.deliver(ctx, &deliveries[i], &hooks[i])
+		}
+		if len(deliveries) < webhookBatchSize {
+			return
+		}
+	}
+}
+
+// deliver makes one attempt and records it, scheduling a retry on failure.
+// An attempt that has started is finished even if ctx is cancelled; the
+// client's timeout bounds it.
+func (s *WebhookService) deliver(ctx context.Context, d *domain.WebhookDelivery, hook *domain.Webhook) {
+	ctx = context.WithoutCancel(ctx)
+	now := s.now()
+	attempt := domain.WebhookAttempt{DeliveryID: d.ID, AttemptedAt: now}
+	start := time.Now()
+	code, err := s.post(ctx, d, hook, now)
+	attempt.DurationMS = time.Since(start).Milliseconds()
+	attempt.ResponseCode = code
+	if err != nil {
+		attempt.Error = err.Error()
+		if len(attempt.Error) > maxWebhookErrorLen {
+			attempt.Error = attempt.Error[:maxWebhookErrorLen]
+		}
+	}
+
+	status := domain.WebhookDelivered
+	var next *time.Time
+	if err != nil || code < 200 || code > 299 {
+		status = domain.WebhookFailed
+		if n := d.Attempts + 1; n < s.maxAttempts {
+			status = domain.WebhookPending
+			at := now.Add(s.backoff(n))
+			next = &at
+		}
+	}
+	if err := s.repo.RecordWebhookAttempt(ctx, attempt, status, next); err != nil {
+		slog.Error("failed to record webhook attempt", "err", err, "delivery_id", d.ID)
+	}
+}
+
+// post sends the delivery and returns the response status.
+func (s *WebhookService) post(
+	ctx context.Context,
+	d *domain.WebhookDelivery,
+	hook *domain.Webhook,
+	now time.Time,
+) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "webapp-webhooks/1")
+	req.Header.Set(WebhookEventHeader, d.EventType)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(hook.Secret, now.Unix(), d.Payload))
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	// Drain a little so the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, nil
+}
+
+// backoff is the wait before retrying after the nth failed attempt.
+func (s *WebhookService) backoff(n int) time.Duration {
+	d := s.baseBackoff
+	for i := 1; i < n && d < webhookMaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, webhookMaxBackoff)
+}
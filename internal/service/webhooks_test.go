@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/db"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/events"
+	"github.com/mdco1990/webapp/internal/repository"
+)
+
+func TestSignWebhook(t *testing.T) {
+	body := []byte(`{"id":"evt_1"}`)
+	sig := SignWebhook("whsec_test", 1700000000, body)
+	if !strings.HasPrefix(sig, "t=1700000000,v1=") || len(sig) != len("t=1700000000,v1=")+64 {
+		t.Fatalf("Expected t=...,v1=<hex sha256>, got %s", sig)
+	}
+	if SignWebhook("whsec_test", 1700000000, []byte(`{"id":"evt_2"}`)) == sig ||
+		SignWebhook("whsec_other", 1700000000, body) == sig ||
+		SignWebhook("whsec_test", 1700000001, body) == sig {
+		t.Error("Expected the signature to cover the secret, timestamp and body")
+	}
+}
+
+func TestWebhookDialControl(t *testing.T) {
+	for addr, public := range map[string]bool{
+		"93.184.215.14:443":         true,
+		"[2606:4700::6810:84e5]:80": true,
+		"127.0.0.1:8080":            false,
+		"[::1]:80":                  false,
+		"10.1.2.3:80":               false,
+		"172.16.0.1:80":             false,
+		"192.168.1.1:80":            false,
+		"169.254.169.254:80":        false,
+		"[fe80::1]:80":              false,
+		"[fd00::1]:80":              false,
+		"0.0.0.0:80":                false,
+		"[::]:80":                   false,
+		"[::ffff:127.0.0.1]:80":     false,
+	} {
+		if err := webhookDialControl("tcp", addr, nil); (err == nil) != public {
+			t.Errorf("%s: expected public=%v, got %v", addr, public, err)
+		}
+	}
+
+	// Deliveries to a loopback receiver are refused before connecting.
+	var hits int
+	receiver := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { hits++ }))
+	defer receiver.Close()
+	webhooks := NewWebhookService(nil)
+	code, err := webhooks.post(context.Background(), &domain.WebhookDelivery{Payload: []byte("{}")},
+		&domain.Webhook{URL: receiver.URL, Secret: "whsec_test"}, time.Now())
+	if !errors.Is(err, errWebhookAddress) || code != 0 || hits != 0 {
+		t.Errorf("Expected the loopback delivery refused, got %d %v (%d hits)", code, err, hits)
+	}
+}
+
+func TestWebhookService_Deliveries(t *testing.T) {
+	database, err := db.Open("sqlite", ":memory:", "")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	defer func() { _ = database.Close() }()
+	database.SetMaxOpenConns(1)
+	if err := db.Migrate(database); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	repo := repository.New(database)
+	bus := events.NewEventBus(nil)
+	announcer := NewChangeAnnouncer(repo, bus)
+	repo.OnChange(func(ctx context.Context, c domain.Change) { announcer.Announce(ctx, 1, c) })
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	webhooks := NewWebhookService(repo)
+	webhooks.now = func() time.Time { return now }
+	webhooks.maxAttempts = 3
+	if _, err := webhooks.Attach(bus); err != nil {
+		t.Fatalf("Attach failed: %v", err)
+	}
+
+	// The receiver answers with the queued status codes, then 200.
+	var (
+		mu       sync.Mutex
+		statuses = []int{http.StatusInternalServerError}
+		received []*http.Request
+		bodies   [][]byte
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		received, bodies = append(received, r), append(bodies, body)
+		status := http.StatusOK
+		if len(statuses) > 0 {
+			status, statuses = statuses[0], statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	defer receiver.Close()
+	// The receiver listens on loopback, which the delivery client refuses.
+	webhooks.client = receiver.Client()
+	webhooks.client.CheckRedirect = newWebhookClient().CheckRedirect
+
+	ctx := context.Background()
+	if _, err := webhooks.CreateWebhook(ctx, 1, domain.CreateWebhookRequest{
+		URL: "ftp://example.com", EventTypes: []string{"expense.exploded"},
+	}); err == nil || len(err.(*domain.Error).Fields) != 2 {
+		t.Errorf("Expected url and event_types errors, got %v", err)
+	}
+	hook, err := webhooks.CreateWebhook(ctx, 1, domain.CreateWebhookRequest{
+		URL: receiver.URL, EventTypes: []string{"income_source.created", domain.WebhookBudgetExceeded},
+	})
+	if err != nil || !strings.HasPrefix(hook.Secret, "whsec_") {
+		t.Fatalf("Expected a webhook with a secret, got %+v (%v)", hook, err)
+	}
+	if hooks, _ := webhooks.ListWebhooks(ctx, 1); len(hooks) != 1 || hooks[0].Secret != "" {
+		t.Errorf("Expected the listed webhook without its secret, got %+v", hooks)
+	}
+
+	ym := domain.YearMonth{Year: 2024, Month: 3}
+	if err := repo.UpsertBudget(ctx, ym, 10000); err != nil {
+		t.Fatalf("UpsertBudget failed: %v", err)
+	}
+	if _, err := repo.CreateIncomeSource(ctx, 1, domain.CreateIncomeSourceRequest{
+		Name: "Salary", Year: 2024, Month: 3, AmountCents: 250000,
+	}); err != nil {
+		t.Fatalf("CreateIncomeSource failed: %v", err)
+	}
+	// Not subscribed to expense.created, but this one exceeds the budget.
+	if _, err := repo.AddExpense(ctx, &domain.Expense{YearMonth: ym, Description: "Rent", AmountCents: 12000}); err != nil {
+		t.Fatalf("AddExpense failed: %v", err)
+	}
+
+	webhooks.deliverDue(ctx)
+	if len(received) != 2 {
+		t.Fatalf("Expected 2 deliveries, got %d", len(received))
+	}
+	for i, r := range received {
+		ts, _ := strconv.ParseInt(strings.TrimPrefix(strings.Split(r.Header.Get(WebhookSignatureHeader), ",")[0], "t="), 10, 64)
+		if r.Header.Get(WebhookSignatureHeader) != SignWebhook(hook.Secret, ts, bodies[i]) || ts != now.Unix() {
+			t.Errorf("Expected a valid signature, got %s", r.Header.Get(WebhookSignatureHeader))
+		}
+	}
+	var exceeded struct {
+		Type string         `json:"type"`
+		Data map[string]any `json:"data"`
+	}
+	_ = json.Unmarshal(bodies[1], &exceeded)
+	if received[1].Header.Get(WebhookEventHeader) != domain.WebhookBudgetExceeded ||
+		exceeded.Type != domain.WebhookBudgetExceeded || exceeded.Data["excess_cents"] != float64(2000) {
+		t.Errorf("Expected budget.exceeded by 2000, got %s", bodies[1])
+	}
+
+	deliveries, err := webhooks.ListDeliveries(ctx, 1, hook.ID, 10)
+	if err != nil || len(deliveries) != 2 {
+		t.Fatalf("Expected 2 logged deliveries, got %d (%v)", len(deliveries), err)
+	}
+	failed := deliveries[1] // newest first
+	if failed.Status != domain.WebhookPending || failed.ResponseCode != http.StatusInternalServerError ||
+		failed.NextAttemptAt == nil || !failed.NextAttemptAt.Equal(now.Add(30*time.Second)) {
+		t.Fatalf("Expected a retry in 30s after a 500, got %+v", failed)
+	}
+	if deliveries[0].Status != domain.WebhookDelivered || deliveries[0].ResponseCode != http.StatusOK {
+		t.Errorf("Expected the second delivery delivered, got %+v", deliveries[0])
+	}
+
+	// Not due yet, then retried and delivered.
+	webhooks.deliverDue(ctx)
+	now = now.Add(30 * time.Second)
+	webhooks.deliverDue(ctx)
+	d, err := webhooks.GetDelivery(ctx, 1, hook.ID, failed.ID)
+	if err != nil || d.Status != domain.WebhookDelivered || len(d.History) != 2 ||
+		d.History[0].ResponseCode != http.StatusInternalServerError || d.History[1].ResponseCode != http.StatusOK {
+		t.Fatalf("Expected delivered on the second attempt, got %+v (%v)", d, err)
+	}
+
+	// A manual redelivery that keeps failing gives up after maxAttempts.
+	statuses = []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}
+	if err := webhooks.Redeliver(ctx, 1, hook.ID, failed.ID); err != nil {
+		t.Fatalf("Redeliver failed: %v", err)
+	}
+	for range 3 {
+		webhooks.deliverDue(ctx)
+		now = now.Add(time.Minute)
+	}
+	d, _ = webhooks.GetDelivery(ctx, 1, hook.ID, failed.ID)
+	if d.Status != domain.WebhookFailed || d.Attempts != 3 || d.NextAttemptAt != nil || len(d.History) != 5 {
+		t.Errorf("Expected failed after 3 more attempts, got %+v", d)
+	}
+
+	if err := webhooks.Redeliver(ctx, 2, hook.ID, failed.ID); !errors.Is(err, ErrWebhookDeliveryNotFound) {
+		t.Errorf("Expected another user's delivery to be not found, got %v", err)
+	}
+	if err := webhooks.DeleteWebhook(ctx, 1, hook.ID); err != nil {
+		t.Fatalf("DeleteWebhook failed: %v", err)
+	}
+	if _, err := webhooks.GetDelivery(ctx, 1, hook.ID, failed.ID); !errors.Is(err, ErrWebhookDeliveryNotFound) {
+		t.Errorf("Expected deliveries deleted with the webhook, got %v", err)
+	}
+}
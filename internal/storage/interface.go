@@ -36,6 +36,15 @@ type Provider interface {
 	Close(ctx context.Context) error
 }
 
+// Claimer is implemented by providers that can store a value only while its
+// key is absent or expired, atomically, so that processes sharing the store
+// can use it for locks.
+type Claimer interface {
+	// SaveIfAbsent stores a value with an optional TTL unless the key holds
+	// a live value, and reports whether it stored it
+	SaveIfAbsent(ctx context.Context, key string, value interface{}, ttl *time.Duration) (bool, error)
+}
+
 // Stats provides information about storage usage
 type Stats struct {
 	TotalKeys    int64     `json:"total_keys"`
@@ -0,0 +1,195 @@
+// Package storage implements an in-memory storage provider.
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStorage implements Provider in process memory. Values are stored as
+// given, without serialization; expired entries are dropped lazily on access
+// and swept on Save once per cleanup interval.
+type MemoryStorage struct {
+	mu          sync.Mutex
+	options     Options
+	entries     map[string]memoryEntry
+	lastCleanup time.Time
+}
+
+type memoryEntry struct {
+	value     interface{}
+	expiresAt *time.Time
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return e.expiresAt != nil && !now.Before(*e.expiresAt)
+}
+
+// NewMemoryStorage creates an empty in-memory storage provider.
+func NewMemoryStorage(options Options) *MemoryStorage {
+	if options.CleanupInterval <= 0 {
+		options.CleanupInterval = 5 * time.Minute
+	}
+	return &MemoryStorage{options: options, entries: make(map[string]memoryEntry), lastCleanup: time.Now()}
+}
+
+func keyNotFound(op, key string) error {
+	return &Error{Op: op, Key: key, Message: "key not found", Code: "KEY_NOT_FOUND"}
+}
+
+// Save stores a value with optional TTL
+func (m *MemoryStorage) Save(_ context.Context, key string, value interface{}, ttl *time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.save(key, value, ttl)
+}
+
+// SaveIfAbsent stores a value with optional TTL unless the key holds a live
+// value
+func (m *MemoryStorage) SaveIfAbsent(_ context.Context, key string, value interface{}, ttl *time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.get(key); exists {
+		return false, nil
+	}
+	if err := m.save(key, value, ttl); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// save stores a value. m.mu must be held.
+func (m *MemoryStorage) save(key string, value interface{}, ttl *time.Duration) error {
+	now := time.Now()
+	if now.Sub(m.lastCleanup) >= m.options.CleanupInterval {
+		m.sweep(now)
+	}
+	if _, exists := m.entries[key]; !exists && m.options.MaxKeys > 0 && int64(len(m.entries)) >= m.options.MaxKeys {
+		return &Error{Op: "Save", Key: key, Message: "maximum number of keys reached", Code: "KEY_LIMIT_EXCEEDED"}
+	}
+
+	if ttl == nil {
+		ttl = m.options.DefaultTTL
+	}
+	e := memoryEntry{value: value}
+	if ttl != nil {
+		exp := now.Add(*ttl)
+		e.expiresAt = &exp
+	}
+	m.entries[key] = e
+	return nil
+}
+
+// Load retrieves a value by key
+func (m *MemoryStorage) Load(_ context.Context, key string) (interface{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.get(key)
+	if !ok {
+		return nil, keyNotFound("Load", key)
+	}
+	return e.value, nil
+}
+
+// Delete removes a value by key
+func (m *MemoryStorage) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.get(key); !ok {
+		return keyNotFound("Delete", key)
+	}
+	delete(m.entries, key)
+	return nil
+}
+
+// Exists checks if a key exists
+func (m *MemoryStorage) Exists(_ context.Context, key string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.get(key)
+	return ok, nil
+}
+
+// GetTTL returns the remaining TTL for a key
+func (m *MemoryStorage) GetTTL(_ context.Context, key string) (*time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.get(key)
+	if !ok {
+		return nil, keyNotFound("GetTTL", key)
+	}
+	if e.expiresAt == nil {
+		return nil, ErrNoTTL
+	}
+	remaining := time.Until(*e.expiresAt)
+	return &remaining, nil
+}
+
+// SetTTL updates the TTL for a key
+func (m *MemoryStorage) SetTTL(_ context.Context, key string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.get(key)
+	if !ok {
+		return keyNotFound("SetTTL", key)
+	}
+	exp := time.Now().Add(ttl)
+	e.expiresAt = &exp
+	m.entries[key] = e
+	return nil
+}
+
+// Clear removes all data from storage
+func (m *MemoryStorage) Clear(_ context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries = make(map[string]memoryEntry)
+	return nil
+}
+
+// GetStats returns storage statistics
+func (m *MemoryStorage) GetStats(_ context.Context) (*Stats, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := &Stats{TotalKeys: int64(len(m.entries)), LastCleanup: m.lastCleanup, ProviderType: "memory"}
+	now := time.Now()
+	for _, e := range m.entries {
+		if e.expired(now) {
+			stats.ExpiredKeys++
+		}
+	}
+	return stats, nil
+}
+
+// Close releases all entries
+func (m *MemoryStorage) Close(ctx context.Context) error {
+	return m.Clear(ctx)
+}
+
+// get returns a live entry, dropping it if it has expired. m.mu must be held.
+func (m *MemoryStorage) get(key string) (memoryEntry, bool) {
+	e, ok := m.entries[key]
+	if ok && e.expired(time.Now()) {
+		delete(m.entries, key)
+		return memoryEntry{}, false
+	}
+	return e, ok
+}
+
+// sweep drops all expired entries. m.mu must be held.
+func (m *MemoryStorage) sweep(now time.Time) {
+	for key, e := range m.entries {
+		if e.expired(now) {
+			delete(m.entries, key)
+		}
+	}
+	m.lastCleanup = now
+}
@@ -22,6 +22,7 @@ type SQLiteStorage struct {
 	db            *sql.DB
 	options       Options
 	mu            sync.RWMutex
+	handlersMu    sync.RWMutex // separate from mu, which writes hold while emitting events
 	eventHandlers []EventHandler
 	cleanupTicker *time.Ticker
 	done          chan bool
@@ -39,7 +40,7 @@ type SQLiteStorageEntry struct {
 
 // NewSQLiteStorage creates a new SQLite storage provider
 func NewSQLiteStorage(dbPath string, options Options) (*SQLiteStorage, error) {
-	db, err := sql.Open("sqlite3", dbPath)
+	db, err := sql.Open("sqlite", dbPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
 	}
@@ -193,6 +194,72 @@ func (s *SQLiteStorage) Save(ctx context.Context, key string, value interface{},
 	return nil
 }
 
+// SaveIfAbsent stores a value with optional TTL unless the key holds a live
+// value. The check and the write are one statement, so processes sharing
+// the database cannot both claim a key.
+func (s *SQLiteStorage) SaveIfAbsent(
+	ctx context.Context,
+	key string,
+	value interface{},
+	ttl *time.Duration,
+) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return false, &Error{
+			Op:      "SaveIfAbsent",
+			Key:     key,
+			Message: fmt.Sprintf("failed to marshal value: %v", err),
+			Code:    "MARSHAL_ERROR",
+		}
+	}
+	now := time.Now()
+	var expiresAt *time.Time
+	if ttl == nil {
+		ttl = s.options.DefaultTTL
+	}
+	if ttl != nil {
+		exp := now.Add(*ttl)
+		expiresAt = &exp
+	}
+
+	// An expired entry that cleanup has not removed yet is replaced.
+	query := `
+	INSERT INTO storage_entries
+	(key, value, created_at, expires_at, size_bytes, metadata, created_at_idx, expires_at_idx)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(key) DO UPDATE SET
+		value = excluded.value, created_at = excluded.created_at, expires_at = excluded.expires_at,
+		size_bytes = excluded.size_bytes, metadata = excluded.metadata,
+		created_at_idx = excluded.created_at_idx, expires_at_idx = excluded.expires_at_idx
+	WHERE storage_entries.expires_at IS NOT NULL AND storage_entries.expires_at < ?
+	`
+	res, err := s.db.ExecContext(ctx, query,
+		key, valueBytes, now, expiresAt, int64(len(valueBytes)), nil, now, expiresAt, now)
+	if err != nil {
+		return false, &Error{
+			Op:      "SaveIfAbsent",
+			Key:     key,
+			Message: fmt.Sprintf("failed to save to database: %v", err),
+			Code:    "DATABASE_ERROR",
+		}
+	}
+	n, err := res.RowsAffected()
+	if err != nil || n == 0 {
+		return false, err
+	}
+
+	s.emitEvent(Event{
+		Type:      EventCreated,
+		Key:       key,
+		Timestamp: now,
+		Data:      value,
+	})
+	return true, nil
+}
+
 // Load retrieves a value by key
 func (s *SQLiteStorage) Load(ctx context.Context, key string) (interface{}, error) {
 	s.mu.RLock()
@@ -467,17 +534,17 @@ func (s *SQLiteStorage) Close(_ context.Context) error {
 
 // AddEventHandler adds an event handler
 func (s *SQLiteStorage) AddEventHandler(handler EventHandler) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
 	s.eventHandlers = append(s.eventHandlers, handler)
 }
 
 // emitEvent emits a storage event to all handlers
 func (s *SQLiteStorage) emitEvent(event Event) {
-	s.mu.RLock()
+	s.handlersMu.RLock()
 	handlers := make([]EventHandler, len(s.eventHandlers))
 	copy(handlers, s.eventHandlers)
-	s.mu.RUnlock()
+	s.handlersMu.RUnlock()
 
 	for _, handler := range handlers {
 		go func(h EventHandler, e Event) {
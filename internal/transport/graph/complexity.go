@@ -0,0 +1,99 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// listCostFactor is the assumed size of a list field when costing a query:
+// its selections count this many times.
+const listCostFactor = 10
+
+// cost walks an operation before it runs. Every field costs 1, and a list
+// field's selections cost listCostFactor times theirs, so nesting lists
+// grows the cost geometrically. Fragment cycles are not followed (validation
+// rejects them anyway).
+type cost struct {
+	fragments map[string]*ast.FragmentDefinition
+	visiting  map[string]bool
+	// budget is the number of fields left to visit. Each field costs at
+	// least 1, so once it runs out the query is over the limit, and fragments
+	// spread many times over cannot make the walk itself expensive.
+	budget int
+}
+
+// measure returns the cost and the field depth of op, or ok false once the
+// cost is known to exceed limit.
+func measure(schema *graphql.Schema, doc *ast.Document, op *ast.OperationDefinition, limit int) (total, depth int, ok bool) {
+	c := &cost{
+		fragments: make(map[string]*ast.FragmentDefinition),
+		visiting:  make(map[string]bool),
+		budget:    limit,
+	}
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok && f.Name != nil {
+			c.fragments[f.Name.Value] = f
+		}
+	}
+	var root graphql.Type = schema.QueryType()
+	if op.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+	total, depth = c.selections(op.SelectionSet, root, 0)
+	return total, depth, c.budget >= 0 && total <= limit
+}
+
+func (c *cost) selections(set *ast.SelectionSet, parent graphql.Type, depth int) (total, maxDepth int) {
+	maxDepth = depth
+	if set == nil {
+		return 0, maxDepth
+	}
+	for _, sel := range set.Selections {
+		if c.budget < 0 {
+			break
+		}
+		var n, d int
+		switch sel := sel.(type) {
+		case *ast.Field:
+			n, d = c.field(sel, parent, depth+1)
+		case *ast.InlineFragment:
+			// The schema has no interfaces or unions: a fragment is on parent.
+			n, d = c.selections(sel.SelectionSet, parent, depth)
+		case *ast.FragmentSpread:
+			f := c.fragments[sel.Name.Value]
+			if f == nil || c.visiting[sel.Name.Value] {
+				continue
+			}
+			c.visiting[sel.Name.Value] = true
+			n, d = c.selections(f.SelectionSet, parent, depth)
+			delete(c.visiting, sel.Name.Value)
+		}
+		total += n
+		maxDepth = max(maxDepth, d)
+	}
+	return total, maxDepth
+}
+
+func (c *cost) field(f *ast.Field, parent graphql.Type, depth int) (int, int) {
+	c.budget--
+	var t graphql.Type
+	if obj, ok := parent.(*graphql.Object); ok {
+		if def, ok := obj.Fields()[f.Name.Value]; ok {
+			t = def.Type
+		}
+	}
+	factor := 1
+	for done := false; !done; {
+		switch w := t.(type) {
+		case *graphql.NonNull:
+			t = w.OfType
+		case *graphql.List:
+			factor *= listCostFactor
+			t = w.OfType
+		default:
+			done = true
+		}
+	}
+	n, d := c.selections(f.SelectionSet, t, depth)
+	return 1 + factor*n, d
+}
@@ -0,0 +1,53 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/middleware"
+)
+
+// Error is a GraphQL error carrying the same code, status and field errors
+// as the REST problem response for the failure.
+type Error struct {
+	Message string
+	Code    string
+	Status  int
+	Fields  []domain.FieldError
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// Extensions implements gqlerrors.ExtendedError.
+func (e *Error) Extensions() map[string]any {
+	ext := map[string]any{"code": e.Code, "status": e.Status}
+	if len(e.Fields) > 0 {
+		ext["errors"] = e.Fields
+	}
+	return ext
+}
+
+// resolverErr maps err as middleware.WriteProblem would. Unexpected errors
+// are logged with the request ID and reported as a generic internal error.
+func resolverErr(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	var ge *Error
+	if errors.As(err, &ge) {
+		return ge
+	}
+	status, p := middleware.ProblemFor(err)
+	if status == http.StatusInternalServerError {
+		slog.Error("graphql resolver failed", "err", err, "request_id", middleware.GetRequestID(ctx))
+	}
+	return &Error{Message: p.Detail, Code: p.Code, Status: status, Fields: p.Errors}
+}
+
+// requestError is an error rejecting the whole request before execution.
+func requestError(code, message string) *Error {
+	return &Error{Message: message, Code: code, Status: http.StatusBadRequest}
+}
@@ -0,0 +1,170 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mdco1990/webapp/internal/db"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/repository"
+	"github.com/mdco1990/webapp/internal/service"
+)
+
+type response struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func setupHandler(t *testing.T, limits Limits) (*repository.Repository, func(query string) (int, response)) {
+	t.Helper()
+	database, err := db.Open("sqlite", ":memory:", "")
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+	database.SetMaxOpenConns(1)
+	if err := db.Migrate(database); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+	repo := repository.New(database)
+	h := NewHandler(repo, service.New(repo), func(context.Context) int64 { return 1 },
+		func(context.Context, string) bool { return true }, limits)
+
+	return repo, func(query string) (int, response) {
+		body, _ := json.Marshal(map[string]string{"query": query})
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body))))
+		var resp response
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Expected a JSON response, got %s", w.Body.String())
+		}
+		return w.Code, resp
+	}
+}
+
+func TestLoaderBatchesKeys(t *testing.T) {
+	calls := 0
+	l := newLoader(func(_ context.Context, keys []int) (map[int]string, error) {
+		calls++
+		out := make(map[int]string)
+		for _, k := range keys {
+			if k != 3 {
+				out[k] = strings.Repeat("x", k)
+			}
+		}
+		return out, nil
+	})
+	ctx := context.Background()
+	thunks := []func() (string, error){l.load(ctx, 1), l.load(ctx, 2), l.load(ctx, 3), l.load(ctx, 2)}
+	for i, want := range []string{"x", "xx", "", "xx"} {
+		if got, err := thunks[i](); err != nil || got != want {
+			t.Errorf("Expected %q, got %q (%v)", want, got, err)
+		}
+	}
+	if got, _ := l.load(ctx, 2)(); got != "xx" || calls != 1 {
+		t.Errorf("Expected one fetch for all keys, got %d", calls)
+	}
+}
+
+func TestQueryMonths(t *testing.T) {
+	repo, query := setupHandler(t, DefaultLimits)
+	ctx := context.Background()
+	for m := 1; m <= 3; m++ {
+		if _, err := repo.CreateIncomeSource(ctx, 1, domain.CreateIncomeSourceRequest{
+			Name: "Salary", Year: 2024, Month: m, AmountCents: domain.Money(100000 * m),
+		}); err != nil {
+			t.Fatalf("CreateIncomeSource failed: %v", err)
+		}
+	}
+
+	code, resp := query(`{ months(from: "2024-02", to: "2024-04") {
+		month income_sources { name amount_cents } manual_budget { version items { name } }
+		totals { total_income_cents remaining_cents }
+	} }`)
+	if code != http.StatusOK || len(resp.Errors) != 0 {
+		t.Fatalf("Expected 200 without errors, got %d %+v", code, resp.Errors)
+	}
+	var months []struct {
+		Month         int `json:"month"`
+		IncomeSources []struct {
+			AmountCents int64 `json:"amount_cents"`
+		} `json:"income_sources"`
+		Totals struct {
+			TotalIncomeCents int64 `json:"total_income_cents"`
+		} `json:"totals"`
+	}
+	if err := json.Unmarshal(resp.Data["months"], &months); err != nil || len(months) != 3 {
+		t.Fatalf("Expected 3 months, got %s", resp.Data["months"])
+	}
+	if months[0].Month != 2 || len(months[0].IncomeSources) != 1 || months[0].Totals.TotalIncomeCents != 200000 {
+		t.Errorf("Expected February's salary, got %+v", months[0])
+	}
+	if len(months[2].IncomeSources) != 0 {
+		t.Errorf("Expected no income in April, got %+v", months[2])
+	}
+}
+
+func TestQueryLimits(t *testing.T) {
+	_, query := setupHandler(t, Limits{MaxDepth: 3, MaxComplexity: 100})
+
+	// 1 + 10 * (1 + 10 * 2) = 211
+	code, resp := query(`{ months(from: "2024-01", to: "2024-12") { income_sources { id name } } }`)
+	if code != http.StatusBadRequest || len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != "query_too_complex" {
+		t.Errorf("Expected 400 query_too_complex, got %d %+v", code, resp.Errors)
+	}
+	code, resp = query(`{ month(year: 2024, month: 1) { manual_budget { items { name } } } }`)
+	if code != http.StatusBadRequest || len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != "query_too_deep" {
+		t.Errorf("Expected 400 query_too_deep, got %d %+v", code, resp.Errors)
+	}
+	code, resp = query(`{ month(year: 2024, month: 1) { nope } }`)
+	if code != http.StatusBadRequest || len(resp.Errors) == 0 {
+		t.Errorf("Expected 400 for an unknown field, got %d %+v", code, resp.Errors)
+	}
+}
+
+func TestMutations(t *testing.T) {
+	_, query := setupHandler(t, DefaultLimits)
+
+	code, resp := query(`mutation { create_budget_source(input: {
+		name: "Rent", year: 2024, month: 3, amount_cents: 9000000000
+	}) { id version amount_cents } }`)
+	if code != http.StatusOK || len(resp.Errors) != 0 {
+		t.Fatalf("Expected 200 without errors, got %d %+v", code, resp.Errors)
+	}
+	var created struct {
+		ID          string `json:"id"`
+		Version     int64  `json:"version"`
+		AmountCents int64  `json:"amount_cents"`
+	}
+	_ = json.Unmarshal(resp.Data["create_budget_source"], &created)
+	if created.ID == "" || created.Version != 1 || created.AmountCents != 9000000000 {
+		t.Fatalf("Expected a new source at version 1, got %+v", created)
+	}
+
+	_, resp = query(`mutation { update_budget_source(id: "` + created.ID + `", version: 4,
+		input: {name: "Rent", amount_cents: 1}) { version } }`)
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != "version_mismatch" {
+		t.Errorf("Expected version_mismatch, got %+v", resp.Errors)
+	}
+	_, resp = query(`mutation { create_expense(input: {
+		year: 2024, month: 13, description: "Coffee", amount_cents: 350
+	}) { id } }`)
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != "validation_failed" {
+		t.Errorf("Expected validation_failed, got %+v", resp.Errors)
+	}
+	_, resp = query(`mutation { delete_budget_source(id: "0") }`)
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["status"] != float64(http.StatusBadRequest) {
+		t.Errorf("Expected a 400 invalid id, got %+v", resp.Errors)
+	}
+	_, resp = query(`mutation { delete_budget_source(id: "` + created.ID + `", version: 1) }`)
+	if len(resp.Errors) != 0 || string(resp.Data["delete_budget_source"]) != "true" {
+		t.Errorf("Expected the source deleted, got %+v", resp.Errors)
+	}
+}
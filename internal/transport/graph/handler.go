@@ -0,0 +1,220 @@
+// Package graph serves the API over GraphQL. It reads and writes through the
+// same repository, service and validation as the REST handlers, and maps
+// errors to the same problem codes.
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/repository"
+	"github.com/mdco1990/webapp/internal/security"
+	"github.com/mdco1990/webapp/internal/service"
+)
+
+// Limits bound a query before it runs.
+type Limits struct {
+	MaxDepth      int // nesting depth of fields
+	MaxComplexity int // see cost
+}
+
+// DefaultLimits admit a full-year dashboard (a year of months with every list)
+// and reject queries nesting lists beyond what the schema needs.
+var DefaultLimits = Limits{MaxDepth: 8, MaxComplexity: 5000}
+
+var schema = func() graphql.Schema {
+	s, err := graphql.NewSchema(graphql.SchemaConfig{Query: queryType, Mutation: mutationType})
+	if err != nil {
+		panic(fmt.Sprintf("graph: invalid schema: %v", err))
+	}
+	return s
+}()
+
+// handler serves POST requests with a JSON {query, operationName, variables}
+// body.
+type handler struct {
+	repo     *repository.Repository
+	svc      *service.Service
+	userID   func(context.Context) int64
+	hasScope func(context.Context, string) bool
+	limits   Limits
+}
+
+// NewHandler returns the GraphQL endpoint. userID reads the authenticated
+// user from the request context, and hasScope whether its credentials allow
+// an access token scope; authentication itself is left to the surrounding
+// middleware.
+func NewHandler(
+	repo *repository.Repository,
+	svc *service.Service,
+	userID func(context.Context) int64,
+	hasScope func(context.Context, string) bool,
+	limits Limits,
+) http.Handler {
+	return &handler{repo: repo, svc: svc, userID: userID, hasScope: hasScope, limits: limits}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Query         string         `json:"query"`
+		OperationName string         `json:"operationName"`
+		Variables     map[string]any `json:"variables"`
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, security.MaxRequestBodySize)
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeErrors(w, http.StatusBadRequest, requestError("invalid_request", "invalid request body"))
+		return
+	}
+	if body.Query == "" {
+		writeErrors(w, http.StatusBadRequest, requestError("invalid_request", "query is required"))
+		return
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: body.Query})
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, err)
+		return
+	}
+	op := operation(doc, body.OperationName)
+	if op == nil {
+		writeErrors(w, http.StatusBadRequest, requestError("invalid_request", "unknown operation"))
+		return
+	}
+	_, depth, ok := measure(&schema, doc, op, h.limits.MaxComplexity)
+	if !ok {
+		writeErrors(w, http.StatusBadRequest, requestError("query_too_complex",
+			fmt.Sprintf("query complexity exceeds %d", h.limits.MaxComplexity)))
+		return
+	}
+	if depth > h.limits.MaxDepth {
+		writeErrors(w, http.StatusBadRequest, requestError("query_too_deep",
+			fmt.Sprintf("query depth %d exceeds %d", depth, h.limits.MaxDepth)))
+		return
+	}
+	if v := graphql.ValidateDocument(&schema, doc, nil); !v.IsValid {
+		errs := make([]error, len(v.Errors))
+		for i, e := range v.Errors {
+			errs[i] = e
+		}
+		writeErrors(w, http.StatusBadRequest, errs...)
+		return
+	}
+	for _, scope := range requiredScopes(doc, op) {
+		if !h.hasScope(r.Context(), scope) {
+			writeErrors(w, http.StatusForbidden, &Error{
+				Message: "access token lacks the " + scope + " scope",
+				Code:    "insufficient_scope",
+				Status:  http.StatusForbidden,
+			})
+			return
+		}
+	}
+
+	userID := h.userID(r.Context())
+	ctx := withRequest(r.Context(), &request{
+		repo:    h.repo,
+		svc:     h.svc,
+		userID:  userID,
+		loaders: newLoaders(h.repo, userID),
+	})
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        schema,
+		AST:           doc,
+		OperationName: body.OperationName,
+		Args:          body.Variables,
+		Context:       ctx,
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+// operation finds the operation to run: the one named, or the only one.
+func operation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if found != nil {
+				return nil
+			}
+			found = op
+		} else if op.Name != nil && op.Name.Value == name {
+			return op
+		}
+	}
+	return found
+}
+
+// fieldScopes maps the top-level fields an access token needs a particular
+// scope for to that scope, as for the matching REST routes. Other query
+// fields need domain.ScopeReadBudget and other mutations domain.ScopeAdmin.
+var fieldScopes = map[string]string{
+	"users":          domain.ScopeAdmin,
+	"create_expense": domain.ScopeWriteExpenses,
+	"delete_expense": domain.ScopeWriteExpenses,
+}
+
+// requiredScopes returns the access token scopes op needs, one per
+// top-level field. op must have passed validation.
+func requiredScopes(doc *ast.Document, op *ast.OperationDefinition) []string {
+	fallback := domain.ScopeReadBudget
+	if op.Operation == ast.OperationTypeMutation {
+		fallback = domain.ScopeAdmin
+	}
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok && f.Name != nil {
+			fragments[f.Name.Value] = f
+		}
+	}
+	var scopes []string
+	var walk func(set *ast.SelectionSet)
+	walk = func(set *ast.SelectionSet) {
+		if set == nil {
+			return
+		}
+		for _, sel := range set.Selections {
+			switch sel := sel.(type) {
+			case *ast.Field:
+				scope, ok := fieldScopes[sel.Name.Value]
+				if !ok {
+					scope = fallback
+				}
+				scopes = append(scopes, scope)
+			case *ast.InlineFragment:
+				walk(sel.SelectionSet)
+			case *ast.FragmentSpread:
+				if f := fragments[sel.Name.Value]; f != nil {
+					walk(f.SelectionSet)
+				}
+			}
+		}
+	}
+	walk(op.SelectionSet)
+	return scopes
+}
+
+// writeErrors answers a request rejected before execution.
+func writeErrors(w http.ResponseWriter, status int, errs ...error) {
+	formatted := make([]gqlerrors.FormattedError, len(errs))
+	for i, err := range errs {
+		formatted[i] = gqlerrors.FormatError(err)
+		if ge, ok := err.(*Error); ok {
+			formatted[i].Extensions = ge.Extensions()
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"errors": formatted})
+}
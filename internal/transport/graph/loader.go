@@ -0,0 +1,129 @@
+package graph
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/repository"
+	"github.com/mdco1990/webapp/internal/service"
+)
+
+// loader batches loads by key. Resolvers call load and return its thunk; the
+// executor resolves thunks breadth-first, so every key requested at one level
+// of the query is queued before the first thunk runs and fetches them all in
+// a single call. Results are cached for the rest of the request.
+type loader[K comparable, V any] struct {
+	fetch func(context.Context, []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	pending []K
+	done    map[K]V
+	errs    map[K]error
+}
+
+func newLoader[K comparable, V any](fetch func(context.Context, []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{fetch: fetch, done: make(map[K]V), errs: make(map[K]error)}
+}
+
+// load queues key and returns a thunk yielding its value (the zero value if
+// the fetch returned none).
+func (l *loader[K, V]) load(ctx context.Context, key K) func() (V, error) {
+	l.mu.Lock()
+	_, ok := l.done[key]
+	if _, failed := l.errs[key]; !ok && !failed {
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if err, failed := l.errs[key]; failed {
+			var zero V
+			return zero, err
+		}
+		if v, ok := l.done[key]; ok {
+			return v, nil
+		}
+		l.flush(ctx)
+		if err := l.errs[key]; err != nil {
+			var zero V
+			return zero, err
+		}
+		return l.done[key], nil
+	}
+}
+
+// flush fetches every pending key. l.mu must be held.
+func (l *loader[K, V]) flush(ctx context.Context) {
+	keys := make([]K, 0, len(l.pending))
+	seen := make(map[K]bool, len(l.pending))
+	for _, k := range l.pending {
+		_, ok := l.done[k]
+		if !ok && !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	l.pending = l.pending[:0]
+	if len(keys) == 0 {
+		return
+	}
+	values, err := l.fetch(ctx, keys)
+	for _, k := range keys {
+		if err != nil {
+			l.errs[k] = err
+			continue
+		}
+		l.done[k] = values[k]
+	}
+}
+
+// loaders are the per-request loaders for one user's months.
+type loaders struct {
+	incomeSources *loader[domain.YearMonth, []domain.IncomeSource]
+	budgetSources *loader[domain.YearMonth, []domain.BudgetSource]
+	expenses      *loader[domain.YearMonth, []domain.Expense]
+	manualBudgets *loader[domain.YearMonth, *domain.ManualBudget]
+}
+
+func newLoaders(repo *repository.Repository, userID int64) *loaders {
+	return &loaders{
+		incomeSources: newLoader(func(ctx context.Context, months []domain.YearMonth) (
+			map[domain.YearMonth][]domain.IncomeSource, error,
+		) {
+			return repo.ListIncomeSourcesForMonths(ctx, userID, months)
+		}),
+		budgetSources: newLoader(func(ctx context.Context, months []domain.YearMonth) (
+			map[domain.YearMonth][]domain.BudgetSource, error,
+		) {
+			return repo.ListBudgetSourcesForMonths(ctx, userID, months)
+		}),
+		expenses: newLoader(repo.ListExpensesForMonths),
+		manualBudgets: newLoader(func(ctx context.Context, months []domain.YearMonth) (
+			map[domain.YearMonth]*domain.ManualBudget, error,
+		) {
+			return repo.GetManualBudgetsForMonths(ctx, userID, months)
+		}),
+	}
+}
+
+type requestKey struct{}
+
+// request is the per-request state resolvers read from the context.
+type request struct {
+	repo    *repository.Repository
+	svc     *service.Service
+	userID  int64
+	loaders *loaders
+}
+
+func withRequest(ctx context.Context, req *request) context.Context {
+	return context.WithValue(ctx, requestKey{}, req)
+}
+
+func requestFrom(ctx context.Context) *request {
+	req, _ := ctx.Value(requestKey{}).(*request)
+	return req
+}
@@ -0,0 +1,449 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/security"
+)
+
+// MaxMonths bounds the range a months query may span.
+const MaxMonths = 36
+
+// resolve adapts a root resolver: it hands over the request state and maps
+// errors to Error.
+func resolve(fn func(p graphql.ResolveParams, req *request) (any, error)) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		v, err := fn(p, requestFrom(p.Context))
+		if err != nil {
+			return nil, resolverErr(p.Context, err)
+		}
+		return v, nil
+	}
+}
+
+var queryType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Query",
+	Fields: graphql.Fields{
+		"me": {
+			Type: graphql.NewNonNull(userType),
+			Resolve: resolve(func(p graphql.ResolveParams, req *request) (any, error) {
+				u, _, err := req.repo.GetUserByID(p.Context, req.userID)
+				if err != nil {
+					return nil, err
+				}
+				return *u, nil
+			}),
+		},
+		"users": {
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(userType))),
+			Description: "All users, optionally by status. Admins only.",
+			Args: graphql.FieldConfigArgument{
+				"status": {Type: graphql.String},
+			},
+			Resolve: resolve(func(p graphql.ResolveParams, req *request) (any, error) {
+				isAdmin, err := req.repo.IsUserAdmin(p.Context, req.userID)
+				if err != nil {
+					return nil, err
+				}
+				if !isAdmin {
+					return nil, domain.Forbidden("forbidden")
+				}
+				status, _ := p.Args["status"].(string)
+				if status != "" {
+					if status, err = security.ValidateUserStatus(status); err != nil {
+						return nil, err
+					}
+				}
+				users, err := req.repo.ListUsers(p.Context, status)
+				return orEmpty(users), err
+			}),
+		},
+		"month": {
+			Type: graphql.NewNonNull(monthType),
+			Args: graphql.FieldConfigArgument{
+				"year":  {Type: graphql.NewNonNull(graphql.Int)},
+				"month": {Type: graphql.NewNonNull(graphql.Int)},
+			},
+			Resolve: resolve(func(p graphql.ResolveParams, _ *request) (any, error) {
+				ym := domain.YearMonth{Year: p.Args["year"].(int), Month: p.Args["month"].(int)}
+				if err := security.ValidateYearMonth(ym); err != nil {
+					return nil, err
+				}
+				return ym, nil
+			}),
+		},
+		"months": {
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(monthType))),
+			Description: fmt.Sprintf("Each month from from to to (YYYY-MM, inclusive), at most %d.", MaxMonths),
+			Args: graphql.FieldConfigArgument{
+				"from": {Type: graphql.NewNonNull(graphql.String)},
+				"to":   {Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: resolve(func(p graphql.ResolveParams, _ *request) (any, error) {
+				from, err := parseMonth(p.Args["from"].(string), "from")
+				if err != nil {
+					return nil, err
+				}
+				to, err := parseMonth(p.Args["to"].(string), "to")
+				if err != nil {
+					return nil, err
+				}
+				n := (to.Year-from.Year)*12 + to.Month - from.Month + 1
+				if n < 1 || n > MaxMonths {
+					return nil, domain.Invalid(
+						fmt.Sprintf("to must be from 0 to %d months after from", MaxMonths-1),
+						domain.FieldError{Field: "to", Message: "out of range"})
+				}
+				months := make([]domain.YearMonth, n)
+				for i := range months {
+					m := from.Month - 1 + i
+					months[i] = domain.YearMonth{Year: from.Year + m/12, Month: m%12 + 1}
+				}
+				return months, nil
+			}),
+		},
+		"income_source": {
+			Type: incomeSourceType,
+			Args: graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+			Resolve: resolve(func(p graphql.ResolveParams, req *request) (any, error) {
+				id, err := idArg(p.Args, "id")
+				if err != nil {
+					return nil, err
+				}
+				s, err := req.repo.GetIncomeSource(p.Context, id, req.userID)
+				if err != nil {
+					return nil, err
+				}
+				return *s, nil
+			}),
+		},
+		"budget_source": {
+			Type: budgetSourceType,
+			Args: graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+			Resolve: resolve(func(p graphql.ResolveParams, req *request) (any, error) {
+				id, err := idArg(p.Args, "id")
+				if err != nil {
+					return nil, err
+				}
+				s, err := req.repo.GetBudgetSource(p.Context, id, req.userID)
+				if err != nil {
+					return nil, err
+				}
+				return *s, nil
+			}),
+		},
+	},
+})
+
+var createSourceInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "CreateSourceInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"name":         {Type: graphql.NewNonNull(graphql.String)},
+		"year":         {Type: graphql.NewNonNull(graphql.Int)},
+		"month":        {Type: graphql.NewNonNull(graphql.Int)},
+		"amount_cents": {Type: graphql.NewNonNull(centsScalar)},
+		"day_of_month": {Type: graphql.Int},
+	},
+})
+
+var updateSourceInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "UpdateSourceInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"name":         {Type: graphql.NewNonNull(graphql.String)},
+		"amount_cents": {Type: graphql.NewNonNull(centsScalar)},
+		"day_of_month": {Type: graphql.Int},
+	},
+})
+
+var createExpenseInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "CreateExpenseInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"year":         {Type: graphql.NewNonNull(graphql.Int)},
+		"month":        {Type: graphql.NewNonNull(graphql.Int)},
+		"category":     {Type: graphql.String},
+		"description":  {Type: graphql.NewNonNull(graphql.String)},
+		"amount_cents": {Type: graphql.NewNonNull(centsScalar)},
+	},
+})
+
+var manualBudgetItemInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "ManualBudgetItemInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"name":         {Type: graphql.NewNonNull(graphql.String)},
+		"amount_cents": {Type: graphql.NewNonNull(centsScalar)},
+	},
+})
+
+var saveManualBudgetInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "SaveManualBudgetInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"year":              {Type: graphql.NewNonNull(graphql.Int)},
+		"month":             {Type: graphql.NewNonNull(graphql.Int)},
+		"bank_amount_cents": {Type: graphql.NewNonNull(centsScalar)},
+		"items":             {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(manualBudgetItemInput)))},
+	},
+})
+
+// Mutations mirror the REST writes: the same validation, the same optional
+// version checks (the If-Match of REST), and the same error codes.
+var mutationType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Mutation",
+	Fields: graphql.Fields{
+		"create_income_source": {
+			Type: graphql.NewNonNull(incomeSourceType),
+			Args: graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(createSourceInput)}},
+			Resolve: resolve(func(p graphql.ResolveParams, req *request) (any, error) {
+				in := p.Args["input"].(map[string]any)
+				v, err := security.ValidateCreateIncomeSourceRequest(domain.CreateIncomeSourceRequest{
+					Name: str(in, "name"), Year: in["year"].(int), Month: in["month"].(int),
+					AmountCents: in["amount_cents"].(domain.Money), DayOfMonth: optInt(in, "day_of_month"),
+				})
+				if err != nil {
+					return nil, err
+				}
+				s, err := req.repo.CreateIncomeSource(p.Context, req.userID, *v)
+				if err != nil {
+					return nil, err
+				}
+				return *s, nil
+			}),
+		},
+		"update_income_source": {
+			Type: graphql.NewNonNull(incomeSourceType),
+			Args: updateSourceArgs,
+			Resolve: resolve(func(p graphql.ResolveParams, req *request) (any, error) {
+				id, version, in, err := updateSourceRequest(p.Args)
+				if err != nil {
+					return nil, err
+				}
+				if _, err := req.repo.UpdateIncomeSource(p.Context, id, req.userID, version, in); err != nil {
+					return nil, err
+				}
+				s, err := req.repo.GetIncomeSource(p.Context, id, req.userID)
+				if err != nil {
+					return nil, err
+				}
+				return *s, nil
+			}),
+		},
+		"delete_income_source": {
+			Type: graphql.NewNonNull(graphql.Boolean),
+			Args: deleteSourceArgs,
+			Resolve: resolve(func(p graphql.ResolveParams, req *request) (any, error) {
+				id, err := idArg(p.Args, "id")
+				if err != nil {
+					return nil, err
+				}
+				return true, req.repo.DeleteIncomeSource(p.Context, id, req.userID, versionArg(p.Args))
+			}),
+		},
+		"create_budget_source": {
+			Type: graphql.NewNonNull(budgetSourceType),
+			Args: graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(createSourceInput)}},
+			Resolve: resolve(func(p graphql.ResolveParams, req *request) (any, error) {
+				in := p.Args["input"].(map[string]any)
+				v, err := security.ValidateCreateBudgetSourceRequest(domain.CreateBudgetSourceRequest{
+					Name: str(in, "name"), Year: in["year"].(int), Month: in["month"].(int),
+					AmountCents: in["amount_cents"].(domain.Money), DayOfMonth: optInt(in, "day_of_month"),
+				})
+				if err != nil {
+					return nil, err
+				}
+				s, err := req.repo.CreateBudgetSource(p.Context, req.userID, *v)
+				if err != nil {
+					return nil, err
+				}
+				return *s, nil
+			}),
+		},
+		"update_budget_source": {
+			Type: graphql.NewNonNull(budgetSourceType),
+			Args: updateSourceArgs,
+			Resolve: resolve(func(p graphql.ResolveParams, req *request) (any, error) {
+				id, version, in, err := updateSourceRequest(p.Args)
+				if err != nil {
+					return nil, err
+				}
+				if _, err := req.repo.UpdateBudgetSource(p.Context, id, req.userID, version, in); err != nil {
+					return nil, err
+				}
+				s, err := req.repo.GetBudgetSource(p.Context, id, req.userID)
+				if err != nil {
+					return nil, err
+				}
+				return *s, nil
+			}),
+		},
+		"delete_budget_source": {
+			Type: graphql.NewNonNull(graphql.Boolean),
+			Args: deleteSourceArgs,
+			Resolve: resolve(func(p graphql.ResolveParams, req *request) (any, error) {
+				id, err := idArg(p.Args, "id")
+				if err != nil {
+					return nil, err
+				}
+				return true, req.repo.DeleteBudgetSource(p.Context, id, req.userID, versionArg(p.Args))
+			}),
+		},
+		"create_expense": {
+			Type: graphql.NewNonNull(expenseType),
+			Args: graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(createExpenseInput)}},
+			Resolve: resolve(func(p graphql.ResolveParams, req *request) (any, error) {
+				in := p.Args["input"].(map[string]any)
+				e, err := security.ValidateExpense(&domain.Expense{
+					YearMonth:   domain.YearMonth{Year: in["year"].(int), Month: in["month"].(int)},
+					Category:    str(in, "category"),
+					Description: str(in, "description"),
+					AmountCents: in["amount_cents"].(domain.Money),
+				})
+				if err != nil {
+					return nil, err
+				}
+				if e.ID, err = req.svc.AddExpense(p.Context, e); err != nil {
+					return nil, err
+				}
+				e.CreatedAt = time.Now().UTC()
+				return *e, nil
+			}),
+		},
+		"delete_expense": {
+			Type: graphql.NewNonNull(graphql.Boolean),
+			Args: graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+			Resolve: resolve(func(p graphql.ResolveParams, req *request) (any, error) {
+				id, err := idArg(p.Args, "id")
+				if err != nil {
+					return nil, err
+				}
+				return true, req.svc.DeleteExpense(p.Context, id)
+			}),
+		},
+		"set_salary": {
+			Type: graphql.NewNonNull(graphql.Boolean),
+			Args: amountArgs,
+			Resolve: resolve(func(p graphql.ResolveParams, req *request) (any, error) {
+				ym := domain.YearMonth{Year: p.Args["year"].(int), Month: p.Args["month"].(int)}
+				return true, req.svc.SetSalary(p.Context, ym, p.Args["amount_cents"].(domain.Money))
+			}),
+		},
+		"set_budget": {
+			Type: graphql.NewNonNull(graphql.Boolean),
+			Args: amountArgs,
+			Resolve: resolve(func(p graphql.ResolveParams, req *request) (any, error) {
+				ym := domain.YearMonth{Year: p.Args["year"].(int), Month: p.Args["month"].(int)}
+				return true, req.svc.SetBudget(p.Context, ym, p.Args["amount_cents"].(domain.Money))
+			}),
+		},
+		"save_manual_budget": {
+			Type: graphql.NewNonNull(manualBudgetType),
+			Args: graphql.FieldConfigArgument{
+				"version": {Type: graphql.Int},
+				"input":   {Type: graphql.NewNonNull(saveManualBudgetInput)},
+			},
+			Resolve: resolve(func(p graphql.ResolveParams, req *request) (any, error) {
+				in := p.Args["input"].(map[string]any)
+				mb := domain.ManualBudget{
+					YearMonth:       domain.YearMonth{Year: in["year"].(int), Month: in["month"].(int)},
+					BankAmountCents: in["bank_amount_cents"].(domain.Money),
+				}
+				for _, it := range in["items"].([]any) {
+					item := it.(map[string]any)
+					mb.Items = append(mb.Items, domain.ManualBudgetItem{
+						Name: str(item, "name"), AmountCents: item["amount_cents"].(domain.Money),
+					})
+				}
+				if err := security.ValidateManualBudget(mb); err != nil {
+					return nil, err
+				}
+				items, err := security.ValidateManualBudgetItems(mb.Items)
+				if err != nil {
+					return nil, err
+				}
+				_, err = req.repo.UpsertManualBudget(p.Context, req.userID, mb.YearMonth,
+					versionArg(p.Args), mb.BankAmountCents, items)
+				if err != nil {
+					return nil, err
+				}
+				return req.repo.GetManualBudget(p.Context, req.userID, mb.YearMonth)
+			}),
+		},
+	},
+})
+
+var updateSourceArgs = graphql.FieldConfigArgument{
+	"id":      {Type: graphql.NewNonNull(graphql.ID)},
+	"version": {Type: graphql.Int, Description: "Fail with version_mismatch unless this is the current version."},
+	"input":   {Type: graphql.NewNonNull(updateSourceInput)},
+}
+
+var deleteSourceArgs = graphql.FieldConfigArgument{
+	"id":      {Type: graphql.NewNonNull(graphql.ID)},
+	"version": {Type: graphql.Int, Description: "Fail with version_mismatch unless this is the current version."},
+}
+
+var amountArgs = graphql.FieldConfigArgument{
+	"year":         {Type: graphql.NewNonNull(graphql.Int)},
+	"month":        {Type: graphql.NewNonNull(graphql.Int)},
+	"amount_cents": {Type: graphql.NewNonNull(centsScalar)},
+}
+
+// updateSourceRequest reads and validates the update_*_source arguments.
+func updateSourceRequest(args map[string]any) (int64, int64, domain.UpdateSourceRequest, error) {
+	id, err := idArg(args, "id")
+	if err != nil {
+		return 0, 0, domain.UpdateSourceRequest{}, err
+	}
+	in := args["input"].(map[string]any)
+	v, err := security.ValidateUpdateSourceRequest(domain.UpdateSourceRequest{
+		Name: str(in, "name"), AmountCents: in["amount_cents"].(domain.Money), DayOfMonth: optInt(in, "day_of_month"),
+	})
+	if err != nil {
+		return 0, 0, domain.UpdateSourceRequest{}, err
+	}
+	return id, versionArg(args), *v, nil
+}
+
+// idArg parses an ID argument; IDs are positive integers.
+func idArg(args map[string]any, name string) (int64, error) {
+	s, _ := args[name].(string)
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, domain.Invalid("invalid id", domain.FieldError{Field: name, Message: "must be a positive integer"})
+	}
+	return id, nil
+}
+
+// versionArg returns the optional version argument, 0 (unconditional) if absent.
+func versionArg(args map[string]any) int64 {
+	v, _ := args["version"].(int)
+	return int64(v)
+}
+
+// parseMonth parses a YYYY-MM argument.
+func parseMonth(s, field string) (domain.YearMonth, error) {
+	t, err := time.Parse("2006-01", s)
+	if err != nil {
+		return domain.YearMonth{}, domain.Invalid(field+" must be YYYY-MM",
+			domain.FieldError{Field: field, Message: "must be YYYY-MM"})
+	}
+	ym := domain.YearMonth{Year: t.Year(), Month: int(t.Month())}
+	if err := security.ValidateYearMonth(ym); err != nil {
+		return domain.YearMonth{}, err
+	}
+	return ym, nil
+}
+
+func str(m map[string]any, k string) string {
+	s, _ := m[k].(string)
+	return s
+}
+
+func optInt(m map[string]any, k string) *int {
+	if n, ok := m[k].(int); ok {
+		return &n
+	}
+	return nil
+}
@@ -0,0 +1,283 @@
+package graph
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// Field names follow the REST JSON (snake_case), so clients can share types
+// between the two APIs.
+
+// centsScalar carries domain.Money; GraphQL's Int is only 32 bits.
+var centsScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Cents",
+	Description: "An amount in cents, as a 64-bit integer.",
+	Serialize: func(v any) any {
+		switch v := v.(type) {
+		case domain.Money:
+			return int64(v)
+		case int64:
+			return v
+		}
+		return nil
+	},
+	ParseValue: func(v any) any {
+		switch v := v.(type) {
+		case float64: // JSON variables
+			if v == math.Trunc(v) && math.Abs(v) <= 1<<53 {
+				return domain.Money(v)
+			}
+		case int:
+			return domain.Money(v)
+		case int64:
+			return domain.Money(v)
+		}
+		return nil
+	},
+	ParseLiteral: func(v ast.Value) any {
+		if iv, ok := v.(*ast.IntValue); ok {
+			if n, err := strconv.ParseInt(iv.Value, 10, 64); err == nil {
+				return domain.Money(n)
+			}
+		}
+		return nil
+	},
+})
+
+// prop is a field read from a source of type T.
+func prop[T any](t graphql.Output, get func(T) any) *graphql.Field {
+	return &graphql.Field{Type: t, Resolve: func(p graphql.ResolveParams) (any, error) {
+		src, ok := p.Source.(T)
+		if !ok {
+			return nil, nil
+		}
+		return get(src), nil
+	}}
+}
+
+var userType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "User",
+	Fields: graphql.Fields{
+		"id":         prop(graphql.NewNonNull(graphql.ID), func(u domain.User) any { return u.ID }),
+		"username":   prop(graphql.NewNonNull(graphql.String), func(u domain.User) any { return u.Username }),
+		"email":      prop(graphql.String, func(u domain.User) any { return u.Email }),
+		"created_at": prop(graphql.NewNonNull(graphql.DateTime), func(u domain.User) any { return u.CreatedAt }),
+		"last_login": prop(graphql.DateTime, func(u domain.User) any {
+			if u.LastLogin == nil {
+				return nil
+			}
+			return *u.LastLogin
+		}),
+		"is_admin": prop(graphql.NewNonNull(graphql.Boolean), func(u domain.User) any { return u.IsAdmin }),
+		"status":   prop(graphql.String, func(u domain.User) any { return u.Status }),
+	},
+})
+
+// sourceFields are the fields shared by IncomeSource and BudgetSource.
+func sourceFields[T any](get func(T) sourceView) graphql.Fields {
+	return graphql.Fields{
+		"id":           prop(graphql.NewNonNull(graphql.ID), func(s T) any { return get(s).id }),
+		"name":         prop(graphql.NewNonNull(graphql.String), func(s T) any { return get(s).name }),
+		"year":         prop(graphql.NewNonNull(graphql.Int), func(s T) any { return get(s).ym.Year }),
+		"month":        prop(graphql.NewNonNull(graphql.Int), func(s T) any { return get(s).ym.Month }),
+		"amount_cents": prop(graphql.NewNonNull(centsScalar), func(s T) any { return get(s).amount }),
+		"day_of_month": prop(graphql.Int, func(s T) any {
+			if d := get(s).day; d != nil {
+				return *d
+			}
+			return nil
+		}),
+		"version":    prop(graphql.NewNonNull(graphql.Int), func(s T) any { return get(s).version }),
+		"created_at": prop(graphql.NewNonNull(graphql.DateTime), func(s T) any { return get(s).createdAt }),
+		"updated_at": prop(graphql.NewNonNull(graphql.DateTime), func(s T) any { return get(s).updatedAt }),
+	}
+}
+
+// sourceView is the common shape of income and budget sources.
+type sourceView struct {
+	id                   int64
+	name                 string
+	ym                   domain.YearMonth
+	amount               domain.Money
+	day                  *int
+	version              int64
+	createdAt, updatedAt any
+}
+
+var incomeSourceType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "IncomeSource",
+	Fields: sourceFields(func(s domain.IncomeSource) sourceView {
+		return sourceView{s.ID, s.Name, s.YearMonth, s.AmountCents, s.DayOfMonth, s.Version, s.CreatedAt, s.UpdatedAt}
+	}),
+})
+
+var budgetSourceType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "BudgetSource",
+	Fields: sourceFields(func(s domain.BudgetSource) sourceView {
+		return sourceView{s.ID, s.Name, s.YearMonth, s.AmountCents, s.DayOfMonth, s.Version, s.CreatedAt, s.UpdatedAt}
+	}),
+})
+
+var expenseType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Expense",
+	Fields: graphql.Fields{
+		"id":           prop(graphql.NewNonNull(graphql.ID), func(e domain.Expense) any { return e.ID }),
+		"year":         prop(graphql.NewNonNull(graphql.Int), func(e domain.Expense) any { return e.Year }),
+		"month":        prop(graphql.NewNonNull(graphql.Int), func(e domain.Expense) any { return e.Month }),
+		"category":     prop(graphql.String, func(e domain.Expense) any { return e.Category }),
+		"description":  prop(graphql.NewNonNull(graphql.String), func(e domain.Expense) any { return e.Description }),
+		"amount_cents": prop(graphql.NewNonNull(centsScalar), func(e domain.Expense) any { return e.AmountCents }),
+		"created_at":   prop(graphql.NewNonNull(graphql.DateTime), func(e domain.Expense) any { return e.CreatedAt }),
+	},
+})
+
+var manualBudgetItemType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "ManualBudgetItem",
+	Fields: graphql.Fields{
+		"id":           prop(graphql.NewNonNull(graphql.ID), func(i domain.ManualBudgetItem) any { return i.ID }),
+		"name":         prop(graphql.NewNonNull(graphql.String), func(i domain.ManualBudgetItem) any { return i.Name }),
+		"amount_cents": prop(graphql.NewNonNull(centsScalar), func(i domain.ManualBudgetItem) any { return i.AmountCents }),
+	},
+})
+
+var manualBudgetType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ManualBudget",
+	Description: "A month's manual budget; version is 0 until the month is first saved.",
+	Fields: graphql.Fields{
+		"year":  prop(graphql.NewNonNull(graphql.Int), func(b *domain.ManualBudget) any { return b.Year }),
+		"month": prop(graphql.NewNonNull(graphql.Int), func(b *domain.ManualBudget) any { return b.Month }),
+		"bank_amount_cents": prop(graphql.NewNonNull(centsScalar),
+			func(b *domain.ManualBudget) any { return b.BankAmountCents }),
+		"items": prop(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(manualBudgetItemType))),
+			func(b *domain.ManualBudget) any { return b.Items }),
+		"version": prop(graphql.NewNonNull(graphql.Int), func(b *domain.ManualBudget) any { return b.Version }),
+	},
+})
+
+// monthTotals are the MonthlyData totals for a month.
+type monthTotals struct {
+	income, budget, expenses domain.Money
+}
+
+var monthTotalsType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "MonthTotals",
+	Fields: graphql.Fields{
+		"total_income_cents":   prop(graphql.NewNonNull(centsScalar), func(t monthTotals) any { return t.income }),
+		"total_budget_cents":   prop(graphql.NewNonNull(centsScalar), func(t monthTotals) any { return t.budget }),
+		"total_expenses_cents": prop(graphql.NewNonNull(centsScalar), func(t monthTotals) any { return t.expenses }),
+		"remaining_cents": prop(graphql.NewNonNull(centsScalar),
+			func(t monthTotals) any { return t.income - t.expenses }),
+	},
+})
+
+var monthNames = [...]string{
+	"", "January", "February", "March", "April", "May", "June",
+	"July", "August", "September", "October", "November", "December",
+}
+
+// monthType resolves a domain.YearMonth. Its lists are loaded through the
+// request's loaders, so a query over many months costs one query per table.
+var monthType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Month",
+	Fields: graphql.Fields{
+		"year":  prop(graphql.NewNonNull(graphql.Int), func(ym domain.YearMonth) any { return ym.Year }),
+		"month": prop(graphql.NewNonNull(graphql.Int), func(ym domain.YearMonth) any { return ym.Month }),
+		"month_name": prop(graphql.NewNonNull(graphql.String),
+			func(ym domain.YearMonth) any { return monthNames[ym.Month] }),
+		"income_sources": {
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(incomeSourceType))),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				load := requestFrom(p.Context).loaders.incomeSources.load(p.Context, p.Source.(domain.YearMonth))
+				return func() (any, error) {
+					v, err := load()
+					return orEmpty(v), resolverErr(p.Context, err)
+				}, nil
+			},
+		},
+		"budget_sources": {
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(budgetSourceType))),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				load := requestFrom(p.Context).loaders.budgetSources.load(p.Context, p.Source.(domain.YearMonth))
+				return func() (any, error) {
+					v, err := load()
+					return orEmpty(v), resolverErr(p.Context, err)
+				}, nil
+			},
+		},
+		"expenses": {
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(expenseType))),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				load := requestFrom(p.Context).loaders.expenses.load(p.Context, p.Source.(domain.YearMonth))
+				return func() (any, error) {
+					v, err := load()
+					return orEmpty(v), resolverErr(p.Context, err)
+				}, nil
+			},
+		},
+		"manual_budget": {
+			Type: graphql.NewNonNull(manualBudgetType),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				ym := p.Source.(domain.YearMonth)
+				load := requestFrom(p.Context).loaders.manualBudgets.load(p.Context, ym)
+				return func() (any, error) {
+					mb, err := load()
+					if err != nil {
+						return nil, resolverErr(p.Context, err)
+					}
+					if mb == nil {
+						// Never saved: empty, like GET /manual-budget.
+						mb = &domain.ManualBudget{YearMonth: ym, Items: []domain.ManualBudgetItem{}}
+					}
+					return mb, nil
+				}, nil
+			},
+		},
+		"totals": {
+			Type: graphql.NewNonNull(monthTotalsType),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				ym := p.Source.(domain.YearMonth)
+				l := requestFrom(p.Context).loaders
+				income := l.incomeSources.load(p.Context, ym)
+				budget := l.budgetSources.load(p.Context, ym)
+				expenses := l.expenses.load(p.Context, ym)
+				return func() (any, error) {
+					var t monthTotals
+					is, err := income()
+					if err != nil {
+						return nil, resolverErr(p.Context, err)
+					}
+					bs, err := budget()
+					if err != nil {
+						return nil, resolverErr(p.Context, err)
+					}
+					es, err := expenses()
+					if err != nil {
+						return nil, resolverErr(p.Context, err)
+					}
+					for _, s := range is {
+						t.income += s.AmountCents
+					}
+					for _, s := range bs {
+						t.budget += s.AmountCents
+					}
+					for _, e := range es {
+						t.expenses += e.AmountCents
+					}
+					return t, nil
+				}, nil
+			},
+		},
+	},
+})
+
+// orEmpty returns an empty list for a month with no rows.
+func orEmpty[T any](v []T) []T {
+	if v == nil {
+		return []T{}
+	}
+	return v
+}
@@ -3,16 +3,18 @@ package httpapi
 
 import (
 	"encoding/json"
-	"io"
+	"log/slog"
 	"net/http"
-	"os"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/mdco1990/webapp/internal/auth"
+	"github.com/mdco1990/webapp/internal/domain"
 	"github.com/mdco1990/webapp/internal/repository"
 	"github.com/mdco1990/webapp/internal/security"
-	"gopkg.in/yaml.v3"
+	"github.com/mdco1990/webapp/internal/service"
 )
 
 const pathDBAdmin = "/db-admin"
@@ -60,64 +62,82 @@ const swaggerHTML = `<!DOCTYPE html>
 </body>
 </html>`
 
-// OpenAPISpecHandler handles OpenAPI specification serving
-type OpenAPISpecHandler struct{}
-
-// NewOpenAPISpecHandler creates a new OpenAPI spec handler
-func NewOpenAPISpecHandler() *OpenAPISpecHandler {
-	return &OpenAPISpecHandler{}
+// OpenAPISpecHandler serves the OpenAPI document generated from the
+// declared routes
+type OpenAPISpecHandler struct {
+	doc  *OpenAPIDocument
+	once sync.Once
+	spec []byte
+	err  error
 }
 
-// ServeSpec serves the OpenAPI specification as JSON
-func (oash *OpenAPISpecHandler) ServeSpec(w http.ResponseWriter, _ *http.Request) {
-	f, err := os.Open("api/swagger.yaml")
-	if err != nil {
-		http.Error(w, "spec not found", http.StatusNotFound)
-		return
-	}
-	defer func() { _ = f.Close() }()
-
-	data, err := io.ReadAll(f)
-	if err != nil {
-		http.Error(w, "failed to read spec", http.StatusInternalServerError)
-		return
-	}
-
-	var v any
-	if err := yaml.Unmarshal(data, &v); err != nil {
-		http.Error(w, "invalid yaml", http.StatusInternalServerError)
-		return
-	}
+// NewOpenAPISpecHandler creates a new OpenAPI spec handler for doc
+func NewOpenAPISpecHandler(doc *OpenAPIDocument) *OpenAPISpecHandler {
+	return &OpenAPISpecHandler{doc: doc}
+}
 
-	out, err := json.Marshal(v)
-	if err != nil {
-		http.Error(w, "failed to encode json", http.StatusInternalServerError)
+// ServeSpec serves the OpenAPI specification as JSON. The document is
+// rendered on first use, once every route has been declared.
+func (oash *OpenAPISpecHandler) ServeSpec(w http.ResponseWriter, r *http.Request) {
+	oash.once.Do(func() {
+		oash.spec, oash.err = json.Marshal(oash.doc)
+	})
+	if oash.err != nil {
+		slog.Error("failed to render OpenAPI document", "err", oash.err)
+		respondErr(w, r, http.StatusInternalServerError, "failed to encode spec")
 		return
 	}
 
 	w.Header().Set(headerContentType, contentTypeJSON)
 	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write(out)
+	_, _ = w.Write(oash.spec)
 }
 
 // registerAdminRoutes wires Swagger UI and DB admin proxy (admin only)
-func registerAdminRoutes(r chi.Router, repo *repository.Repository) {
+func registerAdminRoutes(
+	routes *Routes,
+	repo *repository.Repository,
+	tokens *service.TokenService,
+	jwts *auth.Service,
+) {
 	// Swagger UI route
-	r.With(AdminOnly(repo)).Get("/swagger", handleSwaggerUI)
+	routes.router.With(AdminOnly(repo, tokens, jwts)).Get("/swagger", handleSwaggerUI)
 
 	// Admin user management API under /api/v1/admin/
-	r.With(AdminOnly(repo)).Route("/api/v1/admin", func(a chi.Router) {
-		a.Get("/users", handleListUsers(repo))
-		a.Get("/users/pending", handleListPendingUsers(repo))
-		a.Post("/users/{id}/approve", handleApproveUser(repo))
-		a.Post("/users/{id}/reject", handleRejectUser(repo))
-		a.Delete("/users/{id}", handleDeleteUser(repo))
-		a.Get("/logs", handleGetLogs)
+	admin := routes.Tagged("Admin").Secured(adminSecurity).Scoped(domain.ScopeAdmin).With(AdminOnly(repo, tokens, jwts))
+	admin.Route("/api/v1/admin", func(a *Routes) {
+		a.Add(
+			route(http.MethodGet, "/users", handleListUsers(repo)).
+				WithSummary("List users").
+				WithParams(queryParam("status", enumSchema("pending", "approved", "rejected"), "")).
+				WithParams(listQueryParams()...).
+				WithResponse(http.StatusOK, []domain.User{}),
+			route(http.MethodGet, "/users/pending", handleListPendingUsers(repo)).
+				WithSummary("List users awaiting approval").
+				WithResponse(http.StatusOK, []domain.User{}),
+			route(http.MethodPost, "/users/{id}/approve", handleApproveUser(repo)).
+				WithSummary("Approve a user").
+				WithResponse(http.StatusOK, statusResponse{}),
+			route(http.MethodPost, "/users/{id}/reject", handleRejectUser(repo)).
+				WithSummary("Reject a user").
+				WithResponse(http.StatusOK, statusResponse{}),
+			route(http.MethodDelete, "/users/{id}", handleDeleteUser(repo)).
+				WithSummary("Delete a user").
+				WithResponse(http.StatusOK, statusResponse{}),
+			route(http.MethodGet, "/logs", handleGetLogs).
+				WithSummary("Get server logs").
+				WithResponse(http.StatusOK, logsResponse{}),
+		)
 	})
 
 	// Note: SQLite Admin UI is now proxied directly by nginx to sqlite-admin:8080
 	// This route is kept for backward compatibility but redirects to nginx
-	r.With(AdminOnly(repo)).Get(pathDBAdmin, handleDBAdminRedirect)
+	routes.router.With(AdminOnly(repo, tokens, jwts)).Get(pathDBAdmin, handleDBAdminRedirect)
+}
+
+// logsResponse is the body of GET /api/v1/admin/logs.
+type logsResponse struct {
+	Logs []string `json:"logs"`
 }
 
 // handleSwaggerUI serves the Swagger UI interface
@@ -127,16 +147,22 @@ func handleSwaggerUI(w http.ResponseWriter, _ *http.Request) {
 	_, _ = w.Write([]byte(swaggerHTML))
 }
 
-// handleListUsers returns a handler for listing users with optional status filter
+// handleListUsers returns a handler for listing users with an optional
+// status filter and the shared list parameters, newest first by default
 func handleListUsers(repo *repository.Repository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		status := strings.TrimSpace(r.URL.Query().Get("status"))
-		users, err := repo.ListUsers(r.Context(), status)
+		q, err := parseListQuery(r, "-"+domain.SortDate)
 		if err != nil {
-			respondErr(w, http.StatusInternalServerError, "failed to list users")
+			respondError(w, r, err)
 			return
 		}
-		respondJSON(w, http.StatusOK, users)
+		q.Status = strings.TrimSpace(r.URL.Query().Get("status"))
+		page, err := repo.ListUsersPage(r.Context(), q)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondPage(w, r, page)
 	}
 }
 
@@ -145,7 +171,7 @@ func handleListPendingUsers(repo *repository.Repository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		users, err := repo.ListUsers(r.Context(), "pending")
 		if err != nil {
-			respondErr(w, http.StatusInternalServerError, "failed to list pending users")
+			respondErr(w, r, http.StatusInternalServerError, "failed to list pending users")
 			return
 		}
 		respondJSON(w, http.StatusOK, users)
@@ -159,18 +185,18 @@ func handleApproveUser(repo *repository.Repository) http.HandlerFunc {
 
 		id, err := strconv.ParseInt(idStr, 10, 64)
 		if err != nil || id <= 0 {
-			respondErr(w, http.StatusBadRequest, "invalid id")
+			respondErr(w, r, http.StatusBadRequest, "invalid id")
 			return
 		}
 
 		// Enhanced validation using security package
 		if err := security.ValidateID(id, "id"); err != nil {
-			respondErr(w, http.StatusBadRequest, "invalid id")
+			respondErr(w, r, http.StatusBadRequest, "invalid id")
 			return
 		}
 
 		if err := repo.UpdateUserStatus(r.Context(), id, "approved"); err != nil {
-			respondErr(w, http.StatusInternalServerError, "failed to approve")
+			respondError(w, r, err)
 			return
 		}
 		respondJSON(w, http.StatusOK, map[string]string{"status": "approved"})
@@ -183,11 +209,11 @@ func handleRejectUser(repo *repository.Repository) http.HandlerFunc {
 		idStr := chi.URLParam(r, "id")
 		id, err := strconv.ParseInt(idStr, 10, 64)
 		if err != nil || id <= 0 {
-			respondErr(w, http.StatusBadRequest, "invalid id")
+			respondErr(w, r, http.StatusBadRequest, "invalid id")
 			return
 		}
 		if err := repo.UpdateUserStatus(r.Context(), id, "rejected"); err != nil {
-			respondErr(w, http.StatusInternalServerError, "failed to reject")
+			respondError(w, r, err)
 			return
 		}
 		respondJSON(w, http.StatusOK, map[string]string{"status": "rejected"})
@@ -200,11 +226,11 @@ func handleDeleteUser(repo *repository.Repository) http.HandlerFunc {
 		idStr := chi.URLParam(r, "id")
 		id, err := strconv.ParseInt(idStr, 10, 64)
 		if err != nil || id <= 0 {
-			respondErr(w, http.StatusBadRequest, "invalid id")
+			respondErr(w, r, http.StatusBadRequest, "invalid id")
 			return
 		}
 		if err := repo.DeleteUser(r.Context(), id); err != nil {
-			respondErr(w, http.StatusInternalServerError, "failed to delete")
+			respondError(w, r, err)
 			return
 		}
 		respondJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
@@ -213,7 +239,7 @@ func handleDeleteUser(repo *repository.Repository) http.HandlerFunc {
 
 // handleGetLogs returns a handler for getting logs (stub)
 func handleGetLogs(w http.ResponseWriter, _ *http.Request) {
-	respondJSON(w, http.StatusOK, map[string]any{"logs": []string{}})
+	respondJSON(w, http.StatusOK, logsResponse{Logs: []string{}})
 }
 
 // handleDBAdminRedirect handles the DB admin redirect
@@ -7,8 +7,10 @@ import (
 	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/mdco1990/webapp/internal/auth"
 	"github.com/mdco1990/webapp/internal/config"
 	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/events"
 	"github.com/mdco1990/webapp/internal/middleware"
 	"github.com/mdco1990/webapp/internal/repository"
 	"github.com/mdco1990/webapp/internal/security"
@@ -17,35 +19,134 @@ import (
 
 const errInvalidID = "invalid id"
 
+// idempotencyKeyParam documents the Idempotency-Key header honored on
+// every write under /api/v1.
+var idempotencyKeyParam = headerParam("Idempotency-Key",
+	"Client-chosen unique key (1-255 printable ASCII characters) that makes a retry safe. "+
+		"A repeat of the same request with the same key within 24h replays the first response "+
+		"with Idempotent-Replayed: true instead of performing the write again.")
+
+// statusResponse documents the {"status": ...} body many writes answer with.
+type statusResponse struct {
+	Status string `json:"status"`
+}
+
+// monthAmountRequest sets an amount for a month.
+type monthAmountRequest struct {
+	Year        int   `json:"year" openapi:"required,minimum=1970,maximum=3000"`
+	Month       int   `json:"month" openapi:"required,minimum=1,maximum=12"`
+	AmountCents int64 `json:"amount_cents" openapi:"required"`
+}
+
+// expenseRequest is the body of POST /expenses.
+type expenseRequest struct {
+	Year        int    `json:"year" openapi:"required,minimum=1970,maximum=3000"`
+	Month       int    `json:"month" openapi:"required,minimum=1,maximum=12"`
+	Category    string `json:"category"`
+	Description string `json:"description" openapi:"required"`
+	AmountCents int64  `json:"amount_cents" openapi:"required,minimum=0"`
+}
+
 // registerAPIRoutes wires protected API endpoints
 func registerAPIRoutes(
-	r chi.Router,
+	routes *Routes,
 	cfg config.Config,
 	repo *repository.Repository,
 	svc *service.Service,
+	bg *service.BackgroundService,
+	shares *service.ShareService,
+	tokens *service.TokenService,
+	jwts *auth.Service,
+	limits *rateLimits,
+	feed *events.ChangeFeed,
+	webhooks *service.WebhookService,
+	trash *service.TrashService,
 ) {
-	r.Route("/api/v1", func(api chi.Router) {
+	routes.Route("/api/v1", func(api *Routes) {
 		api.Use(
-			middleware.APIKeyAuth(middleware.APIKeyConfig{Header: "X-API-Key", Key: cfg.APIKey}),
+			middleware.APIKeyAuth(middleware.APIKeyConfig{Header: "X-API-Key", Key: cfg.APIKey, Skip: hasBearerToken}),
 		)
-		api.Use(RequireSession(repo))
-
-		registerLegacyEndpoints(api, svc)
+		api.Use(RequireSession(repo, tokens, jwts))
+		api.Use(limits.api())
+		api.Use(middleware.Idempotency(middleware.IdempotencyConfig{
+			Store: limits.store,
+			TTL:   cfg.IdempotencyTTL,
+			Scope: userScope,
+		}))
+		api = api.Secured(apiSecurity).WithWriteParams(idempotencyKeyParam)
+
+		registerLegacyEndpoints(api, repo, svc)
 		registerEnhancedEndpoints(api, repo)
 		registerIncomeSourceEndpoints(api, repo)
 		registerBudgetSourceEndpoints(api, repo)
 		registerManualBudgetEndpoints(api, repo)
+		registerQIFEndpoints(api, repo)
+		registerSpreadsheetEndpoints(api, repo)
+		registerJournalEndpoints(api, repo)
+		registerDataTransferEndpoints(api, repo, bg)
+		registerReportEndpoints(api, repo, bg)
+		registerCalendarEndpoints(api, repo)
+		registerShareEndpoints(api, shares)
+		registerBatchEndpoints(api, repo)
+		registerGraphQLEndpoints(api, repo, svc)
+		registerEventStreamEndpoints(api, feed)
+		registerWebhookEndpoints(api, webhooks)
+		registerTokenEndpoints(api, tokens)
+		registerSearchEndpoints(api, repo)
+		registerTrashEndpoints(api, trash)
+		registerSyncEndpoints(api, repo)
 	})
 }
 
+// userScope keys idempotency records by the session's user.
+func userScope(r *http.Request) string {
+	userID := getUserIDFromContext(r.Context())
+	if userID <= 0 {
+		return ""
+	}
+	return strconv.FormatInt(userID, 10)
+}
+
 // registerLegacyEndpoints wires legacy API endpoints
-func registerLegacyEndpoints(api chi.Router, svc *service.Service) {
-	api.Get("/summary", handleSummary(svc))
-	api.Post("/salary", handleSetSalary(svc))
-	api.Post("/budget", handleSetBudget(svc))
-	api.Get("/expenses", handleListExpenses(svc))
-	api.Post("/expenses", handleAddExpense(svc))
-	api.Delete("/expenses/{id}", handleDeleteExpense(svc))
+func registerLegacyEndpoints(api *Routes, repo *repository.Repository, svc *service.Service) {
+	legacy := api.Tagged("Legacy")
+	legacy.Add(
+		route(http.MethodGet, "/summary", handleSummary(svc)).
+			WithSummary("Get monthly summary").
+			WithParams(yearMonthParams()...).
+			WithResponse(http.StatusOK, domain.Summary{}),
+		route(http.MethodPost, "/salary", handleSetSalary(svc)).
+			WithSummary("Set monthly salary").
+			WithRequest(monthAmountRequest{}).
+			WithResponse(http.StatusOK, statusResponse{}),
+		route(http.MethodPost, "/budget", handleSetBudget(svc)).
+			WithSummary("Set monthly budget").
+			WithRequest(monthAmountRequest{}).
+			WithResponse(http.StatusOK, statusResponse{}),
+		route(http.MethodGet, "/expenses", handleListExpenses(svc)).
+			WithSummary("List expenses").
+			WithDescription("Lists expenses, newest period first unless `sort` is given.").
+			WithParams(listQueryParams()...).
+			WithParams(amountQueryParams()...).
+			WithParams(categoryQueryParam).
+			WithResponse(http.StatusOK, []domain.Expense{}),
+		route(http.MethodPost, "/expenses", handleAddExpense(svc)).
+			WithScope(domain.ScopeWriteExpenses).
+			WithSummary("Create expense").
+			WithRequest(expenseRequest{}).
+			WithResponse(http.StatusCreated, idResponse{}),
+		route(http.MethodDelete, "/expenses/{id}", handleDeleteExpense(svc)).
+			WithScope(domain.ScopeWriteExpenses).
+			WithSummary("Delete expense").
+			WithDescription("Moves the expense to the trash, from which it can be restored.").
+			WithResponse(http.StatusOK, statusResponse{}),
+	)
+	legacy.Add(historyRoutes(repo, domain.BatchExpense, "/expenses/{id}", "expense")...)
+}
+
+// idResponse is the body of writes answering with the new record's ID.
+type idResponse struct {
+	ID int64 `json:"id"`
 }
 
 // handleSummary returns monthly summary
@@ -53,12 +154,12 @@ func handleSummary(svc *service.Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ym, err := parseYM(r)
 		if err != nil {
-			respondErr(w, http.StatusBadRequest, err.Error())
+			respondErr(w, r, http.StatusBadRequest, err.Error())
 			return
 		}
 		s, err := svc.Summary(r.Context(), ym)
 		if err != nil {
-			respondErr(w, http.StatusInternalServerError, "failed")
+			respondErr(w, r, http.StatusInternalServerError, "failed")
 			return
 		}
 		respondJSON(w, http.StatusOK, s)
@@ -68,17 +169,13 @@ func handleSummary(svc *service.Service) http.HandlerFunc {
 // handleSetSalary sets salary for a month
 func handleSetSalary(svc *service.Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var req struct {
-			Year        int   `json:"year"`
-			Month       int   `json:"month"`
-			AmountCents int64 `json:"amount_cents"`
-		}
+		var req monthAmountRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			respondErr(w, http.StatusBadRequest, invalidBodyMsg)
+			respondErr(w, r, http.StatusBadRequest, invalidBodyMsg)
 			return
 		}
 		if err := svc.SetSalary(r.Context(), domain.YearMonth{Year: req.Year, Month: req.Month}, domain.Money(req.AmountCents)); err != nil {
-			respondErr(w, http.StatusBadRequest, err.Error())
+			respondErr(w, r, http.StatusBadRequest, err.Error())
 			return
 		}
 		respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
@@ -88,37 +185,33 @@ func handleSetSalary(svc *service.Service) http.HandlerFunc {
 // handleSetBudget sets budget for a month
 func handleSetBudget(svc *service.Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var req struct {
-			Year        int   `json:"year"`
-			Month       int   `json:"month"`
-			AmountCents int64 `json:"amount_cents"`
-		}
+		var req monthAmountRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			respondErr(w, http.StatusBadRequest, invalidBodyMsg)
+			respondErr(w, r, http.StatusBadRequest, invalidBodyMsg)
 			return
 		}
 		if err := svc.SetBudget(r.Context(), domain.YearMonth{Year: req.Year, Month: req.Month}, domain.Money(req.AmountCents)); err != nil {
-			respondErr(w, http.StatusBadRequest, err.Error())
+			respondErr(w, r, http.StatusBadRequest, err.Error())
 			return
 		}
 		respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 	}
 }
 
-// handleListExpenses lists expenses for a month
+// handleListExpenses lists expenses, newest period first by default
 func handleListExpenses(svc *service.Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ym, err := parseYM(r)
+		q, err := parseListQuery(r, "-"+domain.SortDate)
 		if err != nil {
-			respondErr(w, http.StatusBadRequest, err.Error())
+			respondError(w, r, err)
 			return
 		}
-		items, err := svc.ListExpenses(r.Context(), ym)
+		page, err := svc.ListExpensesPage(r.Context(), q)
 		if err != nil {
-			respondErr(w, http.StatusInternalServerError, "failed")
+			respondError(w, r, err)
 			return
 		}
-		respondJSON(w, http.StatusOK, items)
+		respondPage(w, r, page)
 	}
 }
 
@@ -126,16 +219,10 @@ func handleListExpenses(svc *service.Service) http.HandlerFunc {
 func handleAddExpense(svc *service.Service) http.HandlerFunc {
 	secureHandler := security.NewSecureHandler()
 	return func(w http.ResponseWriter, r *http.Request) {
-		var req struct {
-			Year        int    `json:"year"`
-			Month       int    `json:"month"`
-			Category    string `json:"category"`
-			Description string `json:"description"`
-			AmountCents int64  `json:"amount_cents"`
-		}
+		var req expenseRequest
 
 		if err := secureHandler.SecureJSONDecoder(r, &req); err != nil {
-			secureHandler.SecureErrorResponse(w, http.StatusBadRequest, "invalid request body")
+			secureHandler.SecureErrorResponse(w, r, http.StatusBadRequest, "invalid request body")
 			return
 		}
 
@@ -149,17 +236,17 @@ func handleAddExpense(svc *service.Service) http.HandlerFunc {
 		// Enhanced OWASP validation and sanitization
 		validatedExpense, err := security.ValidateExpense(expense)
 		if err != nil {
-			secureHandler.SecureErrorResponse(w, http.StatusBadRequest, err.Error())
+			secureHandler.SecureErrorResponse(w, r, http.StatusBadRequest, err.Error())
 			return
 		}
 
 		id, err := svc.AddExpense(r.Context(), validatedExpense)
 		if err != nil {
-			secureHandler.SecureErrorResponse(w, http.StatusBadRequest, err.Error())
+			secureHandler.SecureErrorResponse(w, r, http.StatusBadRequest, err.Error())
 			return
 		}
 
-		secureHandler.SecureJSONResponse(w, http.StatusCreated, map[string]any{"id": id})
+		secureHandler.SecureJSONResponse(w, http.StatusCreated, idResponse{ID: id})
 	}
 }
 
@@ -169,11 +256,11 @@ func handleDeleteExpense(svc *service.Service) http.HandlerFunc {
 		idStr := chi.URLParam(r, "id")
 		id, err := strconv.ParseInt(idStr, 10, 64)
 		if err != nil || id <= 0 {
-			respondErr(w, http.StatusBadRequest, errInvalidID)
+			respondErr(w, r, http.StatusBadRequest, errInvalidID)
 			return
 		}
 		if err := svc.DeleteExpense(r.Context(), id); err != nil {
-			respondErr(w, http.StatusInternalServerError, "failed")
+			respondError(w, r, err)
 			return
 		}
 		respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
@@ -181,9 +268,33 @@ func handleDeleteExpense(svc *service.Service) http.HandlerFunc {
 }
 
 // registerEnhancedEndpoints wires new enhanced API endpoints
-func registerEnhancedEndpoints(api chi.Router, repo *repository.Repository) {
-	api.Get("/monthly-data", handleMonthlyData(repo))
-	api.Post("/seed-defaults", handleSeedDefaults(repo))
+func registerEnhancedEndpoints(api *Routes, repo *repository.Repository) {
+	api.Add(
+		route(http.MethodGet, "/monthly-data", handleMonthlyData(repo)).
+			WithTag("Monthly Data").
+			WithSummary("Get comprehensive monthly data").
+			WithDescription("Returns the month's income sources, budget sources and expenses with their totals.").
+			WithParams(yearMonthParams()...).
+			WithResponse(http.StatusOK, domain.MonthlyData{}),
+		route(http.MethodPost, "/seed-defaults", handleSeedDefaults(repo)).
+			WithTag("Utilities").
+			WithSummary("Seed default income and budget sources").
+			WithDescription("Seeds default income and budget sources for the month if it has none.").
+			WithRequest(seedDefaultsRequest{}).
+			WithResponse(http.StatusOK, seedDefaultsResponse{}),
+	)
+}
+
+// seedDefaultsRequest picks the month to seed.
+type seedDefaultsRequest struct {
+	Year  int `json:"year" openapi:"required,minimum=1970,maximum=3000"`
+	Month int `json:"month" openapi:"required,minimum=1,maximum=12"`
+}
+
+// seedDefaultsResponse counts the seeded sources.
+type seedDefaultsResponse struct {
+	SeededIncome int `json:"seeded_income"`
+	SeededBudget int `json:"seeded_budget"`
 }
 
 // handleMonthlyData gets monthly data
@@ -192,13 +303,13 @@ func handleMonthlyData(repo *repository.Repository) http.HandlerFunc {
 		userID := getUserIDFromContext(r.Context())
 		ym, err := parseYM(r)
 		if err != nil {
-			respondErr(w, http.StatusBadRequest, err.Error())
+			respondErr(w, r, http.StatusBadRequest, err.Error())
 			return
 		}
 
 		data, err := repo.GetMonthlyData(r.Context(), userID, ym)
 		if err != nil {
-			respondErr(w, http.StatusInternalServerError, "failed to get monthly data")
+			respondErr(w, r, http.StatusInternalServerError, "failed to get monthly data")
 			return
 		}
 
@@ -210,16 +321,13 @@ func handleMonthlyData(repo *repository.Repository) http.HandlerFunc {
 func handleSeedDefaults(repo *repository.Repository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := getUserIDFromContext(r.Context())
-		var req struct {
-			Year  int `json:"year"`
-			Month int `json:"month"`
-		}
+		var req seedDefaultsRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			respondErr(w, http.StatusBadRequest, invalidBodyMsg)
+			respondErr(w, r, http.StatusBadRequest, invalidBodyMsg)
 			return
 		}
 		if req.Year == 0 || req.Month == 0 {
-			respondErr(w, http.StatusBadRequest, "year and month required")
+			respondErr(w, r, http.StatusBadRequest, "year and month required")
 			return
 		}
 
@@ -229,9 +337,9 @@ func handleSeedDefaults(repo *repository.Repository) http.HandlerFunc {
 			return
 		}
 
-		respondJSON(w, http.StatusOK, map[string]any{
-			"seeded_income": seededIncome,
-			"seeded_budget": seededBudget,
+		respondJSON(w, http.StatusOK, seedDefaultsResponse{
+			SeededIncome: seededIncome,
+			SeededBudget: seededBudget,
 		})
 	}
 }
@@ -323,30 +431,52 @@ func seedDefaultBudget(
 }
 
 // registerIncomeSourceEndpoints wires income source CRUD endpoints
-func registerIncomeSourceEndpoints(api chi.Router, repo *repository.Repository) {
-	api.Route("/income-sources", func(income chi.Router) {
-		income.Get("/", handleListIncomeSources(repo))
-		income.Post("/", handleCreateIncomeSource(repo))
-		income.Put("/{id}", handleUpdateIncomeSource(repo))
-		income.Delete("/{id}", handleDeleteIncomeSource(repo))
+func registerIncomeSourceEndpoints(api *Routes, repo *repository.Repository) {
+	api.Tagged("Income Sources").Route("/income-sources", func(income *Routes) {
+		income.Add(
+			route(http.MethodGet, "/", handleListIncomeSources(repo)).
+				WithSummary("List income sources").
+				WithDescription("Lists the user's income sources, sorted by name unless `sort` is given.").
+				WithParams(listQueryParams()...).
+				WithParams(amountQueryParams()...).
+				WithResponse(http.StatusOK, []domain.IncomeSource{}),
+			route(http.MethodGet, "/{id}", handleGetIncomeSource(repo)).
+				WithSummary("Get income source").
+				WithDescription("Returns one income source. The ETag is its version, for use in If-Match.").
+				WithResponse(http.StatusOK, domain.IncomeSource{}),
+			route(http.MethodPost, "/", handleCreateIncomeSource(repo)).
+				WithSummary("Create income source").
+				WithRequest(domain.CreateIncomeSourceRequest{}).
+				WithResponse(http.StatusCreated, domain.IncomeSource{}),
+			route(http.MethodPut, "/{id}", handleUpdateIncomeSource(repo)).
+				WithSummary("Update income source").
+				WithParams(ifMatchParam).
+				WithRequest(domain.UpdateSourceRequest{}).
+				WithResponse(http.StatusOK, statusResponse{}),
+			route(http.MethodDelete, "/{id}", handleDeleteIncomeSource(repo)).
+				WithSummary("Delete income source").
+				WithDescription("Moves the income source to the trash, from which it can be restored.").
+				WithParams(ifMatchParam).
+				WithResponse(http.StatusOK, statusResponse{}),
+		)
+		income.Add(historyRoutes(repo, domain.BatchIncomeSource, "/{id}", "income source")...)
 	})
 }
 
-// handleListIncomeSources lists income sources for a month
+// handleListIncomeSources lists the user's income sources, by name by default
 func handleListIncomeSources(repo *repository.Repository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		userID := getUserIDFromContext(r.Context())
-		ym, err := parseYM(r)
+		q, err := parseListQuery(r, domain.SortName)
 		if err != nil {
-			respondErr(w, http.StatusBadRequest, err.Error())
+			respondError(w, r, err)
 			return
 		}
-		sources, err := repo.ListIncomeSources(r.Context(), userID, ym)
+		page, err := repo.ListIncomeSourcesPage(r.Context(), getUserIDFromContext(r.Context()), q)
 		if err != nil {
-			respondErr(w, http.StatusInternalServerError, "failed")
+			respondError(w, r, err)
 			return
 		}
-		respondJSON(w, http.StatusOK, sources)
+		respondPage(w, r, page)
 	}
 }
 
@@ -358,20 +488,20 @@ func handleCreateIncomeSource(repo *repository.Repository) http.HandlerFunc {
 
 		// Validate user ID
 		if err := security.ValidateUserID(userID); err != nil {
-			secureHandler.SecureErrorResponse(w, http.StatusUnauthorized, "invalid user")
+			secureHandler.SecureErrorResponse(w, r, http.StatusUnauthorized, "invalid user")
 			return
 		}
 
 		var req domain.CreateIncomeSourceRequest
 		if err := secureHandler.SecureJSONDecoder(r, &req); err != nil {
-			secureHandler.SecureErrorResponse(w, http.StatusBadRequest, "invalid request body")
+			secureHandler.SecureErrorResponse(w, r, http.StatusBadRequest, "invalid request body")
 			return
 		}
 
 		// Enhanced OWASP validation and sanitization
 		validatedReq, err := security.ValidateCreateIncomeSourceRequest(req)
 		if err != nil {
-			secureHandler.SecureErrorResponse(w, http.StatusBadRequest, err.Error())
+			secureHandler.SecureErrorResponse(w, r, http.StatusBadRequest, err.Error())
 			return
 		}
 
@@ -379,6 +509,7 @@ func handleCreateIncomeSource(repo *repository.Repository) http.HandlerFunc {
 		if err != nil {
 			secureHandler.SecureErrorResponse(
 				w,
+				r,
 				http.StatusInternalServerError,
 				"failed to create income source",
 			)
@@ -389,41 +520,75 @@ func handleCreateIncomeSource(repo *repository.Repository) http.HandlerFunc {
 	}
 }
 
-// handleUpdateIncomeSource updates an income source
+// handleGetIncomeSource returns one income source with its ETag
+func handleGetIncomeSource(repo *repository.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil || id <= 0 {
+			respondErr(w, r, http.StatusBadRequest, errInvalidID)
+			return
+		}
+		source, err := repo.GetIncomeSource(r.Context(), id, getUserIDFromContext(r.Context()))
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		setETag(w, source.Version)
+		respondJSON(w, http.StatusOK, source)
+	}
+}
+
+// handleUpdateIncomeSource updates an income source, honoring If-Match
 func handleUpdateIncomeSource(repo *repository.Repository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := getUserIDFromContext(r.Context())
 		idStr := chi.URLParam(r, "id")
 		id, err := strconv.ParseInt(idStr, 10, 64)
 		if err != nil || id <= 0 {
-			respondErr(w, http.StatusBadRequest, errInvalidID)
+			respondErr(w, r, http.StatusBadRequest, errInvalidID)
 			return
 		}
 		var req domain.UpdateSourceRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			respondErr(w, http.StatusBadRequest, invalidBodyMsg)
+			respondErr(w, r, http.StatusBadRequest, invalidBodyMsg)
 			return
 		}
-		if err := repo.UpdateIncomeSource(r.Context(), id, userID, req); err != nil {
-			respondErr(w, http.StatusInternalServerError, "failed to update income source")
+		if err := security.ValidateDayOfMonth(req.DayOfMonth); err != nil {
+			respondErr(w, r, http.StatusBadRequest, err.Error())
 			return
 		}
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		version, err = repo.UpdateIncomeSource(r.Context(), id, userID, version, req)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		setETag(w, version)
 		respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 	}
 }
 
-// handleDeleteIncomeSource deletes an income source
+// handleDeleteIncomeSource deletes an income source, honoring If-Match
 func handleDeleteIncomeSource(repo *repository.Repository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := getUserIDFromContext(r.Context())
 		idStr := chi.URLParam(r, "id")
 		id, err := strconv.ParseInt(idStr, 10, 64)
 		if err != nil || id <= 0 {
-			respondErr(w, http.StatusBadRequest, errInvalidID)
+			respondErr(w, r, http.StatusBadRequest, errInvalidID)
 			return
 		}
-		if err := repo.DeleteIncomeSource(r.Context(), id, userID); err != nil {
-			respondErr(w, http.StatusInternalServerError, "failed to delete income source")
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		if err := repo.DeleteIncomeSource(r.Context(), id, userID, version); err != nil {
+			respondError(w, r, err)
 			return
 		}
 		respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
@@ -431,30 +596,52 @@ func handleDeleteIncomeSource(repo *repository.Repository) http.HandlerFunc {
 }
 
 // registerBudgetSourceEndpoints wires budget source CRUD endpoints
-func registerBudgetSourceEndpoints(api chi.Router, repo *repository.Repository) {
-	api.Route("/budget-sources", func(budget chi.Router) {
-		budget.Get("/", handleListBudgetSources(repo))
-		budget.Post("/", handleCreateBudgetSource(repo))
-		budget.Put("/{id}", handleUpdateBudgetSource(repo))
-		budget.Delete("/{id}", handleDeleteBudgetSource(repo))
+func registerBudgetSourceEndpoints(api *Routes, repo *repository.Repository) {
+	api.Tagged("Budget Sources").Route("/budget-sources", func(budget *Routes) {
+		budget.Add(
+			route(http.MethodGet, "/", handleListBudgetSources(repo)).
+				WithSummary("List budget sources").
+				WithDescription("Lists the user's budget sources, sorted by name unless `sort` is given.").
+				WithParams(listQueryParams()...).
+				WithParams(amountQueryParams()...).
+				WithResponse(http.StatusOK, []domain.BudgetSource{}),
+			route(http.MethodGet, "/{id}", handleGetBudgetSource(repo)).
+				WithSummary("Get budget source").
+				WithDescription("Returns one budget source. The ETag is its version, for use in If-Match.").
+				WithResponse(http.StatusOK, domain.BudgetSource{}),
+			route(http.MethodPost, "/", handleCreateBudgetSource(repo)).
+				WithSummary("Create budget source").
+				WithRequest(domain.CreateBudgetSourceRequest{}).
+				WithResponse(http.StatusCreated, domain.BudgetSource{}),
+			route(http.MethodPut, "/{id}", handleUpdateBudgetSource(repo)).
+				WithSummary("Update budget source").
+				WithParams(ifMatchParam).
+				WithRequest(domain.UpdateSourceRequest{}).
+				WithResponse(http.StatusOK, statusResponse{}),
+			route(http.MethodDelete, "/{id}", handleDeleteBudgetSource(repo)).
+				WithSummary("Delete budget source").
+				WithDescription("Moves the budget source to the trash, from which it can be restored.").
+				WithParams(ifMatchParam).
+				WithResponse(http.StatusOK, statusResponse{}),
+		)
+		budget.Add(historyRoutes(repo, domain.BatchBudgetSource, "/{id}", "budget source")...)
 	})
 }
 
-// handleListBudgetSources lists budget sources for a month
+// handleListBudgetSources lists the user's budget sources, by name by default
 func handleListBudgetSources(repo *repository.Repository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		userID := getUserIDFromContext(r.Context())
-		ym, err := parseYM(r)
+		q, err := parseListQuery(r, domain.SortName)
 		if err != nil {
-			respondErr(w, http.StatusBadRequest, err.Error())
+			respondError(w, r, err)
 			return
 		}
-		sources, err := repo.ListBudgetSources(r.Context(), userID, ym)
+		page, err := repo.ListBudgetSourcesPage(r.Context(), getUserIDFromContext(r.Context()), q)
 		if err != nil {
-			respondErr(w, http.StatusInternalServerError, "failed")
+			respondError(w, r, err)
 			return
 		}
-		respondJSON(w, http.StatusOK, sources)
+		respondPage(w, r, page)
 	}
 }
 
@@ -464,53 +651,91 @@ func handleCreateBudgetSource(repo *repository.Repository) http.HandlerFunc {
 		userID := getUserIDFromContext(r.Context())
 		var req domain.CreateBudgetSourceRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			respondErr(w, http.StatusBadRequest, invalidBodyMsg)
+			respondErr(w, r, http.StatusBadRequest, invalidBodyMsg)
+			return
+		}
+		if err := security.ValidateDayOfMonth(req.DayOfMonth); err != nil {
+			respondErr(w, r, http.StatusBadRequest, err.Error())
 			return
 		}
 		source, err := repo.CreateBudgetSource(r.Context(), userID, req)
 		if err != nil {
-			respondErr(w, http.StatusInternalServerError, "failed to create budget source")
+			respondErr(w, r, http.StatusInternalServerError, "failed to create budget source")
 			return
 		}
 		respondJSON(w, http.StatusCreated, source)
 	}
 }
 
-// handleUpdateBudgetSource updates a budget source
+// handleGetBudgetSource returns one budget source with its ETag
+func handleGetBudgetSource(repo *repository.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil || id <= 0 {
+			respondErr(w, r, http.StatusBadRequest, errInvalidID)
+			return
+		}
+		source, err := repo.GetBudgetSource(r.Context(), id, getUserIDFromContext(r.Context()))
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		setETag(w, source.Version)
+		respondJSON(w, http.StatusOK, source)
+	}
+}
+
+// handleUpdateBudgetSource updates a budget source, honoring If-Match
 func handleUpdateBudgetSource(repo *repository.Repository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := getUserIDFromContext(r.Context())
 		idStr := chi.URLParam(r, "id")
 		id, err := strconv.ParseInt(idStr, 10, 64)
 		if err != nil || id <= 0 {
-			respondErr(w, http.StatusBadRequest, errInvalidID)
+			respondErr(w, r, http.StatusBadRequest, errInvalidID)
 			return
 		}
 		var req domain.UpdateSourceRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			respondErr(w, http.StatusBadRequest, invalidBodyMsg)
+			respondErr(w, r, http.StatusBadRequest, invalidBodyMsg)
 			return
 		}
-		if err := repo.UpdateBudgetSource(r.Context(), id, userID, req); err != nil {
-			respondErr(w, http.StatusInternalServerError, "failed to update budget source")
+		if err := security.ValidateDayOfMonth(req.DayOfMonth); err != nil {
+			respondErr(w, r, http.StatusBadRequest, err.Error())
 			return
 		}
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		version, err = repo.UpdateBudgetSource(r.Context(), id, userID, version, req)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		setETag(w, version)
 		respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 	}
 }
 
-// handleDeleteBudgetSource deletes a budget source
+// handleDeleteBudgetSource deletes a budget source, honoring If-Match
 func handleDeleteBudgetSource(repo *repository.Repository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := getUserIDFromContext(r.Context())
 		idStr := chi.URLParam(r, "id")
 		id, err := strconv.ParseInt(idStr, 10, 64)
 		if err != nil || id <= 0 {
-			respondErr(w, http.StatusBadRequest, errInvalidID)
+			respondErr(w, r, http.StatusBadRequest, errInvalidID)
 			return
 		}
-		if err := repo.DeleteBudgetSource(r.Context(), id, userID); err != nil {
-			respondErr(w, http.StatusInternalServerError, "failed to delete budget source")
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		if err := repo.DeleteBudgetSource(r.Context(), id, userID, version); err != nil {
+			respondError(w, r, err)
 			return
 		}
 		respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
@@ -518,54 +743,82 @@ func handleDeleteBudgetSource(repo *repository.Repository) http.HandlerFunc {
 }
 
 // registerManualBudgetEndpoints wires manual budget endpoints
-func registerManualBudgetEndpoints(api chi.Router, repo *repository.Repository) {
-	api.Route("/manual-budget", func(mb chi.Router) {
-		mb.Get("/", handleGetManualBudget(repo))
-		mb.Put("/", handleUpdateManualBudget(repo))
+func registerManualBudgetEndpoints(api *Routes, repo *repository.Repository) {
+	api.Tagged("Manual Budget").Route("/manual-budget", func(mb *Routes) {
+		mb.Add(
+			route(http.MethodGet, "/", handleGetManualBudget(repo)).
+				WithSummary("Get manual budget data").
+				WithDescription("Returns the month's bank amount and manual budget items. The ETag is the budget's version.").
+				WithParams(yearMonthParams()...).
+				WithResponse(http.StatusOK, manualBudgetResponse{}),
+			route(http.MethodPut, "/", handleUpdateManualBudget(repo)).
+				WithSummary("Save manual budget data").
+				WithDescription("Upserts the month's bank amount and replaces all its items. Items with the same "+
+					"name and amount as a current one are kept; the others go to the trash. "+
+					"Send the ETag from GET in If-Match to avoid overwriting a concurrent change.").
+				WithParams(ifMatchParam).
+				WithRequest(manualBudgetRequest{}).
+				WithResponse(http.StatusOK, statusResponse{}),
+		)
+		mb.Add(historyRoutes(repo, domain.ChangeManualBudget, "/{id}", "manual budget")...)
 	})
 }
 
-// handleGetManualBudget gets manual budget data
+// manualBudgetResponse is a month's manual budget.
+type manualBudgetResponse struct {
+	ID              int64                     `json:"id,omitempty"` // absent until the month is saved
+	BankAmountCents int64                     `json:"bank_amount_cents"`
+	Items           []domain.ManualBudgetItem `json:"items"`
+	Version         int64                     `json:"version"`
+}
+
+// manualBudgetRequest replaces a month's manual budget.
+type manualBudgetRequest struct {
+	Year            int   `json:"year" openapi:"required,minimum=1970,maximum=3000"`
+	Month           int   `json:"month" openapi:"required,minimum=1,maximum=12"`
+	BankAmountCents int64 `json:"bank_amount_cents"`
+	Items           []struct {
+		ID          any    `json:"id"` // Accept both string and int64
+		Name        string `json:"name"`
+		AmountCents int64  `json:"amount_cents"`
+	} `json:"items"`
+}
+
+// handleGetManualBudget gets manual budget data with its ETag
 func handleGetManualBudget(repo *repository.Repository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := getUserIDFromContext(r.Context())
 		ym, err := parseYM(r)
 		if err != nil {
-			respondErr(w, http.StatusBadRequest, err.Error())
+			respondErr(w, r, http.StatusBadRequest, err.Error())
 			return
 		}
 		data, err := repo.GetManualBudget(r.Context(), userID, ym)
 		if err != nil {
-			respondErr(w, http.StatusInternalServerError, "failed to get manual budget")
+			respondErr(w, r, http.StatusInternalServerError, "failed to get manual budget")
 			return
 		}
-		respondJSON(w, http.StatusOK, map[string]any{
-			"bank_amount_cents": int64(data.BankAmountCents),
-			"items":             data.Items,
+		setETag(w, data.Version)
+		respondJSON(w, http.StatusOK, manualBudgetResponse{
+			ID:              data.ID,
+			BankAmountCents: int64(data.BankAmountCents),
+			Items:           data.Items,
+			Version:         data.Version,
 		})
 	}
 }
 
-// handleUpdateManualBudget updates manual budget data
+// handleUpdateManualBudget updates manual budget data, honoring If-Match
 func handleUpdateManualBudget(repo *repository.Repository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := getUserIDFromContext(r.Context())
-		var req struct {
-			Year            int   `json:"year"`
-			Month           int   `json:"month"`
-			BankAmountCents int64 `json:"bank_amount_cents"`
-			Items           []struct {
-				ID          interface{} `json:"id"` // Accept both string and int64
-				Name        string      `json:"name"`
-				AmountCents int64       `json:"amount_cents"`
-			} `json:"items"`
-		}
+		var req manualBudgetRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			respondErr(w, http.StatusBadRequest, invalidBodyMsg)
+			respondErr(w, r, http.StatusBadRequest, invalidBodyMsg)
 			return
 		}
 		if req.Year == 0 || req.Month == 0 {
-			respondErr(w, http.StatusBadRequest, "year and month required")
+			respondErr(w, r, http.StatusBadRequest, "year and month required")
 			return
 		}
 
@@ -578,10 +831,18 @@ func handleUpdateManualBudget(repo *repository.Repository) http.HandlerFunc {
 			})
 		}
 
-		if err := repo.UpsertManualBudget(r.Context(), userID, domain.YearMonth{Year: req.Year, Month: req.Month}, domain.Money(req.BankAmountCents), items); err != nil {
-			respondErr(w, http.StatusInternalServerError, "failed to save manual budget")
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		ym := domain.YearMonth{Year: req.Year, Month: req.Month}
+		version, err = repo.UpsertManualBudget(r.Context(), userID, ym, version, domain.Money(req.BankAmountCents), items)
+		if err != nil {
+			respondError(w, r, err)
 			return
 		}
+		setETag(w, version)
 		respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 	}
 }
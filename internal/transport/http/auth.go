@@ -1,8 +1,6 @@
 package httpapi
 
 import (
-	"context"
-	"database/sql"
 	"encoding/json"
 	"errors"
 	"io"
@@ -11,7 +9,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/go-chi/chi/v5"
+	"github.com/mdco1990/webapp/internal/auth"
 	"github.com/mdco1990/webapp/internal/domain"
 	"github.com/mdco1990/webapp/internal/repository"
 	"golang.org/x/crypto/bcrypt"
@@ -24,16 +22,72 @@ const (
 )
 
 // registerAuthRoutes wires public auth endpoints
-func registerAuthRoutes(r chi.Router, repo *repository.Repository) {
-	r.Route("/auth", func(auth chi.Router) {
-		auth.Post("/login", handleLogin(repo))
-		auth.Post("/logout", handleLogout(repo))
-		auth.Post("/update-password", handleUpdatePassword(repo))
-		auth.Post("/register", handleRegister(repo))
-		auth.Get("/me", handleMe(repo))
+func registerAuthRoutes(routes *Routes, repo *repository.Repository, jwts *auth.Service, limits *rateLimits) {
+	login := limits.login()
+	routes.Tagged("Auth").Route("/auth", func(a *Routes) {
+		a.Add(
+			route(http.MethodPost, "/login", handleLogin(repo)).
+				WithMiddleware(login).
+				WithSummary("User login").
+				WithDescription("Authenticates the user and creates a session, also set as the session_id cookie.").
+				WithRequest(LoginRequest{}).
+				WithResponse(http.StatusOK, LoginResponse{}),
+			route(http.MethodPost, "/logout", handleLogout(repo)).
+				WithSummary("User logout").
+				WithDescription("Invalidates the current session.").
+				WithResponse(http.StatusOK, statusResponse{}),
+			route(http.MethodPost, "/update-password", handleUpdatePassword(repo)).
+				WithSecurity(sessionSecurity).
+				WithSummary("Update password").
+				WithRequest(updatePasswordRequest{}).
+				WithResponse(http.StatusOK, statusResponse{}),
+			route(http.MethodPost, "/register", handleRegister(repo)).
+				WithMiddleware(login).
+				WithSummary("User registration").
+				WithDescription("Creates a user account, pending until an admin approves it.").
+				WithRequest(registerRequest{}).
+				WithResponse(http.StatusCreated, domain.User{}),
+			route(http.MethodPost, "/token", handleIssueJWT(jwts)).
+				WithMiddleware(login).
+				WithSummary("Issue a JWT").
+				WithDescription("Exchanges a username and password for a short-lived JWT, sent as "+
+					"`Authorization: Bearer <jwt>` in place of a session, and a refresh token. "+
+					"JWTs can be verified with the keys at /.well-known/jwks.json.").
+				WithRequest(LoginRequest{}).
+				WithResponse(http.StatusOK, auth.Result{}),
+			route(http.MethodPost, "/token/refresh", handleRefreshJWT(jwts)).
+				WithMiddleware(login).
+				WithSummary("Refresh a JWT").
+				WithDescription("Exchanges a refresh token for a new JWT and refresh token; the old refresh token stops working.").
+				WithRequest(tokenRefreshRequest{}).
+				WithResponse(http.StatusOK, auth.Result{}),
+			route(http.MethodGet, "/me", handleMe(repo)).
+				WithSecurity(sessionSecurity).
+				WithSummary("Current user").
+				WithResponse(http.StatusOK, meResponse{}),
+		)
 	})
 }
 
+// updatePasswordRequest is the body of POST /auth/update-password.
+type updatePasswordRequest struct {
+	CurrentPassword string `json:"current_password" openapi:"required"`
+	NewPassword     string `json:"new_password" openapi:"required"`
+}
+
+// registerRequest is the body of POST /auth/register.
+type registerRequest struct {
+	Username string `json:"username" openapi:"required"`
+	Password string `json:"password" openapi:"required"`
+	Email    string `json:"email"`
+}
+
+// meResponse is the body of GET /auth/me.
+type meResponse struct {
+	Success bool         `json:"success"`
+	User    *domain.User `json:"user"`
+}
+
 // LoginProcessor handles the complete login workflow
 type LoginProcessor struct {
 	repo *repository.Repository
@@ -46,8 +100,8 @@ func NewLoginProcessor(repo *repository.Repository) *LoginProcessor {
 
 // LoginRequest represents a login request
 type LoginRequest struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username string `json:"username" openapi:"required"`
+	Password string `json:"password" openapi:"required"`
 }
 
 // LoginResponse represents a login response
@@ -63,14 +117,14 @@ func (lp *LoginProcessor) validateRequest(w http.ResponseWriter, r *http.Request
 	// Check content type first
 	contentType := r.Header.Get("Content-Type")
 	if !strings.HasPrefix(contentType, "application/json") {
-		respondErr(w, http.StatusBadRequest, "Content-Type must be application/json")
+		respondErr(w, r, http.StatusBadRequest, "Content-Type must be application/json")
 		return nil, false
 	}
 
 	// Read the request body
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		respondErr(w, http.StatusBadRequest, "failed to read request body")
+		respondErr(w, r, http.StatusBadRequest, "failed to read request body")
 		return nil, false
 	}
 	defer func() {
@@ -82,7 +136,7 @@ func (lp *LoginProcessor) validateRequest(w http.ResponseWriter, r *http.Request
 
 	// Check if body is empty
 	if len(body) == 0 {
-		respondErr(w, http.StatusBadRequest, "request body is empty")
+		respondErr(w, r, http.StatusBadRequest, "request body is empty")
 		return nil, false
 	}
 
@@ -92,22 +146,23 @@ func (lp *LoginProcessor) validateRequest(w http.ResponseWriter, r *http.Request
 // parseCredentials parses and validates login credentials
 func (lp *LoginProcessor) parseCredentials(
 	w http.ResponseWriter,
+	r *http.Request,
 	body []byte,
 ) (*LoginRequest, bool) {
 	var req LoginRequest
 
 	if err := json.Unmarshal(body, &req); err != nil {
-		respondErr(w, http.StatusBadRequest, "invalid JSON format")
+		respondErr(w, r, http.StatusBadRequest, "invalid JSON format")
 		return nil, false
 	}
 
 	// Validate required fields
 	if req.Username == "" {
-		respondErr(w, http.StatusBadRequest, "username is required")
+		respondErr(w, r, http.StatusBadRequest, "username is required")
 		return nil, false
 	}
 	if req.Password == "" {
-		respondErr(w, http.StatusBadRequest, "password is required")
+		respondErr(w, r, http.StatusBadRequest, "password is required")
 		return nil, false
 	}
 
@@ -116,20 +171,20 @@ func (lp *LoginProcessor) parseCredentials(
 
 // authenticateUser authenticates the user credentials
 func (lp *LoginProcessor) authenticateUser(
-	ctx context.Context,
 	w http.ResponseWriter,
+	r *http.Request,
 	req *LoginRequest,
 ) (*domain.User, bool) {
-	user, passwordHash, err := lp.repo.GetUserByUsername(ctx, req.Username)
+	user, passwordHash, err := lp.repo.GetUserByUsername(r.Context(), req.Username)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+		if errors.Is(err, repository.ErrNotFound) {
 			respondJSON(w, http.StatusOK, LoginResponse{
 				Success: false,
 				Message: "Invalid username or password",
 			})
 			return nil, false
 		}
-		respondErr(w, http.StatusInternalServerError, "login failed")
+		respondErr(w, r, http.StatusInternalServerError, "login failed")
 		return nil, false
 	}
 
@@ -152,7 +207,7 @@ func (lp *LoginProcessor) createSession(
 ) (*domain.Session, bool) {
 	session, err := lp.repo.CreateSession(r.Context(), user.ID)
 	if err != nil {
-		respondErr(w, http.StatusInternalServerError, "failed to create session")
+		respondErr(w, r, http.StatusInternalServerError, "failed to create session")
 		return nil, false
 	}
 
@@ -173,13 +228,13 @@ func (lp *LoginProcessor) ProcessLogin(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Parse and validate credentials
-	req, ok := lp.parseCredentials(w, body)
+	req, ok := lp.parseCredentials(w, r, body)
 	if !ok {
 		return
 	}
 
 	// Authenticate user
-	user, ok := lp.authenticateUser(r.Context(), w, req)
+	user, ok := lp.authenticateUser(w, r, req)
 	if !ok {
 		return
 	}
@@ -225,16 +280,13 @@ func handleUpdatePassword(repo *repository.Repository) http.HandlerFunc {
 			return // Response already sent
 		}
 
-		var req struct {
-			CurrentPassword string `json:"current_password"`
-			NewPassword     string `json:"new_password"`
-		}
+		var req updatePasswordRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			respondErr(w, http.StatusBadRequest, invalidBodyMsg)
+			respondErr(w, r, http.StatusBadRequest, invalidBodyMsg)
 			return
 		}
 
-		if err := validatePasswordChangeInput(w, req.CurrentPassword, req.NewPassword); err != nil {
+		if err := validatePasswordChangeInput(w, r, req.CurrentPassword, req.NewPassword); err != nil {
 			return // Response already sent
 		}
 
@@ -243,7 +295,7 @@ func handleUpdatePassword(repo *repository.Repository) http.HandlerFunc {
 		}
 
 		if err := repo.UpdateUserPassword(r.Context(), session.UserID, req.NewPassword); err != nil {
-			respondErr(w, http.StatusInternalServerError, "failed to update password")
+			respondErr(w, r, http.StatusInternalServerError, "failed to update password")
 			return
 		}
 
@@ -259,13 +311,13 @@ func validatePasswordUpdateRequest(
 ) (*domain.Session, error) {
 	sessionID := getSessionFromRequest(r)
 	if sessionID == "" {
-		respondErr(w, http.StatusUnauthorized, errNotAuthenticated)
+		respondErr(w, r, http.StatusUnauthorized, errNotAuthenticated)
 		return nil, errors.New("no session")
 	}
 
 	session, err := repo.GetSession(r.Context(), sessionID)
 	if err != nil {
-		respondErr(w, http.StatusUnauthorized, errInvalidSession)
+		respondErr(w, r, http.StatusUnauthorized, errInvalidSession)
 		return nil, err
 	}
 
@@ -273,14 +325,18 @@ func validatePasswordUpdateRequest(
 }
 
 // validatePasswordChangeInput checks password requirements
-func validatePasswordChangeInput(w http.ResponseWriter, currentPassword, newPassword string) error {
+func validatePasswordChangeInput(
+	w http.ResponseWriter,
+	r *http.Request,
+	currentPassword, newPassword string,
+) error {
 	if currentPassword == "" || newPassword == "" {
-		respondErr(w, http.StatusBadRequest, "current password and new password required")
+		respondErr(w, r, http.StatusBadRequest, "current password and new password required")
 		return errors.New("empty passwords")
 	}
 
 	if len(newPassword) < 6 {
-		respondErr(w, http.StatusBadRequest, "new password must be at least 6 characters long")
+		respondErr(w, r, http.StatusBadRequest, "new password must be at least 6 characters long")
 		return errors.New("password too short")
 	}
 
@@ -297,12 +353,12 @@ func verifyCurrentPassword(
 ) error {
 	_, passwordHash, err := repo.GetUserByID(r.Context(), userID)
 	if err != nil {
-		respondErr(w, http.StatusInternalServerError, "failed to get user")
+		respondErr(w, r, http.StatusInternalServerError, "failed to get user")
 		return err
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(currentPassword)); err != nil {
-		respondErr(w, http.StatusBadRequest, "current password is incorrect")
+		respondErr(w, r, http.StatusBadRequest, "current password is incorrect")
 		return err
 	}
 
@@ -312,28 +368,24 @@ func verifyCurrentPassword(
 // handleRegister processes user registration requests
 func handleRegister(repo *repository.Repository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var req struct {
-			Username string `json:"username"`
-			Password string `json:"password"`
-			Email    string `json:"email"`
-		}
+		var req registerRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			respondErr(w, http.StatusBadRequest, invalidBodyMsg)
+			respondErr(w, r, http.StatusBadRequest, invalidBodyMsg)
 			return
 		}
 
 		if req.Username == "" || req.Password == "" {
-			respondErr(w, http.StatusBadRequest, "username and password required")
+			respondErr(w, r, http.StatusBadRequest, "username and password required")
 			return
 		}
 
 		user, err := repo.CreateUser(r.Context(), req.Username, req.Password, req.Email)
 		if err != nil {
-			if strings.Contains(err.Error(), "UNIQUE constraint failed") {
-				respondErr(w, http.StatusConflict, "username already exists")
+			if errors.Is(err, repository.ErrConflict) {
+				respondErr(w, r, http.StatusConflict, "username already exists")
 				return
 			}
-			respondErr(w, http.StatusInternalServerError, "failed to create user")
+			respondErr(w, r, http.StatusInternalServerError, "failed to create user")
 			return
 		}
 
@@ -346,27 +398,27 @@ func handleMe(repo *repository.Repository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		sessionID := getSessionFromRequest(r)
 		if sessionID == "" {
-			respondErr(w, http.StatusUnauthorized, errNotAuthenticated)
+			respondErr(w, r, http.StatusUnauthorized, errNotAuthenticated)
 			return
 		}
 		session, err := repo.GetSession(r.Context(), sessionID)
 		if err != nil {
-			respondErr(w, http.StatusUnauthorized, errInvalidSession)
+			respondErr(w, r, http.StatusUnauthorized, errInvalidSession)
 			return
 		}
 
 		user, _, err := repo.GetUserByID(r.Context(), session.UserID)
 		if err != nil || user == nil {
-			respondErr(w, http.StatusInternalServerError, "failed to load user")
+			respondErr(w, r, http.StatusInternalServerError, "failed to load user")
 			return
 		}
 
 		// Refresh cookie to keep browser session fresh
 		http.SetCookie(w, buildSessionCookie(r, sessionID, 24*60*60))
 
-		respondJSON(w, http.StatusOK, map[string]any{
-			"success": true,
-			"user":    user,
+		respondJSON(w, http.StatusOK, meResponse{
+			Success: true,
+			User:    user,
 		})
 	}
 }
@@ -0,0 +1,241 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/repository"
+	"github.com/mdco1990/webapp/internal/security"
+)
+
+// batchOperation is the wire form of domain.BatchOperation; Data holds the
+// create or update payload for Type.
+type batchOperation struct {
+	Op      string          `json:"op" openapi:"required,enum=create|update|delete"`
+	Type    string          `json:"type" openapi:"required,enum=income_source|budget_source|expense|manual_budget_item"`
+	ID      int64           `json:"id,omitempty"`
+	Version int64           `json:"version,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// batchRequest is the body of POST /batch.
+type batchRequest struct {
+	Operations []batchOperation `json:"operations" openapi:"required"`
+}
+
+// batchResponse reports the applied operations in order.
+type batchResponse struct {
+	Results []domain.BatchResult `json:"results"`
+}
+
+// registerBatchEndpoints wires the multi-operation write endpoint
+func registerBatchEndpoints(api *Routes, repo *repository.Repository) {
+	api.Add(
+		route(http.MethodPost, "/batch", handleBatch(repo)).
+			WithTag("Utilities").
+			WithSummary("Apply several writes at once").
+			WithDescription("Applies an ordered list of create, update and delete operations on income sources, "+
+				"budget sources, expenses and manual budget items in one transaction. Either every operation "+
+				"is applied or none is. All operations are validated first; a failure is answered with the "+
+				"status the single write would have returned, and its `errors[0].field` names the failing "+
+				"operation (`operations[2]`, or a payload field such as `operations[2].data.name`).").
+			WithRequest(batchRequest{}).
+			WithResponse(http.StatusOK, batchResponse{}),
+	)
+}
+
+// handleBatch applies an ordered list of writes all-or-nothing. Every
+// operation is validated before any is applied; if one then fails, the batch
+// is rolled back and the problem names it (operations[i]).
+func handleBatch(repo *repository.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req batchRequest
+		r.Body = http.MaxBytesReader(w, r.Body, security.MaxRequestBodySize)
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondErr(w, r, http.StatusBadRequest, invalidBodyMsg)
+			return
+		}
+		if len(req.Operations) == 0 || len(req.Operations) > domain.MaxBatchOperations {
+			respondError(w, r, domain.Invalid(
+				fmt.Sprintf("operations must hold 1 to %d operations", domain.MaxBatchOperations),
+				domain.FieldError{Field: "operations", Message: "wrong number of operations"}))
+			return
+		}
+
+		ops := make([]domain.BatchOperation, len(req.Operations))
+		for i, in := range req.Operations {
+			op, err := parseBatchOperation(in)
+			if err != nil {
+				respondError(w, r, batchOperationError("operations", i, err))
+				return
+			}
+			ops[i] = op
+		}
+
+		results, err := repo.ApplyBatch(r.Context(), getUserIDFromContext(r.Context()), ops)
+		if err != nil {
+			var be *domain.BatchError
+			if errors.As(err, &be) {
+				err = batchOperationError("operations", be.Index, err)
+			}
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, batchResponse{Results: results})
+	}
+}
+
+// parseBatchOperation checks an operation's shape and decodes and validates
+// its payload.
+func parseBatchOperation(in batchOperation) (domain.BatchOperation, error) {
+	switch in.Op {
+	case domain.BatchCreate:
+		if in.ID != 0 {
+			return domain.BatchOperation{}, security.ValidationError{Field: "id", Message: "id must not be set on create"}
+		}
+	case domain.BatchUpdate, domain.BatchDelete:
+		if in.ID <= 0 {
+			return domain.BatchOperation{}, security.ValidationError{Field: "id", Message: "id is required"}
+		}
+	}
+	return decodeBatchOperation(in)
+}
+
+// decodeBatchOperation checks an operation's op, type and version and
+// decodes and validates its payload, leaving the ID to the caller.
+func decodeBatchOperation(in batchOperation) (domain.BatchOperation, error) {
+	op := domain.BatchOperation{Op: in.Op, Type: in.Type, ID: in.ID, Version: in.Version}
+	switch in.Op {
+	case domain.BatchCreate, domain.BatchUpdate, domain.BatchDelete:
+	default:
+		return op, security.ValidationError{Field: "op", Message: "op must be create, update or delete"}
+	}
+	isSource := in.Type == domain.BatchIncomeSource || in.Type == domain.BatchBudgetSource
+	if in.Version < 0 || (in.Version != 0 && (!isSource || in.Op == domain.BatchCreate)) {
+		return op, security.ValidationError{
+			Field:   "version",
+			Message: "version is only supported when updating or deleting sources",
+		}
+	}
+
+	var err error
+	switch in.Type + ":" + in.Op {
+	case domain.BatchIncomeSource + ":" + domain.BatchCreate:
+		var req domain.CreateIncomeSourceRequest
+		if err = decodeBatchData(in.Data, &req); err == nil {
+			op.IncomeSource, err = security.ValidateCreateIncomeSourceRequest(req)
+		}
+	case domain.BatchBudgetSource + ":" + domain.BatchCreate:
+		var req domain.CreateBudgetSourceRequest
+		if err = decodeBatchData(in.Data, &req); err == nil {
+			op.BudgetSource, err = security.ValidateCreateBudgetSourceRequest(req)
+		}
+	case domain.BatchIncomeSource + ":" + domain.BatchUpdate, domain.BatchBudgetSource + ":" + domain.BatchUpdate:
+		var req domain.UpdateSourceRequest
+		if err = decodeBatchData(in.Data, &req); err == nil {
+			op.Source, err = security.ValidateUpdateSourceRequest(req)
+		}
+	case domain.BatchExpense + ":" + domain.BatchCreate:
+		var req struct {
+			Year        int    `json:"year"`
+			Month       int    `json:"month"`
+			Category    string `json:"category"`
+			Description string `json:"description"`
+			AmountCents int64  `json:"amount_cents"`
+		}
+		if err = decodeBatchData(in.Data, &req); err == nil {
+			op.Expense, err = security.ValidateExpense(&domain.Expense{
+				YearMonth:   domain.YearMonth{Year: req.Year, Month: req.Month},
+				Category:    req.Category,
+				Description: req.Description,
+				AmountCents: domain.Money(req.AmountCents),
+			})
+		}
+	case domain.BatchManualBudgetItem + ":" + domain.BatchCreate, domain.BatchManualBudgetItem + ":" + domain.BatchUpdate:
+		var req domain.ManualBudgetItemRequest
+		if err = decodeBatchData(in.Data, &req); err == nil {
+			op.Item, err = validateManualBudgetItemRequest(req, in.Op == domain.BatchCreate)
+		}
+	case domain.BatchIncomeSource + ":" + domain.BatchDelete,
+		domain.BatchBudgetSource + ":" + domain.BatchDelete,
+		domain.BatchExpense + ":" + domain.BatchDelete,
+		domain.BatchManualBudgetItem + ":" + domain.BatchDelete:
+	case domain.BatchExpense + ":" + domain.BatchUpdate:
+		err = security.ValidationError{Field: "op", Message: "expenses cannot be updated"}
+	default:
+		err = security.ValidationError{
+			Field:   "type",
+			Message: "type must be income_source, budget_source, expense or manual_budget_item",
+		}
+	}
+	return op, err
+}
+
+// decodeBatchData decodes an operation payload, rejecting unknown fields.
+func decodeBatchData(data json.RawMessage, v any) error {
+	if len(data) == 0 {
+		return security.ValidationError{Field: "data", Message: "data is required"}
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return security.ValidationError{Field: "data", Message: "data is not a valid payload for this type", Err: err}
+	}
+	return nil
+}
+
+// validateManualBudgetItemRequest validates an item payload; the month is only
+// needed to create an item.
+func validateManualBudgetItemRequest(
+	req domain.ManualBudgetItemRequest,
+	create bool,
+) (*domain.ManualBudgetItemRequest, error) {
+	if create {
+		if err := security.ValidateYearMonth(domain.YearMonth{Year: req.Year, Month: req.Month}); err != nil {
+			return nil, err
+		}
+	}
+	name, err := security.ValidateName(req.Name, "name")
+	if err != nil {
+		return nil, err
+	}
+	if err := security.ValidateAmount(req.AmountCents, "amount_cents"); err != nil {
+		return nil, err
+	}
+	return &domain.ManualBudgetItemRequest{
+		Year: req.Year, Month: req.Month, Name: name, AmountCents: req.AmountCents,
+	}, nil
+}
+
+// batchOperationFields are the fields of batchOperation and syncMutation;
+// other validation errors are about the payload.
+var batchOperationFields = map[string]bool{
+	"op": true, "type": true, "id": true, "version": true, "data": true, "client_id": true, "cursor": true, "ref": true,
+}
+
+// batchOperationError attributes err to operation i of list, keeping its
+// kind and code so the batch answers with the status the single write would
+// have.
+func batchOperationError(list string, i int, err error) error {
+	path := fmt.Sprintf("%s[%d]", list, i)
+	var ve security.ValidationError
+	if errors.As(err, &ve) {
+		field := path + "." + ve.Field
+		if !batchOperationFields[ve.Field] {
+			field = path + ".data." + ve.Field
+		}
+		return domain.Invalid(path+": "+ve.Message, domain.FieldError{Field: field, Message: ve.Message}).WithCause(ve.Err)
+	}
+	var de *domain.Error
+	if !errors.As(err, &de) {
+		return err
+	}
+	c := *de
+	c.Message = path + ": " + de.Message
+	c.Fields = append([]domain.FieldError{{Field: path, Message: de.Message}}, de.Fields...)
+	return &c
+}
@@ -0,0 +1,97 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/middleware"
+	"github.com/mdco1990/webapp/internal/repository"
+)
+
+func TestBatchEndpoint(t *testing.T) {
+	database := setupTestDB(t)
+	defer func() { _ = database.Close() }()
+	database.SetMaxOpenConns(1)
+	repo := repository.New(database)
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userIDKey, int64(1))))
+		})
+	})
+	registerBatchEndpoints(NewRoutes(r, NewOpenAPIDocument()), repo)
+	post := func(body string) (*httptest.ResponseRecorder, middleware.Problem) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(body)))
+		var p middleware.Problem
+		if w.Code != http.StatusOK {
+			_ = json.Unmarshal(w.Body.Bytes(), &p)
+		}
+		return w, p
+	}
+
+	w, _ := post(`{"operations":[
+		{"op":"create","type":"income_source","data":{"name":"Salary","year":2024,"month":3,"amount_cents":250000}},
+		{"op":"create","type":"expense","data":{"year":2024,"month":3,"description":"Coffee","amount_cents":350}},
+		{"op":"create","type":"manual_budget_item","data":{"year":2024,"month":3,"name":"Groceries","amount_cents":40000}}
+	]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Results []domain.BatchResult `json:"results"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || len(resp.Results) != 3 {
+		t.Fatalf("Expected 3 results, got %s", w.Body.String())
+	}
+	incomeID := resp.Results[0].ID
+
+	// A stale version fails the whole batch with the single-write status.
+	w, p := post(`{"operations":[
+		{"op":"create","type":"budget_source","data":{"name":"Rent","year":2024,"month":3,"amount_cents":90000}},
+		{"op":"update","type":"income_source","id":` + jsonInt(incomeID) + `,"version":7,"data":{"name":"Salary","amount_cents":1}}
+	]}`)
+	if w.Code != http.StatusPreconditionFailed || p.Code != "version_mismatch" {
+		t.Errorf("Expected 412 version_mismatch, got %d %s", w.Code, w.Body.String())
+	}
+	if len(p.Errors) == 0 || p.Errors[0].Field != "operations[1]" {
+		t.Errorf("Expected the error to name operations[1], got %+v", p.Errors)
+	}
+	budgets, err := repo.ListBudgetSources(context.Background(), 1, domain.YearMonth{Year: 2024, Month: 3})
+	if err != nil || len(budgets) != 0 {
+		t.Errorf("Expected the failed batch to create nothing, got %d budget sources (%v)", len(budgets), err)
+	}
+
+	tests := []struct {
+		body  string
+		field string
+	}{
+		{`{"operations":[]}`, "operations"},
+		{`{"operations":[{"op":"upsert","type":"expense"}]}`, "operations[0].op"},
+		{`{"operations":[{"op":"delete","type":"expense"}]}`, "operations[0].id"},
+		{`{"operations":[{"op":"update","type":"expense","id":1,"data":{}}]}`, "operations[0].op"},
+		{`{"operations":[{"op":"create","type":"income_source"}]}`, "operations[0].data"},
+		{`{"operations":[{"op":"delete","type":"expense","id":1,"version":2}]}`, "operations[0].version"},
+		{`{"operations":[{"op":"delete","type":"expense","id":1},` +
+			`{"op":"create","type":"budget_source","data":{"name":"","year":2024,"month":3}}]}`,
+			"operations[1].data.name"},
+	}
+	for _, tt := range tests {
+		w, p := post(tt.body)
+		if w.Code != http.StatusBadRequest || len(p.Errors) == 0 || p.Errors[0].Field != tt.field {
+			t.Errorf("%s: Expected 400 on %s, got %d %s", tt.body, tt.field, w.Code, w.Body.String())
+		}
+	}
+}
+
+func jsonInt(n int64) string {
+	b, _ := json.Marshal(n)
+	return string(b)
+}
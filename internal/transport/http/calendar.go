@@ -0,0 +1,149 @@
+package httpapi
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mdco1990/webapp/internal/calendar"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/repository"
+)
+
+// Calendar feed settings.
+const (
+	calendarReminderSetting = "calendar_reminder_days"
+	defaultReminderDays     = 1
+	maxReminderDays         = 30
+	calendarMonthsBack      = 1
+	calendarMonthsAhead     = 12
+	calendarRefresh         = 6 * time.Hour
+)
+
+var feedTokenPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// registerCalendarEndpoints wires calendar feed token management
+func registerCalendarEndpoints(api *Routes, repo *repository.Repository) {
+	api.Tagged("Sharing").Add(
+		route(http.MethodPost, "/calendar/token", handleCreateCalendarToken(repo)).
+			WithSummary("Create calendar feed URL").
+			WithDescription("Issues the user's iCalendar feed URL of pay and due days, revoking any previous one.").
+			WithResponse(http.StatusCreated, calendarTokenResponse{}),
+		route(http.MethodDelete, "/calendar/token", handleDeleteCalendarToken(repo)).
+			WithSummary("Revoke calendar feed URL").
+			WithResponse(http.StatusOK, statusResponse{}),
+	)
+}
+
+// registerCalendarFeedRoutes wires the public, token-protected .ics feed.
+// Calendar apps cannot send session cookies or API keys, so the unguessable
+// token in the URL is the only credential.
+func registerCalendarFeedRoutes(routes *Routes, repo *repository.Repository) {
+	routes.Add(
+		route(http.MethodGet, "/calendar/{file}", handleCalendarFeed(repo)).
+			WithTag("Sharing").
+			WithSummary("Calendar feed").
+			WithParams(pathParam("file", &Schema{Type: "string", Description: "<token>.ics"})).
+			WithRawResponse(http.StatusOK, "text/calendar"),
+	)
+}
+
+// calendarTokenResponse holds a new calendar feed URL.
+type calendarTokenResponse struct {
+	Token     string `json:"token"`
+	URL       string `json:"url"`
+	WebcalURL string `json:"webcal_url"`
+}
+
+// handleCreateCalendarToken issues (or rotates) the user's feed URL
+func handleCreateCalendarToken(repo *repository.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := getUserIDFromContext(r.Context())
+		token, err := repo.CreateCalendarToken(r.Context(), userID)
+		if err != nil {
+			respondErr(w, r, http.StatusInternalServerError, "failed to create calendar token")
+			return
+		}
+		url := feedURL(r, token)
+		respondJSON(w, http.StatusCreated, calendarTokenResponse{
+			Token:     token,
+			URL:       url,
+			WebcalURL: "webcal://" + strings.SplitN(url, "://", 2)[1],
+		})
+	}
+}
+
+// handleDeleteCalendarToken revokes the user's feed URL
+func handleDeleteCalendarToken(repo *repository.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := getUserIDFromContext(r.Context())
+		if err := repo.DeleteCalendarToken(r.Context(), userID); err != nil {
+			respondErr(w, r, http.StatusInternalServerError, "failed to revoke calendar token")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
+
+// handleCalendarFeed serves /calendar/{token}.ics
+func handleCalendarFeed(repo *repository.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutSuffix(chi.URLParam(r, "file"), ".ics")
+		if !ok || !feedTokenPattern.MatchString(token) {
+			respondErr(w, r, http.StatusNotFound, "not found")
+			return
+		}
+		userID, err := repo.GetUserIDByCalendarToken(r.Context(), token)
+		if errors.Is(err, repository.ErrNotFound) {
+			respondErr(w, r, http.StatusNotFound, "not found")
+			return
+		}
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+
+		now := time.Now().UTC()
+		month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		start := month.AddDate(0, -calendarMonthsBack, 0)
+		end := month.AddDate(0, calendarMonthsAhead, 0)
+		payments, err := repo.ListScheduledPayments(r.Context(), userID,
+			domain.YearMonth{Year: start.Year(), Month: int(start.Month())},
+			domain.YearMonth{Year: end.Year(), Month: int(end.Month())})
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+
+		reminder := defaultReminderDays
+		if settings, err := repo.GetUserSettings(r.Context(), userID); err == nil {
+			if v, err := strconv.Atoi(settings[calendarReminderSetting]); err == nil {
+				reminder = min(v, maxReminderDays) // negative disables reminders
+			}
+		}
+
+		feed := calendar.BuildFeed(payments, calendar.FeedOptions{
+			Format:       resolveExportFormat(r, repo, userID),
+			ReminderDays: reminder,
+			UIDDomain:    calendar.DefaultUIDDomain,
+			Refresh:      calendarRefresh,
+		})
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", `inline; filename="budget.ics"`)
+		w.Header().Set("Cache-Control", "private, max-age=300")
+		w.WriteHeader(http.StatusOK)
+		if err := calendar.Write(w, feed); err != nil {
+			slog.Error("calendar feed write failed", "err", err)
+		}
+	}
+}
+
+// feedURL builds the absolute subscription URL for a token.
+func feedURL(r *http.Request, token string) string {
+	return requestBaseURL(r) + "/calendar/" + token + ".ics"
+}
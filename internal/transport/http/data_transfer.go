@@ -0,0 +1,200 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/repository"
+	"github.com/mdco1990/webapp/internal/security"
+	"github.com/mdco1990/webapp/internal/service"
+)
+
+const (
+	maxSettingValueLength = 256
+	errTaskNotFound       = "task not found"
+	// maxArchiveUploadSize caps an uploaded archive, which may be far
+	// larger than the request bodies the API otherwise accepts.
+	maxArchiveUploadSize = 64 << 20
+)
+
+var settingKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// registerDataTransferEndpoints wires account export/import, task status and settings endpoints
+func registerDataTransferEndpoints(
+	api *Routes,
+	repo *repository.Repository,
+	bg *service.BackgroundService,
+) {
+	taskID := pathParam("id", &Schema{Type: "string", Description: "Task ID"})
+	api.Tagged("Import and Export").Add(
+		route(http.MethodPost, "/data/export", handleStartDataExport(bg)).
+			WithSummary("Start a full account export").
+			WithResponse(http.StatusAccepted, taskResponse{}),
+		route(http.MethodGet, "/data/export/{id}/download", handleDownloadDataExport(bg)).
+			WithSummary("Download a finished account export").
+			WithDescription("The archive can be downloaded for an hour after the export finishes.").
+			WithParams(taskID).
+			WithRawResponse(http.StatusOK, "application/zip"),
+		route(http.MethodPost, "/data/import", handleStartDataImport(bg)).
+			WithSummary("Start an account import").
+			WithDescription("Accepts an archive produced by an export, of up to 64 MiB.").
+			WithParams(
+				queryParam("mode", enumSchema(string(service.ImportModeMerge), string(service.ImportModeRestore)),
+					"merge (default) adds to the account; restore replaces it"),
+				queryParam("dry_run", boolSchema(), "Only report what the import would do"),
+			).
+			WithRawRequest("application/zip").
+			WithResponse(http.StatusAccepted, taskResponse{}),
+		route(http.MethodGet, "/tasks/{id}", handleGetTask(bg)).
+			WithSummary("Get a background task").
+			WithParams(taskID).
+			WithResponse(http.StatusOK, service.BackgroundTask{}),
+		route(http.MethodGet, "/settings", handleGetSettings(repo)).
+			WithSummary("Get user settings").
+			WithResponse(http.StatusOK, domain.UserSettings{}),
+		route(http.MethodPut, "/settings", handleUpdateSettings(repo)).
+			WithSummary("Update user settings").
+			WithRequest(domain.UserSettings{}).
+			WithResponse(http.StatusOK, statusResponse{}),
+	)
+}
+
+// taskResponse identifies a queued background task.
+type taskResponse struct {
+	TaskID string `json:"task_id"`
+}
+
+// handleStartDataExport queues a full account export and returns the task ID
+func handleStartDataExport(bg *service.BackgroundService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := getUserIDFromContext(r.Context())
+		taskID, err := bg.ProcessDataExportAsync(r.Context(), userID)
+		if err != nil {
+			respondErr(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondJSON(w, http.StatusAccepted, taskResponse{TaskID: taskID})
+	}
+}
+
+// handleDownloadDataExport streams the archive produced by a completed export task
+func handleDownloadDataExport(bg *service.BackgroundService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := getUserIDFromContext(r.Context())
+		taskID := chi.URLParam(r, "id")
+		task, err := bg.GetUserTask(taskID, userID)
+		if err != nil || task.Type != service.TaskTypeDataExport {
+			respondErr(w, r, http.StatusNotFound, errTaskNotFound)
+			return
+		}
+		archive, err := bg.GetTaskArtifact(taskID, userID)
+		if err != nil {
+			respondErr(w, r, http.StatusConflict, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition",
+			fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("webapp-export-%s.zip", taskID)))
+		w.Header().Set("Content-Length", strconv.Itoa(len(archive)))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(archive)
+	}
+}
+
+// handleStartDataImport validates an uploaded archive and queues its import.
+// Query: mode=merge|restore (default merge), dry_run=true to only report.
+func handleStartDataImport(bg *service.BackgroundService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := getUserIDFromContext(r.Context())
+		mode, err := service.ParseImportMode(r.URL.Query().Get("mode"))
+		if err != nil {
+			respondErr(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+
+		security.LimitRequestBody(w, r, maxArchiveUploadSize)
+		archive, err := io.ReadAll(r.Body)
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			respondErr(w, r, http.StatusRequestEntityTooLarge, "archive too large")
+			return
+		case err != nil:
+			respondErr(w, r, http.StatusBadRequest, "failed to read archive")
+			return
+		}
+
+		taskID, err := bg.ProcessDataImportAsync(r.Context(), userID, archive, mode, dryRun)
+		switch {
+		case errors.Is(err, service.ErrArchiveInvalid),
+			errors.Is(err, service.ErrArchiveChecksum),
+			errors.Is(err, service.ErrArchiveVersion):
+			respondErr(w, r, http.StatusUnprocessableEntity, err.Error())
+			return
+		case err != nil:
+			respondErr(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondJSON(w, http.StatusAccepted, taskResponse{TaskID: taskID})
+	}
+}
+
+// handleGetTask returns the status (and result, once done) of one of the caller's tasks
+func handleGetTask(bg *service.BackgroundService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := getUserIDFromContext(r.Context())
+		task, err := bg.GetUserTask(chi.URLParam(r, "id"), userID)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, task)
+	}
+}
+
+// handleGetSettings returns the user's settings
+func handleGetSettings(repo *repository.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		settings, err := repo.GetUserSettings(r.Context(), getUserIDFromContext(r.Context()))
+		if err != nil {
+			respondErr(w, r, http.StatusInternalServerError, "failed to get settings")
+			return
+		}
+		respondJSON(w, http.StatusOK, settings)
+	}
+}
+
+// handleUpdateSettings upserts the provided settings keys
+func handleUpdateSettings(repo *repository.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := getUserIDFromContext(r.Context())
+		var req domain.UserSettings
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondErr(w, r, http.StatusBadRequest, invalidBodyMsg)
+			return
+		}
+		for k, v := range req {
+			if !settingKeyPattern.MatchString(k) {
+				respondErr(w, r, http.StatusBadRequest, fmt.Sprintf("invalid setting key %q", k))
+				return
+			}
+			if len(v) > maxSettingValueLength {
+				respondErr(w, r, http.StatusBadRequest, fmt.Sprintf("setting %q is too long", k))
+				return
+			}
+		}
+		if err := repo.SetUserSettings(r.Context(), userID, req); err != nil {
+			respondErr(w, r, http.StatusInternalServerError, "failed to save settings")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
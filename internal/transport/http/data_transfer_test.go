@@ -0,0 +1,76 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/repository"
+	"github.com/mdco1990/webapp/internal/security"
+	"github.com/mdco1990/webapp/internal/service"
+)
+
+func TestImportArchiveSizeLimit(t *testing.T) {
+	database := setupTestDB(t)
+	defer func() { _ = database.Close() }()
+	database.SetMaxOpenConns(1)
+	bg := service.NewBackgroundService(repository.New(database))
+
+	r := chi.NewRouter()
+	r.Use(security.RequestSizeLimit(security.MaxRequestBodySize))
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userIDKey, int64(1))))
+		})
+	})
+	registerDataTransferEndpoints(NewRoutes(r, NewOpenAPIDocument()), repository.New(database), bg)
+	upload := func(body []byte) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/data/import?dry_run=true", bytes.NewReader(body)))
+		return w
+	}
+
+	// An export larger than the API's default body limit imports back.
+	data := &domain.UserDataSet{}
+	for range 40000 {
+		b := make([]byte, 32)
+		_, _ = rand.Read(b)
+		data.Expenses = append(data.Expenses, domain.Expense{
+			YearMonth: domain.YearMonth{Year: 2024, Month: 1}, Description: hex.EncodeToString(b), AmountCents: 100,
+		})
+	}
+	archive, _, err := service.BuildArchive(1, data, time.Now())
+	if err != nil {
+		t.Fatalf("BuildArchive failed: %v", err)
+	}
+	if len(archive) <= security.MaxRequestBodySize {
+		t.Fatalf("Expected an archive over %d bytes, got %d", security.MaxRequestBodySize, len(archive))
+	}
+	w := upload(archive)
+	var queued taskResponse
+	if w.Code != http.StatusAccepted || json.Unmarshal(w.Body.Bytes(), &queued) != nil {
+		t.Fatalf("Expected 202, got %d %s", w.Code, w.Body.String())
+	}
+	for {
+		task, err := bg.GetUserTask(queued.TaskID, 1)
+		if err != nil || task.Status == service.TaskStatusFailed {
+			t.Fatalf("Expected the dry run to succeed, got %+v (%v)", task, err)
+		}
+		if task.Status == service.TaskStatusCompleted {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if w := upload(make([]byte, maxArchiveUploadSize+1)); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413 over the archive limit, got %d", w.Code)
+	}
+}
@@ -0,0 +1,55 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// ETags are the quoted record version ("3"). Writes bump the version, so a
+// client that sends back the ETag it read in If-Match gets 412 instead of
+// overwriting someone else's change.
+
+// ifMatchParam documents the If-Match header read by ifMatchVersion.
+var ifMatchParam = headerParam("If-Match",
+	`ETag from a previous GET or write (the quoted record version, e.g. "3"). `+
+		"The write fails with 412 if the record has changed since. Omit or send * to write unconditionally.")
+
+// setETag sets the ETag header for a record version. Version 0 (never saved)
+// has no ETag.
+func setETag(w http.ResponseWriter, version int64) {
+	if version > 0 {
+		w.Header().Set("ETag", `"`+strconv.FormatInt(version, 10)+`"`)
+	}
+}
+
+// ifMatchVersion returns the version named by the If-Match header, or 0 when
+// the header is absent or "*" and the write is unconditional. Tags this
+// server did not issue (weak, non-numeric) can never match and fail the
+// precondition.
+func ifMatchVersion(r *http.Request) (int64, error) {
+	h := strings.TrimSpace(r.Header.Get("If-Match"))
+	if h == "" || h == "*" {
+		return 0, nil
+	}
+	if strings.Contains(h, ",") {
+		return 0, domain.Invalid("If-Match must name a single entity tag",
+			domain.FieldError{Field: "If-Match", Message: "must be one entity tag"})
+	}
+	if strings.HasPrefix(h, "W/") {
+		return 0, errStaleETag
+	}
+	if len(h) < 2 || h[0] != '"' || h[len(h)-1] != '"' {
+		return 0, domain.Invalid("If-Match must be a quoted entity tag",
+			domain.FieldError{Field: "If-Match", Message: "must be a quoted entity tag"})
+	}
+	v, err := strconv.ParseInt(h[1:len(h)-1], 10, 64)
+	if err != nil || v <= 0 {
+		return 0, errStaleETag
+	}
+	return v, nil
+}
+
+var errStaleETag = domain.PreconditionFailed("version_mismatch", "entity tag does not match the current version")
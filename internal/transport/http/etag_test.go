@@ -0,0 +1,103 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/repository"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		header string
+		want   int64
+		kind   error
+	}{
+		{"", 0, nil},
+		{"*", 0, nil},
+		{`"7"`, 7, nil},
+		{` "12" `, 12, nil},
+		{`W/"7"`, 0, domain.ErrPreconditionFailed},
+		{`"abc"`, 0, domain.ErrPreconditionFailed},
+		{`"0"`, 0, domain.ErrPreconditionFailed},
+		{`7`, 0, domain.ErrValidation},
+		{`"1", "2"`, 0, domain.ErrValidation},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPut, "/", nil)
+		if tt.header != "" {
+			req.Header.Set("If-Match", tt.header)
+		}
+		got, err := ifMatchVersion(req)
+		if got != tt.want {
+			t.Errorf("%q: Expected version %d, got %d", tt.header, tt.want, got)
+		}
+		if (tt.kind == nil) != (err == nil) || (tt.kind != nil && !errors.Is(err, tt.kind)) {
+			t.Errorf("%q: Expected error kind %v, got %v", tt.header, tt.kind, err)
+		}
+	}
+}
+
+func TestIncomeSourceETags(t *testing.T) {
+	database := setupTestDB(t)
+	defer func() { _ = database.Close() }()
+	database.SetMaxOpenConns(1)
+	repo := repository.New(database)
+
+	source, err := repo.CreateIncomeSource(context.Background(), 1, domain.CreateIncomeSourceRequest{
+		Name: "Salary", Year: 2024, Month: 1, AmountCents: 100,
+	})
+	if err != nil {
+		t.Fatalf("CreateIncomeSource failed: %v", err)
+	}
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userIDKey, int64(1))))
+		})
+	})
+	registerIncomeSourceEndpoints(NewRoutes(r, NewOpenAPIDocument()), repo)
+	path := "/income-sources/" + strconv.FormatInt(source.ID, 10)
+	do := func(method, ifMatch, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodGet, "", "")
+	if w.Code != http.StatusOK || w.Header().Get("ETag") != `"1"` {
+		t.Fatalf("Expected 200 with ETag \"1\", got %d %q", w.Code, w.Header().Get("ETag"))
+	}
+
+	body := `{"name":"Salary","amount_cents":200}`
+	w = do(http.MethodPut, `"1"`, body)
+	if w.Code != http.StatusOK || w.Header().Get("ETag") != `"2"` {
+		t.Fatalf("Expected 200 with ETag \"2\", got %d %q", w.Code, w.Header().Get("ETag"))
+	}
+
+	// A second tab still holding "1" must not overwrite the change.
+	w = do(http.MethodPut, `"1"`, `{"name":"Salary","amount_cents":300}`)
+	if w.Code != http.StatusPreconditionFailed || !strings.Contains(w.Body.String(), `"version_mismatch"`) {
+		t.Errorf("Expected 412 version_mismatch, got %d %s", w.Code, w.Body.String())
+	}
+	w = do(http.MethodDelete, `"1"`, "")
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("Expected 412 for a stale delete, got %d", w.Code)
+	}
+	w = do(http.MethodDelete, `"2"`, "")
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected 200 for a current delete, got %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,160 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/events"
+	"github.com/mdco1990/webapp/internal/repository"
+	"github.com/mdco1990/webapp/internal/security"
+	"github.com/mdco1990/webapp/internal/service"
+)
+
+const (
+	// changeFeedSize is how many recent changes a reconnecting stream can
+	// resume from.
+	changeFeedSize = 1000
+	// streamHeartbeat is how often an idle stream sends a comment, so proxies
+	// keep it open and clients notice a dead connection.
+	streamHeartbeat = 25 * time.Second
+	// streamRetry is the reconnect delay suggested to clients, in ms.
+	streamRetry = 3000
+	// maxStreamMonths caps the months one stream can watch.
+	maxStreamMonths = 24
+)
+
+// announceChanges publishes the repository's committed writes on bus,
+// attributed to the session's user.
+func announceChanges(repo *repository.Repository, bus *events.EventBus) {
+	announcer := service.NewChangeAnnouncer(repo, bus)
+	repo.OnChange(func(ctx context.Context, c domain.Change) {
+		announcer.Announce(ctx, getUserIDFromContext(ctx), c)
+	})
+}
+
+// newChangeFeed returns a feed of the changes published on bus for event
+// streams.
+func newChangeFeed(bus *events.EventBus) *events.ChangeFeed {
+	feed := events.NewChangeFeed(changeFeedSize)
+	if _, err := feed.Attach(bus); err != nil {
+		slog.Error("failed to attach change feed", "err", err)
+	}
+	return feed
+}
+
+// registerEventStreamEndpoints wires the live updates stream
+func registerEventStreamEndpoints(api *Routes, feed *events.ChangeFeed) {
+	api.Add(
+		route(http.MethodGet, "/events", handleEventStream(feed, streamHeartbeat)).
+			WithTag("Monthly Data").
+			WithSummary("Stream live changes").
+			WithDescription("Server-Sent Events for changes to the given months. Each event is named "+
+				"`entity.action` (for example `income_source.updated`) and carries the change as JSON. "+
+				"Idle streams get a comment every 25 seconds. A client reconnecting with Last-Event-ID "+
+				"receives the changes it missed, or a `reset` event if they are no longer kept, after "+
+				"which it should reload.").
+			WithParams(
+				requiredQueryParam("months", stringSchema(), "Comma-separated months, e.g. 2024-03,2024-04"),
+				headerParam("Last-Event-ID", "ID of the last event received, to resume a stream"),
+			).
+			WithRawResponse(http.StatusOK, "text/event-stream"),
+	)
+}
+
+// handleEventStream streams changes to the requested months as Server-Sent
+// Events, e.g. GET /events?months=2024-03,2024-04. Each event is named
+// entity.action (income_source.updated) and carries a domain.Change; users
+// see changes to their own records and to expenses, which are shared. A
+// client that reconnects with Last-Event-ID gets the changes it missed, or a
+// reset event if they are no longer kept, after which it should reload.
+func handleEventStream(feed *events.ChangeFeed, heartbeat time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		months, err := parseStreamMonths(r.URL.Query().Get("months"))
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		userID := getUserIDFromContext(r.Context())
+		sub := feed.Subscribe(r.Header.Get("Last-Event-ID"), func(c domain.Change) bool {
+			return (c.UserID == 0 || c.UserID == userID) && months[c.YearMonth]
+		})
+		defer sub.Close()
+
+		rc := http.NewResponseController(w)
+		// The stream outlives the server's write timeout.
+		_ = rc.SetWriteDeadline(time.Time{})
+		h := w.Header()
+		h.Set("Content-Type", "text/event-stream")
+		h.Set("Cache-Control", "no-cache")
+		h.Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, "retry: %d\n\n", streamRetry)
+		if sub.Reset {
+			fmt.Fprintf(w, "id: %s\nevent: reset\ndata: {}\n\n", sub.Head)
+		}
+		for _, e := range sub.Replay {
+			writeStreamEvent(w, e)
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-ticker.C:
+				fmt.Fprint(w, ": heartbeat\n\n")
+			case e, ok := <-sub.C:
+				if !ok {
+					return // fell behind; the client resumes on reconnect
+				}
+				writeStreamEvent(w, e)
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeStreamEvent(w http.ResponseWriter, e events.FeedEntry) {
+	data, _ := json.Marshal(e.Change)
+	fmt.Fprintf(w, "id: %s\nevent: %s.%s\ndata: %s\n\n", e.ID, e.Change.Entity, e.Change.Action, data)
+}
+
+// parseStreamMonths parses a comma-separated list of YYYY-MM months.
+func parseStreamMonths(s string) (map[domain.YearMonth]bool, error) {
+	if s == "" {
+		return nil, domain.Invalid("months is required",
+			domain.FieldError{Field: "months", Message: "is required"})
+	}
+	parts := strings.Split(s, ",")
+	if len(parts) > maxStreamMonths {
+		return nil, domain.Invalid(fmt.Sprintf("at most %d months can be watched", maxStreamMonths),
+			domain.FieldError{Field: "months", Message: fmt.Sprintf("must list at most %d months", maxStreamMonths)})
+	}
+	months := make(map[domain.YearMonth]bool, len(parts))
+	for _, p := range parts {
+		t, err := time.Parse("2006-01", strings.TrimSpace(p))
+		if err != nil {
+			return nil, domain.Invalid("months must be YYYY-MM",
+				domain.FieldError{Field: "months", Message: "must be YYYY-MM"})
+		}
+		ym := domain.YearMonth{Year: t.Year(), Month: int(t.Month())}
+		if err := security.ValidateYearMonth(ym); err != nil {
+			return nil, err
+		}
+		months[ym] = true
+	}
+	return months, nil
+}
@@ -0,0 +1,143 @@
+package httpapi
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/events"
+	"github.com/mdco1990/webapp/internal/repository"
+)
+
+// streamEvent is one parsed Server-Sent Event.
+type streamEvent struct {
+	id, name, data string
+}
+
+// openStream connects to the event stream and returns a function reading
+// the next event (skipping retry lines and heartbeats).
+func openStream(t *testing.T, url, lastID string) (*http.Response, func() streamEvent) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	lines := bufio.NewScanner(resp.Body)
+	return resp, func() streamEvent {
+		t.Helper()
+		var e streamEvent
+		for lines.Scan() {
+			field, value, _ := strings.Cut(lines.Text(), ": ")
+			switch field {
+			case "id":
+				e.id = value
+			case "event":
+				e.name = value
+			case "data":
+				e.data = value
+			case "":
+				if e.name != "" {
+					return e
+				}
+			}
+		}
+		t.Fatalf("Expected an event, got %v", lines.Err())
+		return e
+	}
+}
+
+func TestEventStream(t *testing.T) {
+	database := setupTestDB(t)
+	defer func() { _ = database.Close() }()
+	database.SetMaxOpenConns(1)
+	repo := repository.New(database)
+	bus := events.NewEventBus(nil)
+	announceChanges(repo, bus)
+	feed := newChangeFeed(bus)
+	ctx := context.Background()
+	other, err := repo.CreateUser(ctx, "other", "password123", "other@example.com")
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userIDKey, int64(1))))
+		})
+	})
+	r.Get("/events", handleEventStream(feed, time.Hour))
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	url := srv.URL + "/events?months=2024-03,2024-05"
+
+	resp, next := openStream(t, url, "")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	march := domain.CreateIncomeSourceRequest{Name: "Salary", Year: 2024, Month: 3, AmountCents: 250000}
+	source, err := repo.CreateIncomeSource(ctx, 1, march)
+	if err != nil {
+		t.Fatalf("CreateIncomeSource failed: %v", err)
+	}
+	first := next()
+	if first.name != "income_source.created" || !strings.Contains(first.data, `"year":2024,"month":3`) {
+		t.Fatalf("Expected income_source.created for March, got %+v", first)
+	}
+
+	// Another user's change and an unwatched month are filtered out;
+	// expenses are shared.
+	if _, err := repo.CreateIncomeSource(ctx, other.ID, march); err != nil {
+		t.Fatalf("CreateIncomeSource failed: %v", err)
+	}
+	april := march
+	april.Month = 4
+	if _, err := repo.CreateIncomeSource(ctx, 1, april); err != nil {
+		t.Fatalf("CreateIncomeSource failed: %v", err)
+	}
+	if _, err := repo.AddExpense(ctx, &domain.Expense{
+		YearMonth: domain.YearMonth{Year: 2024, Month: 5}, Description: "Coffee", AmountCents: 350,
+	}); err != nil {
+		t.Fatalf("AddExpense failed: %v", err)
+	}
+	if e := next(); e.name != "expense.created" {
+		t.Fatalf("Expected expense.created, got %+v", e)
+	}
+	if err := repo.DeleteIncomeSource(ctx, source.ID, 1, 1); err != nil {
+		t.Fatalf("DeleteIncomeSource failed: %v", err)
+	}
+	if e := next(); e.name != "income_source.deleted" {
+		t.Fatalf("Expected income_source.deleted, got %+v", e)
+	}
+
+	// Resuming replays what was missed; an unknown ID asks for a reload.
+	_, next = openStream(t, url, first.id)
+	if e := next(); e.name != "expense.created" {
+		t.Errorf("Expected the expense replayed, got %+v", e)
+	}
+	if e := next(); e.name != "income_source.deleted" {
+		t.Errorf("Expected the deletion replayed, got %+v", e)
+	}
+	_, next = openStream(t, url, "stale-1")
+	if e := next(); e.name != "reset" || e.id == "" {
+		t.Errorf("Expected a reset, got %+v", e)
+	}
+
+	resp, _ = openStream(t, srv.URL+"/events?months=2024-13", "")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid month, got %d", resp.StatusCode)
+	}
+}
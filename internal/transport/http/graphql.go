@@ -0,0 +1,41 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/mdco1990/webapp/internal/repository"
+	"github.com/mdco1990/webapp/internal/service"
+	"github.com/mdco1990/webapp/internal/transport/graph"
+)
+
+// registerGraphQLEndpoints wires the GraphQL endpoint; it sits behind the
+// same API-key, session and idempotency middleware as the REST routes
+func registerGraphQLEndpoints(api *Routes, repo *repository.Repository, svc *service.Service) {
+	handler := graph.NewHandler(repo, svc, getUserIDFromContext, hasScope, graph.DefaultLimits)
+	api.Add(
+		route(http.MethodPost, "/graphql", handler.ServeHTTP).
+			WithTag("Utilities").
+			WithScopesByBody().
+			WithSummary("Run a GraphQL query").
+			WithDescription("Executes a GraphQL query or mutation over the same data as the REST routes. "+
+				"Queries costing more than 5000 or nested deeper than 8 levels are rejected with "+
+				"`query_too_complex` or `query_too_deep` before they run. Access tokens need `read:budget` "+
+				"for queries (`admin` for `users`), `write:expenses` for the expense mutations and `admin` "+
+				"for the others.").
+			WithRequest(graphQLRequest{}).
+			WithResponse(http.StatusOK, graphQLResponse{}),
+	)
+}
+
+// graphQLRequest documents the body of POST /graphql.
+type graphQLRequest struct {
+	Query         string         `json:"query" openapi:"required"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	OperationName string         `json:"operationName,omitempty"`
+}
+
+// graphQLResponse documents the result of POST /graphql.
+type graphQLResponse struct {
+	Data   any              `json:"data,omitempty"`
+	Errors []map[string]any `json:"errors,omitempty"`
+}
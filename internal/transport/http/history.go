@@ -0,0 +1,76 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/repository"
+)
+
+// historyRoutes returns the routes listing and reverting the history of a
+// record of entity, under path (the record's own path, ending in {id}).
+func historyRoutes(repo *repository.Repository, entity, path, noun string) []*RouteBuilder {
+	revertScope := "" // admin, like other writes
+	if entity == domain.BatchExpense {
+		revertScope = domain.ScopeWriteExpenses
+	}
+	return []*RouteBuilder{
+		route(http.MethodGet, path+"/history", handleListHistory(repo, entity)).
+			WithSummary("List "+noun+" history").
+			WithDescription("Lists every write to the "+noun+", newest first: who made it, from which request, "+
+				"the "+noun+" before and after, and the fields that changed. History is kept while the "+
+				noun+" is in the trash.").
+			WithResponse(http.StatusOK, []domain.HistoryEntry{}),
+		route(http.MethodPost, path+"/history/{entry}/revert", handleRevertRecord(repo, entity)).
+			WithScope(revertScope).
+			WithSummary("Revert "+noun+" to a previous version").
+			WithDescription("Sets the "+noun+" back to its state after the history entry, as a new write "+
+				"recorded in its history. Entries that deleted the "+noun+" cannot be reverted to; "+
+				"restore it from the trash instead.").
+			WithParams(ifMatchParam).
+			WithResponse(http.StatusOK, statusResponse{}),
+	}
+}
+
+// handleListHistory lists the history of one of the user's records
+func handleListHistory(repo *repository.Repository, entity string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r, "id")
+		if !ok {
+			return
+		}
+		entries, err := repo.ListHistory(r.Context(), getUserIDFromContext(r.Context()), entity, id)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, entries)
+	}
+}
+
+// handleRevertRecord reverts one of the user's records to a history entry,
+// honoring If-Match
+func handleRevertRecord(repo *repository.Repository, entity string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r, "id")
+		if !ok {
+			return
+		}
+		entryID, ok := pathID(w, r, "entry")
+		if !ok {
+			return
+		}
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		version, err = repo.RevertRecord(r.Context(), getUserIDFromContext(r.Context()), entity, id, entryID, version)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		setETag(w, version)
+		respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
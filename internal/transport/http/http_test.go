@@ -1,12 +1,20 @@
 package httpapi
 
 import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
 	"database/sql"
+	"encoding/pem"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/mdco1990/webapp/internal/auth"
 	"github.com/mdco1990/webapp/internal/config"
 	"github.com/mdco1990/webapp/internal/db"
 )
@@ -30,6 +38,16 @@ func setupTestDB(t *testing.T) *sql.DB {
 	return database
 }
 
+// newTestRouter builds the router, failing the test if it cannot be built.
+func newTestRouter(t *testing.T, cfg config.Config, database *sql.DB) http.Handler {
+	t.Helper()
+	router, _, err := NewRouter(cfg, database)
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+	return router
+}
+
 func TestNewRouter(t *testing.T) {
 	// Create test config
 	cfg := config.Config{
@@ -51,10 +69,15 @@ func TestNewRouter(t *testing.T) {
 	}()
 
 	// Create router with real database
-	router := NewRouter(cfg, database)
-	if router == nil {
-		t.Fatal("Expected router to be created")
+	router, workers, err := NewRouter(cfg, database)
+	if err != nil || router == nil || workers == nil {
+		t.Fatalf("Expected router to be created, got %v", err)
 	}
+
+	// The workers are not started with the router, and return once stopped.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	workers.Run(ctx)
 }
 
 func TestHealthEndpoint(t *testing.T) {
@@ -78,7 +101,7 @@ func TestHealthEndpoint(t *testing.T) {
 	}()
 
 	// Create router
-	router := NewRouter(cfg, database)
+	router := newTestRouter(t, cfg, database)
 
 	// Create test request
 	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
@@ -99,6 +122,90 @@ func TestHealthEndpoint(t *testing.T) {
 	}
 }
 
+func TestJWKSEndpoint(t *testing.T) {
+	cfg := config.Config{
+		CORSAllowedOrigins: []string{"http://localhost:3000"},
+		Env:                "test",
+	}
+	database := setupTestDB(t)
+	defer func() { _ = database.Close() }()
+
+	router := newTestRouter(t, cfg, database)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	// A generated Ed25519 key is published when none is configured
+	body := w.Body.String()
+	if !strings.Contains(body, `"kty":"OKP"`) || !strings.Contains(body, `"alg":"EdDSA"`) || strings.Contains(body, `"d"`) {
+		t.Errorf("Expected one public Ed25519 key, got %s", body)
+	}
+}
+
+func TestNewRouterJWTKeyFiles(t *testing.T) {
+	database := setupTestDB(t)
+	defer func() { _ = database.Close() }()
+
+	dir := t.TempDir()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	good := filepath.Join(dir, "signing.pem")
+	bad := filepath.Join(dir, "bad.pem")
+	if err := os.WriteFile(good, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(bad, []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A configured key file that cannot be used stops startup rather than
+	// falling back to a per-process key.
+	for _, cfg := range []config.Config{
+		{JWTSigningKeyFile: bad},
+		{JWTSigningKeyFile: filepath.Join(dir, "missing.pem")},
+		{JWTSigningKeyFile: good, JWTPreviousKeyFiles: []string{bad}},
+	} {
+		if _, _, err := NewRouter(cfg, database); err == nil {
+			t.Errorf("Expected an error for %+v", cfg)
+		}
+	}
+
+	key, err := auth.NewSigningKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := newTestRouter(t, config.Config{JWTSigningKeyFile: good}, database)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+	if !strings.Contains(w.Body.String(), `"kid":"`+key.ID+`"`) {
+		t.Errorf("Expected the configured key %s published, got %s", key.ID, w.Body.String())
+	}
+}
+
+func TestNewRouterRateLimitConfig(t *testing.T) {
+	database := setupTestDB(t)
+	defer func() { _ = database.Close() }()
+
+	// A limiter that cannot be set up as configured stops startup rather
+	// than limiting per instance or by the proxy's address.
+	for _, cfg := range []config.Config{
+		{RateLimitStore: "sqlite", RateLimitStorePath: filepath.Join(t.TempDir(), "missing", "ratelimit.db")},
+		{TrustedProxies: []string{"not-an-address"}},
+	} {
+		if _, _, err := NewRouter(cfg, database); err == nil {
+			t.Errorf("Expected an error for %+v", cfg)
+		}
+	}
+}
+
 func TestCORSHeaders(t *testing.T) {
 	// Create test config with CORS
 	cfg := config.Config{
@@ -120,7 +227,7 @@ func TestCORSHeaders(t *testing.T) {
 	}()
 
 	// Create router
-	router := NewRouter(cfg, database)
+	router := newTestRouter(t, cfg, database)
 
 	// Test preflight request
 	req := httptest.NewRequest(http.MethodOptions, "/api/test", nil)
@@ -170,7 +277,7 @@ func TestNotFoundHandler(t *testing.T) {
 	}()
 
 	// Create router
-	router := NewRouter(cfg, database)
+	router := newTestRouter(t, cfg, database)
 
 	// Test non-existent endpoint
 	req := httptest.NewRequest(http.MethodGet, "/nonexistent", nil)
@@ -206,7 +313,7 @@ func TestMethodNotAllowed(t *testing.T) {
 	}()
 
 	// Create router
-	router := NewRouter(cfg, database)
+	router := newTestRouter(t, cfg, database)
 
 	// Test POST to health endpoint (should not be allowed)
 	req := httptest.NewRequest(http.MethodPost, "/healthz", nil)
@@ -242,7 +349,7 @@ func TestRouterMiddleware(t *testing.T) {
 	}()
 
 	// Create router
-	router := NewRouter(cfg, database)
+	router := newTestRouter(t, cfg, database)
 
 	// Test that middleware is applied (check for common headers)
 	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
@@ -279,7 +386,7 @@ func TestRouterWithDatabase(t *testing.T) {
 	}()
 
 	// Should not panic
-	router := NewRouter(cfg, database)
+	router := newTestRouter(t, cfg, database)
 	if router == nil {
 		t.Fatal("Router should not be nil")
 	}
@@ -306,7 +413,7 @@ func TestRouterConcurrency(t *testing.T) {
 	}()
 
 	// Create router
-	router := NewRouter(cfg, database)
+	router := newTestRouter(t, cfg, database)
 
 	// Test concurrent requests
 	done := make(chan bool, 10)
@@ -330,3 +437,40 @@ func TestRouterConcurrency(t *testing.T) {
 		<-done
 	}
 }
+
+func TestLoginRateLimit(t *testing.T) {
+	cfg := config.Config{
+		CORSAllowedOrigins:      []string{"http://localhost:3000"},
+		RateLimitPerMinute:      100,
+		LoginRateLimitPerMinute: 2,
+	}
+	database := setupTestDB(t)
+	defer func() { _ = database.Close() }()
+	router := newTestRouter(t, cfg, database)
+
+	login := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/auth/login",
+			strings.NewReader(`{"username":"admin","password":"wrong-password"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	for i := 0; i < 2; i++ {
+		if w := login(); w.Code == http.StatusTooManyRequests {
+			t.Fatalf("Expected attempt %d to be allowed, got %d", i+1, w.Code)
+		}
+	}
+	w := login()
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Fatalf("Expected 429 with Retry-After, got %d %v", w.Code, w.Header())
+	}
+
+	// Other routes only count against the looser limit.
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Header().Get("RateLimit-Limit") != "100" {
+		t.Errorf("Expected healthz within the public limit, got %d %v", rec.Code, rec.Header())
+	}
+}
@@ -0,0 +1,46 @@
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mdco1990/webapp/internal/export"
+	"github.com/mdco1990/webapp/internal/repository"
+)
+
+// registerJournalEndpoints wires plain-text accounting exports
+func registerJournalEndpoints(api *Routes, repo *repository.Repository) {
+	api.Add(
+		route(http.MethodGet, "/export/journal", handleExportJournal(repo)).
+			WithTag("Import and Export").
+			WithSummary("Export a plain-text journal").
+			WithParams(append(exportPeriodParams(),
+				queryParam("format", enumSchema("ledger", "hledger", "beancount"), "Journal dialect (default ledger)"))...).
+			WithRawResponse(http.StatusOK, "text/plain"),
+	)
+}
+
+// handleExportJournal streams a month or a year as a ledger, hledger or beancount journal
+func handleExportJournal(repo *repository.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := getUserIDFromContext(r.Context())
+		period, err := parseExportPeriod(r)
+		if err != nil {
+			respondErr(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		dialect, err := export.ParseDialect(r.URL.Query().Get("format"))
+		if err != nil {
+			respondErr(w, r, http.StatusBadRequest, "format must be ledger, hledger or beancount")
+			return
+		}
+		format := resolveExportFormat(r, repo, userID)
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Content-Disposition",
+			fmt.Sprintf("attachment; filename=%q", period.filename("budget", dialect.Extension())))
+		w.WriteHeader(http.StatusOK)
+
+		streamMonths(r.Context(), repo, userID, period, export.NewJournalWriter(w, dialect, format.Currency))
+	}
+}
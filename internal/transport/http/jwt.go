@@ -0,0 +1,161 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/auth"
+	"github.com/mdco1990/webapp/internal/config"
+	"github.com/mdco1990/webapp/internal/repository"
+	"github.com/mdco1990/webapp/internal/storage"
+)
+
+// JWTs are short-lived and renewed with a refresh token. Repeated failed
+// logins at /auth/token lock the account for a while.
+const (
+	jwtStrategy         = "token"
+	jwtTTL              = 15 * time.Minute
+	jwtRefreshTTL       = 30 * 24 * time.Hour
+	jwtClockSkew        = 30 * time.Second
+	jwtMaxLoginAttempts = 5
+	jwtLockout          = 15 * time.Minute
+)
+
+// newJWTAuth builds the JWT authentication service. Credentials are checked
+// against the users' bcrypt hashes; refresh tokens and login attempts are
+// kept in store, shared between instances when it is.
+func newJWTAuth(cfg config.Config, repo *repository.Repository, keys *auth.KeySet, store storage.Provider) *auth.Service {
+	return auth.NewAuthService(store, repo, keys, auth.Config{
+		DefaultStrategy:  jwtStrategy,
+		SessionTimeout:   24 * time.Hour,
+		TokenTimeout:     jwtTTL,
+		RefreshTimeout:   jwtRefreshTTL,
+		MaxLoginAttempts: jwtMaxLoginAttempts,
+		LockoutDuration:  jwtLockout,
+		Issuer:           cfg.JWTIssuer,
+		Audience:         cfg.JWTAudience,
+		ClockSkew:        jwtClockSkew,
+	})
+}
+
+// loadJWTKeys builds the JWT key set from the configured key files; a file
+// that cannot be read or parsed is an error. Without a signing key file a
+// random key is generated, so tokens do not survive a restart.
+func loadJWTKeys(cfg config.Config) (*auth.KeySet, error) {
+	var previous []*auth.SigningKey
+	for _, path := range cfg.JWTPreviousKeyFiles {
+		key, err := readSigningKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("load previous JWT key %s: %w", path, err)
+		}
+		previous = append(previous, key)
+	}
+
+	if cfg.JWTSigningKeyFile == "" {
+		slog.Warn("JWT_SIGNING_KEY_FILE not set; using a generated key, so JWTs will not survive a restart")
+		current, err := auth.GenerateSigningKey()
+		if err != nil {
+			return nil, fmt.Errorf("generate JWT signing key: %w", err)
+		}
+		return auth.NewKeySet(current, previous...), nil
+	}
+	current, err := readSigningKey(cfg.JWTSigningKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load JWT signing key %s: %w", cfg.JWTSigningKeyFile, err)
+	}
+	return auth.NewKeySet(current, previous...), nil
+}
+
+// readSigningKey reads a PEM private key file.
+func readSigningKey(path string) (*auth.SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return auth.ParseSigningKeyPEM(data)
+}
+
+// registerJWKSRoute serves the public JWT keys for token verification
+func registerJWKSRoute(routes *Routes, keys *auth.KeySet) {
+	routes.Tagged("Auth").Add(
+		route(http.MethodGet, "/.well-known/jwks.json", handleJWKS(keys)).
+			WithSummary("JSON Web Key Set").
+			WithDescription("Public keys that verify the JWTs this server issues, matched by the token's `kid`. "+
+				"Keys rotated out stay listed until retired, so tokens they signed keep verifying.").
+			WithResponse(http.StatusOK, auth.JWKS{}),
+	)
+}
+
+// handleJWKS returns the key set; clients may cache it briefly
+func handleJWKS(keys *auth.KeySet) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		respondJSON(w, http.StatusOK, keys.JWKS())
+	}
+}
+
+// tokenRefreshRequest is the body of POST /auth/token/refresh.
+type tokenRefreshRequest struct {
+	RefreshToken string `json:"refresh_token" openapi:"required"`
+}
+
+// handleIssueJWT exchanges a username and password for a JWT and a refresh
+// token
+func handleIssueJWT(jwts *auth.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req LoginRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondErr(w, r, http.StatusBadRequest, invalidBodyMsg)
+			return
+		}
+		if req.Username == "" || req.Password == "" {
+			respondErr(w, r, http.StatusBadRequest, "username and password are required")
+			return
+		}
+		result, err := jwts.AuthenticateWithDefault(r.Context(), auth.Credentials{
+			Username: req.Username, Password: req.Password,
+		})
+		if err != nil {
+			respondJWTError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, result)
+	}
+}
+
+// handleRefreshJWT exchanges a refresh token, which is used up, for a new
+// JWT and refresh token
+func handleRefreshJWT(jwts *auth.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req tokenRefreshRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+			respondErr(w, r, http.StatusBadRequest, invalidBodyMsg)
+			return
+		}
+		result, err := jwts.Refresh(r.Context(), jwtStrategy, req.RefreshToken)
+		if err != nil {
+			respondJWTError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, result)
+	}
+}
+
+// respondJWTError maps authentication failures to 401 and lockouts to 429.
+func respondJWTError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, auth.ErrAccountLocked):
+		respondErr(w, r, http.StatusTooManyRequests, err.Error())
+	case errors.Is(err, auth.ErrInvalidCredentials):
+		respondErr(w, r, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, auth.ErrInvalidRefresh), errors.Is(err, auth.ErrInvalidToken):
+		respondErr(w, r, http.StatusUnauthorized, "invalid or expired token")
+	default:
+		respondError(w, r, err)
+	}
+}
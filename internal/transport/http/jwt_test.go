@@ -0,0 +1,114 @@
+package httpapi
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mdco1990/webapp/internal/auth"
+	"github.com/mdco1990/webapp/internal/config"
+	"github.com/mdco1990/webapp/internal/repository"
+)
+
+func TestJWTAuth(t *testing.T) {
+	database := setupTestDB(t)
+	defer func() { _ = database.Close() }()
+	database.SetMaxOpenConns(1)
+	router := newTestRouter(t, config.Config{
+		CORSAllowedOrigins: []string{"http://localhost:3000"},
+		Env:                "test",
+		APIKey:             "shared-key",
+		JWTIssuer:          "webapp",
+		JWTAudience:        "webapp-api",
+	}, database)
+	if _, err := repository.New(database).CreateUser(context.Background(), "alice", "correct horse", ""); err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+
+	do := func(method, target, bearer, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	issue := func(target, body string) auth.Result {
+		t.Helper()
+		w := do(http.MethodPost, target, "", body)
+		var result auth.Result
+		if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &result) != nil || result.Token == "" {
+			t.Fatalf("Expected a JWT from %s, got %d %s", target, w.Code, w.Body.String())
+		}
+		return result
+	}
+
+	if w := do(http.MethodPost, "/auth/token", "", `{"username":"alice","password":"wrong horse"}`); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a wrong password, got %d", w.Code)
+	}
+	issued := issue("/auth/token", `{"username":"alice","password":"correct horse"}`)
+
+	// The JWT stands in for both the session and the API key.
+	expenses := "/api/v1/expenses?year=2024&month=3"
+	if w := do(http.MethodGet, expenses, issued.Token, ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 with the JWT, got %d %s", w.Code, w.Body.String())
+	}
+	parts := strings.Split(issued.Token, ".")
+	if w := do(http.MethodGet, expenses, parts[0]+"."+parts[1]+".AAAA", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a bad signature, got %d", w.Code)
+	}
+	if w := do(http.MethodGet, "/api/v1/admin/users", issued.Token, ""); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for the admin endpoints as a non-admin, got %d", w.Code)
+	}
+
+	// Anyone holding the published keys can verify the token.
+	var set auth.JWKS
+	if err := json.Unmarshal(do(http.MethodGet, "/.well-known/jwks.json", "", "").Body.Bytes(), &set); err != nil {
+		t.Fatalf("Failed to decode JWKS: %v", err)
+	}
+	var header struct{ Alg, Kid string }
+	var claims struct {
+		Iss, Aud, Sub string
+	}
+	decode := func(segment string, v any) {
+		t.Helper()
+		data, err := base64.RawURLEncoding.DecodeString(segment)
+		if err != nil || json.Unmarshal(data, v) != nil {
+			t.Fatalf("Failed to decode token segment %q", segment)
+		}
+	}
+	decode(parts[0], &header)
+	decode(parts[1], &claims)
+	if claims.Iss != "webapp" || claims.Aud != "webapp-api" || claims.Sub == "" {
+		t.Errorf("Expected the configured issuer and audience, got %+v", claims)
+	}
+	verified := false
+	for _, key := range set.Keys {
+		if key.Kid != header.Kid || key.Alg != header.Alg || key.Crv != "Ed25519" {
+			continue
+		}
+		x, _ := base64.RawURLEncoding.DecodeString(key.X)
+		sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		verified = ed25519.Verify(ed25519.PublicKey(x), []byte(parts[0]+"."+parts[1]), sig)
+	}
+	if !verified {
+		t.Errorf("Expected the JWT to verify against the JWKS, got header %+v and keys %+v", header, set.Keys)
+	}
+
+	// Refresh tokens are used up by a refresh.
+	refreshed := issue("/auth/token/refresh", `{"refresh_token":"`+issued.RefreshToken+`"}`)
+	if w := do(http.MethodGet, expenses, refreshed.Token, ""); w.Code != http.StatusOK {
+		t.Errorf("Expected 200 with the refreshed JWT, got %d", w.Code)
+	}
+	w := do(http.MethodPost, "/auth/token/refresh", "", `{"refresh_token":"`+issued.RefreshToken+`"}`)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a used refresh token, got %d", w.Code)
+	}
+}
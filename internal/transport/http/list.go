@@ -0,0 +1,176 @@
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/repository"
+)
+
+const maxSearchLength = 100
+
+// parseListQuery reads the query parameters shared by all list endpoints:
+//
+//	limit       page size (1..500; default 100 with a cursor, every row without)
+//	cursor      next-page cursor from a previous X-Next-Cursor header
+//	sort        date, amount or name; prefix "-" for descending
+//	year, month exact period (month needs year)
+//	from, to    inclusive period range, YYYY-MM
+//	category    exact category
+//	min_amount, max_amount  amount range in cents
+//	q           case-insensitive text search
+//
+// defaultSort uses the same syntax as the sort parameter. All problems are
+// reported together as one validation error with field details.
+func parseListQuery(r *http.Request, defaultSort string) (domain.ListQuery, error) {
+	v := r.URL.Query()
+	var q domain.ListQuery
+	var fields []domain.FieldError
+	bad := func(field, msg string) { fields = append(fields, domain.FieldError{Field: field, Message: msg}) }
+
+	if s := v.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > repository.MaxListLimit {
+			bad("limit", fmt.Sprintf("must be between 1 and %d", repository.MaxListLimit))
+		}
+		q.Limit = n
+	}
+
+	sort := v.Get("sort")
+	if sort == "" {
+		sort = defaultSort
+	}
+	q.Sort = strings.TrimPrefix(sort, "-")
+	q.Desc = strings.HasPrefix(sort, "-")
+	switch q.Sort {
+	case domain.SortDate, domain.SortAmount, domain.SortName:
+	default:
+		bad("sort", "must be date, amount or name, optionally prefixed with -")
+	}
+
+	if s := v.Get("cursor"); s != "" {
+		c, err := domain.DecodeCursor(s)
+		if err != nil {
+			bad("cursor", "is malformed")
+		}
+		q.After = c
+	}
+
+	q.Year = intParam(v.Get("year"), "year", 1970, 3000, bad)
+	q.Month = intParam(v.Get("month"), "month", 1, 12, bad)
+	if q.Month != 0 && q.Year == 0 {
+		bad("month", "requires year")
+	}
+	q.From = monthParam(v.Get("from"), "from", bad)
+	q.To = monthParam(v.Get("to"), "to", bad)
+	if q.From != nil && q.To != nil && q.From.Year*100+q.From.Month > q.To.Year*100+q.To.Month {
+		bad("to", "must not be before from")
+	}
+
+	q.Category = strings.TrimSpace(v.Get("category"))
+	q.MinAmount = moneyParam(v.Get("min_amount"), "min_amount", bad)
+	q.MaxAmount = moneyParam(v.Get("max_amount"), "max_amount", bad)
+	if q.MinAmount != nil && q.MaxAmount != nil && *q.MinAmount > *q.MaxAmount {
+		bad("max_amount", "must not be less than min_amount")
+	}
+	q.Search = strings.TrimSpace(v.Get("q"))
+	if len(q.Search) > maxSearchLength {
+		bad("q", fmt.Sprintf("must be at most %d bytes", maxSearchLength))
+	}
+
+	if len(fields) > 0 {
+		return domain.ListQuery{}, domain.Invalid("invalid list parameters", fields...)
+	}
+	return q, nil
+}
+
+// listQueryParams documents the parameters read by parseListQuery that all
+// lists support; see amountQueryParams and categoryQueryParam for the rest.
+func listQueryParams() []Parameter {
+	month := `^\d{4}-(0[1-9]|1[0-2])$`
+	return []Parameter{
+		queryParam("limit", intSchema(1, repository.MaxListLimit),
+			"Page size. Without limit or cursor every row is returned; with a cursor, 100 by default."),
+		queryParam("cursor", stringSchema(),
+			"Opaque cursor from the X-Next-Cursor header of the previous page. Only valid with the same sort."),
+		queryParam("sort", enumSchema("date", "-date", "amount", "-amount", "name", "-name"),
+			`Sort key; prefix with "-" for descending. "date" orders by period (sign-up order for users).`),
+		queryParam("year", intSchema(1970, 3000), "Only rows of this year"),
+		queryParam("month", intSchema(1, 12), "Only rows of this month; requires year"),
+		queryParam("from", patternSchema(month), "First month of an inclusive period range, YYYY-MM"),
+		queryParam("to", patternSchema(month), "Last month of an inclusive period range, YYYY-MM"),
+		queryParam("q", &Schema{Type: "string", MaxLength: intPtr(maxSearchLength)},
+			"Case-insensitive text search on names and descriptions (username and email for users)"),
+	}
+}
+
+// amountQueryParams documents the amount range filter of lists of amounts.
+func amountQueryParams() []Parameter {
+	cents := &Schema{Type: "integer", Format: "int64", Minimum: new(float64)}
+	return []Parameter{
+		queryParam("min_amount", cents, "Minimum amount in cents"),
+		queryParam("max_amount", cents, "Maximum amount in cents"),
+	}
+}
+
+// categoryQueryParam documents the category filter of the expense list.
+var categoryQueryParam = queryParam("category", stringSchema(), "Exact category")
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func intParam(s, field string, lo, hi int, bad func(string, string)) int {
+	if s == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < lo || n > hi {
+		bad(field, fmt.Sprintf("must be between %d and %d", lo, hi))
+		return 0
+	}
+	return n
+}
+
+func monthParam(s, field string, bad func(string, string)) *domain.YearMonth {
+	if s == "" {
+		return nil
+	}
+	t, err := time.Parse("2006-01", s)
+	if err != nil {
+		bad(field, "must be YYYY-MM")
+		return nil
+	}
+	return &domain.YearMonth{Year: t.Year(), Month: int(t.Month())}
+}
+
+func moneyParam(s, field string, bad func(string, string)) *domain.Money {
+	if s == "" {
+		return nil
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		bad(field, "must be a non-negative amount in cents")
+		return nil
+	}
+	m := domain.Money(n)
+	return &m
+}
+
+// respondPage writes the items of a page as a JSON array. The next page, if
+// any, is announced in the X-Next-Cursor header and a Link rel="next" header.
+func respondPage[T any](w http.ResponseWriter, r *http.Request, page domain.Page[T]) {
+	if page.NextCursor != "" {
+		next := *r.URL
+		v := next.Query()
+		v.Set("cursor", page.NextCursor)
+		next.RawQuery = v.Encode()
+		w.Header().Set("X-Next-Cursor", page.NextCursor)
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next.RequestURI()))
+	}
+	respondJSON(w, http.StatusOK, page.Items)
+}
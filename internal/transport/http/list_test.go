@@ -0,0 +1,95 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+func TestParseListQuery(t *testing.T) {
+	cursor := domain.Cursor{Sort: domain.SortAmount, Desc: true, Int: 500, ID: 7}.Encode()
+	req := httptest.NewRequest(http.MethodGet,
+		"/api/v1/expenses?limit=20&sort=-amount&cursor="+cursor+
+			"&year=2024&month=3&from=2024-01&to=2024-06&category=food&min_amount=100&max_amount=900&q=+bread+", nil)
+
+	q, err := parseListQuery(req, domain.SortName)
+	if err != nil {
+		t.Fatalf("parseListQuery failed: %v", err)
+	}
+	if q.Limit != 20 || q.Sort != domain.SortAmount || !q.Desc {
+		t.Errorf("Expected limit 20 sorted by amount descending, got %+v", q)
+	}
+	if q.After == nil || q.After.ID != 7 || q.After.Int != 500 {
+		t.Errorf("Expected decoded cursor, got %+v", q.After)
+	}
+	if q.Year != 2024 || q.Month != 3 || q.From == nil || q.From.Month != 1 || q.To == nil || q.To.Month != 6 {
+		t.Errorf("Expected period filters, got %+v", q)
+	}
+	if q.Category != "food" || *q.MinAmount != 100 || *q.MaxAmount != 900 || q.Search != "bread" {
+		t.Errorf("Expected category, amount and search filters, got %+v", q)
+	}
+
+	q, err = parseListQuery(httptest.NewRequest(http.MethodGet, "/api/v1/income-sources", nil), "-date")
+	if err != nil {
+		t.Fatalf("parseListQuery failed: %v", err)
+	}
+	if q.Sort != domain.SortDate || !q.Desc || q.Limit != 0 {
+		t.Errorf("Expected the default sort and limit, got %+v", q)
+	}
+}
+
+func TestParseListQueryErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		field string
+	}{
+		{"limit=0", "limit"},
+		{"limit=100000", "limit"},
+		{"sort=price", "sort"},
+		{"cursor=!!!", "cursor"},
+		{"month=3", "month"},
+		{"year=99999", "year"},
+		{"from=2024-13", "from"},
+		{"from=2024-06&to=2024-01", "to"},
+		{"min_amount=-1", "min_amount"},
+		{"min_amount=500&max_amount=100", "max_amount"},
+		{"q=" + strings.Repeat("x", maxSearchLength+1), "q"},
+	}
+	for _, tt := range tests {
+		_, err := parseListQuery(httptest.NewRequest(http.MethodGet, "/x?"+tt.query, nil), domain.SortName)
+		var de *domain.Error
+		if !errors.As(err, &de) || !errors.Is(err, domain.ErrValidation) {
+			t.Errorf("%s: Expected a validation error, got %v", tt.query, err)
+			continue
+		}
+		if len(de.Fields) != 1 || de.Fields[0].Field != tt.field {
+			t.Errorf("%s: Expected one error on %s, got %+v", tt.query, tt.field, de.Fields)
+		}
+	}
+}
+
+func TestRespondPage(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/expenses?limit=2&sort=name", nil)
+	w := httptest.NewRecorder()
+	respondPage(w, req, domain.Page[string]{Items: []string{"a", "b"}, NextCursor: "abc"})
+
+	if got := w.Header().Get("X-Next-Cursor"); got != "abc" {
+		t.Errorf("Expected X-Next-Cursor abc, got %q", got)
+	}
+	if got := w.Header().Get("Link"); got != `</api/v1/expenses?cursor=abc&limit=2&sort=name>; rel="next"` {
+		t.Errorf("Expected next link, got %q", got)
+	}
+	if body := strings.TrimSpace(w.Body.String()); body != `["a","b"]` {
+		t.Errorf("Expected a plain JSON array, got %s", body)
+	}
+
+	w = httptest.NewRecorder()
+	respondPage(w, req, domain.Page[string]{Items: []string{}})
+	if w.Header().Get("Link") != "" || w.Header().Get("X-Next-Cursor") != "" {
+		t.Error("Expected no next-page headers on the last page")
+	}
+}
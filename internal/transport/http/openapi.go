@@ -0,0 +1,567 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// OpenAPIDocument is the OpenAPI 3.1 description of the API, generated from
+// the routes declared through Routes. Go request and response types become
+// JSON schemas by reflection, following encoding/json; named struct types are
+// shared under components/schemas. The openapi struct tag adds constraints
+// to a field, comma-separated:
+//
+//	required                  the field must be present
+//	minimum=N, maximum=N      numeric bounds
+//	minLength=N, maxLength=N  string length bounds, in characters
+//	enum=a|b|c                allowed string values
+//	format=F                  e.g. uri or email
+//
+// The same schemas are used to validate incoming requests (see
+// ValidateRequest).
+type OpenAPIDocument struct {
+	paths   map[string]map[string]*Operation
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+// Schema is the subset of JSON Schema the generator emits and the request
+// validator understands.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"-"`
+	Nullable             bool               `json:"-"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// MarshalJSON writes a nullable type the OpenAPI 3.1 way, as ["T", "null"].
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	type plain Schema
+	out := struct {
+		Type any `json:"type,omitempty"`
+		*plain
+	}{plain: (*plain)(s)}
+	switch {
+	case s.Type != "" && s.Nullable:
+		out.Type = []string{s.Type, "null"}
+	case s.Type != "":
+		out.Type = s.Type
+	}
+	return json.Marshal(out)
+}
+
+// Parameter describes a query, header or path parameter of a route.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// Operation is one method on one path.
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+}
+
+// RequestBody describes the body an operation accepts.
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes one response of an operation.
+type Response struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of a body.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Security requirements: every /api/v1 route needs the API key and a
+// session, sent as a bearer token or cookie, or else a JWT or a personal
+// access token with the route's scope, which addRoute fills in.
+var (
+	sessionSecurity = []map[string][]string{
+		{"BearerAuth": {}},
+		{"SessionCookie": {}},
+	}
+	apiSecurity = []map[string][]string{
+		{"APIKeyAuth": {}, "BearerAuth": {}},
+		{"APIKeyAuth": {}, "SessionCookie": {}},
+		{"JWT": {}},
+		{"AccessToken": {}},
+	}
+	adminSecurity = []map[string][]string{
+		{"BearerAuth": {}},
+		{"SessionCookie": {}},
+		{"JWT": {}},
+		{"AccessToken": {}},
+	}
+)
+
+// apiTags lists the operation tags in display order.
+var apiTags = []struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}{
+	{"Health", "System health checks"},
+	{"Auth", "Authentication and session management"},
+	{"Legacy", "Legacy budget and expense endpoints"},
+	{"Income Sources", "Named income source management"},
+	{"Budget Sources", "Named budget category management"},
+	{"Monthly Data", "Aggregated monthly financial data"},
+	{"Manual Budget", "Manual budget bank amount and ad-hoc items"},
+	{"Import and Export", "Data transfer, exports and background tasks"},
+	{"Reports", "Generated PDF reports"},
+	{"Sharing", "Read-only share links and calendar feeds"},
+	{"Webhooks", "Outgoing webhooks and their delivery log"},
+	{"Access Tokens", "Personal access tokens for scripts and integrations"},
+	{"Search", "Full-text search across expenses and sources"},
+	{"Trash", "Deleted records awaiting restore or purge"},
+	{"Sync", "Delta sync and offline mutations for offline-capable clients"},
+	{"Utilities", "Seeding, batch writes, GraphQL and live updates"},
+	{"Secure", "Income, budget and manual budget writes with strict input validation"},
+	{"Admin", "User administration"},
+}
+
+// NewOpenAPIDocument creates an empty document.
+func NewOpenAPIDocument() *OpenAPIDocument {
+	return &OpenAPIDocument{
+		paths:   map[string]map[string]*Operation{},
+		schemas: map[string]*Schema{"ErrorResponse": problemSchema()},
+		names:   map[reflect.Type]string{},
+	}
+}
+
+// MarshalJSON renders the whole document.
+func (d *OpenAPIDocument) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":       "Budget Planner API",
+			"description": "A comprehensive budget planning and expense tracking API",
+			"version":     "1.0.0",
+			"license":     map[string]string{"name": "MIT", "identifier": "MIT"},
+		},
+		"servers": []map[string]string{{"url": "/", "description": "This server"}},
+		"tags":    apiTags,
+		"paths":   d.paths,
+		"components": map[string]any{
+			"schemas": d.schemas,
+			"responses": map[string]*Response{
+				"Problem": {
+					Description: "Error, as RFC 7807 problem details",
+					Content: map[string]MediaType{
+						"application/problem+json": {Schema: &Schema{Ref: "#/components/schemas/ErrorResponse"}},
+					},
+				},
+			},
+			"securitySchemes": map[string]any{
+				"APIKeyAuth": map[string]string{
+					"type": "apiKey", "in": "header", "name": "X-API-Key",
+					"description": "API key authentication",
+				},
+				"BearerAuth": map[string]string{
+					"type": "http", "scheme": "bearer", "bearerFormat": "session_id",
+					"description": "Session ID from /auth/login",
+				},
+				"JWT": map[string]string{
+					"type": "http", "scheme": "bearer", "bearerFormat": "JWT",
+					"description": "JWT from /auth/token, verifiable with the keys at /.well-known/jwks.json",
+				},
+				"AccessToken": map[string]string{
+					"type": "http", "scheme": "bearer", "bearerFormat": domain.AccessTokenPrefix + "...",
+					"description": "Personal access token from /api/v1/tokens; the operation lists the scope it needs " +
+						"(`admin` grants every scope)",
+				},
+				"SessionCookie": map[string]string{
+					"type": "apiKey", "in": "cookie", "name": "session_id",
+					"description": "Session cookie set by /auth/login",
+				},
+			},
+		},
+	})
+}
+
+// scopedSecurity returns security with scope as the access token's required
+// role, or none if scope is empty, leaving the shared requirement lists
+// untouched.
+func scopedSecurity(security []map[string][]string, scope string) []map[string][]string {
+	if security == nil {
+		return nil
+	}
+	out := make([]map[string][]string, 0, len(security))
+	for _, req := range security {
+		if _, ok := req["AccessToken"]; ok {
+			req = map[string][]string{"AccessToken": {}}
+			if scope != "" {
+				req["AccessToken"] = []string{scope}
+			}
+		}
+		out = append(out, req)
+	}
+	return out
+}
+
+// Operation returns the operation for method on the documented path, or nil.
+func (d *OpenAPIDocument) Operation(method, path string) *Operation {
+	return d.paths[path][strings.ToLower(method)]
+}
+
+// addRoute documents def under path and returns its operation.
+func (d *OpenAPIDocument) addRoute(path string, def RouteDefinition, g *Routes) *Operation {
+	op := &Operation{
+		Summary:     def.Summary,
+		Description: def.Description,
+		Security:    g.security,
+		Responses:   map[string]*Response{},
+	}
+	if def.Security != nil {
+		op.Security = def.Security
+	}
+	op.Security = scopedSecurity(op.Security, g.routeScope(def))
+	if tag := def.Tag; tag != "" {
+		op.Tags = []string{tag}
+	} else if g.tag != "" {
+		op.Tags = []string{g.tag}
+	}
+
+	if def.Method != http.MethodGet && def.Method != http.MethodHead {
+		op.Parameters = append(op.Parameters, g.writeParams...)
+	}
+	for _, name := range pathParamNames(path) {
+		if !hasParam(def.Params, name, "path") {
+			op.Parameters = append(op.Parameters, Parameter{
+				Name: name, In: "path", Required: true, Schema: idSchema(),
+			})
+		}
+	}
+	op.Parameters = append(op.Parameters, def.Params...)
+
+	switch {
+	case def.Request != nil:
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+			contentTypeJSON: {Schema: d.schemaFor(reflect.TypeOf(def.Request))},
+		}}
+	case def.RequestType != "":
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+			def.RequestType: {Schema: &Schema{Type: "string", Format: "binary"}},
+		}}
+	}
+
+	status := def.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	resp := &Response{Description: http.StatusText(status)}
+	switch {
+	case def.Response != nil:
+		resp.Content = map[string]MediaType{
+			contentTypeJSON: {Schema: d.schemaFor(reflect.TypeOf(def.Response))},
+		}
+	case def.ResponseType != "":
+		resp.Content = map[string]MediaType{
+			def.ResponseType: {Schema: &Schema{Type: "string", Format: "binary"}},
+		}
+	}
+	op.Responses[strconv.Itoa(status)] = resp
+	op.Responses["default"] = &Response{Ref: "#/components/responses/Problem"}
+
+	if d.paths[path] == nil {
+		d.paths[path] = map[string]*Operation{}
+	}
+	d.paths[path][strings.ToLower(def.Method)] = op
+	return op
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaFor returns the schema of t. Named struct types are added to the
+// components and referenced.
+func (d *OpenAPIDocument) schemaFor(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := d.schemaFor(t.Elem())
+		if s.Ref == "" {
+			s.Nullable = true
+		}
+		return s
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: d.schemaFor(t.Elem()), Nullable: t.Kind() == reflect.Slice}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.schemaFor(t.Elem()), Nullable: true}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + d.componentName(t)}
+	default: // interfaces
+		return &Schema{}
+	}
+}
+
+// componentName registers t's schema under a unique name, the first time it
+// is seen.
+func (d *OpenAPIDocument) componentName(t reflect.Type) string {
+	if name, ok := d.names[t]; ok {
+		return name
+	}
+	name := exportedName(t.Name())
+	if _, taken := d.schemas[name]; taken {
+		pkg := t.PkgPath()
+		name = exportedName(pkg[strings.LastIndex(pkg, "/")+1:]) + name
+	}
+	d.names[t] = name
+	d.schemas[name] = &Schema{Type: "object"} // placeholder for recursive types
+	d.schemas[name] = d.structSchema(t)
+	return name
+}
+
+// structSchema describes the JSON object encoding/json makes of t.
+func (d *OpenAPIDocument) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	d.addFields(s, t)
+	return s
+}
+
+func (d *OpenAPIDocument) addFields(s *Schema, t reflect.Type) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				d.addFields(s, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fs := d.schemaFor(f.Type)
+		if strings.Contains(opts, "string") && fs.Type != "" {
+			fs = &Schema{Type: "string"}
+		}
+		if required := applySchemaTag(fs, f.Tag.Get("openapi")); required {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = fs
+	}
+}
+
+// applySchemaTag adds the constraints of an openapi struct tag to s and
+// reports whether the field is required.
+func applySchemaTag(s *Schema, tag string) (required bool) {
+	if tag == "" {
+		return false
+	}
+	for _, opt := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(opt), "=")
+		switch key {
+		case "required":
+			required = true
+		case "minimum":
+			s.Minimum = floatPtr(value)
+		case "maximum":
+			s.Maximum = floatPtr(value)
+		case "minLength":
+			n, _ := strconv.Atoi(value)
+			s.MinLength = &n
+		case "maxLength":
+			n, _ := strconv.Atoi(value)
+			s.MaxLength = &n
+		case "enum":
+			s.Enum = strings.Split(value, "|")
+		case "format":
+			s.Format = value
+		default:
+			panic(fmt.Sprintf("openapi: unknown struct tag option %q", key))
+		}
+	}
+	return required
+}
+
+func floatPtr(s string) *float64 {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		panic(fmt.Sprintf("openapi: invalid bound %q", s))
+	}
+	return &f
+}
+
+func exportedName(name string) string {
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// pathParamNames returns the {name} segments of a chi path.
+func pathParamNames(path string) []string {
+	var names []string
+	for _, seg := range strings.Split(path, "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			name, _, _ := strings.Cut(seg[1:len(seg)-1], ":")
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func hasParam(params []Parameter, name, in string) bool {
+	for _, p := range params {
+		if p.Name == name && p.In == in {
+			return true
+		}
+	}
+	return false
+}
+
+// Parameter and schema helpers for route declarations.
+
+func queryParam(name string, s *Schema, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: s}
+}
+
+func requiredQueryParam(name string, s *Schema, description string) Parameter {
+	p := queryParam(name, s, description)
+	p.Required = true
+	return p
+}
+
+func headerParam(name, description string) Parameter {
+	return Parameter{Name: name, In: "header", Description: description, Schema: stringSchema()}
+}
+
+func pathParam(name string, s *Schema) Parameter {
+	return Parameter{Name: name, In: "path", Required: true, Schema: s}
+}
+
+func stringSchema() *Schema {
+	return &Schema{Type: "string"}
+}
+
+func boolSchema() *Schema {
+	return &Schema{Type: "boolean"}
+}
+
+func intSchema(lo, hi int) *Schema {
+	minimum, maximum := float64(lo), float64(hi)
+	return &Schema{Type: "integer", Minimum: &minimum, Maximum: &maximum}
+}
+
+func idSchema() *Schema {
+	minimum := 1.0
+	return &Schema{Type: "integer", Format: "int64", Minimum: &minimum}
+}
+
+func enumSchema(values ...string) *Schema {
+	return &Schema{Type: "string", Enum: values}
+}
+
+func patternSchema(pattern string) *Schema {
+	return &Schema{Type: "string", Pattern: pattern, pattern: regexp.MustCompile(pattern)}
+}
+
+// yearMonthParams are the year and month query parameters of single-month
+// endpoints.
+func yearMonthParams() []Parameter {
+	return []Parameter{
+		requiredQueryParam("year", intSchema(1970, 3000), ""),
+		requiredQueryParam("month", intSchema(1, 12), ""),
+	}
+}
+
+// problemSchema describes the application/problem+json error body.
+func problemSchema() *Schema {
+	str := func(description string) *Schema { return &Schema{Type: "string", Description: description} }
+	return &Schema{
+		Type:        "object",
+		Description: "RFC 7807 problem details, served as application/problem+json",
+		Required:    []string{"type", "title", "status", "code"},
+		Properties: map[string]*Schema{
+			"type":       str(""),
+			"title":      str(""),
+			"status":     {Type: "integer"},
+			"detail":     str(""),
+			"instance":   str(""),
+			"code":       str("Stable, machine-readable error code"),
+			"request_id": str("Value of the X-Request-ID response header"),
+			"errors": {
+				Type:        "array",
+				Description: "Per-field details for validation failures",
+				Items: &Schema{Type: "object", Properties: map[string]*Schema{
+					"field":   str(""),
+					"message": str(""),
+				}},
+			},
+		},
+	}
+}
@@ -0,0 +1,150 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mdco1990/webapp/internal/config"
+	"github.com/mdco1990/webapp/internal/middleware"
+)
+
+func TestOpenAPIDocument(t *testing.T) {
+	database := setupTestDB(t)
+	defer func() { _ = database.Close() }()
+	router := newTestRouter(t, config.Config{Env: "test"}, database)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+		Paths   map[string]map[string]struct {
+			Tags        []string `json:"tags"`
+			RequestBody *struct {
+				Content map[string]struct {
+					Schema struct {
+						Ref string `json:"$ref"`
+					} `json:"schema"`
+				} `json:"content"`
+			} `json:"requestBody"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Expected a JSON document, got %v", err)
+	}
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("Expected OpenAPI 3.1.0, got %q", doc.OpenAPI)
+	}
+	for _, path := range []string{
+		"/healthz", "/auth/login", "/api/v1/expenses", "/api/v1/income-sources/{id}",
+		"/api/v1/secure/income-sources", "/api/v1/admin/users/{id}/approve", "/shared/{token}",
+	} {
+		if _, ok := doc.Paths[path]; !ok {
+			t.Errorf("Expected %s in the document", path)
+		}
+	}
+	create := doc.Paths["/api/v1/income-sources"]["post"]
+	if create.RequestBody == nil ||
+		create.RequestBody.Content[contentTypeJSON].Schema.Ref != "#/components/schemas/CreateIncomeSourceRequest" {
+		t.Errorf("Expected the create request schema, got %+v", create.RequestBody)
+	}
+	if tags := doc.Paths["/api/v1/admin/users"]["get"].Tags; len(tags) != 1 || tags[0] != "Admin" {
+		t.Errorf("Expected the Admin tag, got %v", tags)
+	}
+	if _, ok := doc.Components.Schemas["ErrorResponse"]; !ok {
+		t.Error("Expected the ErrorResponse schema")
+	}
+}
+
+type validateTestItem struct {
+	Name string `json:"name" openapi:"required,minLength=1"`
+}
+
+type validateTestRequest struct {
+	Kind   string             `json:"kind" openapi:"required,enum=a|b"`
+	Amount int64              `json:"amount" openapi:"minimum=0"`
+	Items  []validateTestItem `json:"items"`
+	Note   *string            `json:"note"`
+}
+
+func TestValidateRequest(t *testing.T) {
+	r := chi.NewRouter()
+	var got string
+	NewRoutes(r, NewOpenAPIDocument()).Add(
+		route(http.MethodPost, "/things", func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			got = string(body)
+			respondJSON(w, http.StatusOK, statusResponse{Status: "ok"})
+		}).
+			WithParams(queryParam("limit", intSchema(1, 10), ""), queryParam("dry_run", boolSchema(), "")).
+			WithRequest(validateTestRequest{}),
+	)
+
+	tests := []struct {
+		name        string
+		query       string
+		contentType string
+		body        string
+		status      int
+		field       string
+		message     string
+	}{
+		{"valid", "?limit=5&dry_run=true", contentTypeJSON, `{"kind":"a","amount":3,"items":[{"name":"x"}],"note":null}`,
+			http.StatusOK, "", ""},
+		{"no content type", "", "", `{"kind":"b"}`, http.StatusOK, "", ""},
+		{"limit out of range", "?limit=0", contentTypeJSON, `{"kind":"a"}`,
+			http.StatusBadRequest, "limit", "must be between 1 and 10"},
+		{"limit not a number", "?limit=x", contentTypeJSON, `{"kind":"a"}`,
+			http.StatusBadRequest, "limit", "must be a number"},
+		{"bad boolean", "?dry_run=maybe", contentTypeJSON, `{"kind":"a"}`,
+			http.StatusBadRequest, "dry_run", "must be true or false"},
+		{"missing required", "", contentTypeJSON, `{"amount":1}`, http.StatusBadRequest, "kind", "is required"},
+		{"enum", "", contentTypeJSON, `{"kind":"c"}`, http.StatusBadRequest, "kind", "must be one of a, b"},
+		{"wrong type", "", contentTypeJSON, `{"kind":"a","amount":"3"}`, http.StatusBadRequest, "amount", "must be a number"},
+		{"fraction", "", contentTypeJSON, `{"kind":"a","amount":1.5}`, http.StatusBadRequest, "amount", "must be an integer"},
+		{"nested", "", contentTypeJSON, `{"kind":"a","items":[{"name":"x"},{}]}`,
+			http.StatusBadRequest, "items[1].name", "is required"},
+		{"empty body", "", contentTypeJSON, ``, http.StatusBadRequest, "body", "is required"},
+		{"not an object", "", contentTypeJSON, `[1]`, http.StatusBadRequest, "body", "must be an object"},
+		{"malformed", "", contentTypeJSON, `{"kind":`, http.StatusBadRequest, "", ""},
+		{"wrong content type", "", "text/plain", `{"kind":"a"}`, http.StatusUnsupportedMediaType, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = ""
+			req := httptest.NewRequest(http.MethodPost, "/things"+tt.query, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set(headerContentType, tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("Expected %d, got %d %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.status == http.StatusOK {
+				if got != tt.body {
+					t.Errorf("Expected the handler to read the original body, got %q", got)
+				}
+				return
+			}
+			if tt.field == "" {
+				return
+			}
+			var p middleware.Problem
+			_ = json.Unmarshal(w.Body.Bytes(), &p)
+			if len(p.Errors) == 0 || p.Errors[0].Field != tt.field || p.Errors[0].Message != tt.message {
+				t.Errorf("Expected %s %q, got %+v", tt.field, tt.message, p.Errors)
+			}
+		})
+	}
+}
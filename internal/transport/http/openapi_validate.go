@@ -0,0 +1,255 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+// maxValidationErrors caps the field details of one rejected request.
+const maxValidationErrors = 20
+
+// uuidPattern matches strings of format uuid, in any case.
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// ValidateRequest returns middleware that checks a request's query
+// parameters and JSON body against op before calling next. A mismatch is
+// answered with 400 and one field error per problem, like the handlers' own
+// validation errors. A body is taken to be JSON when the request has no
+// Content-Type.
+func (d *OpenAPIDocument) ValidateRequest(op *Operation) func(http.Handler) http.Handler {
+	var jsonBody *Schema
+	if op.RequestBody != nil {
+		if mt, ok := op.RequestBody.Content[contentTypeJSON]; ok {
+			jsonBody = mt.Schema
+		}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if fields := d.checkQuery(op.Parameters, r); len(fields) > 0 {
+				respondError(w, r, domain.Invalid("invalid query parameters", fields...))
+				return
+			}
+			if jsonBody == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if ct := r.Header.Get(headerContentType); ct != "" {
+				if mt, _, _ := mime.ParseMediaType(ct); mt != contentTypeJSON {
+					respondErr(w, r, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+					return
+				}
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				respondErr(w, r, http.StatusBadRequest, invalidBodyMsg)
+				return
+			}
+			_ = r.Body.Close()
+			var v any
+			dec := json.NewDecoder(bytes.NewReader(body))
+			dec.UseNumber()
+			switch err := dec.Decode(&v); {
+			case err == io.EOF:
+				respondError(w, r, domain.Invalid(invalidBodyMsg,
+					domain.FieldError{Field: "body", Message: "is required"}))
+				return
+			case err != nil:
+				respondErr(w, r, http.StatusBadRequest, invalidBodyMsg)
+				return
+			}
+			c := schemaCheck{doc: d}
+			c.check(v, jsonBody, "")
+			if len(c.fields) > 0 {
+				respondError(w, r, domain.Invalid(invalidBodyMsg, c.fields...))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// checkQuery validates the query parameters among params.
+func (d *OpenAPIDocument) checkQuery(params []Parameter, r *http.Request) []domain.FieldError {
+	c := schemaCheck{doc: d}
+	values := r.URL.Query()
+	for _, p := range params {
+		if p.In != "query" {
+			continue
+		}
+		s := d.resolve(p.Schema)
+		raw, ok := values[p.Name]
+		if !ok || raw[0] == "" {
+			if p.Required {
+				c.fail(p.Name, "is required")
+			}
+			continue
+		}
+		var v any = raw[0]
+		switch s.Type {
+		case "integer", "number":
+			v = json.Number(raw[0])
+		case "boolean":
+			b, err := strconv.ParseBool(raw[0])
+			if err != nil {
+				c.fail(p.Name, "must be true or false")
+				continue
+			}
+			v = b
+		}
+		c.check(v, s, p.Name)
+	}
+	return c.fields
+}
+
+// resolve follows a component reference.
+func (d *OpenAPIDocument) resolve(s *Schema) *Schema {
+	for s.Ref != "" {
+		s = d.schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	return s
+}
+
+// schemaCheck collects the field errors of a value checked against a schema.
+type schemaCheck struct {
+	doc    *OpenAPIDocument
+	fields []domain.FieldError
+}
+
+func (c *schemaCheck) fail(field, msg string) {
+	if field == "" {
+		field = "body"
+	}
+	if len(c.fields) < maxValidationErrors {
+		c.fields = append(c.fields, domain.FieldError{Field: field, Message: msg})
+	}
+}
+
+// check validates v, as decoded by encoding/json with UseNumber, against s.
+func (c *schemaCheck) check(v any, s *Schema, field string) {
+	s = c.doc.resolve(s)
+	if v == nil {
+		if s.Type != "" && !s.Nullable {
+			c.fail(field, "must not be null")
+		}
+		return
+	}
+
+	switch s.Type {
+	case "":
+		return
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			c.fail(field, "must be an object")
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				c.fail(joinField(field, name), "is required")
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(obj)) {
+			value := obj[name]
+			if ps, ok := s.Properties[name]; ok {
+				c.check(value, ps, joinField(field, name))
+			} else if s.AdditionalProperties != nil {
+				c.check(value, s.AdditionalProperties, joinField(field, name))
+			}
+		}
+	case "array":
+		items, ok := v.([]any)
+		if !ok {
+			c.fail(field, "must be an array")
+			return
+		}
+		for i, item := range items {
+			c.check(item, s.Items, fmt.Sprintf("%s[%d]", field, i))
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			c.fail(field, "must be a string")
+			return
+		}
+		c.checkString(str, s, field)
+	case "integer", "number":
+		n, ok := v.(json.Number)
+		if !ok {
+			c.fail(field, "must be a number")
+			return
+		}
+		f, err := n.Float64()
+		if err != nil {
+			c.fail(field, "must be a number")
+			return
+		}
+		if s.Type == "integer" {
+			if _, err := n.Int64(); err != nil {
+				c.fail(field, "must be an integer")
+				return
+			}
+		}
+		c.checkRange(f, s, field)
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			c.fail(field, "must be true or false")
+		}
+	}
+}
+
+func (c *schemaCheck) checkString(str string, s *Schema, field string) {
+	if len(s.Enum) > 0 && !slices.Contains(s.Enum, str) {
+		c.fail(field, "must be one of "+strings.Join(s.Enum, ", "))
+		return
+	}
+	n := utf8.RuneCountInString(str)
+	switch {
+	case s.MinLength != nil && s.MaxLength != nil && (n < *s.MinLength || n > *s.MaxLength):
+		c.fail(field, fmt.Sprintf("must be %d to %d characters", *s.MinLength, *s.MaxLength))
+	case s.MinLength != nil && n < *s.MinLength:
+		c.fail(field, fmt.Sprintf("must be at least %d characters", *s.MinLength))
+	case s.MaxLength != nil && n > *s.MaxLength:
+		c.fail(field, fmt.Sprintf("must be at most %d characters", *s.MaxLength))
+	case s.pattern != nil && !s.pattern.MatchString(str):
+		c.fail(field, "must match "+s.Pattern)
+	case s.Format == "date-time":
+		if _, err := time.Parse(time.RFC3339, str); err != nil {
+			c.fail(field, "must be an RFC 3339 date-time")
+		}
+	case s.Format == "uuid" && !uuidPattern.MatchString(str):
+		c.fail(field, "must be a UUID")
+	}
+}
+
+func (c *schemaCheck) checkRange(f float64, s *Schema, field string) {
+	switch {
+	case s.Minimum != nil && s.Maximum != nil && (f < *s.Minimum || f > *s.Maximum):
+		c.fail(field, fmt.Sprintf("must be between %g and %g", *s.Minimum, *s.Maximum))
+	case s.Minimum != nil && f < *s.Minimum:
+		c.fail(field, fmt.Sprintf("must be at least %g", *s.Minimum))
+	case s.Maximum != nil && f > *s.Maximum:
+		c.fail(field, fmt.Sprintf("must be at most %g", *s.Maximum))
+	}
+}
+
+func joinField(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
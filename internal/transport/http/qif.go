@@ -0,0 +1,135 @@
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/qif"
+	"github.com/mdco1990/webapp/internal/repository"
+	"github.com/mdco1990/webapp/internal/security"
+)
+
+// qifImportResult summarises what an import created.
+type qifImportResult struct {
+	ExpensesCreated      int      `json:"expenses_created"`
+	IncomeSourcesCreated int      `json:"income_sources_created"`
+	Skipped              int      `json:"skipped"`
+	Errors               []string `json:"errors,omitempty"`
+}
+
+// registerQIFEndpoints wires QIF import/export endpoints
+func registerQIFEndpoints(api *Routes, repo *repository.Repository) {
+	api.Tagged("Import and Export").Add(
+		route(http.MethodPost, "/import/qif", handleImportQIF(repo)).
+			WithSummary("Import a QIF file").
+			WithDescription("Creates an expense for each payment and an income source for each deposit. Rows "+
+				"that fail validation are skipped and listed in `errors`; the others are written together, so "+
+				"an import that fails writes nothing.").
+			WithParams(queryParam("date_format", enumSchema("mdy", "dmy"), "Date order in the file (default mdy)")).
+			WithRawRequest("application/qif").
+			WithResponse(http.StatusOK, qifImportResult{}),
+		route(http.MethodGet, "/export/qif", handleExportQIF(repo)).
+			WithSummary("Export a month or a year as QIF").
+			WithParams(exportPeriodParams()...).
+			WithRawResponse(http.StatusOK, "application/qif"),
+	)
+}
+
+// handleImportQIF parses a QIF upload and creates expenses and income sources.
+// Pass date_format=dmy for files written with day-first dates. Rows that fail
+// validation are skipped and reported; the rest are written in one
+// transaction, so a failed import writes nothing and can be retried.
+func handleImportQIF(repo *repository.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := getUserIDFromContext(r.Context())
+
+		reader := qif.NewReader(r.Body)
+		reader.DayFirst = r.URL.Query().Get("date_format") == "dmy"
+		txs, err := reader.ReadAll()
+		if err != nil {
+			respondErr(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		mapped := qif.ToDomain(txs)
+		result := qifImportResult{Skipped: mapped.Skipped}
+		ops := make([]domain.BatchOperation, 0, len(mapped.Expenses)+len(mapped.IncomeSources))
+
+		for i := range mapped.Expenses {
+			validated, err := security.ValidateExpense(&mapped.Expenses[i])
+			if err != nil {
+				result.Skipped++
+				result.Errors = append(result.Errors, fmt.Sprintf("expense %q: %v", mapped.Expenses[i].Description, err))
+				continue
+			}
+			ops = append(ops, domain.BatchOperation{Op: domain.BatchCreate, Type: domain.BatchExpense, Expense: validated})
+		}
+
+		for _, req := range mapped.IncomeSources {
+			validated, err := security.ValidateCreateIncomeSourceRequest(req)
+			if err != nil {
+				result.Skipped++
+				result.Errors = append(result.Errors, fmt.Sprintf("income %q: %v", req.Name, err))
+				continue
+			}
+			ops = append(ops, domain.BatchOperation{
+				Op: domain.BatchCreate, Type: domain.BatchIncomeSource, IncomeSource: validated,
+			})
+		}
+
+		if len(ops) > 0 {
+			if _, err := repo.ApplyBatch(r.Context(), userID, ops); err != nil {
+				respondError(w, r, err)
+				return
+			}
+		}
+		for _, op := range ops {
+			if op.Type == domain.BatchExpense {
+				result.ExpensesCreated++
+			} else {
+				result.IncomeSourcesCreated++
+			}
+		}
+
+		respondJSON(w, http.StatusOK, result)
+	}
+}
+
+// handleExportQIF writes a month (year+month) or a whole year (year only) as QIF.
+func handleExportQIF(repo *repository.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := getUserIDFromContext(r.Context())
+		period, err := parseExportPeriod(r)
+		if err != nil {
+			respondErr(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// Load everything first so a failure can still produce a clean error response.
+		var txs []qif.Transaction
+		for _, month := range period.Months {
+			data, err := repo.GetMonthlyData(r.Context(), userID, domain.YearMonth{Year: period.Year, Month: month})
+			if err != nil {
+				respondErr(w, r, http.StatusInternalServerError, "failed to get monthly data")
+				return
+			}
+			txs = append(txs, qif.FromMonthlyData(data)...)
+		}
+
+		w.Header().Set("Content-Type", "application/qif")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", period.filename("budget", "qif")))
+		w.WriteHeader(http.StatusOK)
+
+		qw := qif.NewWriter(w)
+		if err := qw.WriteHeader(qif.AccountBank); err != nil {
+			return
+		}
+		for _, tx := range txs {
+			if err := qw.Write(tx); err != nil {
+				return
+			}
+		}
+		_ = qw.Flush()
+	}
+}
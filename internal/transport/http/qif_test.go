@@ -0,0 +1,85 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/repository"
+)
+
+const importQIF = `!Type:Bank
+D01/15/2024
+T-1,234.56
+PLandlord
+LRent
+^
+D01/20/2024
+T-12.50
+PCoffee
+^
+D01/31/2024
+T2500.00
+PACME Corp
+LSalary
+^
+`
+
+func TestImportQIF(t *testing.T) {
+	database := setupTestDB(t)
+	defer func() { _ = database.Close() }()
+	database.SetMaxOpenConns(1)
+	repo := repository.New(database)
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userIDKey, int64(1))))
+		})
+	})
+	registerQIFEndpoints(NewRoutes(r, NewOpenAPIDocument()), repo)
+	upload := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/import/qif", strings.NewReader(importQIF)))
+		return w
+	}
+	january := domain.YearMonth{Year: 2024, Month: 1}
+	ctx := context.Background()
+
+	// The income source is written after both expenses; failing it fails
+	// the whole import.
+	if _, err := database.Exec(`CREATE TRIGGER fail_import BEFORE INSERT ON income_sources
+		BEGIN SELECT RAISE(ABORT, 'refused'); END`); err != nil {
+		t.Fatalf("Failed to create trigger: %v", err)
+	}
+	if w := upload(); w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400 for the refused insert, got %d %s", w.Code, w.Body.String())
+	}
+	if expenses, _ := repo.ListExpenses(ctx, january); len(expenses) != 0 {
+		t.Errorf("Expected no expenses written by the failed import, got %+v", expenses)
+	}
+
+	// A retry imports every row once.
+	if _, err := database.Exec(`DROP TRIGGER fail_import`); err != nil {
+		t.Fatalf("Failed to drop trigger: %v", err)
+	}
+	w := upload()
+	var result qifImportResult
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &result) != nil {
+		t.Fatalf("Expected 200, got %d %s", w.Code, w.Body.String())
+	}
+	if result.ExpensesCreated != 2 || result.IncomeSourcesCreated != 1 || result.Skipped != 0 {
+		t.Errorf("Expected 2 expenses and 1 income source, got %+v", result)
+	}
+	if expenses, _ := repo.ListExpenses(ctx, january); len(expenses) != 2 {
+		t.Errorf("Expected 2 expenses, got %+v", expenses)
+	}
+	if sources, _ := repo.ListIncomeSources(ctx, 1, january); len(sources) != 1 || sources[0].AmountCents != 250000 {
+		t.Errorf("Expected the salary, got %+v", sources)
+	}
+}
@@ -0,0 +1,83 @@
+package httpapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mdco1990/webapp/internal/config"
+	"github.com/mdco1990/webapp/internal/middleware"
+	"github.com/mdco1990/webapp/internal/storage"
+)
+
+// rateLimits builds the router's rate limiters, which share one store with
+// the Idempotency-Key records.
+type rateLimits struct {
+	cfg   config.Config
+	store storage.Provider
+	ips   *middleware.ClientIPResolver
+}
+
+// newRateLimits sets up rate limiting from cfg. It fails if the configured
+// store cannot be opened or TRUSTED_PROXIES is invalid, rather than limiting
+// per instance or by the wrong client IP.
+func newRateLimits(cfg config.Config) (*rateLimits, error) {
+	ips, err := middleware.NewClientIPResolver(cfg.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("TRUSTED_PROXIES: %w", err)
+	}
+	rl := &rateLimits{cfg: cfg, ips: ips}
+	if cfg.RateLimitStore == "sqlite" {
+		store, err := storage.NewSQLiteStorage(cfg.RateLimitStorePath, storage.Options{})
+		if err != nil {
+			return nil, fmt.Errorf("open rate limit store %s: %w", cfg.RateLimitStorePath, err)
+		}
+		rl.store = store
+	} else {
+		rl.store = storage.NewMemoryStorage(storage.Options{})
+	}
+	return rl, nil
+}
+
+// limit returns a limiter allowing perMinute requests a minute per key.
+func (rl *rateLimits) limit(name string, perMinute int, key func(*http.Request) string) func(http.Handler) http.Handler {
+	return middleware.RateLimit(middleware.RateLimitConfig{
+		Store:  rl.store,
+		Policy: middleware.RateLimitPolicy{Name: name, Limit: perMinute, Window: time.Minute},
+		Key:    key,
+	})
+}
+
+// public limits every request per client IP.
+func (rl *rateLimits) public() func(http.Handler) http.Handler {
+	return rl.limit("public", rl.cfg.RateLimitPerMinute, rl.ipKey)
+}
+
+// api limits API requests per caller.
+func (rl *rateLimits) api() func(http.Handler) http.Handler {
+	return rl.limit("api", rl.cfg.APIRateLimitPerMinute, rl.callerKey)
+}
+
+// login limits credential attempts per client IP, much more strictly, to
+// slow down password guessing.
+func (rl *rateLimits) login() func(http.Handler) http.Handler {
+	return rl.limit("login", rl.cfg.LoginRateLimitPerMinute, rl.ipKey)
+}
+
+func (rl *rateLimits) ipKey(r *http.Request) string {
+	return "ip:" + rl.ips.ClientIP(r)
+}
+
+// callerKey identifies a caller by session user, else by API key, else by IP.
+func (rl *rateLimits) callerKey(r *http.Request) string {
+	if id := userScope(r); id != "" {
+		return "user:" + id
+	}
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		sum := sha256.Sum256([]byte(key))
+		return "key:" + hex.EncodeToString(sum[:8])
+	}
+	return rl.ipKey(r)
+}
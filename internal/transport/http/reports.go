@@ -0,0 +1,149 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/repository"
+	"github.com/mdco1990/webapp/internal/service"
+)
+
+const errReportNotFound = "report not found"
+
+// reportFileURL is the download URL recorded in domain.ExpenseReport.FileURL.
+func reportFileURL(reportID string) string {
+	return "/api/v1/reports/" + reportID + "/download"
+}
+
+// registerReportEndpoints wires PDF statement endpoints
+func registerReportEndpoints(api *Routes, repo *repository.Repository, bg *service.BackgroundService) {
+	reportID := pathParam("id", &Schema{Type: "string", Description: "Report ID"})
+	api.Tagged("Reports").Route("/reports", func(reports *Routes) {
+		reports.Add(
+			route(http.MethodPost, "/monthly", handleCreateMonthlyReport(repo, bg)).
+				WithSummary("Generate a monthly PDF statement").
+				WithRequest(monthlyReportRequest{}).
+				WithResponse(http.StatusAccepted, domain.ExpenseReport{}),
+			route(http.MethodGet, "/{id}", handleGetReport(bg)).
+				WithSummary("Get report status").
+				WithParams(reportID).
+				WithResponse(http.StatusOK, domain.ExpenseReport{}),
+			route(http.MethodGet, "/{id}/download", handleDownloadReport(bg)).
+				WithSummary("Download a generated statement").
+				WithDescription("The statement can be downloaded for an hour after it is generated.").
+				WithParams(reportID).
+				WithRawResponse(http.StatusOK, "application/pdf"),
+		)
+	})
+}
+
+// monthlyReportRequest is the body of POST /reports/monthly. Locale and
+// currency default to the user's settings.
+type monthlyReportRequest struct {
+	Year     int    `json:"year" openapi:"required,minimum=1970,maximum=3000"`
+	Month    int    `json:"month" openapi:"required,minimum=1,maximum=12"`
+	Locale   string `json:"locale"`
+	Currency string `json:"currency"`
+}
+
+// handleCreateMonthlyReport queues a monthly PDF statement
+func handleCreateMonthlyReport(repo *repository.Repository, bg *service.BackgroundService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := getUserIDFromContext(r.Context())
+		var req monthlyReportRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondErr(w, r, http.StatusBadRequest, invalidBodyMsg)
+			return
+		}
+		// Fall back to the saved settings like the spreadsheet exports do.
+		q := r.URL.Query()
+		if req.Locale != "" {
+			q.Set("locale", req.Locale)
+		}
+		if req.Currency != "" {
+			q.Set("currency", req.Currency)
+		}
+		r.URL.RawQuery = q.Encode()
+		format := resolveExportFormat(r, repo, userID)
+
+		period := domain.YearMonth{Year: req.Year, Month: req.Month}
+		id, err := bg.ProcessMonthlyReportAsync(r.Context(), service.MonthlyReportRequest{
+			UserID:   userID,
+			Period:   period,
+			Locale:   format.Locale,
+			Currency: format.Currency,
+			FileURL:  reportFileURL,
+		})
+		if err != nil {
+			respondErr(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondJSON(w, http.StatusAccepted, domain.ExpenseReport{
+			ID:     id,
+			UserID: userID,
+			Type:   "monthly",
+			Period: period,
+			Status: string(service.TaskStatusProcessing),
+		})
+	}
+}
+
+// handleGetReport returns the report metadata; FileURL is set once it is ready
+func handleGetReport(bg *service.BackgroundService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := getUserIDFromContext(r.Context())
+		task, err := bg.GetUserTask(chi.URLParam(r, "id"), userID)
+		if err != nil || task.Type != service.TaskTypeExpenseReport {
+			respondErr(w, r, http.StatusNotFound, errReportNotFound)
+			return
+		}
+		if report, ok := task.Result.(*domain.ExpenseReport); ok {
+			respondJSON(w, http.StatusOK, report)
+			return
+		}
+		period, _ := task.Data["year_month"].(domain.YearMonth)
+		status := string(task.Status)
+		if task.Status == service.TaskStatusPending {
+			status = string(service.TaskStatusProcessing)
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"id":      task.ID,
+			"user_id": userID,
+			"type":    "monthly",
+			"period":  period,
+			"status":  status,
+			"error":   task.Error,
+		})
+	}
+}
+
+// handleDownloadReport serves the generated PDF
+func handleDownloadReport(bg *service.BackgroundService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := getUserIDFromContext(r.Context())
+		id := chi.URLParam(r, "id")
+		task, err := bg.GetUserTask(id, userID)
+		if err != nil || task.Type != service.TaskTypeExpenseReport {
+			respondErr(w, r, http.StatusNotFound, errReportNotFound)
+			return
+		}
+		pdf, err := bg.GetTaskArtifact(id, userID)
+		if err != nil {
+			respondErr(w, r, http.StatusConflict, "report not ready")
+			return
+		}
+		filename := "statement.pdf"
+		if period, ok := task.Data["year_month"].(domain.YearMonth); ok {
+			filename = fmt.Sprintf("statement-%04d-%02d.pdf", period.Year, period.Month)
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		w.Header().Set("Content-Length", strconv.Itoa(len(pdf)))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(pdf)
+	}
+}
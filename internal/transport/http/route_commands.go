@@ -65,7 +65,7 @@ func (ds *DocumentationState) Register(r chi.Router) error {
 	}
 
 	// Register OpenAPI spec handler
-	specHandler := NewOpenAPISpecHandler()
+	specHandler := NewOpenAPISpecHandler(ds.context.doc)
 	r.Get("/openapi.json", specHandler.ServeSpec)
 
 	return nil
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/mdco1990/webapp/internal/domain"
 	"github.com/mdco1990/webapp/internal/repository"
 )
 
@@ -23,21 +24,149 @@ type BaseRouteRegistry struct {
 // RouteContext holds route configuration and dependencies
 type RouteContext struct {
 	repo *repository.Repository
+	doc  *OpenAPIDocument
 }
 
 // NewRouteContext creates a new route context
-func NewRouteContext(repo *repository.Repository) *RouteContext {
+func NewRouteContext(repo *repository.Repository, doc *OpenAPIDocument) *RouteContext {
 	return &RouteContext{
 		repo: repo,
+		doc:  doc,
 	}
 }
 
-// RouteDefinition represents a route with its configuration
+// RouteDefinition represents a route with its configuration. Besides the
+// handler it carries what the OpenAPI document says about the route; Request
+// and Response are values of the JSON body types (nil for none).
 type RouteDefinition struct {
 	Method     string
 	Path       string
 	Handler    http.HandlerFunc
 	Middleware []func(http.Handler) http.Handler
+
+	Tag          string
+	Security     []map[string][]string // overrides the group's
+	Scope        string                // access token scope; overrides the group's
+	ScopesByBody bool                  // the handler checks scopes against the request body
+	Summary      string
+	Description  string
+	Params       []Parameter
+	Request      any
+	RequestType  string // content type of a non-JSON request body
+	Status       int    // success status; 200 if zero
+	Response     any
+	ResponseType string // content type of a non-JSON response body
+}
+
+// Routes mounts routes on a chi router and records each, under its full
+// path, in the OpenAPI document, so a route is declared once. Every route
+// is wrapped in its access token scope check and request validation
+// against its documented operation, after its own middleware.
+type Routes struct {
+	router      chi.Router
+	doc         *OpenAPIDocument
+	prefix      string
+	tag         string
+	security    []map[string][]string
+	scope       string
+	writeParams []Parameter
+}
+
+// NewRoutes declares routes on r, documenting them in doc.
+func NewRoutes(r chi.Router, doc *OpenAPIDocument) *Routes {
+	return &Routes{router: r, doc: doc}
+}
+
+// Route mounts a subrouter at prefix, like chi's Route.
+func (rs *Routes) Route(prefix string, fn func(sub *Routes)) {
+	rs.router.Route(prefix, func(r chi.Router) {
+		sub := *rs
+		sub.router, sub.prefix = r, joinRoutePath(rs.prefix, prefix)
+		fn(&sub)
+	})
+}
+
+// With returns a view of rs whose routes run behind middleware, like chi's
+// With.
+func (rs *Routes) With(middleware ...func(http.Handler) http.Handler) *Routes {
+	sub := *rs
+	sub.router = rs.router.With(middleware...)
+	return &sub
+}
+
+// Use appends middleware to the underlying router.
+func (rs *Routes) Use(middleware ...func(http.Handler) http.Handler) {
+	rs.router.Use(middleware...)
+}
+
+// Tagged returns a view of rs whose routes default to tag.
+func (rs *Routes) Tagged(tag string) *Routes {
+	sub := *rs
+	sub.tag = tag
+	return &sub
+}
+
+// Secured returns a view of rs whose routes are documented with security.
+func (rs *Routes) Secured(security []map[string][]string) *Routes {
+	sub := *rs
+	sub.security = security
+	return &sub
+}
+
+// Scoped returns a view of rs whose routes require scope of access tokens.
+func (rs *Routes) Scoped(scope string) *Routes {
+	sub := *rs
+	sub.scope = scope
+	return &sub
+}
+
+// WithWriteParams returns a view of rs that documents params on every
+// route except GET and HEAD ones.
+func (rs *Routes) WithWriteParams(params ...Parameter) *Routes {
+	sub := *rs
+	sub.writeParams = params
+	return &sub
+}
+
+// Add mounts and documents routes.
+func (rs *Routes) Add(routes ...*RouteBuilder) {
+	for _, rb := range routes {
+		def := rb.Build()
+		op := rs.doc.addRoute(joinRoutePath(rs.prefix, def.Path), def, rs)
+		middleware := def.Middleware[:len(def.Middleware):len(def.Middleware)]
+		if scope := rs.routeScope(def); scope != "" {
+			middleware = append(middleware, requireScope(scope))
+		}
+		middleware = append(middleware, rs.doc.ValidateRequest(op))
+		rs.router.With(middleware...).Method(def.Method, def.Path, def.Handler)
+	}
+}
+
+// routeScope returns the access token scope a route requires: its own, its
+// group's, or else read:budget to read and admin to write. It is empty for
+// routes whose handler checks scopes.
+func (rs *Routes) routeScope(def RouteDefinition) string {
+	switch {
+	case def.ScopesByBody:
+		return ""
+	case def.Scope != "":
+		return def.Scope
+	case rs.scope != "":
+		return rs.scope
+	case def.Method == http.MethodGet || def.Method == http.MethodHead:
+		return domain.ScopeReadBudget
+	default:
+		return domain.ScopeAdmin
+	}
+}
+
+// joinRoutePath joins a mount prefix and a route path the way chi matches
+// them; a subrouter's "/" is the prefix itself.
+func joinRoutePath(prefix, path string) string {
+	if path == "/" && prefix != "" {
+		return prefix
+	}
+	return prefix + path
 }
 
 // RouteStrategy defines the interface for different route strategies
@@ -55,7 +184,7 @@ func (drs *DocumentationRouteStrategy) GetName() string {
 }
 
 // Register registers documentation routes
-func (drs *DocumentationRouteStrategy) Register(r chi.Router, _ *RouteContext) error {
+func (drs *DocumentationRouteStrategy) Register(r chi.Router, ctx *RouteContext) error {
 	// Register redirects
 	redirects := map[string]string{
 		"/api":     "/api/",
@@ -71,7 +200,7 @@ func (drs *DocumentationRouteStrategy) Register(r chi.Router, _ *RouteContext) e
 	}
 
 	// Register OpenAPI spec handler
-	specHandler := NewOpenAPISpecHandler()
+	specHandler := NewOpenAPISpecHandler(ctx.doc)
 	r.Get("/openapi.json", specHandler.ServeSpec)
 
 	return nil
@@ -94,51 +223,118 @@ func (ars *AdminRouteStrategy) Register(_ chi.Router, _ *RouteContext) error {
 
 // RouteBuilder provides a fluent interface for building routes
 type RouteBuilder struct {
-	method     string
-	path       string
-	handler    http.HandlerFunc
-	middleware []func(http.Handler) http.Handler
+	def RouteDefinition
 }
 
 // NewRouteBuilder creates a new route builder
 func NewRouteBuilder() *RouteBuilder {
-	return &RouteBuilder{
-		middleware: []func(http.Handler) http.Handler{},
-	}
+	return &RouteBuilder{def: RouteDefinition{
+		Middleware: []func(http.Handler) http.Handler{},
+	}}
+}
+
+// route starts a route declaration
+func route(method, path string, handler http.HandlerFunc) *RouteBuilder {
+	return NewRouteBuilder().WithMethod(method).WithPath(path).WithHandler(handler)
 }
 
 // WithMethod sets the HTTP method for the route
 func (rb *RouteBuilder) WithMethod(method string) *RouteBuilder {
-	rb.method = method
+	rb.def.Method = method
 	return rb
 }
 
 // WithPath sets the path for the route
 func (rb *RouteBuilder) WithPath(path string) *RouteBuilder {
-	rb.path = path
+	rb.def.Path = path
 	return rb
 }
 
 // WithHandler sets the handler for the route
 func (rb *RouteBuilder) WithHandler(handler http.HandlerFunc) *RouteBuilder {
-	rb.handler = handler
+	rb.def.Handler = handler
 	return rb
 }
 
 // WithMiddleware adds middleware to the route
 func (rb *RouteBuilder) WithMiddleware(middleware func(http.Handler) http.Handler) *RouteBuilder {
-	rb.middleware = append(rb.middleware, middleware)
+	rb.def.Middleware = append(rb.def.Middleware, middleware)
+	return rb
+}
+
+// WithTag sets the route's OpenAPI tag, overriding the group's
+func (rb *RouteBuilder) WithTag(tag string) *RouteBuilder {
+	rb.def.Tag = tag
+	return rb
+}
+
+// WithSecurity sets the route's security requirements, overriding the group's
+func (rb *RouteBuilder) WithSecurity(security []map[string][]string) *RouteBuilder {
+	rb.def.Security = security
+	return rb
+}
+
+// WithScope sets the access token scope the route requires, overriding the
+// group's
+func (rb *RouteBuilder) WithScope(scope string) *RouteBuilder {
+	rb.def.Scope = scope
+	return rb
+}
+
+// WithScopesByBody leaves access token scopes to the handler, for routes
+// whose body says whether they read or write
+func (rb *RouteBuilder) WithScopesByBody() *RouteBuilder {
+	rb.def.ScopesByBody = true
+	return rb
+}
+
+// WithSummary sets the one-line summary of the route
+func (rb *RouteBuilder) WithSummary(summary string) *RouteBuilder {
+	rb.def.Summary = summary
+	return rb
+}
+
+// WithDescription sets the longer description of the route
+func (rb *RouteBuilder) WithDescription(description string) *RouteBuilder {
+	rb.def.Description = description
+	return rb
+}
+
+// WithParams adds query, header or path parameters
+func (rb *RouteBuilder) WithParams(params ...Parameter) *RouteBuilder {
+	rb.def.Params = append(rb.def.Params, params...)
+	return rb
+}
+
+// WithRequest sets the JSON request body type from a value of it
+func (rb *RouteBuilder) WithRequest(v any) *RouteBuilder {
+	rb.def.Request = v
+	return rb
+}
+
+// WithRawRequest sets the content type of a non-JSON request body
+func (rb *RouteBuilder) WithRawRequest(contentType string) *RouteBuilder {
+	rb.def.RequestType = contentType
+	return rb
+}
+
+// WithResponse sets the success status and JSON response body type (v may
+// be nil for no body)
+func (rb *RouteBuilder) WithResponse(status int, v any) *RouteBuilder {
+	rb.def.Status, rb.def.Response = status, v
+	return rb
+}
+
+// WithRawResponse sets the success status and the content type of a
+// non-JSON response body
+func (rb *RouteBuilder) WithRawResponse(status int, contentType string) *RouteBuilder {
+	rb.def.Status, rb.def.ResponseType = status, contentType
 	return rb
 }
 
 // Build creates the route definition
 func (rb *RouteBuilder) Build() RouteDefinition {
-	return RouteDefinition{
-		Method:     rb.method,
-		Path:       rb.path,
-		Handler:    rb.handler,
-		Middleware: rb.middleware,
-	}
+	return rb.def
 }
 
 // AdminRouteManager manages admin route registration with strategy pattern
@@ -148,8 +344,8 @@ type AdminRouteManager struct {
 }
 
 // NewAdminRouteManager creates a new admin route manager
-func NewAdminRouteManager(repo *repository.Repository) *AdminRouteManager {
-	context := NewRouteContext(repo)
+func NewAdminRouteManager(repo *repository.Repository, doc *OpenAPIDocument) *AdminRouteManager {
+	context := NewRouteContext(repo, doc)
 	return &AdminRouteManager{
 		strategies: []RouteStrategy{
 			&DocumentationRouteStrategy{},
@@ -9,12 +9,15 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
+	"github.com/mdco1990/webapp/internal/auth"
 	"github.com/mdco1990/webapp/internal/config"
 	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/events"
 	"github.com/mdco1990/webapp/internal/middleware"
 	"github.com/mdco1990/webapp/internal/repository"
 	"github.com/mdco1990/webapp/internal/security"
@@ -24,7 +27,10 @@ import (
 // Context key for user ID
 type contextKey string
 
-const userIDKey contextKey = "userID"
+const (
+	userIDKey      contextKey = "userID"
+	accessTokenKey contextKey = "accessToken"
+)
 
 // statusWriter wraps ResponseWriter to capture status code
 type statusWriter struct {
@@ -37,15 +43,56 @@ func (w *statusWriter) WriteHeader(code int) {
 	w.ResponseWriter.WriteHeader(code)
 }
 
-// NewRouter builds and returns the API HTTP router.
-func NewRouter(cfg config.Config, db *sql.DB) http.Handler {
+// Unwrap lets http.ResponseController reach the underlying writer, e.g. to
+// flush event streams.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Workers are the background loops behind the router's services. NewRouter
+// does not start them; run them for as long as the server is up.
+type Workers struct {
+	loops []func(context.Context)
+}
+
+// Run runs every worker until ctx is done and all of them have returned, so
+// work under way when ctx is cancelled, like a webhook delivery, finishes.
+func (w *Workers) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, loop := range w.loops {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			loop(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// NewRouter builds and returns the API HTTP router and the workers its
+// services need. It fails if configured JWT key files cannot be loaded or
+// rate limiting is misconfigured.
+func NewRouter(cfg config.Config, db *sql.DB) (http.Handler, *Workers, error) {
+	keys, err := loadJWTKeys(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	limits, err := newRateLimits(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	r := chi.NewRouter()
 
 	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   cfg.CORSAllowedOrigins,
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Content-Type", "X-API-Key", "Authorization"},
-		ExposedHeaders:   []string{"Set-Cookie"},
+		AllowedOrigins: cfg.CORSAllowedOrigins,
+		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders: []string{"Accept", "Content-Type", "X-API-Key", "Authorization", "If-Match", "Idempotency-Key"},
+		ExposedHeaders: []string{
+			"Set-Cookie", "ETag", "Idempotent-Replayed", "Retry-After", "X-Next-Cursor", "Link",
+			middleware.RateLimitLimitHeader, middleware.RateLimitRemainingHeader,
+			middleware.RateLimitResetHeader, middleware.RateLimitPolicyHeader,
+		},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -54,6 +101,10 @@ func NewRouter(cfg config.Config, db *sql.DB) http.Handler {
 	r.Use(middleware.SecurityHeaders)
 	r.Use(middleware.RequestID())
 
+	// Rate limits: every request per client IP here, API requests per caller
+	// and login attempts more strictly on their routes.
+	r.Use(limits.public())
+
 	// Enhanced security middleware
 	r.Use(security.HeadersMiddleware)
 	r.Use(security.InputValidationMiddleware)
@@ -64,7 +115,7 @@ func NewRouter(cfg config.Config, db *sql.DB) http.Handler {
 			defer func() {
 				if rec := recover(); rec != nil {
 					slog.Error("panic recovered", "err", rec)
-					respondErr(w, http.StatusInternalServerError, "internal error")
+					respondErr(w, r, http.StatusInternalServerError, "internal error")
 				}
 			}()
 			next.ServeHTTP(w, r)
@@ -99,21 +150,45 @@ func NewRouter(cfg config.Config, db *sql.DB) http.Handler {
 		})
 	})
 
-	r.Get("/healthz", func(w http.ResponseWriter, _ *http.Request) {
-		// Basic health info
-		status := map[string]any{
-			"status": "ok",
-			"env":    cfg.Env,
-		}
-		respondJSON(w, http.StatusOK, status)
-	})
-	// Readiness endpoint (lightweight)
-	r.Get("/readyz", func(w http.ResponseWriter, _ *http.Request) {
-		respondJSON(w, http.StatusOK, map[string]string{"status": "ready"})
-	})
+	doc := NewOpenAPIDocument()
+	routes := NewRoutes(r, doc)
+	routes.Tagged("Health").Add(
+		route(http.MethodGet, "/healthz", func(w http.ResponseWriter, _ *http.Request) {
+			// Basic health info
+			status := map[string]any{
+				"status": "ok",
+				"env":    cfg.Env,
+			}
+			respondJSON(w, http.StatusOK, status)
+		}).WithSummary("Health check"),
+		// Readiness endpoint (lightweight)
+		route(http.MethodGet, "/readyz", func(w http.ResponseWriter, _ *http.Request) {
+			respondJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+		}).WithSummary("Readiness check").
+			WithResponse(http.StatusOK, statusResponse{}),
+	)
 
 	repo := repository.New(db)
 	svc := service.New(repo)
+	bg := service.NewBackgroundService(repo)
+	if cfg.ShareLinkSecret == "" {
+		slog.Warn("SHARE_LINK_SECRET not set; share links will not survive a restart")
+	}
+	shares := service.NewShareService(repo, service.NewShareSigner([]byte(cfg.ShareLinkSecret)))
+
+	// Committed writes and background task outcomes are published on the bus
+	// for event streams and webhooks.
+	bus := events.NewEventBus(nil)
+	announceChanges(repo, bus)
+	bg.WithEventPublisher(bus)
+	feed := newChangeFeed(bus)
+	webhooks := service.NewWebhookService(repo)
+	if _, err := webhooks.Attach(bus); err != nil {
+		slog.Error("failed to attach webhooks", "err", err)
+	}
+	trash := service.NewTrashService(repo, cfg.TrashRetention)
+	tokens := service.NewTokenService(repo)
+	jwts := newJWTAuth(cfg, repo, keys, limits.store)
 
 	// Serve static files from docs directory
 	r.Route("/docs", func(docs chi.Router) {
@@ -122,65 +197,143 @@ func NewRouter(cfg config.Config, db *sql.DB) http.Handler {
 		docs.Handle("/*", http.StripPrefix("/docs", fs))
 	})
 
+	// OpenAPI document generated from the routes declared below
+	r.Get("/openapi.json", NewOpenAPISpecHandler(doc).ServeSpec)
+
 	// Admin/Docs routes (Swagger UI and DB admin proxy)
-	registerAdminRoutes(r, repo)
+	registerAdminRoutes(routes, repo, tokens, jwts)
 
 	// Authentication routes (public)
-	registerAuthRoutes(r, repo)
+	registerAuthRoutes(routes, repo, jwts, limits)
+
+	// Public keys verifying issued JWTs
+	registerJWKSRoute(routes, keys)
+
+	// Calendar subscription feed (public, token in URL)
+	registerCalendarFeedRoutes(routes, repo)
+
+	// Read-only shared reports (public, signed token in URL)
+	registerSharedReportRoutes(routes, shares, limits.ips)
 
 	// Protected API routes (require valid session + API key)
-	registerAPIRoutes(r, cfg, repo, svc)
+	registerAPIRoutes(routes, cfg, repo, svc, bg, shares, tokens, jwts, limits, feed, webhooks, trash)
+
+	// Connect/gRPC services (same auth as the API routes)
+	registerRPCRoutes(r, cfg, repo, svc, tokens, jwts)
 
 	// Secure API routes with enhanced OWASP validation
-	registerSecureAPIRoutes(r, repo, svc)
+	registerSecureAPIRoutes(routes, repo, svc)
 
-	return r
+	return r, &Workers{loops: []func(context.Context){webhooks.Run, trash.Run, bg.Run}}, nil
 }
 
-// RequireSession ensures a valid session is present and sets user ID in context
-func RequireSession(repo *repository.Repository) func(http.Handler) http.Handler {
+// RequireSession ensures a valid session, personal access token or JWT is
+// present and sets user ID in context
+func RequireSession(
+	repo *repository.Repository,
+	tokens *service.TokenService,
+	jwts *auth.Service,
+) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if hasAccessToken(r) {
+				token, err := tokens.Authenticate(r.Context(), getSessionFromRequest(r))
+				if err != nil {
+					respondError(w, r, err)
+					return
+				}
+				next.ServeHTTP(w, r.WithContext(withAccessToken(r.Context(), token)))
+				return
+			}
+			if hasJWT(r) {
+				result, err := jwts.Validate(r.Context(), jwtStrategy, getSessionFromRequest(r))
+				if err != nil {
+					respondJWTError(w, r, err)
+					return
+				}
+				next.ServeHTTP(w, r.WithContext(withUser(r.Context(), result.User.ID)))
+				return
+			}
 			sessionID := getSessionFromRequest(r)
 			if sessionID == "" {
-				respondErr(w, http.StatusUnauthorized, "not authenticated")
+				respondErr(w, r, http.StatusUnauthorized, "not authenticated")
 				return
 			}
 			session, err := repo.GetSession(r.Context(), sessionID)
 			if err != nil {
-				respondErr(w, http.StatusUnauthorized, "invalid session")
+				respondErr(w, r, http.StatusUnauthorized, "invalid session")
 				return
 			}
 			// Refresh the session cookie on each authenticated request to extend browser validity.
 			// Server-side session validity window is enforced by the repository layer.
 			http.SetCookie(w, buildSessionCookie(r, sessionID, 24*60*60))
-			ctx := context.WithValue(r.Context(), userIDKey, session.UserID)
-			next.ServeHTTP(w, r.WithContext(ctx))
+			next.ServeHTTP(w, r.WithContext(withUser(r.Context(), session.UserID)))
 		})
 	}
 }
 
-// AdminOnly ensures the requester is an authenticated admin
-func AdminOnly(repo *repository.Repository) func(http.Handler) http.Handler {
+// AdminOnly ensures the requester is an authenticated admin, by session, by
+// JWT or by an access token with the admin scope
+func AdminOnly(
+	repo *repository.Repository,
+	tokens *service.TokenService,
+	jwts *auth.Service,
+) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if hasAccessToken(r) {
+				token, err := tokens.Authenticate(r.Context(), getSessionFromRequest(r))
+				if err != nil {
+					respondError(w, r, err)
+					return
+				}
+				isAdmin, err := repo.IsUserAdmin(r.Context(), token.UserID)
+				if err != nil {
+					respondErr(w, r, http.StatusInternalServerError, "failed to check admin")
+					return
+				}
+				if !isAdmin || !token.HasScope(domain.ScopeAdmin) {
+					respondErr(w, r, http.StatusForbidden, "forbidden")
+					return
+				}
+				next.ServeHTTP(w, r.WithContext(withAccessToken(r.Context(), token)))
+				return
+			}
+			if hasJWT(r) {
+				result, err := jwts.Validate(r.Context(), jwtStrategy, getSessionFromRequest(r))
+				if err != nil {
+					respondJWTError(w, r, err)
+					return
+				}
+				isAdmin, err := repo.IsUserAdmin(r.Context(), result.User.ID)
+				if err != nil {
+					respondErr(w, r, http.StatusInternalServerError, "failed to check admin")
+					return
+				}
+				if !isAdmin {
+					respondErr(w, r, http.StatusForbidden, "forbidden")
+					return
+				}
+				next.ServeHTTP(w, r.WithContext(withUser(r.Context(), result.User.ID)))
+				return
+			}
 			sessionID := getSessionFromRequest(r)
 			if sessionID == "" {
-				respondErr(w, http.StatusUnauthorized, "not authenticated")
+				respondErr(w, r, http.StatusUnauthorized, "not authenticated")
 				return
 			}
 			session, err := repo.GetSession(r.Context(), sessionID)
 			if err != nil {
-				respondErr(w, http.StatusUnauthorized, "invalid session")
+				respondErr(w, r, http.StatusUnauthorized, "invalid session")
 				return
 			}
 			isAdmin, err := repo.IsUserAdmin(r.Context(), session.UserID)
 			if err != nil {
-				respondErr(w, http.StatusInternalServerError, "failed to check admin")
+				respondErr(w, r, http.StatusInternalServerError, "failed to check admin")
 				return
 			}
 			if !isAdmin {
-				respondErr(w, http.StatusForbidden, "forbidden")
+				respondErr(w, r, http.StatusForbidden, "forbidden")
 				return
 			}
 			// Also refresh cookie for admin-only paths
@@ -210,14 +363,22 @@ func respondJSON(w http.ResponseWriter, status int, v any) {
 	_ = json.NewEncoder(w).Encode(v)
 }
 
-func respondErr(w http.ResponseWriter, status int, msg string) {
-	respondJSON(w, status, map[string]string{"error": msg})
+// respondErr writes an application/problem+json error with the default code
+// for status.
+func respondErr(w http.ResponseWriter, r *http.Request, status int, msg string) {
+	middleware.WriteProblemStatus(w, r, status, msg)
+}
+
+// respondError maps a typed domain error to an application/problem+json
+// response; unexpected errors become a logged, generic 500.
+func respondError(w http.ResponseWriter, r *http.Request, err error) {
+	middleware.WriteProblem(w, r, err)
 }
 
 func getSessionFromRequest(r *http.Request) string {
 	// Try Authorization header first
-	if auth := r.Header.Get("Authorization"); auth != "" {
-		parts := strings.Split(auth, " ")
+	if header := r.Header.Get("Authorization"); header != "" {
+		parts := strings.Split(header, " ")
 		if len(parts) == 2 && parts[0] == "Bearer" {
 			return parts[1]
 		}
@@ -231,6 +392,69 @@ func getSessionFromRequest(r *http.Request) string {
 	return ""
 }
 
+// hasAccessToken reports whether r authenticates with a personal access
+// token rather than a session.
+func hasAccessToken(r *http.Request) bool {
+	header := r.Header.Get("Authorization")
+	return strings.HasPrefix(header, "Bearer ") && domain.IsAccessToken(strings.TrimPrefix(header, "Bearer "))
+}
+
+// hasJWT reports whether r authenticates with a bearer JWT, which has three
+// dot-separated parts where session IDs and access tokens have none.
+func hasJWT(r *http.Request) bool {
+	header := r.Header.Get("Authorization")
+	return strings.HasPrefix(header, "Bearer ") && strings.Count(header, ".") == 2
+}
+
+// hasBearerToken reports whether r carries a per-user bearer token, an
+// access token or a JWT, which stands in for the shared API key.
+func hasBearerToken(r *http.Request) bool {
+	return hasAccessToken(r) || hasJWT(r)
+}
+
+// withAccessToken sets the token's user, and the token for scope checks, in
+// context.
+func withAccessToken(ctx context.Context, token *domain.AccessToken) context.Context {
+	return withUser(context.WithValue(ctx, accessTokenKey, token), token.UserID)
+}
+
+// withUser sets the authenticated user ID and the audit actor on ctx.
+func withUser(ctx context.Context, userID int64) context.Context {
+	ctx = context.WithValue(ctx, userIDKey, userID)
+	return repository.WithActor(ctx, domain.Actor{UserID: userID, RequestID: middleware.GetRequestID(ctx)})
+}
+
+// requireScope rejects requests made with an access token that lacks scope.
+// Session requests are not limited by scope.
+func requireScope(scope string) func(http.Handler) http.Handler {
+	return requireScopeFor(func(*http.Request) string { return scope })
+}
+
+// requireScopeFor is requireScope for routes whose scope depends on the
+// request.
+func requireScopeFor(scopeOf func(*http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if scope := scopeOf(r); !hasScope(r.Context(), scope) {
+				respondError(w, r, &domain.Error{
+					Kind:    domain.ErrForbidden,
+					Code:    "insufficient_scope",
+					Message: "access token lacks the " + scope + " scope",
+				})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// hasScope reports whether the request's credentials allow scope: sessions
+// allow everything, access tokens what they were granted.
+func hasScope(ctx context.Context, scope string) bool {
+	token, ok := ctx.Value(accessTokenKey).(*domain.AccessToken)
+	return !ok || token.HasScope(scope)
+}
+
 func getUserIDFromContext(ctx context.Context) int64 {
 	if userID, ok := ctx.Value(userIDKey).(int64); ok {
 		return userID
@@ -0,0 +1,60 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mdco1990/webapp/internal/auth"
+	"github.com/mdco1990/webapp/internal/config"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/gen/budget/v1/budgetv1connect"
+	"github.com/mdco1990/webapp/internal/middleware"
+	"github.com/mdco1990/webapp/internal/repository"
+	"github.com/mdco1990/webapp/internal/service"
+	rpcapi "github.com/mdco1990/webapp/internal/transport/rpc"
+)
+
+// rpcScopes maps the RPCs access tokens can call without the admin scope to
+// the scope they need, as for the matching REST routes.
+var rpcScopes = map[string]string{
+	budgetv1connect.FinancialServiceGetMonthlySummaryProcedure:  domain.ScopeReadBudget,
+	budgetv1connect.FinancialServiceGetMonthlyDataProcedure:     domain.ScopeReadBudget,
+	budgetv1connect.FinancialServiceGetIncomeProcedure:          domain.ScopeReadBudget,
+	budgetv1connect.FinancialServiceListIncomeSourcesProcedure:  domain.ScopeReadBudget,
+	budgetv1connect.FinancialServiceGetBudgetProcedure:          domain.ScopeReadBudget,
+	budgetv1connect.FinancialServiceListBudgetSourcesProcedure:  domain.ScopeReadBudget,
+	budgetv1connect.FinancialServiceListExpensesProcedure:       domain.ScopeReadBudget,
+	budgetv1connect.FinancialServiceAddExpenseProcedure:         domain.ScopeWriteExpenses,
+	budgetv1connect.FinancialServiceDeleteExpenseProcedure:      domain.ScopeWriteExpenses,
+	budgetv1connect.ManualBudgetServiceGetManualBudgetProcedure: domain.ScopeReadBudget,
+}
+
+// rpcScope returns the access token scope an RPC needs: its own from
+// rpcScopes, or admin for the other writes.
+func rpcScope(r *http.Request) string {
+	if scope, ok := rpcScopes[r.URL.Path]; ok {
+		return scope
+	}
+	return domain.ScopeAdmin
+}
+
+// registerRPCRoutes serves the Connect/gRPC services (/budget.v1.*) behind
+// the same API-key and session checks as /api/v1. Access tokens need the
+// scope rpcScope gives each RPC. gRPC clients need HTTP/2, which the server
+// accepts in cleartext (h2c).
+func registerRPCRoutes(
+	r chi.Router,
+	cfg config.Config,
+	repo *repository.Repository,
+	svc *service.Service,
+	tokens *service.TokenService,
+	jwts *auth.Service,
+) {
+	r.Group(func(rpc chi.Router) {
+		rpc.Use(
+			middleware.APIKeyAuth(middleware.APIKeyConfig{Header: "X-API-Key", Key: cfg.APIKey, Skip: hasBearerToken}),
+		)
+		rpc.Use(RequireSession(repo, tokens, jwts), requireScopeFor(rpcScope))
+		rpcapi.Mount(rpc, repo, svc, getUserIDFromContext)
+	})
+}
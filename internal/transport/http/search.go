@@ -0,0 +1,177 @@
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/repository"
+)
+
+// maxSearchQueryLength caps the q parameter of /search.
+const maxSearchQueryLength = 200
+
+// registerSearchEndpoints wires full-text search
+func registerSearchEndpoints(api *Routes, repo *repository.Repository) {
+	api.Add(
+		route(http.MethodGet, "/search", handleSearch(repo)).
+			WithTag("Search").
+			WithSummary("Search expenses and sources").
+			WithDescription("Finds expenses, income sources, budget sources and manual budget items by name or "+
+				"description across all months, best match first. Every word must match; the last one also "+
+				"matches as a prefix, and \"quoted words\" match as a phrase. `snippet` is HTML with the matched "+
+				"words in `<mark>` tags.\n\nFilters can be mixed into q:\n\n"+
+				"- `category:food` (or `category:\"eating out\"`): only expenses of that category\n"+
+				"- `amount:>50`, `amount:>=50`, `amount:<50`, `amount:<=50`, `amount:50` or `amount:10..50`: "+
+				"amounts in currency units, up to two decimals").
+			WithParams(
+				requiredQueryParam("q", &Schema{Type: "string", MaxLength: intPtr(maxSearchQueryLength)},
+					"Search words and filters, e.g. `coffee category:food amount:>5`"),
+				queryParam("limit", intSchema(1, repository.MaxSearchLimit), ""),
+			).
+			WithResponse(http.StatusOK, []domain.SearchResult{}),
+	)
+}
+
+// handleSearch runs a full-text search for the session's user
+func handleSearch(repo *repository.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q, err := parseSearchQuery(r)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		results, err := repo.Search(r.Context(), getUserIDFromContext(r.Context()), q)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, results)
+	}
+}
+
+// parseSearchQuery reads the q and limit parameters of /search. Words of q
+// become search terms, except the category: and amount: filters; all
+// problems are reported together like parseListQuery does.
+func parseSearchQuery(r *http.Request) (domain.SearchQuery, error) {
+	v := r.URL.Query()
+	var q domain.SearchQuery
+	var fields []domain.FieldError
+	bad := func(field, msg string) { fields = append(fields, domain.FieldError{Field: field, Message: msg}) }
+
+	q.Limit = intParam(v.Get("limit"), "limit", 1, repository.MaxSearchLimit, bad)
+	text := v.Get("q")
+	if len(text) > maxSearchQueryLength {
+		bad("q", fmt.Sprintf("must be at most %d bytes", maxSearchQueryLength))
+		text = ""
+	}
+	for _, token := range splitSearchQuery(text) {
+		key, value, _ := strings.Cut(token, ":")
+		switch strings.ToLower(key) {
+		case "category":
+			q.Category = value
+		case "amount":
+			if !applyAmountFilter(&q, value) {
+				bad("q", fmt.Sprintf("amount filter %q must look like >50, <=12.50, 30 or 10..50", value))
+			}
+		default:
+			q.Terms = append(q.Terms, token)
+		}
+	}
+	if len(q.Terms) == 0 && len(fields) == 0 {
+		bad("q", "must contain a search word")
+	}
+	if q.MinAmount != nil && q.MaxAmount != nil && *q.MinAmount > *q.MaxAmount {
+		bad("q", "amount filters exclude every amount")
+	}
+
+	if len(fields) > 0 {
+		return q, domain.Invalid("invalid search", fields...)
+	}
+	return q, nil
+}
+
+// splitSearchQuery splits s on spaces outside double quotes and drops the
+// quotes, so `category:"eating out"` is one token.
+func splitSearchQuery(s string) []string {
+	var (
+		tokens []string
+		b      strings.Builder
+		quoted bool
+	)
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case unicode.IsSpace(r) && !quoted:
+			if b.Len() > 0 {
+				tokens = append(tokens, b.String())
+			}
+			b.Reset()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() > 0 {
+		tokens = append(tokens, b.String())
+	}
+	return tokens
+}
+
+// applyAmountFilter narrows q's amount range by an amount: filter value and
+// reports whether it was well-formed.
+func applyAmountFilter(q *domain.SearchQuery, value string) bool {
+	narrow := func(lo, hi *domain.Money) {
+		if lo != nil && (q.MinAmount == nil || *lo > *q.MinAmount) {
+			q.MinAmount = lo
+		}
+		if hi != nil && (q.MaxAmount == nil || *hi < *q.MaxAmount) {
+			q.MaxAmount = hi
+		}
+	}
+	if from, to, ok := strings.Cut(value, ".."); ok {
+		lo, okLo := parseAmount(from)
+		hi, okHi := parseAmount(to)
+		if !okLo || !okHi {
+			return false
+		}
+		narrow(&lo, &hi)
+		return true
+	}
+	op := value[:len(value)-len(strings.TrimLeft(value, "<>="))]
+	n, ok := parseAmount(value[len(op):])
+	if !ok {
+		return false
+	}
+	switch op {
+	case ">":
+		n++
+		narrow(&n, nil)
+	case ">=":
+		narrow(&n, nil)
+	case "<":
+		n--
+		narrow(nil, &n)
+	case "<=":
+		narrow(nil, &n)
+	case "", "=":
+		narrow(&n, &n)
+	default:
+		return false
+	}
+	return true
+}
+
+// parseAmount parses a non-negative amount in currency units with up to two
+// decimals, e.g. 12 or 12.5, into cents.
+func parseAmount(s string) (domain.Money, bool) {
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" || len(frac) > 2 || strings.Trim(whole+frac, "0123456789") != "" {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(whole+(frac + "00")[:2], 10, 64)
+	return domain.Money(n), err == nil
+}
@@ -0,0 +1,69 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+
+	"github.com/mdco1990/webapp/internal/domain"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		q        string
+		terms    []string
+		category string
+		min, max int64 // -1 for none
+	}{
+		{"coffee", []string{"coffee"}, "", -1, -1},
+		{` "eating out"  Category:"Fast Food" amount:>50`, []string{"eating out"}, "Fast Food", 5001, -1},
+		{"rent amount:>=12.5 amount:<=900", []string{"rent"}, "", 1250, 90000},
+		{"rent amount:<1", []string{"rent"}, "", -1, 99},
+		{"gift amount:30", []string{"gift"}, "", 3000, 3000},
+		{"gift amount:10..20.05 amount:<15", []string{"gift"}, "", 1000, 1499},
+		{"12:30 train", []string{"12:30", "train"}, "", -1, -1},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/search?limit=5&q="+url.QueryEscape(tt.q), nil)
+		q, err := parseSearchQuery(req)
+		if err != nil {
+			t.Errorf("%s: parseSearchQuery failed: %v", tt.q, err)
+			continue
+		}
+		if !slices.Equal(q.Terms, tt.terms) || q.Category != tt.category || q.Limit != 5 {
+			t.Errorf("%s: Expected terms %q and category %q, got %+v", tt.q, tt.terms, tt.category, q)
+		}
+		amount := func(m *domain.Money) int64 {
+			if m == nil {
+				return -1
+			}
+			return int64(*m)
+		}
+		if amount(q.MinAmount) != tt.min || amount(q.MaxAmount) != tt.max {
+			t.Errorf("%s: Expected amounts %d..%d, got %d..%d",
+				tt.q, tt.min, tt.max, amount(q.MinAmount), amount(q.MaxAmount))
+		}
+	}
+}
+
+func TestParseSearchQueryErrors(t *testing.T) {
+	for _, query := range []string{
+		"q=",
+		"q=category:food",
+		"q=tea+amount:>x",
+		"q=tea+amount:=>5",
+		"q=tea+amount:1.234",
+		"q=tea+amount:-5",
+		"q=tea+amount:>50+amount:<10",
+		"q=tea&limit=0",
+	} {
+		_, err := parseSearchQuery(httptest.NewRequest(http.MethodGet, "/search?"+query, nil))
+		var de *domain.Error
+		if !errors.As(err, &de) || !errors.Is(err, domain.ErrValidation) || len(de.Fields) != 1 {
+			t.Errorf("%s: Expected one validation error, got %v", query, err)
+		}
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"net/http"
 	"strings"
 
-	"github.com/go-chi/chi/v5"
 	"github.com/mdco1990/webapp/internal/domain"
 	"github.com/mdco1990/webapp/internal/repository"
 	"github.com/mdco1990/webapp/internal/security"
@@ -33,9 +32,9 @@ func newSecureAPIHandlers() *secureAPIHandlers {
 
 // Helper functions to reduce cognitive complexity
 
-func (h *secureAPIHandlers) validateUserID(w http.ResponseWriter, userID int64) bool {
+func (h *secureAPIHandlers) validateUserID(w http.ResponseWriter, r *http.Request, userID int64) bool {
 	if err := security.ValidateUserID(userID); err != nil {
-		h.secureHandler.SecureErrorResponse(w, http.StatusUnauthorized, msgInvalidUser)
+		h.secureHandler.SecureErrorResponse(w, r, http.StatusUnauthorized, msgInvalidUser)
 		return false
 	}
 	return true
@@ -43,14 +42,15 @@ func (h *secureAPIHandlers) validateUserID(w http.ResponseWriter, userID int64)
 
 func (h *secureAPIHandlers) handleValidationError(
 	w http.ResponseWriter,
+	r *http.Request,
 	err error,
 	defaultMsg string,
 ) {
 	var validationErr security.ValidationError
 	if errors.As(err, &validationErr) {
-		h.secureHandler.SecureErrorResponse(w, http.StatusBadRequest, validationErr.Message)
+		h.secureHandler.SecureProblemResponse(w, r, validationErr)
 	} else {
-		h.secureHandler.SecureErrorResponse(w, http.StatusBadRequest, defaultMsg)
+		h.secureHandler.SecureErrorResponse(w, r, http.StatusBadRequest, defaultMsg)
 	}
 }
 
@@ -62,19 +62,19 @@ func (h *secureAPIHandlers) secureHandleCreateIncomeSource(
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := getUserIDFromContext(r.Context())
-		if !h.validateUserID(w, userID) {
+		if !h.validateUserID(w, r, userID) {
 			return
 		}
 
 		var req domain.CreateIncomeSourceRequest
 		if err := h.secureHandler.SecureJSONDecoder(r, &req); err != nil {
-			h.handleValidationError(w, err, msgInvalidRequestBody)
+			h.handleValidationError(w, r, err, msgInvalidRequestBody)
 			return
 		}
 
 		validatedReq, err := security.ValidateCreateIncomeSourceRequest(req)
 		if err != nil {
-			h.handleValidationError(w, err, msgValidationFailed)
+			h.handleValidationError(w, r, err, msgValidationFailed)
 			return
 		}
 
@@ -82,6 +82,7 @@ func (h *secureAPIHandlers) secureHandleCreateIncomeSource(
 		if err != nil {
 			h.secureHandler.SecureErrorResponse(
 				w,
+				r,
 				http.StatusInternalServerError,
 				"failed to create income source",
 			)
@@ -98,36 +99,39 @@ func (h *secureAPIHandlers) secureHandleUpdateIncomeSource(
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := getUserIDFromContext(r.Context())
-		if !h.validateUserID(w, userID) {
+		if !h.validateUserID(w, r, userID) {
 			return
 		}
 
 		id, err := h.secureHandler.ValidateAndParseURLParam(r, "id")
 		if err != nil {
-			h.handleValidationError(w, err, msgInvalidID)
+			h.handleValidationError(w, r, err, msgInvalidID)
 			return
 		}
 
 		var req domain.UpdateSourceRequest
 		if err := h.secureHandler.SecureJSONDecoder(r, &req); err != nil {
-			h.handleValidationError(w, err, msgInvalidRequestBody)
+			h.handleValidationError(w, r, err, msgInvalidRequestBody)
 			return
 		}
 
 		validatedReq, err := security.ValidateUpdateSourceRequest(req)
 		if err != nil {
-			h.handleValidationError(w, err, msgValidationFailed)
+			h.handleValidationError(w, r, err, msgValidationFailed)
 			return
 		}
 
-		if err := repo.UpdateIncomeSource(r.Context(), id, userID, *validatedReq); err != nil {
-			h.secureHandler.SecureErrorResponse(
-				w,
-				http.StatusInternalServerError,
-				"failed to update",
-			)
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			h.secureHandler.SecureProblemResponse(w, r, err)
 			return
 		}
+		version, err = repo.UpdateIncomeSource(r.Context(), id, userID, version, *validatedReq)
+		if err != nil {
+			h.secureHandler.SecureProblemResponse(w, r, err)
+			return
+		}
+		setETag(w, version)
 
 		h.secureHandler.SecureJSONResponse(w, http.StatusOK, map[string]string{"status": "ok"})
 	}
@@ -139,22 +143,23 @@ func (h *secureAPIHandlers) secureHandleDeleteIncomeSource(
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := getUserIDFromContext(r.Context())
-		if !h.validateUserID(w, userID) {
+		if !h.validateUserID(w, r, userID) {
 			return
 		}
 
 		id, err := h.secureHandler.ValidateAndParseURLParam(r, "id")
 		if err != nil {
-			h.handleValidationError(w, err, msgInvalidID)
+			h.handleValidationError(w, r, err, msgInvalidID)
 			return
 		}
 
-		if err := repo.DeleteIncomeSource(r.Context(), id, userID); err != nil {
-			h.secureHandler.SecureErrorResponse(
-				w,
-				http.StatusInternalServerError,
-				"failed to delete income source",
-			)
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			h.secureHandler.SecureProblemResponse(w, r, err)
+			return
+		}
+		if err := repo.DeleteIncomeSource(r.Context(), id, userID, version); err != nil {
+			h.secureHandler.SecureProblemResponse(w, r, err)
 			return
 		}
 
@@ -168,19 +173,19 @@ func (h *secureAPIHandlers) secureHandleCreateBudgetSource(
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := getUserIDFromContext(r.Context())
-		if !h.validateUserID(w, userID) {
+		if !h.validateUserID(w, r, userID) {
 			return
 		}
 
 		var req domain.CreateBudgetSourceRequest
 		if err := h.secureHandler.SecureJSONDecoder(r, &req); err != nil {
-			h.handleValidationError(w, err, msgInvalidRequestBody)
+			h.handleValidationError(w, r, err, msgInvalidRequestBody)
 			return
 		}
 
 		validatedReq, err := security.ValidateCreateBudgetSourceRequest(req)
 		if err != nil {
-			h.handleValidationError(w, err, msgValidationFailed)
+			h.handleValidationError(w, r, err, msgValidationFailed)
 			return
 		}
 
@@ -188,6 +193,7 @@ func (h *secureAPIHandlers) secureHandleCreateBudgetSource(
 		if err != nil {
 			h.secureHandler.SecureErrorResponse(
 				w,
+				r,
 				http.StatusInternalServerError,
 				"failed to create budget source",
 			)
@@ -204,36 +210,39 @@ func (h *secureAPIHandlers) secureHandleUpdateBudgetSource(
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := getUserIDFromContext(r.Context())
-		if !h.validateUserID(w, userID) {
+		if !h.validateUserID(w, r, userID) {
 			return
 		}
 
 		id, err := h.secureHandler.ValidateAndParseURLParam(r, "id")
 		if err != nil {
-			h.handleValidationError(w, err, msgInvalidID)
+			h.handleValidationError(w, r, err, msgInvalidID)
 			return
 		}
 
 		var req domain.UpdateSourceRequest
 		if err := h.secureHandler.SecureJSONDecoder(r, &req); err != nil {
-			h.handleValidationError(w, err, msgInvalidRequestBody)
+			h.handleValidationError(w, r, err, msgInvalidRequestBody)
 			return
 		}
 
 		validatedReq, err := security.ValidateUpdateSourceRequest(req)
 		if err != nil {
-			h.handleValidationError(w, err, msgValidationFailed)
+			h.handleValidationError(w, r, err, msgValidationFailed)
 			return
 		}
 
-		if err := repo.UpdateBudgetSource(r.Context(), id, userID, *validatedReq); err != nil {
-			h.secureHandler.SecureErrorResponse(
-				w,
-				http.StatusInternalServerError,
-				"failed to update budget source",
-			)
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			h.secureHandler.SecureProblemResponse(w, r, err)
 			return
 		}
+		version, err = repo.UpdateBudgetSource(r.Context(), id, userID, version, *validatedReq)
+		if err != nil {
+			h.secureHandler.SecureProblemResponse(w, r, err)
+			return
+		}
+		setETag(w, version)
 
 		h.secureHandler.SecureJSONResponse(w, http.StatusOK, map[string]string{"status": "ok"})
 	}
@@ -245,22 +254,23 @@ func (h *secureAPIHandlers) secureHandleDeleteBudgetSource(
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := getUserIDFromContext(r.Context())
-		if !h.validateUserID(w, userID) {
+		if !h.validateUserID(w, r, userID) {
 			return
 		}
 
 		id, err := h.secureHandler.ValidateAndParseURLParam(r, "id")
 		if err != nil {
-			h.handleValidationError(w, err, msgInvalidID)
+			h.handleValidationError(w, r, err, msgInvalidID)
 			return
 		}
 
-		if err := repo.DeleteBudgetSource(r.Context(), id, userID); err != nil {
-			h.secureHandler.SecureErrorResponse(
-				w,
-				http.StatusInternalServerError,
-				"failed to delete budget source",
-			)
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			h.secureHandler.SecureProblemResponse(w, r, err)
+			return
+		}
+		if err := repo.DeleteBudgetSource(r.Context(), id, userID, version); err != nil {
+			h.secureHandler.SecureProblemResponse(w, r, err)
 			return
 		}
 
@@ -274,38 +284,42 @@ func (h *secureAPIHandlers) secureHandleUpsertManualBudget(
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := getUserIDFromContext(r.Context())
-		if !h.validateUserID(w, userID) {
+		if !h.validateUserID(w, r, userID) {
 			return
 		}
 
 		var req domain.ManualBudget
 		if err := h.secureHandler.SecureJSONDecoder(r, &req); err != nil {
-			h.handleValidationError(w, err, msgInvalidRequestBody)
+			h.handleValidationError(w, r, err, msgInvalidRequestBody)
 			return
 		}
 
 		// Validate the manual budget data
 		if err := security.ValidateManualBudget(req); err != nil {
-			h.handleValidationError(w, err, msgValidationFailed)
+			h.handleValidationError(w, r, err, msgValidationFailed)
+			return
+		}
+
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			h.secureHandler.SecureProblemResponse(w, r, err)
 			return
 		}
 
 		// Call the repository method with correct parameters
-		err := repo.UpsertManualBudget(
+		version, err = repo.UpsertManualBudget(
 			r.Context(),
 			userID,
 			req.YearMonth,
+			version,
 			req.BankAmountCents,
 			req.Items,
 		)
 		if err != nil {
-			h.secureHandler.SecureErrorResponse(
-				w,
-				http.StatusInternalServerError,
-				"failed to upsert manual budget",
-			)
+			h.secureHandler.SecureProblemResponse(w, r, err)
 			return
 		}
+		setETag(w, version)
 
 		h.secureHandler.SecureJSONResponse(w, http.StatusOK, map[string]string{"status": "ok"})
 	}
@@ -323,6 +337,7 @@ func ValidatedJSONDecoder(next http.Handler) http.Handler {
 				secureHandler := security.NewSecureHandler()
 				secureHandler.SecureErrorResponse(
 					w,
+					r,
 					http.StatusBadRequest,
 					"Content-Type must be application/json",
 				)
@@ -340,31 +355,61 @@ func contains(s, substr string) bool {
 
 // registerSecureAPIRoutes registers API routes with enhanced security validation
 func registerSecureAPIRoutes(
-	r chi.Router,
+	routes *Routes,
 	repo *repository.Repository,
 	_ *service.Service,
 ) {
 	handlers := newSecureAPIHandlers()
 
-	r.Route("/api/v1/secure", func(api chi.Router) {
+	routes.Tagged("Secure").Secured(apiSecurity).Route("/api/v1/secure", func(api *Routes) {
 		// Income Sources with enhanced validation
-		api.Route("/income-sources", func(income chi.Router) {
-			income.Post("/", handlers.secureHandleCreateIncomeSource(repo))
-			income.Put("/{id}", handlers.secureHandleUpdateIncomeSource(repo))
-			income.Delete("/{id}", handlers.secureHandleDeleteIncomeSource(repo))
+		api.Route("/income-sources", func(income *Routes) {
+			income.Add(
+				route(http.MethodPost, "/", handlers.secureHandleCreateIncomeSource(repo)).
+					WithSummary("Create income source (strict validation)").
+					WithRequest(domain.CreateIncomeSourceRequest{}).
+					WithResponse(http.StatusCreated, domain.IncomeSource{}),
+				route(http.MethodPut, "/{id}", handlers.secureHandleUpdateIncomeSource(repo)).
+					WithSummary("Update income source (strict validation)").
+					WithRequest(domain.UpdateSourceRequest{}).
+					WithResponse(http.StatusOK, statusResponse{}),
+				route(http.MethodDelete, "/{id}", handlers.secureHandleDeleteIncomeSource(repo)).
+					WithSummary("Delete income source").
+					WithResponse(http.StatusOK, statusResponse{}),
+			)
 		})
 
 		// Budget Sources with enhanced validation
-		api.Route("/budget-sources", func(budget chi.Router) {
-			budget.Post("/", handlers.secureHandleCreateBudgetSource(repo))
-			budget.Put("/{id}", handlers.secureHandleUpdateBudgetSource(repo))
-			budget.Delete("/{id}", handlers.secureHandleDeleteBudgetSource(repo))
+		api.Route("/budget-sources", func(budget *Routes) {
+			budget.Add(
+				route(http.MethodPost, "/", handlers.secureHandleCreateBudgetSource(repo)).
+					WithSummary("Create budget source (strict validation)").
+					WithRequest(domain.CreateBudgetSourceRequest{}).
+					WithResponse(http.StatusCreated, domain.BudgetSource{}),
+				route(http.MethodPut, "/{id}", handlers.secureHandleUpdateBudgetSource(repo)).
+					WithSummary("Update budget source (strict validation)").
+					WithRequest(domain.UpdateSourceRequest{}).
+					WithResponse(http.StatusOK, statusResponse{}),
+				route(http.MethodDelete, "/{id}", handlers.secureHandleDeleteBudgetSource(repo)).
+					WithSummary("Delete budget source").
+					WithResponse(http.StatusOK, statusResponse{}),
+			)
 		})
 
 		// Manual Budget with enhanced validation
-		api.Route("/manual-budgets", func(manual chi.Router) {
-			manual.Post("/", handlers.secureHandleUpsertManualBudget(repo))
-			manual.Put("/{id}", handlers.secureHandleUpsertManualBudget(repo))
+		api.Route("/manual-budgets", func(manual *Routes) {
+			manual.Add(
+				route(http.MethodPost, "/", handlers.secureHandleUpsertManualBudget(repo)).
+					WithSummary("Save manual budget (strict validation)").
+					WithParams(ifMatchParam).
+					WithRequest(domain.ManualBudget{}).
+					WithResponse(http.StatusOK, statusResponse{}),
+				route(http.MethodPut, "/{id}", handlers.secureHandleUpsertManualBudget(repo)).
+					WithSummary("Save manual budget (strict validation)").
+					WithParams(ifMatchParam).
+					WithRequest(domain.ManualBudget{}).
+					WithResponse(http.StatusOK, statusResponse{}),
+			)
 		})
 	})
 }
@@ -0,0 +1,163 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/middleware"
+	"github.com/mdco1990/webapp/internal/service"
+)
+
+const (
+	defaultShareAccessLog = 100
+	maxShareAccessLog     = 1000
+	maxUserAgentLength    = 512
+)
+
+// registerShareEndpoints wires share link management for the owner
+func registerShareEndpoints(api *Routes, shares *service.ShareService) {
+	linkID := pathParam("id", &Schema{Type: "string", Description: "Share link ID"})
+	api.Tagged("Sharing").Route("/shares", func(sh *Routes) {
+		sh.Add(
+			route(http.MethodGet, "/", handleListShareLinks(shares)).
+				WithSummary("List share links").
+				WithResponse(http.StatusOK, []domain.ShareLink{}),
+			route(http.MethodPost, "/", handleCreateShareLink(shares)).
+				WithSummary("Create a share link").
+				WithDescription("Creates a signed, expiring, read-only link to a month or a year. "+
+					"The token is only returned here.").
+				WithRequest(domain.CreateShareLinkRequest{}).
+				WithResponse(http.StatusCreated, shareLinkResponse{}),
+			route(http.MethodDelete, "/{id}", handleRevokeShareLink(shares)).
+				WithSummary("Revoke a share link").
+				WithParams(linkID).
+				WithResponse(http.StatusOK, statusResponse{}),
+			route(http.MethodGet, "/{id}/access", handleListShareAccess(shares)).
+				WithSummary("List share link accesses").
+				WithParams(linkID, queryParam("limit", intSchema(1, maxShareAccessLog), "")).
+				WithResponse(http.StatusOK, []domain.ShareAccess{}),
+		)
+	})
+}
+
+// registerSharedReportRoutes wires the public, read-only view behind share
+// tokens; accesses are logged with the client IP that ips resolves
+func registerSharedReportRoutes(routes *Routes, shares *service.ShareService, ips *middleware.ClientIPResolver) {
+	routes.Add(
+		route(http.MethodGet, "/shared/{token}", handleSharedReport(shares, ips)).
+			WithTag("Sharing").
+			WithSummary("Open a shared report").
+			WithDescription("Public; invalid, expired and revoked tokens all answer 404.").
+			WithParams(pathParam("token", stringSchema())).
+			WithResponse(http.StatusOK, domain.SharedReport{}),
+	)
+}
+
+// shareLinkResponse holds a new share link and its token.
+type shareLinkResponse struct {
+	Link  *domain.ShareLink `json:"link"`
+	Token string            `json:"token"`
+	URL   string            `json:"url"`
+}
+
+// handleListShareLinks lists the user's share links with access counts
+func handleListShareLinks(shares *service.ShareService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		links, err := shares.ListLinks(r.Context(), getUserIDFromContext(r.Context()))
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, links)
+	}
+}
+
+// handleCreateShareLink creates a signed, expiring link to a month or a year
+func handleCreateShareLink(shares *service.ShareService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req domain.CreateShareLinkRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondErr(w, r, http.StatusBadRequest, invalidBodyMsg)
+			return
+		}
+		link, token, err := shares.CreateLink(r.Context(), getUserIDFromContext(r.Context()), req)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusCreated, shareLinkResponse{
+			Link:  link,
+			Token: token,
+			URL:   requestBaseURL(r) + "/shared/" + token,
+		})
+	}
+}
+
+// handleRevokeShareLink revokes a share link immediately
+func handleRevokeShareLink(shares *service.ShareService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := shares.RevokeLink(r.Context(), getUserIDFromContext(r.Context()), chi.URLParam(r, "id"))
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
+
+// handleListShareAccess returns the access log of a share link
+func handleListShareAccess(shares *service.ShareService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit := defaultShareAccessLog
+		if v := r.URL.Query().Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 || n > maxShareAccessLog {
+				respondErr(w, r, http.StatusBadRequest, "invalid limit")
+				return
+			}
+			limit = n
+		}
+		accesses, err := shares.ListAccess(r.Context(), getUserIDFromContext(r.Context()), chi.URLParam(r, "id"), limit)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, accesses)
+	}
+}
+
+// handleSharedReport serves the shared month or year to anyone holding a valid token
+func handleSharedReport(shares *service.ShareService, ips *middleware.ClientIPResolver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ua := r.UserAgent()
+		if len(ua) > maxUserAgentLength {
+			ua = ua[:maxUserAgentLength]
+		}
+		report, err := shares.OpenSharedReport(r.Context(), chi.URLParam(r, "token"), domain.ShareAccess{
+			IPAddress: ips.ClientIP(r),
+			UserAgent: ua,
+		})
+		if err != nil {
+			// Invalid, expired and revoked links all answer as not found, so
+			// tokens cannot be probed.
+			respondError(w, r, err)
+			return
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("X-Robots-Tag", "noindex")
+		respondJSON(w, http.StatusOK, report)
+	}
+}
+
+// requestBaseURL returns scheme://host for building absolute links.
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https") {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
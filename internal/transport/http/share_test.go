@@ -0,0 +1,64 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/middleware"
+	"github.com/mdco1990/webapp/internal/repository"
+	"github.com/mdco1990/webapp/internal/service"
+)
+
+func TestSharedReportAccessIP(t *testing.T) {
+	database := setupTestDB(t)
+	defer func() { _ = database.Close() }()
+	database.SetMaxOpenConns(1)
+	ctx := context.Background()
+	shares := service.NewShareService(repository.New(database), service.NewShareSigner([]byte("secret")))
+	link, token, err := shares.CreateLink(ctx, 1, domain.CreateShareLinkRequest{
+		Scope: domain.ShareScopeMonth, Year: 2024, Month: 3,
+	})
+	if err != nil {
+		t.Fatalf("CreateLink failed: %v", err)
+	}
+	ips, err := middleware.NewClientIPResolver([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := chi.NewRouter()
+	registerSharedReportRoutes(NewRoutes(r, NewOpenAPIDocument()), shares, ips)
+	open := func(remoteAddr, forwardedFor string) {
+		req := httptest.NewRequest(http.MethodGet, "/shared/"+token, nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		req.Header.Set("X-Real-IP", "192.0.2.99")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d %s", w.Code, w.Body.String())
+		}
+	}
+
+	// Forwarding headers from an untrusted peer are ignored; a trusted
+	// proxy's X-Forwarded-For names the client.
+	open("203.0.113.7:4000", "198.51.100.1")
+	open("10.0.0.5:4000", "198.51.100.2")
+
+	accesses, err := shares.ListAccess(ctx, 1, link.ID, 10)
+	if err != nil {
+		t.Fatalf("ListAccess failed: %v", err)
+	}
+	var logged []string
+	for _, a := range accesses {
+		logged = append(logged, a.IPAddress)
+	}
+	slices.Sort(logged)
+	if !slices.Equal(logged, []string{"198.51.100.2", "203.0.113.7"}) {
+		t.Errorf("Expected the peer and the proxied client logged, got %v", logged)
+	}
+}
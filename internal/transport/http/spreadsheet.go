@@ -0,0 +1,159 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/export"
+	"github.com/mdco1990/webapp/internal/repository"
+)
+
+// monthWriter is implemented by the streaming CSV, XLSX and journal writers.
+type monthWriter interface {
+	WriteMonth(data *domain.MonthlyData) error
+}
+
+// exportPeriod is a year, or a single month of it, requested for export.
+type exportPeriod struct {
+	Year   int
+	Months []int
+}
+
+// filename builds "<prefix>-2024.<ext>" or "<prefix>-2024-03.<ext>".
+func (p exportPeriod) filename(prefix, ext string) string {
+	if len(p.Months) == 1 {
+		return fmt.Sprintf("%s-%04d-%02d.%s", prefix, p.Year, p.Months[0], ext)
+	}
+	return fmt.Sprintf("%s-%04d.%s", prefix, p.Year, ext)
+}
+
+// parseExportPeriod reads year (required) and month (optional; whole year when absent).
+func parseExportPeriod(r *http.Request) (exportPeriod, error) {
+	year, err := strconv.Atoi(r.URL.Query().Get("year"))
+	if err != nil || year < 1970 || year > 3000 {
+		return exportPeriod{}, errors.New("invalid year")
+	}
+	p := exportPeriod{Year: year, Months: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}}
+	if m := r.URL.Query().Get("month"); m != "" {
+		month, err := strconv.Atoi(m)
+		if err != nil || month < 1 || month > 12 {
+			return exportPeriod{}, errors.New("invalid month")
+		}
+		p.Months = []int{month}
+	}
+	return p, nil
+}
+
+// exportPeriodParams documents the query parameters read by parseExportPeriod.
+func exportPeriodParams() []Parameter {
+	return []Parameter{
+		requiredQueryParam("year", intSchema(1970, 3000), ""),
+		queryParam("month", intSchema(1, 12), "Omit to export the whole year"),
+	}
+}
+
+// resolveExportFormat picks locale and currency from the query string, then the
+// user's saved settings, then the defaults.
+func resolveExportFormat(r *http.Request, repo *repository.Repository, userID int64) export.Format {
+	locale, currency := r.URL.Query().Get("locale"), r.URL.Query().Get("currency")
+	if locale == "" || currency == "" {
+		if settings, err := repo.GetUserSettings(r.Context(), userID); err == nil {
+			if locale == "" {
+				locale = settings["locale"]
+			}
+			if currency == "" {
+				currency = settings["currency"]
+			}
+		}
+	}
+	return export.NewFormat(locale, currency)
+}
+
+// registerSpreadsheetEndpoints wires CSV and XLSX export endpoints
+func registerSpreadsheetEndpoints(api *Routes, repo *repository.Repository) {
+	params := append(exportPeriodParams(),
+		queryParam("locale", stringSchema(), "Number and date locale, e.g. de-DE (default: the user's setting)"),
+		queryParam("currency", stringSchema(), "ISO 4217 code (default: the user's setting)"),
+	)
+	api.Tagged("Import and Export").Add(
+		route(http.MethodGet, "/export/csv", handleExportCSV(repo)).
+			WithSummary("Export a month or a year as CSV").
+			WithParams(params...).
+			WithRawResponse(http.StatusOK, "text/csv"),
+		route(http.MethodGet, "/export/xlsx", handleExportXLSX(repo)).
+			WithSummary("Export a month or a year as an XLSX workbook").
+			WithParams(params...).
+			WithRawResponse(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"),
+	)
+}
+
+// handleExportCSV streams a month or a year as CSV
+func handleExportCSV(repo *repository.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := getUserIDFromContext(r.Context())
+		period, err := parseExportPeriod(r)
+		if err != nil {
+			respondErr(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		format := resolveExportFormat(r, repo, userID)
+
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", period.filename("budget", "csv")))
+		w.WriteHeader(http.StatusOK)
+
+		streamMonths(r.Context(), repo, userID, period, export.NewCSVWriter(w, format))
+	}
+}
+
+// handleExportXLSX streams a month or a year as an XLSX workbook
+func handleExportXLSX(repo *repository.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := getUserIDFromContext(r.Context())
+		period, err := parseExportPeriod(r)
+		if err != nil {
+			respondErr(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		format := resolveExportFormat(r, repo, userID)
+
+		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", period.filename("budget", "xlsx")))
+		w.WriteHeader(http.StatusOK)
+
+		xw := export.NewXLSXWriter(w, format)
+		if streamMonths(r.Context(), repo, userID, period, xw) {
+			if err := xw.Close(); err != nil {
+				slog.Error("xlsx export failed", "err", err)
+			}
+		}
+	}
+}
+
+// streamMonths loads and writes one month at a time. Headers are already sent,
+// so failures can only be logged; the truncated body signals the error.
+func streamMonths(
+	ctx context.Context,
+	repo *repository.Repository,
+	userID int64,
+	period exportPeriod,
+	mw monthWriter,
+) bool {
+	for _, month := range period.Months {
+		data, err := repo.GetMonthlyData(ctx, userID, domain.YearMonth{Year: period.Year, Month: month})
+		if err != nil {
+			slog.Error("export: failed to load month", "year", period.Year, "month", month, "err", err)
+			return false
+		}
+		if err := mw.WriteMonth(data); err != nil {
+			slog.Error("export: failed to write month", "year", period.Year, "month", month, "err", err)
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,193 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/middleware"
+	"github.com/mdco1990/webapp/internal/repository"
+	"github.com/mdco1990/webapp/internal/security"
+)
+
+// syncMutation is the wire form of domain.SyncMutation: a batch operation
+// with the client's ID for it and the cursor it was queued at.
+type syncMutation struct {
+	ClientID string          `json:"client_id" openapi:"required,format=uuid"`
+	Cursor   string          `json:"cursor,omitempty"`
+	Ref      string          `json:"ref,omitempty" openapi:"format=uuid"`
+	Op       string          `json:"op" openapi:"required,enum=create|update|delete"`
+	Type     string          `json:"type" openapi:"required,enum=income_source|budget_source|expense|manual_budget_item"`
+	ID       int64           `json:"id,omitempty"`
+	Version  int64           `json:"version,omitempty"`
+	Data     json.RawMessage `json:"data,omitempty"`
+}
+
+// syncPushRequest is the body of POST /sync.
+type syncPushRequest struct {
+	Mutations []syncMutation `json:"mutations" openapi:"required"`
+}
+
+// syncResult is a mutation's result with the problem that failed it.
+type syncResult struct {
+	domain.SyncResult
+	Error *middleware.Problem `json:"error,omitempty"`
+}
+
+// syncPushResponse reports the pushed mutations in order.
+type syncPushResponse struct {
+	Results []syncResult `json:"results"`
+}
+
+// registerSyncEndpoints wires the delta sync endpoints for offline clients
+func registerSyncEndpoints(api *Routes, repo *repository.Repository) {
+	api.Tagged("Sync").Route("/sync", func(sync *Routes) {
+		sync.Add(
+			route(http.MethodGet, "/", handleSyncPull(repo)).
+				WithSummary("Pull changes").
+				WithDescription("Without `cursor`, returns every live income source, budget source, expense and "+
+					"manual budget (items included). With the `cursor` of the previous pull, returns the current "+
+					"state of each record written since, and a tombstone (`deleted`) for records deleted since. "+
+					"Send the returned `cursor` next time; pull again straight away while `has_more` is true.").
+				WithParams(
+					queryParam("cursor", stringSchema(), "Opaque cursor returned by the previous pull"),
+					queryParam("limit", intSchema(1, repository.MaxSyncLimit),
+						fmt.Sprintf("Writes to read, %d by default", repository.DefaultSyncLimit)),
+				).
+				WithResponse(http.StatusOK, domain.SyncPage{}),
+			route(http.MethodPost, "/", handleSyncPush(repo)).
+				WithSummary("Push offline mutations").
+				WithDescription("Applies writes queued while offline, in order, each on its own. `client_id` is a "+
+					"UUID generated by the client: pushing a mutation again returns its first result instead of "+
+					"applying it twice. An update or delete conflicts when its record was written after the "+
+					"mutation's `cursor` (the cursor of the last pull before it was queued) other than by this "+
+					"push. To update or delete a record created offline, send the `client_id` of its create as "+
+					"`ref` instead of an `id`; the create may be earlier in the same push or in a previous one. "+
+					"Conflicting expense writes are applied (last writer wins) and flagged `conflict`; "+
+					"conflicting source and manual budget item writes, and writes with a stale `version`, are "+
+					"`rejected` with the server's `current` state. Mutations that fail otherwise are `failed` "+
+					"with an `error` and do not stop the rest.").
+				WithRequest(syncPushRequest{}).
+				WithResponse(http.StatusOK, syncPushResponse{}),
+		)
+	})
+}
+
+// handleSyncPull returns a snapshot, or the changes since the cursor
+func handleSyncPull(repo *repository.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := getUserIDFromContext(r.Context())
+		q := r.URL.Query()
+		limit := repository.DefaultSyncLimit
+		if s := q.Get("limit"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 1 || n > repository.MaxSyncLimit {
+				respondError(w, r, domain.Invalid("invalid limit", domain.FieldError{
+					Field: "limit", Message: fmt.Sprintf("must be between 1 and %d", repository.MaxSyncLimit),
+				}))
+				return
+			}
+			limit = n
+		}
+
+		var (
+			page domain.SyncPage
+			err  error
+		)
+		if s := q.Get("cursor"); s == "" {
+			page, err = repo.SyncSnapshot(r.Context(), userID)
+		} else {
+			var after int64
+			if after, err = domain.DecodeSyncCursor(s); err != nil {
+				respondError(w, r, domain.Invalid("invalid cursor",
+					domain.FieldError{Field: "cursor", Message: "is malformed"}))
+				return
+			}
+			page, err = repo.SyncChanges(r.Context(), userID, after, limit)
+		}
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, page)
+	}
+}
+
+// handleSyncPush applies a client's queued mutations. Every mutation is
+// validated before any is applied; after that, each one succeeds or fails on
+// its own and is reported in its result.
+func handleSyncPush(repo *repository.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req syncPushRequest
+		r.Body = http.MaxBytesReader(w, r.Body, security.MaxRequestBodySize)
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondErr(w, r, http.StatusBadRequest, invalidBodyMsg)
+			return
+		}
+		if len(req.Mutations) == 0 || len(req.Mutations) > domain.MaxSyncMutations {
+			respondError(w, r, domain.Invalid(
+				fmt.Sprintf("mutations must hold 1 to %d mutations", domain.MaxSyncMutations),
+				domain.FieldError{Field: "mutations", Message: "wrong number of mutations"}))
+			return
+		}
+
+		mutations := make([]domain.SyncMutation, len(req.Mutations))
+		for i, in := range req.Mutations {
+			m, err := parseSyncMutation(in)
+			if err != nil {
+				respondError(w, r, batchOperationError("mutations", i, err))
+				return
+			}
+			mutations[i] = m
+		}
+
+		results := repo.PushSyncMutations(r.Context(), getUserIDFromContext(r.Context()), mutations)
+		resp := syncPushResponse{Results: make([]syncResult, len(results))}
+		for i, res := range results {
+			resp.Results[i] = syncResult{SyncResult: res}
+			if res.Err != nil {
+				status, p := middleware.ProblemFor(res.Err)
+				if status == http.StatusInternalServerError {
+					slog.Error("sync mutation failed", "err", res.Err, "client_id", res.ClientID,
+						"request_id", middleware.GetRequestID(r.Context()))
+				}
+				resp.Results[i].Error = &p
+			}
+		}
+		respondJSON(w, http.StatusOK, resp)
+	}
+}
+
+// parseSyncMutation checks a mutation's client ID, cursor and ref and decodes
+// and validates its operation.
+func parseSyncMutation(in syncMutation) (domain.SyncMutation, error) {
+	m := domain.SyncMutation{ClientID: in.ClientID}
+	if !uuidPattern.MatchString(in.ClientID) {
+		return m, security.ValidationError{Field: "client_id", Message: "client_id must be a UUID"}
+	}
+	var err error
+	if in.Cursor != "" {
+		if m.Cursor, err = domain.DecodeSyncCursor(in.Cursor); err != nil {
+			return m, security.ValidationError{Field: "cursor", Message: "cursor is malformed", Err: err}
+		}
+	}
+	op := batchOperation{Op: in.Op, Type: in.Type, ID: in.ID, Version: in.Version, Data: in.Data}
+	if in.Ref == "" {
+		m.Operation, err = parseBatchOperation(op)
+		return m, err
+	}
+	switch {
+	case !uuidPattern.MatchString(in.Ref):
+		return m, security.ValidationError{Field: "ref", Message: "ref must be a UUID"}
+	case in.Op != domain.BatchUpdate && in.Op != domain.BatchDelete:
+		return m, security.ValidationError{Field: "ref", Message: "ref is only supported on update and delete"}
+	case in.ID != 0:
+		return m, security.ValidationError{Field: "id", Message: "id must not be set with ref"}
+	}
+	m.Ref = in.Ref
+	m.Operation, err = decodeBatchOperation(op)
+	return m, err
+}
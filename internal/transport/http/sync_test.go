@@ -0,0 +1,116 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/middleware"
+	"github.com/mdco1990/webapp/internal/repository"
+)
+
+func TestSyncEndpoints(t *testing.T) {
+	database := setupTestDB(t)
+	defer func() { _ = database.Close() }()
+	database.SetMaxOpenConns(1)
+	repo := repository.New(database)
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userIDKey, int64(1))))
+		})
+	})
+	registerSyncEndpoints(NewRoutes(r, NewOpenAPIDocument()), repo)
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return w
+	}
+
+	w := do(http.MethodGet, "/sync", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d %s", w.Code, w.Body.String())
+	}
+	var page domain.SyncPage
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil || page.Cursor == "" || len(page.Changes) != 0 {
+		t.Fatalf("Expected an empty snapshot with a cursor, got %s", w.Body.String())
+	}
+
+	w = do(http.MethodPost, "/sync", `{"mutations":[
+		{"client_id":"5f0c8c3e-2b7a-4a8e-9f3d-1c2b3a4d5e6f","cursor":"`+page.Cursor+`",
+		 "op":"create","type":"expense","data":{"year":2024,"month":3,"description":"Coffee","amount_cents":350}},
+		{"client_id":"5f0c8c3e-2b7a-4a8e-9f3d-1c2b3a4d5e70","op":"delete","type":"income_source","id":42}
+	]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Results []struct {
+			Status string              `json:"status"`
+			ID     int64               `json:"id"`
+			Error  *middleware.Problem `json:"error"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || len(resp.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %s", w.Body.String())
+	}
+	if res := resp.Results[0]; res.Status != domain.SyncApplied || res.ID == 0 || res.Error != nil {
+		t.Errorf("Expected the expense created, got %+v", res)
+	}
+	if res := resp.Results[1]; res.Status != domain.SyncFailed || res.Error == nil || res.Error.Status != http.StatusNotFound {
+		t.Errorf("Expected a 404 problem for the unknown source, got %+v", res)
+	}
+
+	// A record created offline can be updated in the same push by ref.
+	w = do(http.MethodPost, "/sync", `{"mutations":[
+		{"client_id":"5f0c8c3e-2b7a-4a8e-9f3d-1c2b3a4d5e72","op":"create","type":"income_source",
+		 "data":{"name":"Salary","year":2024,"month":3,"amount_cents":250000}},
+		{"client_id":"5f0c8c3e-2b7a-4a8e-9f3d-1c2b3a4d5e73","ref":"5f0c8c3e-2b7a-4a8e-9f3d-1c2b3a4d5e72",
+		 "op":"update","type":"income_source","data":{"name":"Salary","amount_cents":260000}}
+	]}`)
+	resp.Results = nil
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || len(resp.Results) != 2 ||
+		resp.Results[1].Status != domain.SyncApplied || resp.Results[1].ID != resp.Results[0].ID {
+		t.Errorf("Expected the new salary updated by ref, got %d %s", w.Code, w.Body.String())
+	}
+	if source, _ := repo.GetIncomeSource(context.Background(), resp.Results[0].ID, 1); source == nil ||
+		source.AmountCents != 260000 {
+		t.Errorf("Expected the salary updated, got %+v", source)
+	}
+
+	w = do(http.MethodGet, "/sync?cursor="+page.Cursor, "")
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil || len(page.Changes) != 2 ||
+		page.Changes[0].Type != domain.BatchExpense || page.Changes[1].Type != domain.BatchIncomeSource {
+		t.Errorf("Expected the new expense and salary, got %d %s", w.Code, w.Body.String())
+	}
+
+	tests := []struct {
+		method, target, body string
+		field                string
+	}{
+		{http.MethodGet, "/sync?cursor=" + domain.Cursor{Sort: domain.SortName, ID: 3}.Encode(), "", "cursor"},
+		{http.MethodGet, "/sync?cursor=" + page.Cursor + "&limit=0", "", "limit"},
+		{http.MethodPost, "/sync", `{"mutations":[{"client_id":"offline-1","op":"delete","type":"expense","id":1}]}`,
+			"mutations[0].client_id"},
+		{http.MethodPost, "/sync", `{"mutations":[{"client_id":"5f0c8c3e-2b7a-4a8e-9f3d-1c2b3a4d5e71",` +
+			`"cursor":"nope","op":"delete","type":"expense","id":1}]}`, "mutations[0].cursor"},
+		{http.MethodPost, "/sync", `{"mutations":[{"client_id":"5f0c8c3e-2b7a-4a8e-9f3d-1c2b3a4d5e74",` +
+			`"ref":"5f0c8c3e-2b7a-4a8e-9f3d-1c2b3a4d5e72","op":"delete","type":"expense","id":1}]}`, "mutations[0].id"},
+		{http.MethodPost, "/sync", `{"mutations":[{"client_id":"5f0c8c3e-2b7a-4a8e-9f3d-1c2b3a4d5e75",` +
+			`"ref":"5f0c8c3e-2b7a-4a8e-9f3d-1c2b3a4d5e72","op":"create","type":"expense","data":{}}]}`, "mutations[0].ref"},
+	}
+	for _, tt := range tests {
+		w := do(tt.method, tt.target, tt.body)
+		var p middleware.Problem
+		_ = json.Unmarshal(w.Body.Bytes(), &p)
+		if w.Code != http.StatusBadRequest || len(p.Errors) == 0 || p.Errors[0].Field != tt.field {
+			t.Errorf("%s %s: Expected 400 on %s, got %d %s", tt.method, tt.target, tt.field, w.Code, w.Body.String())
+		}
+	}
+}
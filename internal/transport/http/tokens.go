@@ -0,0 +1,85 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/service"
+)
+
+// registerTokenEndpoints wires personal access token management
+func registerTokenEndpoints(api *Routes, tokens *service.TokenService) {
+	api.Tagged("Access Tokens").Scoped(domain.ScopeAdmin).Route("/tokens", func(t *Routes) {
+		t.Add(
+			route(http.MethodGet, "/", handleListAccessTokens(tokens)).
+				WithSummary("List access tokens").
+				WithDescription("Lists the user's personal access tokens with their scopes, expiry and last use. "+
+					"Secrets are only returned on creation.").
+				WithResponse(http.StatusOK, []domain.AccessToken{}),
+			route(http.MethodPost, "/", handleCreateAccessToken(tokens)).
+				WithSummary("Create an access token").
+				WithDescription(accessTokenCreateDescription).
+				WithRequest(domain.CreateAccessTokenRequest{}).
+				WithResponse(http.StatusCreated, domain.AccessToken{}),
+			route(http.MethodDelete, "/{id}", handleRevokeAccessToken(tokens)).
+				WithSummary("Revoke an access token").
+				WithDescription("Deletes the token; requests made with it are refused from then on.").
+				WithResponse(http.StatusOK, statusResponse{}),
+		)
+	})
+}
+
+// accessTokenCreateDescription documents token use and scopes.
+const accessTokenCreateDescription = "Creates a token for scripts and integrations, sent as " +
+	"`Authorization: Bearer <token>` in place of a session (the API key is not needed with it). " +
+	"The response holds the token, which is not shown again; only its hash is kept. Scopes:\n\n" +
+	"- `read:budget`: every read\n" +
+	"- `write:expenses`: adding, deleting and reverting expenses\n" +
+	"- `admin`: everything the user can do, including managing tokens and, for admins, the admin endpoints\n\n" +
+	"Without `expires_in_days` the token does not expire. A user may have at most 20 tokens."
+
+// handleListAccessTokens lists the user's tokens (without secrets)
+func handleListAccessTokens(tokens *service.TokenService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		list, err := tokens.ListTokens(r.Context(), getUserIDFromContext(r.Context()))
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, list)
+	}
+}
+
+// handleCreateAccessToken creates a token; the response holds its secret,
+// which is not shown again
+func handleCreateAccessToken(tokens *service.TokenService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req domain.CreateAccessTokenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondErr(w, r, http.StatusBadRequest, invalidBodyMsg)
+			return
+		}
+		token, err := tokens.CreateToken(r.Context(), getUserIDFromContext(r.Context()), req)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusCreated, token)
+	}
+}
+
+// handleRevokeAccessToken deletes one of the user's tokens
+func handleRevokeAccessToken(tokens *service.TokenService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r, "id")
+		if !ok {
+			return
+		}
+		if err := tokens.RevokeToken(r.Context(), getUserIDFromContext(r.Context()), id); err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
@@ -0,0 +1,156 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mdco1990/webapp/internal/config"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/middleware"
+	"github.com/mdco1990/webapp/internal/repository"
+	"github.com/mdco1990/webapp/internal/service"
+)
+
+func TestAccessTokenAuth(t *testing.T) {
+	database := setupTestDB(t)
+	defer func() { _ = database.Close() }()
+	database.SetMaxOpenConns(1)
+	cfg := config.Config{
+		CORSAllowedOrigins: []string{"http://localhost:3000"},
+		Env:                "test",
+		APIKey:             "shared-key",
+	}
+	router := newTestRouter(t, cfg, database)
+
+	tokens := service.NewTokenService(repository.New(database))
+	ctx := context.Background()
+	read, err := tokens.CreateToken(ctx, 1, domain.CreateAccessTokenRequest{
+		Name: "read", Scopes: []string{domain.ScopeReadBudget},
+	})
+	if err != nil {
+		t.Fatalf("CreateToken failed: %v", err)
+	}
+	write, err := tokens.CreateToken(ctx, 1, domain.CreateAccessTokenRequest{
+		Name: "write", Scopes: []string{domain.ScopeWriteExpenses},
+	})
+	if err != nil {
+		t.Fatalf("CreateToken failed: %v", err)
+	}
+
+	// Tokens replace both the session and the shared API key.
+	do := func(method, target, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	code := func(w *httptest.ResponseRecorder) string {
+		var p middleware.Problem
+		_ = json.Unmarshal(w.Body.Bytes(), &p)
+		return p.Code
+	}
+
+	if w := do(http.MethodGet, "/api/v1/expenses?year=2024&month=3", read.Token, ""); w.Code != http.StatusOK {
+		t.Errorf("Expected 200 for a read with a read token, got %d %s", w.Code, w.Body.String())
+	}
+	if w := do(http.MethodGet, "/api/v1/expenses?year=2024&month=3", read.Token+"x", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for an unknown token, got %d", w.Code)
+	}
+
+	expense := `{"year":2024,"month":3,"description":"Coffee","amount_cents":350}`
+	w := do(http.MethodPost, "/api/v1/expenses", read.Token, expense)
+	if w.Code != http.StatusForbidden || code(w) != "insufficient_scope" {
+		t.Errorf("Expected 403 insufficient_scope for a write with a read token, got %d %s", w.Code, w.Body.String())
+	}
+	if w := do(http.MethodPost, "/api/v1/expenses", write.Token, expense); w.Code != http.StatusCreated {
+		t.Errorf("Expected 201 for a write with a write token, got %d %s", w.Code, w.Body.String())
+	}
+
+	// Managing tokens needs the admin scope.
+	if w := do(http.MethodGet, "/api/v1/tokens", write.Token, ""); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 listing tokens without the admin scope, got %d", w.Code)
+	}
+	if w := do(http.MethodGet, "/api/v1/admin/users", read.Token, ""); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for the admin endpoints without the admin scope, got %d", w.Code)
+	}
+}
+
+func TestAccessTokenScopesGraphQLAndRPC(t *testing.T) {
+	database := setupTestDB(t)
+	defer func() { _ = database.Close() }()
+	database.SetMaxOpenConns(1)
+	router := newTestRouter(t, config.Config{
+		CORSAllowedOrigins: []string{"http://localhost:3000"},
+		Env:                "test",
+		APIKey:             "shared-key",
+	}, database)
+
+	tokens := service.NewTokenService(repository.New(database))
+	token := func(scope string) string {
+		t.Helper()
+		created, err := tokens.CreateToken(context.Background(), 1, domain.CreateAccessTokenRequest{
+			Name: scope, Scopes: []string{scope},
+		})
+		if err != nil {
+			t.Fatalf("CreateToken failed: %v", err)
+		}
+		return created.Token
+	}
+	read, write := token(domain.ScopeReadBudget), token(domain.ScopeWriteExpenses)
+
+	post := func(target, contentType, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	graphql := func(token, query string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]string{"query": query})
+		return post("/api/v1/graphql", "application/json", token, string(body))
+	}
+
+	const (
+		monthQuery     = `{ month(year: 2024, month: 3) { year } }`
+		createExpense  = `mutation { create_expense(input: {year: 2024, month: 3, description: "Coffee", amount_cents: 350}) { id } }`
+		setSalary      = `mutation { set_salary(year: 2024, month: 3, amount_cents: 100) }`
+		viaFragment    = `mutation { ...m } fragment m on Mutation { set_salary(year: 2024, month: 3, amount_cents: 100) }`
+		summary        = "/budget.v1.FinancialService/GetMonthlySummary"
+		addExpense     = "/budget.v1.FinancialService/AddExpense"
+		addIncome      = "/budget.v1.FinancialService/AddIncomeSource"
+		yearMonth      = `{"yearMonth":{"year":2024,"month":3}}`
+		expenseMessage = `{"yearMonth":{"year":2024,"month":3},"description":"Coffee","amountCents":"350"}`
+	)
+	tests := []struct {
+		name string
+		w    *httptest.ResponseRecorder
+		want int
+	}{
+		{"graphql query with a read token", graphql(read, monthQuery), http.StatusOK},
+		{"graphql expense mutation with a read token", graphql(read, createExpense), http.StatusForbidden},
+		{"graphql expense mutation with a write token", graphql(write, createExpense), http.StatusOK},
+		{"graphql other mutation with a write token", graphql(write, setSalary), http.StatusForbidden},
+		{"graphql mutation in a fragment with a write token", graphql(write, viaFragment), http.StatusForbidden},
+		{"read RPC with a read token", post(summary, "application/json", read, yearMonth), http.StatusOK},
+		{"expense RPC with a read token", post(addExpense, "application/json", read, expenseMessage), http.StatusForbidden},
+		{"expense RPC with a write token", post(addExpense, "application/json", write, expenseMessage), http.StatusOK},
+		{"source RPC with a write token", post(addIncome, "application/json", write, `{}`), http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		if tt.w.Code != tt.want {
+			t.Errorf("%s: Expected %d, got %d %s", tt.name, tt.want, tt.w.Code, tt.w.Body.String())
+		}
+		if tt.want == http.StatusOK && strings.Contains(tt.w.Body.String(), `"errors"`) {
+			t.Errorf("%s: Expected no errors, got %s", tt.name, tt.w.Body.String())
+		}
+	}
+}
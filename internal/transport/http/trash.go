@@ -0,0 +1,81 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/service"
+)
+
+// registerTrashEndpoints wires listing, restoring and purging deleted records
+func registerTrashEndpoints(api *Routes, trash *service.TrashService) {
+	recordType := pathParam("type", enumSchema(domain.BatchIncomeSource, domain.BatchBudgetSource,
+		domain.BatchExpense, domain.BatchManualBudgetItem))
+	api.Tagged("Trash").Route("/trash", func(tr *Routes) {
+		tr.Add(
+			route(http.MethodGet, "/", handleListTrash(trash)).
+				WithSummary("List deleted records").
+				WithDescription("Lists the user's deleted income sources, budget sources and manual budget items, "+
+					"plus all deleted expenses, most recently deleted first. Records are purged for good at "+
+					"`purge_at`, which is absent when the trash is kept until purged by hand.").
+				WithResponse(http.StatusOK, []domain.TrashItem{}),
+			route(http.MethodPost, "/{type}/{id}/restore", handleRestoreTrashItem(trash)).
+				WithSummary("Restore a deleted record").
+				WithDescription("Takes the record out of the trash. A restored source gets a new version "+
+					"(ETag); a restored manual budget item is added back to its month's manual budget.").
+				WithParams(recordType).
+				WithResponse(http.StatusOK, statusResponse{}),
+			route(http.MethodDelete, "/{type}/{id}", handlePurgeTrashItem(trash)).
+				WithSummary("Purge a deleted record").
+				WithDescription("Deletes the record from the trash for good, with its snapshots in the "+
+					"record's history.").
+				WithParams(recordType).
+				WithResponse(http.StatusOK, statusResponse{}),
+		)
+	})
+}
+
+// handleListTrash lists the user's deleted records
+func handleListTrash(trash *service.TrashService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		items, err := trash.List(r.Context(), getUserIDFromContext(r.Context()))
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, items)
+	}
+}
+
+// handleRestoreTrashItem takes a record out of the user's trash
+func handleRestoreTrashItem(trash *service.TrashService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r, "id")
+		if !ok {
+			return
+		}
+		err := trash.Restore(r.Context(), getUserIDFromContext(r.Context()), chi.URLParam(r, "type"), id)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
+
+// handlePurgeTrashItem deletes a record from the user's trash for good
+func handlePurgeTrashItem(trash *service.TrashService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r, "id")
+		if !ok {
+			return
+		}
+		err := trash.Purge(r.Context(), getUserIDFromContext(r.Context()), chi.URLParam(r, "type"), id)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
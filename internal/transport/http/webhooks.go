@@ -0,0 +1,181 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/service"
+)
+
+const (
+	defaultWebhookDeliveries = 50
+	maxWebhookDeliveries     = 500
+)
+
+// registerWebhookEndpoints wires webhook management and the delivery log
+func registerWebhookEndpoints(api *Routes, webhooks *service.WebhookService) {
+	api.Tagged("Webhooks").Route("/webhooks", func(wh *Routes) {
+		wh.Add(
+			route(http.MethodGet, "/", handleListWebhooks(webhooks)).
+				WithSummary("List webhooks").
+				WithDescription("Lists the user's webhooks. Signing secrets are only returned on creation.").
+				WithResponse(http.StatusOK, []domain.Webhook{}),
+			route(http.MethodPost, "/", handleCreateWebhook(webhooks)).
+				WithSummary("Create a webhook").
+				WithDescription(webhookCreateDescription).
+				WithRequest(domain.CreateWebhookRequest{}).
+				WithResponse(http.StatusCreated, domain.Webhook{}),
+			route(http.MethodDelete, "/{id}", handleDeleteWebhook(webhooks)).
+				WithSummary("Delete a webhook").
+				WithDescription("Deletes the webhook and its delivery log.").
+				WithResponse(http.StatusOK, statusResponse{}),
+			route(http.MethodGet, "/{id}/deliveries", handleListWebhookDeliveries(webhooks)).
+				WithSummary("List deliveries").
+				WithDescription("Returns the webhook's most recent deliveries, newest first.").
+				WithParams(queryParam("limit", intSchema(1, maxWebhookDeliveries), "")).
+				WithResponse(http.StatusOK, []domain.WebhookDelivery{}),
+			route(http.MethodGet, "/{id}/deliveries/{delivery}", handleGetWebhookDelivery(webhooks)).
+				WithSummary("Get a delivery").
+				WithDescription("Returns a delivery with its payload and every attempt.").
+				WithResponse(http.StatusOK, domain.WebhookDelivery{}),
+			route(http.MethodPost, "/{id}/deliveries/{delivery}/redeliver", handleRedeliverWebhook(webhooks)).
+				WithSummary("Redeliver").
+				WithDescription("Queues the delivery again right away.").
+				WithResponse(http.StatusAccepted, statusResponse{}),
+		)
+	})
+}
+
+// webhookCreateDescription documents the delivery format and retry policy.
+const webhookCreateDescription = "Subscribes an HTTP(S) URL to event types (`*` for all). Events are POSTed as JSON " +
+	"(`{\"id\",\"type\",\"created_at\",\"data\"}`) with these headers:\n\n" +
+	"- `X-Webhook-Event`: the event type\n" +
+	"- `X-Webhook-Delivery`: the delivery ID, stable across retries\n" +
+	"- `X-Webhook-Signature`: `t=<unix seconds>,v1=<hex HMAC-SHA256 of \"<t>.<body>\" keyed with the secret>`\n\n" +
+	"Any 2xx response acknowledges the delivery. Otherwise it is retried with exponential backoff, " +
+	"from 30 seconds up to 6 hours, for 8 attempts in all before it is marked failed. " +
+	"Deliveries are only made to public addresses: a URL that resolves to a loopback, private or " +
+	"link-local address fails each attempt. A user may have at most 10 webhooks."
+
+// handleListWebhooks lists the user's webhooks (without secrets)
+func handleListWebhooks(webhooks *service.WebhookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		hooks, err := webhooks.ListWebhooks(r.Context(), getUserIDFromContext(r.Context()))
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, hooks)
+	}
+}
+
+// handleCreateWebhook subscribes a URL to event types; the response holds the
+// signing secret, which is not shown again
+func handleCreateWebhook(webhooks *service.WebhookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req domain.CreateWebhookRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondErr(w, r, http.StatusBadRequest, invalidBodyMsg)
+			return
+		}
+		hook, err := webhooks.CreateWebhook(r.Context(), getUserIDFromContext(r.Context()), req)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusCreated, hook)
+	}
+}
+
+// handleDeleteWebhook deletes a webhook and its delivery log
+func handleDeleteWebhook(webhooks *service.WebhookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r, "id")
+		if !ok {
+			return
+		}
+		if err := webhooks.DeleteWebhook(r.Context(), getUserIDFromContext(r.Context()), id); err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
+
+// handleListWebhookDeliveries returns a webhook's most recent deliveries
+func handleListWebhookDeliveries(webhooks *service.WebhookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r, "id")
+		if !ok {
+			return
+		}
+		limit := defaultWebhookDeliveries
+		if v := r.URL.Query().Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 || n > maxWebhookDeliveries {
+				respondErr(w, r, http.StatusBadRequest, "invalid limit")
+				return
+			}
+			limit = n
+		}
+		deliveries, err := webhooks.ListDeliveries(r.Context(), getUserIDFromContext(r.Context()), id, limit)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, deliveries)
+	}
+}
+
+// handleGetWebhookDelivery returns a delivery with every attempt and its response code
+func handleGetWebhookDelivery(webhooks *service.WebhookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r, "id")
+		if !ok {
+			return
+		}
+		deliveryID, ok := pathID(w, r, "delivery")
+		if !ok {
+			return
+		}
+		d, err := webhooks.GetDelivery(r.Context(), getUserIDFromContext(r.Context()), id, deliveryID)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, d)
+	}
+}
+
+// handleRedeliverWebhook queues a delivery again right away
+func handleRedeliverWebhook(webhooks *service.WebhookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r, "id")
+		if !ok {
+			return
+		}
+		deliveryID, ok := pathID(w, r, "delivery")
+		if !ok {
+			return
+		}
+		err := webhooks.Redeliver(r.Context(), getUserIDFromContext(r.Context()), id, deliveryID)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusAccepted, map[string]string{"status": domain.WebhookPending})
+	}
+}
+
+// pathID parses a positive ID path parameter, answering 400 if it is not one.
+func pathID(w http.ResponseWriter, r *http.Request, param string) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, param), 10, 64)
+	if err != nil || id <= 0 {
+		respondErr(w, r, http.StatusBadRequest, errInvalidID)
+		return 0, false
+	}
+	return id, true
+}
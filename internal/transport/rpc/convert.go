@@ -0,0 +1,125 @@
+package rpcapi
+
+import (
+	"github.com/mdco1990/webapp/internal/domain"
+	budgetv1 "github.com/mdco1990/webapp/internal/gen/budget/v1"
+	"github.com/mdco1990/webapp/internal/security"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// yearMonth validates a request's year_month.
+func yearMonth(pb *budgetv1.YearMonth) (domain.YearMonth, error) {
+	if pb == nil {
+		return domain.YearMonth{}, domain.Invalid("year_month is required",
+			domain.FieldError{Field: "year_month", Message: "is required"})
+	}
+	ym := domain.YearMonth{Year: int(pb.GetYear()), Month: int(pb.GetMonth())}
+	if err := security.ValidateYearMonth(ym); err != nil {
+		return domain.YearMonth{}, err
+	}
+	return ym, nil
+}
+
+// version validates an optional version; 0 means unconditional.
+func version(v int64) (int64, error) {
+	if v < 0 {
+		return 0, domain.Invalid("version must not be negative",
+			domain.FieldError{Field: "version", Message: "must not be negative"})
+	}
+	return v, nil
+}
+
+func dayOfMonth(d *int32) *int {
+	if d == nil {
+		return nil
+	}
+	n := int(*d)
+	return &n
+}
+
+func toYearMonth(ym domain.YearMonth) *budgetv1.YearMonth {
+	return &budgetv1.YearMonth{Year: int32(ym.Year), Month: int32(ym.Month)}
+}
+
+func toDayOfMonth(d *int) *int32 {
+	if d == nil {
+		return nil
+	}
+	n := int32(*d)
+	return &n
+}
+
+func toIncomeSource(s *domain.IncomeSource) *budgetv1.IncomeSource {
+	return &budgetv1.IncomeSource{
+		Id:          s.ID,
+		Name:        s.Name,
+		YearMonth:   toYearMonth(s.YearMonth),
+		AmountCents: int64(s.AmountCents),
+		DayOfMonth:  toDayOfMonth(s.DayOfMonth),
+		Version:     s.Version,
+		CreatedAt:   timestamppb.New(s.CreatedAt),
+		UpdatedAt:   timestamppb.New(s.UpdatedAt),
+	}
+}
+
+func toBudgetSource(s *domain.BudgetSource) *budgetv1.BudgetSource {
+	return &budgetv1.BudgetSource{
+		Id:          s.ID,
+		Name:        s.Name,
+		YearMonth:   toYearMonth(s.YearMonth),
+		AmountCents: int64(s.AmountCents),
+		DayOfMonth:  toDayOfMonth(s.DayOfMonth),
+		Version:     s.Version,
+		CreatedAt:   timestamppb.New(s.CreatedAt),
+		UpdatedAt:   timestamppb.New(s.UpdatedAt),
+	}
+}
+
+func toExpense(e *domain.Expense) *budgetv1.Expense {
+	return &budgetv1.Expense{
+		Id:          e.ID,
+		YearMonth:   toYearMonth(e.YearMonth),
+		Category:    e.Category,
+		Description: e.Description,
+		AmountCents: int64(e.AmountCents),
+		CreatedAt:   timestamppb.New(e.CreatedAt),
+	}
+}
+
+func toManualBudget(mb *domain.ManualBudget) *budgetv1.ManualBudget {
+	out := &budgetv1.ManualBudget{
+		YearMonth:       toYearMonth(mb.YearMonth),
+		BankAmountCents: int64(mb.BankAmountCents),
+		Version:         mb.Version,
+	}
+	for _, it := range mb.Items {
+		out.Items = append(out.Items, &budgetv1.ManualBudgetItem{
+			Id: it.ID, Name: it.Name, AmountCents: int64(it.AmountCents),
+		})
+	}
+	return out
+}
+
+func toIncomeSources(in []domain.IncomeSource) []*budgetv1.IncomeSource {
+	out := make([]*budgetv1.IncomeSource, len(in))
+	for i := range in {
+		out[i] = toIncomeSource(&in[i])
+	}
+	return out
+}
+
+func toBudgetSources(in []domain.BudgetSource) []*budgetv1.BudgetSource {
+	out := make([]*budgetv1.BudgetSource, len(in))
+	for i := range in {
+		out[i] = toBudgetSource(&in[i])
+	}
+	return out
+}
+
+func toExpenses(in []domain.Expense) []*budgetv1.Expense {
+	out := make([]*budgetv1.Expense, len(in))
+	for i := range in {
+		out[i] = toExpense(&in[i])
+	}
+	return out
+}
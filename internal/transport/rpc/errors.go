@@ -0,0 +1,54 @@
+package rpcapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"connectrpc.com/connect"
+	"github.com/mdco1990/webapp/internal/middleware"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// errorDomain names this API in ErrorInfo details.
+const errorDomain = "budget.v1"
+
+// statusCodes maps the REST problem status to the Connect code.
+var statusCodes = map[int]connect.Code{
+	http.StatusBadRequest:          connect.CodeInvalidArgument,
+	http.StatusUnauthorized:        connect.CodeUnauthenticated,
+	http.StatusForbidden:           connect.CodePermissionDenied,
+	http.StatusNotFound:            connect.CodeNotFound,
+	http.StatusConflict:            connect.CodeAlreadyExists,
+	http.StatusPreconditionFailed:  connect.CodeFailedPrecondition,
+	http.StatusUnprocessableEntity: connect.CodeInvalidArgument,
+	http.StatusTooManyRequests:     connect.CodeResourceExhausted,
+}
+
+// connectError maps err as middleware.WriteProblem would: the problem code
+// travels as an ErrorInfo reason and field errors as a BadRequest detail.
+// Unexpected errors are logged and reported as a bare internal error.
+func connectError(ctx context.Context, err error) *connect.Error {
+	status, p := middleware.ProblemFor(err)
+	code, ok := statusCodes[status]
+	if !ok {
+		slog.Error("rpc failed", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return connect.NewError(connect.CodeInternal, errors.New(p.Detail))
+	}
+	ce := connect.NewError(code, errors.New(p.Detail))
+	if d, err := connect.NewErrorDetail(&errdetails.ErrorInfo{Reason: p.Code, Domain: errorDomain}); err == nil {
+		ce.AddDetail(d)
+	}
+	if len(p.Errors) > 0 {
+		br := &errdetails.BadRequest{}
+		for _, f := range p.Errors {
+			br.FieldViolations = append(br.FieldViolations,
+				&errdetails.BadRequest_FieldViolation{Field: f.Field, Description: f.Message})
+		}
+		if d, err := connect.NewErrorDetail(br); err == nil {
+			ce.AddDetail(d)
+		}
+	}
+	return ce
+}
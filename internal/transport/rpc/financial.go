@@ -0,0 +1,366 @@
+package rpcapi
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/mdco1990/webapp/internal/domain"
+	budgetv1 "github.com/mdco1990/webapp/internal/gen/budget/v1"
+	"github.com/mdco1990/webapp/internal/gen/budget/v1/budgetv1connect"
+	"github.com/mdco1990/webapp/internal/repository"
+	"github.com/mdco1990/webapp/internal/security"
+	"github.com/mdco1990/webapp/internal/service"
+)
+
+// financialServer implements budget.v1.FinancialService.
+type financialServer struct {
+	repo   *repository.Repository
+	svc    *service.Service
+	userID func(context.Context) int64
+}
+
+var _ budgetv1connect.FinancialServiceHandler = (*financialServer)(nil)
+
+func (s *financialServer) GetMonthlySummary(
+	ctx context.Context,
+	req *connect.Request[budgetv1.GetMonthlySummaryRequest],
+) (*connect.Response[budgetv1.GetMonthlySummaryResponse], error) {
+	ym, err := yearMonth(req.Msg.GetYearMonth())
+	if err != nil {
+		return nil, err
+	}
+	sum, err := s.svc.Summary(ctx, ym)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&budgetv1.GetMonthlySummaryResponse{
+		YearMonth:      toYearMonth(ym),
+		SalaryCents:    int64(sum.SalaryCents),
+		BudgetCents:    int64(sum.BudgetCents),
+		ExpenseCents:   int64(sum.ExpenseCents),
+		RemainingCents: int64(sum.Remaining),
+	}), nil
+}
+
+func (s *financialServer) GetMonthlyData(
+	ctx context.Context,
+	req *connect.Request[budgetv1.GetMonthlyDataRequest],
+) (*connect.Response[budgetv1.GetMonthlyDataResponse], error) {
+	ym, err := yearMonth(req.Msg.GetYearMonth())
+	if err != nil {
+		return nil, err
+	}
+	data, err := s.repo.GetMonthlyData(ctx, s.userID(ctx), ym)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&budgetv1.GetMonthlyDataResponse{
+		YearMonth:          toYearMonth(ym),
+		MonthName:          data.MonthName,
+		IncomeSources:      toIncomeSources(data.IncomeSources),
+		BudgetSources:      toBudgetSources(data.BudgetSources),
+		Expenses:           toExpenses(data.Expenses),
+		TotalIncomeCents:   int64(data.TotalIncome),
+		TotalBudgetCents:   int64(data.TotalBudget),
+		TotalExpensesCents: int64(data.TotalExpenses),
+		RemainingCents:     int64(data.Remaining),
+	}), nil
+}
+
+func (s *financialServer) SetIncome(
+	ctx context.Context,
+	req *connect.Request[budgetv1.SetIncomeRequest],
+) (*connect.Response[budgetv1.SetIncomeResponse], error) {
+	ym, err := yearMonth(req.Msg.GetYearMonth())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.svc.SetSalary(ctx, ym, domain.Money(req.Msg.GetAmountCents())); err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&budgetv1.SetIncomeResponse{}), nil
+}
+
+func (s *financialServer) GetIncome(
+	ctx context.Context,
+	req *connect.Request[budgetv1.GetIncomeRequest],
+) (*connect.Response[budgetv1.GetIncomeResponse], error) {
+	ym, err := yearMonth(req.Msg.GetYearMonth())
+	if err != nil {
+		return nil, err
+	}
+	amount, err := s.repo.GetSalary(ctx, ym)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&budgetv1.GetIncomeResponse{AmountCents: int64(amount)}), nil
+}
+
+func (s *financialServer) ListIncomeSources(
+	ctx context.Context,
+	req *connect.Request[budgetv1.ListIncomeSourcesRequest],
+) (*connect.Response[budgetv1.ListIncomeSourcesResponse], error) {
+	ym, err := yearMonth(req.Msg.GetYearMonth())
+	if err != nil {
+		return nil, err
+	}
+	sources, err := s.repo.ListIncomeSources(ctx, s.userID(ctx), ym)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&budgetv1.ListIncomeSourcesResponse{Sources: toIncomeSources(sources)}), nil
+}
+
+func (s *financialServer) AddIncomeSource(
+	ctx context.Context,
+	req *connect.Request[budgetv1.AddIncomeSourceRequest],
+) (*connect.Response[budgetv1.AddIncomeSourceResponse], error) {
+	ym, err := yearMonth(req.Msg.GetYearMonth())
+	if err != nil {
+		return nil, err
+	}
+	in, err := security.ValidateCreateIncomeSourceRequest(domain.CreateIncomeSourceRequest{
+		Name:        req.Msg.GetName(),
+		Year:        ym.Year,
+		Month:       ym.Month,
+		AmountCents: domain.Money(req.Msg.GetAmountCents()),
+		DayOfMonth:  dayOfMonth(req.Msg.DayOfMonth),
+	})
+	if err != nil {
+		return nil, err
+	}
+	source, err := s.repo.CreateIncomeSource(ctx, s.userID(ctx), *in)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&budgetv1.AddIncomeSourceResponse{Source: toIncomeSource(source)}), nil
+}
+
+func (s *financialServer) UpdateIncomeSource(
+	ctx context.Context,
+	req *connect.Request[budgetv1.UpdateIncomeSourceRequest],
+) (*connect.Response[budgetv1.UpdateIncomeSourceResponse], error) {
+	id, v, in, err := updateSourceRequest(req.Msg)
+	if err != nil {
+		return nil, err
+	}
+	userID := s.userID(ctx)
+	if _, err := s.repo.UpdateIncomeSource(ctx, id, userID, v, in); err != nil {
+		return nil, err
+	}
+	source, err := s.repo.GetIncomeSource(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&budgetv1.UpdateIncomeSourceResponse{Source: toIncomeSource(source)}), nil
+}
+
+func (s *financialServer) DeleteIncomeSource(
+	ctx context.Context,
+	req *connect.Request[budgetv1.DeleteIncomeSourceRequest],
+) (*connect.Response[budgetv1.DeleteIncomeSourceResponse], error) {
+	id, v, err := deleteSourceRequest(req.Msg)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.DeleteIncomeSource(ctx, id, s.userID(ctx), v); err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&budgetv1.DeleteIncomeSourceResponse{}), nil
+}
+
+func (s *financialServer) SetBudget(
+	ctx context.Context,
+	req *connect.Request[budgetv1.SetBudgetRequest],
+) (*connect.Response[budgetv1.SetBudgetResponse], error) {
+	ym, err := yearMonth(req.Msg.GetYearMonth())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.svc.SetBudget(ctx, ym, domain.Money(req.Msg.GetAmountCents())); err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&budgetv1.SetBudgetResponse{}), nil
+}
+
+func (s *financialServer) GetBudget(
+	ctx context.Context,
+	req *connect.Request[budgetv1.GetBudgetRequest],
+) (*connect.Response[budgetv1.GetBudgetResponse], error) {
+	ym, err := yearMonth(req.Msg.GetYearMonth())
+	if err != nil {
+		return nil, err
+	}
+	amount, err := s.repo.GetBudget(ctx, ym)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&budgetv1.GetBudgetResponse{AmountCents: int64(amount)}), nil
+}
+
+func (s *financialServer) ListBudgetSources(
+	ctx context.Context,
+	req *connect.Request[budgetv1.ListBudgetSourcesRequest],
+) (*connect.Response[budgetv1.ListBudgetSourcesResponse], error) {
+	ym, err := yearMonth(req.Msg.GetYearMonth())
+	if err != nil {
+		return nil, err
+	}
+	sources, err := s.repo.ListBudgetSources(ctx, s.userID(ctx), ym)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&budgetv1.ListBudgetSourcesResponse{Sources: toBudgetSources(sources)}), nil
+}
+
+func (s *financialServer) AddBudgetSource(
+	ctx context.Context,
+	req *connect.Request[budgetv1.AddBudgetSourceRequest],
+) (*connect.Response[budgetv1.AddBudgetSourceResponse], error) {
+	ym, err := yearMonth(req.Msg.GetYearMonth())
+	if err != nil {
+		return nil, err
+	}
+	in, err := security.ValidateCreateBudgetSourceRequest(domain.CreateBudgetSourceRequest{
+		Name:        req.Msg.GetName(),
+		Year:        ym.Year,
+		Month:       ym.Month,
+		AmountCents: domain.Money(req.Msg.GetAmountCents()),
+		DayOfMonth:  dayOfMonth(req.Msg.DayOfMonth),
+	})
+	if err != nil {
+		return nil, err
+	}
+	source, err := s.repo.CreateBudgetSource(ctx, s.userID(ctx), *in)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&budgetv1.AddBudgetSourceResponse{Source: toBudgetSource(source)}), nil
+}
+
+func (s *financialServer) UpdateBudgetSource(
+	ctx context.Context,
+	req *connect.Request[budgetv1.UpdateBudgetSourceRequest],
+) (*connect.Response[budgetv1.UpdateBudgetSourceResponse], error) {
+	id, v, in, err := updateSourceRequest(req.Msg)
+	if err != nil {
+		return nil, err
+	}
+	userID := s.userID(ctx)
+	if _, err := s.repo.UpdateBudgetSource(ctx, id, userID, v, in); err != nil {
+		return nil, err
+	}
+	source, err := s.repo.GetBudgetSource(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&budgetv1.UpdateBudgetSourceResponse{Source: toBudgetSource(source)}), nil
+}
+
+func (s *financialServer) DeleteBudgetSource(
+	ctx context.Context,
+	req *connect.Request[budgetv1.DeleteBudgetSourceRequest],
+) (*connect.Response[budgetv1.DeleteBudgetSourceResponse], error) {
+	id, v, err := deleteSourceRequest(req.Msg)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.DeleteBudgetSource(ctx, id, s.userID(ctx), v); err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&budgetv1.DeleteBudgetSourceResponse{}), nil
+}
+
+func (s *financialServer) AddExpense(
+	ctx context.Context,
+	req *connect.Request[budgetv1.AddExpenseRequest],
+) (*connect.Response[budgetv1.AddExpenseResponse], error) {
+	ym, err := yearMonth(req.Msg.GetYearMonth())
+	if err != nil {
+		return nil, err
+	}
+	e, err := security.ValidateExpense(&domain.Expense{
+		YearMonth:   ym,
+		Category:    req.Msg.GetCategory(),
+		Description: req.Msg.GetDescription(),
+		AmountCents: domain.Money(req.Msg.GetAmountCents()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	id, err := s.svc.AddExpense(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&budgetv1.AddExpenseResponse{Id: id}), nil
+}
+
+func (s *financialServer) DeleteExpense(
+	ctx context.Context,
+	req *connect.Request[budgetv1.DeleteExpenseRequest],
+) (*connect.Response[budgetv1.DeleteExpenseResponse], error) {
+	if err := security.ValidateID(req.Msg.GetId(), "id"); err != nil {
+		return nil, err
+	}
+	if err := s.svc.DeleteExpense(ctx, req.Msg.GetId()); err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&budgetv1.DeleteExpenseResponse{}), nil
+}
+
+func (s *financialServer) ListExpenses(
+	ctx context.Context,
+	req *connect.Request[budgetv1.ListExpensesRequest],
+) (*connect.Response[budgetv1.ListExpensesResponse], error) {
+	ym, err := yearMonth(req.Msg.GetYearMonth())
+	if err != nil {
+		return nil, err
+	}
+	expenses, err := s.svc.ListExpenses(ctx, ym)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&budgetv1.ListExpensesResponse{Expenses: toExpenses(expenses)}), nil
+}
+
+// sourceUpdate is the shape shared by the Update*SourceRequest messages.
+type sourceUpdate interface {
+	GetId() int64
+	GetVersion() int64
+	GetName() string
+	GetAmountCents() int64
+	GetDayOfMonth() int32
+}
+
+// updateSourceRequest validates an Update*SourceRequest.
+func updateSourceRequest(msg sourceUpdate) (int64, int64, domain.UpdateSourceRequest, error) {
+	id, v, err := deleteSourceRequest(msg)
+	if err != nil {
+		return 0, 0, domain.UpdateSourceRequest{}, err
+	}
+	var day *int
+	if d := msg.GetDayOfMonth(); d != 0 {
+		day = dayOfMonth(&d)
+	}
+	in, err := security.ValidateUpdateSourceRequest(domain.UpdateSourceRequest{
+		Name:        msg.GetName(),
+		AmountCents: domain.Money(msg.GetAmountCents()),
+		DayOfMonth:  day,
+	})
+	if err != nil {
+		return 0, 0, domain.UpdateSourceRequest{}, err
+	}
+	return id, v, *in, nil
+}
+
+// deleteSourceRequest validates the id and version of a source write.
+func deleteSourceRequest(msg interface {
+	GetId() int64
+	GetVersion() int64
+}) (int64, int64, error) {
+	if err := security.ValidateID(msg.GetId(), "id"); err != nil {
+		return 0, 0, err
+	}
+	v, err := version(msg.GetVersion())
+	return msg.GetId(), v, err
+}
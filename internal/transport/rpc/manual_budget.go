@@ -0,0 +1,71 @@
+package rpcapi
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/mdco1990/webapp/internal/domain"
+	budgetv1 "github.com/mdco1990/webapp/internal/gen/budget/v1"
+	"github.com/mdco1990/webapp/internal/gen/budget/v1/budgetv1connect"
+	"github.com/mdco1990/webapp/internal/repository"
+	"github.com/mdco1990/webapp/internal/security"
+)
+
+// manualBudgetServer implements budget.v1.ManualBudgetService.
+type manualBudgetServer struct {
+	repo   *repository.Repository
+	userID func(context.Context) int64
+}
+
+var _ budgetv1connect.ManualBudgetServiceHandler = (*manualBudgetServer)(nil)
+
+func (s *manualBudgetServer) GetManualBudget(
+	ctx context.Context,
+	req *connect.Request[budgetv1.GetManualBudgetRequest],
+) (*connect.Response[budgetv1.GetManualBudgetResponse], error) {
+	ym, err := yearMonth(req.Msg.GetYearMonth())
+	if err != nil {
+		return nil, err
+	}
+	mb, err := s.repo.GetManualBudget(ctx, s.userID(ctx), ym)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&budgetv1.GetManualBudgetResponse{Budget: toManualBudget(mb)}), nil
+}
+
+func (s *manualBudgetServer) SaveManualBudget(
+	ctx context.Context,
+	req *connect.Request[budgetv1.SaveManualBudgetRequest],
+) (*connect.Response[budgetv1.SaveManualBudgetResponse], error) {
+	ym, err := yearMonth(req.Msg.GetYearMonth())
+	if err != nil {
+		return nil, err
+	}
+	v, err := version(req.Msg.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+	mb := domain.ManualBudget{YearMonth: ym, BankAmountCents: domain.Money(req.Msg.GetBankAmountCents())}
+	for _, it := range req.Msg.GetItems() {
+		mb.Items = append(mb.Items, domain.ManualBudgetItem{
+			Name: it.GetName(), AmountCents: domain.Money(it.GetAmountCents()),
+		})
+	}
+	if err := security.ValidateManualBudget(mb); err != nil {
+		return nil, err
+	}
+	items, err := security.ValidateManualBudgetItems(mb.Items)
+	if err != nil {
+		return nil, err
+	}
+	userID := s.userID(ctx)
+	if _, err := s.repo.UpsertManualBudget(ctx, userID, ym, v, mb.BankAmountCents, items); err != nil {
+		return nil, err
+	}
+	saved, err := s.repo.GetManualBudget(ctx, userID, ym)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&budgetv1.SaveManualBudgetResponse{Budget: toManualBudget(saved)}), nil
+}
@@ -0,0 +1,186 @@
+package rpcapi
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/go-chi/chi/v5"
+	"github.com/mdco1990/webapp/internal/db"
+	budgetv1 "github.com/mdco1990/webapp/internal/gen/budget/v1"
+	"github.com/mdco1990/webapp/internal/gen/budget/v1/budgetv1connect"
+	"github.com/mdco1990/webapp/internal/repository"
+	"github.com/mdco1990/webapp/internal/service"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+type userKey struct{}
+
+// setupServer serves the services over h2c, as the real server does, with
+// the user taken from the X-User header in place of a session.
+func setupServer(t *testing.T) string {
+	t.Helper()
+	database, err := db.Open("sqlite", ":memory:", "")
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+	database.SetMaxOpenConns(1)
+	if err := db.Migrate(database); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+	repo := repository.New(database)
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-User") == "1" {
+				r = r.WithContext(context.WithValue(r.Context(), userKey{}, int64(1)))
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	Mount(r, repo, service.New(repo), func(ctx context.Context) int64 {
+		id, _ := ctx.Value(userKey{}).(int64)
+		return id
+	})
+	srv := httptest.NewServer(h2c.NewHandler(r, &http2.Server{}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+// h2cClient speaks HTTP/2 without TLS.
+func h2cClient() *http.Client {
+	return &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}}
+}
+
+func withUser(req connect.AnyRequest) {
+	req.Header().Set("X-User", "1")
+}
+
+func TestFinancialService(t *testing.T) {
+	client := budgetv1connect.NewFinancialServiceClient(h2cClient(), setupServer(t), connect.WithGRPC())
+	ctx := context.Background()
+	march := &budgetv1.YearMonth{Year: 2024, Month: 3}
+
+	add := connect.NewRequest(&budgetv1.AddIncomeSourceRequest{Name: "Salary", YearMonth: march, AmountCents: 250000})
+	withUser(add)
+	res, err := client.AddIncomeSource(ctx, add)
+	if err != nil {
+		t.Fatalf("AddIncomeSource failed: %v", err)
+	}
+	source := res.Msg.GetSource()
+	if source.GetId() == 0 || source.GetVersion() != 1 {
+		t.Fatalf("Expected a new source at version 1, got %v", source)
+	}
+
+	list := connect.NewRequest(&budgetv1.GetMonthlyDataRequest{YearMonth: march})
+	withUser(list)
+	data, err := client.GetMonthlyData(ctx, list)
+	if err != nil {
+		t.Fatalf("GetMonthlyData failed: %v", err)
+	}
+	if len(data.Msg.GetIncomeSources()) != 1 || data.Msg.GetTotalIncomeCents() != 250000 {
+		t.Errorf("Expected the salary in March, got %v", data.Msg)
+	}
+
+	update := connect.NewRequest(&budgetv1.UpdateIncomeSourceRequest{
+		Id: source.GetId(), Version: 5, Name: "Salary", AmountCents: 1,
+	})
+	withUser(update)
+	_, err = client.UpdateIncomeSource(ctx, update)
+	if connect.CodeOf(err) != connect.CodeFailedPrecondition || reason(err) != "version_mismatch" {
+		t.Errorf("Expected FAILED_PRECONDITION version_mismatch, got %v (%q)", err, reason(err))
+	}
+
+	invalid := connect.NewRequest(&budgetv1.AddExpenseRequest{
+		YearMonth: &budgetv1.YearMonth{Year: 2024, Month: 13}, Description: "Coffee", AmountCents: 350,
+	})
+	withUser(invalid)
+	_, err = client.AddExpense(ctx, invalid)
+	if connect.CodeOf(err) != connect.CodeInvalidArgument || reason(err) != "validation_failed" {
+		t.Errorf("Expected INVALID_ARGUMENT validation_failed, got %v (%q)", err, reason(err))
+	}
+
+	_, err = client.ListIncomeSources(ctx, connect.NewRequest(&budgetv1.ListIncomeSourcesRequest{YearMonth: march}))
+	if connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Errorf("Expected UNAUTHENTICATED without a user, got %v", err)
+	}
+}
+
+func TestManualBudgetService(t *testing.T) {
+	client := budgetv1connect.NewManualBudgetServiceClient(http.DefaultClient, setupServer(t))
+	ctx := context.Background()
+	march := &budgetv1.YearMonth{Year: 2024, Month: 3}
+
+	save := connect.NewRequest(&budgetv1.SaveManualBudgetRequest{
+		YearMonth: march, BankAmountCents: 100000,
+		Items: []*budgetv1.ManualBudgetItem{{Name: "Groceries", AmountCents: 40000}},
+	})
+	withUser(save)
+	res, err := client.SaveManualBudget(ctx, save)
+	if err != nil {
+		t.Fatalf("SaveManualBudget failed: %v", err)
+	}
+	if b := res.Msg.GetBudget(); b.GetVersion() != 1 || len(b.GetItems()) != 1 || b.GetItems()[0].GetId() == 0 {
+		t.Errorf("Expected the saved budget at version 1, got %v", b)
+	}
+
+	stale := connect.NewRequest(&budgetv1.SaveManualBudgetRequest{YearMonth: march, Version: 3})
+	withUser(stale)
+	_, err = client.SaveManualBudget(ctx, stale)
+	if connect.CodeOf(err) != connect.CodeFailedPrecondition {
+		t.Errorf("Expected FAILED_PRECONDITION for a stale version, got %v", err)
+	}
+
+	missing := connect.NewRequest(&budgetv1.GetManualBudgetRequest{})
+	withUser(missing)
+	_, err = client.GetManualBudget(ctx, missing)
+	if field := violation(err); connect.CodeOf(err) != connect.CodeInvalidArgument || field != "year_month" {
+		t.Errorf("Expected INVALID_ARGUMENT on year_month, got %v (%q)", err, field)
+	}
+}
+
+// reason returns the ErrorInfo reason (the REST problem code) of err.
+func reason(err error) string {
+	var ce *connect.Error
+	if !errors.As(err, &ce) {
+		return ""
+	}
+	for _, d := range ce.Details() {
+		if v, err := d.Value(); err == nil {
+			if info, ok := v.(*errdetails.ErrorInfo); ok {
+				return info.GetReason()
+			}
+		}
+	}
+	return ""
+}
+
+// violation returns the first BadRequest field of err.
+func violation(err error) string {
+	var ce *connect.Error
+	if !errors.As(err, &ce) {
+		return ""
+	}
+	for _, d := range ce.Details() {
+		if v, err := d.Value(); err == nil {
+			if br, ok := v.(*errdetails.BadRequest); ok && len(br.GetFieldViolations()) > 0 {
+				return br.GetFieldViolations()[0].GetField()
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+// Package rpcapi serves the budget.v1 Connect services (Connect, gRPC and
+// gRPC-Web protocols). Handlers use the same repository, service and
+// validation as the REST API and report the same error codes.
+package rpcapi
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+	"github.com/go-chi/chi/v5"
+	"github.com/mdco1990/webapp/internal/domain"
+	"github.com/mdco1990/webapp/internal/gen/budget/v1/budgetv1connect"
+	"github.com/mdco1990/webapp/internal/repository"
+	"github.com/mdco1990/webapp/internal/service"
+)
+
+// Mount serves the services on r. Authentication is left to r's middleware;
+// userID reads the authenticated user from the request context.
+func Mount(
+	r chi.Router,
+	repo *repository.Repository,
+	svc *service.Service,
+	userID func(context.Context) int64,
+) {
+	opts := connect.WithInterceptors(errorInterceptor(userID))
+	path, h := budgetv1connect.NewFinancialServiceHandler(&financialServer{repo: repo, svc: svc, userID: userID}, opts)
+	r.Handle(path+"*", h)
+	path, h = budgetv1connect.NewManualBudgetServiceHandler(&manualBudgetServer{repo: repo, userID: userID}, opts)
+	r.Handle(path+"*", h)
+}
+
+// errorInterceptor rejects calls without a user and turns the errors
+// handlers return into Connect errors.
+func errorInterceptor(userID func(context.Context) int64) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if userID(ctx) <= 0 {
+				return nil, connectError(ctx, domain.Unauthorized("not authenticated"))
+			}
+			res, err := next(ctx, req)
+			var ce *connect.Error
+			if err != nil && !errors.As(err, &ce) {
+				return nil, connectError(ctx, err)
+			}
+			return res, err
+		}
+	}
+}
@@ -22,6 +22,7 @@ import { useManualBudget } from './hooks/useManualBudget';
 import ManualBudgetSection from './components/ManualBudgetSection';
 import SavingsSection from './components/SavingsSection';
 import PasswordModal from './components/PasswordModal';
+import AccessTokensModal from './components/AccessTokensModal';
 import HeaderControls from './components/HeaderControls';
 import AuthScreen from './components/AuthScreen';
 import AnalyticsChartsSection from './components/AnalyticsChartsSection';
@@ -72,6 +73,7 @@ const App: React.FC = () => {
   const [registerForm, setRegisterForm] = useState({ username: '', password: '', email: '' });
   const [showRegister, setShowRegister] = useState(false);
   const [showPasswordForm, setShowPasswordForm] = useState(false);
+  const [showAccessTokens, setShowAccessTokens] = useState(false);
   const [passwordForm, setPasswordForm] = useState({
     currentPassword: '',
     newPassword: '',
@@ -282,6 +284,12 @@ const App: React.FC = () => {
         onClose={() => setShowPasswordForm(false)}
         onSubmit={handlePasswordUpdate}
       />
+      {/* Personal Access Tokens Modal */}
+      <AccessTokensModal
+        show={showAccessTokens}
+        isDarkMode={theme.isDarkMode}
+        onClose={() => setShowAccessTokens(false)}
+      />
       {/* Page Header */}
       <PageHeader
         title={getPageTitle(navigation.currentDate, t)}
@@ -298,6 +306,7 @@ const App: React.FC = () => {
             onMonthChange={navigation.onMonthChange}
             user={auth.user}
             onChangePasswordClick={() => setShowPasswordForm(true)}
+            onAccessTokensClick={() => setShowAccessTokens(true)}
             onLogout={auth.logout}
             onNavigateToUserManagement={() => setShowUserManagement(true)}
             onNavigateToDBAdmin={() => setShowDBAdmin(true)}
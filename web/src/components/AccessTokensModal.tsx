@@ -0,0 +1,198 @@
+import React from 'react';
+import { Modal, Button, Form, Table } from 'react-bootstrap';
+import { useTranslation } from 'react-i18next';
+import { listAccessTokens, createAccessToken, revokeAccessToken } from '../services/api';
+import { useToast } from '../shared/toast';
+import type { AccessToken, TokenScope } from '../types/budget';
+
+const SCOPES: { value: TokenScope; label: string }[] = [
+  { value: 'read:budget', label: 'Read budgets, sources and expenses' },
+  { value: 'write:expenses', label: 'Add and delete expenses' },
+  { value: 'admin', label: 'Full access' },
+];
+
+type Props = {
+  show: boolean;
+  isDarkMode: boolean;
+  onClose: () => void;
+};
+
+const formatDate = (value?: string) => (value ? new Date(value).toLocaleDateString() : '—');
+
+const AccessTokensModal: React.FC<Props> = ({ show, isDarkMode, onClose }) => {
+  const { t } = useTranslation();
+  const { push } = useToast();
+  const [tokens, setTokens] = React.useState<AccessToken[]>([]);
+  const [name, setName] = React.useState('');
+  const [scopes, setScopes] = React.useState<TokenScope[]>(['read:budget']);
+  const [expiresInDays, setExpiresInDays] = React.useState('90');
+  const [created, setCreated] = React.useState<AccessToken | null>(null);
+
+  const reload = React.useCallback(async () => {
+    try {
+      setTokens(await listAccessTokens());
+    } catch {
+      push(t('toast.errorTokens', { defaultValue: 'Failed to load access tokens' }), 'error');
+    }
+  }, [push, t]);
+
+  React.useEffect(() => {
+    if (show) {
+      setCreated(null);
+      reload();
+    }
+  }, [show, reload]);
+
+  const toggleScope = (scope: TokenScope) =>
+    setScopes((prev) => (prev.includes(scope) ? prev.filter((s) => s !== scope) : [...prev, scope]));
+
+  const handleCreate = async (e: React.FormEvent) => {
+    e.preventDefault();
+    if (!name.trim() || scopes.length === 0) {
+      push(t('toast.tokenInvalid', { defaultValue: 'Name the token and pick a scope' }), 'error');
+      return;
+    }
+    try {
+      const days = Number(expiresInDays);
+      const token = await createAccessToken({
+        name: name.trim(),
+        scopes,
+        ...(days > 0 ? { expires_in_days: days } : {}),
+      });
+      setCreated(token);
+      setName('');
+      reload();
+    } catch {
+      push(t('toast.errorCreateToken', { defaultValue: 'Failed to create access token' }), 'error');
+    }
+  };
+
+  const handleRevoke = async (token: AccessToken) => {
+    if (
+      !window.confirm(
+        t('confirm.revokeToken', {
+          defaultValue: 'Revoke "{{name}}"? Scripts using it will stop working.',
+          name: token.name,
+        })
+      )
+    ) {
+      return;
+    }
+    try {
+      await revokeAccessToken(token.id);
+      reload();
+    } catch {
+      push(t('toast.errorRevokeToken', { defaultValue: 'Failed to revoke access token' }), 'error');
+    }
+  };
+
+  return (
+    <Modal
+      show={show}
+      onHide={onClose}
+      centered
+      size="lg"
+      data-bs-theme={isDarkMode ? 'dark' : 'light'}
+    >
+      <Modal.Header closeButton>
+        <Modal.Title>{t('tokens.title', { defaultValue: 'Personal Access Tokens' })}</Modal.Title>
+      </Modal.Header>
+      <Modal.Body>
+        {created?.token && (
+          <div className="alert alert-success" role="status">
+            <div className="mb-2">
+              {t('tokens.copyNow', {
+                defaultValue: 'Copy your new token now. It will not be shown again.',
+              })}
+            </div>
+            <code className="user-select-all text-break">{created.token}</code>
+          </div>
+        )}
+
+        <Form onSubmit={handleCreate} noValidate className="mb-4">
+          <Form.Group className="mb-3" controlId="tokenName">
+            <Form.Label>{t('tokens.name', { defaultValue: 'Name' })}</Form.Label>
+            <Form.Control
+              type="text"
+              maxLength={100}
+              value={name}
+              onChange={(e) => setName(e.target.value)}
+              required
+            />
+          </Form.Group>
+          <Form.Group className="mb-3">
+            <Form.Label>{t('tokens.scopes', { defaultValue: 'Scopes' })}</Form.Label>
+            {SCOPES.map((s) => (
+              <Form.Check
+                key={s.value}
+                id={`scope-${s.value}`}
+                type="checkbox"
+                label={`${s.value} — ${s.label}`}
+                checked={scopes.includes(s.value)}
+                onChange={() => toggleScope(s.value)}
+              />
+            ))}
+          </Form.Group>
+          <Form.Group className="mb-3" controlId="tokenExpiry">
+            <Form.Label>{t('tokens.expiry', { defaultValue: 'Expires after' })}</Form.Label>
+            <Form.Select value={expiresInDays} onChange={(e) => setExpiresInDays(e.target.value)}>
+              <option value="30">30 days</option>
+              <option value="90">90 days</option>
+              <option value="365">1 year</option>
+              <option value="0">{t('tokens.never', { defaultValue: 'Never' })}</option>
+            </Form.Select>
+          </Form.Group>
+          <Button type="submit" variant="primary">
+            {t('tokens.create', { defaultValue: 'Create token' })}
+          </Button>
+        </Form>
+
+        <Table size="sm" responsive>
+          <thead>
+            <tr>
+              <th>{t('tokens.name', { defaultValue: 'Name' })}</th>
+              <th>{t('tokens.scopes', { defaultValue: 'Scopes' })}</th>
+              <th>{t('tokens.expires', { defaultValue: 'Expires' })}</th>
+              <th>{t('tokens.lastUsed', { defaultValue: 'Last used' })}</th>
+              <th />
+            </tr>
+          </thead>
+          <tbody>
+            {tokens.length === 0 && (
+              <tr>
+                <td colSpan={5} className="text-muted">
+                  {t('tokens.none', { defaultValue: 'No access tokens yet' })}
+                </td>
+              </tr>
+            )}
+            {tokens.map((token) => (
+              <tr key={token.id}>
+                <td>
+                  {token.name}
+                  <div className="text-muted small">
+                    <code>{token.prefix}…</code>
+                  </div>
+                </td>
+                <td>{token.scopes.join(', ')}</td>
+                <td>{formatDate(token.expires_at)}</td>
+                <td>{formatDate(token.last_used_at)}</td>
+                <td className="text-end">
+                  <Button size="sm" variant="outline-danger" onClick={() => handleRevoke(token)}>
+                    {t('tokens.revoke', { defaultValue: 'Revoke' })}
+                  </Button>
+                </td>
+              </tr>
+            ))}
+          </tbody>
+        </Table>
+      </Modal.Body>
+      <Modal.Footer>
+        <Button variant="secondary" onClick={onClose}>
+          {t('btn.close', { defaultValue: 'Close' })}
+        </Button>
+      </Modal.Footer>
+    </Modal>
+  );
+};
+
+export default AccessTokensModal;
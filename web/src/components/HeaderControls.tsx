@@ -15,6 +15,7 @@ export type HeaderControlsProps = {
   onMonthChange: (v: string) => void;
   user: User | null;
   onChangePasswordClick: () => void;
+  onAccessTokensClick?: () => void;
   onLogout: () => void;
   onNavigateToUserManagement?: () => void;
   onNavigateToDBAdmin?: () => void;
@@ -31,6 +32,7 @@ const HeaderControls: React.FC<HeaderControlsProps> = ({
   onMonthChange,
   user,
   onChangePasswordClick,
+  onAccessTokensClick,
   onLogout,
   onNavigateToUserManagement,
   onNavigateToDBAdmin,
@@ -205,6 +207,14 @@ const HeaderControls: React.FC<HeaderControlsProps> = ({
               {t('nav.password', { defaultValue: 'Change Password' })}
             </button>
           </li>
+          {onAccessTokensClick && (
+            <li>
+              <button className="dropdown-item" onClick={onAccessTokensClick}>
+                <span className="me-2"></span>
+                {t('nav.accessTokens', { defaultValue: 'Access Tokens' })}
+              </button>
+            </li>
+          )}
 
           {/* Admin Panel Section - only show if user is admin */}
           {user?.is_admin && (
@@ -107,7 +107,7 @@ export const useAuth = () => {
         return true;
       } else {
         const error = await response.json();
-        push(error.error || 'Registration failed', 'error');
+        push(error.detail || 'Registration failed', 'error');
         return false;
       }
     } catch (error) {
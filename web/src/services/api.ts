@@ -1,4 +1,4 @@
-import { YearMonth, Expense } from '../types/budget';
+import { YearMonth, Expense, AccessToken, TokenScope } from '../types/budget';
 
 function baseHeaders() {
   const apiKey = sessionStorage.getItem('api_key') || '';
@@ -237,3 +237,26 @@ export async function rejectUser(userId: number) {
   const res = await fetchWithTimeout(`/api/v1/admin/users/${userId}/reject`, { method: 'POST' });
   return res;
 }
+
+// Personal access tokens
+export async function listAccessTokens(): Promise<AccessToken[]> {
+  const res = await fetchWithTimeout('/api/v1/tokens');
+  const data = await res.json();
+  return Array.isArray(data) ? data : [];
+}
+
+export async function createAccessToken(payload: {
+  name: string;
+  scopes: TokenScope[];
+  expires_in_days?: number;
+}): Promise<AccessToken> {
+  const res = await fetchWithTimeout('/api/v1/tokens', {
+    method: 'POST',
+    body: JSON.stringify(payload),
+  });
+  return res.json();
+}
+
+export async function revokeAccessToken(id: number) {
+  return fetchWithTimeout(`/api/v1/tokens/${id}`, { method: 'DELETE' });
+}
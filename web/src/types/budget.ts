@@ -34,6 +34,20 @@ export interface User {
   is_admin?: boolean;
 }
 
+export type TokenScope = 'read:budget' | 'write:expenses' | 'admin';
+
+// Personal access token; `token` is only present in the create response
+export interface AccessToken {
+  id: number;
+  name: string;
+  prefix: string;
+  scopes: TokenScope[];
+  expires_at?: string;
+  last_used_at?: string;
+  created_at: string;
+  token?: string;
+}
+
 export interface LoginData {
   success: boolean;
   message: string;